@@ -0,0 +1,95 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi/clientgen"
+)
+
+// GoFileName is the name of the file the Go bindings are written to within the
+// directory of their package
+const GoFileName = "bindings.go"
+
+// TypeScriptFileName is the name of the file the TypeScript bindings are written to
+const TypeScriptFileName = "bindings.ts"
+
+type options struct {
+	metadataPath string
+	outDir       string
+	packageName  string
+	typescript   bool
+}
+
+func run(opts options) error {
+	file, err := ioutil.ReadFile(opts.metadataPath)
+
+	if err != nil {
+		return fmt.Errorf("Failed to read metadata file. %s", err.Error())
+	}
+
+	var ccm contractapi.ContractChaincodeMetadata
+	err = json.Unmarshal(file, &ccm)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse metadata file. %s", err.Error())
+	}
+
+	bindings, err := clientgen.GenerateGo(ccm, opts.packageName)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(filepath.Join(opts.outDir, opts.packageName), GoFileName, bindings)
+
+	if err != nil {
+		return err
+	}
+
+	if !opts.typescript {
+		return nil
+	}
+
+	bindings, err = clientgen.GenerateTypeScript(ccm)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(opts.outDir, TypeScriptFileName, bindings)
+}
+
+func writeFile(dir string, name string, contents []byte) error {
+	err := os.MkdirAll(dir, 0755)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create output directory %s. %s", dir, err.Error())
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, name), contents, 0644)
+
+	if err != nil {
+		return fmt.Errorf("Failed to write %s. %s", name, err.Error())
+	}
+
+	return nil
+}
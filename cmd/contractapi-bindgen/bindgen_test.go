@@ -0,0 +1,66 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestRun(t *testing.T) {
+	var err error
+
+	dir, _ := ioutil.TempDir("", "contractapi-bindgen")
+	defer os.RemoveAll(dir)
+
+	metadataPath := filepath.Join(dir, "metadata.json")
+	ioutil.WriteFile(metadataPath, []byte(`{"info":{"title":"assets"},"contracts":{"assets":{"name":"assets","transactions":[{"name":"ReadAsset","tag":["evaluateTx"],"parameters":[{"name":"id","schema":{"type":"string"}}],"returns":{"type":"string"}}]}},"components":{}}`), 0644)
+
+	// Should error when metadata file missing
+	err = run(options{metadataPath: filepath.Join(dir, "missing.json"), outDir: dir, packageName: "client"})
+	assert.Contains(t, err.Error(), "Failed to read metadata file.", "should error when file missing")
+
+	// Should error when metadata file not JSON
+	badPath := filepath.Join(dir, "bad.json")
+	ioutil.WriteFile(badPath, []byte("not json"), 0644)
+	err = run(options{metadataPath: badPath, outDir: dir, packageName: "client"})
+	assert.Contains(t, err.Error(), "Failed to parse metadata file.", "should error when file invalid")
+
+	// Should error when package name invalid
+	err = run(options{metadataPath: metadataPath, outDir: dir, packageName: "not-valid"})
+	assert.EqualError(t, err, "Package not-valid is not a valid package name", "should error when package invalid")
+
+	// Should write only go bindings by default
+	err = run(options{metadataPath: metadataPath, outDir: dir, packageName: "client"})
+	assert.Nil(t, err, "should not error for valid metadata")
+	goBindings, _ := ioutil.ReadFile(filepath.Join(dir, "client", GoFileName))
+	assert.Contains(t, string(goBindings), "func (c *AssetsContract) ReadAsset(id string) (string, error) {", "should write go bindings in package directory")
+	_, err = os.Stat(filepath.Join(dir, TypeScriptFileName))
+	assert.True(t, os.IsNotExist(err), "should not write typescript bindings by default")
+
+	// Should also write typescript bindings when requested
+	err = run(options{metadataPath: metadataPath, outDir: dir, packageName: "client", typescript: true})
+	assert.Nil(t, err, "should not error for valid metadata")
+	tsBindings, _ := ioutil.ReadFile(filepath.Join(dir, TypeScriptFileName))
+	assert.Contains(t, string(tsBindings), "public async readAsset(id: string): Promise<string> {", "should write typescript bindings")
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command contractapi-bindgen generates typed client bindings for a chaincode from
+// its metadata, as returned by the GetMetadata transaction of the system contract
+// or written by contractapi-gen. The bindings call the transactions of each
+// contract through the contract of a Fabric gateway network.
+//
+// Usage:
+//
+//	contractapi-bindgen -metadata ./gen/metadata.json -out ./gen -package client
+//
+// Pass -typescript to also generate TypeScript bindings for use with the Node
+// fabric-network gateway.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	metadata := flag.String("metadata", "", "path of the chaincode metadata file")
+	out := flag.String("out", ".", "directory to write the bindings to")
+	pkg := flag.String("package", "client", "package name of the generated Go bindings")
+	typescript := flag.Bool("typescript", false, "also generate TypeScript bindings")
+	flag.Parse()
+
+	if *metadata == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := options{
+		metadataPath: *metadata,
+		outDir:       *out,
+		packageName:  *pkg,
+		typescript:   *typescript,
+	}
+
+	if err := run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// findNondeterminism parses the source file and returns descriptions of its uses
+// of math/rand and time.Now
+func findNondeterminism(file string) ([]string, error) {
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s. %s", file, err.Error())
+	}
+
+	found := []string{}
+	timeNames := make(map[string]bool)
+
+	for _, spec := range parsed.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		name := filepath.Base(path)
+
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		if path == "math/rand" {
+			found = append(found, fmt.Sprintf("%s imports math/rand", filepath.Base(file)))
+		} else if path == "time" {
+			timeNames[name] = true
+		}
+	}
+
+	callsNow := false
+
+	ast.Inspect(parsed, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok && selector.Sel.Name == "Now" {
+			if ident, ok := selector.X.(*ast.Ident); ok && timeNames[ident.Name] {
+				callsNow = true
+			}
+		}
+
+		return !callsNow
+	})
+
+	if callsNow {
+		found = append(found, fmt.Sprintf("%s calls time.Now", filepath.Base(file)))
+	}
+
+	return found, nil
+}
+
+// checkDeterminism finds uses of math/rand and time.Now in the Go files, other than
+// tests, of the package. These differ between endorsing peers so cause endorsement
+// mismatches when used by transactions. Only the files of the package itself are
+// checked, not those of packages it imports.
+func checkDeterminism(packagePath string) ([]string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	cmd := exec.Command("go", "list", "-f", "{{.Dir}}{{range .GoFiles}}\n{{.}}{{end}}", packagePath)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Failed to list files of package %s. %s", packagePath, strings.TrimSpace(stderr.String()))
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	found := []string{}
+
+	for _, file := range lines[1:] {
+		fileFound, err := findNondeterminism(filepath.Join(lines[0], file))
+
+		if err != nil {
+			return nil, err
+		}
+
+		found = append(found, fileFound...)
+	}
+
+	return found, nil
+}
+
+func nondeterminismWarning(packagePath string, found []string) string {
+	return fmt.Sprintf("Package %s may not be deterministic as %s. Use the transaction timestamp (see GetTxTimestamp) and DeterministicRand instead", packagePath, strings.Join(found, ", "))
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+const nondeterministicTestSource = `package mycc
+
+import (
+	random "math/rand"
+	t "time"
+)
+
+func Roll() (int, t.Duration) {
+	_ = t.Now()
+	return random.Int(), t.Second
+}
+`
+
+const deterministicTestSource = `package mycc
+
+import "time"
+
+func Wait() time.Duration {
+	now := struct{ Now int }{}
+	return time.Duration(now.Now)
+}
+`
+
+func writeNondeterminismTestPackage(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir(".", ".nondeterminism")
+	assert.Nil(t, err, "should create temp dir")
+
+	ioutil.WriteFile(filepath.Join(dir, "nondeterministic.go"), []byte(nondeterministicTestSource), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "deterministic.go"), []byte(deterministicTestSource), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "deterministic_test.go"), []byte(nondeterministicTestSource), 0644)
+
+	return dir
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestFindNondeterminism(t *testing.T) {
+	var found []string
+	var err error
+
+	dir := writeNondeterminismTestPackage(t)
+	defer os.RemoveAll(dir)
+
+	// Should find imports of math/rand and calls to time.Now using import names
+	found, err = findNondeterminism(filepath.Join(dir, "nondeterministic.go"))
+	assert.Nil(t, err, "should not error for valid file")
+	assert.Equal(t, []string{"nondeterministic.go imports math/rand", "nondeterministic.go calls time.Now"}, found, "should find sources of nondeterminism")
+
+	// Should not find uses of time other than Now
+	found, _ = findNondeterminism(filepath.Join(dir, "deterministic.go"))
+	assert.Equal(t, []string{}, found, "should not find nondeterminism in deterministic file")
+
+	// Should error for files that cannot be read
+	_, err = findNondeterminism(filepath.Join(dir, "missing.go"))
+	assert.Contains(t, err.Error(), "Failed to parse "+filepath.Join(dir, "missing.go")+".", "should error for missing file")
+}
+
+func TestCheckDeterminism(t *testing.T) {
+	var found []string
+	var err error
+
+	dir := writeNondeterminismTestPackage(t)
+	defer os.RemoveAll(dir)
+
+	// Should check go files of package other than tests
+	found, err = checkDeterminism("./" + dir)
+	assert.Nil(t, err, "should not error for valid package")
+	assert.Equal(t, []string{"nondeterministic.go imports math/rand", "nondeterministic.go calls time.Now"}, found, "should find sources of nondeterminism in package")
+
+	// Should error when package cannot be listed
+	_, err = checkDeterminism("./" + dir + "/missing")
+	assert.Contains(t, err.Error(), "Failed to list files of package ./"+dir+"/missing.", "should error for missing package")
+}
+
+func TestNondeterminismWarning(t *testing.T) {
+	assert.Equal(t, "Package example.com/mycc may not be deterministic as a.go imports math/rand, b.go calls time.Now. Use the transaction timestamp (see GetTxTimestamp) and DeterministicRand instead", nondeterminismWarning("example.com/mycc", []string{"a.go imports math/rand", "b.go calls time.Now"}), "should list sources of nondeterminism")
+}
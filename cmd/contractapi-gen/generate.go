@@ -0,0 +1,357 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi/clientgen"
+)
+
+// MetadataFileName name of the file the metadata is written to in the output directory
+const MetadataFileName = "metadata.json"
+
+// ClientFileName name of the file the client stub is written to in the client package
+const ClientFileName = "client.go"
+
+// MocksFileName name of the file the mocks are written to in the mocks package
+const MocksFileName = "mocks.go"
+
+var warningWriter io.Writer = os.Stderr
+
+type options struct {
+	packagePath   string
+	contracts     []string
+	outDir        string
+	clientPackage string
+	mocksPackage  string
+	check         bool
+}
+
+type functionSignature struct {
+	Name    string   `json:"name"`
+	Params  []string `json:"params"`
+	Returns []string `json:"returns"`
+}
+
+type contractSignature struct {
+	Type      string              `json:"type"`
+	Name      string              `json:"name"`
+	Context   string              `json:"context"`
+	Functions []functionSignature `json:"functions"`
+}
+
+// generatorOutput the output of the metadata program, the metadata of the chaincode
+// and the Go signatures of the transactions of its contracts along with the import
+// paths and names of the packages of the types they use
+type generatorOutput struct {
+	Metadata  json.RawMessage     `json:"metadata"`
+	Contracts []contractSignature `json:"contracts"`
+	Imports   map[string]string   `json:"imports"`
+}
+
+var metadataProgramTemplate = template.Must(template.New("metadata").Parse(`// Code generated by contractapi-gen. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	contracts "{{.Package}}"
+)
+
+type functionSignature struct {
+	Name    string   ` + "`json:\"name\"`" + `
+	Params  []string ` + "`json:\"params\"`" + `
+	Returns []string ` + "`json:\"returns\"`" + `
+}
+
+type contractSignature struct {
+	Type      string              ` + "`json:\"type\"`" + `
+	Name      string              ` + "`json:\"name\"`" + `
+	Context   string              ` + "`json:\"context\"`" + `
+	Functions []functionSignature ` + "`json:\"functions\"`" + `
+}
+
+var imports = map[string]string{}
+
+func typeString(t reflect.Type) string {
+	if t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Ptr:
+			return "*" + typeString(t.Elem())
+		case reflect.Slice:
+			return "[]" + typeString(t.Elem())
+		case reflect.Array:
+			return fmt.Sprintf("[%d]%s", t.Len(), typeString(t.Elem()))
+		case reflect.Map:
+			return "map[" + typeString(t.Key()) + "]" + typeString(t.Elem())
+		}
+	} else if t.PkgPath() != "" {
+		imports[t.PkgPath()] = strings.Split(t.String(), ".")[0]
+	}
+
+	return t.String()
+}
+
+func getSignature(contract contractapi.ContractInterface, metadata contractapi.ContractChaincodeMetadata) contractSignature {
+	contractType := reflect.TypeOf(contract)
+
+	signature := contractSignature{Type: contractType.Elem().Name(), Name: contract.GetName()}
+
+	if signature.Name == "" {
+		signature.Name = signature.Type
+	}
+
+	signature.Context = typeString(reflect.TypeOf(contract.GetTransactionContextHandler()).Elem())
+
+	for _, tx := range metadata.Contracts[signature.Name].Transactions {
+		method, _ := contractType.MethodByName(tx.Name)
+
+		function := functionSignature{Name: tx.Name, Params: []string{}, Returns: []string{}}
+
+		for i := 1; i < method.Type.NumIn(); i++ {
+			function.Params = append(function.Params, typeString(method.Type.In(i)))
+		}
+
+		for i := 0; i < method.Type.NumOut(); i++ {
+			function.Returns = append(function.Returns, typeString(method.Type.Out(i)))
+		}
+
+		signature.Functions = append(signature.Functions, function)
+	}
+
+	return signature
+}
+
+func main() {
+	contractList := []contractapi.ContractInterface{ {{- range $i, $c := .Contracts}}{{if $i}}, {{end}}new(contracts.{{$c}}){{end -}} }
+
+	cc := contractapi.CreateNewChaincode(contractList...)
+
+	output := struct {
+		Metadata  contractapi.ContractChaincodeMetadata ` + "`json:\"metadata\"`" + `
+		Contracts []contractSignature                   ` + "`json:\"contracts\"`" + `
+		Imports   map[string]string                     ` + "`json:\"imports\"`" + `
+	}{Metadata: cc.GetMetadata()}
+
+	for _, contract := range contractList {
+		output.Contracts = append(output.Contracts, getSignature(contract, output.Metadata))
+	}
+
+	output.Imports = imports
+
+	bytes, err := json.Marshal(output)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Println(string(bytes))
+}
+`))
+
+func run(opts options) error {
+	output, err := generateMetadata(opts.packagePath, opts.contracts)
+
+	if err != nil {
+		return err
+	}
+
+	found, err := checkDeterminism(opts.packagePath)
+
+	if err != nil {
+		return err
+	}
+
+	if len(found) > 0 && opts.check {
+		return errors.New(nondeterminismWarning(opts.packagePath, found))
+	} else if len(found) > 0 {
+		fmt.Fprintln(warningWriter, "Warning: "+nondeterminismWarning(opts.packagePath, found))
+	}
+
+	metadata := new(bytes.Buffer)
+	json.Indent(metadata, output.Metadata, "", "    ")
+	metadata.WriteString("\n")
+
+	metadataPath := filepath.Join(opts.outDir, MetadataFileName)
+
+	if opts.check {
+		return checkDrift(metadataPath, metadata.Bytes())
+	}
+
+	var ccm contractapi.ContractChaincodeMetadata
+	err = json.Unmarshal(output.Metadata, &ccm)
+
+	if err != nil {
+		return fmt.Errorf("Failed to parse generated metadata. %s", err.Error())
+	}
+
+	client, err := clientgen.GenerateStub(ccm, opts.clientPackage)
+
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(opts.outDir, MetadataFileName, metadata.Bytes())
+
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(filepath.Join(opts.outDir, opts.clientPackage), ClientFileName, client)
+
+	if err != nil {
+		return err
+	}
+
+	if opts.mocksPackage == "" {
+		return nil
+	}
+
+	mocks, err := generateMocks(*output, ccm.Info.Title, opts.mocksPackage)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(opts.outDir, opts.mocksPackage), MocksFileName, mocks)
+}
+
+func writeFile(dir string, name string, contents []byte) error {
+	err := os.MkdirAll(dir, 0755)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create output directory %s. %s", dir, err.Error())
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, name), contents, 0644)
+
+	if err != nil {
+		return fmt.Errorf("Failed to write %s. %s", name, err.Error())
+	}
+
+	return nil
+}
+
+func metadataProgram(packagePath string, contracts []string) ([]byte, error) {
+	for _, contract := range contracts {
+		if !clientgen.IsIdentifier(contract) || !unicode.IsUpper([]rune(contract)[0]) {
+			return nil, fmt.Errorf("Contract %s is not a valid exported type name", contract)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	err := metadataProgramTemplate.Execute(buf, struct {
+		Package   string
+		Contracts []string
+	}{packagePath, contracts})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// generateMetadata builds and runs a program in the current module which creates
+// the chaincode from the contracts and outputs its metadata and the signatures of
+// the transactions of its contracts
+func generateMetadata(packagePath string, contracts []string) (*generatorOutput, error) {
+	program, err := metadataProgram(packagePath, contracts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir(".", ".contractapi-gen")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create temporary directory. %s", err.Error())
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "main.go"), program, 0644)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write metadata program. %s", err.Error())
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Failed to generate metadata. %s", strings.TrimSpace(stderr.String()))
+	}
+
+	output := new(generatorOutput)
+	err = json.Unmarshal(stdout.Bytes(), output)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse output of metadata program. %s", err.Error())
+	}
+
+	return output, nil
+}
+
+// checkDrift compares the generated metadata with that stored at path,
+// ignoring formatting differences
+func checkDrift(path string, generated []byte) error {
+	existing, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return fmt.Errorf("Failed to read existing metadata. %s", err.Error())
+	}
+
+	var existingValue, generatedValue interface{}
+
+	if err := json.Unmarshal(existing, &existingValue); err != nil {
+		return fmt.Errorf("Failed to parse existing metadata. %s", err.Error())
+	}
+
+	if err := json.Unmarshal(generated, &generatedValue); err != nil {
+		return fmt.Errorf("Failed to parse generated metadata. %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(existingValue, generatedValue) {
+		return fmt.Errorf("Metadata in %s does not match that generated from the contracts", path)
+	}
+
+	return nil
+}
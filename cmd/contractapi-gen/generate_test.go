@@ -0,0 +1,74 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestMetadataProgram(t *testing.T) {
+	var program []byte
+	var err error
+
+	// Should create program for contracts
+	program, err = metadataProgram("example.com/mycc", []string{"AssetContract", "OwnerContract"})
+	assert.Nil(t, err, "should not error for valid contracts")
+	assert.Contains(t, string(program), `contracts "example.com/mycc"`, "should import contracts package")
+	assert.Contains(t, string(program), "contractList := []contractapi.ContractInterface{new(contracts.AssetContract), new(contracts.OwnerContract)}", "should create each contract")
+	assert.Contains(t, string(program), "contractapi.CreateNewChaincode(contractList...)", "should create chaincode from contracts")
+
+	// Should error for invalid contract names
+	program, err = metadataProgram("example.com/mycc", []string{"assetContract"})
+	assert.EqualError(t, err, "Contract assetContract is not a valid exported type name", "should error for unexported type")
+	assert.Nil(t, program, "should not return program on error")
+
+	_, err = metadataProgram("example.com/mycc", []string{"Asset Contract"})
+	assert.EqualError(t, err, "Contract Asset Contract is not a valid exported type name", "should error for invalid identifier")
+}
+
+func TestCheckDrift(t *testing.T) {
+	var err error
+
+	dir, _ := ioutil.TempDir("", "contractapi-gen")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, MetadataFileName)
+	ioutil.WriteFile(path, []byte(`{"info": {"title": "some title"}}`), 0644)
+
+	// Should not error when metadata matches ignoring formatting
+	err = checkDrift(path, []byte(`{"info":{"title":"some title"}}`))
+	assert.Nil(t, err, "should not error when metadata matches")
+
+	// Should error when metadata differs
+	err = checkDrift(path, []byte(`{"info":{"title":"other title"}}`))
+	assert.EqualError(t, err, "Metadata in "+path+" does not match that generated from the contracts", "should error when metadata differs")
+
+	// Should error when generated metadata is not JSON
+	err = checkDrift(path, []byte("not json"))
+	assert.Contains(t, err.Error(), "Failed to parse generated metadata.", "should error when generated metadata invalid")
+
+	// Should error when existing metadata missing
+	err = checkDrift(filepath.Join(dir, "missing.json"), []byte("{}"))
+	assert.Contains(t, err.Error(), "Failed to read existing metadata.", "should error when file missing")
+}
@@ -0,0 +1,71 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command contractapi-gen generates the metadata of a chaincode built from contracts
+// using the contractapi, and a client stub for calling its transactions, without
+// needing to start the chaincode. It must be run from within a module that can
+// import the package containing the contracts.
+//
+// Usage:
+//
+//	contractapi-gen -package example.com/mycc/contracts -contracts AssetContract,OwnerContract -out ./gen
+//
+// Pass -mocks-package to also generate mocks of the contracts, with the same names,
+// transaction contexts and transaction signatures, whose transactions call functions
+// set by tests. Mocks can be used in place of the contracts in CreateNewChaincode to
+// test clients without the business logic of the contracts.
+//
+// The Go files of the package are checked for uses of math/rand and time.Now, which
+// cause endorsements to differ between peers, and a warning is written for any found.
+//
+// Pass -check to compare the generated metadata with that already in the output
+// directory and exit with a non zero status if it has drifted or the package uses
+// math/rand or time.Now.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	pkgPath := flag.String("package", "", "import path of the package containing the contracts")
+	contracts := flag.String("contracts", "", "comma separated names of the contract types, in the order passed to CreateNewChaincode")
+	out := flag.String("out", ".", "directory to write the metadata and client stub to")
+	clientPkg := flag.String("client-package", "client", "package name of the generated client stub")
+	mocksPkg := flag.String("mocks-package", "", "package name of the generated contract mocks. Mocks are not generated if blank")
+	check := flag.Bool("check", false, "compare the generated metadata with that in the output directory rather than writing it, and fail if the package may not be deterministic")
+	flag.Parse()
+
+	if *pkgPath == "" || *contracts == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := options{
+		packagePath:   *pkgPath,
+		contracts:     strings.Split(*contracts, ","),
+		outDir:        *out,
+		clientPackage: *clientPkg,
+		mocksPackage:  *mocksPkg,
+		check:         *check,
+	}
+
+	if err := run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi/clientgen"
+)
+
+const contractapiPath = "github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+
+var mocksTemplate = template.Must(template.New("mocks").Parse(`// Code generated by contractapi-gen. DO NOT EDIT.
+
+// Package {{.Package}} provides mocks of the contracts of the {{.Title}} chaincode
+// for testing clients without the business logic of the contracts
+package {{.Package}}
+
+import (
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"{{range .Imports}}
+	{{.}}{{end}}
+)
+{{range .Mocks}}
+// {{.TypeName}} mocks the {{.Name}} contract. Set the func fields of the mock
+// to define the responses of its transactions. Transactions whose func is not
+// set return an error with code 501, or panic if they do not return an error.
+type {{.TypeName}} struct {
+	contractapi.Contract
+{{range .Functions}}	{{.Field}} func({{.ParamTypes}}){{.Returns}}
+{{end}}}
+
+// New{{.TypeName}} returns a mock with the name and transaction context of the {{.Name}} contract
+func New{{.TypeName}}() *{{.TypeName}} {
+	mock := new({{.TypeName}})
+	mock.SetName({{printf "%q" .Name}})
+	mock.SetTransactionContextHandler(new({{.Context}}))
+
+	return mock
+}
+{{$mock := .}}{{range .Functions}}
+// {{.Name}} calls {{.Field}} of the mock
+func (m *{{$mock.TypeName}}) {{.Name}}({{.Params}}){{.Returns}} {
+	if m.{{.Field}} == nil {
+		{{.NotMocked}}
+	}
+
+	{{if .Returns}}return {{end}}m.{{.Field}}({{.Args}})
+}
+{{end}}{{end}}`))
+
+type mockFunction struct {
+	Name       string
+	Field      string
+	Params     string
+	ParamTypes string
+	Args       string
+	Returns    string
+	NotMocked  string
+}
+
+type mockContract struct {
+	Name      string
+	TypeName  string
+	Context   string
+	Functions []mockFunction
+}
+
+func generateMocks(output generatorOutput, title string, packageName string) ([]byte, error) {
+	if !clientgen.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("Mocks package %s is not a valid package name", packageName)
+	}
+
+	imports := []string{}
+
+	for path, name := range output.Imports {
+		if path != contractapiPath {
+			imports = append(imports, fmt.Sprintf("%s %q", name, path))
+		}
+	}
+
+	sort.Strings(imports)
+
+	mocks := []mockContract{}
+
+	for _, contract := range output.Contracts {
+		mock := mockContract{
+			Name:     contract.Name,
+			TypeName: contract.Type + "Mock",
+			Context:  contract.Context,
+		}
+
+		for _, function := range contract.Functions {
+			mock.Functions = append(mock.Functions, newMockFunction(function))
+		}
+
+		mocks = append(mocks, mock)
+	}
+
+	buf := new(bytes.Buffer)
+	err := mocksTemplate.Execute(buf, struct {
+		Package string
+		Title   string
+		Imports []string
+		Mocks   []mockContract
+	}{packageName, title, imports, mocks})
+
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format mocks. %s", err.Error())
+	}
+
+	return formatted, nil
+}
+
+func newMockFunction(function functionSignature) mockFunction {
+	mf := mockFunction{
+		Name:       function.Name,
+		Field:      function.Name + "Func",
+		ParamTypes: strings.Join(function.Params, ", "),
+	}
+
+	params := []string{}
+	args := []string{}
+
+	for i, param := range function.Params {
+		params = append(params, fmt.Sprintf("param%d %s", i, param))
+		args = append(args, fmt.Sprintf("param%d", i))
+	}
+
+	mf.Params = strings.Join(params, ", ")
+	mf.Args = strings.Join(args, ", ")
+
+	switch len(function.Returns) {
+	case 0:
+	case 1:
+		mf.Returns = " " + function.Returns[0]
+	default:
+		mf.Returns = " (" + strings.Join(function.Returns, ", ") + ")"
+	}
+
+	if len(function.Returns) == 0 || function.Returns[len(function.Returns)-1] != "error" {
+		mf.NotMocked = fmt.Sprintf("panic(%q)", function.Name+" not mocked")
+		return mf
+	}
+
+	declarations := []string{}
+	values := []string{}
+
+	for i, ret := range function.Returns[:len(function.Returns)-1] {
+		declarations = append(declarations, fmt.Sprintf("var result%d %s", i, ret))
+		values = append(values, fmt.Sprintf("result%d", i))
+	}
+
+	values = append(values, fmt.Sprintf("contractapi.NewError(501, %q, nil)", function.Name+" not mocked"))
+
+	mf.NotMocked = strings.Join(append(declarations, "return "+strings.Join(values, ", ")), "\n")
+
+	return mf
+}
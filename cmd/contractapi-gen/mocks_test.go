@@ -0,0 +1,88 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestGenerateMocks(t *testing.T) {
+	var mocks []byte
+	var err error
+
+	output := generatorOutput{
+		Contracts: []contractSignature{
+			{
+				Type:    "AssetContract",
+				Name:    "org.example.asset",
+				Context: "contracts.Context",
+				Functions: []functionSignature{
+					{Name: "Create", Params: []string{"*contracts.Context", "string", "contracts.Asset"}, Returns: []string{"error"}},
+					{Name: "Read", Params: []string{"*contracts.Context", "string"}, Returns: []string{"*contracts.Asset", "error"}},
+					{Name: "Count", Params: []string{"*contracts.Context"}, Returns: []string{"int"}},
+				},
+			},
+		},
+		Imports: map[string]string{
+			"example.com/mycc/contracts": "contracts",
+			contractapiPath:              "contractapi",
+		},
+	}
+
+	// Should generate mock for each contract
+	mocks, err = generateMocks(output, "mycc", "assetmocks")
+	assert.Nil(t, err, "should not error for valid output")
+	assert.Contains(t, string(mocks), "package assetmocks", "should use package name")
+	assert.Contains(t, string(mocks), "import (\n\tcontracts \"example.com/mycc/contracts\"\n\t\"github.com/awjh-ibm/fabric-go-developer-api/contractapi\"\n)", "should import packages of types once")
+	assert.Contains(t, string(mocks), "type AssetContractMock struct {\n\tcontractapi.Contract\n\tCreateFunc func(*contracts.Context, string, contracts.Asset) error\n\tReadFunc   func(", "should create mock type with func fields")
+	assert.Contains(t, string(mocks), "mock.SetName(\"org.example.asset\")\n\tmock.SetTransactionContextHandler(new(contracts.Context))", "should set name and context of contract")
+	assert.Contains(t, string(mocks), "func (m *AssetContractMock) Read(param0 *contracts.Context, param1 string) (*contracts.Asset, error) {", "should create method for transaction")
+	assert.Contains(t, string(mocks), "return m.ReadFunc(param0, param1)", "should call func field")
+	assert.Contains(t, string(mocks), "func (m *AssetContractMock) Count(param0 *contracts.Context) int {\n\tif m.CountFunc == nil {\n\t\tpanic(\"Count not mocked\")\n\t}", "should panic for unset transaction returning only values")
+
+	// Should error for invalid package name
+	mocks, err = generateMocks(output, "mycc", "asset mocks")
+	assert.EqualError(t, err, "Mocks package asset mocks is not a valid package name", "should error for invalid package")
+	assert.Nil(t, mocks, "should not return mocks on error")
+}
+
+func TestNewMockFunction(t *testing.T) {
+	var mf mockFunction
+
+	// Should panic when not mocked for function without returns
+	mf = newMockFunction(functionSignature{Name: "Ping", Params: []string{}, Returns: []string{}})
+	assert.Equal(t, mockFunction{Name: "Ping", Field: "PingFunc", NotMocked: "panic(\"Ping not mocked\")"}, mf, "should panic without returns")
+
+	// Should panic when not mocked for function returning only values
+	mf = newMockFunction(functionSignature{Name: "List", Params: []string{"int"}, Returns: []string{"[]string"}})
+	assert.Equal(t, mockFunction{Name: "List", Field: "ListFunc", Params: "param0 int", ParamTypes: "int", Args: "param0", Returns: " []string", NotMocked: "panic(\"List not mocked\")"}, mf, "should panic without error return")
+
+	// Should return only not mocked error for function returning error
+	mf = newMockFunction(functionSignature{Name: "Delete", Params: []string{}, Returns: []string{"error"}})
+	assert.Equal(t, "return contractapi.NewError(501, \"Delete not mocked\", nil)", mf.NotMocked, "should return not mocked error")
+
+	// Should return not mocked error
+	mf = newMockFunction(functionSignature{Name: "Read", Params: []string{"string", "bool"}, Returns: []string{"int", "error"}})
+	assert.Equal(t, "param0 string, param1 bool", mf.Params, "should name params")
+	assert.Equal(t, "param0, param1", mf.Args, "should pass params")
+	assert.Equal(t, " (int, error)", mf.Returns, "should group returns")
+	assert.Equal(t, "var result0 int\nreturn result0, contractapi.NewError(501, \"Read not mocked\", nil)", mf.NotMocked, "should return not mocked error")
+}
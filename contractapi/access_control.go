@@ -0,0 +1,104 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+)
+
+// RequireAttribute returns middleware that only allows the functions it is used for
+// to be called by clients whose identity has the named attribute with the passed
+// value. Add it to a function, or all functions of a contract, using Use e.g.
+//
+//	contract.Use("DeleteAsset", contractapi.RequireAttribute("role", "admin"))
+//
+// If the identity does not have the attribute value an Error with code 403 is
+// returned and the function is not called.
+func RequireAttribute(name string, value string) func(TransactionContextInterface) error {
+	return func(ctx TransactionContextInterface) error {
+		ci, err := getClientIdentity(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		actual, found, err := ci.GetAttributeValue(name)
+
+		if err != nil {
+			return fmt.Errorf("Failed to get attribute %s of client identity. %s", name, err.Error())
+		}
+
+		if !found || actual != value {
+			return NewError(http.StatusForbidden, fmt.Sprintf("Access denied. Client identity does not have attribute %s with value %s", name, value), nil)
+		}
+
+		return nil
+	}
+}
+
+// RequireMSP returns middleware that only allows the functions it is used for to
+// be called by clients whose identity belongs to one of the passed MSPs. Add it to
+// a function, or all functions of a contract, using Use e.g.
+//
+//	contract.Use(contractapi.AllFunctions, contractapi.RequireMSP("Org1MSP"))
+//
+// If the identity belongs to another MSP an Error with code 403 is returned and the
+// function is not called.
+func RequireMSP(mspIDs ...string) func(TransactionContextInterface) error {
+	return func(ctx TransactionContextInterface) error {
+		ci, err := getClientIdentity(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		mspID, err := ci.GetMSPID()
+
+		if err != nil {
+			return fmt.Errorf("Failed to get MSP ID of client identity. %s", err.Error())
+		}
+
+		if !stringInSlice(mspID, mspIDs) {
+			return NewError(http.StatusForbidden, fmt.Sprintf("Access denied. Client identity MSP %s is not one of %s", mspID, strings.Join(mspIDs, ", ")), nil)
+		}
+
+		return nil
+	}
+}
+
+// getClientIdentity returns the identity of the client that submitted the
+// transaction, if the transaction context provides it as TransactionContext does
+func getClientIdentity(ctx TransactionContextInterface) (cid.ClientIdentity, error) {
+	identityProvider, ok := ctx.(interface {
+		GetClientIdentity() (cid.ClientIdentity, error)
+	})
+
+	if !ok {
+		return nil, errors.New("Failed to get client identity. Transaction context does not provide the client identity")
+	}
+
+	ci, err := identityProvider.GetClientIdentity()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get client identity. %s", err.Error())
+	}
+
+	return ci, nil
+}
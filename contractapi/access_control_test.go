@@ -0,0 +1,119 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func accessControlContext(mspID string, attrs map[string]string) *TransactionContext {
+	stub := shimtest.NewMockStub("accessControlTest", nil)
+
+	if mspID != "" {
+		stub.Creator = createCreator(mspID, attrs)
+	}
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	return ctx
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestRequireAttribute(t *testing.T) {
+	var err error
+
+	middleware := RequireAttribute("role", "admin")
+
+	// Should allow identity with attribute value
+	err = middleware(accessControlContext("Org1MSP", map[string]string{"role": "admin"}))
+	assert.Nil(t, err, "should not error when identity has attribute value")
+
+	// Should deny identity with other attribute value
+	err = middleware(accessControlContext("Org1MSP", map[string]string{"role": "reader"}))
+	assert.Equal(t, NewError(403, "Access denied. Client identity does not have attribute role with value admin", nil), err, "should return 403 error for other value")
+
+	// Should deny identity without attribute
+	err = middleware(accessControlContext("Org1MSP", map[string]string{}))
+	assert.Equal(t, NewError(403, "Access denied. Client identity does not have attribute role with value admin", nil), err, "should return 403 error for missing attribute")
+
+	// Should error when identity cannot be read
+	err = middleware(accessControlContext("", nil))
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error when creator invalid")
+
+	// Should error when context does not provide identity
+	err = middleware(new(contextlessTestContext))
+	assert.EqualError(t, err, "Failed to get client identity. Transaction context does not provide the client identity", "should error when context has no identity")
+}
+
+func TestRequireMSP(t *testing.T) {
+	var err error
+
+	middleware := RequireMSP("Org1MSP", "Org2MSP")
+
+	// Should allow identity of listed MSP
+	err = middleware(accessControlContext("Org2MSP", nil))
+	assert.Nil(t, err, "should not error when identity of listed MSP")
+
+	// Should deny identity of other MSP
+	err = middleware(accessControlContext("Org3MSP", nil))
+	assert.Equal(t, NewError(403, "Access denied. Client identity MSP Org3MSP is not one of Org1MSP, Org2MSP", nil), err, "should return 403 error for other MSP")
+
+	// Should error when identity cannot be read
+	err = middleware(accessControlContext("", nil))
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error when creator invalid")
+
+	// Should error when context does not provide identity
+	err = middleware(new(contextlessTestContext))
+	assert.EqualError(t, err, "Failed to get client identity. Transaction context does not provide the client identity", "should error when context has no identity")
+}
+
+func TestAccessControlMiddleware(t *testing.T) {
+	mtc := new(middlewareTestContract)
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.Use(AllFunctions, RequireMSP("Org1MSP"))
+	mtc.Use("Update", RequireAttribute("role", "admin"))
+	cc := convertC2CC(mtc)
+
+	stub := shimtest.NewMockStub("accessControlTest", &cc)
+
+	// Should call functions when identity allowed
+	stub.Creator = createCreator("Org1MSP", map[string]string{"role": "admin"})
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Update")})
+	assert.Equal(t, "before,Update", string(response.Payload), "should call function when allowed")
+
+	// Should return 403 status when identity denied
+	stub.Creator = createCreator("Org1MSP", map[string]string{"role": "reader"})
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Update")})
+	assert.Equal(t, int32(403), response.Status, "should return 403 when attribute missing")
+	assert.Equal(t, "Access denied. Client identity does not have attribute role with value admin", response.Message, "should return access denied message")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Read")})
+	assert.Equal(t, "before,Read", string(response.Payload), "should only require attribute for named function")
+
+	stub.Creator = createCreator("Org2MSP", nil)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Read")})
+	assert.Equal(t, int32(403), response.Status, "should return 403 for other MSP for all functions")
+}
@@ -0,0 +1,96 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArgCountMode defines how a contract handles transactions passed a number of
+// args other than the number of parameters of the function called
+type ArgCountMode int
+
+const (
+	// TruncateArgCount ignores args beyond the parameters of the function and
+	// errors when required args are missing. This is the default
+	TruncateArgCount ArgCountMode = iota
+	// StrictArgCount errors when args are passed beyond the parameters of the
+	// function or required args are missing
+	StrictArgCount
+	// PadArgCount ignores args beyond the parameters of the function and passes
+	// the zero value of their type for missing args without defaults, so that
+	// all parameters of the contract's functions are optional in the metadata
+	PadArgCount
+)
+
+// ArgCountContractInterface can optionally be implemented by a contract to set
+// how its transactions handle being passed too many or too few args
+type ArgCountContractInterface interface {
+	// GetArgCountMode returns the mode for the contract's transactions
+	GetArgCountMode() ArgCountMode
+}
+
+// checkArgCount returns the params to pass to the function, errors if the number of
+// params passed is not valid for the arg count mode of the contract. Params with
+// defaults should be applied before the count is checked.
+func (cc *ContractChaincode) checkArgCount(ns string, fn string, transaction *TransactionMetadata, params []string) ([]string, error) {
+	nsContract := cc.contracts[ns]
+	function := nsContract.functions[fn]
+
+	numParams := len(function.params.fields)
+	requiredParams := function.requiredParams()
+
+	switch {
+	case len(params) > numParams && nsContract.argCountMode == StrictArgCount:
+		expected := fmt.Sprintf("%d", numParams)
+
+		if requiredParams < numParams {
+			expected = fmt.Sprintf("at most %d", numParams)
+		}
+
+		return nil, fmt.Errorf("Incorrect number of params for function %s. Expected %s, received %d. Function signature is %s", fn, expected, len(params), functionSignature(fn, function, transaction))
+	case len(params) > numParams:
+		return params[:numParams], nil
+	case len(params) < requiredParams:
+		expected := fmt.Sprintf("%d", numParams)
+
+		if requiredParams < numParams {
+			expected = fmt.Sprintf("at least %d", requiredParams)
+		}
+
+		return nil, fmt.Errorf("Incorrect number of params for function %s. Expected %s, received %d. Function signature is %s", fn, expected, len(params), functionSignature(fn, function, transaction))
+	}
+
+	return params, nil
+}
+
+// functionSignature describes the parameters of the function using the names
+// of its parameters in the metadata e.g. Create(assetID string, value int)
+func functionSignature(fn string, function *contractFunction, transaction *TransactionMetadata) string {
+	params := []string{}
+
+	for i, field := range function.params.fields {
+		name := fmt.Sprintf("param%d", i)
+
+		if transaction != nil && i < len(transaction.Parameters) {
+			name = transaction.Parameters[i].Name
+		}
+
+		params = append(params, name+" "+field.String())
+	}
+
+	return fn + "(" + strings.Join(params, ", ") + ")"
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type argCountContract struct {
+	Contract
+}
+
+func (acc *argCountContract) Create(ctx *TransactionContext, id string, count int) string {
+	return fmt.Sprintf("%s %d", id, count)
+}
+
+func (acc *argCountContract) Update(id string, note *string) string {
+	if note == nil {
+		return id
+	}
+
+	return id + " " + *note
+}
+
+func newArgCountChaincode(mode ArgCountMode) ContractChaincode {
+	acc := new(argCountContract)
+	acc.SetArgCountMode(mode)
+
+	return convertC2CC(acc)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestCheckArgCount(t *testing.T) {
+	var params []string
+	var err error
+
+	// Should truncate extra args and error for missing args by default
+	cc := newArgCountChaincode(TruncateArgCount)
+	params, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a", "1", "extra"})
+	assert.Nil(t, err, "should not error for extra args when truncating")
+	assert.Equal(t, []string{"a", "1"}, params, "should remove extra args when truncating")
+
+	params, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a", "1"})
+	assert.Nil(t, err, "should not error for correct args")
+	assert.Equal(t, []string{"a", "1"}, params, "should return args as passed")
+
+	_, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a"})
+	assert.EqualError(t, err, "Incorrect number of params for function Create. Expected 2, received 1. Function signature is Create(param0 string, param1 int)", "should error for missing args when truncating")
+
+	_, err = cc.checkArgCount("argCountContract", "Update", nil, []string{})
+	assert.EqualError(t, err, "Incorrect number of params for function Update. Expected at least 1, received 0. Function signature is Update(param0 string, param1 *string)", "should error for missing required args when truncating")
+
+	// Should error for extra and missing args when strict
+	cc = newArgCountChaincode(StrictArgCount)
+	_, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a", "1", "extra"})
+	assert.EqualError(t, err, "Incorrect number of params for function Create. Expected 2, received 3. Function signature is Create(param0 string, param1 int)", "should error for extra args when strict")
+
+	_, err = cc.checkArgCount("argCountContract", "Update", nil, []string{"a", "b", "extra"})
+	assert.EqualError(t, err, "Incorrect number of params for function Update. Expected at most 2, received 3. Function signature is Update(param0 string, param1 *string)", "should error for extra args with optional params when strict")
+
+	_, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a"})
+	assert.EqualError(t, err, "Incorrect number of params for function Create. Expected 2, received 1. Function signature is Create(param0 string, param1 int)", "should error for missing args when strict")
+
+	params, err = cc.checkArgCount("argCountContract", "Update", nil, []string{"a"})
+	assert.Nil(t, err, "should not error for missing optional args when strict")
+	assert.Equal(t, []string{"a"}, params, "should return args as passed when strict")
+
+	// Should truncate extra args and allow missing args when padding
+	cc = newArgCountChaincode(PadArgCount)
+	params, err = cc.checkArgCount("argCountContract", "Create", nil, []string{"a", "1", "extra"})
+	assert.Nil(t, err, "should not error for extra args when padding")
+	assert.Equal(t, []string{"a", "1"}, params, "should remove extra args when padding")
+
+	params, err = cc.checkArgCount("argCountContract", "Create", nil, []string{})
+	assert.Nil(t, err, "should not error for missing args when padding")
+	assert.Equal(t, []string{}, params, "should return args as passed when padding")
+}
+
+func TestFunctionSignature(t *testing.T) {
+	cc := newArgCountChaincode(TruncateArgCount)
+	function := cc.contracts["argCountContract"].functions["Create"]
+
+	// Should use generated names without metadata
+	assert.Equal(t, "Create(param0 string, param1 int)", functionSignature("Create", function, nil), "should name params by position without metadata")
+
+	// Should use names of parameters in metadata
+	transaction := TransactionMetadata{Parameters: []ParameterMetadata{{Name: "id"}, {Name: "count"}}}
+	assert.Equal(t, "Create(id string, count int)", functionSignature("Create", function, &transaction), "should name params using metadata")
+}
+
+func TestPadArgCountMetadata(t *testing.T) {
+	// Should mark all parameters not required when padding
+	cc := newArgCountChaincode(PadArgCount)
+	assert.True(t, cc.contracts["argCountContract"].functions["Create"].padParams, "should pad params of functions")
+	assert.Equal(t, 0, cc.contracts["argCountContract"].functions["Create"].requiredParams(), "should not require params when padding")
+
+	for _, tx := range cc.metadata.Contracts["argCountContract"].Transactions {
+		for _, param := range tx.Parameters {
+			assert.False(t, param.Required, "should mark param not required when padding")
+		}
+	}
+
+	// Should not pad params by default
+	cc = newArgCountChaincode(TruncateArgCount)
+	assert.False(t, cc.contracts["argCountContract"].functions["Create"].padParams, "should not pad params by default")
+	assert.Equal(t, TruncateArgCount, cc.contracts["argCountContract"].argCountMode, "should set arg count mode of contract")
+}
+
+func TestInvokeArgCount(t *testing.T) {
+	// Should ignore extra args when truncating
+	cc := newArgCountChaincode(TruncateArgCount)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "a", "1", "extra"}, invokeType, "a 1")
+	callContractFunctionAndCheckError(t, cc, []string{"Create", "a"}, invokeType, "Incorrect number of params for function Create. Expected 2, received 1. Function signature is Create(param0 string, param1 int)")
+
+	// Should error for extra args when strict
+	cc = newArgCountChaincode(StrictArgCount)
+	callContractFunctionAndCheckError(t, cc, []string{"Create", "a", "1", "extra"}, invokeType, "Incorrect number of params for function Create. Expected 2, received 3. Function signature is Create(param0 string, param1 int)")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "a"}, invokeType, "a")
+
+	// Should pass zero values for missing args when padding
+	cc = newArgCountChaincode(PadArgCount)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "a"}, invokeType, "a 0")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create"}, invokeType, " 0")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "a", "b", "extra"}, invokeType, "a b")
+}
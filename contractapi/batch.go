@@ -0,0 +1,111 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// BatchTransactionName the name of the system contract transaction used to
+// make multiple invocations in a single transaction when batching is enabled
+const BatchTransactionName = "BatchInvoke"
+
+// BatchInvocation an invocation to be made as part of a batch transaction.
+// Function is the name of the function, optionally prefixed with the contract
+// name as when calling the chaincode directly.
+type BatchInvocation struct {
+	Function string   `json:"function"`
+	Args     []string `json:"args"`
+}
+
+// BatchResult the result of an invocation made as part of a batch transaction
+type BatchResult struct {
+	Status  int32  `json:"status"`
+	Payload string `json:"payload"`
+}
+
+// EnableBatchInvocation enables the BatchInvoke transaction of the system
+// contract. The transaction takes a JSON array of BatchInvocation and calls
+// each in turn as if they had been invoked separately. The results of each
+// are returned as a JSON array of BatchResult. If any invocation returns an
+// error then the batch stops and the error is returned so that none of the
+// invocations are committed.
+func (cc *ContractChaincode) EnableBatchInvocation() {
+	cc.batchInvocation = true
+}
+
+func (cc *ContractChaincode) batchInvoke(stub shim.ChaincodeStubInterface, params []string) peer.Response {
+	if len(params) != 1 {
+		return shim.Error(fmt.Sprintf("Incorrect number of params. Expected 1, received %d", len(params)))
+	}
+
+	invocations := []BatchInvocation{}
+
+	err := json.Unmarshal([]byte(params[0]), &invocations)
+
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Value %s was not passed in expected format []BatchInvocation", params[0]))
+	}
+
+	results := []BatchResult{}
+
+	for i, invocation := range invocations {
+		if invocation.Function == BatchTransactionName || invocation.Function == SystemContractName+":"+BatchTransactionName {
+			return shim.Error(fmt.Sprintf("Batch invocation %d failed. Batch transactions cannot be nested", i))
+		}
+
+		response := cc.Invoke(&batchStub{stub, invocation.Function, invocation.Args})
+
+		if response.Status >= shim.ERRORTHRESHOLD {
+			return shim.Error(fmt.Sprintf("Batch invocation %d failed. %s", i, response.Message))
+		}
+
+		results = append(results, BatchResult{response.Status, string(response.Payload)})
+	}
+
+	resultsJSON, _ := json.Marshal(results)
+
+	return shim.Success(resultsJSON)
+}
+
+// batchStub presents a single invocation of a batch as the function and args
+// of the transaction
+type batchStub struct {
+	shim.ChaincodeStubInterface
+	function string
+	args     []string
+}
+
+func (bs *batchStub) GetFunctionAndParameters() (string, []string) {
+	return bs.function, bs.args
+}
+
+func (bs *batchStub) GetStringArgs() []string {
+	return append([]string{bs.function}, bs.args...)
+}
+
+func (bs *batchStub) GetArgs() [][]byte {
+	args := [][]byte{}
+
+	for _, arg := range bs.GetStringArgs() {
+		args = append(args, []byte(arg))
+	}
+
+	return args
+}
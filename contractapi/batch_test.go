@@ -0,0 +1,78 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableBatchInvocation(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableBatchInvocation()
+
+	assert.True(t, cc.batchInvocation, "should enable batch invocation")
+}
+
+func TestBatchInvoke(t *testing.T) {
+	batchFn := SystemContractName + ":" + BatchTransactionName
+
+	mc := myContract{}
+	cc := convertC2CC(&mc)
+
+	// Should not be callable when not enabled
+	callContractFunctionAndCheckError(t, cc, []string{batchFn, "[]"}, invokeType, "Function BatchInvoke not found in contract org.hyperledger.fabric")
+
+	cc.EnableBatchInvocation()
+
+	// Should error when wrong number of params
+	callContractFunctionAndCheckError(t, cc, []string{batchFn}, invokeType, "Incorrect number of params. Expected 1, received 0")
+
+	// Should error when invocations are not valid JSON
+	callContractFunctionAndCheckError(t, cc, []string{batchFn, "not json"}, invokeType, "Value not json was not passed in expected format []BatchInvocation")
+
+	// Should error when batch is nested
+	callContractFunctionAndCheckError(t, cc, []string{batchFn, "[{\"function\":\"BatchInvoke\"}]"}, invokeType, "Batch invocation 0 failed. Batch transactions cannot be nested")
+	callContractFunctionAndCheckError(t, cc, []string{batchFn, "[{\"function\":\"" + batchFn + "\"}]"}, invokeType, "Batch invocation 0 failed. Batch transactions cannot be nested")
+
+	// Should error with index of failing invocation
+	callContractFunctionAndCheckError(t, cc, []string{batchFn, "[{\"function\":\"ReturnsString\"},{\"function\":\"myContract:ReturnsError\"}]"}, invokeType, "Batch invocation 1 failed. Some error")
+
+	// Should return results of each invocation in order
+	callContractFunctionAndCheckSuccess(t, cc, []string{batchFn, "[{\"function\":\"ReturnsString\"},{\"function\":\"myContract:UsesContext\",\"args\":[\"" + standardAssetID + "\",\"" + standardValue + "\"]},{\"function\":\"ReturnsNothing\"}]"}, invokeType, "[{\"status\":200,\"payload\":\"Some string\"},{\"status\":200,\"payload\":\"You called a function that uses the ctx\"},{\"status\":200,\"payload\":\"\"}]")
+
+	// Should return empty results for empty batch
+	callContractFunctionAndCheckSuccess(t, cc, []string{batchFn, "[]"}, invokeType, "[]")
+}
+
+func TestBatchStub(t *testing.T) {
+	stub := shimtest.NewMockStub("batchStubTest", nil)
+	stub.TxID = standardTxID
+
+	bs := batchStub{stub, "somefunction", []string{"arg1", "arg2"}}
+
+	fn, params := bs.GetFunctionAndParameters()
+	assert.Equal(t, "somefunction", fn, "should return function of invocation")
+	assert.Equal(t, []string{"arg1", "arg2"}, params, "should return args of invocation")
+	assert.Equal(t, []string{"somefunction", "arg1", "arg2"}, bs.GetStringArgs(), "should return function and args as string args")
+	assert.Equal(t, [][]byte{[]byte("somefunction"), []byte("arg1"), []byte("arg2")}, bs.GetArgs(), "should return function and args as args")
+	assert.Equal(t, standardTxID, bs.GetTxID(), "should pass through other calls to the stub")
+}
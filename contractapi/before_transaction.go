@@ -0,0 +1,52 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+)
+
+// BeforeTransactionResultKey is the key under which the value returned by a
+// before transaction is stored on the transaction context (see SetData)
+const BeforeTransactionResultKey = "contractapi.beforeTransactionResult"
+
+// GetBeforeTransactionResult returns the value returned by the before transaction
+// of the transaction and whether one was returned. When both the chaincode and the
+// contract set a before transaction returning a value, the contract's is returned.
+func (ctx *TransactionContext) GetBeforeTransactionResult() (interface{}, bool) {
+	return ctx.GetData(BeforeTransactionResultKey)
+}
+
+type dataSetterInterface interface {
+	SetData(string, interface{})
+}
+
+// callBeforeTransaction calls the before transaction storing the value it returns
+// on the context. An error returned by it is wrapped as a BeforeTransactionError
+func (cc *ContractChaincode) callBeforeTransaction(handler *transactionHandler, ns string, ctx reflect.Value, serializer Serializer) error {
+	result, err := cc.callHook(handler, ns, ctx, nil, serializer)
+
+	if err != nil {
+		return &BeforeTransactionError{err}
+	}
+
+	if handler.returns.success != nil {
+		if setter, ok := ctx.Interface().(dataSetterInterface); ok {
+			setter.SetData(BeforeTransactionResultKey, result)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type beforeResultTestContract struct {
+	Contract
+}
+
+func (brtc *beforeResultTestContract) ReadResult(ctx *TransactionContext) (string, error) {
+	result, ok := ctx.GetBeforeTransactionResult()
+
+	if !ok {
+		return "", errors.New("no before result")
+	}
+
+	return result.(string), nil
+}
+
+type customDataContext struct {
+	TransactionContext
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestGetBeforeTransactionResult(t *testing.T) {
+	ctx := new(TransactionContext)
+
+	// Should return false when no result stored
+	_, ok := ctx.GetBeforeTransactionResult()
+	assert.False(t, ok, "should not find result")
+
+	// Should return stored result
+	ctx.SetData(BeforeTransactionResultKey, "some value")
+	result, ok := ctx.GetBeforeTransactionResult()
+	assert.True(t, ok, "should find result")
+	assert.Equal(t, "some value", result, "should return stored result")
+}
+
+func TestCallBeforeTransaction(t *testing.T) {
+	var err error
+	var ctx *TransactionContext
+
+	cc := convertC2CC()
+
+	// Should wrap error as before transaction error
+	ctx = new(TransactionContext)
+	err = cc.callBeforeTransaction(newTransactionHandler(func() (string, error) { return "", errors.New("some error") }, basicContextPtrType, before), "some contract", reflect.ValueOf(ctx), nil)
+	assert.Equal(t, &BeforeTransactionError{errors.New("some error")}, err, "should return before transaction error")
+	_, ok := ctx.GetBeforeTransactionResult()
+	assert.False(t, ok, "should not store result on error")
+
+	// Should store result on context
+	ctx = new(TransactionContext)
+	err = cc.callBeforeTransaction(newTransactionHandler(func() string { return "some value" }, basicContextPtrType, before), "some contract", reflect.ValueOf(ctx), nil)
+	assert.Nil(t, err, "should not error")
+	result, _ := ctx.GetBeforeTransactionResult()
+	assert.Equal(t, "some value", result, "should store result")
+
+	// Should store result on custom context embedding transaction context
+	customCtx := new(customDataContext)
+	err = cc.callBeforeTransaction(newTransactionHandler(func() string { return "some value" }, reflect.TypeOf(customCtx), before), "some contract", reflect.ValueOf(customCtx), nil)
+	assert.Nil(t, err, "should not error for custom context")
+	result, _ = customCtx.GetBeforeTransactionResult()
+	assert.Equal(t, "some value", result, "should store result on custom context")
+
+	// Should not store result when before transaction has no success return
+	ctx = new(TransactionContext)
+	err = cc.callBeforeTransaction(newTransactionHandler(func() error { return nil }, basicContextPtrType, before), "some contract", reflect.ValueOf(ctx), nil)
+	assert.Nil(t, err, "should not error when no success return")
+	_, ok = ctx.GetBeforeTransactionResult()
+	assert.False(t, ok, "should not store result when no success return")
+}
+
+func TestInvokeWithBeforeTransactionResult(t *testing.T) {
+	var cc ContractChaincode
+	var brtc *beforeResultTestContract
+
+	// Should make before result available to named function
+	brtc = new(beforeResultTestContract)
+	brtc.SetBeforeTransaction(func() string { return "from before" })
+	cc = convertC2CC(brtc)
+	response := shimtest.NewMockStub("beforeTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("ReadResult")})
+	assert.Equal(t, "from before", string(response.Payload), "should pass before result to named function")
+
+	// Should use contract before result over chaincode before result
+	brtc = new(beforeResultTestContract)
+	brtc.SetBeforeTransaction(func() string { return "from contract" })
+	cc = convertC2CC(brtc)
+	cc.SetBeforeTransaction(func() string { return "from chaincode" })
+	response = shimtest.NewMockStub("beforeTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("ReadResult")})
+	assert.Equal(t, "from contract", string(response.Payload), "should pass contract before result to named function")
+
+	// Should not call named function when before errors
+	brtc = new(beforeResultTestContract)
+	brtc.SetBeforeTransaction(func() (string, error) { return "", NewError(403, "not allowed", nil) })
+	cc = convertC2CC(brtc)
+	response = shimtest.NewMockStub("beforeTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("ReadResult")})
+	assert.Equal(t, int32(403), response.Status, "should return status of before error")
+	assert.Equal(t, "Before transaction failed. not allowed", response.Message, "should return before error")
+}
@@ -0,0 +1,195 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var benchmarkArgs = map[string][]string{
+	"Noop":     {"Noop"},
+	"Basics":   {"Basics", "some string", "10", "1.5", "true"},
+	"Transfer": {"Transfer", `{"id":"asset1","owner":"Alice","value":100}`, "Bob"},
+	"Put":      {"Put", "asset1", "some value"},
+}
+
+type address struct {
+	Lines    []string `json:"lines"`
+	Postcode string   `json:"postcode"`
+}
+
+type party struct {
+	Name     string             `json:"name"`
+	Address  address            `json:"address"`
+	Contacts map[string]address `json:"contacts"`
+}
+
+type holding struct {
+	Asset Asset   `json:"asset"`
+	Owner party   `json:"owner"`
+	Past  []party `json:"past"`
+}
+
+type portfolio struct {
+	ID       string    `json:"id"`
+	Manager  party     `json:"manager"`
+	Holdings []holding `json:"holdings"`
+}
+
+// graphContract has functions taking and returning a deep graph of structs
+type graphContract struct {
+	contractapi.Contract
+}
+
+func (gc *graphContract) Create(p portfolio) portfolio { return p }
+
+func (gc *graphContract) AddHolding(p portfolio, h holding) portfolio { return p }
+
+func (gc *graphContract) Transfer(h holding, to party) holding { return h }
+
+func (gc *graphContract) Move(p party, a address) party { return p }
+
+func (gc *graphContract) Summary(p portfolio, currency string, detailed bool) string { return p.ID }
+
+// newGraphChaincode creates a chaincode of the passed number of graph contracts
+func newGraphChaincode(numContracts int) contractapi.ContractChaincode {
+	contracts := []contractapi.ContractInterface{}
+
+	for i := 0; i < numContracts; i++ {
+		gc := new(graphContract)
+		gc.SetName(fmt.Sprintf("graph%d", i))
+		contracts = append(contracts, gc)
+	}
+
+	return contractapi.CreateNewChaincode(contracts...)
+}
+
+func benchmarkInvoke(b *testing.B, chaincode shim.Chaincode, args []string) {
+	b.Helper()
+
+	stub := NewStub(chaincode)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if response := Invoke(stub, args...); response.Status != shim.OK {
+			b.Fatalf("Invoke failed. %s", response.Message)
+		}
+	}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestChaincodesMatch(t *testing.T) {
+	cc := NewChaincode()
+
+	for name, args := range benchmarkArgs {
+		expected := Invoke(NewStub(new(ShimChaincode)), args...)
+		actual := Invoke(NewStub(&cc), args...)
+
+		assert.Equal(t, int32(shim.OK), actual.Status, "should call "+name+" successfully")
+		assert.Equal(t, string(expected.Payload), string(actual.Payload), "should return same payload for "+name)
+	}
+}
+
+func BenchmarkShimNoop(b *testing.B) {
+	benchmarkInvoke(b, new(ShimChaincode), benchmarkArgs["Noop"])
+}
+
+func BenchmarkContractNoop(b *testing.B) {
+	cc := NewChaincode()
+	benchmarkInvoke(b, &cc, benchmarkArgs["Noop"])
+}
+
+func BenchmarkShimBasics(b *testing.B) {
+	benchmarkInvoke(b, new(ShimChaincode), benchmarkArgs["Basics"])
+}
+
+func BenchmarkContractBasics(b *testing.B) {
+	cc := NewChaincode()
+	benchmarkInvoke(b, &cc, benchmarkArgs["Basics"])
+}
+
+func BenchmarkShimTransfer(b *testing.B) {
+	benchmarkInvoke(b, new(ShimChaincode), benchmarkArgs["Transfer"])
+}
+
+func BenchmarkContractTransfer(b *testing.B) {
+	cc := NewChaincode()
+	benchmarkInvoke(b, &cc, benchmarkArgs["Transfer"])
+}
+
+func BenchmarkShimPut(b *testing.B) {
+	benchmarkInvoke(b, new(ShimChaincode), benchmarkArgs["Put"])
+}
+
+func BenchmarkContractPut(b *testing.B) {
+	cc := NewChaincode()
+	benchmarkInvoke(b, &cc, benchmarkArgs["Put"])
+}
+
+func BenchmarkCreateNewChaincode(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		newGraphChaincode(40)
+	}
+}
+
+func BenchmarkContractGraphFirstCall(b *testing.B) {
+	args := []string{"graph0:Move", `{"name":"Alice","address":{"lines":["1 Street"],"postcode":"AB1"},"contacts":{}}`, `{"lines":["2 Street"],"postcode":"AB2"}`}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		cc := newGraphChaincode(40)
+		stub := NewStub(&cc)
+		b.StartTimer()
+
+		if response := Invoke(stub, args...); response.Status != shim.OK {
+			b.Fatalf("Invoke failed. %s", response.Message)
+		}
+	}
+}
+
+func BenchmarkContractParallel(b *testing.B) {
+	cc := NewChaincode()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		stub := NewStub(&cc)
+
+		for pb.Next() {
+			if response := Invoke(stub, benchmarkArgs["Basics"]...); response.Status != shim.OK {
+				b.Fatalf("Invoke failed. %s", response.Message)
+			}
+		}
+	})
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// TxID the transaction ID used for benchmark transactions
+const TxID = "bench"
+
+// Asset a struct passed to and returned by benchmark functions
+type Asset struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+	Value int    `json:"value"`
+}
+
+// BenchContract the contract benchmarked through contractapi
+type BenchContract struct {
+	contractapi.Contract
+}
+
+// Noop takes and returns nothing so measures the cost of dispatch alone
+func (bc *BenchContract) Noop() {}
+
+// Basics takes basic types so measures the cost of converting args
+func (bc *BenchContract) Basics(str string, i int, f float64, b bool) string {
+	return fmt.Sprintf("%s %d %g %t", str, i, f, b)
+}
+
+// Transfer takes and returns a struct so measures the cost of serialization
+// and validating args against their schema
+func (bc *BenchContract) Transfer(asset Asset, owner string) Asset {
+	asset.Owner = owner
+
+	return asset
+}
+
+// Put writes to the world state so measures the cost of creating the context
+func (bc *BenchContract) Put(ctx *contractapi.TransactionContext, key string, value string) error {
+	return ctx.GetStub().PutState(key, []byte(value))
+}
+
+// NewChaincode returns the chaincode containing BenchContract
+func NewChaincode() contractapi.ContractChaincode {
+	return contractapi.CreateNewChaincode(new(BenchContract))
+}
+
+// ShimChaincode implements the functions of BenchContract directly against the
+// shim as a baseline
+type ShimChaincode struct{}
+
+// Init returns success
+func (sc *ShimChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	return shim.Success(nil)
+}
+
+// Invoke calls the named function converting args by hand
+func (sc *ShimChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, params := stub.GetFunctionAndParameters()
+
+	switch fn {
+	case "Noop":
+		return shim.Success(nil)
+	case "Basics":
+		if len(params) != 4 {
+			return shim.Error("Incorrect number of params")
+		}
+
+		i, err := strconv.Atoi(params[1])
+
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		f, err := strconv.ParseFloat(params[2], 64)
+
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		b, err := strconv.ParseBool(params[3])
+
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success([]byte(fmt.Sprintf("%s %d %g %t", params[0], i, f, b)))
+	case "Transfer":
+		if len(params) != 2 {
+			return shim.Error("Incorrect number of params")
+		}
+
+		asset := Asset{}
+
+		if err := json.Unmarshal([]byte(params[0]), &asset); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		asset.Owner = params[1]
+
+		assetBytes, _ := json.Marshal(asset)
+
+		return shim.Success(assetBytes)
+	case "Put":
+		if len(params) != 2 {
+			return shim.Error("Incorrect number of params")
+		}
+
+		if err := stub.PutState(params[0], []byte(params[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(nil)
+	default:
+		return shim.Error(fmt.Sprintf("Function %s not found", fn))
+	}
+}
+
+// NewStub returns a mock stub for the chaincode
+func NewStub(chaincode shim.Chaincode) *shimtest.MockStub {
+	return shimtest.NewMockStub("bench", chaincode)
+}
+
+// Invoke calls the chaincode of the stub with the args as a transaction
+func Invoke(stub *shimtest.MockStub, args ...string) peer.Response {
+	byteArgs := [][]byte{}
+
+	for _, arg := range args {
+		byteArgs = append(byteArgs, []byte(arg))
+	}
+
+	return stub.MockInvoke(TxID, byteArgs)
+}
@@ -0,0 +1,20 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bench benchmarks the overhead contractapi adds to transactions on top
+// of the shim. The benchmarks of the package compare calling functions of
+// BenchContract through contractapi with calling the equivalent functions of
+// ShimChaincode, which implements the shim interface directly. Both are defined
+// in the test files of the package so that it does not import shimtest.
+package bench
@@ -0,0 +1,111 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+
+	"github.com/go-openapi/spec"
+)
+
+// DecimalPattern the pattern the string representation of a Decimal must match
+const DecimalPattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+var decimalRegexp = regexp.MustCompile(DecimalPattern)
+
+// Decimal is an arbitrary-precision decimal number held as its string
+// representation e.g. "1250.75". Unlike float64 it is passed to and returned
+// from transactions without rounding. Use Rat to perform exact arithmetic
+// with the value and NewDecimalFromRat to convert the result back.
+type Decimal string
+
+// NewDecimal returns the passed value as a Decimal, erroring if it is not a
+// valid decimal number
+func NewDecimal(value string) (Decimal, error) {
+	if !decimalRegexp.MatchString(value) {
+		return "", fmt.Errorf("Value %s is not a valid decimal. Expected it to match %s", value, DecimalPattern)
+	}
+
+	return Decimal(value), nil
+}
+
+// NewDecimalFromRat returns the passed rational number as a Decimal rounded to
+// the passed number of decimal places
+func NewDecimalFromRat(r *big.Rat, places int) Decimal {
+	return Decimal(r.FloatString(places))
+}
+
+// Rat returns the value of the decimal as a big.Rat
+func (d Decimal) Rat() (*big.Rat, error) {
+	if _, err := NewDecimal(string(d)); err != nil {
+		return nil, err
+	}
+
+	r, _ := new(big.Rat).SetString(string(d))
+
+	return r, nil
+}
+
+// String returns the string representation of the decimal
+func (d Decimal) String() string {
+	return string(d)
+}
+
+type decimalType struct{}
+
+func (dt *decimalType) convert(value string) (reflect.Value, error) {
+	if value == "" {
+		value = "0"
+	}
+
+	decimal, err := NewDecimal(value)
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("Cannot convert passed value %s to contractapi.Decimal", value)
+	}
+
+	return reflect.ValueOf(decimal), nil
+}
+
+func (dt *decimalType) getSchema() *spec.Schema {
+	schema := spec.StringProperty().WithPattern(DecimalPattern)
+	schema.Format = "decimal"
+
+	return schema
+}
+
+type bigIntType struct{}
+
+func (bit *bigIntType) convert(value string) (reflect.Value, error) {
+	bigInt := new(big.Int)
+
+	if value != "" {
+		if _, ok := bigInt.SetString(value, 10); !ok {
+			return reflect.Value{}, fmt.Errorf("Cannot convert passed value %s to *big.Int", value)
+		}
+	}
+
+	return reflect.ValueOf(bigInt), nil
+}
+
+func (bit *bigIntType) getSchema() *spec.Schema {
+	schema := new(spec.Schema)
+	schema.Typed("integer", "")
+
+	return schema
+}
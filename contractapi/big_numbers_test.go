@@ -0,0 +1,179 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+const largeInteger = "123456789012345678901234567890"
+
+type bigNumbersTestAsset struct {
+	Supply *big.Int `json:"supply"`
+	Price  Decimal  `json:"price"`
+}
+
+type bigNumbersTestContract struct {
+	Contract
+}
+
+func (bntc *bigNumbersTestContract) Mint(supply *big.Int, amount *big.Int) *big.Int {
+	return new(big.Int).Add(supply, amount)
+}
+
+func (bntc *bigNumbersTestContract) Total(price Decimal, quantity int) (Decimal, error) {
+	r, err := price.Rat()
+
+	if err != nil {
+		return "", err
+	}
+
+	return NewDecimalFromRat(r.Mul(r, big.NewRat(int64(quantity), 1)), 2), nil
+}
+
+func (bntc *bigNumbersTestContract) Create(supply *big.Int, price Decimal) bigNumbersTestAsset {
+	return bigNumbersTestAsset{supply, price}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewDecimal(t *testing.T) {
+	var decimal Decimal
+	var err error
+
+	// Should return decimal for valid values
+	decimal, err = NewDecimal("-1250.75")
+	assert.Nil(t, err, "should not error for valid decimal")
+	assert.Equal(t, Decimal("-1250.75"), decimal, "should return decimal")
+	decimal, err = NewDecimal(largeInteger)
+	assert.Nil(t, err, "should not error for integer")
+	assert.Equal(t, Decimal(largeInteger), decimal, "should return integer decimal")
+
+	// Should error for invalid values
+	_, err = NewDecimal("1.2e3")
+	assert.EqualError(t, err, "Value 1.2e3 is not a valid decimal. Expected it to match "+DecimalPattern, "should error for exponent")
+	_, err = NewDecimal("1.")
+	assert.NotNil(t, err, "should error for missing decimal places")
+	_, err = NewDecimal("")
+	assert.NotNil(t, err, "should error for blank value")
+}
+
+func TestDecimalRat(t *testing.T) {
+	var r *big.Rat
+	var err error
+
+	// Should convert decimal to and from rat without rounding
+	r, err = Decimal("0.1").Rat()
+	assert.Nil(t, err, "should not error for valid decimal")
+	r.Add(r, big.NewRat(2, 10))
+	assert.Equal(t, Decimal("0.30"), NewDecimalFromRat(r, 2), "should perform exact arithmetic")
+	assert.Equal(t, Decimal("0.333"), NewDecimalFromRat(big.NewRat(1, 3), 3), "should round to places")
+
+	// Should error for invalid decimal
+	_, err = Decimal("abc").Rat()
+	assert.NotNil(t, err, "should error for invalid decimal")
+
+	assert.Equal(t, "1.5", Decimal("1.5").String(), "should return string")
+}
+
+func TestDecimalType(t *testing.T) {
+	var value reflect.Value
+	var err error
+
+	dt := new(decimalType)
+
+	// Should convert valid values
+	value, err = dt.convert("12.50")
+	assert.Nil(t, err, "should not error for valid value")
+	assert.Equal(t, Decimal("12.50"), value.Interface(), "should convert to decimal")
+	value, err = dt.convert("")
+	assert.Nil(t, err, "should not error for blank value")
+	assert.Equal(t, Decimal("0"), value.Interface(), "should convert blank to zero")
+
+	// Should error for invalid values
+	_, err = dt.convert("twelve")
+	assert.EqualError(t, err, "Cannot convert passed value twelve to contractapi.Decimal", "should error for invalid value")
+
+	expectedSchema := spec.StringProperty().WithPattern(DecimalPattern)
+	expectedSchema.Format = "decimal"
+	assert.Equal(t, expectedSchema, dt.getSchema(), "should return decimal schema")
+}
+
+func TestBigIntType(t *testing.T) {
+	var value reflect.Value
+	var err error
+
+	bit := new(bigIntType)
+
+	// Should convert valid values
+	value, err = bit.convert("-" + largeInteger)
+	assert.Nil(t, err, "should not error for valid value")
+	assert.Equal(t, "-"+largeInteger, value.Interface().(*big.Int).String(), "should convert to big int")
+	value, err = bit.convert("")
+	assert.Nil(t, err, "should not error for blank value")
+	assert.Equal(t, int64(0), value.Interface().(*big.Int).Int64(), "should convert blank to zero")
+
+	// Should error for invalid values
+	_, err = bit.convert("1.5")
+	assert.EqualError(t, err, "Cannot convert passed value 1.5 to *big.Int", "should error for invalid value")
+
+	assert.Equal(t, spec.StringOrArray{"integer"}, bit.getSchema().Type, "should return integer schema")
+}
+
+func TestBigNumberTypes(t *testing.T) {
+	// Should treat big numbers as valid non marshalling types
+	assert.True(t, isSpecialType(reflect.TypeOf(new(big.Int))), "should be special type for *big.Int")
+	assert.True(t, isSpecialType(reflect.TypeOf(Decimal(""))), "should be special type for Decimal")
+	assert.False(t, isSpecialType(reflect.TypeOf(big.Int{})), "should not be special type for big.Int")
+	assert.False(t, isMarshallingType(reflect.TypeOf(new(big.Int))), "should not marshal *big.Int")
+	assert.Nil(t, typeIsValid(reflect.TypeOf(new(big.Int)), []reflect.Type{}), "should be valid type")
+
+	// Should describe big numbers in metadata and components
+	components := ComponentMetadata{Schemas: make(map[string]ObjectMetadata)}
+	schema, err := getSchema(reflect.TypeOf(bigNumbersTestAsset{}), &components)
+	assert.Nil(t, err, "should not error getting schema")
+	assert.Equal(t, "#/components/schemas/bigNumbersTestAsset", schema.Ref.String(), "should reference component")
+	assert.Equal(t, *new(bigIntType).getSchema(), components.Schemas["bigNumbersTestAsset"].Properties["supply"], "should use big int schema for field")
+	assert.Equal(t, *new(decimalType).getSchema(), components.Schemas["bigNumbersTestAsset"].Properties["price"], "should use decimal schema for field")
+	_, ok := components.Schemas["Int"]
+	assert.False(t, ok, "should not add big int as component")
+}
+
+func TestInvokeWithBigNumbers(t *testing.T) {
+	cc := convertC2CC(new(bigNumbersTestContract))
+	cc.augmentMetadata()
+
+	// Should pass and return big ints without rounding
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Mint", largeInteger, "1"}, invokeType, "123456789012345678901234567891")
+	callContractFunctionAndCheckError(t, cc, []string{"Mint", "1.5", "1"}, invokeType, "Failed to convert arg for parameter 0 (param0) of function Mint. Expected type *big.Int, received \"1.5\". Param 1.5 could not be converted to type *big.Int")
+
+	// Should pass and return decimals without rounding
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Total", "0.10", "3"}, invokeType, "0.30")
+	callContractFunctionAndCheckError(t, cc, []string{"Total", "1e3", "3"}, invokeType, "Failed to convert arg for parameter 0 (param0) of function Total. Expected type contractapi.Decimal, received \"1e3\". Param 1e3 could not be converted to type contractapi.Decimal")
+
+	// Should marshal big numbers in structs
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", largeInteger, "99.99"}, invokeType, "{\"supply\":"+largeInteger+",\"price\":\"99.99\"}")
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	"github.com/go-openapi/spec"
+)
+
+// BinaryFormat the schema format of []byte parameters and returns passed untouched
+const BinaryFormat = "binary"
+
+// Base64Format the schema format of []byte parameters and returns passed base64
+// encoded and of []byte properties of structs, which are marshalled as base64
+const Base64Format = "byte"
+
+var bytesReflectType = reflect.TypeOf([]byte{})
+
+type bytesType struct{}
+
+func (bt *bytesType) convert(value string) (reflect.Value, error) {
+	return reflect.ValueOf([]byte(value)), nil
+}
+
+func (bt *bytesType) getSchema() *spec.Schema {
+	schema := spec.StringProperty()
+	schema.Format = Base64Format
+
+	return schema
+}
+
+// SetBase64Bytes sets whether []byte parameters and returns of the function are
+// passed base64 encoded rather than untouched. Their schemas in the metadata have
+// the format byte rather than binary. The format may also be set in a metadata
+// file.
+func (fc *FunctionConfig) SetBase64Bytes(base64 bool) *FunctionConfig {
+	fc.base64Bytes = base64
+	return fc
+}
+
+// setBinaryFormat marks the schema of a []byte parameter or return as binary
+// since, unlike []byte properties of structs, they are not marshalled
+func setBinaryFormat(schema *spec.Schema, t reflect.Type) {
+	if t == bytesReflectType {
+		schema.Format = BinaryFormat
+	}
+}
+
+func isBase64Bytes(t reflect.Type, schema *spec.Schema) bool {
+	return t == bytesReflectType && schema != nil && schema.Format == Base64Format
+}
+
+func decodeBytesArg(name string, arg string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(arg)
+
+	if err != nil {
+		return "", fmt.Errorf("Value passed for parameter \"%s\" is not valid base64. %s", name, err.Error())
+	}
+
+	return string(decoded), nil
+}
+
+func encodeBytesReturn(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var binaryTestValue = string([]byte{0x00, 0xff, 0x10, 'a'})
+
+type bytesTestDocument struct {
+	Hash []byte `json:"hash"`
+}
+
+type bytesTestContract struct {
+	Contract
+}
+
+func (btc *bytesTestContract) Echo(data []byte) []byte {
+	return data
+}
+
+func (btc *bytesTestContract) Length(data []byte) int {
+	return len(data)
+}
+
+func (btc *bytesTestContract) Document(hash []byte) bytesTestDocument {
+	return bytesTestDocument{hash}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestBytesType(t *testing.T) {
+	bt := new(bytesType)
+
+	// Should pass value untouched
+	value, err := bt.convert(binaryTestValue)
+	assert.Nil(t, err, "should not error converting bytes")
+	assert.Equal(t, []byte(binaryTestValue), value.Interface(), "should convert to bytes untouched")
+
+	// Should describe bytes as base64 string as marshalled in JSON
+	expectedSchema := spec.StringProperty()
+	expectedSchema.Format = Base64Format
+	assert.Equal(t, expectedSchema, bt.getSchema(), "should return base64 string schema")
+}
+
+func TestSetBase64Bytes(t *testing.T) {
+	var tm TransactionMetadata
+
+	binarySchema := spec.StringProperty()
+	binarySchema.Format = BinaryFormat
+
+	// Should set base64 bytes
+	fc := new(FunctionConfig)
+	assert.Equal(t, fc, fc.SetBase64Bytes(true), "should return config")
+	assert.True(t, fc.base64Bytes, "should set base64 bytes")
+
+	// Should change format of binary params and returns
+	tm = TransactionMetadata{Parameters: []ParameterMetadata{{Name: "param0", Schema: *binarySchema}, {Name: "param1", Schema: *spec.StringProperty()}}, Returns: spec.StringProperty()}
+	tm.Returns.Format = BinaryFormat
+	fc.applyTo(&tm)
+	assert.Equal(t, Base64Format, tm.Parameters[0].Schema.Format, "should set base64 format for binary param")
+	assert.Equal(t, "", tm.Parameters[1].Schema.Format, "should not set format of other params")
+	assert.Equal(t, Base64Format, tm.Returns.Format, "should set base64 format for binary return")
+
+	// Should not change format when not set
+	tm = TransactionMetadata{Parameters: []ParameterMetadata{{Name: "param0", Schema: *binarySchema}}}
+	new(FunctionConfig).applyTo(&tm)
+	assert.Equal(t, BinaryFormat, tm.Parameters[0].Schema.Format, "should leave binary format")
+}
+
+func TestBytesHelpers(t *testing.T) {
+	var schema *spec.Schema
+
+	// Should set binary format only for bytes
+	schema = spec.StringProperty()
+	setBinaryFormat(schema, bytesReflectType)
+	assert.Equal(t, BinaryFormat, schema.Format, "should set binary format for bytes")
+	schema = spec.StringProperty()
+	setBinaryFormat(schema, reflect.TypeOf(""))
+	assert.Equal(t, "", schema.Format, "should not set format for other types")
+
+	// Should identify base64 bytes by format
+	schema.Format = Base64Format
+	assert.True(t, isBase64Bytes(bytesReflectType, schema), "should be base64 for bytes with byte format")
+	assert.False(t, isBase64Bytes(reflect.TypeOf(""), schema), "should not be base64 for other types")
+	assert.False(t, isBase64Bytes(bytesReflectType, nil), "should not be base64 without schema")
+	assert.False(t, isBase64Bytes(bytesReflectType, spec.StringProperty()), "should not be base64 without format")
+
+	// Should decode and encode base64
+	decoded, err := decodeBytesArg("data", base64.StdEncoding.EncodeToString([]byte(binaryTestValue)))
+	assert.Nil(t, err, "should not error for valid base64")
+	assert.Equal(t, binaryTestValue, decoded, "should decode base64")
+	_, err = decodeBytesArg("data", "not base64!")
+	assert.Contains(t, err.Error(), "Value passed for parameter \"data\" is not valid base64.", "should error for invalid base64")
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(binaryTestValue)), encodeBytesReturn(binaryTestValue), "should encode base64")
+}
+
+func TestBytesSerializer(t *testing.T) {
+	serializer := new(JSONSerializer)
+
+	// Should pass bytes through untouched
+	value, err := serializer.FromString(binaryTestValue, bytesReflectType)
+	assert.Nil(t, err, "should not error converting bytes")
+	assert.Equal(t, []byte(binaryTestValue), value.Interface(), "should convert bytes untouched")
+
+	str, err := serializer.ToString(reflect.ValueOf([]byte(binaryTestValue)), bytesReflectType)
+	assert.Nil(t, err, "should not error converting bytes to string")
+	assert.Equal(t, binaryTestValue, str, "should return bytes untouched")
+
+	str, _ = serializer.ToString(reflect.ValueOf([]byte(nil)), bytesReflectType)
+	assert.Equal(t, "", str, "should return nil bytes as blank")
+}
+
+func TestInvokeWithBytes(t *testing.T) {
+	var cc ContractChaincode
+
+	// Should pass bytes through untouched
+	cc = convertC2CC(new(bytesTestContract))
+	assert.Equal(t, BinaryFormat, cc.metadata.Contracts["bytesTestContract"].Transactions[1].Parameters[0].Schema.Format, "should describe bytes param as binary")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Echo", binaryTestValue}, invokeType, binaryTestValue)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Length", binaryTestValue}, invokeType, "4")
+
+	// Should marshal bytes in structs as base64
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Document", "abc"}, invokeType, "{\"hash\":\"YWJj\"}")
+
+	// Should decode and encode bytes as base64 when configured
+	btc := new(bytesTestContract)
+	btc.ConfigureFunction("Echo").SetBase64Bytes(true)
+	btc.ConfigureFunction("Length").SetBase64Bytes(true)
+	cc = convertC2CC(btc)
+	encoded := base64.StdEncoding.EncodeToString([]byte(binaryTestValue))
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Echo", encoded}, invokeType, encoded)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Length", encoded}, invokeType, "4")
+	callContractFunctionAndCheckError(t, cc, []string{"Length", "not base64!"}, invokeType, "Value passed for parameter \"param0\" is not valid base64. illegal base64 data at input byte 3")
+}
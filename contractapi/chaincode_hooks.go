@@ -0,0 +1,81 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+)
+
+// SetBeforeTransaction sets a function to be called before every transaction of
+// every contract in the chaincode, ahead of the contract's own before transaction.
+// The function takes the same form as a contract's before transaction except that
+// its context param may be an interface, e.g. TransactionContextInterface, implemented
+// by the transaction contexts of all contracts. Panics if the function is not valid
+// for any contract. The system contract is not affected
+func (cc *ContractChaincode) SetBeforeTransaction(fn interface{}) {
+	cc.beforeTransactions = cc.newChaincodeTransactionHandlers(fn, before)
+}
+
+// SetAfterTransaction sets a function to be called after every transaction of
+// every contract in the chaincode, following the contract's own after transaction.
+// The function takes the same form as a contract's after transaction except that
+// its context param may be an interface implemented by the transaction contexts of
+// all contracts. Panics if the function is not valid for any contract. The system
+// contract is not affected
+func (cc *ContractChaincode) SetAfterTransaction(fn interface{}) {
+	cc.afterTransactions = cc.newChaincodeTransactionHandlers(fn, after)
+}
+
+func (cc *ContractChaincode) newChaincodeTransactionHandlers(fn interface{}, handlesType transactionHandlerType) map[string]*transactionHandler {
+	if fn == nil {
+		return nil
+	}
+
+	handlers := make(map[string]*transactionHandler)
+
+	for name, contract := range cc.contracts {
+		if name == SystemContractName {
+			continue
+		}
+
+		handlers[name] = newTransactionHandler(fn, chaincodeHandlerContextType(fn, contract.transactionContextPtrHandler), handlesType)
+	}
+
+	return handlers
+}
+
+func chaincodeHandlerContextType(fn interface{}, contextPtrHandler reflect.Type) reflect.Type {
+	fnType := reflect.TypeOf(fn)
+
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return contextPtrHandler
+	}
+
+	contextIndex := 0
+
+	if fnType.NumIn() > 0 && fnType.In(0) == goContextType {
+		contextIndex++
+	}
+
+	if fnType.NumIn() > contextIndex {
+		firstParam := fnType.In(contextIndex)
+
+		if firstParam.Kind() == reflect.Interface && firstParam.NumMethod() > 0 && contextPtrHandler.Implements(firstParam) {
+			return firstParam
+		}
+	}
+
+	return contextPtrHandler
+}
@@ -0,0 +1,141 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var hookCalls []string
+
+type hooksTestContract struct {
+	Contract
+}
+
+func (htc *hooksTestContract) DoSomething() string {
+	hookCalls = append(hookCalls, "named")
+	return "done"
+}
+
+func newHooksTestContract() *hooksTestContract {
+	htc := new(hooksTestContract)
+	htc.SetBeforeTransaction(func() { hookCalls = append(hookCalls, "contract before") })
+	htc.SetAfterTransaction(func(data interface{}) { hookCalls = append(hookCalls, "contract after") })
+
+	return htc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetChaincodeBeforeTransaction(t *testing.T) {
+	sc := new(simpleTestContractWithCustomContext)
+	sc.SetTransactionContextHandler(new(customContext))
+	cc := convertC2CC(new(simpleTestContract), sc)
+
+	// Should create handler for each non system contract
+	cc.SetBeforeTransaction(func(ctx TransactionContextInterface) {})
+	assert.Len(t, cc.beforeTransactions, 2, "should create handler for each contract")
+	assert.Contains(t, cc.beforeTransactions, "simpleTestContract", "should create handler for contract")
+	assert.Contains(t, cc.beforeTransactions, "simpleTestContractWithCustomContext", "should create handler for custom context contract")
+	assert.Equal(t, before, cc.beforeTransactions["simpleTestContract"].handlesType, "should create before handler")
+
+	// Should clear handlers when nil
+	cc.SetBeforeTransaction(nil)
+	assert.Nil(t, cc.beforeTransactions, "should clear handlers")
+
+	// Should panic when context does not match a contract
+	assert.Panics(t, func() { cc.SetBeforeTransaction(func(ctx *TransactionContext) {}) }, "should panic when context not valid for custom context contract")
+
+	// Should panic when function not valid before transaction
+	assert.PanicsWithValue(t, "Before transactions may not take any params other than the transaction context", func() { cc.SetBeforeTransaction(func(str string) {}) }, "should panic when before takes params")
+}
+
+func TestSetChaincodeAfterTransaction(t *testing.T) {
+	cc := convertC2CC(new(simpleTestContract))
+
+	// Should create handler for each non system contract
+	cc.SetAfterTransaction(func(ctx TransactionContextInterface, data interface{}) {})
+	assert.Len(t, cc.afterTransactions, 1, "should create handler for each contract")
+	assert.Equal(t, after, cc.afterTransactions["simpleTestContract"].handlesType, "should create after handler")
+
+	// Should panic when function not valid after transaction
+	assert.PanicsWithValue(t, "After transactions must take at most one non-context param", func() { cc.SetAfterTransaction(func(a interface{}, b interface{}) {}) }, "should panic when after takes too many params")
+}
+
+func TestChaincodeHandlerContextType(t *testing.T) {
+	ctxType := reflect.TypeOf(new(customContext))
+	ifaceType := reflect.TypeOf((*TransactionContextInterface)(nil)).Elem()
+
+	// Should use interface when implemented by context
+	assert.Equal(t, ifaceType, chaincodeHandlerContextType(func(ctx TransactionContextInterface) {}, ctxType), "should use interface")
+	assert.Equal(t, ifaceType, chaincodeHandlerContextType(func(goCtx context.Context, ctx TransactionContextInterface) {}, ctxType), "should use interface following context.Context")
+
+	// Should use context type otherwise
+	assert.Equal(t, ctxType, chaincodeHandlerContextType(func(ctx *customContext) {}, ctxType), "should use context type")
+	assert.Equal(t, ctxType, chaincodeHandlerContextType(func(data interface{}) {}, ctxType), "should use context type for empty interface")
+	assert.Equal(t, ctxType, chaincodeHandlerContextType(func() {}, ctxType), "should use context type when no params")
+	assert.Equal(t, ctxType, chaincodeHandlerContextType(func(goCtx context.Context) {}, ctxType), "should use context type when only context.Context")
+	assert.Equal(t, ctxType, chaincodeHandlerContextType(nil, ctxType), "should use context type when no function")
+}
+
+func TestChaincodeHooksInvoke(t *testing.T) {
+	var cc ContractChaincode
+
+	// Should call chaincode hooks around contract hooks
+	hookCalls = []string{}
+	cc = convertC2CC(newHooksTestContract())
+	cc.SetBeforeTransaction(func(ctx TransactionContextInterface) { hookCalls = append(hookCalls, "chaincode before") })
+	cc.SetAfterTransaction(func(ctx TransactionContextInterface, data interface{}) {
+		hookCalls = append(hookCalls, "chaincode after "+data.(string))
+	})
+
+	mockStub := shimtest.NewMockStub("hooksTest", &cc)
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("DoSomething")})
+	assert.Equal(t, "done", string(response.Payload), "should return value of named function")
+	assert.Equal(t, []string{"chaincode before", "contract before", "named", "contract after", "chaincode after done"}, hookCalls, "should call hooks in order")
+
+	// Should not call system contract hooks
+	hookCalls = []string{}
+	mockStub.MockInvoke(standardTxID, [][]byte{[]byte(SystemContractName + ":GetMetadata")})
+	assert.Equal(t, []string{}, hookCalls, "should not call hooks for system contract")
+
+	// Should return error of chaincode before and not call named
+	hookCalls = []string{}
+	cc = convertC2CC(newHooksTestContract())
+	cc.SetBeforeTransaction(func() error { return errors.New("before failed") })
+	mockStub = shimtest.NewMockStub("hooksTest", &cc)
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("DoSomething")})
+	assert.Equal(t, "Before transaction failed. before failed", response.Message, "should return before error")
+	assert.Equal(t, []string{}, hookCalls, "should not call contract hooks or named function")
+
+	// Should return error of chaincode after
+	cc = convertC2CC(newHooksTestContract())
+	cc.SetAfterTransaction(func() error { return errors.New("after failed") })
+	mockStub = shimtest.NewMockStub("hooksTest", &cc)
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("DoSomething")})
+	assert.Equal(t, "after failed", response.Message, "should return after error")
+}
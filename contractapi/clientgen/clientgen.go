@@ -0,0 +1,429 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package clientgen generates typed client bindings for a chaincode from its
+// metadata, as returned by the GetMetadata transaction of the system contract,
+// so that applications call transactions with the types of the contracts rather
+// than marshalling args by hand. Go bindings are generated by GenerateGo and
+// TypeScript bindings by GenerateTypeScript. Both call transactions through the
+// contract of a Fabric gateway network.
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+// toArgSource the function of the generated Go packages converting a value to
+// an arg. Basic types are formatted as the contractapi parses them and others
+// marshalled to JSON.
+const toArgSource = `func toArg(value interface{}) (string, error) {
+	switch value.(type) {
+	case string, bool, int64, float64:
+		return fmt.Sprint(value), nil
+	}
+
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal argument. %s", err.Error())
+	}
+
+	return string(bytes), nil
+}
+`
+
+// goReservedWords are names used by the generated Go methods that params must not shadow
+var goReservedWords = []string{"c", "txArgs", "err", "out", "payload", "json", "fmt", "toArg"}
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by contractapi-bindgen. DO NOT EDIT.
+
+// Package {{.Package}} provides typed bindings for calling the transactions
+// of the {{.Title}} chaincode through a Fabric gateway
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Contract is met by the contract of a gateway network e.g. *gateway.Contract
+type Contract interface {
+	SubmitTransaction(name string, args ...string) ([]byte, error)
+	EvaluateTransaction(name string, args ...string) ([]byte, error)
+}
+{{range .Types}}
+// {{.Name}} is the {{.Schema}} component of the chaincode metadata
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JSONName}}{{if not .Required}},omitempty{{end}}\"`" + `
+{{- end}}
+}
+{{end}}
+{{- range .Contracts}}
+// {{.TypeName}} calls the transactions of the {{.Name}} contract
+type {{.TypeName}} struct {
+	contract Contract
+}
+
+// New{{.TypeName}} returns bindings for the {{.Name}} contract calling its
+// transactions through the passed gateway contract
+func New{{.TypeName}}(contract Contract) *{{.TypeName}} {
+	return &{{.TypeName}}{contract}
+}
+{{$contract := .}}{{range .Transactions}}{{$tx := .}}
+// {{.Method}} {{if .Evaluate}}evaluates{{else}}submits{{end}} {{.Name}}.{{if .Description}} {{.Description}}{{end}}
+func (c *{{$contract.TypeName}}) {{.Method}}({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) {{if .Returns}}({{.Returns}}, error){{else}}error{{end}} {
+{{- if .Returns}}
+	var out {{.Returns}}
+{{end}}
+	txArgs := []string{}
+{{range .Parameters}}
+{{- if .IsString}}
+	txArgs = append(txArgs, {{.Name}})
+{{else}}
+	{{.Name}}Arg, err := toArg({{.Name}})
+
+	if err != nil {
+		return {{if $tx.Returns}}out, {{end}}err
+	}
+
+	txArgs = append(txArgs, {{.Name}}Arg)
+{{end}}
+{{- end}}
+{{- if .Returns}}
+	payload, err := c.contract.{{if .Evaluate}}Evaluate{{else}}Submit{{end}}Transaction("{{$contract.Name}}:{{.Name}}", txArgs...)
+
+	if err != nil {
+		return out, err
+	}
+{{if .ReturnsString}}
+	return string(payload), nil
+{{- else}}
+	err = json.Unmarshal(payload, &out)
+
+	if err != nil {
+		return out, fmt.Errorf("Failed to unmarshal result of {{.Name}}. %s", err.Error())
+	}
+
+	return out, nil
+{{- end}}
+{{- else}}
+	if _, err := c.contract.{{if .Evaluate}}Evaluate{{else}}Submit{{end}}Transaction("{{$contract.Name}}:{{.Name}}", txArgs...); err != nil {
+		return err
+	}
+
+	return nil
+{{- end}}
+}
+{{end}}{{end}}
+` + toArgSource))
+
+type bindingField struct {
+	Name     string
+	JSONName string
+	Type     string
+	Required bool
+}
+
+type bindingType struct {
+	Name   string
+	Schema string
+	Fields []bindingField
+}
+
+type bindingParameter struct {
+	Name     string
+	Type     string
+	IsString bool
+}
+
+type bindingTransaction struct {
+	Name          string
+	Method        string
+	Description   string
+	Evaluate      bool
+	Parameters    []bindingParameter
+	Returns       string
+	ReturnsString bool
+}
+
+type bindingContract struct {
+	Name         string
+	TypeName     string
+	Transactions []bindingTransaction
+}
+
+// typeMapper converts the schemas of the metadata to the types of a language
+type typeMapper struct {
+	basic           map[string]string
+	any             string
+	identifier      func(name string, exported bool) string
+	array           func(item string) string
+	mapOf           func(value string) string
+	exportedMethods bool
+}
+
+func (tm typeMapper) typeOf(schema spec.Schema) string {
+	if ref := schema.Ref.String(); ref != "" {
+		return tm.identifier(strings.TrimPrefix(ref, componentsPrefix), true)
+	}
+
+	if len(schema.Type) != 1 {
+		return tm.any
+	}
+
+	switch schema.Type[0] {
+	case "array":
+		if schema.Items != nil && schema.Items.Schema != nil {
+			return tm.array(tm.typeOf(*schema.Items.Schema))
+		}
+
+		return tm.array(tm.any)
+	case "object":
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			return tm.mapOf(tm.typeOf(*schema.AdditionalProperties.Schema))
+		}
+
+		return tm.mapOf(tm.any)
+	}
+
+	if basic, ok := tm.basic[schema.Type[0]]; ok {
+		return basic
+	}
+
+	return tm.any
+}
+
+var goTypes = typeMapper{
+	basic:           map[string]string{"string": "string", "integer": "int64", "number": "float64", "boolean": "bool"},
+	any:             "interface{}",
+	identifier:      goIdentifier,
+	array:           func(item string) string { return "[]" + item },
+	mapOf:           func(value string) string { return "map[string]" + value },
+	exportedMethods: true,
+}
+
+// bindings holds the details of the metadata needed to generate bindings of
+// either language
+type bindings struct {
+	Package   string
+	Title     string
+	Types     []bindingType
+	Contracts []bindingContract
+}
+
+func newBindings(ccm contractapi.ContractChaincodeMetadata, types typeMapper) bindings {
+	b := bindings{Title: ccm.Info.Title}
+
+	schemaNames := []string{}
+
+	for name := range ccm.Components.Schemas {
+		schemaNames = append(schemaNames, name)
+	}
+
+	sort.Strings(schemaNames)
+
+	for _, name := range schemaNames {
+		object := ccm.Components.Schemas[name]
+		bt := bindingType{Name: types.identifier(name, true), Schema: name}
+
+		propertyNames := []string{}
+
+		for property := range object.Properties {
+			propertyNames = append(propertyNames, property)
+		}
+
+		sort.Strings(propertyNames)
+
+		for _, property := range propertyNames {
+			bt.Fields = append(bt.Fields, bindingField{
+				Name:     types.identifier(property, true),
+				JSONName: property,
+				Type:     types.typeOf(object.Properties[property]),
+				Required: stringInSlice(property, object.Required),
+			})
+		}
+
+		b.Types = append(b.Types, bt)
+	}
+
+	contractNames := []string{}
+
+	for name := range ccm.Contracts {
+		if name != contractapi.SystemContractName {
+			contractNames = append(contractNames, name)
+		}
+	}
+
+	sort.Strings(contractNames)
+
+	for _, name := range contractNames {
+		contract := bindingContract{Name: name, TypeName: types.identifier(name, true) + "Contract"}
+
+		for _, tx := range ccm.Contracts[name].Transactions {
+			btx := bindingTransaction{
+				Name:        tx.Name,
+				Method:      types.identifier(tx.Name, types.exportedMethods),
+				Description: tx.Description,
+				Evaluate:    stringInSlice("evaluateTx", tx.Tag),
+			}
+
+			for _, param := range tx.Parameters {
+				btx.Parameters = append(btx.Parameters, bindingParameter{
+					Name:     types.identifier(param.Name, false),
+					Type:     types.typeOf(param.Schema),
+					IsString: isStringSchema(param.Schema),
+				})
+			}
+
+			if tx.Returns != nil {
+				btx.Returns = types.typeOf(*tx.Returns)
+				btx.ReturnsString = isStringSchema(*tx.Returns)
+			}
+
+			contract.Transactions = append(contract.Transactions, btx)
+		}
+
+		b.Contracts = append(b.Contracts, contract)
+	}
+
+	return b
+}
+
+// GenerateGo returns the source of a Go package, with the passed name, of typed
+// bindings for the chaincode described by the metadata. The package has a struct
+// for each component of the metadata and for each contract, other than the system
+// contract, whose methods call its transactions through a gateway contract. Args
+// of transactions are passed as JSON, other than strings which are passed as they
+// are, and results unmarshalled into the return type of the transaction.
+func GenerateGo(ccm contractapi.ContractChaincodeMetadata, packageName string) ([]byte, error) {
+	if !IsIdentifier(packageName) {
+		return nil, fmt.Errorf("Package %s is not a valid package name", packageName)
+	}
+
+	b := newBindings(ccm, goTypes)
+	b.Package = packageName
+
+	buf := new(bytes.Buffer)
+	err := goTemplate.Execute(buf, b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format bindings. %s", err.Error())
+	}
+
+	return formatted, nil
+}
+
+func isStringSchema(schema spec.Schema) bool {
+	return schema.Ref.String() == "" && len(schema.Type) == 1 && schema.Type[0] == "string"
+}
+
+func isBasicSchema(schema spec.Schema) bool {
+	if schema.Ref.String() != "" || len(schema.Type) != 1 {
+		return false
+	}
+
+	_, ok := goTypes.basic[schema.Type[0]]
+
+	return ok
+}
+
+// IsIdentifier returns whether the name is a Go identifier that is not a keyword,
+// e.g. so can be used as the name of a generated package or type
+func IsIdentifier(name string) bool {
+	if name == "" || isKeyword(name) {
+		return false
+	}
+
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isKeyword(name string) bool {
+	return token.Lookup(name).IsKeyword()
+}
+
+func goIdentifier(name string, exported bool) string {
+	identifier := toIdentifier(name, exported)
+
+	if isKeyword(identifier) || stringInSlice(identifier, goReservedWords) {
+		identifier += "_"
+	}
+
+	return identifier
+}
+
+// toIdentifier converts the name to an identifier by removing characters other
+// than letters, digits and underscores and capitalising the letter following each
+func toIdentifier(name string, exported bool) string {
+	runes := []rune{}
+	upperNext := exported
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			upperNext = len(runes) > 0 || exported
+			continue
+		}
+
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+
+		runes = append(runes, r)
+	}
+
+	if len(runes) == 0 || unicode.IsDigit(runes[0]) {
+		runes = append([]rune{'X'}, runes...)
+	}
+
+	if !exported {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+
+	return string(runes)
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+
+	return false
+}
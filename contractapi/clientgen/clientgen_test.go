@@ -0,0 +1,171 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clientgen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func testMetadata() contractapi.ContractChaincodeMetadata {
+	ccm := contractapi.ContractChaincodeMetadata{}
+	ccm.Info.Title = "assets"
+
+	ccm.Components.Schemas = map[string]contractapi.ObjectMetadata{
+		"Asset": {
+			Properties: map[string]spec.Schema{
+				"id":          *spec.StringProperty(),
+				"value":       *spec.Int64Property(),
+				"owner-names": *spec.ArrayProperty(spec.StringProperty()),
+				"history":     *spec.MapProperty(spec.RefSchema("#/components/schemas/Event")),
+			},
+			Required: []string{"id", "value"},
+		},
+		"Event": {
+			Properties: map[string]spec.Schema{
+				"type": *spec.StringProperty(),
+			},
+			Required: []string{"type"},
+		},
+	}
+
+	ccm.Contracts = map[string]contractapi.ContractMetadata{
+		"assets": {
+			Name: "assets",
+			Transactions: []contractapi.TransactionMetadata{
+				{
+					Name: "CreateAsset",
+					Tag:  []string{"submitTx"},
+					Parameters: []contractapi.ParameterMetadata{
+						{Name: "id", Schema: *spec.StringProperty()},
+						{Name: "value", Schema: *spec.Int64Property()},
+					},
+				},
+				{
+					Name:        "ReadAsset",
+					Description: "Returns the asset with the ID.",
+					Tag:         []string{"evaluateTx"},
+					Parameters: []contractapi.ParameterMetadata{
+						{Name: "id", Schema: *spec.StringProperty()},
+					},
+					Returns: spec.RefSchema("#/components/schemas/Asset"),
+				},
+				{
+					Name: "GetOwner",
+					Tag:  []string{"evaluateTx"},
+					Parameters: []contractapi.ParameterMetadata{
+						{Name: "asset", Schema: *spec.RefSchema("#/components/schemas/Asset")},
+					},
+					Returns: spec.StringProperty(),
+				},
+			},
+		},
+		contractapi.SystemContractName: {
+			Name: contractapi.SystemContractName,
+			Transactions: []contractapi.TransactionMetadata{
+				{Name: "GetMetadata", Returns: spec.StringProperty()},
+			},
+		},
+	}
+
+	return ccm
+}
+
+func typeCheck(t *testing.T, src []byte) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bindings.go", src, parser.ParseComments)
+	assert.Nil(t, err, "should generate parsable go")
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("client", fset, []*ast.File{file}, nil)
+	assert.Nil(t, err, "should generate go that type checks")
+
+	return pkg
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestIsIdentifier(t *testing.T) {
+	assert.True(t, IsIdentifier("assetclient"), "should be true for lower case name")
+	assert.True(t, IsIdentifier("_Asset1"), "should be true for name with underscore and digit")
+	assert.False(t, IsIdentifier(""), "should be false for empty name")
+	assert.False(t, IsIdentifier("1asset"), "should be false for name starting with digit")
+	assert.False(t, IsIdentifier("asset client"), "should be false for name containing space")
+	assert.False(t, IsIdentifier("func"), "should be false for keyword")
+}
+
+func TestGoIdentifier(t *testing.T) {
+	assert.Equal(t, "OwnerNames", goIdentifier("owner-names", true), "should capitalise letters following removed characters")
+	assert.Equal(t, "ownerNames", goIdentifier("owner names", false), "should lower the first letter when not exported")
+	assert.Equal(t, "X1st", goIdentifier("1st", true), "should prefix identifiers starting with a digit")
+	assert.Equal(t, "type_", goIdentifier("type", false), "should suffix keywords")
+	assert.Equal(t, "txArgs_", goIdentifier("txArgs", false), "should suffix names used by the generated methods")
+}
+
+func TestGenerateGo(t *testing.T) {
+	var src []byte
+	var err error
+
+	src, err = GenerateGo(testMetadata(), "not-valid")
+	assert.EqualError(t, err, "Package not-valid is not a valid package name", "should error when package name not an identifier")
+	assert.Nil(t, src, "should not return source on error")
+
+	src, err = GenerateGo(testMetadata(), "client")
+	assert.Nil(t, err, "should not error for valid metadata")
+
+	pkg := typeCheck(t, src)
+	scope := pkg.Scope()
+
+	asset := scope.Lookup("Asset").Type().Underlying().(*types.Struct)
+	fields := map[string]string{}
+	tags := map[string]string{}
+
+	for i := 0; i < asset.NumFields(); i++ {
+		fields[asset.Field(i).Name()] = asset.Field(i).Type().String()
+		tags[asset.Field(i).Name()] = asset.Tag(i)
+	}
+
+	assert.Equal(t, map[string]string{"History": "map[string]client.Event", "Id": "string", "OwnerNames": "[]string", "Value": "int64"}, fields, "should generate struct fields of schema types")
+	assert.Equal(t, `json:"id"`, tags["Id"], "should tag required properties without omitempty")
+	assert.Equal(t, `json:"owner-names,omitempty"`, tags["OwnerNames"], "should tag optional properties with omitempty")
+
+	contract := types.NewPointer(scope.Lookup("AssetsContract").Type())
+	methods := types.NewMethodSet(contract)
+
+	assert.Equal(t, "func(id string, value int64) error", methods.Lookup(pkg, "CreateAsset").Type().String(), "should generate method for transaction without return")
+	assert.Equal(t, "func(id string) (client.Asset, error)", methods.Lookup(pkg, "ReadAsset").Type().String(), "should generate method for transaction with return")
+	assert.Equal(t, "func(asset client.Asset) (string, error)", methods.Lookup(pkg, "GetOwner").Type().String(), "should generate method taking component")
+	assert.Nil(t, scope.Lookup("OrgHyperledgerFabricContract"), "should not generate bindings for the system contract")
+
+	assert.Contains(t, string(src), `c.contract.SubmitTransaction("assets:CreateAsset", txArgs...)`, "should submit transactions not tagged evaluate")
+	assert.Contains(t, string(src), `c.contract.EvaluateTransaction("assets:ReadAsset", txArgs...)`, "should evaluate transactions tagged evaluate")
+	assert.Contains(t, string(src), "// ReadAsset evaluates ReadAsset. Returns the asset with the ID.", "should document methods with transaction description")
+}
@@ -0,0 +1,300 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clientgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"text/template"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+)
+
+// stubReservedWords are names used by the generated stub methods that params must not shadow
+var stubReservedWords = []string{"c", "args", "err", "json", "fmt", "toArg"}
+
+var stubTemplate = template.Must(template.New("stub").Parse(`// Code generated by contractapi-gen. DO NOT EDIT.
+
+// Package {{.Package}} provides functions for building the arguments to pass
+// when submitting or evaluating transactions of the {{.Title}} chaincode
+package {{.Package}}
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+{{range .Contracts}}
+// {{.TypeName}} builds calls to the transactions of the {{.Name}} contract
+type {{.TypeName}} struct{}
+{{$contract := .}}{{range .Transactions}}
+// {{.Method}} returns the function name and arguments for calling {{.Name}}.{{if .Description}} {{.Description}}{{end}}
+// It should be {{if .Evaluate}}evaluated{{else}}submitted{{end}}
+func (c *{{$contract.TypeName}}) {{.Method}}({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) (string, []string, error) {
+	args := []string{}
+{{range .Parameters}}
+	{{.Name}}Arg, err := toArg({{.Name}})
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	args = append(args, {{.Name}}Arg)
+{{end}}
+	return "{{$contract.Name}}:{{.Name}}", args, nil
+}
+{{if .Pagination}}
+// {{.Method}}Pages returns a pager over the pages of results of {{.Name}}, each page
+// holding at most {{.Pagination.PageSize}} results
+func (c *{{$contract.TypeName}}) {{.Method}}Pages({{range $i, $p := .Pagination.Parameters}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) *Pager {
+	return &Pager{
+		pageSize:         int64({{.Pagination.PageSize}}),
+		resultsProperty:  "{{.Pagination.ResultsProperty}}",
+		metadataProperty: "{{.Pagination.MetadataProperty}}",
+		call: func(pageBookmark string) (string, []string, error) {
+			return c.{{.Method}}({{range $i, $a := .Pagination.Arguments}}{{if $i}}, {{end}}{{$a}}{{end}})
+		},
+	}
+}
+{{end}}{{end}}{{end}}
+{{if .Paginated}}
+// Evaluator evaluates the transaction with the passed function name and
+// arguments returning its result
+type Evaluator func(function string, args ...string) ([]byte, error)
+
+// Pager iterates over the pages of results of a paginated transaction,
+// passing the bookmark of each page when requesting the next
+type Pager struct {
+	pageSize         int64
+	resultsProperty  string
+	metadataProperty string
+	call             func(pageBookmark string) (string, []string, error)
+	bookmark         string
+	done             bool
+}
+
+// HasNext returns whether there may be further pages of results
+func (p *Pager) HasNext() bool {
+	return !p.done
+}
+
+// Next evaluates the transaction for the next page using evaluate and
+// unmarshals the results of the page into target e.g. a *[]Asset
+func (p *Pager) Next(evaluate Evaluator, target interface{}) error {
+	if p.done {
+		return fmt.Errorf("No further pages of results")
+	}
+
+	function, args, err := p.call(p.bookmark)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := evaluate(function, args...)
+
+	if err != nil {
+		return err
+	}
+
+	page := make(map[string]json.RawMessage)
+	err = json.Unmarshal(result, &page)
+
+	if err != nil {
+		return fmt.Errorf("Failed to unmarshal page. %s", err.Error())
+	}
+
+	if results, ok := page[p.resultsProperty]; ok {
+		err = json.Unmarshal(results, target)
+
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal results of page. %s", err.Error())
+		}
+	}
+
+	metadata := struct {
+		FetchedRecordsCount int64
+		Bookmark            string
+	}{}
+
+	if raw, ok := page[p.metadataProperty]; ok {
+		err = json.Unmarshal(raw, &metadata)
+
+		if err != nil {
+			return fmt.Errorf("Failed to unmarshal metadata of page. %s", err.Error())
+		}
+	}
+
+	p.bookmark = metadata.Bookmark
+	p.done = metadata.Bookmark == "" || metadata.FetchedRecordsCount < p.pageSize
+
+	return nil
+}
+{{end}}
+` + toArgSource))
+
+type stubParameter struct {
+	Name string
+	Type string
+}
+
+type stubTransaction struct {
+	Name        string
+	Method      string
+	Description string
+	Evaluate    bool
+	Parameters  []stubParameter
+	Pagination  *stubPagination
+}
+
+type stubPagination struct {
+	PageSize         string
+	ResultsProperty  string
+	MetadataProperty string
+	Parameters       []stubParameter
+	Arguments        []string
+}
+
+type stubContract struct {
+	Name         string
+	TypeName     string
+	Transactions []stubTransaction
+}
+
+// GenerateStub returns the source of a Go package, with the passed name, of a
+// client stub for the chaincode described by the metadata. The package has a
+// struct for each contract, other than the system contract, whose methods return
+// the function name and args for calling its transactions, leaving the calls to
+// the application. Paginated transactions also have a method returning a pager
+// over their pages of results. Params of types other than basic types are passed
+// as interface{} and marshalled to JSON.
+func GenerateStub(ccm contractapi.ContractChaincodeMetadata, packageName string) ([]byte, error) {
+	if !IsIdentifier(packageName) {
+		return nil, fmt.Errorf("Package %s is not a valid package name", packageName)
+	}
+
+	contractNames := []string{}
+
+	for name := range ccm.Contracts {
+		if name != contractapi.SystemContractName {
+			contractNames = append(contractNames, name)
+		}
+	}
+
+	sort.Strings(contractNames)
+
+	contracts := []stubContract{}
+	paginated := false
+
+	for _, name := range contractNames {
+		contract := stubContract{
+			Name:     name,
+			TypeName: toIdentifier(name, true) + "Client",
+		}
+
+		for _, tx := range ccm.Contracts[name].Transactions {
+			stubTx := stubTransaction{
+				Name:        tx.Name,
+				Method:      stubIdentifier(tx.Name, true),
+				Description: tx.Description,
+				Evaluate:    stringInSlice("evaluateTx", tx.Tag),
+			}
+
+			for _, param := range tx.Parameters {
+				stubTx.Parameters = append(stubTx.Parameters, stubParameter{
+					Name: stubIdentifier(param.Name, false),
+					Type: stubType(param.Schema),
+				})
+			}
+
+			if tx.Pagination != nil {
+				stubTx.Pagination = newStubPagination(tx, stubTx.Parameters)
+				paginated = true
+			}
+
+			contract.Transactions = append(contract.Transactions, stubTx)
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	buf := new(bytes.Buffer)
+	err := stubTemplate.Execute(buf, struct {
+		Package   string
+		Title     string
+		Contracts []stubContract
+		Paginated bool
+	}{packageName, ccm.Info.Title, contracts, paginated})
+
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format stub. %s", err.Error())
+	}
+
+	return formatted, nil
+}
+
+// newStubPagination builds the details of the pager for a paginated transaction.
+// The pager takes the parameters of the transaction other than its bookmark and
+// passes the bookmark of the previous page when calling the transaction.
+func newStubPagination(tx contractapi.TransactionMetadata, params []stubParameter) *stubPagination {
+	pagination := &stubPagination{
+		ResultsProperty:  tx.Pagination.ResultsProperty,
+		MetadataProperty: tx.Pagination.MetadataProperty,
+	}
+
+	for i, param := range tx.Parameters {
+		switch param.Name {
+		case tx.Pagination.BookmarkParameter:
+			pagination.Arguments = append(pagination.Arguments, "pageBookmark")
+			continue
+		case tx.Pagination.PageSizeParameter:
+			pagination.PageSize = params[i].Name
+		}
+
+		pagination.Parameters = append(pagination.Parameters, params[i])
+		pagination.Arguments = append(pagination.Arguments, params[i].Name)
+	}
+
+	return pagination
+}
+
+// stubType returns the Go type of basic types and interface{} for others, as the
+// stub does not define types for the components of the metadata
+func stubType(schema spec.Schema) string {
+	if isBasicSchema(schema) {
+		return goTypes.typeOf(schema)
+	}
+
+	return goTypes.any
+}
+
+func stubIdentifier(name string, exported bool) string {
+	identifier := toIdentifier(name, exported)
+
+	if isKeyword(identifier) || stringInSlice(identifier, stubReservedWords) {
+		identifier += "_"
+	}
+
+	return identifier
+}
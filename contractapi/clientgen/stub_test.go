@@ -0,0 +1,159 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clientgen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func schemaOfType(typ string) spec.Schema {
+	schema := spec.Schema{}
+	schema.Type = []string{typ}
+
+	return schema
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestGenerateStub(t *testing.T) {
+	var client []byte
+	var err error
+
+	ccm := contractapi.ContractChaincodeMetadata{}
+	ccm.Info.Title = "mycc"
+	ccm.Contracts = map[string]contractapi.ContractMetadata{
+		contractapi.SystemContractName: {
+			Name:         contractapi.SystemContractName,
+			Transactions: []contractapi.TransactionMetadata{{Name: "GetMetadata"}},
+		},
+		"org.example.asset": {
+			Name: "org.example.asset",
+			Transactions: []contractapi.TransactionMetadata{
+				{
+					Name:        "Create",
+					Tag:         []string{"submitTx"},
+					Description: "Creates an asset.",
+					Parameters: []contractapi.ParameterMetadata{
+						{Name: "id", Schema: schemaOfType("string")},
+						{Name: "type", Schema: schemaOfType("integer")},
+						{Name: "value", Schema: schemaOfType("object")},
+					},
+				},
+				{
+					Name: "Read",
+					Tag:  []string{"evaluateTx"},
+				},
+				{
+					Name: "List",
+					Tag:  []string{"evaluateTx"},
+					Parameters: []contractapi.ParameterMetadata{
+						{Name: "owner", Schema: schemaOfType("string")},
+						{Name: "pageSize", Schema: schemaOfType("integer")},
+						{Name: "bookmark", Schema: schemaOfType("string")},
+					},
+					Pagination: &contractapi.PaginationMetadata{
+						PageSizeParameter: "pageSize",
+						BookmarkParameter: "bookmark",
+						ResultsProperty:   "assets",
+						MetadataProperty:  "metadata",
+					},
+				},
+			},
+		},
+	}
+
+	// Should generate stub for non system contracts
+	client, err = GenerateStub(ccm, "assetclient")
+	assert.Nil(t, err, "should not error for valid metadata")
+	assert.Contains(t, string(client), "package assetclient", "should use package name")
+	assert.Contains(t, string(client), "type OrgExampleAssetClient struct{}", "should create type for contract")
+	assert.Contains(t, string(client), "func (c *OrgExampleAssetClient) Create(id string, type_ int64, value interface{}) (string, []string, error)", "should create method for transaction")
+	assert.Contains(t, string(client), "Creates an asset.\n// It should be submitted", "should document description and submit")
+	assert.Contains(t, string(client), "It should be evaluated\nfunc (c *OrgExampleAssetClient) Read() (string, []string, error)", "should document evaluate")
+	assert.Contains(t, string(client), `return "org.example.asset:Create", args, nil`, "should return namespaced function name")
+	assert.False(t, strings.Contains(string(client), "GetMetadata"), "should not include system contract")
+	assert.Contains(t, string(client), "func (c *OrgExampleAssetClient) ListPages(owner string, pageSize int64) *Pager {", "should create pages method without bookmark for paginated transaction")
+	assert.Contains(t, string(client), "return c.List(owner, pageSize, pageBookmark)", "should pass bookmark of previous page")
+	assert.Contains(t, string(client), "resultsProperty:  \"assets\",\n\t\tmetadataProperty: \"metadata\",", "should read page using response properties")
+	assert.Contains(t, string(client), "type Pager struct", "should include pager when transactions paginated")
+	assert.False(t, strings.Contains(string(client), "ReadPages"), "should not create pages method for transaction not paginated")
+
+	// Should not include pager when no transactions paginated
+	asset := ccm.Contracts["org.example.asset"]
+	asset.Transactions = asset.Transactions[:2]
+	ccm.Contracts["org.example.asset"] = asset
+	client, _ = GenerateStub(ccm, "assetclient")
+	assert.False(t, strings.Contains(string(client), "Pager"), "should not include pager when no transactions paginated")
+
+	// Should error for invalid package name
+	client, err = GenerateStub(ccm, "asset client")
+	assert.EqualError(t, err, "Package asset client is not a valid package name", "should error for invalid package")
+	assert.Nil(t, client, "should not return stub on error")
+}
+
+func TestStubType(t *testing.T) {
+	assert.Equal(t, "string", stubType(schemaOfType("string")), "should map string")
+	assert.Equal(t, "int64", stubType(schemaOfType("integer")), "should map integer")
+	assert.Equal(t, "float64", stubType(schemaOfType("number")), "should map number")
+	assert.Equal(t, "bool", stubType(schemaOfType("boolean")), "should map boolean")
+	assert.Equal(t, "interface{}", stubType(schemaOfType("array")), "should use interface for others")
+	assert.Equal(t, "interface{}", stubType(spec.Schema{}), "should use interface when no type")
+}
+
+func TestStubIdentifier(t *testing.T) {
+	assert.Equal(t, "OrgExampleAsset", stubIdentifier("org.example.asset", true), "should camel case exported")
+	assert.Equal(t, "someParam", stubIdentifier("SomeParam", false), "should lower first letter unexported")
+	assert.Equal(t, "someParam", stubIdentifier("some-param", false), "should camel case unexported")
+	assert.Equal(t, "X1st", stubIdentifier("1st", true), "should prefix leading digit")
+	assert.Equal(t, "func_", stubIdentifier("func", false), "should suffix keywords")
+	assert.Equal(t, "err_", stubIdentifier("err", false), "should suffix names used by generated code")
+}
+
+func TestNewStubPagination(t *testing.T) {
+	tx := contractapi.TransactionMetadata{
+		Name: "List",
+		Parameters: []contractapi.ParameterMetadata{
+			{Name: "start"},
+			{Name: "size"},
+			{Name: "owner"},
+		},
+		Pagination: &contractapi.PaginationMetadata{
+			PageSizeParameter: "size",
+			BookmarkParameter: "start",
+			ResultsProperty:   "results",
+			MetadataProperty:  "meta",
+		},
+	}
+	params := []stubParameter{{"start", "string"}, {"size", "int64"}, {"owner", "string"}}
+
+	// Should exclude bookmark from parameters and pass bookmark of previous page in its place
+	pagination := newStubPagination(tx, params)
+	assert.Equal(t, "size", pagination.PageSize, "should use page size parameter")
+	assert.Equal(t, []stubParameter{{"size", "int64"}, {"owner", "string"}}, pagination.Parameters, "should exclude bookmark from parameters")
+	assert.Equal(t, []string{"pageBookmark", "size", "owner"}, pagination.Arguments, "should pass bookmark in place")
+	assert.Equal(t, "results", pagination.ResultsProperty, "should use results property")
+	assert.Equal(t, "meta", pagination.MetadataProperty, "should use metadata property")
+}
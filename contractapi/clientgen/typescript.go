@@ -0,0 +1,101 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clientgen
+
+import (
+	"bytes"
+	"regexp"
+	"text/template"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+)
+
+var tsIdentifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+var tsReservedWords = []string{"break", "case", "catch", "class", "const", "continue", "debugger", "default", "delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null", "return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with", "contract", "result"}
+
+var tsTemplate = template.Must(template.New("typescript").Funcs(template.FuncMap{
+	"property": tsProperty,
+}).Parse(`// Code generated by contractapi-bindgen. DO NOT EDIT.
+
+// Contract is met by the contract of a gateway network e.g. Contract of fabric-network
+export interface Contract {
+    submitTransaction(name: string, ...args: string[]): Promise<Buffer>;
+    evaluateTransaction(name: string, ...args: string[]): Promise<Buffer>;
+}
+{{range .Types}}
+// {{.Name}} is the {{.Schema}} component of the chaincode metadata
+export interface {{.Name}} {
+{{- range .Fields}}
+    {{property .JSONName}}{{if not .Required}}?{{end}}: {{.Type}};
+{{- end}}
+}
+{{end}}
+{{- range .Contracts}}
+// {{.TypeName}} calls the transactions of the {{.Name}} contract
+export class {{.TypeName}} {
+    constructor(private readonly contract: Contract) {}
+{{$contract := .}}{{range .Transactions}}
+    // {{.Method}} {{if .Evaluate}}evaluates{{else}}submits{{end}} {{.Name}}.{{if .Description}} {{.Description}}{{end}}
+    public async {{.Method}}({{range $i, $p := .Parameters}}{{if $i}}, {{end}}{{$p.Name}}: {{$p.Type}}{{end}}): Promise<{{if .Returns}}{{.Returns}}{{else}}void{{end}}> {
+        {{if .Returns}}const result = {{end}}await this.contract.{{if .Evaluate}}evaluate{{else}}submit{{end}}Transaction('{{$contract.Name}}:{{.Name}}'{{range .Parameters}}, {{if .IsString}}{{.Name}}{{else}}JSON.stringify({{.Name}}){{end}}{{end}});
+{{- if .Returns}}
+        return {{if .ReturnsString}}result.toString(){{else}}JSON.parse(result.toString()){{end}};
+{{- end}}
+    }
+{{end}}}
+{{end}}`))
+
+var tsTypes = typeMapper{
+	basic:      map[string]string{"string": "string", "integer": "number", "number": "number", "boolean": "boolean"},
+	any:        "any",
+	identifier: tsIdentifier,
+	array:      func(item string) string { return item + "[]" },
+	mapOf:      func(value string) string { return "{ [key: string]: " + value + " }" },
+}
+
+// GenerateTypeScript returns the source of a TypeScript module of typed bindings
+// for the chaincode described by the metadata. The module has an interface for
+// each component of the metadata and a class for each contract, other than the
+// system contract, whose async methods call its transactions through a gateway
+// contract. Args and results are converted as they are for GenerateGo.
+func GenerateTypeScript(ccm contractapi.ContractChaincodeMetadata) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := tsTemplate.Execute(buf, newBindings(ccm, tsTypes))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func tsIdentifier(name string, exported bool) string {
+	identifier := toIdentifier(name, exported)
+
+	if stringInSlice(identifier, tsReservedWords) {
+		identifier += "_"
+	}
+
+	return identifier
+}
+
+func tsProperty(name string) string {
+	if tsIdentifierRegex.MatchString(name) {
+		return name
+	}
+
+	return "'" + name + "'"
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clientgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestTSIdentifier(t *testing.T) {
+	assert.Equal(t, "ownerNames", tsIdentifier("owner-names", false), "should camel case names")
+	assert.Equal(t, "new_", tsIdentifier("new", false), "should suffix reserved words")
+	assert.Equal(t, "contract_", tsIdentifier("contract", false), "should suffix names used by the generated methods")
+}
+
+func TestTSProperty(t *testing.T) {
+	assert.Equal(t, "id", tsProperty("id"), "should not quote identifiers")
+	assert.Equal(t, "'owner-names'", tsProperty("owner-names"), "should quote names that are not identifiers")
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	src, err := GenerateTypeScript(testMetadata())
+	assert.Nil(t, err, "should not error for valid metadata")
+
+	ts := string(src)
+
+	assert.Contains(t, ts, "export interface Asset {\n    history?: { [key: string]: Event };\n    id: string;\n    'owner-names'?: string[];\n    value: number;\n}", "should generate interface for component")
+	assert.Contains(t, ts, "export class AssetsContract {", "should generate class for contract")
+	assert.NotContains(t, ts, "OrgHyperledgerFabricContract", "should not generate bindings for the system contract")
+	assert.Contains(t, ts, "public async createAsset(id: string, value: number): Promise<void> {\n        await this.contract.submitTransaction('assets:CreateAsset', id, JSON.stringify(value));\n    }", "should generate method submitting transaction without return")
+	assert.Contains(t, ts, "public async readAsset(id: string): Promise<Asset> {\n        const result = await this.contract.evaluateTransaction('assets:ReadAsset', id);\n        return JSON.parse(result.toString());\n    }", "should generate method evaluating transaction and parsing result")
+	assert.Contains(t, ts, "public async getOwner(asset: Asset): Promise<string> {\n        const result = await this.contract.evaluateTransaction('assets:GetOwner', JSON.stringify(asset));\n        return result.toString();\n    }", "should generate method returning string results as they are")
+}
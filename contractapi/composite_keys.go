@@ -0,0 +1,62 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+)
+
+// CreateKey returns a composite key of the passed object type and attributes
+// for use with the world state. Errors if the object type or any of the
+// attributes contain characters not allowed in composite keys. If the chaincode
+// has state namespacing enabled the object type is prefixed with the name of the
+// contract being invoked (see EnableStateNamespacing).
+func (ctx *TransactionContext) CreateKey(objectType string, attrs ...string) (string, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(ctx.namespaceObjectType(objectType), attrs)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create key of type %s. %s", objectType, err.Error())
+	}
+
+	return key, nil
+}
+
+// SplitKey returns the object type and attributes that make up the passed
+// composite key. The object type is returned as passed to CreateKey.
+func (ctx *TransactionContext) SplitKey(key string) (string, []string, error) {
+	objectType, attrs, err := ctx.GetStub().SplitCompositeKey(key)
+
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to split key %s. %s", key, err.Error())
+	}
+
+	return ctx.unnamespaceObjectType(objectType), attrs, nil
+}
+
+// GetStatesByPartialKey reads each value in the world state stored under a
+// composite key of the passed object type beginning with the passed attributes
+// and unmarshals it into a new element of the slice pointed to by target e.g.
+// a *[]MyAsset. Passing no attributes returns all values of the object type.
+func (ctx *TransactionContext) GetStatesByPartialKey(objectType string, attrs []string, target interface{}) error {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(ctx.namespaceObjectType(objectType), attrs)
+
+	if err != nil {
+		return fmt.Errorf("Failed to get states of type %s. %s", objectType, err.Error())
+	}
+
+	si := StateIterator{iterator}
+
+	return si.unmarshalAll(target)
+}
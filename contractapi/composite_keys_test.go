@@ -0,0 +1,98 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestCreateKey(t *testing.T) {
+	var key string
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	// Should return composite key
+	key, err = ctx.CreateKey("asset", "alice", "1")
+	assert.Nil(t, err, "should not error for valid key")
+	expected, _ := stub.CreateCompositeKey("asset", []string{"alice", "1"})
+	assert.Equal(t, expected, key, "should return composite key")
+
+	// Should error for invalid attributes
+	_, err = ctx.CreateKey("asset", string([]byte{0x00}))
+	assert.Contains(t, err.Error(), "Failed to create key of type asset.", "should error for invalid attribute")
+}
+
+func TestSplitKey(t *testing.T) {
+	var objectType string
+	var attrs []string
+	var err error
+
+	ctx, _ := newStateTestContext()
+
+	// Should return object type and attributes
+	key, _ := ctx.CreateKey("asset", "alice", "1")
+	objectType, attrs, err = ctx.SplitKey(key)
+	assert.Nil(t, err, "should not error for composite key")
+	assert.Equal(t, "asset", objectType, "should return object type")
+	assert.Equal(t, []string{"alice", "1"}, attrs, "should return attributes")
+}
+
+func TestGetStatesByPartialKey(t *testing.T) {
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	key1, _ := ctx.CreateKey("asset", "alice", "1")
+	key2, _ := ctx.CreateKey("asset", "alice", "2")
+	key3, _ := ctx.CreateKey("asset", "bob", "1")
+	key4, _ := ctx.CreateKey("other", "alice", "1")
+	stub.PutState(key1, []byte("{\"Prop1\":\"alice1\",\"prop2\":1}"))
+	stub.PutState(key2, []byte("{\"Prop1\":\"alice2\",\"prop2\":2}"))
+	stub.PutState(key3, []byte("{\"Prop1\":\"bob1\",\"prop2\":3}"))
+	stub.PutState(key4, []byte("{\"Prop1\":\"other\",\"prop2\":4}"))
+
+	// Should unmarshal values matching partial key
+	alices := []GoodStruct{}
+	err = ctx.GetStatesByPartialKey("asset", []string{"alice"}, &alices)
+	assert.Nil(t, err, "should not error for partial key")
+	assert.Equal(t, []GoodStruct{{Prop1: "alice1", Prop2: 1}, {Prop1: "alice2", Prop2: 2}}, alices, "should return values matching partial key")
+
+	// Should unmarshal all values of object type
+	all := []*GoodStruct{}
+	err = ctx.GetStatesByPartialKey("asset", []string{}, &all)
+	assert.Nil(t, err, "should not error for object type only")
+	assert.Len(t, all, 3, "should return all values of object type")
+
+	// Should error when target not pointer to slice
+	err = ctx.GetStatesByPartialKey("asset", []string{}, all)
+	assert.EqualError(t, err, "Target must be a pointer to a slice. Received []*contractapi.GoodStruct", "should error when target not pointer")
+
+	// Should error when partial key invalid
+	err = ctx.GetStatesByPartialKey("asset", []string{string([]byte{0x00})}, &all)
+	assert.Contains(t, err.Error(), "Failed to get states of type asset.", "should error for invalid partial key")
+
+	// Should error when value cannot be unmarshalled
+	key5, _ := ctx.CreateKey("asset", "carol", "1")
+	stub.PutState(key5, []byte("not json"))
+	err = ctx.GetStatesByPartialKey("asset", []string{"carol"}, &all)
+	assert.Contains(t, err.Error(), "Value for key "+key5+" could not be unmarshalled.", "should error when value not JSON")
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// ReadConflictDetails is the details of the error returned by Invoke when the
+// concurrency guard finds keys written using stale reads
+type ReadConflictDetails struct {
+	Keys []string `json:"keys"`
+}
+
+// EnableConcurrencyGuard enables checking that transactions do not overwrite their
+// own writes using values read before them. Reads of the world state return the value
+// committed before the transaction rather than that written by the transaction, so
+// once a transaction writes a key any value it read for the key, e.g. pre-loaded by
+// a before transaction, is stale. The keys read and written by each transaction,
+// including by before and after transactions, are recorded and if a key read by the
+// transaction is written again after the transaction has already written it, an
+// Error with code 409 is returned, with ReadConflictDetails listing the keys, once
+// the after transactions have returned. Keys only written, and keys written once
+// after being read, are not conflicts. Changes made by other transactions cannot be
+// seen while a transaction is simulated and are still found by the peer as MVCC read
+// conflicts when the transaction is committed.
+func (cc *ContractChaincode) EnableConcurrencyGuard() {
+	cc.concurrencyGuard = true
+}
+
+// concurrencyGuardStub records the keys read and written by the transaction and
+// those written again after being read and written
+type concurrencyGuardStub struct {
+	shim.ChaincodeStubInterface
+	read      map[string]bool
+	written   map[string]bool
+	conflicts map[string]bool
+}
+
+func newConcurrencyGuardStub(stub shim.ChaincodeStubInterface) *concurrencyGuardStub {
+	return &concurrencyGuardStub{stub, make(map[string]bool), make(map[string]bool), make(map[string]bool)}
+}
+
+func (cgs *concurrencyGuardStub) GetState(key string) ([]byte, error) {
+	value, err := cgs.ChaincodeStubInterface.GetState(key)
+
+	if err == nil {
+		cgs.read[key] = true
+	}
+
+	return value, err
+}
+
+func (cgs *concurrencyGuardStub) PutState(key string, value []byte) error {
+	err := cgs.ChaincodeStubInterface.PutState(key, value)
+
+	if err == nil {
+		cgs.recordWrite(key)
+	}
+
+	return err
+}
+
+func (cgs *concurrencyGuardStub) DelState(key string) error {
+	err := cgs.ChaincodeStubInterface.DelState(key)
+
+	if err == nil {
+		cgs.recordWrite(key)
+	}
+
+	return err
+}
+
+func (cgs *concurrencyGuardStub) recordWrite(key string) {
+	if cgs.read[key] && cgs.written[key] {
+		cgs.conflicts[key] = true
+	}
+
+	cgs.written[key] = true
+}
+
+// verify returns an error listing the keys written using stale reads
+func (cgs *concurrencyGuardStub) verify() error {
+	if len(cgs.conflicts) == 0 {
+		return nil
+	}
+
+	keys := []string{}
+
+	for key := range cgs.conflicts {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return NewError(http.StatusConflict, fmt.Sprintf("Read conflict. Keys %s were written again after being read and written by the transaction", strings.Join(keys, ", ")), ReadConflictDetails{keys})
+}
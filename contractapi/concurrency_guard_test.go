@@ -0,0 +1,125 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type concurrencyGuardTestContract struct {
+	Contract
+}
+
+func (cgtc *concurrencyGuardTestContract) Update(ctx *TransactionContext, value string) error {
+	return ctx.GetStub().PutState("asset1", []byte(value))
+}
+
+func (cgtc *concurrencyGuardTestContract) UpdateTwice(ctx *TransactionContext, value string) error {
+	ctx.GetStub().PutState("asset1", []byte(value))
+	return ctx.GetStub().PutState("asset1", []byte(value+" again"))
+}
+
+func (cgtc *concurrencyGuardTestContract) WriteBlind(ctx *TransactionContext) error {
+	ctx.GetStub().PutState("other", []byte("first"))
+	return ctx.GetStub().PutState("other", []byte("second"))
+}
+
+func newConcurrencyGuardTestChaincode(enabled bool) *ContractChaincode {
+	cgtc := new(concurrencyGuardTestContract)
+	cgtc.SetBeforeTransaction(func(ctx *TransactionContext) {
+		ctx.GetStub().GetState("asset1")
+	})
+
+	cc := convertC2CC(cgtc)
+
+	if enabled {
+		cc.EnableConcurrencyGuard()
+	}
+
+	return &cc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableConcurrencyGuard(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableConcurrencyGuard()
+
+	assert.True(t, cc.concurrencyGuard, "should enable concurrency guard")
+}
+
+func TestConcurrencyGuardStub(t *testing.T) {
+	stub := shimtest.NewMockStub("concurrencyGuardTest", nil)
+	stub.MockTransactionStart(standardTxID)
+
+	// Should not find conflicts for reads then single writes or blind writes
+	cgs := newConcurrencyGuardStub(stub)
+	cgs.GetState("key1")
+	cgs.PutState("key1", []byte("value"))
+	cgs.PutState("key2", []byte("value"))
+	cgs.DelState("key2")
+	assert.Nil(t, cgs.verify(), "should not error without stale reads")
+
+	// Should find keys written again after being read and written
+	cgs = newConcurrencyGuardStub(stub)
+	cgs.PutState("key2", []byte("value"))
+	cgs.GetState("key2")
+	cgs.DelState("key2")
+	cgs.GetState("key1")
+	cgs.PutState("key1", []byte("value"))
+	cgs.PutState("key1", []byte("other value"))
+	assert.Equal(t, NewError(409, "Read conflict. Keys key1, key2 were written again after being read and written by the transaction", ReadConflictDetails{[]string{"key1", "key2"}}), cgs.verify(), "should error listing keys written using stale reads")
+
+	// Should not record failed writes
+	errStub := &stateErrorTestStub{MockStub: stub, putErr: errors.New("some error")}
+	cgs = newConcurrencyGuardStub(errStub)
+	cgs.GetState("key1")
+	cgs.PutState("key1", []byte("value"))
+	cgs.PutState("key1", []byte("value"))
+	assert.Nil(t, cgs.verify(), "should not record writes that errored")
+}
+
+func TestInvokeWithConcurrencyGuard(t *testing.T) {
+	stub := shimtest.NewMockStub("concurrencyGuardTest", newConcurrencyGuardTestChaincode(true))
+
+	// Should allow key pre-loaded by before transaction to be written once
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Update"), []byte("value")})
+	assert.Equal(t, int32(200), response.Status, "should succeed writing pre-loaded key once")
+
+	// Should allow blind writes
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("WriteBlind")})
+	assert.Equal(t, int32(200), response.Status, "should succeed writing key not read")
+
+	// Should return conflict when pre-loaded key written again
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("UpdateTwice"), []byte("value")})
+	assert.Equal(t, int32(409), response.Status, "should return 409 for stale read")
+	assert.Equal(t, "Read conflict. Keys asset1 were written again after being read and written by the transaction", response.Message, "should return conflict message")
+	assert.Equal(t, `{"code":409,"message":"Read conflict. Keys asset1 were written again after being read and written by the transaction","details":{"keys":["asset1"]}}`, string(response.Payload), "should return conflicting keys as details")
+
+	// Should not check when guard not enabled
+	stub = shimtest.NewMockStub("concurrencyGuardTest", newConcurrencyGuardTestChaincode(false))
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("UpdateTwice"), []byte("value")})
+	assert.Equal(t, int32(200), response.Status, "should not check without guard")
+}
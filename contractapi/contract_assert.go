@@ -0,0 +1,165 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var contractInterfaceType = reflect.TypeOf((*ContractInterface)(nil)).Elem()
+
+// optionalContractInterfaces are the interfaces a contract may implement to change
+// how it is used by the chaincode. A contract defining a method of one of them with
+// the wrong signature does not implement it and so would otherwise be used without it.
+var optionalContractInterfaces = []reflect.Type{
+	reflect.TypeOf((*TransformerContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*SerializerContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*InitContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*ConstantsContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*FunctionConfigContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*DependencyContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*MiddlewareContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*IgnoreContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*AliasContractInterface)(nil)).Elem(),
+	reflect.TypeOf((*ArgCountContractInterface)(nil)).Elem(),
+}
+
+// AssertContract checks the contract when the chaincode starts rather than leaving
+// missing methods to silently change its behaviour, and returns it as a ContractInterface
+// to pass to CreateNewChaincode. Panics if the contract is not a pointer to a struct,
+// does not implement ContractInterface or any of the interfaces passed, given as nil
+// pointers e.g. (*contractapi.IgnoreContractInterface)(nil), or defines a method of an
+// optional contract interface, such as GetSerializer, with the wrong signature. For a
+// check at build time declare var _ contractapi.ContractInterface = (*MyContract)(nil).
+func AssertContract(contract interface{}, interfaces ...interface{}) ContractInterface {
+	if err := assertContract(contract, interfaces); err != nil {
+		panic(err.Error())
+	}
+
+	return contract.(ContractInterface)
+}
+
+func assertContract(contract interface{}, interfaces []interface{}) error {
+	if contract == nil {
+		return fmt.Errorf("Contract is nil. Expected a pointer to a struct implementing ContractInterface")
+	}
+
+	ct := reflect.TypeOf(contract)
+
+	if ct.Kind() != reflect.Ptr || ct.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Contract %s is not valid. Expected a pointer to a struct implementing ContractInterface", ct.String())
+	}
+
+	required := []reflect.Type{contractInterfaceType}
+
+	for _, iface := range interfaces {
+		it := reflect.TypeOf(iface)
+
+		if it == nil || it.Kind() != reflect.Ptr || it.Elem().Kind() != reflect.Interface {
+			return fmt.Errorf("Cannot assert contract %s implements %v. Expected a nil pointer to an interface", ct.String(), it)
+		}
+
+		required = append(required, it.Elem())
+	}
+
+	for _, it := range required {
+		if missing := missingMethods(ct, it); len(missing) > 0 {
+			return fmt.Errorf("Contract %s does not implement %s. Missing methods %s", ct.String(), it.Name(), sliceAsCommaSentence(missing))
+		}
+	}
+
+	for _, it := range optionalContractInterfaces {
+		if ct.Implements(it) {
+			continue
+		}
+
+		for i := 0; i < it.NumMethod(); i++ {
+			expected := it.Method(i)
+
+			if method, ok := ct.MethodByName(expected.Name); ok {
+				return fmt.Errorf("Contract %s does not implement %s. Method %s has signature %s. Expected %s", ct.String(), it.Name(), expected.Name, methodSignature(method.Type, 1), methodSignature(expected.Type, 0))
+			}
+		}
+	}
+
+	return nil
+}
+
+// missingMethods returns the names of the methods of the interface that the type
+// does not define with the same signature
+func missingMethods(t reflect.Type, iface reflect.Type) []string {
+	missing := []string{}
+
+	for i := 0; i < iface.NumMethod(); i++ {
+		expected := iface.Method(i)
+		method, ok := t.MethodByName(expected.Name)
+
+		if !ok || !signatureMatches(method.Type, expected.Type) {
+			missing = append(missing, expected.Name)
+		}
+	}
+
+	return missing
+}
+
+// signatureMatches returns whether the method, whose first param is its receiver,
+// has the params and returns of the func type of the interface method
+func signatureMatches(method reflect.Type, expected reflect.Type) bool {
+	if method.NumIn()-1 != expected.NumIn() || method.NumOut() != expected.NumOut() || method.IsVariadic() != expected.IsVariadic() {
+		return false
+	}
+
+	for i := 0; i < expected.NumIn(); i++ {
+		if method.In(i+1) != expected.In(i) {
+			return false
+		}
+	}
+
+	for i := 0; i < expected.NumOut(); i++ {
+		if method.Out(i) != expected.Out(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// methodSignature formats the params and returns of a func type, skipping the
+// first params e.g. the receiver of methods
+func methodSignature(ft reflect.Type, skip int) string {
+	params := []string{}
+
+	for i := skip; i < ft.NumIn(); i++ {
+		params = append(params, ft.In(i).String())
+	}
+
+	returns := []string{}
+
+	for i := 0; i < ft.NumOut(); i++ {
+		returns = append(returns, ft.Out(i).String())
+	}
+
+	signature := "(" + strings.Join(params, ", ") + ")"
+
+	if len(returns) == 1 {
+		signature += " " + returns[0]
+	} else if len(returns) > 1 {
+		signature += " (" + strings.Join(returns, ", ") + ")"
+	}
+
+	return signature
+}
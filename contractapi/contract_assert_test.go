@@ -0,0 +1,110 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type assertTestContract struct {
+	Contract
+}
+
+func (atc *assertTestContract) GetIgnoredFunctions() []string {
+	return []string{}
+}
+
+type badSignatureTestContract struct {
+	Contract
+}
+
+func (bstc *badSignatureTestContract) GetSerializer() JSONSerializer {
+	return JSONSerializer{}
+}
+
+type migratingContractInterface interface {
+	GetPreviousVersions() []string
+}
+
+type notContractTestContract struct{}
+
+func (nctc *notContractTestContract) GetName() string {
+	return "notContract"
+}
+
+func (nctc *notContractTestContract) GetVersion() int {
+	return 1
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestAssertContract(t *testing.T) {
+	var err error
+
+	// Should return the contract as a ContractInterface
+	atc := new(assertTestContract)
+	assert.Equal(t, atc, AssertContract(atc, (*IgnoreContractInterface)(nil)), "should return the contract")
+
+	// Should error for invalid contracts
+	err = assertContract(nil, nil)
+	assert.EqualError(t, err, "Contract is nil. Expected a pointer to a struct implementing ContractInterface", "should error for nil contract")
+
+	err = assertContract(assertTestContract{}, nil)
+	assert.EqualError(t, err, "Contract contractapi.assertTestContract is not valid. Expected a pointer to a struct implementing ContractInterface", "should error for contract not a pointer")
+
+	// Should error for missing methods
+	err = assertContract(new(notContractTestContract), nil)
+	assert.EqualError(t, err, "Contract *contractapi.notContractTestContract does not implement ContractInterface. Missing methods GetAfterTransaction, GetBeforeTransaction, GetTransactionContextHandler, GetUnknownTransaction and GetVersion", "should error when not contract interface")
+
+	err = assertContract(atc, []interface{}{(*IgnoreContractInterface)(nil), (*migratingContractInterface)(nil)})
+	assert.EqualError(t, err, "Contract *contractapi.assertTestContract does not implement migratingContractInterface. Missing methods GetPreviousVersions", "should error when not interface passed")
+
+	// Should error for interfaces not passed as nil pointers
+	err = assertContract(atc, []interface{}{"IgnoreContractInterface"})
+	assert.EqualError(t, err, "Cannot assert contract *contractapi.assertTestContract implements string. Expected a nil pointer to an interface", "should error for interface not passed as pointer")
+
+	// Should error for optional interface methods with the wrong signature
+	err = assertContract(new(badSignatureTestContract), nil)
+	assert.EqualError(t, err, "Contract *contractapi.badSignatureTestContract does not implement SerializerContractInterface. Method GetSerializer has signature () contractapi.JSONSerializer. Expected () contractapi.Serializer", "should error for optional interface method with wrong signature")
+
+	assert.PanicsWithValue(t, err.Error(), func() { AssertContract(new(badSignatureTestContract)) }, "should panic with error")
+
+	// Should accept the contracts embedding Contract
+	assert.Nil(t, assertContract(new(myContract), nil), "should not error for contract embedding Contract")
+}
+
+func TestSignatureMatches(t *testing.T) {
+	ignore := reflect.TypeOf((*IgnoreContractInterface)(nil)).Elem().Method(0).Type
+	method, _ := reflect.TypeOf(new(assertTestContract)).MethodByName("GetIgnoredFunctions")
+	other, _ := reflect.TypeOf(new(assertTestContract)).MethodByName("GetName")
+
+	assert.True(t, signatureMatches(method.Type, ignore), "should match same params and returns")
+	assert.False(t, signatureMatches(other.Type, ignore), "should not match different returns")
+}
+
+func TestMethodSignature(t *testing.T) {
+	assert.Equal(t, "()", methodSignature(reflect.TypeOf(func() {}), 0), "should format no params or returns")
+	assert.Equal(t, "(int, string) error", methodSignature(reflect.TypeOf(func(int, string) error { return nil }), 0), "should format single return")
+	assert.Equal(t, "(string) (int, error)", methodSignature(reflect.TypeOf(func(bool, string) (int, error) { return 0, nil }), 1), "should skip params and format multiple returns")
+}
@@ -15,10 +15,13 @@
 package contractapi
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-protos-go/peer"
@@ -32,17 +35,91 @@ type contractChaincodeContract struct {
 	afterTransaction             *transactionHandler
 	transactionContextHandler    reflect.Type
 	transactionContextPtrHandler reflect.Type
+	argTransformer               ArgumentTransformer
+	respTransformer              ResponseTransformer
+	serializer                   Serializer
+	initTransaction              string
+	constants                    map[string]interface{}
+	functionConfigs              map[string]*FunctionConfig
+	receiver                     reflect.Value
+	sharedFields                 []string
+	dependencies                 []Dependency
+	middleware                   map[string][]*transactionHandler
+	pagination                   map[string]*paginationDetails
+	aliases                      []string
+	argCountMode                 ArgCountMode
+	functionAliases              map[string]string
+}
+
+// newReceiver returns a copy of the contract as registered with the chaincode
+// for a transaction to call its function on, so that changes a transaction makes
+// to the fields of the contract are not seen by other transactions
+func (ccn contractChaincodeContract) newReceiver() reflect.Value {
+	receiver := reflect.New(ccn.receiver.Elem().Type())
+	receiver.Elem().Set(ccn.receiver.Elem())
+
+	return receiver
 }
 
 // ContractChaincode a struct to meet the chaincode interface and provide routing of calls to contracts
 type ContractChaincode struct {
-	defaultContract string
-	contracts       map[string]contractChaincodeContract
-	metadata        ContractChaincodeMetadata
-	title           string
-	version         string
+	defaultContract          string
+	contracts                map[string]contractChaincodeContract
+	metadata                 ContractChaincodeMetadata
+	title                    string
+	version                  string
+	voidResponse             VoidResponse
+	featureFlags             map[string]bool
+	resolvedFeatureFlags     map[string]bool
+	batchInvocation          bool
+	serializer               Serializer
+	stateValidation          bool
+	canonicalState           bool
+	diagnosticsWriter        io.Writer
+	transactionTimeout       time.Duration
+	beforeTransactions       map[string]*transactionHandler
+	afterTransactions        map[string]*transactionHandler
+	converters               map[string]ArgumentConverter
+	strictContracts          bool
+	stateTriggers            []stateTrigger
+	aliases                  map[string]string
+	startHooks               []func() error
+	startHookStops           []int
+	stopHooks                []func() error
+	tracer                   Tracer
+	metricsAddress           string
+	metrics                  *chaincodeMetrics
+	routes                   map[string]map[string]*route
+	schemaCache              *schemaCache
+	metadataFileMismatches   []string
+	skipMetadataValidation   bool
+	maxExportPageSize        int32
+	stateNamespacing         bool
+	sharedStatePrefixes      []string
+	caseInsensitiveFunctions bool
+	propagatePanics          bool
+	compressionThreshold     int
+	maxArgumentSize          int
+	maxResponseSize          int
+	nameValidator            NameValidator
+	concurrencyGuard         bool
 }
 
+// VoidResponse defines the payload returned on success by transactions whose
+// function does not declare a success return type
+type VoidResponse int
+
+const (
+	// EmptyVoidResponse returns an empty payload. This is the default
+	EmptyVoidResponse VoidResponse = iota
+	// StatusVoidResponse returns the JSON acknowledgement {"status":"OK"}
+	StatusVoidResponse
+	// TxIDVoidResponse returns the ID of the transaction
+	TxIDVoidResponse
+)
+
+const statusOKResponse = "{\"status\":\"OK\"}"
+
 // SystemContractName the name of the system smart contract
 const SystemContractName = "org.hyperledger.fabric"
 
@@ -55,14 +132,22 @@ const SystemContractName = "org.hyperledger.fabric"
 // of the public functions. It also outlines version details for contracts and the chaincode. If these are blank
 // strings this is set to latest. The names for parameters do not match those used in the functions instead they are
 // recorded as param0, param1, ..., paramN. If there exists a file META-INF/chaincode/metadata.json then this
-// will overwrite the generated metadata. The contents of this file must validate against the schema.
+// will overwrite the generated metadata. The contents of this file must validate against the schema. Its
+// contracts and functions must exist in the chaincode and take the same number and types of parameters,
+// otherwise Start returns an error unless SkipMetadataValidation is called.
 func CreateNewChaincode(contracts ...ContractInterface) ContractChaincode {
 	return convertC2CC(contracts...)
 }
 
-// Start starts the chaincode in the fabric shim
+// Start starts the chaincode in the fabric shim. If startup diagnostics are
+// enabled they are written before the chaincode is started. Contract names
+// rejected by the name validator cause an error (see SetNameValidator). Contracts with
+// exported fields shared across transactions are warned about, or cause an
+// error if strict contracts are enabled (see EnableStrictContracts). The chaincode
+// stops cleanly, calling its stop functions, when the process is sent an interrupt
+// or terminate signal (see StartWithContext).
 func (cc *ContractChaincode) Start() error {
-	return shim.Start(cc)
+	return cc.StartWithContext(context.Background())
 }
 
 // SetTitle sets the title
@@ -70,25 +155,65 @@ func (cc *ContractChaincode) SetTitle(title string) {
 	cc.title = title
 }
 
-// SetVersion sets the version
+// SetVersion sets the version, as returned by the GetVersions transaction of
+// the system contract
 func (cc *ContractChaincode) SetVersion(version string) {
 	cc.version = version
+
+	cc.setSystemContractVersions()
 }
 
-// SetDefault sets the default contract name
+// SetDefault sets the contract called when no contract name is passed in the
+// first arg of a transaction, in place of the first contract passed to
+// CreateNewChaincode. The default contract is marked in the metadata. Panics
+// if the contract is not one of the chaincode's.
 func (cc *ContractChaincode) SetDefault(c ContractInterface) {
-	cc.defaultContract = c.GetName()
+	ns := getContractName(c)
+
+	if _, ok := cc.contracts[ns]; !ok {
+		panic(fmt.Sprintf("Cannot set default contract %s. Contract not found in chaincode", ns))
+	}
+
+	cc.defaultContract = ns
+
+	cc.setSystemContractMetadata()
+}
+
+// GetMetadata returns the metadata of the chaincode, generated from its contracts
+// and combined with the metadata file if used, as returned by the GetMetadata
+// transaction of the system contract
+func (cc *ContractChaincode) GetMetadata() ContractChaincodeMetadata {
+	return cc.metadata
+}
+
+// SetVoidResponse sets the payload returned when a function that does not
+// declare a success return type completes without error
+func (cc *ContractChaincode) SetVoidResponse(vr VoidResponse) {
+	cc.voidResponse = vr
 }
 
 // Init is called during Instantiate transaction after the chaincode container
 // has been established for the first time, passes off details of the request to Invoke
-// for handling the request if a function name is passed, otherwise returns shim.Success
+// for handling the request if a function name is passed, otherwise returns shim.Success.
+// If the named contract implements InitContractInterface and designates an init function
+// then a shim.Error is returned when any other function is named. If the named contract wraps a
+// legacy chaincode its Init is called instead (see WrapLegacyChaincode).
 func (cc *ContractChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
 	nsFcn, _ := stub.GetFunctionAndParameters()
 	if nsFcn == "" {
 		return shim.Success([]byte("Default initiator successful."))
 	}
 
+	ns, fn := cc.splitFunctionName(nsFcn)
+
+	if chaincode, ok := cc.getLegacyChaincode(ns); ok {
+		return chaincode.Init(newLegacyStub(stub, fn))
+	}
+
+	if nsContract, ok := cc.contracts[ns]; ok && nsContract.initTransaction != "" && nsContract.initTransaction != cc.resolveFunctionName(ns, fn) {
+		return shim.Error(fmt.Sprintf("Function %s cannot be called during Init of contract %s. Expected %s", fn, ns, nsContract.initTransaction))
+	}
+
 	return cc.Invoke(stub)
 }
 
@@ -97,7 +222,8 @@ func (cc *ContractChaincode) Init(stub shim.ChaincodeStubInterface) peer.Respons
 // and function of that contract to be called. The remaining args are then used as
 // parameters to that function. Args are converted from strings to the expected parameter
 // types of the function before being passed. A transaction context is generated and is passed,
-// if required, as the first parameter to the named function. Before and after functions are
+// if required, as the first parameter to the named function, or as the second if the function takes the
+// context.Context of the transaction context first (see TransactionContext.Context). Before and after functions are
 // called before and after the named function passed if the contract defines such functions to
 // exist. If the before function returns an error the named function is not called and its error
 // is returned in shim.Error. If the after function returns an error then its value is returned
@@ -110,45 +236,149 @@ func (cc *ContractChaincode) Init(stub shim.ChaincodeStubInterface) peer.Respons
 // if defined is not called. If the named function or unknown function handler returns a non-error type then then the after transaction
 // is sent this value. The same transaction context is passed as a pointer to before, after, named
 // and unknown functions on each Invoke. If no contract name is passed then the default contract is used.
+// If the named or unknown function does not declare a success return type then the payload returned on
+// success is determined by the chaincode's void response (see SetVoidResponse), and if it returns a nil value
+// by the nil return of the function's config (see FunctionConfig.SetNilReturn). Functions returning TxMetadata
+// return their success value in an envelope with the metadata (see TxMetadata). If the contract implements
+// TransformerContractInterface its argument transformer is applied to the args before they are converted
+// and its response transformer to the payload before it is returned. Args and returned values are converted
+// using the contract's serializer if it implements SerializerContractInterface, otherwise the chaincode's.
+// Params failing the rules of their validate struct tags return a response with status 400. If a
+// transaction timeout is set, for the chaincode or the function, the stub passed to the transaction enforces its
+// deadline (see SetTransactionTimeout) and the context of the transaction context is done once it passes.
+// Before and after functions set on the chaincode are called around those of the contract. The named
+// function is called on a copy of the contract as registered, so that transactions processed concurrently
+// do not share the fields of its receiver. Converters named in the parameter tags of the function's
+// config are applied to args after the argument transformer (see FunctionConfig.SetParameterTags).
+// Middleware
+// added for the named function is called in order after the before function (see Contract.Use). Errors
+// of type *Error returned by any function called are returned with their code and details (see NewError).
+// Transactions of contracts wrapping a legacy chaincode are passed to its Invoke (see WrapLegacyChaincode).
+// A contract can be named by any of its aliases as well as its own name (see Contract.AddNameAlias), and
+// a function by any of its aliases (see Contract.AddFunctionAlias) or, if enabled, its name in any case (see
+// EnableCaseInsensitiveFunctions). If response compression is enabled a payload longer than its
+// threshold is returned compressed in a CompressedResponse (see EnableResponseCompression). Args and
+// payloads larger than the maximum sizes set return an Error with code 413 (see SetMaxArgumentSize).
+// If a tracer is set a span is started for the transaction (see SetTracer) and if metrics are
+// enabled the transaction is recorded in them (see EnableMetrics). If any function called panics
+// a response with status 500 and a CrashReport as its payload is returned, rather than the chaincode
+// crashing, and the panic is logged with its stack.
 func (cc *ContractChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	invoke := cc.invoke
+
+	if cc.tracer != nil {
+		invoke = cc.traceInvoke
+	}
+
+	if cc.metrics != nil {
+		return cc.measureInvoke(stub, invoke)
+	}
+
+	return invoke(stub)
+}
+
+func (cc *ContractChaincode) invoke(stub shim.ChaincodeStubInterface) (response peer.Response) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if cc.propagatePanics {
+				panic(recovered)
+			}
+
+			response = cc.recoverResponse(stub, recovered)
+		}
+	}()
+
+	if err := cc.checkArgSizes(stub.GetArgs()); err != nil {
+		return errorResponse(err)
+	}
+
 	nsFcn, params := stub.GetFunctionAndParameters()
 
-	li := strings.LastIndex(nsFcn, ":")
+	ns, fn := cc.splitFunctionName(nsFcn)
 
-	var ns string
-	var fn string
+	if cc.batchInvocation && ns == SystemContractName && fn == BatchTransactionName {
+		return cc.batchInvoke(stub, params)
+	}
 
-	if li == -1 {
-		ns = cc.defaultContract
-		fn = nsFcn
-	} else {
-		ns = nsFcn[:li]
-		fn = nsFcn[li+1:]
+	if cc.maxExportPageSize > 0 && ns == SystemContractName && fn == ExportTransactionName {
+		return cc.exportState(stub, params)
 	}
 
 	if _, ok := cc.contracts[ns]; !ok {
 		return shim.Error(fmt.Sprintf("Contract not found with name %s", ns))
 	}
 
+	if chaincode, ok := cc.getLegacyChaincode(ns); ok {
+		return chaincode.Invoke(newLegacyStub(stub, fn))
+	}
+
+	fn = cc.resolveFunctionName(ns, fn)
+
 	nsContract := cc.contracts[ns]
 
+	var deadline time.Time
+	var txContext context.Context
+	var cancel context.CancelFunc
+
+	if timeout := cc.getTimeout(nsContract.functionConfigs[fn]); timeout > 0 {
+		deadline = time.Now().Add(timeout)
+		stub = &deadlineStub{stub, deadline}
+		txContext, cancel = context.WithDeadline(context.Background(), deadline)
+	} else {
+		txContext, cancel = context.WithCancel(context.Background())
+	}
+
+	defer cancel()
+
+	if config, ok := nsContract.functionConfigs[fn]; ok && config.readOnly {
+		stub = &readOnlyStub{stub, fn}
+	}
+
+	var guard *concurrencyGuardStub
+
+	if cc.concurrencyGuard {
+		guard = newConcurrencyGuardStub(stub)
+		stub = guard
+	}
+
 	ctx := reflect.New(nsContract.transactionContextHandler)
-	ctxIface := ctx.Interface().(TransactionContextInterface)
+	ctxIface := ctx.Interface().(SettableTransactionContextInterface)
 	ctxIface.SetStub(stub)
 
+	if detailsIface, ok := ctxIface.(settableTransactionDetailsInterface); ok {
+		details := cc.getTransactionDetails()
+		details.contractName = ns
+		details.functionName = fn
+		details.deadline = deadline
+		details.context = txContext
+
+		detailsIface.setTransactionDetails(details)
+	}
+
+	serializer := nsContract.serializer
+
+	if serializer == nil {
+		serializer = cc.serializer
+	}
+
+	if beforeTransaction, ok := cc.beforeTransactions[ns]; ok {
+		if errRes := cc.callBeforeTransaction(beforeTransaction, ns, ctx, serializer); errRes != nil {
+			return errorResponse(errRes)
+		}
+	}
+
 	beforeTransaction := nsContract.beforeTransaction
 
 	if beforeTransaction != nil {
-		_, _, errRes := beforeTransaction.call(ctx, nil)
-
-		if errRes != nil {
-			return shim.Error(errRes.Error())
+		if errRes := cc.callBeforeTransaction(beforeTransaction, ns, ctx, serializer); errRes != nil {
+			return errorResponse(errRes)
 		}
 	}
 
 	var successReturn string
 	var successIFace interface{}
 	var errorReturn error
+	var returns contractFunctionReturns
 
 	if _, ok := nsContract.functions[fn]; !ok {
 		unknownTransaction := nsContract.unknownTransaction
@@ -156,51 +386,200 @@ func (cc *ContractChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Respo
 			return shim.Error(fmt.Sprintf("Function %s not found in contract %s", fn, ns))
 		}
 
-		successReturn, successIFace, errorReturn = unknownTransaction.call(ctx, nil)
+		returns = unknownTransaction.returns
+		successReturn, successIFace, errorReturn = unknownTransaction.call(ctx, unknownTransactionRequest{fn, params}, serializer)
 	} else {
-		var transactionSchema *TransactionMetadata
+		for _, middleware := range nsContract.middleware[fn] {
+			_, _, errRes := middleware.call(ctx, nil, serializer)
+
+			if errRes != nil {
+				return errorResponse(errRes)
+			}
+		}
+
+		r := cc.getRoute(ns, fn)
+		function := *nsContract.functions[fn]
+		transaction := r.transaction
+
+		if isResultStreamFunction(&function) {
+			params, function.stream, errorReturn = splitResultStreamParams(params)
+
+			if errorReturn != nil {
+				cc.recordArgumentError(ns, fn)
+				return shim.Error(errorReturn.Error())
+			}
+
+			transaction = withoutResultStreamParameters(transaction, len(function.params.fields))
+		}
+
+		if nsContract.argTransformer != nil {
+			params, errorReturn = nsContract.argTransformer(stub, params)
 
-		for _, v := range cc.metadata.Contracts[ns].Transactions {
-			if v.Name == fn {
-				transactionSchema = &v
-				break
+			if errorReturn != nil {
+				return errorResponse(errorReturn)
 			}
 		}
 
-		successReturn, successIFace, errorReturn = nsContract.functions[fn].call(ctx, transactionSchema, &cc.metadata.Components, params...)
+		if config, ok := nsContract.functionConfigs[fn]; ok {
+			params = config.applyDefaults(len(nsContract.functions[fn].params.fields), params)
+		}
+
+		params, errorReturn = cc.checkArgCount(ns, fn, transaction, params)
+
+		if errorReturn != nil {
+			cc.recordArgumentError(ns, fn)
+			return shim.Error(errorReturn.Error())
+		}
+
+		params, errorReturn = cc.convertArgs(nsContract.functions[fn], nsContract.functionConfigs[fn], params)
+
+		if errorReturn != nil {
+			cc.recordArgumentError(ns, fn)
+			return shim.Error(errorReturn.Error())
+		}
+
+		function.function = nsContract.newReceiver().Method(r.methodIndex)
+
+		values, err := getArgsWithSchemas(function, ctx, transaction, &cc.metadata.Components, r.getSchemas(), serializer, params)
+
+		if err != nil {
+			cc.recordArgumentError(ns, fn)
+			return errorResponse(err)
+		}
+
+		returns = function.returns
+		successReturn, successIFace, errorReturn = function.callWithArgs(values, transaction, serializer)
 	}
 
 	if errorReturn != nil {
-		return shim.Error(errorReturn.Error())
+		return errorResponse(errorReturn)
 	}
 
 	afterTransaction := nsContract.afterTransaction
 
 	if afterTransaction != nil {
-		_, _, errRes := afterTransaction.call(ctx, successIFace)
+		if _, errRes := cc.callHook(afterTransaction, ns, ctx, successIFace, serializer); errRes != nil {
+			return errorResponse(errRes)
+		}
+	}
+
+	if afterTransaction, ok := cc.afterTransactions[ns]; ok {
+		if _, errRes := cc.callHook(afterTransaction, ns, ctx, successIFace, serializer); errRes != nil {
+			return errorResponse(errRes)
+		}
+	}
+
+	if guard != nil {
+		if err := guard.verify(); err != nil {
+			return errorResponse(err)
+		}
+	}
 
-		if errRes != nil {
-			return shim.Error(errRes.Error())
+	if !deadline.IsZero() {
+		if err := checkDeadline(deadline); err != nil {
+			return shim.Error(err.Error())
 		}
 	}
 
+	if returns.success == nil {
+		successReturn = cc.getVoidResponse(stub)
+	} else if !returns.metadata && isNilReturn(successIFace) {
+		successReturn = nsContract.functionConfigs[fn].nilReturnResponse(successReturn)
+	}
+
+	if nsContract.respTransformer != nil {
+		successReturn, errorReturn = nsContract.respTransformer(stub, successReturn)
+
+		if errorReturn != nil {
+			return errorResponse(errorReturn)
+		}
+	}
+
+	successReturn, errorReturn = cc.compressResponse(successReturn)
+
+	if errorReturn != nil {
+		return shim.Error(errorReturn.Error())
+	}
+
+	if err := cc.checkResponseSize(successReturn); err != nil {
+		return errorResponse(err)
+	}
+
 	return shim.Success([]byte(successReturn))
 }
 
+func (cc *ContractChaincode) splitFunctionName(nsFcn string) (string, string) {
+	li := strings.LastIndex(nsFcn, ":")
+
+	if li == -1 {
+		return cc.defaultContract, nsFcn
+	}
+
+	if ns, ok := cc.aliases[nsFcn[:li]]; ok {
+		return ns, nsFcn[li+1:]
+	}
+
+	return nsFcn[:li], nsFcn[li+1:]
+}
+
+func (cc *ContractChaincode) getTransactionDetails() transactionDetails {
+	details := transactionDetails{}
+	details.featureFlags = cc.getFeatureFlags()
+	details.stateValidation = cc.stateValidation
+	details.canonicalState = cc.canonicalState
+	details.components = &cc.metadata.Components
+	details.stateTriggers = cc.stateTriggers
+	details.stateNamespacing = cc.stateNamespacing
+	details.sharedStatePrefixes = cc.sharedStatePrefixes
+
+	return details
+}
+
+func (cc *ContractChaincode) getVoidResponse(stub shim.ChaincodeStubInterface) string {
+	switch cc.voidResponse {
+	case StatusVoidResponse:
+		return statusOKResponse
+	case TxIDVoidResponse:
+		return stub.GetTxID()
+	default:
+		return ""
+	}
+}
+
+// getContractName returns the name of the contract, or the name of its type if
+// it does not set one
+func getContractName(contract ContractInterface) string {
+	if name := contract.GetName(); name != "" {
+		return name
+	}
+
+	return reflect.TypeOf(contract).Elem().Name()
+}
+
 func (cc *ContractChaincode) addContract(contract ContractInterface, excludeFuncs []string) {
-	ns := contract.GetName()
+	ns := getContractName(contract)
 
-	if ns == "" {
-		ns = reflect.TypeOf(contract).Elem().Name()
+	if err := ValidateName(ns); err != nil {
+		panic(fmt.Sprintf("Invalid name %s for contract. %s", ns, err.Error()))
 	}
 
 	if _, ok := cc.contracts[ns]; ok {
 		panic(fmt.Sprintf("Multiple contracts being merged into chaincode with name %s", contract.GetName()))
 	}
 
+	if other, ok := cc.aliases[ns]; ok {
+		panic(fmt.Sprintf("Cannot use name %s for contract. Name already used as an alias of contract %s", ns, other))
+	}
+
+	contextHandler := contract.GetTransactionContextHandler()
+
+	if err := validateTransactionContextHandler(contextHandler); err != nil {
+		panic(fmt.Sprintf("Invalid transaction context for contract %s. %s", ns, err.Error()))
+	}
+
 	ccn := contractChaincodeContract{}
-	ccn.transactionContextHandler = reflect.ValueOf(contract.GetTransactionContextHandler()).Elem().Type()
-	ccn.transactionContextPtrHandler = reflect.ValueOf(contract.GetTransactionContextHandler()).Type()
+	ccn.transactionContextHandler = reflect.TypeOf(contextHandler).Elem()
+	ccn.transactionContextPtrHandler = reflect.TypeOf(contextHandler)
 	ccn.functions = make(map[string]*contractFunction)
 	ccn.version = contract.GetVersion()
 
@@ -210,6 +589,22 @@ func (cc *ContractChaincode) addContract(contract ContractInterface, excludeFunc
 
 	scT := reflect.PtrTo(reflect.TypeOf(contract).Elem())
 	scV := reflect.ValueOf(contract).Elem().Addr()
+	ccn.receiver = scV
+	ccn.sharedFields = getSharedFields(scT.Elem())
+
+	if ic, ok := contract.(IgnoreContractInterface); ok {
+		for _, name := range ic.GetIgnoredFunctions() {
+			if _, ok := scT.MethodByName(name); !ok {
+				panic(fmt.Sprintf("Cannot ignore function %s. Function not found in contract %s", name, ns))
+			}
+		}
+
+		excludeFuncs = append(append([]string{}, excludeFuncs...), ic.GetIgnoredFunctions()...)
+	}
+
+	if ambiguous := getAmbiguousFunctions(scT.Elem(), excludeFuncs); len(ambiguous) > 0 {
+		panic(fmt.Sprintf("Contract %s embeds multiple contracts defining functions %s. Define them on %s to choose which is used", ns, sliceAsCommaSentence(ambiguous), scT.Elem().Name()))
+	}
 
 	ut := contract.GetUnknownTransaction()
 
@@ -229,6 +624,15 @@ func (cc *ContractChaincode) addContract(contract ContractInterface, excludeFunc
 		ccn.afterTransaction = newTransactionHandler(at, ccn.transactionContextPtrHandler, after)
 	}
 
+	if tc, ok := contract.(TransformerContractInterface); ok {
+		ccn.argTransformer = tc.GetArgumentTransformer()
+		ccn.respTransformer = tc.GetResponseTransformer()
+	}
+
+	if sc, ok := contract.(SerializerContractInterface); ok {
+		ccn.serializer = sc.GetSerializer()
+	}
+
 	for i := 0; i < scT.NumMethod(); i++ {
 		typeMethod := scT.Method(i)
 		valueMethod := scV.Method(i)
@@ -238,6 +642,135 @@ func (cc *ContractChaincode) addContract(contract ContractInterface, excludeFunc
 		}
 	}
 
+	if ic, ok := contract.(InitContractInterface); ok && ic.GetInit() != "" {
+		ccn.initTransaction = ic.GetInit()
+
+		if _, ok := ccn.functions[ccn.initTransaction]; !ok {
+			panic(fmt.Sprintf("Init function %s not found in contract %s", ccn.initTransaction, ns))
+		}
+	}
+
+	if mc, ok := contract.(MiddlewareContractInterface); ok {
+		contractMiddleware := mc.GetMiddleware()
+
+		if len(contractMiddleware) > 0 {
+			ccn.middleware = make(map[string][]*transactionHandler)
+		}
+
+		for _, fn := range contractMiddleware[AllFunctions] {
+			handler := newTransactionHandler(fn, ccn.transactionContextPtrHandler, before)
+
+			for name := range ccn.functions {
+				ccn.middleware[name] = append(ccn.middleware[name], handler)
+			}
+		}
+
+		for name, middleware := range contractMiddleware {
+			if name == AllFunctions {
+				continue
+			}
+
+			if _, ok := ccn.functions[name]; !ok {
+				panic(fmt.Sprintf("Cannot use middleware for function %s. Function not found in contract %s", name, ns))
+			}
+
+			for _, fn := range middleware {
+				ccn.middleware[name] = append(ccn.middleware[name], newTransactionHandler(fn, ccn.transactionContextPtrHandler, before))
+			}
+		}
+	}
+
+	for name, fn := range ccn.functions {
+		pagination, err := getPaginationDetails(fn)
+
+		if err != nil {
+			panic(fmt.Sprintf("Invalid paginated function %s in contract %s. %s", name, ns, err.Error()))
+		}
+
+		if pagination != nil {
+			if ccn.pagination == nil {
+				ccn.pagination = make(map[string]*paginationDetails)
+			}
+
+			ccn.pagination[name] = pagination
+		}
+	}
+
+	if dc, ok := contract.(DependencyContractInterface); ok {
+		ccn.dependencies = dc.GetDependencies()
+	}
+
+	if kc, ok := contract.(ConstantsContractInterface); ok {
+		ccn.constants = kc.GetConstants()
+	}
+
+	if fc, ok := contract.(FunctionConfigContractInterface); ok {
+		ccn.functionConfigs = fc.GetFunctionConfigs()
+
+		for name, config := range ccn.functionConfigs {
+			fn, ok := ccn.functions[name]
+
+			if !ok {
+				panic(fmt.Sprintf("Cannot configure function %s. Function not found in contract %s", name, ns))
+			}
+
+			if config.parameterNames != nil && len(config.parameterNames) != len(fn.params.fields) {
+				panic(fmt.Sprintf("Function %s of contract %s configured with %d parameter names. Expected %d", name, ns, len(config.parameterNames), len(fn.params.fields)))
+			}
+
+			if config.parameterTags != nil && len(config.parameterTags) != len(fn.params.fields) {
+				panic(fmt.Sprintf("Function %s of contract %s configured with %d parameter tags. Expected %d", name, ns, len(config.parameterTags), len(fn.params.fields)))
+			}
+
+			if err := config.validateDefaults(fn, ccn.serializer); err != nil {
+				panic(fmt.Sprintf("Function %s of contract %s configured with invalid defaults. %s", name, ns, err.Error()))
+			}
+		}
+	}
+
+	if acc, ok := contract.(ArgCountContractInterface); ok {
+		ccn.argCountMode = acc.GetArgCountMode()
+
+		if ccn.argCountMode == PadArgCount {
+			for _, fn := range ccn.functions {
+				fn.padParams = true
+			}
+		}
+	}
+
+	if ac, ok := contract.(AliasContractInterface); ok {
+		for _, alias := range ac.GetNameAliases() {
+			if err := ValidateName(alias); err != nil {
+				panic(fmt.Sprintf("Invalid alias %s for contract %s. %s", alias, ns, err.Error()))
+			}
+
+			if _, ok := cc.contracts[alias]; ok || alias == ns {
+				panic(fmt.Sprintf("Cannot use alias %s for contract %s. Name already used by a contract", alias, ns))
+			}
+
+			if other, ok := cc.aliases[alias]; ok {
+				panic(fmt.Sprintf("Cannot use alias %s for contract %s. Name already used as an alias of contract %s", alias, ns, other))
+			}
+
+			if cc.aliases == nil {
+				cc.aliases = make(map[string]string)
+			}
+
+			cc.aliases[alias] = ns
+			ccn.aliases = append(ccn.aliases, alias)
+		}
+	}
+
+	if fac, ok := contract.(FunctionAliasContractInterface); ok {
+		ccn.functionAliases = fac.GetFunctionAliases()
+
+		for alias, function := range ccn.functionAliases {
+			if _, ok := ccn.functions[function]; !ok {
+				panic(fmt.Sprintf("Cannot add alias %s for function %s. Function not found in contract %s", alias, function, ns))
+			}
+		}
+	}
+
 	cc.contracts[ns] = ccn
 
 	if cc.defaultContract == "" {
@@ -265,6 +798,9 @@ func (cc *ContractChaincode) reflectMetadata() ContractChaincodeMetadata {
 		contractMetadata.Name = key
 		contractMetadata.Info.Version = contract.version
 		contractMetadata.Info.Title = key
+		contractMetadata.Constants = contract.constants
+		contractMetadata.Aliases = contract.aliases
+		contractMetadata.Default = key == cc.defaultContract
 
 		for key, fn := range contract.functions {
 			transactionMetadata := TransactionMetadata{}
@@ -275,6 +811,8 @@ func (cc *ContractChaincode) reflectMetadata() ContractChaincodeMetadata {
 				transactionMetadata.Tag = append(transactionMetadata.Tag, "submitTx")
 			}
 
+			requiredParams := fn.requiredParams()
+
 			for index, field := range fn.params.fields {
 				schema, err := getSchema(field, &reflectedMetadata.Components)
 
@@ -282,23 +820,55 @@ func (cc *ContractChaincode) reflectMetadata() ContractChaincodeMetadata {
 					panic(fmt.Sprintf("Failed to generate metadata. Invalid function parameter type. %s", err))
 				}
 
+				setBinaryFormat(schema, field)
+
 				param := ParameterMetadata{}
 				param.Name = fmt.Sprintf("param%d", index)
+				param.Required = index < requiredParams
 				param.Schema = *schema
 
 				transactionMetadata.Parameters = append(transactionMetadata.Parameters, param)
 			}
 
 			if fn.returns.success != nil {
-				schema, err := getSchema(fn.returns.success, &reflectedMetadata.Components)
+				returnType := fn.returns.success
+
+				if isResultStreamFunction(fn) {
+					returnType = resultStreamPageType
+				}
+
+				schema, err := getSchema(returnType, &reflectedMetadata.Components)
 
 				if err != nil {
 					panic(fmt.Sprintf("Failed to generate metadata. Invalid function success return type. %s", err))
 				}
 
+				setBinaryFormat(schema, fn.returns.success)
+
 				transactionMetadata.Returns = schema
 			}
 
+			if fn.returns.metadata {
+				transactionMetadata.Tag = append(transactionMetadata.Tag, TxMetadataTag)
+			}
+
+			if config, ok := contract.functionConfigs[key]; ok {
+				config.applyTo(&transactionMetadata)
+				config.applyDefaultsTo(&transactionMetadata, fn, contract.serializer)
+			}
+
+			if fn.returns.metadata {
+				transactionMetadata.Returns = txMetadataSchema(transactionMetadata.Returns)
+			}
+
+			if pagination, ok := contract.pagination[key]; ok {
+				pagination.applyTo(&transactionMetadata)
+			}
+
+			if isResultStreamFunction(fn) {
+				applyResultStreamMetadata(&transactionMetadata)
+			}
+
 			contractMetadata.Transactions = append(contractMetadata.Transactions, transactionMetadata)
 		}
 
@@ -316,6 +886,8 @@ func (cc *ContractChaincode) augmentMetadata() {
 	fileMetadata := readMetadataFile()
 	reflectedMetadata := cc.reflectMetadata()
 
+	cc.metadataFileMismatches = validateMetadataFile(fileMetadata, reflectedMetadata)
+
 	fileMetadata.append(reflectedMetadata)
 
 	cc.metadata = fileMetadata
@@ -15,10 +15,14 @@
 package contractapi
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/spec"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
@@ -119,10 +123,16 @@ func testCallingContractFunctions(t *testing.T, callType string) {
 	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:somebadfunctionname"}, callType, mc.ReturnsString())
 	mc = myContract{}
 
+	// Should pass requested function name and args to unknown function taking them
+	mc.SetUnknownTransaction(mc.UnknownTransactionWithRequest)
+	cc = convertC2CC(&mc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:somebadfunctionname", "arg1", "arg2"}, callType, "Unknown function somebadfunctionname called with args [arg1 arg2]")
+	mc = myContract{}
+
 	// Should return error when before function returns error and not call main function
 	mc.SetBeforeTransaction(mc.ReturnsError)
 	cc = convertC2CC(&mc)
-	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsString"}, callType, mc.ReturnsError().Error())
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsString"}, callType, "Before transaction failed. "+mc.ReturnsError().Error())
 	mc = myContract{}
 
 	// Should return success from passed function when before function returns no error
@@ -143,12 +153,13 @@ func testCallingContractFunctions(t *testing.T, callType string) {
 	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, callType, mc.ReturnsString())
 	mc = myContract{}
 
-	// Should call before, named then after functions in order and pass name response
+	// Should call before, named then after functions in order and pass name response. Named
+	// function is called on a copy of the contract so does not log to the registered contract
 	mc.SetBeforeTransaction(mc.logBefore)
 	mc.SetAfterTransaction(mc.logAfter)
 	cc = convertC2CC(&mc)
 	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:LogNamed"}, callType, "named response")
-	assert.Equal(t, []string{"Before function called", "Named function called", "After function called with named response"}, mc.called, "Expected called field of myContract to have logged in order before, named then after")
+	assert.Equal(t, []string{"Before function called", "After function called with named response"}, mc.called, "Expected called field of myContract to have logged in order before then after with named response")
 	mc = myContract{}
 
 	// Should call before, unknown then after functions in order and pass unknown response
@@ -200,11 +211,13 @@ func TestReflectMetadata(t *testing.T) {
 	someBadFunctionContractFunction.params = contractFunctionParams{
 		basicContextPtrType,
 		[]reflect.Type{stringRefType, complexType},
+		false,
 	}
 	bcFuncs := make(map[string]*contractFunction)
 	bcFuncs["BadFunction"] = someBadFunctionContractFunction
 	bcccn := contractChaincodeContract{
-		"some version", bcFuncs, nil, nil, nil, nil, nil,
+		version:   "some version",
+		functions: bcFuncs,
 	}
 
 	cc.contracts = map[string]contractChaincodeContract{
@@ -220,13 +233,15 @@ func TestReflectMetadata(t *testing.T) {
 	anotherBadFunctionContractFunction.params = contractFunctionParams{
 		basicContextPtrType,
 		[]reflect.Type{stringRefType},
+		false,
 	}
 	anotherBadFunctionContractFunction.returns = contractFunctionReturns{}
 	anotherBadFunctionContractFunction.returns.success = complexType
 	abcFuncs := make(map[string]*contractFunction)
 	abcFuncs["AnotherBadFunction"] = anotherBadFunctionContractFunction
 	abcccn := contractChaincodeContract{
-		"some version", abcFuncs, nil, nil, nil, nil, nil,
+		version:   "some version",
+		functions: abcFuncs,
 	}
 
 	cc.contracts = map[string]contractChaincodeContract{
@@ -261,18 +276,22 @@ func TestReflectMetadata(t *testing.T) {
 	anotherFunctionContractFunction.params = contractFunctionParams{
 		basicContextPtrType,
 		[]reflect.Type{stringRefType, reflect.TypeOf(SomeStruct{})},
+		false,
 	}
 	anotherFunctionContractFunction.returns = contractFunctionReturns{
 		reflect.TypeOf(SomeStruct{}),
 		true,
+		false,
 	}
 
 	param0AsParam := ParameterMetadata{}
 	param0AsParam.Name = "param0"
+	param0AsParam.Required = true
 	param0AsParam.Schema = *(stringTypeVar.getSchema())
 
 	param1AsParam := ParameterMetadata{}
 	param1AsParam.Name = "param1"
+	param1AsParam.Required = true
 	param1AsParam.Schema = *spec.RefSchema("#/components/schemas/SomeStruct")
 
 	anotherFunctionMetadata := TransactionMetadata{}
@@ -295,7 +314,8 @@ func TestReflectMetadata(t *testing.T) {
 	scFuncs := make(map[string]*contractFunction)
 	scFuncs["SomeFunction"] = someFunctionContractFunction
 	scccn := contractChaincodeContract{
-		"some version", scFuncs, nil, nil, nil, nil, nil,
+		version:   "some version",
+		functions: scFuncs,
 	}
 
 	cscFuncs := make(map[string]*contractFunction)
@@ -303,7 +323,8 @@ func TestReflectMetadata(t *testing.T) {
 
 	cscFuncs["AnotherFunction"] = anotherFunctionContractFunction
 	cscccn := contractChaincodeContract{
-		"some other version", cscFuncs, nil, nil, nil, nil, nil,
+		version:   "some other version",
+		functions: cscFuncs,
 	}
 
 	// Should handle generating metadata for a single name
@@ -422,13 +443,43 @@ func TestReflectMetadata(t *testing.T) {
 	testMetadata(t, cc.reflectMetadata(), expectedMetadata)
 }
 
+type optionalParamsTestContract struct {
+	Contract
+}
+
+func (optc *optionalParamsTestContract) Create(id string, name *string, asset *GoodStruct) string {
+	if name == nil {
+		return id
+	}
+
+	return id + " " + *name
+}
+
+func TestOptionalParams(t *testing.T) {
+	cc := convertC2CC(new(optionalParamsTestContract))
+
+	// Should mark only required params as required in metadata
+	params := cc.metadata.Contracts["optionalParamsTestContract"].Transactions[0].Parameters
+	assert.True(t, params[0].Required, "should mark non pointer param required")
+	assert.False(t, params[1].Required, "should not mark trailing *string param required")
+	assert.False(t, params[2].Required, "should not mark trailing pointer to struct param required")
+
+	// Should call function with nil for omitted params
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "1"}, invokeType, "1")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "1", "asset"}, invokeType, "1 asset")
+
+	// Should error when required param omitted
+	callContractFunctionAndCheckError(t, cc, []string{"Create"}, invokeType, "Incorrect number of params for function Create. Expected at least 1, received 0. Function signature is Create(param0 string, param1 *string, param2 *contractapi.GoodStruct)")
+}
+
 func TestAugmentMetadata(t *testing.T) {
 	someFunctionContractFunction := new(contractFunction)
 
 	scFuncs := make(map[string]*contractFunction)
 	scFuncs["SomeFunction"] = someFunctionContractFunction
 	scccn := contractChaincodeContract{
-		"some version", scFuncs, nil, nil, nil, nil, nil,
+		version:   "some version",
+		functions: scFuncs,
 	}
 
 	cc := ContractChaincode{}
@@ -531,6 +582,27 @@ func TestAddContract(t *testing.T) {
 	cc.addContract(&sc, fullExclude)
 	testContractChaincodeContractRepresentsContract(t, cc.contracts["simpleTestContract"], sc)
 	sc.afterTransaction = nil
+
+	// Should add contract to map with init transaction
+	cc = new(ContractChaincode)
+	cc.contracts = make(map[string]contractChaincodeContract)
+	sc.SetInit("DoSomething")
+	cc.addContract(&sc, fullExclude)
+	assert.Equal(t, "DoSomething", cc.contracts["simpleTestContract"].initTransaction, "should set init transaction")
+
+	// Should panic when init transaction is not a function of the contract
+	cc = new(ContractChaincode)
+	cc.contracts = make(map[string]contractChaincodeContract)
+	sc.SetInit("Missing")
+	assert.PanicsWithValue(t, "Init function Missing not found in contract simpleTestContract", func() { cc.addContract(&sc, fullExclude) }, "should panic when init function does not exist")
+	sc.SetInit("")
+
+	// Should panic when transaction context is not valid
+	cc = new(ContractChaincode)
+	cc.contracts = make(map[string]contractChaincodeContract)
+	sc.SetTransactionContextHandler(new(pointerEmbeddedContext))
+	assert.PanicsWithValue(t, "Invalid transaction context for contract simpleTestContract. Transaction context *contractapi.pointerEmbeddedContext is not valid. Methods SetStub and setTransactionDetails must take a pointer receiver. If embedding TransactionContext embed it by value rather than as a pointer", func() { cc.addContract(&sc, fullExclude) }, "should panic when transaction context invalid")
+	sc.SetTransactionContextHandler(nil)
 }
 
 func TestCreateNewChaincode(t *testing.T) {
@@ -564,6 +636,12 @@ func TestSetTitle(t *testing.T) {
 
 	assert.Equal(t, "some title", cc.title, "should set the title")
 }
+func TestGetChaincodeMetadata(t *testing.T) {
+	cc := convertC2CC(new(myContract))
+
+	assert.Equal(t, cc.metadata, cc.GetMetadata(), "should return the metadata")
+}
+
 func TestSetChaincodeVersion(t *testing.T) {
 	cc := ContractChaincode{}
 	cc.SetVersion("some version")
@@ -572,13 +650,134 @@ func TestSetChaincodeVersion(t *testing.T) {
 }
 
 func TestSetDefault(t *testing.T) {
-	c := new(myContract)
-	c.SetName("some name")
+	first := new(middlewareTestContract)
+	first.SetName("first")
+	second := new(middlewareTestContract)
+
+	cc := convertC2CC(first, second)
+	assert.True(t, cc.metadata.Contracts["first"].Default, "should mark first contract as default in metadata")
+
+	// Should set default contract using name of its type when contract not named
+	cc.SetDefault(second)
+	assert.Equal(t, "middlewareTestContract", cc.defaultContract, "should set the default contract name")
+	assert.True(t, cc.metadata.Contracts["middlewareTestContract"].Default, "should mark default contract in metadata")
+	assert.False(t, cc.metadata.Contracts["first"].Default, "should unmark previous default contract in metadata")
+
+	sysC := cc.contracts[SystemContractName].receiver.Interface().(*systemContract)
+	assert.Contains(t, sysC.GetMetadata(), `"name":"middlewareTestContract","transactions"`, "should update metadata of system contract")
+	assert.Contains(t, sysC.GetMetadata(), `"default":true`, "should include default in metadata of system contract")
+
+	// Should panic when contract not in chaincode
+	other := new(myContract)
+	other.SetName("some name")
+	assert.PanicsWithValue(t, "Cannot set default contract some name. Contract not found in chaincode", func() { cc.SetDefault(other) }, "should panic for unknown contract")
+}
 
+func TestSetVoidResponse(t *testing.T) {
 	cc := ContractChaincode{}
-	cc.SetDefault(c)
+	cc.SetVoidResponse(TxIDVoidResponse)
+
+	assert.Equal(t, TxIDVoidResponse, cc.voidResponse, "should set the void response")
+}
+
+func TestVoidResponse(t *testing.T) {
+	mc := myContract{}
+	cc := convertC2CC(&mc)
+
+	// Should return empty payload by default
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsNil"}, invokeType, "")
+
+	// Should return status acknowledgement when set
+	cc.SetVoidResponse(StatusVoidResponse)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsNil"}, invokeType, "{\"status\":\"OK\"}")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsNothing"}, invokeType, "{\"status\":\"OK\"}")
+
+	// Should return the transaction ID when set
+	cc.SetVoidResponse(TxIDVoidResponse)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsNil"}, invokeType, standardTxID)
+
+	// Should not affect functions that declare a success return
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, mc.ReturnsString())
+
+	// Should still return errors
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsError"}, invokeType, mc.ReturnsError().Error())
+
+	// Should apply to unknown transactions without a success return
+	mc.SetUnknownTransaction(mc.ReturnsNothing)
+	cc = convertC2CC(&mc)
+	cc.SetVoidResponse(StatusVoidResponse)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:somebadfunctionname"}, invokeType, "{\"status\":\"OK\"}")
+}
+
+func TestTransformers(t *testing.T) {
+	reverse := func(stub shim.ChaincodeStubInterface, args []string) ([]string, error) {
+		return []string{args[1], args[0]}, nil
+	}
+
+	wrap := func(stub shim.ChaincodeStubInterface, response string) (string, error) {
+		return fmt.Sprintf("<%s:%s>", stub.GetTxID(), response), nil
+	}
+
+	mc := myContract{}
+	mc.SetArgumentTransformer(reverse)
+	cc := convertC2CC(&mc)
+
+	// Should transform args before conversion
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:UsesContext", standardValue, standardAssetID}, invokeType, "You called a function that uses the ctx")
+
+	// Should return error when argument transformer errors
+	mc.SetArgumentTransformer(func(stub shim.ChaincodeStubInterface, args []string) ([]string, error) {
+		return nil, errors.New("Could not decrypt args")
+	})
+	cc = convertC2CC(&mc)
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsString"}, invokeType, "Could not decrypt args")
+
+	// Should transform the response
+	mc = myContract{}
+	mc.SetResponseTransformer(wrap)
+	cc = convertC2CC(&mc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, "<"+standardTxID+":"+mc.ReturnsString()+">")
+
+	// Should not transform error responses
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsError"}, invokeType, mc.ReturnsError().Error())
 
-	assert.Equal(t, "some name", cc.defaultContract, "should set the default contract name")
+	// Should return error when response transformer errors
+	mc.SetResponseTransformer(func(stub shim.ChaincodeStubInterface, response string) (string, error) {
+		return "", errors.New("Could not encrypt response")
+	})
+	cc = convertC2CC(&mc)
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsString"}, invokeType, "Could not encrypt response")
+}
+
+func TestInvokeWithMaps(t *testing.T) {
+	cc := convertC2CC(new(mapTestContract))
+
+	// Should convert map params and marshal map returns
+	callContractFunctionAndCheckSuccess(t, cc, []string{"UsesStringMap", "{\"key\":\"value\"}"}, invokeType, "{\"key\":\"value\"}")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"UsesIntMap", "{\"key\":1}"}, invokeType, "{\"key\":1}")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"UsesStructMap", "{\"key\":{\"Prop1\":\"value\",\"prop2\":1}}"}, invokeType, "{\"key\":{\"Prop1\":\"value\",\"prop2\":1}}")
+
+	// Should error when map items are of wrong type
+	callContractFunctionAndCheckError(t, cc, []string{"UsesIntMap", "{\"key\":\"value\"}"}, invokeType, "Failed to convert arg for parameter 0 (param0) of function UsesIntMap. Expected type map[string]int, received \"{\\\"key\\\":\\\"value\\\"}\". Value {\"key\":\"value\"} was not passed in expected format map[string]int")
+
+	// Should error when map struct items do not match schema
+	response := shimtest.NewMockStub("mapTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("UsesStructMap"), []byte("{\"key\":{\"Prop1\":\"value\"}}")})
+	assert.Equal(t, int32(400), response.Status, "should return status 400 when schema not matched")
+	assert.Equal(t, "Value passed for parameter \"param0\" did not match schema: 1. prop.key: prop2 is required", response.Message, "should describe schema failures")
+	assert.Equal(t, `{"parameter":"param0","failures":[{"property":"key.prop2","message":"prop2 is required"}]}`, string(response.Payload), "should list failing properties")
+
+	// Should describe maps in metadata
+	var structMapMetadata TransactionMetadata
+	for _, tx := range cc.metadata.Contracts["mapTestContract"].Transactions {
+		if tx.Name == "UsesStructMap" {
+			structMapMetadata = tx
+		}
+	}
+
+	expectedSchema := spec.MapProperty(spec.RefSchema("#/components/schemas/GoodStruct"))
+	assert.Equal(t, *expectedSchema, structMapMetadata.Parameters[0].Schema, "should use map schema for map param")
+	assert.Equal(t, expectedSchema, structMapMetadata.Returns, "should use map schema for map return")
+	assert.Equal(t, goodStructMetadata, cc.metadata.Components.Schemas["GoodStruct"], "should add component for map items")
 }
 
 func TestInit(t *testing.T) {
@@ -591,6 +790,207 @@ func TestInit(t *testing.T) {
 	testCallingContractFunctions(t, initType)
 }
 
+func TestInitDesignation(t *testing.T) {
+	mc := myContract{}
+	mc.SetInit("ReturnsString")
+	cc := convertC2CC(&mc)
+
+	// Should call designated init function
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, initType, mc.ReturnsString())
+
+	// Should reject other functions during init
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:ReturnsInt"}, initType, "Function ReturnsInt cannot be called during Init of contract myContract. Expected ReturnsString")
+	callContractFunctionAndCheckError(t, cc, []string{"ReturnsInt"}, initType, "Function ReturnsInt cannot be called during Init of contract myContract. Expected ReturnsString")
+
+	// Should not restrict functions called via invoke
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsInt"}, invokeType, fmt.Sprint(mc.ReturnsInt()))
+}
+
+func TestConstants(t *testing.T) {
+	mc := myContract{}
+	mc.AddConstants("currencies", []string{"GBP", "USD"})
+	cc := convertC2CC(&mc)
+
+	// Should include constants in metadata
+	assert.Equal(t, map[string]interface{}{"currencies": []string{"GBP", "USD"}}, cc.metadata.Contracts["myContract"].Constants, "should include constants in contract metadata")
+	assert.Nil(t, cc.metadata.Contracts[SystemContractName].Constants, "should not include constants for contracts without them")
+
+	// Should return constants from system contract
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetContractConstants", "myContract"}, invokeType, "{\"currencies\":[\"GBP\",\"USD\"]}")
+	callContractFunctionAndCheckError(t, cc, []string{SystemContractName + ":GetContractConstants", SystemContractName}, invokeType, "Contract "+SystemContractName+" does not define constants")
+}
+
 func TestInvoke(t *testing.T) {
 	testCallingContractFunctions(t, invokeType)
 }
+
+type middlewareTestContract struct {
+	Contract
+}
+
+func (mtc *middlewareTestContract) Read(ctx *TransactionContext) string {
+	calls, _ := ctx.GetData("calls")
+	return strings.Join(append(calls.([]string), "Read"), ",")
+}
+
+func (mtc *middlewareTestContract) Update(ctx *TransactionContext) string {
+	calls, _ := ctx.GetData("calls")
+	return strings.Join(append(calls.([]string), "Update"), ",")
+}
+
+func recordCall(name string) func(*TransactionContext) {
+	return func(ctx *TransactionContext) {
+		calls, _ := ctx.GetData("calls")
+		ctx.SetData("calls", append(calls.([]string), name))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var mtc *middlewareTestContract
+	var cc ContractChaincode
+
+	// Should call middleware in order for named function only
+	mtc = new(middlewareTestContract)
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.Use("Read", recordCall("first"), recordCall("second"))
+	cc = convertC2CC(mtc)
+	assert.Len(t, cc.contracts["middlewareTestContract"].middleware["Read"], 2, "should create handler for each middleware")
+
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Read"}, invokeType, "before,first,second,Read")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update"}, invokeType, "before,Update")
+
+	// Should call middleware for all functions before that of named function
+	mtc = new(middlewareTestContract)
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.Use("Read", recordCall("named"))
+	mtc.Use(AllFunctions, recordCall("all"))
+	cc = convertC2CC(mtc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Read"}, invokeType, "before,all,named,Read")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update"}, invokeType, "before,all,Update")
+
+	// Should stop when middleware returns error
+	mtc = new(middlewareTestContract)
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.Use("Read", func() error { return errors.New("middleware failed") }, func() { panic("should not be called") })
+	cc = convertC2CC(mtc)
+	callContractFunctionAndCheckError(t, cc, []string{"Read"}, invokeType, "middleware failed")
+
+	// Should panic when function does not exist
+	mtc = new(middlewareTestContract)
+	mtc.Use("Missing", recordCall("first"))
+	assert.PanicsWithValue(t, "Cannot use middleware for function Missing. Function not found in contract middlewareTestContract", func() { convertC2CC(mtc) }, "should panic for missing function")
+
+	// Should panic when middleware not valid
+	mtc = new(middlewareTestContract)
+	mtc.Use("Read", func(str string) {})
+	assert.PanicsWithValue(t, "Before transactions may not take any params other than the transaction context", func() { convertC2CC(mtc) }, "should panic for invalid middleware")
+}
+
+func TestNameAliases(t *testing.T) {
+	var mtc *middlewareTestContract
+	var cc ContractChaincode
+
+	// Should call contract using its name or aliases
+	mtc = new(middlewareTestContract)
+	mtc.SetName("org.asset.simple")
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.AddNameAlias("simpleasset")
+	mtc.AddNameAlias("asset")
+	cc = convertC2CC(mtc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"org.asset.simple:Read"}, invokeType, "before,Read")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"simpleasset:Read"}, invokeType, "before,Read")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"asset:Update"}, invokeType, "before,Update")
+	callContractFunctionAndCheckError(t, cc, []string{"other:Read"}, invokeType, "Contract not found with name other")
+
+	// Should list aliases in metadata under contract name
+	assert.Equal(t, []string{"simpleasset", "asset"}, cc.metadata.Contracts["org.asset.simple"].Aliases, "should include aliases in metadata")
+	_, ok := cc.metadata.Contracts["simpleasset"]
+	assert.False(t, ok, "should not include alias as contract in metadata")
+
+	// Should panic when alias used by another contract
+	mtc = new(middlewareTestContract)
+	mtc.AddNameAlias("org.asset.simple")
+	other := new(middlewareTestContract)
+	other.SetName("org.asset.simple")
+	assert.PanicsWithValue(t, "Cannot use alias org.asset.simple for contract middlewareTestContract. Name already used by a contract", func() { convertC2CC(other, mtc) }, "should panic when alias is name of earlier contract")
+	assert.PanicsWithValue(t, "Cannot use name org.asset.simple for contract. Name already used as an alias of contract middlewareTestContract", func() { convertC2CC(mtc, other) }, "should panic when name is alias of earlier contract")
+
+	other.AddNameAlias("simpleasset")
+	mtc.nameAliases = []string{"simpleasset"}
+	assert.PanicsWithValue(t, "Cannot use alias simpleasset for contract middlewareTestContract. Name already used as an alias of contract org.asset.simple", func() { convertC2CC(other, mtc) }, "should panic when alias is alias of earlier contract")
+}
+
+func TestIgnoredFunctions(t *testing.T) {
+	var mtc *middlewareTestContract
+	var cc ContractChaincode
+
+	// Should not add ignored functions to contract or metadata
+	mtc = new(middlewareTestContract)
+	mtc.SetBeforeTransaction(func(ctx *TransactionContext) { ctx.SetData("calls", []string{"before"}) })
+	mtc.SetIgnoredFunctions("Update")
+	cc = convertC2CC(mtc)
+	_, ok := cc.contracts["middlewareTestContract"].functions["Update"]
+	assert.False(t, ok, "should not add ignored function")
+	assert.Len(t, cc.metadata.Contracts["middlewareTestContract"].Transactions, 1, "should not include ignored function in metadata")
+	callContractFunctionAndCheckError(t, cc, []string{"Update"}, invokeType, "Function Update not found in contract middlewareTestContract")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Read"}, invokeType, "before,Read")
+
+	// Should panic when configuring ignored function
+	mtc = new(middlewareTestContract)
+	mtc.SetIgnoredFunctions("Update")
+	mtc.Use("Update", recordCall("first"))
+	assert.PanicsWithValue(t, "Cannot use middleware for function Update. Function not found in contract middlewareTestContract", func() { convertC2CC(mtc) }, "should panic for middleware of ignored function")
+
+	// Should panic when function does not exist
+	mtc = new(middlewareTestContract)
+	mtc.SetIgnoredFunctions("Missing")
+	assert.PanicsWithValue(t, "Cannot ignore function Missing. Function not found in contract middlewareTestContract", func() { convertC2CC(mtc) }, "should panic for missing function")
+}
+
+type receiverTestContract struct {
+	Contract
+	Value string
+}
+
+func (rtc *receiverTestContract) Echo(value string) string {
+	rtc.Value = value
+	time.Sleep(time.Millisecond)
+
+	return rtc.Value
+}
+
+func (rtc *receiverTestContract) GetValue() string {
+	return rtc.Value
+}
+
+func TestInvokeReceiverIsolation(t *testing.T) {
+	rtc := receiverTestContract{Value: "initial"}
+	cc := convertC2CC(&rtc)
+
+	// Should call function on copy of registered contract
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Echo", "changed"}, invokeType, "changed")
+	assert.Equal(t, "initial", rtc.Value, "should not change registered contract")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetValue"}, invokeType, "initial")
+
+	// Should not share receiver between concurrent transactions
+	var wg sync.WaitGroup
+	responses := make([]string, 20)
+
+	for i := 0; i < len(responses); i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			mockStub := shimtest.NewMockStub("receiverTest", &cc)
+			response := mockStub.MockInvoke(fmt.Sprintf("tx%d", i), [][]byte{[]byte("Echo"), []byte(fmt.Sprint(i))})
+			responses[i] = string(response.Payload)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i, response := range responses {
+		assert.Equal(t, fmt.Sprint(i), response, "should return value passed to transaction")
+	}
+}
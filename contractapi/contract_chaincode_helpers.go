@@ -42,7 +42,7 @@ func convertC2CC(contracts ...ContractInterface) ContractChaincode {
 
 	for _, contract := range contracts {
 		additionalExcludes := []string{}
-		if embedsStruct(contract, "contractapi.Contract") {
+		if isContractStruct(reflect.TypeOf(contract).Elem()) {
 			additionalExcludes = contractMethods
 		}
 		cc.addContract(contract, append(ciMethods, additionalExcludes...))
@@ -50,6 +50,7 @@ func convertC2CC(contracts ...ContractInterface) ContractChaincode {
 
 	sysC := new(systemContract)
 	sysC.SetName(SystemContractName)
+	configureSystemContract(sysC)
 
 	cc.addContract(sysC, append(ciMethods, contractMethods...))
 
@@ -61,9 +62,54 @@ func convertC2CC(contracts ...ContractInterface) ContractChaincode {
 
 	cc.augmentMetadata()
 
+	cc.setSystemContractMetadata()
+
+	cc.setSystemContractVersions()
+
+	cc.compileRoutes()
+
+	constants := make(map[string]map[string]interface{})
+
+	for name, contract := range cc.contracts {
+		if len(contract.constants) > 0 {
+			constants[name] = contract.constants
+		}
+	}
+
+	sysC.setConstants(constants)
+
+	dependencies := make(map[string][]Dependency)
+
+	for name, contract := range cc.contracts {
+		if len(contract.dependencies) > 0 {
+			dependencies[name] = contract.dependencies
+		}
+	}
+
+	sysC.setDependencies(dependencies)
+
+	return cc
+}
+
+// setSystemContractMetadata marks the default contract in the metadata and
+// passes the metadata to the system contract to be returned by its transactions
+func (cc *ContractChaincode) setSystemContractMetadata() {
+	for name, contract := range cc.metadata.Contracts {
+		contract.Default = name == cc.defaultContract
+		cc.metadata.Contracts[name] = contract
+	}
+
+	sysC := cc.contracts[SystemContractName].receiver.Interface().(*systemContract)
+
 	metadataJSON, _ := json.Marshal(cc.metadata)
 
 	sysC.setMetadata(string(metadataJSON))
 
-	return cc
+	metadata := cc.metadata
+
+	sysC.setOpenAPI(func() string {
+		openAPIJSON, _ := json.Marshal(metadata.toOpenAPI())
+
+		return string(openAPIJSON)
+	})
 }
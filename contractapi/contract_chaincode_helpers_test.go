@@ -75,6 +75,7 @@ func testConvertCC(t *testing.T, testData []simpleTestContract) {
 		contractMetadata.Transactions = []TransactionMetadata{
 			simpleContractFunctionMetadata,
 		}
+		contractMetadata.Default = i == 0
 
 		expectedSysMetadata.Contracts[ns] = contractMetadata
 
@@ -99,18 +100,65 @@ func testConvertCC(t *testing.T, testData []simpleTestContract) {
 	systemContractFunctionMetadata.Name = "GetMetadata"
 	systemContractFunctionMetadata.Returns = &successSchema
 
+	formattedMetadataFunctionMetadata := TransactionMetadata{}
+	formattedMetadataFunctionMetadata.Name = "GetFormattedMetadata"
+	formatSchema := *spec.StringProperty()
+	formatSchema.Default = MetadataFormatJSON
+	componentsOnlySchema := *spec.BoolProperty()
+	componentsOnlySchema.Default = false
+	formattedMetadataFunctionMetadata.Parameters = []ParameterMetadata{{Name: "format", Schema: formatSchema}, {Name: "componentsOnly", Schema: componentsOnlySchema}}
+	formattedMetadataFunctionMetadata.Returns = &successSchema
+
+	constantsFunctionMetadata := TransactionMetadata{}
+	constantsFunctionMetadata.Name = "GetContractConstants"
+	constantsFunctionMetadata.Parameters = []ParameterMetadata{{Name: "param0", Required: true, Schema: successSchema}}
+	constantsFunctionMetadata.Returns = &successSchema
+
+	openAPIFunctionMetadata := TransactionMetadata{}
+	openAPIFunctionMetadata.Name = "GetOpenAPI"
+	openAPIFunctionMetadata.Returns = &successSchema
+
+	versionsFunctionMetadata := TransactionMetadata{}
+	versionsFunctionMetadata.Name = "GetVersions"
+	versionsFunctionMetadata.Returns = &successSchema
+
+	describeFunctionMetadata := TransactionMetadata{}
+	describeFunctionMetadata.Name = "DescribeFunction"
+	describeFunctionMetadata.Parameters = []ParameterMetadata{{Name: "param0", Required: true, Schema: successSchema}, {Name: "param1", Required: true, Schema: successSchema}}
+	describeFunctionMetadata.Returns = &successSchema
+
+	listContractsMetadata := TransactionMetadata{}
+	listContractsMetadata.Name = "ListContracts"
+	listContractsMetadata.Returns = &successSchema
+
+	listFunctionsMetadata := TransactionMetadata{}
+	listFunctionsMetadata.Name = "ListFunctions"
+	listFunctionsMetadata.Parameters = []ParameterMetadata{{Name: "param0", Required: true, Schema: successSchema}}
+	listFunctionsMetadata.Returns = &successSchema
+
+	verifyDependenciesMetadata := TransactionMetadata{}
+	verifyDependenciesMetadata.Name = "VerifyDependencies"
+
 	systemContractMetadata := ContractMetadata{}
 	systemContractMetadata.Info = spec.Info{}
 	systemContractMetadata.Info.Title = "org.hyperledger.fabric"
 	systemContractMetadata.Info.Version = "latest"
 	systemContractMetadata.Name = SystemContractName
 	systemContractMetadata.Transactions = []TransactionMetadata{
+		describeFunctionMetadata,
+		constantsFunctionMetadata,
+		formattedMetadataFunctionMetadata,
 		systemContractFunctionMetadata,
+		openAPIFunctionMetadata,
+		versionsFunctionMetadata,
+		listContractsMetadata,
+		listFunctionsMetadata,
+		verifyDependenciesMetadata,
 	}
 
 	expectedSysMetadata.Contracts[SystemContractName] = systemContractMetadata
 
-	metadata, _, _ := fn.call(reflect.Value{}, nil, nil)
+	metadata, _, _ := fn.call(reflect.Value{}, nil, nil, nil)
 
 	ccMetadata := ContractChaincodeMetadata{}
 
@@ -139,5 +187,5 @@ func TestConvertC2CC(t *testing.T) {
 	testConvertCC(t, []simpleTestContract{sc, csc})
 
 	// Should panic when contract has function with same name as a Contract function but does not embed Contract and function is invalid
-	assert.PanicsWithValue(t, fmt.Sprintf("SetTransactionContextHandler contains invalid parameter type. Type contractapi.TransactionContextInterface is not valid. Expected a struct, one of the basic types %s, an array/slice of these, or one of these additional types %s", listBasicTypes(), basicContextPtrType.String()), func() { convertC2CC(new(Contract)) }, "should have panicked due to bad function format")
+	assert.PanicsWithValue(t, fmt.Sprintf("GetArgumentTransformer contains invalid single return type. Type contractapi.ArgumentTransformer is not valid. Expected a struct, one of the basic types %s, an array/slice of these, or one of these additional types error", listBasicTypes()), func() { convertC2CC(new(Contract)) }, "should have panicked due to bad function format")
 }
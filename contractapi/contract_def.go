@@ -14,6 +14,10 @@
 
 package contractapi
 
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
 // ContractInterface defines functions a valid contract should have. Contracts to
 // be used in chaincode must implement this interface.
 type ContractInterface interface {
@@ -25,7 +29,9 @@ type ContractInterface interface {
 	// When the contract is used in creating a new chaincode this function is called
 	// and the unknown transaction returned is stored. The unknown function is then
 	// called in cases where an unknown function name is passed for a call to the
-	// contract via Init/Invoke of the chaincode. If nil is returned the
+	// contract via Init/Invoke of the chaincode. The unknown function may take,
+	// after the optional transaction context, a string and a []string which are
+	// passed the requested function name and its args. If nil is returned the
 	// chaincode uses its default handling for unknown function names
 	GetUnknownTransaction() interface{}
 
@@ -34,8 +40,11 @@ type ContractInterface interface {
 	// and the before transaction returned is stored. The before function is then
 	// called before the named function on each Init/Invoke of that contract via the
 	// chaincode. When called the before function is passed no extra args, only the
-	// the transaction context (if specified to take it). If nil is returned
-	// then no before function is called on Init/Invoke.
+	// the transaction context (if specified to take it). If the before function
+	// returns an error the named function is not called and Invoke returns it as
+	// a BeforeTransactionError. A value it returns is available to the named
+	// function via GetBeforeTransactionResult of the transaction context. If nil
+	// is returned then no before function is called on Init/Invoke.
 	GetBeforeTransaction() interface{}
 
 	// GetAfterTransaction returns the after function to be used for a contract.
@@ -54,14 +63,115 @@ type ContractInterface interface {
 	// This function can return a blank string but this is undefined behaviour.
 	GetName() string
 
-	// GetTransactionContextHandler returns the TransactionContextInterface that is
+	// GetTransactionContextHandler returns the SettableTransactionContextInterface that is
 	// used by the functions of the contract. When the contract is used in creating
 	// a new chaincode this function is called and the transaction context returned
 	// is stored. When the chaincode is called via Init/Invoke a transaction context
 	// of the stored type is created and sent as a parameter to the named contract
 	// function (and before/after and unknown functions) if the function requires the
 	// context in its list of parameters.
-	GetTransactionContextHandler() TransactionContextInterface
+	GetTransactionContextHandler() SettableTransactionContextInterface
+}
+
+// ArgumentTransformer is called with the args of a transaction before they are
+// converted to the parameter types of the named function. The args returned are
+// used in their place. Returning an error stops the transaction.
+type ArgumentTransformer func(stub shim.ChaincodeStubInterface, args []string) ([]string, error)
+
+// ResponseTransformer is called with the payload of a successful transaction
+// before it is returned. The value returned is used in its place. Returning
+// an error causes the transaction to return that error.
+type ResponseTransformer func(stub shim.ChaincodeStubInterface, response string) (string, error)
+
+// TransformerContractInterface can optionally be implemented by a contract to
+// transform the args and responses of all its transactions e.g. to decrypt
+// args and encrypt responses using a key passed in the transient data.
+type TransformerContractInterface interface {
+	// GetArgumentTransformer returns the transformer to apply to args before
+	// conversion. If nil is returned the args are used as passed.
+	GetArgumentTransformer() ArgumentTransformer
+
+	// GetResponseTransformer returns the transformer to apply to successful
+	// responses. If nil is returned the response is returned as is.
+	GetResponseTransformer() ResponseTransformer
+}
+
+// SerializerContractInterface can optionally be implemented by a contract to
+// customise how the args of its transactions are converted to the parameter
+// types of its functions and how returned values are converted to the payload
+type SerializerContractInterface interface {
+	// GetSerializer returns the serializer to use for the contract's
+	// transactions. If nil is returned the chaincode's serializer is used.
+	GetSerializer() Serializer
+}
+
+// InitContractInterface can optionally be implemented by a contract to
+// designate the only function of the contract callable during Init
+type InitContractInterface interface {
+	// GetInit returns the name of the function callable during Init. If a
+	// blank string is returned any function of the contract can be called.
+	GetInit() string
+}
+
+// ConstantsContractInterface can optionally be implemented by a contract to
+// expose named sets of reference data, e.g. currencies or status codes, that
+// its transactions validate against. The constants are included in the
+// metadata of the contract and can be retrieved using the GetContractConstants
+// transaction of the system contract.
+type ConstantsContractInterface interface {
+	// GetConstants returns the named sets of reference data of the contract.
+	// Each set must be JSON marshallable.
+	GetConstants() map[string]interface{}
+}
+
+// FunctionConfigContractInterface can optionally be implemented by a contract to
+// provide details of its functions for the metadata, such as descriptions and
+// parameter names, without the need for a metadata file
+type FunctionConfigContractInterface interface {
+	// GetFunctionConfigs returns the configs of the contract's functions
+	// keyed by function name
+	GetFunctionConfigs() map[string]*FunctionConfig
+}
+
+// DependencyContractInterface can optionally be implemented by a contract to
+// declare the chaincodes it calls using InvokeChaincode. Dependencies are verified
+// before the first transaction of the contract is called.
+type DependencyContractInterface interface {
+	// GetDependencies returns the chaincodes the contract depends on
+	GetDependencies() []Dependency
+}
+
+// MiddlewareContractInterface can optionally be implemented by a contract to
+// call functions before only some of its functions
+type MiddlewareContractInterface interface {
+	// GetMiddleware returns the middleware of the contract keyed by the name
+	// of the function it is called before, or AllFunctions for middleware called
+	// before every function. Middleware takes the same form as a before transaction.
+	GetMiddleware() map[string][]interface{}
+}
+
+// IgnoreContractInterface can optionally be implemented by a contract to stop
+// some of its exported methods, e.g. helpers exported for use by other packages,
+// from being callable as functions of the contract
+type IgnoreContractInterface interface {
+	// GetIgnoredFunctions returns the names of the methods of the contract
+	// that are not functions of the contract
+	GetIgnoredFunctions() []string
+}
+
+// AliasContractInterface can optionally be implemented by a contract to have
+// it called using names other than its own, e.g. a legacy name during a migration
+type AliasContractInterface interface {
+	// GetNameAliases returns the other names the contract can be called using
+	GetNameAliases() []string
+}
+
+// FunctionAliasContractInterface can optionally be implemented by a contract to
+// have its functions called using names other than their own
+type FunctionAliasContractInterface interface {
+	// GetFunctionAliases returns the names of the functions of the contract keyed
+	// by the other names they can be called using
+	GetFunctionAliases() map[string]string
 }
 
 // Contract defines functions for setting and getting before, after and unknown transactions
@@ -72,11 +182,24 @@ type Contract struct {
 	unknownTransaction interface{}
 	beforeTransaction  interface{}
 	afterTransaction   interface{}
-	contextHandler     TransactionContextInterface
+	contextHandler     SettableTransactionContextInterface
 	name               string
+	argTransformer     ArgumentTransformer
+	respTransformer    ResponseTransformer
+	serializer         Serializer
+	initTransaction    string
+	constants          map[string]interface{}
+	functionConfigs    map[string]*FunctionConfig
+	dependencies       []Dependency
+	middleware         map[string][]interface{}
+	ignoredFunctions   []string
+	nameAliases        []string
+	argCountMode       ArgCountMode
+	functionAliases    map[string]string
 }
 
-// SetVersion sets the version of the contract
+// SetVersion sets the version of the contract e.g. "1.2.0", as shown in the
+// metadata and returned by the GetVersions transaction of the system contract
 func (c *Contract) SetVersion(version string) {
 	c.version = version
 }
@@ -86,7 +209,9 @@ func (c *Contract) GetVersion() string {
 	return c.version
 }
 
-// SetUnknownTransaction sets function for contract's unknownTransaction.
+// SetUnknownTransaction sets function for contract's unknownTransaction. The
+// function may take the requested function name and args, e.g.
+// func(ctx *TransactionContext, fn string, args []string) error
 func (c *Contract) SetUnknownTransaction(fn interface{}) {
 	c.unknownTransaction = fn
 }
@@ -116,7 +241,30 @@ func (c *Contract) GetAfterTransaction() interface{} {
 	return c.afterTransaction
 }
 
-// SetName sets the name for the contract.
+// SetArgumentTransformer sets the function used to transform args of the
+// contract's transactions before they are converted
+func (c *Contract) SetArgumentTransformer(fn ArgumentTransformer) {
+	c.argTransformer = fn
+}
+
+// GetArgumentTransformer returns the current set argument transformer, may be nil
+func (c *Contract) GetArgumentTransformer() ArgumentTransformer {
+	return c.argTransformer
+}
+
+// SetResponseTransformer sets the function used to transform the successful
+// responses of the contract's transactions
+func (c *Contract) SetResponseTransformer(fn ResponseTransformer) {
+	c.respTransformer = fn
+}
+
+// GetResponseTransformer returns the current set response transformer, may be nil
+func (c *Contract) GetResponseTransformer() ResponseTransformer {
+	return c.respTransformer
+}
+
+// SetName sets the name for the contract. The name must meet the grammar of
+// contract names (see ValidateName) otherwise CreateNewChaincode panics.
 func (c *Contract) SetName(name string) {
 	c.name = name
 }
@@ -129,16 +277,163 @@ func (c *Contract) GetName() string {
 
 // SetTransactionContextHandler sets the transaction context type to be used for
 // the contract.
-func (c *Contract) SetTransactionContextHandler(ctx TransactionContextInterface) {
+func (c *Contract) SetTransactionContextHandler(ctx SettableTransactionContextInterface) {
 	c.contextHandler = ctx
 }
 
 // GetTransactionContextHandler returns the current transaction context set for
 // the contract.
-func (c *Contract) GetTransactionContextHandler() TransactionContextInterface {
+func (c *Contract) GetTransactionContextHandler() SettableTransactionContextInterface {
 	if c.contextHandler == nil {
 		return new(TransactionContext)
 	}
 
 	return c.contextHandler
 }
+
+// SetSerializer sets the serializer used for the contract's transactions
+func (c *Contract) SetSerializer(serializer Serializer) {
+	c.serializer = serializer
+}
+
+// GetSerializer returns the current set serializer, may be nil
+func (c *Contract) GetSerializer() Serializer {
+	return c.serializer
+}
+
+// SetInit sets the name of the only function of the contract that can be
+// called during Init
+func (c *Contract) SetInit(name string) {
+	c.initTransaction = name
+}
+
+// GetInit returns the name of the function set to be called during Init,
+// may be blank
+func (c *Contract) GetInit() string {
+	return c.initTransaction
+}
+
+// AddConstants adds a named set of reference data to the contract. Adding
+// a set with the name of an existing set replaces it.
+func (c *Contract) AddConstants(name string, values interface{}) {
+	if c.constants == nil {
+		c.constants = make(map[string]interface{})
+	}
+
+	c.constants[name] = values
+}
+
+// GetConstants returns the named sets of reference data added to the
+// contract, may be nil
+func (c *Contract) GetConstants() map[string]interface{} {
+	return c.constants
+}
+
+// ConfigureFunction returns the config of the named function, creating it if
+// the function has not yet been configured
+func (c *Contract) ConfigureFunction(name string) *FunctionConfig {
+	if c.functionConfigs == nil {
+		c.functionConfigs = make(map[string]*FunctionConfig)
+	}
+
+	if _, ok := c.functionConfigs[name]; !ok {
+		c.functionConfigs[name] = new(FunctionConfig)
+	}
+
+	return c.functionConfigs[name]
+}
+
+// GetFunctionConfigs returns the configs of functions configured using
+// ConfigureFunction, may be nil
+func (c *Contract) GetFunctionConfigs() map[string]*FunctionConfig {
+	return c.functionConfigs
+}
+
+// AddDependency declares that the contract calls the chaincode described
+// by the dependency. Dependencies are verified by submitting the
+// VerifyDependencies transaction of the system contract.
+func (c *Contract) AddDependency(dependency Dependency) {
+	c.dependencies = append(c.dependencies, dependency)
+}
+
+// GetDependencies returns the dependencies added to the contract, may be nil
+func (c *Contract) GetDependencies() []Dependency {
+	return c.dependencies
+}
+
+// AllFunctions can be passed as the name to Use to add middleware to be called before
+// every function of the contract
+const AllFunctions = "*"
+
+// Use adds middleware to be called before the named function, after the before
+// transaction of the contract. Middleware takes the same form as a before transaction
+// and is called in the order added, with middleware added for AllFunctions called
+// first. If a middleware function returns an error the remaining middleware and the
+// named function are not called.
+func (c *Contract) Use(name string, middleware ...interface{}) {
+	if c.middleware == nil {
+		c.middleware = make(map[string][]interface{})
+	}
+
+	c.middleware[name] = append(c.middleware[name], middleware...)
+}
+
+// GetMiddleware returns the middleware added to functions of the contract
+// using Use, may be nil
+func (c *Contract) GetMiddleware() map[string][]interface{} {
+	return c.middleware
+}
+
+// SetIgnoredFunctions sets the names of exported methods of the contract that
+// are not to be callable as functions of the contract or included in its metadata
+func (c *Contract) SetIgnoredFunctions(names ...string) {
+	c.ignoredFunctions = names
+}
+
+// GetIgnoredFunctions returns the names of methods set to be ignored, may be nil
+func (c *Contract) GetIgnoredFunctions() []string {
+	return c.ignoredFunctions
+}
+
+// AddNameAlias adds a name the contract can be called using as well as its own.
+// The metadata of the contract lists its aliases under its own name.
+func (c *Contract) AddNameAlias(alias string) {
+	c.nameAliases = append(c.nameAliases, alias)
+}
+
+// GetNameAliases returns the aliases added to the contract, may be nil
+func (c *Contract) GetNameAliases() []string {
+	return c.nameAliases
+}
+
+// SetArgCountMode sets how the contract's transactions handle being passed too
+// many or too few args (see ArgCountMode)
+func (c *Contract) SetArgCountMode(mode ArgCountMode) {
+	c.argCountMode = mode
+}
+
+// GetArgCountMode returns the arg count mode of the contract, TruncateArgCount
+// if not set
+func (c *Contract) GetArgCountMode() ArgCountMode {
+	return c.argCountMode
+}
+
+// AddFunctionAlias adds an alternate name the named function of the contract can
+// be called using e.g. read for Read. Calls using the alias are handled exactly as
+// calls using the name of the function. Names of functions of the contract take
+// precedence over aliases so an alias matching the name of a function is not used.
+// Replaces any existing alias of the same name. Creating a chaincode with the
+// contract panics if the function does not exist.
+func (c *Contract) AddFunctionAlias(alias string, function string) {
+	if c.functionAliases == nil {
+		c.functionAliases = make(map[string]string)
+	}
+
+	c.functionAliases[alias] = function
+}
+
+// GetFunctionAliases returns the names of the functions of the contract keyed by
+// their aliases, may be nil
+func (c *Contract) GetFunctionAliases() map[string]string {
+	return c.functionAliases
+}
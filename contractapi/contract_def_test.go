@@ -17,6 +17,7 @@ package contractapi
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -119,6 +120,130 @@ func TestGetVersion(t *testing.T) {
 	assert.Equal(t, "some version", c.GetVersion(), "should set the version")
 }
 
+func TestSetArgumentTransformer(t *testing.T) {
+	c := Contract{}
+	c.SetArgumentTransformer(func(stub shim.ChaincodeStubInterface, args []string) ([]string, error) {
+		return []string{"transformed"}, nil
+	})
+
+	args, _ := c.argTransformer(nil, nil)
+	assert.Equal(t, []string{"transformed"}, args, "should set the argument transformer")
+}
+
+func TestGetArgumentTransformer(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetArgumentTransformer(), "should return nil when not set")
+
+	c.argTransformer = func(stub shim.ChaincodeStubInterface, args []string) ([]string, error) {
+		return []string{"transformed"}, nil
+	}
+
+	args, _ := c.GetArgumentTransformer()(nil, nil)
+	assert.Equal(t, []string{"transformed"}, args, "should return the set argument transformer")
+}
+
+func TestSetResponseTransformer(t *testing.T) {
+	c := Contract{}
+	c.SetResponseTransformer(func(stub shim.ChaincodeStubInterface, response string) (string, error) {
+		return "transformed", nil
+	})
+
+	response, _ := c.respTransformer(nil, "")
+	assert.Equal(t, "transformed", response, "should set the response transformer")
+}
+
+func TestGetResponseTransformer(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetResponseTransformer(), "should return nil when not set")
+
+	c.respTransformer = func(stub shim.ChaincodeStubInterface, response string) (string, error) {
+		return "transformed", nil
+	}
+
+	response, _ := c.GetResponseTransformer()(nil, "")
+	assert.Equal(t, "transformed", response, "should return the set response transformer")
+}
+
+func TestSetSerializer(t *testing.T) {
+	c := Contract{}
+	c.SetSerializer(new(JSONSerializer))
+
+	assert.Equal(t, new(JSONSerializer), c.serializer, "should set the serializer")
+}
+
+func TestGetSerializer(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetSerializer(), "should return nil when not set")
+
+	c.serializer = new(JSONSerializer)
+
+	assert.Equal(t, new(JSONSerializer), c.GetSerializer(), "should return the set serializer")
+}
+
+func TestSetInit(t *testing.T) {
+	c := Contract{}
+	c.SetInit("SomeFunction")
+
+	assert.Equal(t, "SomeFunction", c.initTransaction, "should set the init transaction")
+}
+
+func TestGetInit(t *testing.T) {
+	c := Contract{}
+
+	assert.Equal(t, "", c.GetInit(), "should return blank string when not set")
+
+	c.initTransaction = "SomeFunction"
+
+	assert.Equal(t, "SomeFunction", c.GetInit(), "should return the set init transaction")
+}
+
+func TestAddConstants(t *testing.T) {
+	c := Contract{}
+	c.AddConstants("currencies", []string{"GBP", "USD"})
+	c.AddConstants("statuses", map[string]int{"OPEN": 1})
+
+	assert.Equal(t, map[string]interface{}{"currencies": []string{"GBP", "USD"}, "statuses": map[string]int{"OPEN": 1}}, c.constants, "should add each set of constants")
+
+	c.AddConstants("currencies", []string{"EUR"})
+	assert.Equal(t, []string{"EUR"}, c.constants["currencies"], "should replace set with same name")
+}
+
+func TestGetConstants(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetConstants(), "should return nil when no constants added")
+
+	c.constants = map[string]interface{}{"currencies": []string{"GBP"}}
+
+	assert.Equal(t, map[string]interface{}{"currencies": []string{"GBP"}}, c.GetConstants(), "should return added constants")
+}
+
+func TestConfigureFunction(t *testing.T) {
+	c := Contract{}
+
+	// Should create config for function
+	fc := c.ConfigureFunction("Read")
+	assert.Equal(t, new(FunctionConfig), fc, "should return new config")
+	assert.Equal(t, fc, c.functionConfigs["Read"], "should store config")
+
+	// Should return existing config
+	fc.SetEvaluate(true)
+	assert.Equal(t, fc, c.ConfigureFunction("Read"), "should return existing config")
+}
+
+func TestGetFunctionConfigs(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetFunctionConfigs(), "should return nil when no functions configured")
+
+	c.ConfigureFunction("Read")
+
+	assert.Equal(t, map[string]*FunctionConfig{"Read": new(FunctionConfig)}, c.GetFunctionConfigs(), "should return configs")
+}
+
 func TestSetName(t *testing.T) {
 	mc := myContract{}
 
@@ -157,3 +282,96 @@ func TestGetTransactionContextHandler(t *testing.T) {
 	sc.contextHandler = new(customContext)
 	assert.Equal(t, new(customContext), sc.GetTransactionContextHandler(), "should return custom context when set")
 }
+
+func TestAddDependency(t *testing.T) {
+	c := Contract{}
+	c.AddDependency(Dependency{Chaincode: "cc1"})
+	c.AddDependency(Dependency{Chaincode: "cc2", Channel: "ch1"})
+
+	assert.Equal(t, []Dependency{{Chaincode: "cc1"}, {Chaincode: "cc2", Channel: "ch1"}}, c.dependencies, "should add each dependency")
+}
+
+func TestGetDependencies(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetDependencies(), "should return nil when no dependencies added")
+
+	c.dependencies = []Dependency{{Chaincode: "cc1"}}
+
+	assert.Equal(t, []Dependency{{Chaincode: "cc1"}}, c.GetDependencies(), "should return added dependencies")
+}
+
+func TestUse(t *testing.T) {
+	c := Contract{}
+	c.Use("Read", func() {})
+	c.Use("Read", func() {}, func() {})
+	c.Use("Update", func() {})
+
+	assert.Len(t, c.middleware["Read"], 3, "should append middleware for function")
+	assert.Len(t, c.middleware["Update"], 1, "should add middleware for each function")
+}
+
+func TestGetMiddleware(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetMiddleware(), "should return nil when no middleware added")
+
+	c.Use("Read", func() {})
+
+	assert.Len(t, c.GetMiddleware()["Read"], 1, "should return added middleware")
+}
+
+func TestSetIgnoredFunctions(t *testing.T) {
+	c := Contract{}
+
+	c.SetIgnoredFunctions("Helper1", "Helper2")
+
+	assert.Equal(t, []string{"Helper1", "Helper2"}, c.ignoredFunctions, "should set ignored functions")
+}
+
+func TestGetIgnoredFunctions(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetIgnoredFunctions(), "should return nil when no functions ignored")
+
+	c.ignoredFunctions = []string{"Helper1"}
+
+	assert.Equal(t, []string{"Helper1"}, c.GetIgnoredFunctions(), "should return ignored functions")
+}
+
+func TestAddNameAlias(t *testing.T) {
+	c := Contract{}
+
+	c.AddNameAlias("simpleasset")
+	c.AddNameAlias("asset")
+
+	assert.Equal(t, []string{"simpleasset", "asset"}, c.nameAliases, "should add aliases")
+}
+
+func TestGetNameAliases(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetNameAliases(), "should return nil when no aliases added")
+
+	c.nameAliases = []string{"simpleasset"}
+
+	assert.Equal(t, []string{"simpleasset"}, c.GetNameAliases(), "should return aliases")
+}
+
+func TestSetArgCountMode(t *testing.T) {
+	c := Contract{}
+
+	c.SetArgCountMode(PadArgCount)
+
+	assert.Equal(t, PadArgCount, c.argCountMode, "should set arg count mode")
+}
+
+func TestGetArgCountMode(t *testing.T) {
+	c := Contract{}
+
+	assert.Equal(t, TruncateArgCount, c.GetArgCountMode(), "should return truncate when not set")
+
+	c.argCountMode = StrictArgCount
+
+	assert.Equal(t, StrictArgCount, c.GetArgCountMode(), "should return arg count mode")
+}
@@ -23,31 +23,80 @@ import (
 )
 
 type contractFunctionParams struct {
-	context reflect.Type
-	fields  []reflect.Type
+	context   reflect.Type
+	fields    []reflect.Type
+	goContext bool
 }
 
 type contractFunctionReturns struct {
-	success reflect.Type
-	error   bool
+	success  reflect.Type
+	error    bool
+	metadata bool
 }
 
 type contractFunction struct {
-	function reflect.Value
-	params   contractFunctionParams
-	returns  contractFunctionReturns
+	function   reflect.Value
+	params     contractFunctionParams
+	returns    contractFunctionReturns
+	padParams  bool
+	stream     *resultStreamRequest
+	validators []*typeValidator
 }
 
-func (cf contractFunction) call(ctx reflect.Value, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, params ...string) (string, interface{}, error) {
-	values, err := getArgs(cf, ctx, supplementaryMetadata, components, params)
+func (cf contractFunction) call(ctx reflect.Value, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, serializer Serializer, params ...string) (string, interface{}, error) {
+	values, err := getArgs(cf, ctx, supplementaryMetadata, components, serializer, params)
 
 	if err != nil {
 		return "", nil, err
 	}
 
+	return cf.callWithArgs(values, supplementaryMetadata, serializer)
+}
+
+// callWithArgs calls the function with the values returned by getArgs
+func (cf contractFunction) callWithArgs(values []reflect.Value, supplementaryMetadata *TransactionMetadata, serializer Serializer) (string, interface{}, error) {
 	someResp := cf.function.Call(values)
 
-	return handleContractFunctionResponse(someResp, cf)
+	success, iface, err := handleContractFunctionResponse(someResp, cf, serializer)
+
+	if supplementaryMetadata != nil {
+		returns := supplementaryMetadata.Returns
+
+		if cf.returns.metadata {
+			returns = txMetadataResultSchema(returns)
+		}
+
+		if isBase64Bytes(cf.returns.success, returns) {
+			success = encodeBytesReturn(success)
+		}
+	}
+
+	if cf.returns.metadata && err == nil {
+		success, err = newTxMetadataEnvelope(success, iface, cf.returns.success, someResp[1].Interface().(TxMetadata))
+	}
+
+	return success, iface, err
+}
+
+// requiredParams returns the number of params that must be passed to the function.
+// Trailing params of pointer types are optional and passed nil when omitted, as are
+// all params of functions of contracts padding missing args (see PadArgCount).
+func (cf contractFunction) requiredParams() int {
+	if cf.padParams {
+		return 0
+	}
+
+	required := len(cf.params.fields)
+
+	for required > 0 && isOptionalType(cf.params.fields[required-1]) {
+		required--
+	}
+
+	return required
+}
+
+func isOptionalType(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && !isSpecialType(t)
 }
 
 func (cf contractFunction) exists() bool {
@@ -88,7 +137,9 @@ func typeIsValid(t reflect.Type, additionalTypes []reflect.Type) error {
 		additionalTypesString = append(additionalTypesString, el.String())
 	}
 
-	if t.Kind() == reflect.Array {
+	if isSpecialType(t) || isBasicPtrType(t) {
+		return nil
+	} else if t.Kind() == reflect.Array {
 		array := reflect.New(t).Elem()
 		return arrayOfValidType(array)
 	} else if t.Kind() == reflect.Slice {
@@ -113,6 +164,16 @@ func typeIsValid(t reflect.Type, additionalTypes []reflect.Type) error {
 	return nil
 }
 
+// returnTypeIsValid checks the success return of a function as typeIsValid does,
+// additionally allowing functions to return a ResultStream
+func returnTypeIsValid(t reflect.Type, additionalTypes []reflect.Type) error {
+	if t == resultStreamType {
+		return nil
+	}
+
+	return typeIsValid(t, additionalTypes)
+}
+
 func method2ContractFunctionParams(typeMethod reflect.Method, contextHandlerType reflect.Type) (contractFunctionParams, error) {
 	myContractFnParams := contractFunctionParams{}
 
@@ -128,17 +189,35 @@ func method2ContractFunctionParams(typeMethod reflect.Method, contextHandlerType
 		methodName = "Function"
 	}
 
+	contextIndex := startIndex
+
 	for i := startIndex; i < numIn; i++ {
 		inType := typeMethod.Type.In(i)
 
-		typeError := typeIsValid(inType, []reflect.Type{contextHandlerType})
+		if inType == goContextType {
+			if i != startIndex {
+				return contractFunctionParams{}, fmt.Errorf("Functions requiring a context.Context must require it as the first parameter. %s takes it in as parameter %d", methodName, i-startIndex)
+			}
+
+			myContractFnParams.goContext = true
+			contextIndex++
+			continue
+		}
+
+		isContext := isContextType(inType, contextHandlerType)
+
+		var typeError error
+
+		if !isContext {
+			typeError = typeIsValid(inType, []reflect.Type{contextHandlerType})
+		}
 
 		if typeError != nil {
 			return contractFunctionParams{}, fmt.Errorf("%s contains invalid parameter type. %s", methodName, typeError.Error())
-		} else if i != startIndex && inType == contextHandlerType {
-			return contractFunctionParams{}, fmt.Errorf("Functions requiring the TransactionContext must require it as the first parameter. %s takes it in as parameter %d", methodName, i-startIndex)
-		} else if inType == contextHandlerType {
-			usesCtx = contextHandlerType
+		} else if i != contextIndex && isContext {
+			return contractFunctionParams{}, fmt.Errorf("Functions requiring the TransactionContext must require it as the first parameter, or second following a context.Context. %s takes it in as parameter %d", methodName, i-startIndex)
+		} else if isContext {
+			usesCtx = inType
 		} else {
 			myContractFnParams.fields = append(myContractFnParams.fields, inType)
 		}
@@ -148,6 +227,17 @@ func method2ContractFunctionParams(typeMethod reflect.Method, contextHandlerType
 	return myContractFnParams, nil
 }
 
+// isContextType returns whether a parameter of the passed type takes the
+// transaction context, either as the type of the context handler or as an
+// interface, e.g. TransactionContextInterface, implemented by it
+func isContextType(paramType reflect.Type, contextHandlerType reflect.Type) bool {
+	if paramType == contextHandlerType {
+		return true
+	}
+
+	return contextHandlerType != nil && paramType.Kind() == reflect.Interface && paramType.NumMethod() > 0 && contextHandlerType.Implements(paramType)
+}
+
 func method2ContractFunctionReturns(typeMethod reflect.Method) (contractFunctionReturns, error) {
 	numOut := typeMethod.Type.NumOut()
 
@@ -157,34 +247,48 @@ func method2ContractFunctionReturns(typeMethod reflect.Method) (contractFunction
 		methodName = "Function"
 	}
 
-	if numOut > 2 {
-		return contractFunctionReturns{}, fmt.Errorf("Functions may only return a maximum of two values. %s returns %d", methodName, numOut)
+	if numOut > 3 {
+		return contractFunctionReturns{}, fmt.Errorf("Functions may only return a maximum of three values. %s returns %d", methodName, numOut)
+	} else if numOut == 3 {
+		firstOut := typeMethod.Type.Out(0)
+		secondOut := typeMethod.Type.Out(1)
+		thirdOut := typeMethod.Type.Out(2)
+
+		firstTypeError := typeIsValid(firstOut, []reflect.Type{})
+		if firstTypeError != nil {
+			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid first return type. %s", methodName, firstTypeError.Error())
+		} else if secondOut != txMetadataType {
+			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid second return type. Type %s is not valid. Expected contractapi.TxMetadata", methodName, secondOut.String())
+		} else if thirdOut.String() != "error" {
+			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid third return type. Type %s is not valid. Expected error", methodName, thirdOut.String())
+		}
+		return contractFunctionReturns{firstOut, true, true}, nil
 	} else if numOut == 1 {
 		outType := typeMethod.Type.Out(0)
 
 		errorType := reflect.TypeOf((*error)(nil)).Elem()
 
-		typeError := typeIsValid(outType, []reflect.Type{errorType})
+		typeError := returnTypeIsValid(outType, []reflect.Type{errorType})
 
 		if typeError != nil {
 			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid single return type. %s", methodName, typeError.Error())
 		} else if outType == errorType {
-			return contractFunctionReturns{nil, true}, nil
+			return contractFunctionReturns{nil, true, false}, nil
 		}
-		return contractFunctionReturns{outType, false}, nil
+		return contractFunctionReturns{outType, false, false}, nil
 	} else if numOut == 2 {
 		firstOut := typeMethod.Type.Out(0)
 		secondOut := typeMethod.Type.Out(1)
 
-		firstTypeError := typeIsValid(firstOut, []reflect.Type{})
+		firstTypeError := returnTypeIsValid(firstOut, []reflect.Type{})
 		if firstTypeError != nil {
 			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid first return type. %s", methodName, firstTypeError.Error())
 		} else if secondOut.String() != "error" {
 			return contractFunctionReturns{}, fmt.Errorf("%s contains invalid second return type. Type %s is not valid. Expected error", methodName, secondOut.String())
 		}
-		return contractFunctionReturns{firstOut, true}, nil
+		return contractFunctionReturns{firstOut, true, false}, nil
 	}
-	return contractFunctionReturns{nil, false}, nil
+	return contractFunctionReturns{nil, false, false}, nil
 }
 
 func parseMethod(typeMethod reflect.Method, contextHandlerType reflect.Type) (contractFunctionParams, contractFunctionReturns, error) {
@@ -230,7 +334,7 @@ func newContractFunctionFromFunc(fn interface{}, contextHandlerType reflect.Type
 		panic(err.Error())
 	}
 
-	return newContractFunction(fnValue, paramDetails, returnDetails)
+	return newValidatedContractFunction("Function", fnValue, paramDetails, returnDetails)
 }
 
 func newContractFunctionFromReflect(typeMethod reflect.Method, valueMethod reflect.Value, contextHandlerType reflect.Type) *contractFunction {
@@ -240,7 +344,22 @@ func newContractFunctionFromReflect(typeMethod reflect.Method, valueMethod refle
 		panic(err.Error())
 	}
 
-	return newContractFunction(valueMethod, paramDetails, returnDetails)
+	return newValidatedContractFunction(typeMethod.Name, valueMethod, paramDetails, returnDetails)
+}
+
+// newValidatedContractFunction returns a contract function with the validation tags
+// of its parameter types parsed. Panics if any of the tags is invalid.
+func newValidatedContractFunction(methodName string, fnValue reflect.Value, paramDetails contractFunctionParams, returnDetails contractFunctionReturns) *contractFunction {
+	validators, err := newParamValidators(methodName, paramDetails.fields)
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	cf := newContractFunction(fnValue, paramDetails, returnDetails)
+	cf.validators = validators
+
+	return cf
 }
 
 func createArraySliceMapOrStruct(param string, objType reflect.Type) (reflect.Value, error) {
@@ -255,9 +374,36 @@ func createArraySliceMapOrStruct(param string, objType reflect.Type) (reflect.Va
 	return obj.Elem(), nil
 }
 
-func getArgs(fn contractFunction, ctx reflect.Value, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, params []string) ([]reflect.Value, error) {
+func getArgs(fn contractFunction, ctx reflect.Value, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, serializer Serializer, params []string) ([]reflect.Value, error) {
+	return getArgsWithSchemas(fn, ctx, supplementaryMetadata, components, nil, serializer, params)
+}
+
+// compileParameterSchema compiles the schema of the parameter, with the components
+// it may reference, for args passed for the parameter to be validated against
+func compileParameterSchema(param ParameterMetadata, components *ComponentMetadata) (*gojsonschema.Schema, error) {
+	combined := make(map[string]interface{})
+	combined["components"] = components
+	combined["properties"] = make(map[string]interface{})
+	combined["properties"].(map[string]interface{})["prop"] = param.Schema
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(combined))
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid schema for parameter \"%s\": %s", param.Name, err.Error())
+	}
+
+	return schema, nil
+}
+
+// getArgsWithSchemas converts the params as getArgs does, validating them against
+// the passed schemas compiled for the parameters of the supplementary metadata
+// rather than compiling them. Schemas that are nil are compiled.
+func getArgsWithSchemas(fn contractFunction, ctx reflect.Value, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, schemas []*gojsonschema.Schema, serializer Serializer, params []string) ([]reflect.Value, error) {
 	var shouldValidate bool
 
+	serializer = getSerializer(serializer)
+	_, usesJSON := serializer.(*JSONSerializer)
+
 	numParams := len(fn.params.fields)
 
 	if supplementaryMetadata != nil {
@@ -268,13 +414,13 @@ func getArgs(fn contractFunction, ctx reflect.Value, supplementaryMetadata *Tran
 		}
 	}
 
-	values := []reflect.Value{}
+	values := fn.params.contextValues(ctx)
 
-	if fn.params.context != nil {
-		values = append(values, ctx)
-	}
+	requiredParams := fn.requiredParams()
 
-	if len(params) < numParams {
+	if len(params) < requiredParams && requiredParams < numParams {
+		return nil, fmt.Errorf("Incorrect number of params. Expected at least %d, received %d", requiredParams, len(params))
+	} else if len(params) < requiredParams {
 		return nil, fmt.Errorf("Incorrect number of params. Expected %d, received %d", numParams, len(params))
 	}
 
@@ -282,61 +428,74 @@ func getArgs(fn contractFunction, ctx reflect.Value, supplementaryMetadata *Tran
 
 		fieldType := fn.params.fields[i]
 
-		var converted reflect.Value
+		if i >= len(params) {
+			values = append(values, reflect.Zero(fieldType))
+			continue
+		}
+
 		toValidate := make(map[string]interface{})
-		var err error
-		if fieldType.Kind() == reflect.Array || fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Map {
-			converted, err = createArraySliceMapOrStruct(params[i], fieldType)
 
-			if err != nil {
-				return nil, err
-			}
+		arg := params[i]
 
-			toValidate["prop"] = converted.Interface()
+		if shouldValidate && isBase64Bytes(fieldType, &supplementaryMetadata.Parameters[i].Schema) {
+			var err error
 
-		} else if fieldType.Kind() == reflect.Struct || (fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct) {
-			converted, err = createArraySliceMapOrStruct(params[i], fieldType)
+			arg, err = decodeBytesArg(supplementaryMetadata.Parameters[i].Name, arg)
 
 			if err != nil {
 				return nil, err
 			}
+		}
 
-			structMap := make(map[string]interface{})
-			json.Unmarshal([]byte(params[i]), &structMap)
-			toValidate["prop"] = structMap
-
-		} else {
-			converted, err = basicTypes[fieldType.Kind()].convert(params[i])
+		converted, err := serializer.FromString(arg, fieldType)
 
-			if err != nil {
-				return nil, fmt.Errorf("Param %s could not be converted to type %s", params[i], fieldType.String())
-			}
+		if err != nil {
+			return nil, newConversionError(supplementaryMetadata, i, fieldType, arg, err)
+		}
 
+		if usesJSON && isMarshallingType(fieldType) {
+			// validate the JSON passed rather than the converted value so that
+			// missing properties of structs are not hidden by zero values
+			var raw interface{}
+			json.Unmarshal([]byte(params[i]), &raw)
+			toValidate["prop"] = raw
+		} else {
 			toValidate["prop"] = converted.Interface()
 		}
 
 		if shouldValidate {
+			var schema *gojsonschema.Schema
 
-			suppSchema := supplementaryMetadata.Parameters[i].Schema
+			if i < len(schemas) {
+				schema = schemas[i]
+			}
 
-			combined := make(map[string]interface{})
-			combined["components"] = components
-			combined["properties"] = make(map[string]interface{})
-			combined["properties"].(map[string]interface{})["prop"] = suppSchema
+			if schema == nil {
+				schema, err = compileParameterSchema(supplementaryMetadata.Parameters[i], components)
 
-			combinedLoader := gojsonschema.NewGoLoader(combined)
-			toValidateLoader := gojsonschema.NewGoLoader(toValidate)
+				if err != nil {
+					return nil, err
+				}
+			}
 
-			schema, err := gojsonschema.NewSchema(combinedLoader)
+			result, _ := schema.Validate(gojsonschema.NewGoLoader(toValidate))
 
-			if err != nil {
-				return nil, fmt.Errorf("Invalid schema for parameter \"%s\": %s", supplementaryMetadata.Parameters[i].Name, err.Error())
+			if !result.Valid() {
+				return nil, newSchemaValidationError(supplementaryMetadata.Parameters[i].Name, result.Errors())
 			}
+		}
 
-			result, _ := schema.Validate(toValidateLoader)
+		paramName := fmt.Sprintf("param%d", i)
 
-			if !result.Valid() {
-				return nil, fmt.Errorf("Value passed for parameter \"%s\" did not match schema: %s", supplementaryMetadata.Parameters[i].Name, validateErrorsToString(result.Errors()))
+		if shouldValidate {
+			paramName = supplementaryMetadata.Parameters[i].Name
+		}
+
+		if i < len(fn.validators) {
+			err = fn.validators[i].validateParam(paramName, converted)
+
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -346,12 +505,14 @@ func getArgs(fn contractFunction, ctx reflect.Value, supplementaryMetadata *Tran
 	return values, nil
 }
 
-func handleContractFunctionResponse(response []reflect.Value, function contractFunction) (string, interface{}, error) {
+func handleContractFunctionResponse(response []reflect.Value, function contractFunction, serializer Serializer) (string, interface{}, error) {
 	expectedLength := 0
 
 	returnsSuccess := function.returns.success != nil
 
-	if returnsSuccess && function.returns.error {
+	if function.returns.metadata {
+		expectedLength = 3
+	} else if returnsSuccess && function.returns.error {
 		expectedLength = 2
 	} else if returnsSuccess || function.returns.error {
 		expectedLength = 1
@@ -362,7 +523,10 @@ func handleContractFunctionResponse(response []reflect.Value, function contractF
 		var successResponse reflect.Value
 		var errorResponse reflect.Value
 
-		if returnsSuccess && function.returns.error {
+		if function.returns.metadata {
+			successResponse = response[0]
+			errorResponse = response[2]
+		} else if returnsSuccess && function.returns.error {
 			successResponse = response[0]
 			errorResponse = response[1]
 		} else if returnsSuccess {
@@ -371,19 +535,16 @@ func handleContractFunctionResponse(response []reflect.Value, function contractF
 			errorResponse = response[0]
 		}
 
+		if function.returns.success == resultStreamType {
+			return function.stream.respond(successResponse, errorResponse, serializer)
+		}
+
 		var successString string
 		var errorError error
 		var iface interface{}
 
 		if successResponse.IsValid() {
-			if !isNillableType(successResponse.Kind()) || !successResponse.IsNil() {
-				if isMarshallingType(function.returns.success) || function.returns.success.Kind() == reflect.Interface && isMarshallingType(successResponse.Type()) {
-					bytes, _ := json.Marshal(successResponse.Interface())
-					successString = string(bytes)
-				} else {
-					successString = fmt.Sprint(successResponse.Interface())
-				}
-			}
+			successString, errorError = getSerializer(serializer).ToString(successResponse, function.returns.success)
 
 			iface = successResponse.Interface()
 		}
@@ -403,5 +564,9 @@ func isNillableType(kind reflect.Kind) bool {
 }
 
 func isMarshallingType(typ reflect.Type) bool {
+	if isSpecialType(typ) {
+		return false
+	}
+
 	return typ.Kind() == reflect.Array || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map || typ.Kind() == reflect.Struct || (typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct)
 }
@@ -17,6 +17,7 @@ package contractapi
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 	"sort"
 	"testing"
@@ -121,7 +122,7 @@ func testMethod2ContractFunctionParams(t *testing.T, funcFromStruct bool) {
 	params, err = method2ContractFunctionParams(method, basicContextPtrType)
 
 	assert.Equal(t, contractFunctionParams{}, params, "should return a blank contractFunctionParams")
-	assert.EqualError(t, err, fmt.Sprintf("Functions requiring the TransactionContext must require it as the first parameter. %s takes it in as parameter 1", methodName), "should error when context used but not first arg")
+	assert.EqualError(t, err, fmt.Sprintf("Functions requiring the TransactionContext must require it as the first parameter, or second following a context.Context. %s takes it in as parameter 1", methodName), "should error when context used but not first arg")
 
 	// Should return contractFunctionParams for method with no params
 	if funcFromStruct {
@@ -483,7 +484,7 @@ func setContractFunctionParams(cf *contractFunction, context reflect.Type, field
 func callGetArgsAndBasicTest(t *testing.T, cf contractFunction, ctx *TransactionContext, supplementaryMetadata *TransactionMetadata, components *ComponentMetadata, testParams []string) []reflect.Value {
 	t.Helper()
 
-	values, err := getArgs(cf, reflect.ValueOf(ctx), supplementaryMetadata, components, testParams)
+	values, err := getArgs(cf, reflect.ValueOf(ctx), supplementaryMetadata, components, nil, testParams)
 
 	assert.Nil(t, err, "should not return an error for a valid cf")
 
@@ -574,7 +575,7 @@ func testHandleResponse(t *testing.T, successReturn reflect.Type, errorReturn bo
 	cf := contractFunction{}
 
 	setContractFunctionReturns(&cf, successReturn, errorReturn)
-	strResp, valueResp, errResp := handleContractFunctionResponse(response, cf)
+	strResp, valueResp, errResp := handleContractFunctionResponse(response, cf, nil)
 
 	assert.Equal(t, expectedString, strResp, "should have returned string value from response")
 	assert.Equal(t, expectedValue, valueResp, "should have returned actual value from response")
@@ -655,6 +656,13 @@ func TestTypeIsValid(t *testing.T) {
 	assert.Nil(t, typeIsValid(float32RefType, []reflect.Type{}), "should not return an error for float32 type")
 	assert.Nil(t, typeIsValid(float64RefType, []reflect.Type{}), "should not return an error for float64 type")
 
+	// Should accept pointers to basic types
+	assert.Nil(t, typeIsValid(reflect.TypeOf(new(string)), []reflect.Type{}), "should not return an error for *string type")
+	assert.Nil(t, typeIsValid(reflect.TypeOf(new(int)), []reflect.Type{}), "should not return an error for *int type")
+
+	// Should return error for pointer to interface
+	assert.EqualError(t, typeIsValid(reflect.TypeOf(new(interface{})), []reflect.Type{}), fmt.Sprintf(basicErr, "*interface {}", listBasicTypes()), "should have returned error for pointer to interface")
+
 	mc := myContract{}
 	mcFuncType := reflect.TypeOf(mc.AfterTransactionWithInterface)
 
@@ -855,6 +863,21 @@ func TestMethod2ContractFunctionParams(t *testing.T) {
 	testMethod2ContractFunctionParams(t, true)
 }
 
+func TestIsContextType(t *testing.T) {
+	ifaceType := reflect.TypeOf((*TransactionContextInterface)(nil)).Elem()
+
+	// Should be context when handler type or interface implemented by handler
+	assert.True(t, isContextType(basicContextPtrType, basicContextPtrType), "should be context for handler type")
+	assert.True(t, isContextType(ifaceType, basicContextPtrType), "should be context for interface implemented by handler")
+	assert.True(t, isContextType(ifaceType, ifaceType), "should be context for interface handler")
+
+	// Should not be context for other types
+	assert.False(t, isContextType(stringRefType, basicContextPtrType), "should not be context for basic type")
+	assert.False(t, isContextType(reflect.TypeOf((*interface{})(nil)).Elem(), basicContextPtrType), "should not be context for empty interface")
+	assert.False(t, isContextType(errorType, basicContextPtrType), "should not be context for interface not implemented by handler")
+	assert.False(t, isContextType(ifaceType, nil), "should not be context without handler")
+}
+
 func TestMethod2ContractFunctionReturns(t *testing.T) {
 	testMethod2ContractFunctionReturns(t, false)
 	testMethod2ContractFunctionReturns(t, true)
@@ -1081,19 +1104,63 @@ func TestGetArgs(t *testing.T) {
 		stringRefType,
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, []string{})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "Incorrect number of params. Expected 1, received 0", "should error when missing params")
 
-	// should error when supplementary JSON has not enough params
+	// Should error when fewer params than required sent to function with optional params
+	setContractFunctionParams(&cf, nil, []reflect.Type{
+		stringRefType,
+		reflect.TypeOf(new(string)),
+	})
+
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{})
+	assert.Nil(t, values, "should not return values when missing required params")
+	assert.EqualError(t, err, "Incorrect number of params. Expected at least 1, received 0", "should error when missing required params")
+
+	// Should pass nil for missing trailing pointer params
+	setContractFunctionParams(&cf, nil, []reflect.Type{
+		stringRefType,
+		reflect.TypeOf(new(string)),
+		reflect.TypeOf(new(GoodStruct)),
+	})
+
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"one"})
+	assert.Nil(t, err, "should not error when optional params missing")
+	assert.Len(t, values, 3, "should return value for each param")
+	assert.Equal(t, "one", values[0].Interface(), "should convert passed param")
+	assert.Nil(t, values[1].Interface(), "should pass nil *string")
+	assert.Nil(t, values[2].Interface(), "should pass nil *GoodStruct")
+
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"one", "two", "{\"Prop1\": \"three\", \"prop2\": 3}"})
+	assert.Nil(t, err, "should not error when optional params passed")
+	assert.Equal(t, "two", *(values[1].Interface().(*string)), "should convert *string param")
+	assert.Equal(t, &GoodStruct{Prop1: "three", Prop2: 3}, values[2].Interface(), "should convert *GoodStruct param")
+
+	// Should validate passed optional params against supplementary metadata
 	tm := new(TransactionMetadata)
+	tm.Parameters = []ParameterMetadata{
+		{Name: "id", Required: true, Schema: *spec.StringProperty()},
+		{Name: "name", Schema: *spec.StringProperty().WithMaxLength(2)},
+		{Name: "asset", Schema: *spec.RefSchema("#/components/schemas/GoodStruct")},
+	}
+
+	values, err = getArgs(cf, reflect.ValueOf(ctx), tm, nil, nil, []string{"one"})
+	assert.Nil(t, err, "should not validate missing optional params")
+	assert.Len(t, values, 3, "should return value for each param when validating")
+
+	_, err = getArgs(cf, reflect.ValueOf(ctx), tm, nil, nil, []string{"one", "two2"})
+	assert.Contains(t, err.Error(), "Value passed for parameter \"name\" did not match schema", "should validate passed optional params")
+
+	// should error when supplementary JSON has not enough params
+	tm = new(TransactionMetadata)
 	tm.Parameters = []ParameterMetadata{}
 
 	setContractFunctionParams(&cf, nil, []reflect.Type{
 		stringRefType,
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), tm, nil, []string{})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), tm, nil, nil, []string{})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "Incorrect number of params in supplementary metadata. Expected 1, received 0", "should error when missing params")
 
@@ -1142,7 +1209,7 @@ func TestGetArgs(t *testing.T) {
 	// Should be using context passed
 	setContractFunctionParams(&cf, reflect.TypeOf(new(customContext)), []reflect.Type{})
 
-	values, err = getArgs(cf, reflect.ValueOf(new(customContext)), nil, nil, testParams)
+	values, err = getArgs(cf, reflect.ValueOf(new(customContext)), nil, nil, nil, testParams)
 
 	assert.Nil(t, err, "should not return an error for a valid cf")
 	assert.Equal(t, 1, len(values), "should return same length array list as number of fields plus 1 for context")
@@ -1223,9 +1290,9 @@ func TestGetArgs(t *testing.T) {
 		intRefType,
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, []string{"abc"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"abc"})
 
-	assert.EqualError(t, err, "Param abc could not be converted to type int", "should have returned error when convert returns error")
+	assert.EqualError(t, err, "Failed to convert arg for parameter 0. Expected type int, received \"abc\". Param abc could not be converted to type int", "should have returned error when convert returns error")
 	assert.Nil(t, values, "should not have returned value list on error")
 
 	// Should handle array of basic type
@@ -1249,8 +1316,8 @@ func TestGetArgs(t *testing.T) {
 		reflect.TypeOf([4]int{}),
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, []string{"[1,2,3,\"a\"]"})
-	assert.EqualError(t, err, "Value [1,2,3,\"a\"] was not passed in expected format [4]int", "should have returned error when array conversion returns error")
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"[1,2,3,\"a\"]"})
+	assert.EqualError(t, err, "Failed to convert arg for parameter 0. Expected type [4]int, received \"[1,2,3,\\\"a\\\"]\". Value [1,2,3,\"a\"] was not passed in expected format [4]int", "should have returned error when array conversion returns error")
 	assert.Nil(t, values, "should not have returned value list on error")
 
 	// Should error when the element in multidimensional array they pass is not the correct format
@@ -1258,8 +1325,8 @@ func TestGetArgs(t *testing.T) {
 		reflect.TypeOf([4][1]int{}),
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, []string{"[[1],[2],[3],[\"a\"]]"})
-	assert.EqualError(t, err, "Value [[1],[2],[3],[\"a\"]] was not passed in expected format [4][1]int", "should have returned error when array conversion returns error")
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"[[1],[2],[3],[\"a\"]]"})
+	assert.EqualError(t, err, "Failed to convert arg for parameter 0. Expected type [4][1]int, received \"[[1],[2],[3],[\\\"a\\\"]]\". Value [[1],[2],[3],[\"a\"]] was not passed in expected format [4][1]int", "should have returned error when array conversion returns error")
 	assert.Nil(t, values, "should not have returned value list on error")
 
 	// should handle map of basic type
@@ -1285,8 +1352,8 @@ func TestGetArgs(t *testing.T) {
 		reflect.TypeOf(GoodStruct{}),
 	})
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, []string{"{\"Prop1\": \"Hello world\" \"prop2\": \"\"}"})
-	assert.EqualError(t, err, "Value {\"Prop1\": \"Hello world\" \"prop2\": \"\"} was not passed in expected format contractapi.GoodStruct", "should have returned error when array conversion returns error")
+	values, err = getArgs(cf, reflect.ValueOf(ctx), nil, nil, nil, []string{"{\"Prop1\": \"Hello world\" \"prop2\": \"\"}"})
+	assert.EqualError(t, err, "Failed to convert arg for parameter 0. Expected type contractapi.GoodStruct, received \"{\\\"Prop1\\\": \\\"Hello world\\\" \\\"prop2\\\": \\\"\\\"}\". Value {\"Prop1\": \"Hello world\" \"prop2\": \"\"} was not passed in expected format contractapi.GoodStruct", "should have returned error when array conversion returns error")
 	assert.Nil(t, values, "should not have returned value list on error")
 
 	// should handle struct in struct
@@ -1325,7 +1392,7 @@ func TestGetArgs(t *testing.T) {
 	txMetadata.Parameters = make([]ParameterMetadata, 1)
 	txMetadata.Parameters[0] = paramsMetadata
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, []string{"-1"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, nil, []string{"-1"})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "did not match schema", "should error when schema bad")
 
@@ -1342,7 +1409,7 @@ func TestGetArgs(t *testing.T) {
 	txMetadata.Parameters = make([]ParameterMetadata, 1)
 	txMetadata.Parameters[0] = paramsMetadata
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, []string{"{}"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, nil, []string{"{}"})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "did not match schema", "should error when schema bad")
 
@@ -1359,7 +1426,7 @@ func TestGetArgs(t *testing.T) {
 	txMetadata.Parameters = make([]ParameterMetadata, 1)
 	txMetadata.Parameters[0] = paramsMetadata
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, []string{"{\"additionalProp\": \"some val\"}"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, nil, nil, []string{"{\"additionalProp\": \"some val\"}"})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "did not match schema", "should error when schema bad")
 
@@ -1395,7 +1462,7 @@ func TestGetArgs(t *testing.T) {
 	components.Schemas = make(map[string]ObjectMetadata)
 	components.Schemas["GoodStruct"] = goodStructMetadata
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, &components, []string{"{\"Prop1\": \"hello world\", \"prop2\": 1}"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, &components, nil, []string{"{\"Prop1\": \"hello world\", \"prop2\": 1}"})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "Invalid schema for parameter \"some param\"", "should error when schema bad")
 
@@ -1425,7 +1492,7 @@ func TestGetArgs(t *testing.T) {
 	customMetadata.Properties["prop2"] = *prop2Schema
 	components.Schemas["GoodStruct"] = customMetadata
 
-	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, &components, []string{"{\"Prop1\": \"hello world\", \"prop2\": 1}"})
+	values, err = getArgs(cf, reflect.ValueOf(ctx), &txMetadata, &components, nil, []string{"{\"Prop1\": \"hello world\", \"prop2\": 1}"})
 	assert.Nil(t, values, "should not return values when parameter data bad")
 	assert.Contains(t, err.Error(), "did not match schema", "should error when schema bad")
 }
@@ -1444,25 +1511,25 @@ func TestHandleContractFunctionResponse(t *testing.T) {
 
 	// Should panic if response to handle is longer than the contractFunctions expected return
 	setContractFunctionReturns(&cf, nil, false)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, stringRefType, false)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, nil, true)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, errorValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, stringRefType, true)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, stringRefType, true)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{errorValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{errorValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, stringRefType, true)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, stringValue, errorValue}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{stringValue, stringValue, errorValue}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	setContractFunctionReturns(&cf, stringRefType, true)
-	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{}, cf) }, "should have panicked as response did not match the contractFunctions expected response format")
+	assert.PanicsWithValue(t, "Response does not match expected return for given function.", func() { handleContractFunctionResponse([]reflect.Value{}, cf, nil) }, "should have panicked as response did not match the contractFunctions expected response format")
 
 	// Should return string and nil error values when response contains string and nil error and expecting both
 	response = []reflect.Value{stringValue, nilErrorValue}
@@ -1595,7 +1662,7 @@ func TestCall(t *testing.T) {
 	cf = newContractFunctionFromFunc(mc.UsesContext, basicContextPtrType)
 
 	expectedStr, expectedErr = mc.UsesContext(ctx, standardAssetID, standardValue)
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, standardAssetID, standardValue)
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil, standardAssetID, standardValue)
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as a regular call to UsesContext would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned the string value returned by UsesContext as actual value")
@@ -1604,7 +1671,7 @@ func TestCall(t *testing.T) {
 	// Should call function of contract function with correct params and return expected values for function returning nothing
 	cf = newContractFunctionFromFunc(mc.ReturnsNothing, basicContextPtrType)
 
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil)
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil)
 
 	assert.Equal(t, "", actualStr, "Should have returned blank string")
 	assert.Nil(t, actualValue, "should have returned nil when no value defined to return")
@@ -1615,7 +1682,7 @@ func TestCall(t *testing.T) {
 
 	expectedStr = mc.ReturnsString()
 
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil)
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil)
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as regular call to ReturnsString would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned string that ReturnsString returns as the actual value")
@@ -1626,7 +1693,7 @@ func TestCall(t *testing.T) {
 
 	expectedStr = mc.UsesBasics("some string", true, 123, 45, 6789, 101112, 131415, 123, 45, 6789, 101112, 131415, 1.1, 2.2, 65, 66)
 
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, "some string", "true", "123", "45", "6789", "101112", "131415", "123", "45", "6789", "101112", "131415", "1.1", "2.2", "65", "66")
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil, "some string", "true", "123", "45", "6789", "101112", "131415", "123", "45", "6789", "101112", "131415", "1.1", "2.2", "65", "66")
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as regular call to UsesBasics would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned string that UsesBasics returns as the actual value")
@@ -1637,7 +1704,7 @@ func TestCall(t *testing.T) {
 
 	expectedErr = mc.ReturnsError()
 
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil)
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil)
 
 	assert.Equal(t, "", actualStr, "Should have returned blank string")
 	assert.Nil(t, actualValue, "should be nil as ReturnsError returns no success type")
@@ -1646,15 +1713,35 @@ func TestCall(t *testing.T) {
 	// Should return error when getArgs returns an error
 	cf = newContractFunctionFromFunc(mc.UsesArray, basicContextPtrType)
 
-	expectedErr = errors.New("Value [1] was not passed in expected format [1]string")
+	expectedErr = errors.New("Failed to convert arg for parameter 0. Expected type [1]string, received \"[1]\". Value [1] was not passed in expected format [1]string")
 
-	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, "[1]")
+	actualStr, actualValue, actualErr = cf.call(reflect.ValueOf(ctx), nil, nil, nil, "[1]")
 
 	assert.Equal(t, "", actualStr, "Should have returned blank string")
 	assert.Nil(t, nil, "Should have returned nil as getArgs causes an error")
 	assert.EqualError(t, actualErr, expectedErr.Error(), "Should have returned error from getArgs would")
 }
 
+func TestRequiredParams(t *testing.T) {
+	cf := contractFunction{}
+
+	// Should require all params when none are pointers
+	setContractFunctionParams(&cf, nil, []reflect.Type{stringRefType, intRefType})
+	assert.Equal(t, 2, cf.requiredParams(), "should require all non pointer params")
+
+	// Should not require trailing pointer params
+	setContractFunctionParams(&cf, nil, []reflect.Type{stringRefType, reflect.TypeOf(new(string)), reflect.TypeOf(new(GoodStruct))})
+	assert.Equal(t, 1, cf.requiredParams(), "should not require trailing pointer params")
+
+	// Should require pointer params followed by required params
+	setContractFunctionParams(&cf, nil, []reflect.Type{reflect.TypeOf(new(string)), stringRefType})
+	assert.Equal(t, 2, cf.requiredParams(), "should require pointer param before required param")
+
+	// Should require special types that are pointers
+	setContractFunctionParams(&cf, nil, []reflect.Type{reflect.TypeOf(new(big.Int))})
+	assert.Equal(t, 1, cf.requiredParams(), "should require *big.Int param")
+}
+
 func TestExists(t *testing.T) {
 	mc := myContract{}
 	cf := contractFunction{}
@@ -0,0 +1,302 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package contracttest provides utilities for testing chaincode built using
+// contractapi against mock stubs
+package contracttest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// ReplayTxID the transaction ID used for both runs of an invocation checked
+// for determinism
+const ReplayTxID = "determinism-check"
+
+// Invocation describes a transaction to invoke and the world state to invoke it
+// against
+type Invocation struct {
+	Args      []string
+	State     map[string][]byte
+	Creator   []byte
+	Transient map[string][]byte
+	Timestamp time.Time
+}
+
+// Result holds the outcome of running an invocation against a mock stub. Writes
+// holds the value of every key written, nil for deleted keys, and PrivateWrites
+// the same for each collection of private data. Accesses holds the calls to
+// GetState, PutState and DelState made by the invocation in order.
+type Result struct {
+	Response      peer.Response
+	Writes        map[string][]byte
+	PrivateWrites map[string]map[string][]byte
+	Events        []*peer.ChaincodeEvent
+	Accesses      []StateAccess
+}
+
+// NondeterminismError is returned when two runs of the same invocation produce
+// different results
+type NondeterminismError struct {
+	Differences []string
+}
+
+func (ne *NondeterminismError) Error() string {
+	toReturn := ""
+
+	for i, difference := range ne.Differences {
+		toReturn += strconv.Itoa(i+1) + ". " + difference + "\n"
+	}
+
+	return "Invocation was not deterministic: " + strings.Trim(toReturn, "\n")
+}
+
+// CheckDeterminism runs the invocation twice against identical mock state, with
+// the same transaction ID, creator and timestamp, and returns a NondeterminismError
+// listing any differences in the responses, write sets and events of the runs.
+// Differences indicate behaviour such as iterating maps, reading the clock or
+// using random numbers that would cause endorsing peers to disagree. Where the
+// invocation sets no timestamp the current time is used for both runs.
+func CheckDeterminism(chaincode shim.Chaincode, invocation Invocation) error {
+	if invocation.Timestamp.IsZero() {
+		invocation.Timestamp = time.Now()
+	}
+
+	first, err := Replay(chaincode, invocation)
+
+	if err != nil {
+		return err
+	}
+
+	second, err := Replay(chaincode, invocation)
+
+	if err != nil {
+		return err
+	}
+
+	differences := diffResults(first, second)
+
+	if len(differences) > 0 {
+		return &NondeterminismError{differences}
+	}
+
+	return nil
+}
+
+// Replay invokes the chaincode against a new mock stub holding the state of the
+// invocation and returns the result
+func Replay(chaincode shim.Chaincode, invocation Invocation) (*Result, error) {
+	stub := shimtest.NewMockStub("determinism", chaincode)
+
+	stub.MockTransactionStart("setup")
+
+	for key, value := range invocation.State {
+		err := stub.PutState(key, value)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to set up state for key %s. %s", key, err.Error())
+		}
+	}
+
+	stub.MockTransactionEnd("setup")
+
+	initial := copyState(stub.State)
+
+	timestamp, err := ptypes.TimestampProto(invocation.Timestamp)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid timestamp for invocation. %s", err.Error())
+	}
+
+	stub.MockTransactionStart(ReplayTxID)
+	stub.TxTimestamp = timestamp
+	stub.Creator = invocation.Creator
+
+	args := [][]byte{}
+
+	for _, arg := range invocation.Args {
+		args = append(args, []byte(arg))
+	}
+
+	rs := &replayStub{stub, args, invocation.Transient, nil}
+
+	result := new(Result)
+	result.Response = chaincode.Invoke(rs)
+	result.Accesses = rs.accesses
+
+	stub.MockTransactionEnd(ReplayTxID)
+
+	result.Writes = diffState(initial, stub.State)
+	result.PrivateWrites = make(map[string]map[string][]byte)
+
+	for collection, state := range stub.PvtState {
+		result.PrivateWrites[collection] = diffState(map[string][]byte{}, state)
+	}
+
+	for len(stub.ChaincodeEventsChannel) > 0 {
+		result.Events = append(result.Events, <-stub.ChaincodeEventsChannel)
+	}
+
+	return result, nil
+}
+
+// replayStub passes the args of the invocation, which the mock stub only sets
+// when invoking through it and so setting a new timestamp, and its transient data.
+// It records the accesses of the invocation to the world state.
+type replayStub struct {
+	*shimtest.MockStub
+	args      [][]byte
+	transient map[string][]byte
+	accesses  []StateAccess
+}
+
+func (rs *replayStub) GetTransient() (map[string][]byte, error) {
+	return rs.transient, nil
+}
+
+func (rs *replayStub) GetArgs() [][]byte {
+	return rs.args
+}
+
+func (rs *replayStub) GetStringArgs() []string {
+	strargs := []string{}
+
+	for _, arg := range rs.args {
+		strargs = append(strargs, string(arg))
+	}
+
+	return strargs
+}
+
+func (rs *replayStub) GetFunctionAndParameters() (string, []string) {
+	allargs := rs.GetStringArgs()
+
+	if len(allargs) == 0 {
+		return "", []string{}
+	}
+
+	return allargs[0], allargs[1:]
+}
+
+func copyState(state map[string][]byte) map[string][]byte {
+	copied := make(map[string][]byte)
+
+	for key, value := range state {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+func diffState(initial map[string][]byte, final map[string][]byte) map[string][]byte {
+	writes := make(map[string][]byte)
+
+	for key, value := range final {
+		if existing, ok := initial[key]; !ok || !bytes.Equal(existing, value) {
+			writes[key] = value
+		}
+	}
+
+	for key := range initial {
+		if _, ok := final[key]; !ok {
+			writes[key] = nil
+		}
+	}
+
+	return writes
+}
+
+func diffResults(first *Result, second *Result) []string {
+	differences := diffResponses(first.Response, second.Response)
+	differences = append(differences, diffWrites("key ", first.Writes, second.Writes)...)
+
+	collections := []string{}
+
+	for collection := range first.PrivateWrites {
+		collections = append(collections, collection)
+	}
+
+	for collection := range second.PrivateWrites {
+		if _, ok := first.PrivateWrites[collection]; !ok {
+			collections = append(collections, collection)
+		}
+	}
+
+	sort.Strings(collections)
+
+	for _, collection := range collections {
+		differences = append(differences, diffWrites("private key "+collection+"/", first.PrivateWrites[collection], second.PrivateWrites[collection])...)
+	}
+
+	if len(first.Events) != len(second.Events) {
+		differences = append(differences, fmt.Sprintf("Number of events differed. %d and %d", len(first.Events), len(second.Events)))
+	} else {
+		for i := range first.Events {
+			if first.Events[i].EventName != second.Events[i].EventName || !bytes.Equal(first.Events[i].Payload, second.Events[i].Payload) {
+				differences = append(differences, fmt.Sprintf("Event %d differed. %s %q and %s %q", i, first.Events[i].EventName, first.Events[i].Payload, second.Events[i].EventName, second.Events[i].Payload))
+			}
+		}
+	}
+
+	return differences
+}
+
+func diffWrites(prefix string, first map[string][]byte, second map[string][]byte) []string {
+	keys := []string{}
+
+	for key := range first {
+		keys = append(keys, key)
+	}
+
+	for key := range second {
+		if _, ok := first[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	differences := []string{}
+
+	for _, key := range keys {
+		firstValue, firstOk := first[key]
+		secondValue, secondOk := second[key]
+
+		if firstOk != secondOk || !bytes.Equal(firstValue, secondValue) || (firstValue == nil) != (secondValue == nil) {
+			differences = append(differences, fmt.Sprintf("Write to %s%s differed. %s and %s", prefix, key, describeWrite(firstValue, firstOk), describeWrite(secondValue, secondOk)))
+		}
+	}
+
+	return differences
+}
+
+func describeWrite(value []byte, written bool) string {
+	if !written {
+		return "not written"
+	} else if value == nil {
+		return "deleted"
+	}
+
+	return fmt.Sprintf("%q", value)
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var calls int
+
+type determinismTestContract struct {
+	contractapi.Contract
+}
+
+func (dtc *determinismTestContract) Transfer(ctx *contractapi.TransactionContext, from string, to string) (string, error) {
+	value, err := ctx.GetStub().GetState(from)
+
+	if err != nil {
+		return "", err
+	}
+
+	timestamp, _ := ctx.GetStub().GetTxTimestamp()
+	transient, _ := ctx.GetStub().GetTransient()
+
+	ctx.GetStub().PutState(to, value)
+	ctx.GetStub().DelState(from)
+	ctx.GetStub().PutPrivateData("collection", to, transient["secret"])
+	ctx.GetStub().SetEvent("Transferred", []byte(to))
+
+	return fmt.Sprintf("%d", timestamp.Seconds), nil
+}
+
+func (dtc *determinismTestContract) Count(ctx *contractapi.TransactionContext) error {
+	calls++
+
+	ctx.GetStub().PutState("count", []byte(fmt.Sprint(calls)))
+	ctx.GetStub().PutState(fmt.Sprintf("call%d", calls), []byte("called"))
+	ctx.GetStub().PutPrivateData("collection", "count", []byte(fmt.Sprint(calls)))
+	ctx.GetStub().SetEvent("Counted", []byte(fmt.Sprint(calls)))
+
+	if calls%2 == 0 {
+		return errors.New("even call")
+	}
+
+	return nil
+}
+
+func (dtc *determinismTestContract) Now(ctx *contractapi.TransactionContext) int64 {
+	return time.Now().UnixNano()
+}
+
+func (dtc *determinismTestContract) Emit(ctx *contractapi.TransactionContext) {
+	calls++
+
+	for i := 0; i < calls; i++ {
+		ctx.GetStub().SetEvent("Emitted", nil)
+	}
+}
+
+func newDeterminismTestChaincode() *contractapi.ContractChaincode {
+	cc := contractapi.CreateNewChaincode(new(determinismTestContract))
+	return &cc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNondeterminismError(t *testing.T) {
+	err := &NondeterminismError{[]string{"some difference", "another difference"}}
+
+	assert.EqualError(t, err, "Invocation was not deterministic: 1. some difference\n2. another difference", "should list differences")
+}
+
+func TestReplay(t *testing.T) {
+	cc := newDeterminismTestChaincode()
+
+	invocation := Invocation{
+		Args:      []string{"Transfer", "alice", "bob"},
+		State:     map[string][]byte{"alice": []byte("100"), "carol": []byte("50")},
+		Transient: map[string][]byte{"secret": []byte("some secret")},
+		Timestamp: time.Unix(1000, 0),
+	}
+
+	// Should invoke against state with args, timestamp and transient
+	result, err := Replay(cc, invocation)
+	assert.Nil(t, err, "should not error replaying")
+	assert.Equal(t, int32(200), result.Response.Status, "should invoke successfully")
+	assert.Equal(t, "1000", string(result.Response.Payload), "should use timestamp of invocation")
+
+	// Should record writes, private writes and events
+	assert.Equal(t, map[string][]byte{"bob": []byte("100"), "alice": nil}, result.Writes, "should record writes and deletes")
+	assert.Equal(t, map[string]map[string][]byte{"collection": {"bob": []byte("some secret")}}, result.PrivateWrites, "should record private writes")
+	assert.Equal(t, []*peer.ChaincodeEvent{{EventName: "Transferred", Payload: []byte("bob")}}, result.Events, "should record events")
+
+	// Should error for invalid timestamp
+	invocation.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = Replay(cc, invocation)
+	assert.Contains(t, err.Error(), "Invalid timestamp for invocation.", "should error for invalid timestamp")
+}
+
+func TestCheckDeterminism(t *testing.T) {
+	var err error
+
+	cc := newDeterminismTestChaincode()
+
+	// Should not error for deterministic invocation
+	err = CheckDeterminism(cc, Invocation{Args: []string{"Transfer", "alice", "bob"}, State: map[string][]byte{"alice": []byte("100")}})
+	assert.Nil(t, err, "should not error for deterministic invocation using timestamp")
+
+	// Should list differences of non deterministic invocation
+	calls = 0
+	err = CheckDeterminism(cc, Invocation{Args: []string{"Count"}})
+	assert.IsType(t, new(NondeterminismError), err, "should return nondeterminism error")
+	assert.Equal(t, []string{
+		"Response status differed. 200 and 500",
+		"Response message differed. \"\" and \"even call\"",
+		"Write to key call1 differed. \"called\" and not written",
+		"Write to key call2 differed. not written and \"called\"",
+		"Write to key count differed. \"1\" and \"2\"",
+		"Write to private key collection/count differed. \"1\" and \"2\"",
+		"Event 0 differed. Counted \"1\" and Counted \"2\"",
+	}, err.(*NondeterminismError).Differences, "should list differences")
+
+	err = CheckDeterminism(cc, Invocation{Args: []string{"Now"}})
+	assert.Contains(t, err.Error(), "1. Response payload differed.", "should flag use of clock")
+
+	calls = 0
+	err = CheckDeterminism(cc, Invocation{Args: []string{"Emit"}})
+	assert.EqualError(t, err, "Invocation was not deterministic: 1. Number of events differed. 1 and 2", "should flag differing events")
+}
@@ -0,0 +1,526 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// maxGenerateDepth the depth of nested objects, arrays and maps after which
+// generated values include only required properties and no items
+const maxGenerateDepth = 3
+
+// maxPatternAttempts the number of strings generated for a schema with a pattern
+// before giving up on finding one that matches it and its lengths
+const maxPatternAttempts = 100
+
+// integerFormatBits the size of the integers of each format
+var integerFormatBits = map[string]float64{"int8": 8, "int16": 16, "int32": 32, "int64": 64}
+
+const generatedCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 -_.:"
+
+// PropertyError is returned when invoking transactions with generated args
+// panics or returns output not valid for the schema of the transaction
+type PropertyError struct {
+	Failures []string
+}
+
+func (pe *PropertyError) Error() string {
+	toReturn := ""
+
+	for i, failure := range pe.Failures {
+		toReturn += strconv.Itoa(i+1) + ". " + failure + "\n"
+	}
+
+	return "Properties of transactions did not hold: " + strings.Trim(toReturn, "\n")
+}
+
+// CheckProperties invokes each transaction of the contracts of the chaincode, other
+// than the system contract, the passed number of times using args generated at
+// random from its parameters in the metadata, each time against empty world state.
+// It returns a PropertyError listing, for the first failing invocation of each
+// transaction, transactions which panicked and transactions which succeeded with
+// output not valid for the return schema in the metadata. Transactions returning
+// errors for the generated args, e.g. as they expect existing state, are not
+// failures. The seed makes the generated args repeatable.
+func CheckProperties(cc *contractapi.ContractChaincode, runs int, seed int64) error {
+	metadata := cc.GetMetadata()
+	r := rand.New(rand.NewSource(seed))
+
+	failures := []string{}
+
+	for _, contractName := range sortedContractNames(metadata) {
+		for _, tx := range metadata.Contracts[contractName].Transactions {
+			failure := checkTransactionProperties(cc, r, contractName+":"+tx.Name, tx, metadata.Components, runs)
+
+			if failure != "" {
+				failures = append(failures, failure)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &PropertyError{failures}
+	}
+
+	return nil
+}
+
+func checkTransactionProperties(cc *contractapi.ContractChaincode, r *rand.Rand, fn string, tx contractapi.TransactionMetadata, components contractapi.ComponentMetadata, runs int) string {
+	for i := 0; i < runs; i++ {
+		args, err := GenerateArgs(r, tx, components)
+
+		if err != nil {
+			return fmt.Sprintf("Could not generate args for transaction %s. %s", fn, err.Error())
+		}
+
+		result, err := Replay(cc, Invocation{Args: append([]string{fn}, args...)})
+
+		if err != nil {
+			return fmt.Sprintf("Could not invoke transaction %s. %s", fn, err.Error())
+		}
+
+		response := result.Response
+		report := new(contractapi.CrashReport)
+
+		if response.Status != 200 && json.Unmarshal(response.Payload, report) == nil && report.Digest != "" {
+			return fmt.Sprintf("Transaction %s panicked for args %q. %s", fn, args, response.Message)
+		}
+
+		if response.Status != 200 || tx.Returns == nil || len(response.Payload) == 0 {
+			continue
+		}
+
+		err = validateOutput(*tx.Returns, components, response.Payload)
+
+		if err != nil {
+			return fmt.Sprintf("Transaction %s returned invalid output %q for args %q. %s", fn, response.Payload, args, err.Error())
+		}
+	}
+
+	return ""
+}
+
+// GenerateArgs returns args for the parameters of the transaction generated at
+// random to be valid for their schemas, using the components for references.
+// Args are formatted as they are passed to transactions, strings as they are and
+// other values as JSON. Values are generated for types, formats, enums, minimums,
+// maximums and lengths. Patterns, other than of decimals, are met by generating
+// strings until one matches so that only simple patterns are supported.
+func GenerateArgs(r *rand.Rand, tx contractapi.TransactionMetadata, components contractapi.ComponentMetadata) ([]string, error) {
+	args := []string{}
+
+	for _, param := range tx.Parameters {
+		value, err := generateValue(r, param.Schema, components, 0)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to generate value for parameter %s. %s", param.Name, err.Error())
+		}
+
+		if str, ok := value.(string); ok {
+			args = append(args, str)
+			continue
+		}
+
+		arg, _ := json.Marshal(value)
+		args = append(args, string(arg))
+	}
+
+	return args, nil
+}
+
+func generateValue(r *rand.Rand, schema spec.Schema, components contractapi.ComponentMetadata, depth int) (interface{}, error) {
+	if len(schema.Enum) > 0 {
+		return schema.Enum[r.Intn(len(schema.Enum))], nil
+	}
+
+	if ref := schema.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		object, ok := components.Schemas[name]
+
+		if !ok {
+			return nil, fmt.Errorf("Could not find component %s", name)
+		}
+
+		return generateObject(r, object, components, depth)
+	}
+
+	if len(schema.Type) == 0 {
+		return generateString(r, schema)
+	}
+
+	switch schema.Type[0] {
+	case "string":
+		return generateString(r, schema)
+	case "boolean":
+		return r.Intn(2) == 1, nil
+	case "integer":
+		return int64(generateNumber(r, schema, true)), nil
+	case "number":
+		// unsigned types are described as numbers with a minimum and maximum
+		return generateNumber(r, schema, schema.Minimum != nil && schema.Maximum != nil), nil
+	case "array":
+		return generateArray(r, schema, components, depth)
+	case "object":
+		return generateMap(r, schema, components, depth)
+	}
+
+	return nil, fmt.Errorf("Unsupported type %s", schema.Type[0])
+}
+
+func generateString(r *rand.Rand, schema spec.Schema) (string, error) {
+	switch schema.Format {
+	case "decimal":
+		return fmt.Sprintf("%d.%d", r.Intn(2000)-1000, r.Intn(100)), nil
+	case contractapi.Base64Format:
+		bytes := make([]byte, r.Intn(16))
+		r.Read(bytes)
+
+		return base64.StdEncoding.EncodeToString(bytes), nil
+	}
+
+	minLength := 0
+	maxLength := 16
+
+	if schema.MinLength != nil {
+		minLength = int(*schema.MinLength)
+	}
+
+	if schema.MaxLength != nil {
+		maxLength = int(*schema.MaxLength)
+	} else if maxLength < minLength {
+		maxLength = minLength + 16
+	}
+
+	if schema.Pattern == "" {
+		return randomString(r, minLength, maxLength), nil
+	}
+
+	pattern, err := regexp.Compile(schema.Pattern)
+
+	if err != nil {
+		return "", fmt.Errorf("Invalid pattern %s. %s", schema.Pattern, err.Error())
+	}
+
+	parsed, _ := syntax.Parse(schema.Pattern, syntax.Perl)
+
+	for i := 0; i < maxPatternAttempts; i++ {
+		builder := new(strings.Builder)
+		generateFromPattern(r, parsed.Simplify(), builder)
+		str := builder.String()
+
+		if pattern.MatchString(str) && len(str) >= minLength && (schema.MaxLength == nil || len(str) <= maxLength) {
+			return str, nil
+		}
+	}
+
+	return "", fmt.Errorf("Could not generate string matching pattern %s", schema.Pattern)
+}
+
+// generateFromPattern writes a string matched by the parsed pattern. Assertions
+// such as word boundaries are ignored so the string may not match.
+func generateFromPattern(r *rand.Rand, re *syntax.Regexp, builder *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		builder.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		builder.WriteRune(generateRune(r, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		builder.WriteByte(generatedCharacters[r.Intn(len(generatedCharacters))])
+	case syntax.OpCapture, syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generateFromPattern(r, sub, builder)
+		}
+	case syntax.OpAlternate:
+		generateFromPattern(r, re.Sub[r.Intn(len(re.Sub))], builder)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := 0, 3
+
+		switch re.Op {
+		case syntax.OpPlus:
+			min = 1
+		case syntax.OpQuest:
+			max = 1
+		case syntax.OpRepeat:
+			min, max = re.Min, re.Max
+
+			if max < 0 {
+				max = min + 3
+			}
+		}
+
+		for i := min + r.Intn(max-min+1); i > 0; i-- {
+			generateFromPattern(r, re.Sub[0], builder)
+		}
+	}
+}
+
+// generateRune returns a rune within the ranges of a character class, preferring
+// the characters used for generated strings
+func generateRune(r *rand.Rand, ranges []rune) rune {
+	if len(ranges) == 0 {
+		return 'a'
+	}
+
+	for i := 0; i < 10; i++ {
+		char := rune(generatedCharacters[r.Intn(len(generatedCharacters))])
+
+		for j := 0; j < len(ranges); j += 2 {
+			if char >= ranges[j] && char <= ranges[j+1] {
+				return char
+			}
+		}
+	}
+
+	j := 2 * r.Intn(len(ranges)/2)
+
+	return ranges[j] + rune(r.Int63n(int64(ranges[j+1]-ranges[j])+1))
+}
+
+func randomString(r *rand.Rand, minLength int, maxLength int) string {
+	length := minLength
+
+	if maxLength > minLength {
+		length += r.Intn(maxLength - minLength + 1)
+	}
+
+	chars := make([]byte, length)
+
+	for i := range chars {
+		chars[i] = generatedCharacters[r.Intn(len(generatedCharacters))]
+	}
+
+	return string(chars)
+}
+
+func generateNumber(r *rand.Rand, schema spec.Schema, integer bool) float64 {
+	minimum := -1000.0
+	maximum := 1000.0
+
+	if bits, ok := integerFormatBits[schema.Format]; ok {
+		minimum = math.Max(minimum, -math.Pow(2, bits-1))
+		maximum = math.Min(maximum, math.Pow(2, bits-1)-1)
+	}
+
+	if schema.Minimum != nil {
+		minimum = *schema.Minimum
+
+		if schema.Maximum == nil {
+			maximum = minimum + 2000
+		}
+	}
+
+	if schema.Maximum != nil {
+		maximum = *schema.Maximum
+
+		if schema.Minimum == nil {
+			minimum = maximum - 2000
+		}
+	}
+
+	// keep values exactly representable by float64
+	maximum = math.Min(maximum, 1<<53)
+	minimum = math.Max(minimum, -(1 << 53))
+
+	if integer {
+		minimum = math.Ceil(minimum)
+		maximum = math.Floor(maximum)
+
+		if schema.ExclusiveMinimum {
+			minimum++
+		}
+
+		if schema.ExclusiveMaximum {
+			maximum--
+		}
+
+		return minimum + math.Floor(r.Float64()*(maximum-minimum+1))
+	}
+
+	value := minimum + r.Float64()*(maximum-minimum)
+
+	if schema.ExclusiveMinimum && value == minimum {
+		value = (minimum + maximum) / 2
+	}
+
+	return value
+}
+
+func generateArray(r *rand.Rand, schema spec.Schema, components contractapi.ComponentMetadata, depth int) ([]interface{}, error) {
+	items := []interface{}{}
+
+	if schema.Items == nil || schema.Items.Schema == nil {
+		return items, nil
+	}
+
+	minItems := 0
+	maxItems := 3
+
+	if schema.MinItems != nil {
+		minItems = int(*schema.MinItems)
+	}
+
+	if schema.MaxItems != nil {
+		maxItems = int(*schema.MaxItems)
+	} else if depth >= maxGenerateDepth {
+		maxItems = minItems
+	} else if maxItems < minItems {
+		maxItems = minItems + 3
+	}
+
+	length := minItems + r.Intn(maxItems-minItems+1)
+
+	for i := 0; i < length; i++ {
+		item, err := generateValue(r, *schema.Items.Schema, components, depth+1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func generateMap(r *rand.Rand, schema spec.Schema, components contractapi.ComponentMetadata, depth int) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema == nil || depth >= maxGenerateDepth {
+		return values, nil
+	}
+
+	for i := r.Intn(4); i > 0; i-- {
+		value, err := generateValue(r, *schema.AdditionalProperties.Schema, components, depth+1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[randomString(r, 1, 8)] = value
+	}
+
+	return values, nil
+}
+
+func generateObject(r *rand.Rand, object contractapi.ObjectMetadata, components contractapi.ComponentMetadata, depth int) (map[string]interface{}, error) {
+	if depth > 2*maxGenerateDepth {
+		return nil, fmt.Errorf("Required properties nested too deeply to generate")
+	}
+
+	values := make(map[string]interface{})
+
+	for _, name := range sortedPropertyNames(object) {
+		if !stringInSlice(name, object.Required) && (depth >= maxGenerateDepth || r.Intn(2) == 0) {
+			continue
+		}
+
+		value, err := generateValue(r, object.Properties[name], components, depth+1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// validateOutput validates the payload returned by a transaction against its
+// return schema. Strings and values of any type are returned as they are and
+// other values as JSON.
+func validateOutput(schema spec.Schema, components contractapi.ComponentMetadata, payload []byte) error {
+	var output interface{}
+
+	if len(schema.Type) == 0 && schema.Ref.String() == "" || len(schema.Type) > 0 && schema.Type[0] == "string" {
+		output = string(payload)
+	} else if err := json.Unmarshal(payload, &output); err != nil {
+		return fmt.Errorf("Output is not valid JSON. %s", err.Error())
+	}
+
+	combined := make(map[string]interface{})
+	combined["components"] = components
+	combined["properties"] = map[string]interface{}{"prop": schema}
+
+	validator, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(combined))
+
+	if err != nil {
+		return fmt.Errorf("Invalid return schema. %s", err.Error())
+	}
+
+	result, _ := validator.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"prop": output}))
+
+	if !result.Valid() {
+		errors := []string{}
+
+		for _, resultError := range result.Errors() {
+			errors = append(errors, strings.Replace(resultError.String(), "prop", "output", 1))
+		}
+
+		return fmt.Errorf("Output is not valid for return schema. %s", strings.Join(errors, ". "))
+	}
+
+	return nil
+}
+
+func sortedContractNames(metadata contractapi.ContractChaincodeMetadata) []string {
+	names := []string{}
+
+	for name := range metadata.Contracts {
+		if name != contractapi.SystemContractName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedPropertyNames(object contractapi.ObjectMetadata) []string {
+	names := []string{}
+
+	for name := range object.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func stringInSlice(a string, list []string) bool {
+	for _, b := range list {
+		if b == a {
+			return true
+		}
+	}
+
+	return false
+}
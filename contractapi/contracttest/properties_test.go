@@ -0,0 +1,184 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type propertyTestAsset struct {
+	ID       string              `json:"id"`
+	Owner    string              `json:"owner,omitempty"`
+	Quantity uint8               `json:"quantity"`
+	Tags     []string            `json:"tags,omitempty"`
+	Parts    []*propertyTestPart `json:"parts,omitempty"`
+}
+
+type propertyTestPart struct {
+	Name   string         `json:"name"`
+	Values map[string]int `json:"values"`
+}
+
+type propertyTestContract struct {
+	contractapi.Contract
+}
+
+func (ptc *propertyTestContract) UsesBasics(str string, tf bool, i int, i8 int8, u uint, u16 uint16, f32 float32, f64 float64) string {
+	return str
+}
+
+func (ptc *propertyTestContract) UsesSpecials(amount contractapi.Decimal, total *big.Int, data []byte, ptr *int32) (*big.Int, error) {
+	return total, nil
+}
+
+func (ptc *propertyTestContract) UsesComplex(asset propertyTestAsset, assets []*propertyTestAsset, counts map[string]uint32) (propertyTestAsset, error) {
+	return asset, nil
+}
+
+func (ptc *propertyTestContract) UsesNothing() {}
+
+type brokenPropertyTestContract struct {
+	contractapi.Contract
+}
+
+func (bptc *brokenPropertyTestContract) Index(items []string, i uint8) string {
+	return items[i]
+}
+
+func (bptc *brokenPropertyTestContract) NewPart(name string) propertyTestPart {
+	return propertyTestPart{Name: name}
+}
+
+func (bptc *brokenPropertyTestContract) Reject(name string) error {
+	return errors.New("rejected")
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestPropertyError(t *testing.T) {
+	err := &PropertyError{[]string{"some failure", "another failure"}}
+
+	assert.EqualError(t, err, "Properties of transactions did not hold: 1. some failure\n2. another failure", "should list failures")
+}
+
+func TestGenerateArgs(t *testing.T) {
+	var args []string
+	var err error
+
+	cc := contractapi.CreateNewChaincode(new(propertyTestContract))
+	metadata := cc.GetMetadata()
+	r := rand.New(rand.NewSource(1))
+
+	// Should generate args accepted by transactions
+	for _, tx := range metadata.Contracts["propertyTestContract"].Transactions {
+		for i := 0; i < 50; i++ {
+			args, err = GenerateArgs(r, tx, metadata.Components)
+			assert.Nil(t, err, "should not error generating args for "+tx.Name)
+			assert.Len(t, args, len(tx.Parameters), "should generate arg for each parameter of "+tx.Name)
+
+			result, _ := Replay(&cc, Invocation{Args: append([]string{tx.Name}, args...)})
+			assert.Equal(t, int32(200), result.Response.Status, "should generate valid args for "+tx.Name+". "+result.Response.Message)
+		}
+	}
+
+	// Should generate values meeting constraints of schema
+	minLength := int64(3)
+	maxLength := int64(5)
+	minimum := 10.0
+	maximum := 12.0
+
+	tx := contractapi.TransactionMetadata{Parameters: []contractapi.ParameterMetadata{
+		{Name: "enum", Schema: *spec.StringProperty().WithEnum("a", "b")},
+		{Name: "length", Schema: spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, MinLength: &minLength, MaxLength: &maxLength}}},
+		{Name: "pattern", Schema: *spec.StringProperty().WithPattern("^(ab|[c-d]x?)[0-9]{2,3}$")},
+		{Name: "range", Schema: spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"integer"}, Minimum: &minimum, Maximum: &maximum, ExclusiveMaximum: true}}},
+		{Name: "any", Schema: spec.Schema{}},
+	}}
+
+	for i := 0; i < 50; i++ {
+		args, err = GenerateArgs(r, tx, contractapi.ComponentMetadata{})
+		assert.Nil(t, err, "should not error generating args for constraints")
+		assert.Contains(t, []string{"a", "b"}, args[0], "should use enum")
+		assert.True(t, len(args[1]) >= 3 && len(args[1]) <= 5, "should use min and max length")
+		assert.Regexp(t, "^(ab|[c-d]x?)[0-9]{2,3}$", args[2], "should match pattern")
+		assert.Contains(t, []string{"10", "11"}, args[3], "should use minimum and exclusive maximum")
+	}
+
+	// Should error when cannot generate value
+	_, err = GenerateArgs(r, contractapi.TransactionMetadata{Parameters: []contractapi.ParameterMetadata{{Name: "param", Schema: *spec.RefSchema("#/components/schemas/Missing")}}}, contractapi.ComponentMetadata{})
+	assert.EqualError(t, err, "Failed to generate value for parameter param. Could not find component Missing", "should error for missing component")
+
+	_, err = GenerateArgs(r, contractapi.TransactionMetadata{Parameters: []contractapi.ParameterMetadata{{Name: "param", Schema: *spec.StringProperty().WithPattern("^[0-9]{20}$").WithMaxLength(2)}}}, contractapi.ComponentMetadata{})
+	assert.EqualError(t, err, "Failed to generate value for parameter param. Could not generate string matching pattern ^[0-9]{20}$", "should error when cannot match pattern and lengths")
+
+	_, err = GenerateArgs(r, contractapi.TransactionMetadata{Parameters: []contractapi.ParameterMetadata{{Name: "param", Schema: *spec.StringProperty().WithPattern("[")}}}, contractapi.ComponentMetadata{})
+	assert.Contains(t, err.Error(), "Failed to generate value for parameter param. Invalid pattern [.", "should error for invalid pattern")
+
+	_, err = GenerateArgs(r, contractapi.TransactionMetadata{Parameters: []contractapi.ParameterMetadata{{Name: "param", Schema: spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"null"}}}}}}, contractapi.ComponentMetadata{})
+	assert.EqualError(t, err, "Failed to generate value for parameter param. Unsupported type null", "should error for unsupported type")
+}
+
+func TestValidateOutput(t *testing.T) {
+	components := contractapi.ComponentMetadata{Schemas: map[string]contractapi.ObjectMetadata{
+		"Part": {Properties: map[string]spec.Schema{"name": *spec.StringProperty()}, Required: []string{"name"}},
+	}}
+
+	// Should validate strings and values of any type as they are
+	assert.Nil(t, validateOutput(*spec.StringProperty(), components, []byte("not json")), "should validate string as is")
+	assert.Nil(t, validateOutput(spec.Schema{}, components, []byte("not json")), "should validate any as is")
+
+	// Should validate other values as JSON
+	assert.Nil(t, validateOutput(*spec.Int64Property(), components, []byte("10")), "should validate number")
+	assert.Nil(t, validateOutput(*spec.RefSchema("#/components/schemas/Part"), components, []byte(`{"name":"wheel"}`)), "should validate reference")
+
+	assert.EqualError(t, validateOutput(*spec.Int64Property(), components, []byte("ten")), "Output is not valid JSON. invalid character 'e' in literal true (expecting 'r')", "should error when not JSON")
+	assert.Contains(t, validateOutput(*spec.RefSchema("#/components/schemas/Part"), components, []byte(`{}`)).Error(), "Output is not valid for return schema. output: name is required", "should error when not valid for schema")
+}
+
+func TestCheckProperties(t *testing.T) {
+	var err error
+
+	// Should not error for transactions that hold
+	cc := contractapi.CreateNewChaincode(new(propertyTestContract))
+	err = CheckProperties(&cc, 20, 1)
+	assert.Nil(t, err, "should not error when properties hold")
+
+	// Should list first failure of each transaction
+	cc = contractapi.CreateNewChaincode(new(brokenPropertyTestContract))
+	err = CheckProperties(&cc, 20, 1)
+	assert.IsType(t, new(PropertyError), err, "should return property error")
+
+	failures := err.(*PropertyError).Failures
+	assert.Len(t, failures, 2, "should list failure of each failing transaction")
+	assert.Regexp(t, `^Transaction brokenPropertyTestContract:Index panicked for args \[.*\]\. Function Index of contract brokenPropertyTestContract panicked\. Crash report [0-9a-f]+$`, failures[0], "should list panic")
+	assert.Regexp(t, `^Transaction brokenPropertyTestContract:NewPart returned invalid output ".*" for args \[.*\]\. Output is not valid for return schema\. output\.values: Invalid type\. Expected: object, given: null$`, failures[1], "should list invalid output")
+
+	// Should be repeatable for seed
+	cc2 := contractapi.CreateNewChaincode(new(brokenPropertyTestContract))
+	assert.Equal(t, err, CheckProperties(&cc2, 20, 1), "should generate same args for seed")
+}
@@ -0,0 +1,231 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Operations recorded as state accesses
+const (
+	GetStateOperation = "GetState"
+	PutStateOperation = "PutState"
+	DelStateOperation = "DelState"
+)
+
+// StateAccess describes a call to the stub by an invocation to read or write a
+// key of the world state. Value is the value read or written, nil when the key
+// did not exist or was deleted.
+type StateAccess struct {
+	Operation string
+	Key       string
+	Value     []byte
+}
+
+// Recording holds an invocation and the accesses it made to the world state so
+// that it can be replayed against a modified version of the chaincode. It can be
+// marshalled to JSON to store alongside tests.
+type Recording struct {
+	Invocation Invocation
+	Response   peer.Response
+	Accesses   []StateAccess
+}
+
+// ReadSet returns the keys read by the invocation in order, without duplicates
+func (r *Recording) ReadSet() []string {
+	return readSet(r.Accesses)
+}
+
+// WriteSet returns the final value written to each key by the invocation, nil
+// for deleted keys
+func (r *Recording) WriteSet() map[string][]byte {
+	return writeSet(r.Accesses)
+}
+
+// ReadWriteSetError is returned when replaying a recording produces a different
+// response, read set or write set to the recorded invocation
+type ReadWriteSetError struct {
+	Differences []string
+}
+
+func (rwse *ReadWriteSetError) Error() string {
+	toReturn := ""
+
+	for i, difference := range rwse.Differences {
+		toReturn += strconv.Itoa(i+1) + ". " + difference + "\n"
+	}
+
+	return "Replay did not match recording: " + strings.Trim(toReturn, "\n")
+}
+
+// Record runs the invocation against a mock stub holding its state and returns a
+// recording of the response and the calls made to GetState, PutState and DelState.
+// Where the invocation sets no timestamp the zero time is used so that replaying
+// the recording uses the same timestamp.
+func Record(chaincode shim.Chaincode, invocation Invocation) (*Recording, error) {
+	result, err := Replay(chaincode, invocation)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recording{invocation, result.Response, result.Accesses}, nil
+}
+
+// CheckRecording replays the invocation of the recording against the chaincode,
+// for example after refactoring it, and returns a ReadWriteSetError listing any
+// differences in the response, the keys read and the values written. The order
+// of reads and writes may differ, only the keys read and final value written
+// to each key are compared.
+func CheckRecording(chaincode shim.Chaincode, recording *Recording) error {
+	result, err := Replay(chaincode, recording.Invocation)
+
+	if err != nil {
+		return err
+	}
+
+	differences := diffResponses(recording.Response, result.Response)
+	differences = append(differences, diffReadSets(readSet(recording.Accesses), readSet(result.Accesses))...)
+	differences = append(differences, diffWrites("key ", writeSet(recording.Accesses), writeSet(result.Accesses))...)
+
+	if len(differences) > 0 {
+		return &ReadWriteSetError{differences}
+	}
+
+	return nil
+}
+
+func (rs *replayStub) GetState(key string) ([]byte, error) {
+	value, err := rs.MockStub.GetState(key)
+
+	if err == nil {
+		rs.accesses = append(rs.accesses, StateAccess{GetStateOperation, key, value})
+	}
+
+	return value, err
+}
+
+func (rs *replayStub) PutState(key string, value []byte) error {
+	err := rs.MockStub.PutState(key, value)
+
+	if err == nil {
+		rs.accesses = append(rs.accesses, StateAccess{PutStateOperation, key, value})
+	}
+
+	return err
+}
+
+func (rs *replayStub) DelState(key string) error {
+	err := rs.MockStub.DelState(key)
+
+	if err == nil {
+		rs.accesses = append(rs.accesses, StateAccess{DelStateOperation, key, nil})
+	}
+
+	return err
+}
+
+func readSet(accesses []StateAccess) []string {
+	keys := []string{}
+	seen := make(map[string]bool)
+
+	for _, access := range accesses {
+		if access.Operation == GetStateOperation && !seen[access.Key] {
+			keys = append(keys, access.Key)
+			seen[access.Key] = true
+		}
+	}
+
+	return keys
+}
+
+func writeSet(accesses []StateAccess) map[string][]byte {
+	writes := make(map[string][]byte)
+
+	for _, access := range accesses {
+		switch access.Operation {
+		case PutStateOperation:
+			writes[access.Key] = access.Value
+		case DelStateOperation:
+			writes[access.Key] = nil
+		}
+	}
+
+	return writes
+}
+
+func diffResponses(first peer.Response, second peer.Response) []string {
+	differences := []string{}
+
+	if first.Status != second.Status {
+		differences = append(differences, fmt.Sprintf("Response status differed. %d and %d", first.Status, second.Status))
+	}
+
+	if first.Message != second.Message {
+		differences = append(differences, fmt.Sprintf("Response message differed. %q and %q", first.Message, second.Message))
+	}
+
+	if !bytes.Equal(first.Payload, second.Payload) {
+		differences = append(differences, fmt.Sprintf("Response payload differed. %q and %q", first.Payload, second.Payload))
+	}
+
+	return differences
+}
+
+func diffReadSets(first []string, second []string) []string {
+	firstKeys := make(map[string]bool)
+	secondKeys := make(map[string]bool)
+	keys := []string{}
+
+	for _, key := range first {
+		firstKeys[key] = true
+		keys = append(keys, key)
+	}
+
+	for _, key := range second {
+		secondKeys[key] = true
+
+		if !firstKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	differences := []string{}
+
+	for _, key := range keys {
+		if firstKeys[key] != secondKeys[key] {
+			differences = append(differences, fmt.Sprintf("Read of key %s differed. %s and %s", key, describeRead(firstKeys[key]), describeRead(secondKeys[key])))
+		}
+	}
+
+	return differences
+}
+
+func describeRead(read bool) string {
+	if read {
+		return "read"
+	}
+
+	return "not read"
+}
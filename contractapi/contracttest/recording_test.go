@@ -0,0 +1,159 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contracttest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type originalTestContract struct {
+	contractapi.Contract
+}
+
+func (otc *originalTestContract) Move(ctx *contractapi.TransactionContext, from string, to string) (string, error) {
+	value, err := ctx.GetStub().GetState(from)
+
+	if err != nil {
+		return "", err
+	}
+
+	ctx.GetStub().PutState(to, []byte("pending"))
+	ctx.GetStub().PutState(to, value)
+	ctx.GetStub().DelState(from)
+
+	return string(value), nil
+}
+
+type refactoredTestContract struct {
+	contractapi.Contract
+}
+
+func (rtc *refactoredTestContract) Move(ctx *contractapi.TransactionContext, from string, to string) (string, error) {
+	value, err := ctx.GetStub().GetState(from)
+
+	if err != nil {
+		return "", err
+	}
+
+	ctx.GetStub().DelState(from)
+	ctx.GetStub().PutState(to, value)
+	ctx.GetStub().GetState(from)
+
+	return string(value), nil
+}
+
+type brokenTestContract struct {
+	contractapi.Contract
+}
+
+func (btc *brokenTestContract) Move(ctx *contractapi.TransactionContext, from string, to string) (string, error) {
+	value, _ := ctx.GetStub().GetState(to)
+
+	ctx.GetStub().PutState(to, append(value, '!'))
+
+	return string(value), nil
+}
+
+func newRecordingTestChaincode(contract contractapi.ContractInterface) *contractapi.ContractChaincode {
+	cc := contractapi.CreateNewChaincode(contract)
+	return &cc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestReadWriteSetError(t *testing.T) {
+	err := &ReadWriteSetError{[]string{"some difference", "another difference"}}
+
+	assert.EqualError(t, err, "Replay did not match recording: 1. some difference\n2. another difference", "should list differences")
+}
+
+func TestRecord(t *testing.T) {
+	invocation := Invocation{
+		Args:  []string{"Move", "alice", "bob"},
+		State: map[string][]byte{"alice": []byte("100")},
+	}
+
+	// Should record response and accesses in order
+	recording, err := Record(newRecordingTestChaincode(new(originalTestContract)), invocation)
+	assert.Nil(t, err, "should not error recording")
+	assert.Equal(t, invocation, recording.Invocation, "should set invocation")
+	assert.Equal(t, "100", string(recording.Response.Payload), "should set response")
+	assert.Equal(t, []StateAccess{
+		{GetStateOperation, "alice", []byte("100")},
+		{PutStateOperation, "bob", []byte("pending")},
+		{PutStateOperation, "bob", []byte("100")},
+		{DelStateOperation, "alice", nil},
+	}, recording.Accesses, "should record accesses")
+
+	// Should return read and write sets
+	assert.Equal(t, []string{"alice"}, recording.ReadSet(), "should return keys read")
+	assert.Equal(t, map[string][]byte{"bob": []byte("100"), "alice": nil}, recording.WriteSet(), "should return final values written")
+
+	// Should error when replay errors
+	invocation.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = Record(newRecordingTestChaincode(new(originalTestContract)), invocation)
+	assert.Contains(t, err.Error(), "Invalid timestamp for invocation.", "should error for invalid timestamp")
+}
+
+func TestCheckRecording(t *testing.T) {
+	var err error
+
+	recording, _ := Record(newRecordingTestChaincode(new(originalTestContract)), Invocation{
+		Args:  []string{"Move", "alice", "bob"},
+		State: map[string][]byte{"alice": []byte("100")},
+	})
+
+	// Should not error for same chaincode
+	err = CheckRecording(newRecordingTestChaincode(new(originalTestContract)), recording)
+	assert.Nil(t, err, "should not error replaying against same chaincode")
+
+	// Should not error when only order of accesses differs
+	err = CheckRecording(newRecordingTestChaincode(new(refactoredTestContract)), recording)
+	assert.Nil(t, err, "should not error when read and write sets match")
+
+	// Should list differences of modified chaincode
+	err = CheckRecording(newRecordingTestChaincode(new(brokenTestContract)), recording)
+	assert.IsType(t, new(ReadWriteSetError), err, "should return read write set error")
+	assert.Equal(t, []string{
+		"Response payload differed. \"100\" and \"\"",
+		"Read of key alice differed. read and not read",
+		"Read of key bob differed. not read and read",
+		"Write to key alice differed. deleted and not written",
+		"Write to key bob differed. \"100\" and \"!\"",
+	}, err.(*ReadWriteSetError).Differences, "should list differences")
+
+	// Should replay recording read from JSON
+	recordingJSON, _ := json.Marshal(recording)
+	unmarshalled := new(Recording)
+	json.Unmarshal(recordingJSON, unmarshalled)
+	err = CheckRecording(newRecordingTestChaincode(new(refactoredTestContract)), unmarshalled)
+	assert.Nil(t, err, "should replay recording read from JSON")
+
+	// Should error when replay errors
+	recording.Invocation.Timestamp = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = CheckRecording(newRecordingTestChaincode(new(originalTestContract)), recording)
+	assert.Contains(t, err.Error(), "Invalid timestamp for invocation.", "should error for invalid timestamp")
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConversionError is returned when an arg passed to a transaction cannot be
+// converted to the type of its parameter by the serializer. It describes the
+// parameter and the arg so that the failure can be traced from the peer logs.
+// The function and parameter names are taken from the metadata and are blank
+// when not known.
+type ConversionError struct {
+	Function  string
+	Index     int
+	Parameter string
+	Type      string
+	Value     string
+	Err       error
+}
+
+func newConversionError(transaction *TransactionMetadata, index int, t reflect.Type, value string, err error) *ConversionError {
+	ce := &ConversionError{Index: index, Type: t.String(), Value: value, Err: err}
+
+	if transaction != nil {
+		ce.Function = transaction.Name
+
+		if index < len(transaction.Parameters) {
+			ce.Parameter = transaction.Parameters[index].Name
+		}
+	}
+
+	return ce
+}
+
+func (ce *ConversionError) Error() string {
+	parameter := fmt.Sprintf("parameter %d", ce.Index)
+
+	if ce.Parameter != "" {
+		parameter += fmt.Sprintf(" (%s)", ce.Parameter)
+	}
+
+	if ce.Function != "" {
+		parameter += " of function " + ce.Function
+	}
+
+	return fmt.Sprintf("Failed to convert arg for %s. Expected type %s, received %q. %s", parameter, ce.Type, ce.Value, ce.Err.Error())
+}
+
+// Unwrap returns the error returned by the serializer
+func (ce *ConversionError) Unwrap() error {
+	return ce.Err
+}
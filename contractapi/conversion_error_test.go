@@ -0,0 +1,75 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestNewConversionError(t *testing.T) {
+	var ce *ConversionError
+
+	err := errors.New("some error")
+
+	// Should describe parameter by index without metadata
+	ce = newConversionError(nil, 1, reflect.TypeOf(0.0), "101.23x", err)
+	assert.Equal(t, &ConversionError{Index: 1, Type: "float64", Value: "101.23x", Err: err}, ce, "should create error without names")
+
+	// Should name function and parameter from metadata
+	transaction := TransactionMetadata{Name: "Deposit", Parameters: []ParameterMetadata{{Name: "account"}, {Name: "amount"}}}
+	ce = newConversionError(&transaction, 1, reflect.TypeOf(0.0), "101.23x", err)
+	assert.Equal(t, &ConversionError{Function: "Deposit", Index: 1, Parameter: "amount", Type: "float64", Value: "101.23x", Err: err}, ce, "should create error with names from metadata")
+
+	// Should not name parameter missing from metadata
+	ce = newConversionError(&transaction, 2, reflect.TypeOf(0.0), "101.23x", err)
+	assert.Equal(t, "", ce.Parameter, "should not name parameter missing from metadata")
+}
+
+func TestConversionErrorError(t *testing.T) {
+	ce := &ConversionError{Index: 1, Type: "float64", Value: "101.23x", Err: errors.New("some error")}
+	assert.Equal(t, "Failed to convert arg for parameter 1. Expected type float64, received \"101.23x\". some error", ce.Error(), "should describe parameter by index")
+
+	ce.Parameter = "amount"
+	assert.Equal(t, "Failed to convert arg for parameter 1 (amount). Expected type float64, received \"101.23x\". some error", ce.Error(), "should include parameter name")
+
+	ce.Function = "Deposit"
+	assert.Equal(t, "Failed to convert arg for parameter 1 (amount) of function Deposit. Expected type float64, received \"101.23x\". some error", ce.Error(), "should include function name")
+}
+
+func TestConversionErrorUnwrap(t *testing.T) {
+	err := errors.New("some error")
+	ce := &ConversionError{Err: err}
+
+	assert.Equal(t, err, ce.Unwrap(), "should return serializer error")
+	assert.True(t, errors.Is(ce, err), "should wrap serializer error")
+}
+
+func TestInvokeConversionError(t *testing.T) {
+	mc := new(myContract)
+	mc.ConfigureFunction("UsesArray").SetParameterNames("args")
+
+	cc := convertC2CC(mc)
+
+	// Should name function and parameter from metadata in error
+	callContractFunctionAndCheckError(t, cc, []string{"UsesArray", "[1,2]"}, invokeType, "Failed to convert arg for parameter 0 (args) of function UsesArray. Expected type [1]string, received \"[1,2]\". Value [1,2] was not passed in expected format [1]string")
+}
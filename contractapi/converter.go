@@ -0,0 +1,124 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConvertTag the key in a parameter's tag naming the converter to apply to
+// the arg passed for the parameter e.g. `convert:"csv"`
+const ConvertTag = "convert"
+
+// CSVConverterName the name of the converter that splits a comma separated
+// arg into a JSON array for use with array and slice parameters
+const CSVConverterName = "csv"
+
+// ArgumentConverter converts an arg passed for a parameter of the given type from
+// the format sent by a client to the format expected by the serializer
+type ArgumentConverter func(arg string, t reflect.Type) (string, error)
+
+var defaultConverters = map[string]ArgumentConverter{
+	CSVConverterName: convertCSV,
+}
+
+// AddConverter adds a converter that can be applied to args of contract functions
+// by naming it in the convert tag of a parameter (see FunctionConfig.SetParameterTags).
+// Replaces any existing converter of the same name, including the built in csv
+// converter.
+func (cc *ContractChaincode) AddConverter(name string, converter ArgumentConverter) {
+	if cc.converters == nil {
+		cc.converters = make(map[string]ArgumentConverter)
+	}
+
+	cc.converters[name] = converter
+}
+
+func (cc *ContractChaincode) getConverter(name string) (ArgumentConverter, bool) {
+	if converter, ok := cc.converters[name]; ok {
+		return converter, true
+	}
+
+	converter, ok := defaultConverters[name]
+
+	return converter, ok
+}
+
+// convertArgs applies the converters named in the parameter tags of a function
+// to the args passed for those parameters
+func (cc *ContractChaincode) convertArgs(fn *contractFunction, config *FunctionConfig, params []string) ([]string, error) {
+	if config == nil || len(config.parameterTags) == 0 {
+		return params, nil
+	}
+
+	converted := make([]string, len(params))
+	copy(converted, params)
+
+	for i, tag := range config.parameterTags {
+		name := reflect.StructTag(tag).Get(ConvertTag)
+
+		if name == "" || i >= len(converted) || i >= len(fn.params.fields) {
+			continue
+		}
+
+		converter, ok := cc.getConverter(name)
+
+		if !ok {
+			return nil, fmt.Errorf("Converter %s not found for parameter %d", name, i)
+		}
+
+		arg, err := converter(converted[i], fn.params.fields[i])
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert parameter %d using converter %s. %s", i, name, err.Error())
+		}
+
+		converted[i] = arg
+	}
+
+	return converted, nil
+}
+
+func convertCSV(arg string, t reflect.Type) (string, error) {
+	if t.Kind() != reflect.Array && t.Kind() != reflect.Slice {
+		return "", fmt.Errorf("Type %s is not valid. Expected an array or slice", t.String())
+	}
+
+	elements := []json.RawMessage{}
+
+	if arg != "" {
+		for _, element := range strings.Split(arg, ",") {
+			element = strings.TrimSpace(element)
+
+			if t.Elem().Kind() == reflect.String {
+				bytes, _ := json.Marshal(element)
+				element = string(bytes)
+			}
+
+			elements = append(elements, json.RawMessage(element))
+		}
+	}
+
+	bytes, err := json.Marshal(elements)
+
+	if err != nil {
+		return "", fmt.Errorf("Value %s is not a valid comma separated list of %s", arg, t.Elem().String())
+	}
+
+	return string(bytes), nil
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type converterTestContract struct {
+	Contract
+}
+
+func (ctc *converterTestContract) Join(names []string, counts []int) string {
+	return fmt.Sprintf("%v %v", names, counts)
+}
+
+func (ctc *converterTestContract) Upper(name string) string {
+	return name
+}
+
+func upperConverter(arg string, t reflect.Type) (string, error) {
+	return strings.ToUpper(arg), nil
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestAddConverter(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should add converter
+	cc.AddConverter("upper", upperConverter)
+	assert.Len(t, cc.converters, 1, "should add converter")
+	converted, _ := cc.converters["upper"]("value", nil)
+	assert.Equal(t, "VALUE", converted, "should add converter passed")
+}
+
+func TestGetConverter(t *testing.T) {
+	var converter ArgumentConverter
+	var ok bool
+
+	cc := ContractChaincode{}
+
+	// Should return built in converter
+	converter, ok = cc.getConverter(CSVConverterName)
+	assert.True(t, ok, "should find csv converter")
+	assert.NotNil(t, converter, "should return csv converter")
+
+	// Should return false for unknown converter
+	_, ok = cc.getConverter("upper")
+	assert.False(t, ok, "should not find unknown converter")
+
+	// Should return added converter, overriding built in
+	cc.AddConverter(CSVConverterName, upperConverter)
+	converter, ok = cc.getConverter(CSVConverterName)
+	assert.True(t, ok, "should find added converter")
+	converted, _ := converter("value", nil)
+	assert.Equal(t, "VALUE", converted, "should return added converter")
+}
+
+func TestConvertArgs(t *testing.T) {
+	var params []string
+	var err error
+
+	cc := ContractChaincode{}
+	cc.AddConverter("upper", upperConverter)
+	cc.AddConverter("bad", func(arg string, t reflect.Type) (string, error) {
+		return "", errors.New("some error")
+	})
+
+	fn := &contractFunction{params: contractFunctionParams{fields: []reflect.Type{reflect.TypeOf(""), reflect.TypeOf([]string{})}}}
+
+	// Should return params unchanged when no config or tags
+	params, err = cc.convertArgs(fn, nil, []string{"a", "b,c"})
+	assert.Nil(t, err, "should not error without config")
+	assert.Equal(t, []string{"a", "b,c"}, params, "should not change params without config")
+
+	params, err = cc.convertArgs(fn, new(FunctionConfig), []string{"a", "b,c"})
+	assert.Nil(t, err, "should not error without tags")
+	assert.Equal(t, []string{"a", "b,c"}, params, "should not change params without tags")
+
+	// Should apply converters named in tags
+	original := []string{"a", "b,c"}
+	params, err = cc.convertArgs(fn, new(FunctionConfig).SetParameterTags(`convert:"upper"`, `validate:"required" convert:"csv"`), original)
+	assert.Nil(t, err, "should not error for known converters")
+	assert.Equal(t, []string{"A", `["b","c"]`}, params, "should convert params")
+	assert.Equal(t, []string{"a", "b,c"}, original, "should not change params passed")
+
+	// Should ignore blank tags and missing params
+	params, err = cc.convertArgs(fn, new(FunctionConfig).SetParameterTags("", `convert:"csv"`), []string{"a"})
+	assert.Nil(t, err, "should not error when param missing")
+	assert.Equal(t, []string{"a"}, params, "should leave params without converter")
+
+	// Should error for unknown converter
+	_, err = cc.convertArgs(fn, new(FunctionConfig).SetParameterTags(`convert:"missing"`, ""), []string{"a", "b"})
+	assert.EqualError(t, err, "Converter missing not found for parameter 0", "should error for unknown converter")
+
+	// Should error when converter errors
+	_, err = cc.convertArgs(fn, new(FunctionConfig).SetParameterTags("", `convert:"bad"`), []string{"a", "b"})
+	assert.EqualError(t, err, "Failed to convert parameter 1 using converter bad. some error", "should error when converter fails")
+}
+
+func TestConvertCSV(t *testing.T) {
+	var converted string
+	var err error
+
+	// Should convert to JSON array of strings
+	converted, err = convertCSV("a, b ,c", reflect.TypeOf([]string{}))
+	assert.Nil(t, err, "should not error for string slice")
+	assert.Equal(t, `["a","b","c"]`, converted, "should quote and trim strings")
+
+	// Should convert to JSON array of other types
+	converted, err = convertCSV("1,2", reflect.TypeOf([2]int{}))
+	assert.Nil(t, err, "should not error for int array")
+	assert.Equal(t, `[1,2]`, converted, "should not quote other types")
+
+	// Should convert empty arg to empty array
+	converted, err = convertCSV("", reflect.TypeOf([]string{}))
+	assert.Nil(t, err, "should not error for empty arg")
+	assert.Equal(t, `[]`, converted, "should return empty array")
+
+	// Should error for types other than arrays and slices
+	_, err = convertCSV("a,b", reflect.TypeOf(""))
+	assert.EqualError(t, err, "Type string is not valid. Expected an array or slice", "should error for string")
+
+	// Should error when elements are not valid JSON
+	_, err = convertCSV("1,two", reflect.TypeOf([]int{}))
+	assert.EqualError(t, err, "Value 1,two is not a valid comma separated list of int", "should error for invalid element")
+}
+
+func TestInvokeWithConverters(t *testing.T) {
+	ctc := new(converterTestContract)
+	ctc.ConfigureFunction("Join").SetParameterTags(`convert:"csv"`, `convert:"csv"`)
+	ctc.ConfigureFunction("Upper").SetParameterTags(`convert:"upper"`)
+	cc := convertC2CC(ctc)
+	cc.AddConverter("upper", upperConverter)
+
+	mockStub := shimtest.NewMockStub("converterTest", &cc)
+
+	// Should convert args before calling function
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Join"), []byte("a,b"), []byte("1,2,3")})
+	assert.Equal(t, "[a b] [1 2 3]", string(response.Payload), "should convert csv args")
+
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Upper"), []byte("name")})
+	assert.Equal(t, "NAME", string(response.Payload), "should convert using added converter")
+
+	// Should return error when conversion fails
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Join"), []byte("a,b"), []byte("1,x")})
+	assert.Equal(t, "Failed to convert parameter 1 using converter csv. Value 1,x is not a valid comma separated list of int", response.Message, "should return conversion error")
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// SetTransactionTimeout sets the time each transaction is allowed to run for.
+// Iterators returned by the stub for range scans, queries and history check the
+// deadline between results and return an error once it has passed so that long
+// running scans are aborted. A transaction that completes after its deadline
+// returns an error. A timeout of zero, the default, disables the deadline.
+// Functions can set their own timeout (see FunctionConfig.SetTimeout).
+func (cc *ContractChaincode) SetTransactionTimeout(timeout time.Duration) {
+	cc.transactionTimeout = timeout
+}
+
+// SetTimeout sets the time transactions of the function are allowed to run for,
+// in place of the timeout set for the chaincode (see SetTransactionTimeout). A
+// timeout of zero, the default, uses the timeout of the chaincode.
+func (fc *FunctionConfig) SetTimeout(timeout time.Duration) *FunctionConfig {
+	fc.timeout = timeout
+	return fc
+}
+
+// getTimeout returns the time the transaction of the function is allowed to run
+// for, zero if it has no deadline
+func (cc *ContractChaincode) getTimeout(config *FunctionConfig) time.Duration {
+	if config != nil && config.timeout > 0 {
+		return config.timeout
+	}
+
+	return cc.transactionTimeout
+}
+
+// GetDeadline returns the time by which the transaction must complete and
+// whether the chaincode sets a deadline
+func (ctx *TransactionContext) GetDeadline() (time.Time, bool) {
+	return ctx.details.deadline, !ctx.details.deadline.IsZero()
+}
+
+// Context returns a context that is done when the deadline of the transaction
+// passes or the transaction completes, for passing to calls that should be
+// cancelled with the transaction e.g. to external services. If the transaction
+// has no deadline the context is only done when the transaction completes.
+func (ctx *TransactionContext) Context() context.Context {
+	if ctx.details.context == nil {
+		return context.Background()
+	}
+
+	return ctx.details.context
+}
+
+func checkDeadline(deadline time.Time) error {
+	if time.Now().After(deadline) {
+		return fmt.Errorf("Transaction deadline of %s exceeded", deadline.Format(time.RFC3339Nano))
+	}
+
+	return nil
+}
+
+// deadlineStub wraps the iterators returned by the stub so that they check the
+// deadline of the transaction between results
+type deadlineStub struct {
+	shim.ChaincodeStubInterface
+	deadline time.Time
+}
+
+func (ds *deadlineStub) wrap(iterator shim.StateQueryIteratorInterface, err error) (shim.StateQueryIteratorInterface, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadlineStateIterator{iterator, ds.deadline}, nil
+}
+
+func (ds *deadlineStub) wrapWithMetadata(iterator shim.StateQueryIteratorInterface, metadata *peer.QueryResponseMetadata, err error) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &deadlineStateIterator{iterator, ds.deadline}, metadata, nil
+}
+
+func (ds *deadlineStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetStateByRange(startKey, endKey))
+}
+
+func (ds *deadlineStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	return ds.wrapWithMetadata(ds.ChaincodeStubInterface.GetStateByRangeWithPagination(startKey, endKey, pageSize, bookmark))
+}
+
+func (ds *deadlineStub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetStateByPartialCompositeKey(objectType, keys))
+}
+
+func (ds *deadlineStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	return ds.wrapWithMetadata(ds.ChaincodeStubInterface.GetStateByPartialCompositeKeyWithPagination(objectType, keys, pageSize, bookmark))
+}
+
+func (ds *deadlineStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetQueryResult(query))
+}
+
+func (ds *deadlineStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	return ds.wrapWithMetadata(ds.ChaincodeStubInterface.GetQueryResultWithPagination(query, pageSize, bookmark))
+}
+
+func (ds *deadlineStub) GetPrivateDataByRange(collection, startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetPrivateDataByRange(collection, startKey, endKey))
+}
+
+func (ds *deadlineStub) GetPrivateDataByPartialCompositeKey(collection, objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetPrivateDataByPartialCompositeKey(collection, objectType, keys))
+}
+
+func (ds *deadlineStub) GetPrivateDataQueryResult(collection, query string) (shim.StateQueryIteratorInterface, error) {
+	return ds.wrap(ds.ChaincodeStubInterface.GetPrivateDataQueryResult(collection, query))
+}
+
+func (ds *deadlineStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	iterator, err := ds.ChaincodeStubInterface.GetHistoryForKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadlineHistoryIterator{iterator, ds.deadline}, nil
+}
+
+type deadlineStateIterator struct {
+	shim.StateQueryIteratorInterface
+	deadline time.Time
+}
+
+func (dsi *deadlineStateIterator) Next() (*queryresult.KV, error) {
+	if err := checkDeadline(dsi.deadline); err != nil {
+		return nil, err
+	}
+
+	return dsi.StateQueryIteratorInterface.Next()
+}
+
+type deadlineHistoryIterator struct {
+	shim.HistoryQueryIteratorInterface
+	deadline time.Time
+}
+
+func (dhi *deadlineHistoryIterator) Next() (*queryresult.KeyModification, error) {
+	if err := checkDeadline(dhi.deadline); err != nil {
+		return nil, err
+	}
+
+	return dhi.HistoryQueryIteratorInterface.Next()
+}
@@ -0,0 +1,244 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type deadlineTestContract struct {
+	Contract
+}
+
+func (dtc *deadlineTestContract) Scan(ctx *TransactionContext) (int, error) {
+	ctx.GetStub().PutState("key1", []byte("value1"))
+
+	iterator, err := ctx.GetStub().GetStateByRange("", "")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer iterator.Close()
+
+	count := 0
+
+	for iterator.HasNext() {
+		_, err := iterator.Next()
+
+		if err != nil {
+			return 0, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+func (dtc *deadlineTestContract) HasDeadline(ctx *TransactionContext) bool {
+	_, ok := ctx.GetDeadline()
+	return ok
+}
+
+func (dtc *deadlineTestContract) ContextDeadline(ctx *TransactionContext) bool {
+	_, ok := ctx.Context().Deadline()
+	return ok
+}
+
+func (dtc *deadlineTestContract) Wait(ctx *TransactionContext) error {
+	<-ctx.Context().Done()
+	return ctx.Context().Err()
+}
+
+func newDeadlineTestStub(deadline time.Time) *deadlineStub {
+	stub := shimtest.NewMockStub("deadlineTest", nil)
+	stub.MockTransactionStart(standardTxID)
+	stub.PutState("key1", []byte("value1"))
+	key, _ := stub.CreateCompositeKey("asset", []string{"key2"})
+	stub.PutState(key, []byte("value2"))
+
+	return &deadlineStub{stub, deadline}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetTransactionTimeout(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.SetTransactionTimeout(time.Second)
+
+	assert.Equal(t, time.Second, cc.transactionTimeout, "should set the transaction timeout")
+}
+
+func TestSetTimeout(t *testing.T) {
+	fc := new(FunctionConfig)
+
+	assert.Equal(t, fc, fc.SetTimeout(time.Second), "should return config")
+	assert.Equal(t, time.Second, fc.timeout, "should set the timeout")
+}
+
+func TestGetTimeout(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should return no timeout when none set
+	assert.Equal(t, time.Duration(0), cc.getTimeout(nil), "should return zero when no timeout")
+
+	// Should return timeout of chaincode
+	cc.SetTransactionTimeout(time.Hour)
+	assert.Equal(t, time.Hour, cc.getTimeout(nil), "should return timeout of chaincode without config")
+	assert.Equal(t, time.Hour, cc.getTimeout(new(FunctionConfig)), "should return timeout of chaincode when function sets none")
+
+	// Should return timeout of function
+	assert.Equal(t, time.Second, cc.getTimeout(new(FunctionConfig).SetTimeout(time.Second)), "should return timeout of function")
+	assert.Equal(t, 2*time.Hour, cc.getTimeout(new(FunctionConfig).SetTimeout(2*time.Hour)), "should return longer timeout of function")
+}
+
+func TestContext(t *testing.T) {
+	ctx := TransactionContext{}
+
+	// Should return background context when not set
+	assert.Equal(t, context.Background(), ctx.Context(), "should return background context when not set")
+
+	// Should return context of transaction
+	txContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx.setTransactionDetails(transactionDetails{context: txContext})
+	assert.Equal(t, txContext, ctx.Context(), "should return context of transaction")
+}
+
+func TestGetDeadline(t *testing.T) {
+	var deadline time.Time
+	var ok bool
+
+	ctx := TransactionContext{}
+
+	// Should return no deadline when not set
+	deadline, ok = ctx.GetDeadline()
+	assert.False(t, ok, "should return false when no deadline")
+	assert.True(t, deadline.IsZero(), "should return zero time when no deadline")
+
+	// Should return deadline when set
+	expected := time.Now()
+	ctx.setTransactionDetails(transactionDetails{deadline: expected})
+	deadline, ok = ctx.GetDeadline()
+	assert.True(t, ok, "should return true when deadline set")
+	assert.Equal(t, expected, deadline, "should return deadline")
+}
+
+func TestDeadlineStub(t *testing.T) {
+	var err error
+
+	// Should return results when deadline not passed
+	stub := newDeadlineTestStub(time.Now().Add(time.Hour))
+
+	rangeIterator, err := stub.GetStateByRange("key1", "key2")
+	assert.Nil(t, err, "should not error getting range")
+	assert.IsType(t, new(deadlineStateIterator), rangeIterator, "should wrap range iterator")
+	kv, err := rangeIterator.Next()
+	assert.Nil(t, err, "should not error before deadline")
+	assert.Equal(t, "key1", kv.Key, "should return result before deadline")
+
+	partialIterator, err := stub.GetStateByPartialCompositeKey("asset", []string{})
+	assert.Nil(t, err, "should not error getting partial composite key")
+	assert.IsType(t, new(deadlineStateIterator), partialIterator, "should wrap partial composite key iterator")
+	_, err = partialIterator.Next()
+	assert.Nil(t, err, "should not error before deadline")
+
+	// Should return errors of stub
+	_, err = stub.GetQueryResult("some query")
+	assert.NotNil(t, err, "should return query error of stub")
+	_, err = stub.GetHistoryForKey("key1")
+	assert.NotNil(t, err, "should return history error of stub")
+	_, _, err = stub.wrapWithMetadata(nil, nil, errors.New("some paginated error"))
+	assert.EqualError(t, err, "some paginated error", "should return paginated error of stub")
+
+	// Should error once deadline passed
+	stub = newDeadlineTestStub(time.Now().Add(-time.Second))
+
+	rangeIterator, _ = stub.GetStateByRange("key1", "key2")
+	assert.True(t, rangeIterator.HasNext(), "should still report results")
+	_, err = rangeIterator.Next()
+	assert.Contains(t, err.Error(), "Transaction deadline of", "should error after deadline")
+	assert.True(t, strings.HasSuffix(err.Error(), "exceeded"), "should error after deadline")
+}
+
+func TestDeadlineHistoryIterator(t *testing.T) {
+	iterator := &deadlineHistoryIterator{nil, time.Now().Add(-time.Second)}
+
+	_, err := iterator.Next()
+	assert.Contains(t, err.Error(), "Transaction deadline of", "should error after deadline")
+}
+
+func TestInvokeWithTimeout(t *testing.T) {
+	cc := convertC2CC(new(deadlineTestContract))
+
+	// Should not set deadline when no timeout
+	callContractFunctionAndCheckSuccess(t, cc, []string{"HasDeadline"}, invokeType, "false")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Scan"}, invokeType, "1")
+
+	// Should set deadline when timeout set
+	cc.SetTransactionTimeout(time.Hour)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"HasDeadline"}, invokeType, "true")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Scan"}, invokeType, "1")
+
+	// Should abort scans once deadline passed
+	cc.SetTransactionTimeout(time.Nanosecond)
+	mockStub := shimtest.NewMockStub("deadlineTest", &cc)
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Scan")})
+	assert.Contains(t, response.Message, "Transaction deadline of", "should abort scan after deadline")
+
+	// Should error when transaction completes after deadline
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("HasDeadline")})
+	assert.Contains(t, response.Message, "Transaction deadline of", "should error when completing after deadline")
+}
+
+func TestInvokeWithFunctionTimeout(t *testing.T) {
+	dtc := new(deadlineTestContract)
+	dtc.ConfigureFunction("HasDeadline").SetTimeout(time.Hour)
+	dtc.ConfigureFunction("ContextDeadline").SetTimeout(time.Hour)
+	dtc.ConfigureFunction("Wait").SetTimeout(time.Millisecond)
+
+	cc := convertC2CC(dtc)
+
+	// Should set deadline of function with timeout
+	callContractFunctionAndCheckSuccess(t, cc, []string{"HasDeadline"}, invokeType, "true")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ContextDeadline"}, invokeType, "true")
+
+	// Should not set deadline of function without timeout
+	cc.contracts["deadlineTestContract"].functionConfigs["ContextDeadline"].SetTimeout(0)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ContextDeadline"}, invokeType, "false")
+
+	// Should use timeout of function over that of chaincode
+	cc.SetTransactionTimeout(time.Nanosecond)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"HasDeadline"}, invokeType, "true")
+
+	// Should end context of transaction at deadline
+	mockStub := shimtest.NewMockStub("deadlineTest", &cc)
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Wait")})
+	assert.Equal(t, "context deadline exceeded", response.Message, "should end context at deadline")
+}
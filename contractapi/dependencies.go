@@ -0,0 +1,94 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// Dependency describes a chaincode, built using the contractapi, that a contract
+// calls using InvokeChaincode
+type Dependency struct {
+	// Chaincode the name of the chaincode
+	Chaincode string
+	// Channel the channel of the chaincode. Blank for the channel of the transaction
+	Channel string
+	// MetadataHash the expected hex encoded SHA256 hash of the metadata returned
+	// by the chaincode's GetMetadata transaction, as shown in its startup
+	// diagnostics. Blank to not check the metadata.
+	MetadataHash string
+}
+
+func (d Dependency) String() string {
+	if d.Channel == "" {
+		return d.Chaincode
+	}
+
+	return fmt.Sprintf("%s on channel %s", d.Chaincode, d.Channel)
+}
+
+func verifyDependency(stub shim.ChaincodeStubInterface, dependency Dependency) error {
+	response := stub.InvokeChaincode(dependency.Chaincode, [][]byte{[]byte(SystemContractName + ":GetMetadata")}, dependency.Channel)
+
+	if response.Status != shim.OK {
+		return fmt.Errorf("Dependency %s could not be verified. Calling its GetMetadata transaction returned status %d: %s. Check the chaincode is deployed and built using the contractapi", dependency.String(), response.Status, response.Message)
+	}
+
+	if dependency.MetadataHash != "" {
+		hash := sha256.Sum256(response.Payload)
+		actual := hex.EncodeToString(hash[:])
+
+		if actual != dependency.MetadataHash {
+			return fmt.Errorf("Dependency %s has metadata hash %s. Expected %s. Check the expected version of the chaincode is deployed", dependency.String(), actual, dependency.MetadataHash)
+		}
+	}
+
+	return nil
+}
+
+func (sc *systemContract) setDependencies(dependencies map[string][]Dependency) {
+	sc.dependencies = dependencies
+}
+
+// VerifyDependencies checks the dependencies of each contract of the chaincode
+// the system contract is part of (see Contract.AddDependency), returning an
+// error for the first that fails. Dependencies are checked by calling their
+// GetMetadata transaction so this should be submitted after the chaincode or
+// its dependencies are deployed rather than checked during other transactions,
+// whose endorsements would then depend on when each peer last checked.
+func (sc *systemContract) VerifyDependencies(ctx TransactionContextInterface) error {
+	names := []string{}
+
+	for name := range sc.dependencies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, dependency := range sc.dependencies[name] {
+			if err := verifyDependency(ctx.GetStub(), dependency); err != nil {
+				return fmt.Errorf("Contract %s failed to verify dependencies. %s", name, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type dependencyTestStub struct {
+	*shimtest.MockStub
+	responses map[string]peer.Response
+	calls     []string
+	function  string
+}
+
+func (dts *dependencyTestStub) GetArgs() [][]byte {
+	return [][]byte{[]byte(dts.function)}
+}
+
+func (dts *dependencyTestStub) GetFunctionAndParameters() (string, []string) {
+	return dts.function, []string{}
+}
+
+func (dts *dependencyTestStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) peer.Response {
+	dts.calls = append(dts.calls, chaincodeName+"/"+channel+"/"+string(args[0]))
+
+	if response, ok := dts.responses[chaincodeName+"/"+channel]; ok {
+		return response
+	}
+
+	return shim.Error("chaincode not found")
+}
+
+func newDependencyTestStub() *dependencyTestStub {
+	stub := new(dependencyTestStub)
+	stub.MockStub = shimtest.NewMockStub("dependencyTest", nil)
+	stub.responses = map[string]peer.Response{
+		"cc1/":    shim.Success([]byte("cc1 metadata")),
+		"cc2/ch1": shim.Success([]byte("cc2 metadata")),
+	}
+
+	return stub
+}
+
+func hashOf(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestDependencyString(t *testing.T) {
+	assert.Equal(t, "cc1", Dependency{Chaincode: "cc1"}.String(), "should return chaincode name")
+	assert.Equal(t, "cc1 on channel ch1", Dependency{Chaincode: "cc1", Channel: "ch1"}.String(), "should include channel")
+}
+
+func TestVerifyDependency(t *testing.T) {
+	var err error
+
+	stub := newDependencyTestStub()
+
+	// Should call GetMetadata of dependency
+	err = verifyDependency(stub, Dependency{Chaincode: "cc2", Channel: "ch1"})
+	assert.Nil(t, err, "should not error when dependency exists")
+	assert.Equal(t, []string{"cc2/ch1/" + SystemContractName + ":GetMetadata"}, stub.calls, "should call GetMetadata")
+
+	// Should check metadata hash
+	err = verifyDependency(stub, Dependency{Chaincode: "cc1", MetadataHash: hashOf("cc1 metadata")})
+	assert.Nil(t, err, "should not error when hash matches")
+
+	err = verifyDependency(stub, Dependency{Chaincode: "cc1", MetadataHash: "somehash"})
+	assert.EqualError(t, err, "Dependency cc1 has metadata hash "+hashOf("cc1 metadata")+". Expected somehash. Check the expected version of the chaincode is deployed", "should error when hash differs")
+
+	// Should error when dependency missing
+	err = verifyDependency(stub, Dependency{Chaincode: "cc1", Channel: "ch2"})
+	assert.EqualError(t, err, "Dependency cc1 on channel ch2 could not be verified. Calling its GetMetadata transaction returned status 500: chaincode not found. Check the chaincode is deployed and built using the contractapi", "should error when dependency missing")
+}
+
+func TestVerifyDependencies(t *testing.T) {
+	var err error
+	var stub *dependencyTestStub
+
+	mc := new(myContract)
+	mc.AddDependency(Dependency{Chaincode: "cc1"})
+	mc.AddDependency(Dependency{Chaincode: "cc2", Channel: "ch1"})
+	cc := convertC2CC(mc)
+	sysC := cc.contracts[SystemContractName].receiver.Interface().(*systemContract)
+
+	assert.Equal(t, []Dependency{{Chaincode: "cc1"}, {Chaincode: "cc2", Channel: "ch1"}}, cc.contracts["myContract"].dependencies, "should store dependencies of contract")
+	assert.Equal(t, map[string][]Dependency{"myContract": {{Chaincode: "cc1"}, {Chaincode: "cc2", Channel: "ch1"}}}, sysC.dependencies, "should pass dependencies to system contract")
+
+	// Should verify each dependency
+	stub = newDependencyTestStub()
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	err = sysC.VerifyDependencies(ctx)
+	assert.Nil(t, err, "should not error when dependencies exist")
+	assert.Len(t, stub.calls, 2, "should verify each dependency")
+
+	// Should verify every time
+	stub.calls = nil
+	err = sysC.VerifyDependencies(ctx)
+	assert.Nil(t, err, "should not error when verified again")
+	assert.Len(t, stub.calls, 2, "should verify again")
+
+	// Should not verify dependencies during other transactions
+	stub = newDependencyTestStub()
+	stub.MockTransactionStart(standardTxID)
+	cc.Invoke(stub)
+	assert.Len(t, stub.calls, 0, "should not verify dependencies on invoke")
+
+	// Should error when dependency fails
+	mc = new(myContract)
+	mc.AddDependency(Dependency{Chaincode: "cc3"})
+	cc = convertC2CC(mc)
+
+	stub = newDependencyTestStub()
+	stub.MockTransactionStart(standardTxID)
+	err = cc.contracts[SystemContractName].receiver.Interface().(*systemContract).VerifyDependencies(ctx)
+	assert.EqualError(t, err, "Contract myContract failed to verify dependencies. Dependency cc3 could not be verified. Calling its GetMetadata transaction returned status 500: chaincode not found. Check the chaincode is deployed and built using the contractapi", "should error when dependency missing")
+
+	// Should return error from transaction
+	stub.function = SystemContractName + ":VerifyDependencies"
+	response := cc.Invoke(stub)
+	assert.Equal(t, err.Error(), response.Message, "should return error from verify dependencies transaction")
+}
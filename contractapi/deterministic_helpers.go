@@ -0,0 +1,36 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+)
+
+// DeterministicRand returns a random number generator seeded from the ID of the
+// transaction. Every endorsing peer generates the same sequence of numbers for
+// the transaction so, unlike math/rand, it can be used without causing endorsement
+// mismatches. The numbers are predictable to anyone knowing the transaction ID so
+// must not be used where that matters e.g. generating secrets. The generator is
+// shared by all calls for the transaction.
+func (ctx *TransactionContext) DeterministicRand() *rand.Rand {
+	if ctx.rand == nil {
+		hash := sha256.Sum256([]byte(ctx.GetStub().GetTxID()))
+		ctx.rand = rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(hash[:8]))))
+	}
+
+	return ctx.rand
+}
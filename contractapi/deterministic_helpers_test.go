@@ -0,0 +1,48 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestDeterministicRand(t *testing.T) {
+	// Should generate same sequence for same transaction ID
+	firstCtx, _ := newStateTestContext()
+	secondCtx, _ := newStateTestContext()
+	first := firstCtx.DeterministicRand()
+	second := secondCtx.DeterministicRand()
+	assert.Equal(t, first.Int63(), second.Int63(), "should generate same numbers for same transaction")
+	assert.Equal(t, first.Int63(), second.Int63(), "should generate same sequence for same transaction")
+
+	// Should generate different sequence for different transaction ID
+	sameCtx, _ := newStateTestContext()
+	otherCtx, _ := newStateTestContext(withTxID("another tx"))
+	assert.NotEqual(t, sameCtx.DeterministicRand().Int63(), otherCtx.DeterministicRand().Int63(), "should generate different numbers for different transaction")
+
+	// Should share generator for transaction and reset for new stub
+	ctx, _ := newStateTestContext()
+	assert.Equal(t, ctx.DeterministicRand(), ctx.DeterministicRand(), "should share generator within transaction")
+	rnd := ctx.DeterministicRand()
+	ctx.SetStub(shimtest.NewMockStub("randTest", nil))
+	assert.False(t, rnd == ctx.DeterministicRand(), "should create new generator for new stub")
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// StartupDiagnostics describes the environment a chaincode is started in and
+// the contracts it contains. Comparing the diagnostics of each peer shows
+// whether every peer is running the same chaincode.
+type StartupDiagnostics struct {
+	ChaincodeID   string `json:"chaincodeId"`
+	PeerAddress   string `json:"peerAddress"`
+	TLSEnabled    bool   `json:"tlsEnabled"`
+	GoVersion     string `json:"goVersion"`
+	ContractCount int    `json:"contractCount"`
+	MetadataHash  string `json:"metadataHash"`
+}
+
+// EnableStartupDiagnostics sets Start to write the StartupDiagnostics of the
+// chaincode as JSON to the passed writer, e.g. os.Stdout, before starting.
+// Details are read from the environment the peer starts the chaincode with;
+// command line flags are not parsed. Passing nil disables the diagnostics.
+func (cc *ContractChaincode) EnableStartupDiagnostics(out io.Writer) {
+	cc.diagnosticsWriter = out
+}
+
+func (cc *ContractChaincode) getStartupDiagnostics() StartupDiagnostics {
+	diagnostics := StartupDiagnostics{}
+	diagnostics.ChaincodeID = os.Getenv("CORE_CHAINCODE_ID_NAME")
+	diagnostics.PeerAddress = os.Getenv("CORE_PEER_ADDRESS")
+	diagnostics.TLSEnabled = os.Getenv("CORE_PEER_TLS_ENABLED") == "true"
+	diagnostics.GoVersion = runtime.Version()
+
+	for name := range cc.contracts {
+		if name != SystemContractName {
+			diagnostics.ContractCount++
+		}
+	}
+
+	metadataBytes, _ := json.Marshal(cc.metadata)
+	hash := sha256.Sum256(metadataBytes)
+	diagnostics.MetadataHash = hex.EncodeToString(hash[:])
+
+	return diagnostics
+}
+
+func (cc *ContractChaincode) writeStartupDiagnostics() {
+	if cc.diagnosticsWriter == nil {
+		return
+	}
+
+	diagnosticsBytes, _ := json.MarshalIndent(cc.getStartupDiagnostics(), "", "  ")
+
+	fmt.Fprintf(cc.diagnosticsWriter, "Starting chaincode with diagnostics:\n%s\n", diagnosticsBytes)
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableStartupDiagnostics(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	cc := ContractChaincode{}
+	cc.EnableStartupDiagnostics(buf)
+
+	assert.Equal(t, buf, cc.diagnosticsWriter, "should set the diagnostics writer")
+}
+
+func TestGetStartupDiagnostics(t *testing.T) {
+	os.Setenv("CORE_CHAINCODE_ID_NAME", "mycc:1.0")
+	os.Setenv("CORE_PEER_TLS_ENABLED", "true")
+	os.Setenv("CORE_PEER_ADDRESS", "peer0:7052")
+	defer os.Unsetenv("CORE_CHAINCODE_ID_NAME")
+	defer os.Unsetenv("CORE_PEER_ADDRESS")
+	defer os.Unsetenv("CORE_PEER_TLS_ENABLED")
+
+	cc := convertC2CC(new(myContract), new(simpleTestContract))
+
+	metadataBytes, _ := json.Marshal(cc.metadata)
+	hash := sha256.Sum256(metadataBytes)
+
+	diagnostics := cc.getStartupDiagnostics()
+
+	assert.Equal(t, "mycc:1.0", diagnostics.ChaincodeID, "should read chaincode ID from env")
+	assert.Equal(t, "peer0:7052", diagnostics.PeerAddress, "should read peer address from env")
+	assert.True(t, diagnostics.TLSEnabled, "should read TLS enabled from env")
+	assert.Equal(t, runtime.Version(), diagnostics.GoVersion, "should return go version")
+	assert.Equal(t, 2, diagnostics.ContractCount, "should count contracts excluding system contract")
+	assert.Equal(t, hex.EncodeToString(hash[:]), diagnostics.MetadataHash, "should return hash of metadata")
+
+	// Should return false when TLS not enabled
+	os.Setenv("CORE_PEER_TLS_ENABLED", "false")
+	assert.False(t, cc.getStartupDiagnostics().TLSEnabled, "should return TLS not enabled")
+}
+
+func TestWriteStartupDiagnostics(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	cc := convertC2CC(new(myContract))
+
+	// Should not write when diagnostics not enabled
+	cc.writeStartupDiagnostics()
+	assert.Equal(t, "", buf.String(), "should not write when not enabled")
+
+	// Should write diagnostics as JSON
+	cc.EnableStartupDiagnostics(buf)
+	cc.writeStartupDiagnostics()
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, "Starting chaincode with diagnostics:\n"), "should write header")
+
+	diagnostics := StartupDiagnostics{}
+	json.Unmarshal([]byte(strings.TrimPrefix(output, "Starting chaincode with diagnostics:\n")), &diagnostics)
+	assert.Equal(t, cc.getStartupDiagnostics(), diagnostics, "should write diagnostics")
+
+	// Should write diagnostics on start
+	buf.Reset()
+	cc.Start()
+	assert.True(t, strings.HasPrefix(buf.String(), "Starting chaincode with diagnostics:\n"), "should write diagnostics on start")
+}
@@ -0,0 +1,85 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"sort"
+)
+
+// getEmbeddedContracts returns the types of the base contracts, structs other
+// than Contract that implement ContractInterface, embedded by value in the
+// contract struct. Their functions are promoted to the contract, with those it
+// defines itself taking precedence, so that a base contract can be shared by
+// multiple contracts of the chaincode. Other embedded structs, e.g. sync.Mutex,
+// are not base contracts.
+func getEmbeddedContracts(contractStruct reflect.Type) []reflect.Type {
+	embedded := []reflect.Type{}
+
+	for i := 0; i < contractStruct.NumField(); i++ {
+		field := contractStruct.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Type != contractType && reflect.PtrTo(field.Type).Implements(contractInterfaceType) {
+			embedded = append(embedded, field.Type)
+		}
+	}
+
+	return embedded
+}
+
+// isContractStruct returns whether the type is a struct embedding Contract
+// either directly or through an embedded base contract
+func isContractStruct(structType reflect.Type) bool {
+	if structType.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if field.Anonymous && (field.Type == contractType || isContractStruct(field.Type)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getAmbiguousFunctions returns the names of functions defined by more than one
+// of the structs embedded at the same depth in the contract struct. These are
+// not promoted so must be defined by the embedding contract to choose which of
+// the embedded functions is used.
+func getAmbiguousFunctions(contractStruct reflect.Type, excludeFuncs []string) []string {
+	contractPtr := reflect.PtrTo(contractStruct)
+	ambiguous := []string{}
+
+	for _, embedded := range getEmbeddedContracts(contractStruct) {
+		embeddedPtr := reflect.PtrTo(embedded)
+
+		for i := 0; i < embeddedPtr.NumMethod(); i++ {
+			name := embeddedPtr.Method(i).Name
+
+			if _, ok := contractPtr.MethodByName(name); ok || stringInSlice(name, excludeFuncs) || stringInSlice(name, ambiguous) {
+				continue
+			}
+
+			ambiguous = append(ambiguous, name)
+		}
+	}
+
+	sort.Strings(ambiguous)
+
+	return ambiguous
+}
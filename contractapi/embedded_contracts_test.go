@@ -0,0 +1,145 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type baseContract struct {
+	Contract
+}
+
+func (bc *baseContract) Create(id string) string {
+	return "base created " + id
+}
+
+func (bc *baseContract) Read(id string) string {
+	return "base read " + id
+}
+
+type extendedContract struct {
+	baseContract
+}
+
+func (ec *extendedContract) Read(id string) string {
+	return "extended read " + id
+}
+
+func (ec *extendedContract) Update(id string) string {
+	return "extended updated " + id
+}
+
+type readerBase struct {
+	Contract
+	Owner string
+}
+
+func (rb *readerBase) Read(id string) string {
+	return "reader read " + id
+}
+
+type otherReaderBase struct {
+	Contract
+}
+
+func (orb *otherReaderBase) Read(id string) string {
+	return "other reader read " + id
+}
+
+type ambiguousContract struct {
+	Contract
+	readerBase
+	otherReaderBase
+}
+
+type lockingContract struct {
+	Contract
+	sync.Mutex
+	readerBase
+}
+
+type resolvedContract struct {
+	ambiguousContract
+}
+
+func (rc *resolvedContract) Read(id string) string {
+	return rc.readerBase.Read(id)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestGetEmbeddedContracts(t *testing.T) {
+	// Should return embedded structs other than Contract
+	assert.Equal(t, []reflect.Type{}, getEmbeddedContracts(reflect.TypeOf(baseContract{})), "should not return Contract")
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(baseContract{})}, getEmbeddedContracts(reflect.TypeOf(extendedContract{})), "should return embedded base contract")
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(readerBase{}), reflect.TypeOf(otherReaderBase{})}, getEmbeddedContracts(reflect.TypeOf(ambiguousContract{})), "should return embedded base contracts")
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(readerBase{})}, getEmbeddedContracts(reflect.TypeOf(lockingContract{})), "should not return embedded structs that are not contracts")
+}
+
+func TestIsContractStruct(t *testing.T) {
+	assert.True(t, isContractStruct(reflect.TypeOf(baseContract{})), "should be true when Contract embedded")
+	assert.True(t, isContractStruct(reflect.TypeOf(extendedContract{})), "should be true when Contract embedded through base contract")
+	assert.False(t, isContractStruct(reflect.TypeOf(struct{ sync.Mutex }{})), "should be false when Contract not embedded")
+	assert.False(t, isContractStruct(reflect.TypeOf("")), "should be false for non struct")
+}
+
+func TestGetAmbiguousFunctions(t *testing.T) {
+	// Should return functions defined by embedded structs at same depth
+	assert.Equal(t, []string{"Read"}, getAmbiguousFunctions(reflect.TypeOf(ambiguousContract{}), nil), "should return ambiguous function")
+	assert.Equal(t, []string{}, getAmbiguousFunctions(reflect.TypeOf(ambiguousContract{}), []string{"Read"}), "should not return excluded function")
+
+	// Should not return functions defined by embedding contract
+	assert.Equal(t, []string{}, getAmbiguousFunctions(reflect.TypeOf(resolvedContract{}), nil), "should not return function defined by contract")
+	assert.Equal(t, []string{}, getAmbiguousFunctions(reflect.TypeOf(extendedContract{}), nil), "should not return overridden function")
+}
+
+func TestEmbeddedContracts(t *testing.T) {
+	var cc ContractChaincode
+
+	// Should promote functions of embedded contract without those of Contract
+	cc = convertC2CC(new(extendedContract))
+	functions := []string{}
+	for name := range cc.contracts["extendedContract"].functions {
+		functions = append(functions, name)
+	}
+	assert.ElementsMatch(t, []string{"Create", "Read", "Update"}, functions, "should include promoted functions")
+
+	// Should call promoted and overriding functions
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "asset1"}, "INVOKE", "base created asset1")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Read", "asset1"}, "INVOKE", "extended read asset1")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "asset1"}, "INVOKE", "extended updated asset1")
+
+	// Should panic when embedded structs define same function
+	assert.PanicsWithValue(t, "Contract ambiguousContract embeds multiple contracts defining functions Read. Define them on ambiguousContract to choose which is used", func() { convertC2CC(new(ambiguousContract)) }, "should panic for ambiguous function")
+
+	// Should use function of embedding contract when it resolves ambiguity
+	cc = convertC2CC(new(resolvedContract))
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Read", "asset1"}, "INVOKE", "reader read asset1")
+
+	// Should report exported fields of embedded structs as shared
+	assert.Equal(t, []string{"Owner"}, getSharedFields(reflect.TypeOf(resolvedContract{})), "should return fields of embedded structs")
+	assert.Equal(t, []string{}, getSharedFields(reflect.TypeOf(extendedContract{})), "should not return embedded contracts as fields")
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Labels used to derive the subkeys of an encryption key, so that the key used to
+// derive nonces is not also used for AES-GCM
+const (
+	encryptionSubkeyLabel = "contractapi encryption"
+	nonceSubkeyLabel      = "contractapi nonce"
+)
+
+// Encrypt encrypts the value using AES-GCM with the key passed in the transient
+// data of the transaction under the passed name, so that confidential fields can
+// be written to the world state without the key being recorded in the transaction.
+// The key must be 16, 24 or 32 bytes long. Every endorsing peer must produce the
+// same ciphertext so the nonce is derived from the key, the transaction ID and the
+// value rather than generated randomly. Separate subkeys of the key are derived
+// for encrypting and for deriving the nonce. Encrypting the same value with the same
+// key in one transaction therefore gives the same ciphertext. The returned
+// ciphertext is prefixed with its nonce and can be decrypted using Decrypt.
+func (ctx *TransactionContext) Encrypt(value []byte, keyName string) ([]byte, error) {
+	nonceKey, aead, err := ctx.getTransientCipher(keyName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, nonceKey)
+	mac.Write([]byte(ctx.GetStub().GetTxID()))
+	mac.Write([]byte{0})
+	mac.Write(value)
+
+	nonce := mac.Sum(nil)[:aead.NonceSize()]
+
+	return aead.Seal(nonce, nonce, value, nil), nil
+}
+
+// Decrypt decrypts a ciphertext returned by Encrypt using the key passed in the
+// transient data of the transaction under the passed name. Returns an error if
+// the key is not the key the value was encrypted with or the ciphertext has been
+// altered.
+func (ctx *TransactionContext) Decrypt(ciphertext []byte, keyName string) ([]byte, error) {
+	_, aead, err := ctx.getTransientCipher(keyName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("Failed to decrypt value. Ciphertext is too short")
+	}
+
+	value, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decrypt value. %s", err.Error())
+	}
+
+	return value, nil
+}
+
+// getTransientCipher returns the subkey for deriving nonces from the named key in
+// the transient data of the transaction and an AES-GCM cipher using its subkey
+// for encrypting
+func (ctx *TransactionContext) getTransientCipher(keyName string) ([]byte, cipher.AEAD, error) {
+	transient, err := ctx.GetStub().GetTransient()
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to get transient data. %s", err.Error())
+	}
+
+	key, ok := transient[keyName]
+
+	if !ok {
+		return nil, nil, fmt.Errorf("Encryption key %s not found in transient data", keyName)
+	}
+
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, nil, fmt.Errorf("Encryption key %s is not valid. Expected 16, 24 or 32 bytes", keyName)
+	}
+
+	block, err := aes.NewCipher(deriveSubkey(key, encryptionSubkeyLabel))
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create cipher. %s", err.Error())
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to create cipher. %s", err.Error())
+	}
+
+	return deriveSubkey(key, nonceSubkeyLabel), aead, nil
+}
+
+// deriveSubkey derives a subkey of the same length as the key, up to 32 bytes, for
+// the labelled use as the first block of HKDF-Expand using HMAC-SHA256 would
+func deriveSubkey(key []byte, label string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label))
+	mac.Write([]byte{1})
+
+	subkey := mac.Sum(nil)
+
+	if len(key) < len(subkey) {
+		subkey = subkey[:len(key)]
+	}
+
+	return subkey
+}
@@ -0,0 +1,118 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var testEncryptionKey = bytes.Repeat([]byte("k"), 32)
+
+// ================================
+// Tests
+// ================================
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	var ciphertext []byte
+	var value []byte
+	var err error
+
+	ctx, stub := newStateTestContext(withTxID("txID1"), withTransient(map[string][]byte{"key": testEncryptionKey, "short": []byte("short"), "long": bytes.Repeat([]byte("l"), 40), "other": bytes.Repeat([]byte("o"), 16)}))
+	sameTxCtx, _ := newStateTestContext(withTxID("txID1"), withTransient(map[string][]byte{"key": testEncryptionKey}))
+	otherTxCtx, _ := newStateTestContext(withTxID("txID2"), withTransient(map[string][]byte{"key": testEncryptionKey}))
+
+	// Should encrypt and decrypt value
+	ciphertext, err = ctx.Encrypt([]byte("some value"), "key")
+	assert.Nil(t, err, "should not error encrypting")
+	assert.NotContains(t, string(ciphertext), "some value", "should encrypt value")
+	assert.Len(t, ciphertext, 12+len("some value")+16, "should prefix nonce and append tag")
+
+	value, err = ctx.Decrypt(ciphertext, "key")
+	assert.Nil(t, err, "should not error decrypting")
+	assert.Equal(t, []byte("some value"), value, "should decrypt value")
+
+	// Should encrypt deterministically for transaction
+	sameCiphertext, _ := sameTxCtx.Encrypt([]byte("some value"), "key")
+	assert.Equal(t, ciphertext, sameCiphertext, "should give same ciphertext for same transaction")
+
+	otherTxCiphertext, _ := otherTxCtx.Encrypt([]byte("some value"), "key")
+	assert.NotEqual(t, ciphertext[:12], otherTxCiphertext[:12], "should use different nonce for other transaction")
+
+	otherValueCiphertext, _ := ctx.Encrypt([]byte("other value"), "key")
+	assert.NotEqual(t, ciphertext[:12], otherValueCiphertext[:12], "should use different nonce for other value")
+
+	// Should encrypt using subkey rather than key
+	block, _ := aes.NewCipher(testEncryptionKey)
+	aead, _ := cipher.NewGCM(block)
+	_, err = aead.Open(nil, ciphertext[:12], ciphertext[12:], nil)
+	assert.NotNil(t, err, "should not encrypt using key")
+
+	value, err = otherTxCtx.Decrypt(ciphertext, "key")
+	assert.Nil(t, err, "should decrypt in other transaction")
+	assert.Equal(t, []byte("some value"), value, "should decrypt value in other transaction")
+
+	// Should error for bad key
+	_, err = ctx.Encrypt([]byte("some value"), "missing")
+	assert.EqualError(t, err, "Encryption key missing not found in transient data", "should error when key missing")
+
+	_, err = ctx.Decrypt(ciphertext, "short")
+	assert.EqualError(t, err, "Encryption key short is not valid. Expected 16, 24 or 32 bytes", "should error when key invalid")
+
+	_, err = ctx.Encrypt([]byte("some value"), "long")
+	assert.EqualError(t, err, "Encryption key long is not valid. Expected 16, 24 or 32 bytes", "should error when key longer than 32 bytes")
+
+	_, err = ctx.Decrypt(ciphertext, "other")
+	assert.EqualError(t, err, "Failed to decrypt value. cipher: message authentication failed", "should error when key wrong")
+
+	stub.transientErr = errors.New("some error")
+	_, err = ctx.Encrypt([]byte("some value"), "key")
+	assert.EqualError(t, err, "Failed to get transient data. some error", "should error when transient errors")
+	stub.transientErr = nil
+
+	// Should error for bad ciphertext
+
+	_, err = ctx.Decrypt(ciphertext[:11], "key")
+	assert.EqualError(t, err, "Failed to decrypt value. Ciphertext is too short", "should error when ciphertext too short")
+
+	altered := append([]byte{}, ciphertext...)
+	altered[len(altered)-1] ^= 1
+	_, err = ctx.Decrypt(altered, "key")
+	assert.EqualError(t, err, "Failed to decrypt value. cipher: message authentication failed", "should error when ciphertext altered")
+}
+
+func TestDeriveSubkey(t *testing.T) {
+	encryptionSubkey := deriveSubkey(testEncryptionKey, encryptionSubkeyLabel)
+	nonceSubkey := deriveSubkey(testEncryptionKey, nonceSubkeyLabel)
+
+	// Should derive subkeys of same length as key
+	assert.Len(t, encryptionSubkey, 32, "should derive subkey of length of key")
+	assert.Len(t, deriveSubkey(bytes.Repeat([]byte("k"), 16), encryptionSubkeyLabel), 16, "should derive shorter subkey for shorter key")
+
+	// Should derive distinct subkeys for each label
+	assert.NotEqual(t, encryptionSubkey, nonceSubkey, "should derive different subkeys for different labels")
+	assert.NotEqual(t, testEncryptionKey, encryptionSubkey, "should not use key as subkey")
+	assert.Equal(t, encryptionSubkey, deriveSubkey(testEncryptionKey, encryptionSubkeyLabel), "should derive same subkey each time")
+	assert.NotEqual(t, encryptionSubkey, deriveSubkey(bytes.Repeat([]byte("o"), 32), encryptionSubkeyLabel), "should derive different subkeys for different keys")
+}
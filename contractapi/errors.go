@@ -0,0 +1,122 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Error can be returned by contract functions, before, after and unknown transactions,
+// middleware and transformers to give clients a code and details of the error in the
+// response rather than only a message. Invoke returns the error JSON marshalled as the
+// payload of the response. If the code is an error status, i.e. from 400 to 599, it
+// is used as the status of the response, otherwise the status is 500.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// NewError returns an error with the passed code, message and details.
+// Details must be JSON marshallable.
+func NewError(code int, message string, details interface{}) *Error {
+	return &Error{code, message, details}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// BeforeTransactionError is returned by Invoke when a before transaction returns an
+// error. The named or unknown function and the after transactions are then not
+// called. The response has the status and payload the wrapped error would have but
+// its message is prefixed so that clients can distinguish failures setting up the
+// transaction from those of the function itself.
+type BeforeTransactionError struct {
+	Err error
+}
+
+func (bte *BeforeTransactionError) Error() string {
+	return "Before transaction failed. " + bte.Err.Error()
+}
+
+// Unwrap returns the error returned by the before transaction
+func (bte *BeforeTransactionError) Unwrap() error {
+	return bte.Err
+}
+
+// ErrNotFound is wrapped by the error returned by GetStateAs and Delete when the key
+// does not exist in the world state, and by GetStateHash when the key has no hash.
+// Check for it using errors.Is. Invoke returns a response with status 404 when a
+// function returns the error unchanged.
+var ErrNotFound = errors.New("Key does not exist in the world state")
+
+type keyNotFoundError struct {
+	key  string
+	hash bool
+}
+
+func (knfe *keyNotFoundError) Error() string {
+	if knfe.hash {
+		return fmt.Sprintf("Hash of key %s does not exist in the world state", knfe.key)
+	}
+
+	return fmt.Sprintf("Key %s does not exist in the world state", knfe.key)
+}
+
+// Unwrap returns ErrNotFound
+func (knfe *keyNotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+func errorResponse(err error) peer.Response {
+	switch typedErr := err.(type) {
+	case *BeforeTransactionError:
+		response := errorResponse(typedErr.Err)
+		response.Message = typedErr.Error()
+
+		return response
+	case *keyNotFoundError:
+		return peer.Response{Status: http.StatusNotFound, Message: typedErr.Error()}
+	case *ValidationError:
+		return peer.Response{Status: shim.ERRORTHRESHOLD, Message: typedErr.Error()}
+	case *SchemaValidationError:
+		payload, _ := json.Marshal(typedErr)
+
+		return peer.Response{Status: shim.ERRORTHRESHOLD, Message: typedErr.Error(), Payload: payload}
+	case *Error:
+		payload, marshalErr := json.Marshal(typedErr)
+
+		if marshalErr != nil {
+			return shim.Error(fmt.Sprintf("%s. Failed to marshal error details. %s", typedErr.Message, marshalErr.Error()))
+		}
+
+		status := int32(shim.ERROR)
+
+		if typedErr.Code >= shim.ERRORTHRESHOLD && typedErr.Code < 600 {
+			status = int32(typedErr.Code)
+		}
+
+		return peer.Response{Status: status, Message: typedErr.Message, Payload: payload}
+	default:
+		return shim.Error(err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type errorTestContract struct {
+	Contract
+}
+
+func (etc *errorTestContract) Read(id string) (string, error) {
+	return "", NewError(404, "Cannot read asset "+id, map[string]string{"id": id})
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewError(t *testing.T) {
+	err := NewError(1001, "some message", map[string]int{"limit": 10})
+
+	assert.Equal(t, &Error{Code: 1001, Message: "some message", Details: map[string]int{"limit": 10}}, err, "should create error")
+	assert.Equal(t, "some message", err.Error(), "should use message as error string")
+}
+
+func TestErrorResponse(t *testing.T) {
+	// Should return 500 for other errors
+	assert.Equal(t, shim.Error("some error"), errorResponse(errors.New("some error")), "should return shim error")
+
+	// Should return 400 for validation errors
+	assert.Equal(t, peer.Response{Status: 400, Message: "some validation error"}, errorResponse(&ValidationError{"some validation error"}), "should return 400 for validation error")
+
+	// Should return 400 and failures as payload for schema validation errors
+	sve := &SchemaValidationError{"asset", []SchemaValidationFailure{{"id", "id is required"}}, "some schema error"}
+	assert.Equal(t, peer.Response{Status: 400, Message: "some schema error", Payload: []byte(`{"parameter":"asset","failures":[{"property":"id","message":"id is required"}]}`)}, errorResponse(sve), "should return 400 for schema validation error")
+
+	// Should return 404 for not found errors
+	assert.Equal(t, peer.Response{Status: 404, Message: "Key asset1 does not exist in the world state"}, errorResponse(&keyNotFoundError{key: "asset1"}), "should return 404 for not found error")
+	assert.Equal(t, peer.Response{Status: 404, Message: "Hash of key asset1 does not exist in the world state"}, errorResponse(&keyNotFoundError{key: "asset1", hash: true}), "should return 404 for hash not found error")
+
+	// Should return error as payload using code as status
+	assert.Equal(t, peer.Response{Status: 404, Message: "not found", Payload: []byte(`{"code":404,"message":"not found","details":{"id":"1"}}`)}, errorResponse(NewError(404, "not found", map[string]string{"id": "1"})), "should use error status code")
+
+	// Should use status 500 when code is not error status
+	assert.Equal(t, peer.Response{Status: 500, Message: "limit exceeded", Payload: []byte(`{"code":1001,"message":"limit exceeded"}`)}, errorResponse(NewError(1001, "limit exceeded", nil)), "should use status 500")
+
+	// Should return shim error when details cannot be marshalled
+	response := errorResponse(NewError(1001, "bad details", make(chan int)))
+	assert.Equal(t, int32(500), response.Status, "should return status 500")
+	assert.Contains(t, response.Message, "bad details. Failed to marshal error details.", "should return marshal error")
+
+	// Should prefix message of before transaction errors keeping status and payload
+	assert.Equal(t, shim.Error("Before transaction failed. some error"), errorResponse(&BeforeTransactionError{errors.New("some error")}), "should prefix before error")
+	assert.Equal(t, peer.Response{Status: 404, Message: "Before transaction failed. not found", Payload: []byte(`{"code":404,"message":"not found"}`)}, errorResponse(&BeforeTransactionError{NewError(404, "not found", nil)}), "should keep status and payload of wrapped error")
+}
+
+func TestKeyNotFoundError(t *testing.T) {
+	err := &keyNotFoundError{key: "asset1"}
+
+	assert.EqualError(t, err, "Key asset1 does not exist in the world state", "should include key in message")
+	assert.True(t, errors.Is(err, ErrNotFound), "should wrap ErrNotFound")
+}
+
+func TestBeforeTransactionError(t *testing.T) {
+	err := errors.New("some error")
+	bte := &BeforeTransactionError{err}
+
+	assert.Equal(t, "Before transaction failed. some error", bte.Error(), "should prefix message")
+	assert.Equal(t, err, bte.Unwrap(), "should unwrap to before error")
+}
+
+func TestInvokeWithError(t *testing.T) {
+	cc := convertC2CC(new(errorTestContract))
+	mockStub := shimtest.NewMockStub("errorTest", &cc)
+
+	// Should return code and details of error returned by function
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("Read"), []byte("ASSET1")})
+	assert.Equal(t, peer.Response{Status: 404, Message: "Cannot read asset ASSET1", Payload: []byte(`{"code":404,"message":"Cannot read asset ASSET1","details":{"id":"ASSET1"}}`)}, response, "should return error response")
+}
@@ -0,0 +1,164 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package fabricinterop adapts contracts between this module and
+// github.com/hyperledger/fabric-contract-api-go so that a chaincode can be
+// migrated between the two libraries a contract at a time. The transaction
+// contexts of the libraries differ (GetClientIdentity returns an error here but
+// not in fabric-contract-api-go), so a contract is adapted by wrapping it in a
+// chaincode of its own library rather than by converting its functions.
+package fabricinterop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	fabriccontractapi "github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-contract-api-go/metadata"
+)
+
+// FromFabric returns a contract, for passing to contractapi.CreateNewChaincode,
+// that handles its transactions using the passed contract written against
+// fabric-contract-api-go. The returned contract has the name of the passed one
+// and its transactions are called in the same way e.g. "assets:Create". They
+// run in a chaincode created by fabric-contract-api-go so use its transaction
+// context, serializer and metadata; the metadata of this chaincode lists the
+// contract without functions (see contractapi.WrapLegacyChaincode). Returns an
+// error if fabric-contract-api-go rejects the contract.
+func FromFabric(contract fabriccontractapi.ContractInterface) (contractapi.ContractInterface, error) {
+	chaincode, err := fabriccontractapi.NewChaincode(contract)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create fabric chaincode. %s", err.Error())
+	}
+
+	return contractapi.WrapLegacyChaincode(chaincode, chaincode.DefaultContract), nil
+}
+
+// fabricContract is a fabric-contract-api-go contract handling all its transactions
+// by passing them to a chaincode created by this module
+type fabricContract struct {
+	fabriccontractapi.Contract
+	chaincode *contractapi.ContractChaincode
+}
+
+// GetVersion returns the version of the contract. As fabric-contract-api-go requires
+// a contract to have a public function this is its only function, all other functions
+// of the contract are handled by its unknown transaction.
+func (fc *fabricContract) GetVersion() string {
+	return fc.Info.Version
+}
+
+func (fc *fabricContract) handleTransaction(ctx fabriccontractapi.TransactionContextInterface) (string, error) {
+	stub := ctx.GetStub()
+	fn, _ := stub.GetFunctionAndParameters()
+
+	response := fc.chaincode.Invoke(newContractStub(stub, fc.Name, fn))
+
+	if response.Status >= shim.ERRORTHRESHOLD {
+		return "", errors.New(response.Message)
+	}
+
+	return string(response.Payload), nil
+}
+
+// ToFabric returns contracts, for passing to fabric-contract-api-go's NewChaincode,
+// that handle their transactions using a chaincode created from the passed contracts
+// by contractapi.CreateNewChaincode, and so panics in the same cases. A contract is
+// returned for each passed contract, in order of name, with the same name and
+// version, and its transactions are called in the same way e.g. "assets:Create".
+// As fabric-contract-api-go only returns a message for errors the status of error
+// responses is lost, and its metadata lists GetVersion as the only function of each
+// contract. The system contract of the chaincode is not returned.
+func ToFabric(contracts ...contractapi.ContractInterface) []fabriccontractapi.ContractInterface {
+	cc := contractapi.CreateNewChaincode(contracts...)
+	ccMetadata := cc.GetMetadata()
+
+	names := []string{}
+
+	for name := range ccMetadata.Contracts {
+		if name != contractapi.SystemContractName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	fabricContracts := []fabriccontractapi.ContractInterface{}
+
+	for _, name := range names {
+		info := ccMetadata.Contracts[name].Info
+
+		fc := new(fabricContract)
+		fc.Name = name
+		fc.Info = metadata.InfoMetadata{
+			Title:       info.Title,
+			Description: info.Description,
+			Version:     info.Version,
+		}
+		fc.UnknownTransaction = fc.handleTransaction
+		fc.chaincode = &cc
+
+		fabricContracts = append(fabricContracts, fc)
+	}
+
+	return fabricContracts
+}
+
+// contractStub replaces the args of a transaction so that the function name passed
+// to the chaincode always includes the name of the contract, as fabric-contract-api-go
+// allows it to be left out when calling its default contract
+type contractStub struct {
+	shim.ChaincodeStubInterface
+	args [][]byte
+}
+
+func newContractStub(stub shim.ChaincodeStubInterface, ns string, nsFcn string) *contractStub {
+	args := [][]byte{[]byte(ns + ":" + strings.TrimPrefix(nsFcn, ns+":"))}
+
+	if original := stub.GetArgs(); len(original) > 1 {
+		args = append(args, original[1:]...)
+	}
+
+	return &contractStub{stub, args}
+}
+
+func (cs *contractStub) GetArgs() [][]byte {
+	return cs.args
+}
+
+func (cs *contractStub) GetStringArgs() []string {
+	strArgs := make([]string, 0, len(cs.args))
+
+	for _, arg := range cs.args {
+		strArgs = append(strArgs, string(arg))
+	}
+
+	return strArgs
+}
+
+func (cs *contractStub) GetFunctionAndParameters() (string, []string) {
+	allArgs := cs.GetStringArgs()
+
+	return allArgs[0], allArgs[1:]
+}
+
+func (cs *contractStub) GetArgsSlice() ([]byte, error) {
+	return bytes.Join(cs.args, nil), nil
+}
@@ -0,0 +1,210 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fabricinterop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/contractapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	fabriccontractapi "github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+const standardTxID = "1234567890"
+
+type fabricAssetContract struct {
+	fabriccontractapi.Contract
+}
+
+func (fac *fabricAssetContract) Put(ctx fabriccontractapi.TransactionContextInterface, key string, value string) error {
+	return ctx.GetStub().PutState(key, []byte(value))
+}
+
+func (fac *fabricAssetContract) Get(ctx fabriccontractapi.TransactionContextInterface, key string) (string, error) {
+	value, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+type emptyFabricContract struct {
+	fabriccontractapi.Contract
+}
+
+type assetContract struct {
+	contractapi.Contract
+}
+
+func (ac *assetContract) Put(ctx contractapi.TransactionContextInterface, key string, value string) error {
+	return ctx.GetStub().PutState(key, []byte(value))
+}
+
+func (ac *assetContract) Get(ctx contractapi.TransactionContextInterface, key string) (string, error) {
+	value, err := ctx.GetStub().GetState(key)
+
+	if err != nil {
+		return "", err
+	} else if value == nil {
+		return "", errors.New("Asset " + key + " does not exist")
+	}
+
+	return string(value), nil
+}
+
+type ownerContract struct {
+	contractapi.Contract
+}
+
+func (oc *ownerContract) Owner() string {
+	return "alice"
+}
+
+func invoke(stub *shimtest.MockStub, args ...string) (int32, string, string) {
+	byteArgs := [][]byte{}
+
+	for _, arg := range args {
+		byteArgs = append(byteArgs, []byte(arg))
+	}
+
+	response := stub.MockInvoke(standardTxID, byteArgs)
+
+	return response.Status, string(response.Payload), response.Message
+}
+
+type argsStub struct {
+	*shimtest.MockStub
+	args [][]byte
+}
+
+func (as *argsStub) GetArgs() [][]byte {
+	return as.args
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestFromFabric(t *testing.T) {
+	var status int32
+	var payload string
+
+	// Should error when fabric contract invalid
+	_, err := FromFabric(new(emptyFabricContract))
+	assert.Contains(t, err.Error(), "Failed to create fabric chaincode. Contracts are required to have at least 1 (non-ignored) public method", "should error when fabric rejects contract")
+
+	// Should call transactions of fabric contract by its name
+	contract, err := FromFabric(new(fabricAssetContract))
+	assert.Nil(t, err, "should not error for valid contract")
+	assert.Equal(t, "fabricAssetContract", contract.GetName(), "should use name of fabric contract")
+
+	cc := contractapi.CreateNewChaincode(new(assetContract), contract)
+	mockStub := shimtest.NewMockStub("fromFabric", &cc)
+
+	status, _, _ = invoke(mockStub, "fabricAssetContract:Put", "asset1", "value1")
+	assert.Equal(t, int32(shim.OK), status, "should call put of fabric contract")
+
+	_, payload, _ = invoke(mockStub, "fabricAssetContract:Get", "asset1")
+	assert.Equal(t, "value1", payload, "should return response of fabric contract")
+
+	// Should share world state with other contracts
+	_, payload, _ = invoke(mockStub, "assetContract:Get", "asset1")
+	assert.Equal(t, "value1", payload, "should read state written by fabric contract")
+
+	status, _, _ = invoke(mockStub, "assetContract:Put", "asset2", "value2")
+	assert.Equal(t, int32(shim.OK), status, "should call put of contract")
+
+	_, payload, _ = invoke(mockStub, "fabricAssetContract:Get", "asset2")
+	assert.Equal(t, "value2", payload, "should read state written by contract")
+}
+
+func TestToFabric(t *testing.T) {
+	var status int32
+	var payload string
+	var message string
+
+	ac := new(assetContract)
+	ac.SetVersion("1.0.0")
+
+	contracts := ToFabric(new(ownerContract), ac)
+
+	// Should return contract for each contract in order of name
+	assert.Len(t, contracts, 2, "should not return system contract")
+	assert.Equal(t, "assetContract", contracts[0].GetName(), "should use name of contract")
+	assert.Equal(t, "ownerContract", contracts[1].GetName(), "should sort contracts by name")
+	assert.Equal(t, "1.0.0", contracts[0].GetInfo().Version, "should set version in info")
+	assert.Equal(t, "1.0.0", contracts[0].(*fabricContract).GetVersion(), "should return version")
+
+	// Should call transactions of contracts by their name
+	fcc, err := fabriccontractapi.NewChaincode(contracts...)
+	assert.Nil(t, err, "should be accepted by fabric")
+
+	mockStub := shimtest.NewMockStub("toFabric", fcc)
+
+	status, _, _ = invoke(mockStub, "assetContract:Put", "asset1", "value1")
+	assert.Equal(t, int32(shim.OK), status, "should call put of contract")
+
+	_, payload, _ = invoke(mockStub, "assetContract:Get", "asset1")
+	assert.Equal(t, "value1", payload, "should return response of contract")
+
+	_, payload, _ = invoke(mockStub, "ownerContract:Owner")
+	assert.Equal(t, "alice", payload, "should call other contracts")
+
+	// Should call default contract when name left out
+	_, payload, _ = invoke(mockStub, "Get", "asset1")
+	assert.Equal(t, "value1", payload, "should call default contract of fabric")
+
+	// Should return message of error responses
+	status, _, message = invoke(mockStub, "assetContract:Get", "asset2")
+	assert.Equal(t, int32(shim.ERROR), status, "should return error status")
+	assert.Equal(t, "Asset asset2 does not exist", message, "should return message of error")
+
+	// Should panic when contracts invalid
+	assert.Panics(t, func() { ToFabric(new(ownerContract), new(ownerContract)) }, "should panic as CreateNewChaincode does")
+}
+
+func TestContractStub(t *testing.T) {
+	mockStub := &argsStub{shimtest.NewMockStub("contractStub", nil), [][]byte{[]byte("Transfer"), []byte("alice"), []byte("bob")}}
+	mockStub.MockTransactionStart(standardTxID)
+
+	// Should add name of contract to function
+	stub := newContractStub(mockStub, "assets", "Transfer")
+	assert.Equal(t, [][]byte{[]byte("assets:Transfer"), []byte("alice"), []byte("bob")}, stub.GetArgs(), "should replace function in args")
+	assert.Equal(t, []string{"assets:Transfer", "alice", "bob"}, stub.GetStringArgs(), "should replace function in string args")
+
+	fn, params := stub.GetFunctionAndParameters()
+	assert.Equal(t, "assets:Transfer", fn, "should return function with contract name")
+	assert.Equal(t, []string{"alice", "bob"}, params, "should return params")
+
+	argsSlice, err := stub.GetArgsSlice()
+	assert.Nil(t, err, "should not error getting args slice")
+	assert.Equal(t, []byte("assets:Transferalicebob"), argsSlice, "should join args")
+
+	// Should not add name of contract twice
+	stub = newContractStub(mockStub, "assets", "assets:Transfer")
+	assert.Equal(t, "assets:Transfer", string(stub.GetArgs()[0]), "should keep function with contract name")
+
+	// Should use stub for other functions
+	assert.Equal(t, standardTxID, stub.GetTxID(), "should return transaction ID of stub")
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FeatureFlagEnvPrefix is the prefix of environment variables used to override
+// the value of feature flags. The name of the flag is upper cased with characters
+// that are not letters or digits replaced by underscores e.g. the flag newPricing
+// is overridden by CHAINCODE_FEATURE_NEWPRICING
+const FeatureFlagEnvPrefix = "CHAINCODE_FEATURE_"
+
+// SetFeatureFlag defines a feature flag for the chaincode and its default
+// value. The value can be overridden per environment by setting the flag's
+// environment variable (see FeatureFlagEnvPrefix) to a boolean value. The
+// environment is read once, when the chaincode is started (see Start), and
+// must be the same for the chaincode on every peer, otherwise peers endorsing
+// the same transaction may return different results.
+// Contract functions can check the flag using the FeatureEnabled function of
+// the transaction context. Its value can be overridden by a transaction storing
+// the flag in the world state (see Features and FeatureFlagContract).
+func (cc *ContractChaincode) SetFeatureFlag(name string, enabled bool) {
+	if cc.featureFlags == nil {
+		cc.featureFlags = make(map[string]bool)
+	}
+
+	cc.featureFlags[name] = enabled
+}
+
+// getFeatureFlags returns the flags as resolved when the chaincode was started,
+// or their default values if it has not been started using Start
+func (cc *ContractChaincode) getFeatureFlags() map[string]bool {
+	if cc.resolvedFeatureFlags != nil {
+		return cc.resolvedFeatureFlags
+	}
+
+	return cc.featureFlags
+}
+
+func (cc *ContractChaincode) resolveFeatureFlags() map[string]bool {
+	resolved := make(map[string]bool)
+
+	for name, enabled := range cc.featureFlags {
+		resolved[name] = enabled
+
+		if envVal, ok := os.LookupEnv(featureFlagEnvName(name)); ok {
+			if envEnabled, err := strconv.ParseBool(envVal); err == nil {
+				resolved[name] = envEnabled
+			}
+		}
+	}
+
+	return resolved
+}
+
+func featureFlagEnvName(name string) string {
+	envName := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+
+	return FeatureFlagEnvPrefix + envName
+}
+
+// FeatureEnabled returns whether the named feature flag is enabled, as for
+// Enabled of the Features of the context, so that a flag stored in the world
+// state takes precedence over the flag as defined for the chaincode. Flags not
+// stored or defined by the chaincode are not enabled.
+func (ctx *TransactionContext) FeatureEnabled(name string) bool {
+	return ctx.Features().Enabled(name)
+}
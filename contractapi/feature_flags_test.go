@@ -0,0 +1,124 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type featureFlagContract struct {
+	Contract
+}
+
+func (ffc *featureFlagContract) IsEnabled(ctx *TransactionContext, name string) string {
+	return strconv.FormatBool(ctx.FeatureEnabled(name))
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetFeatureFlag(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.SetFeatureFlag("newPricing", true)
+	cc.SetFeatureFlag("oldPricing", false)
+
+	assert.Equal(t, map[string]bool{"newPricing": true, "oldPricing": false}, cc.featureFlags, "should store flags and their default values")
+}
+
+func TestFeatureFlagEnvName(t *testing.T) {
+	assert.Equal(t, "CHAINCODE_FEATURE_NEWPRICING", featureFlagEnvName("newPricing"), "should upper case name")
+	assert.Equal(t, "CHAINCODE_FEATURE_NEW_PRICING_V2", featureFlagEnvName("new-pricing.v2"), "should replace non alphanumerics")
+}
+
+func TestResolveFeatureFlags(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should return empty when no flags
+	assert.Equal(t, map[string]bool{}, cc.resolveFeatureFlags(), "should return no flags when none defined")
+
+	cc.SetFeatureFlag("flag1", true)
+	cc.SetFeatureFlag("flag2", false)
+	cc.SetFeatureFlag("flag3", false)
+
+	os.Setenv("CHAINCODE_FEATURE_FLAG2", "true")
+	os.Setenv("CHAINCODE_FEATURE_FLAG3", "not a bool")
+	defer os.Unsetenv("CHAINCODE_FEATURE_FLAG2")
+	defer os.Unsetenv("CHAINCODE_FEATURE_FLAG3")
+
+	// Should use defaults unless overridden by valid env values
+	assert.Equal(t, map[string]bool{"flag1": true, "flag2": true, "flag3": false}, cc.resolveFeatureFlags(), "should resolve env overrides")
+}
+
+func TestGetFeatureFlags(t *testing.T) {
+	cc := convertC2CC(new(featureFlagContract))
+	cc.SetFeatureFlag("flag1", false)
+
+	os.Setenv("CHAINCODE_FEATURE_FLAG1", "true")
+	defer os.Unsetenv("CHAINCODE_FEATURE_FLAG1")
+
+	// Should use defaults when not started
+	assert.Equal(t, map[string]bool{"flag1": false}, cc.getFeatureFlags(), "should use defaults when not started")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"IsEnabled", "flag1"}, invokeType, "false")
+
+	// Should resolve env once at start
+	restore := stubShimStart(func(shim.Chaincode) error { return nil })
+	defer restore()
+
+	cc.StartWithContext(context.Background())
+	os.Setenv("CHAINCODE_FEATURE_FLAG1", "false")
+	assert.Equal(t, map[string]bool{"flag1": true}, cc.getFeatureFlags(), "should use flags resolved at start")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"IsEnabled", "flag1"}, invokeType, "true")
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	stub := shimtest.NewMockStub("featureEnabledTest", nil)
+	ctx := TransactionContext{}
+	ctx.SetStub(stub)
+
+	assert.False(t, ctx.FeatureEnabled("someflag"), "should not be enabled when no flags set")
+
+	ctx.details.featureFlags = map[string]bool{"someflag": true, "otherflag": false}
+
+	assert.True(t, ctx.FeatureEnabled("someflag"), "should be enabled when flag true")
+	assert.False(t, ctx.FeatureEnabled("otherflag"), "should not be enabled when flag false")
+	assert.False(t, ctx.FeatureEnabled("unknownflag"), "should not be enabled when flag unknown")
+
+	// Should use flags stored in the world state
+	putFeature(stub, "someflag", "false")
+	putFeature(stub, "unknownflag", "true")
+	assert.False(t, ctx.FeatureEnabled("someflag"), "should use stored value over chaincode value")
+	assert.True(t, ctx.FeatureEnabled("unknownflag"), "should use stored value when not defined for chaincode")
+	assert.Equal(t, ctx.Features().Enabled("someflag"), ctx.FeatureEnabled("someflag"), "should match Features")
+
+	// Should pass flags to context on invoke
+	cc := convertC2CC(new(featureFlagContract))
+	cc.SetFeatureFlag("someflag", true)
+
+	callContractFunctionAndCheckSuccess(t, cc, []string{"IsEnabled", "someflag"}, invokeType, "true")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"IsEnabled", "unknownflag"}, invokeType, "false")
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"sort"
+	"strings"
+)
+
+// EnableCaseInsensitiveFunctions routes function names that do not exactly match a
+// function, or function alias (see Contract.AddFunctionAlias), of the named contract
+// to the function or alias whose name matches ignoring case e.g. read to Read, so
+// that clients following the naming conventions of other languages can call Go
+// functions. Names matching more than one function ignoring case are not routed.
+func (cc *ContractChaincode) EnableCaseInsensitiveFunctions() {
+	cc.caseInsensitiveFunctions = true
+}
+
+// resolveFunctionName returns the name of the function of the contract the passed
+// function name routes to, or the name as passed if it routes to no function so that
+// it is passed to the unknown transaction as sent
+func (cc *ContractChaincode) resolveFunctionName(ns string, fn string) string {
+	functions := cc.contracts[ns].functions
+
+	if _, ok := functions[fn]; ok {
+		return fn
+	}
+
+	aliases := cc.contracts[ns].functionAliases
+
+	if function, ok := aliases[fn]; ok {
+		return function
+	}
+
+	if !cc.caseInsensitiveFunctions {
+		return fn
+	}
+
+	names := []string{}
+
+	for name := range functions {
+		names = append(names, name)
+	}
+
+	if function, ok := matchIgnoringCase(fn, names); ok {
+		return function
+	}
+
+	names = []string{}
+
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+
+	if alias, ok := matchIgnoringCase(fn, names); ok {
+		return aliases[alias]
+	}
+
+	return fn
+}
+
+// matchIgnoringCase returns the only name equal to fn ignoring case
+func matchIgnoringCase(fn string, names []string) (string, bool) {
+	sort.Strings(names)
+
+	matches := []string{}
+
+	for _, name := range names {
+		if strings.EqualFold(name, fn) {
+			matches = append(matches, name)
+		}
+	}
+
+	if len(matches) != 1 {
+		return "", false
+	}
+
+	return matches[0], true
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type caseClashContract struct {
+	Contract
+}
+
+func (ccc *caseClashContract) Read() string {
+	return "Read"
+}
+
+func (ccc *caseClashContract) READ() string {
+	return "READ"
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableCaseInsensitiveFunctions(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableCaseInsensitiveFunctions()
+
+	assert.True(t, cc.caseInsensitiveFunctions, "should enable case insensitive functions")
+}
+
+func TestAddFunctionAlias(t *testing.T) {
+	c := Contract{}
+
+	c.AddFunctionAlias("read", "ReturnsString")
+	c.AddFunctionAlias("get", "ReturnsInt")
+	assert.Equal(t, map[string]string{"read": "ReturnsString", "get": "ReturnsInt"}, c.functionAliases, "should add aliases")
+
+	// Should replace existing alias
+	c.AddFunctionAlias("read", "ReturnsInt")
+	assert.Equal(t, "ReturnsInt", c.functionAliases["read"], "should replace existing alias")
+}
+
+func TestGetFunctionAliases(t *testing.T) {
+	c := Contract{}
+
+	assert.Nil(t, c.GetFunctionAliases(), "should return nil when no aliases added")
+
+	c.functionAliases = map[string]string{"read": "Read"}
+
+	assert.Equal(t, map[string]string{"read": "Read"}, c.GetFunctionAliases(), "should return aliases")
+}
+
+func TestAddContractFunctionAliases(t *testing.T) {
+	mc := new(myContract)
+	mc.AddFunctionAlias("read", "ReturnsString")
+
+	// Should add aliases of contract
+	cc := convertC2CC(mc)
+	assert.Equal(t, map[string]string{"read": "ReturnsString"}, cc.contracts["myContract"].functionAliases, "should add aliases of contract")
+
+	// Should panic when function of alias not found
+	mc = new(myContract)
+	mc.AddFunctionAlias("read", "Missing")
+	assert.PanicsWithValue(t, "Cannot add alias read for function Missing. Function not found in contract myContract", func() { convertC2CC(mc) }, "should panic when function not found")
+}
+
+func TestResolveFunctionName(t *testing.T) {
+	mc := new(myContract)
+	mc.AddFunctionAlias("read", "ReturnsString")
+	mc.AddFunctionAlias("ReturnsInt", "ReturnsString")
+	cc := convertC2CC(mc, new(caseClashContract))
+
+	// Should return function name
+	assert.Equal(t, "ReturnsString", cc.resolveFunctionName("myContract", "ReturnsString"), "should return name of function")
+
+	// Should return function of alias
+	assert.Equal(t, "ReturnsString", cc.resolveFunctionName("myContract", "read"), "should return function of alias")
+
+	// Should prefer function over alias of same name
+	assert.Equal(t, "ReturnsInt", cc.resolveFunctionName("myContract", "ReturnsInt"), "should prefer function to alias")
+
+	// Should return name as passed when not case insensitive
+	assert.Equal(t, "returnsstring", cc.resolveFunctionName("myContract", "returnsstring"), "should not ignore case when not enabled")
+	assert.Equal(t, "READ", cc.resolveFunctionName("myContract", "READ"), "should not ignore case of alias when not enabled")
+
+	cc.EnableCaseInsensitiveFunctions()
+
+	// Should return function matching ignoring case
+	assert.Equal(t, "ReturnsString", cc.resolveFunctionName("myContract", "returnsstring"), "should match function ignoring case")
+
+	// Should return function of alias matching ignoring case
+	assert.Equal(t, "ReturnsString", cc.resolveFunctionName("myContract", "READ"), "should match alias ignoring case")
+
+	// Should return name as passed when matching multiple functions
+	assert.Equal(t, "Read", cc.resolveFunctionName("caseClashContract", "Read"), "should return exact match")
+	assert.Equal(t, "read", cc.resolveFunctionName("caseClashContract", "read"), "should not match multiple functions")
+
+	// Should return name as passed when unknown
+	assert.Equal(t, "Missing", cc.resolveFunctionName("myContract", "Missing"), "should return unknown name as passed")
+	assert.Equal(t, "Missing", cc.resolveFunctionName("missingContract", "Missing"), "should return name for unknown contract as passed")
+}
+
+func TestInvokeFunctionAliases(t *testing.T) {
+	mc := new(myContract)
+	mc.SetUnknownTransaction(mc.UnknownTransactionWithRequest)
+	mc.AddFunctionAlias("returnString", "ReturnsString")
+
+	cc := convertC2CC(mc)
+
+	// Should call function of alias
+	callContractFunctionAndCheckSuccess(t, cc, []string{"returnString"}, invokeType, "Some string")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:returnString"}, invokeType, "Some string")
+
+	// Should pass name as sent to unknown transaction
+	callContractFunctionAndCheckSuccess(t, cc, []string{"returnsstring"}, invokeType, "Unknown function returnsstring called with args []")
+
+	// Should call function matching ignoring case
+	cc.EnableCaseInsensitiveFunctions()
+	callContractFunctionAndCheckSuccess(t, cc, []string{"returnsstring"}, invokeType, "Some string")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"RETURNSTRING"}, invokeType, "Some string")
+}
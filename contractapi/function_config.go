@@ -0,0 +1,235 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FunctionConfig holds details of a contract function used when generating the
+// metadata of the contract. Setters return the config so that calls can be
+// chained e.g. c.ConfigureFunction("Read").SetEvaluate(true).SetDescription("...")
+type FunctionConfig struct {
+	evaluate              bool
+	readOnly              bool
+	description           string
+	parameterNames        []string
+	parameterDescriptions []string
+	parameterTags         []string
+	parameterDefaults     map[string]string
+	base64Bytes           bool
+	nilReturn             NilReturn
+	timeout               time.Duration
+}
+
+// SetEvaluate sets whether the function is intended to be evaluated, i.e. it only
+// queries the world state, rather than submitted. Evaluate functions are tagged
+// evaluateTx in the metadata instead of submitTx.
+func (fc *FunctionConfig) SetEvaluate(evaluate bool) *FunctionConfig {
+	fc.evaluate = evaluate
+	return fc
+}
+
+// SetDescription sets the description of the function in the metadata
+func (fc *FunctionConfig) SetDescription(description string) *FunctionConfig {
+	fc.description = description
+	return fc
+}
+
+// SetParameterNames sets the names of the parameters of the function in the
+// metadata, in order and excluding the transaction context. If set a name must
+// be passed for every parameter. Names are used in place of param0, param1 etc.
+func (fc *FunctionConfig) SetParameterNames(names ...string) *FunctionConfig {
+	fc.parameterNames = names
+	return fc
+}
+
+// SetParameterDescriptions sets the descriptions of the parameters of the function
+// in the metadata, in order and excluding the transaction context
+func (fc *FunctionConfig) SetParameterDescriptions(descriptions ...string) *FunctionConfig {
+	fc.parameterDescriptions = descriptions
+	return fc
+}
+
+// SetParameterTags sets tags for the parameters of the function, in order and
+// excluding the transaction context, in the format of struct field tags. A blank
+// tag may be passed for parameters that need none. The convert tag names the
+// converter applied to the arg passed for the parameter before it is deserialized
+// e.g. `convert:"csv"` (see AddConverter)
+func (fc *FunctionConfig) SetParameterTags(tags ...string) *FunctionConfig {
+	fc.parameterTags = tags
+	return fc
+}
+
+// SetDefault sets the value used for the named parameter when the arg for it is
+// omitted. The value is given as the arg would be passed e.g. "10" for an int and
+// is shown as the default of the parameter in the metadata. Parameters are named
+// as in the metadata, i.e. by SetParameterNames or as param0, param1 etc. Only
+// trailing args may be omitted so parameters following one with a default must
+// also have a default or be of a pointer type.
+func (fc *FunctionConfig) SetDefault(name string, value string) *FunctionConfig {
+	if fc.parameterDefaults == nil {
+		fc.parameterDefaults = make(map[string]string)
+	}
+
+	fc.parameterDefaults[name] = value
+	return fc
+}
+
+func (fc *FunctionConfig) parameterName(index int) string {
+	if index < len(fc.parameterNames) {
+		return fc.parameterNames[index]
+	}
+
+	return fmt.Sprintf("param%d", index)
+}
+
+func (fc *FunctionConfig) getDefault(index int) (string, bool) {
+	value, ok := fc.parameterDefaults[fc.parameterName(index)]
+
+	return value, ok
+}
+
+// validateDefaults checks that the defaults are for parameters of the function,
+// can be converted to the types of those parameters and are only followed by
+// parameters that may be omitted
+func (fc *FunctionConfig) validateDefaults(fn *contractFunction, serializer Serializer) error {
+	names := []string{}
+
+	for name := range fc.parameterDefaults {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !fc.hasParameter(name, len(fn.params.fields)) {
+			return fmt.Errorf("Cannot set default for parameter %s. Parameter not found", name)
+		}
+	}
+
+	firstDefault := -1
+
+	for i, field := range fn.params.fields {
+		value, ok := fc.getDefault(i)
+
+		if !ok {
+			if firstDefault != -1 && !isOptionalType(field) {
+				return fmt.Errorf("Parameter %s must have a default or be a pointer as it follows parameter %s which has a default", fc.parameterName(i), fc.parameterName(firstDefault))
+			}
+
+			continue
+		}
+
+		if _, err := getSerializer(serializer).FromString(value, field); err != nil {
+			return fmt.Errorf("Invalid default for parameter %s. %s", fc.parameterName(i), err.Error())
+		}
+
+		if firstDefault == -1 {
+			firstDefault = i
+		}
+	}
+
+	return nil
+}
+
+func (fc *FunctionConfig) hasParameter(name string, numParams int) bool {
+	for i := 0; i < numParams; i++ {
+		if fc.parameterName(i) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyDefaults returns the params with the defaults of omitted trailing params added
+func (fc *FunctionConfig) applyDefaults(numParams int, params []string) []string {
+	if len(fc.parameterDefaults) == 0 || len(params) >= numParams {
+		return params
+	}
+
+	withDefaults := make([]string, len(params), numParams)
+	copy(withDefaults, params)
+
+	for i := len(params); i < numParams; i++ {
+		value, ok := fc.getDefault(i)
+
+		if !ok {
+			break
+		}
+
+		withDefaults = append(withDefaults, value)
+	}
+
+	return withDefaults
+}
+
+// applyDefaultsTo marks parameters with defaults as not required in the metadata
+// and sets their defaults converted to the types of the parameters
+func (fc *FunctionConfig) applyDefaultsTo(transactionMetadata *TransactionMetadata, fn *contractFunction, serializer Serializer) {
+	for i := range transactionMetadata.Parameters {
+		value, ok := fc.getDefault(i)
+
+		if !ok || i >= len(fn.params.fields) {
+			continue
+		}
+
+		converted, err := getSerializer(serializer).FromString(value, fn.params.fields[i])
+
+		if err != nil {
+			continue
+		}
+
+		transactionMetadata.Parameters[i].Required = false
+		transactionMetadata.Parameters[i].Schema.Default = converted.Interface()
+	}
+}
+
+func (fc *FunctionConfig) applyTo(transactionMetadata *TransactionMetadata) {
+	transactionMetadata.Description = fc.description
+
+	if fc.evaluate || fc.readOnly {
+		for i, tag := range transactionMetadata.Tag {
+			if tag == "submitTx" {
+				transactionMetadata.Tag[i] = "evaluateTx"
+			}
+		}
+	}
+
+	if fc.readOnly {
+		transactionMetadata.Tag = append(transactionMetadata.Tag, ReadOnlyTag)
+	}
+
+	for i := range transactionMetadata.Parameters {
+		if i < len(fc.parameterNames) {
+			transactionMetadata.Parameters[i].Name = fc.parameterNames[i]
+		}
+
+		if i < len(fc.parameterDescriptions) {
+			transactionMetadata.Parameters[i].Description = fc.parameterDescriptions[i]
+		}
+
+		if fc.base64Bytes && transactionMetadata.Parameters[i].Schema.Format == BinaryFormat {
+			transactionMetadata.Parameters[i].Schema.Format = Base64Format
+		}
+	}
+
+	if fc.base64Bytes && transactionMetadata.Returns != nil && transactionMetadata.Returns.Format == BinaryFormat {
+		transactionMetadata.Returns.Format = Base64Format
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type defaultsTestContract struct {
+	Contract
+}
+
+func (dtc *defaultsTestContract) Update(id string, value string, count int) string {
+	return fmt.Sprintf("%s %s %d", id, value, count)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestFunctionConfigSetters(t *testing.T) {
+	fc := new(FunctionConfig)
+
+	// Should set each field and return config for chaining
+	returned := fc.SetEvaluate(true).SetDescription("some description").SetParameterNames("id", "value").SetParameterDescriptions("the id").SetParameterTags("", `convert:"csv"`)
+
+	assert.Equal(t, fc, returned, "should return config")
+	assert.True(t, fc.evaluate, "should set evaluate")
+	assert.Equal(t, "some description", fc.description, "should set description")
+	assert.Equal(t, []string{"id", "value"}, fc.parameterNames, "should set parameter names")
+	assert.Equal(t, []string{"the id"}, fc.parameterDescriptions, "should set parameter descriptions")
+	assert.Equal(t, []string{"", `convert:"csv"`}, fc.parameterTags, "should set parameter tags")
+
+	// Should set defaults
+	returned = fc.SetDefault("value", "Initialised").SetDefault("count", "1")
+	assert.Equal(t, fc, returned, "should return config when setting default")
+	assert.Equal(t, map[string]string{"value": "Initialised", "count": "1"}, fc.parameterDefaults, "should set defaults")
+}
+
+func TestFunctionConfigValidateDefaults(t *testing.T) {
+	var fc *FunctionConfig
+
+	fn := newContractFunctionFromFunc(new(defaultsTestContract).Update, basicContextPtrType)
+
+	// Should not error when no defaults
+	assert.Nil(t, new(FunctionConfig).validateDefaults(fn, nil), "should not error without defaults")
+
+	// Should not error for valid trailing defaults
+	fc = new(FunctionConfig).SetParameterNames("id", "value", "count").SetDefault("value", "Initialised").SetDefault("count", "1")
+	assert.Nil(t, fc.validateDefaults(fn, nil), "should not error for valid defaults")
+
+	// Should use generated parameter names when none set
+	fc = new(FunctionConfig).SetDefault("param2", "1")
+	assert.Nil(t, fc.validateDefaults(fn, nil), "should not error for default of generated parameter name")
+
+	// Should error when parameter not found
+	fc = new(FunctionConfig).SetParameterNames("id", "value", "count").SetDefault("missing", "1")
+	assert.EqualError(t, fc.validateDefaults(fn, nil), "Cannot set default for parameter missing. Parameter not found", "should error for unknown parameter")
+
+	// Should error when default cannot be converted
+	fc = new(FunctionConfig).SetParameterNames("id", "value", "count").SetDefault("count", "many")
+	assert.EqualError(t, fc.validateDefaults(fn, nil), "Invalid default for parameter count. Param many could not be converted to type int", "should error for bad default")
+
+	// Should error when parameter without default follows one with default
+	fc = new(FunctionConfig).SetParameterNames("id", "value", "count").SetDefault("value", "Initialised")
+	assert.EqualError(t, fc.validateDefaults(fn, nil), "Parameter count must have a default or be a pointer as it follows parameter value which has a default", "should error for required param following default")
+}
+
+func TestFunctionConfigApplyDefaults(t *testing.T) {
+	fc := new(FunctionConfig).SetParameterNames("id", "value", "count").SetDefault("value", "Initialised").SetDefault("count", "1")
+
+	// Should not change params when all passed
+	assert.Equal(t, []string{"a", "b", "2"}, fc.applyDefaults(3, []string{"a", "b", "2"}), "should not change params when all passed")
+
+	// Should add defaults for omitted trailing params
+	assert.Equal(t, []string{"a", "b", "1"}, fc.applyDefaults(3, []string{"a", "b"}), "should add default for last param")
+	assert.Equal(t, []string{"a", "Initialised", "1"}, fc.applyDefaults(3, []string{"a"}), "should add defaults for omitted params")
+
+	// Should stop at param without default
+	assert.Equal(t, []string{}, fc.applyDefaults(3, []string{}), "should not add defaults after param without default")
+
+	// Should not change params when no defaults
+	assert.Equal(t, []string{"a"}, new(FunctionConfig).applyDefaults(3, []string{"a"}), "should not change params when no defaults")
+}
+
+func TestFunctionConfigApplyDefaultsTo(t *testing.T) {
+	fn := newContractFunctionFromFunc(new(defaultsTestContract).Update, basicContextPtrType)
+	fc := new(FunctionConfig).SetDefault("param1", "Initialised").SetDefault("param2", "1")
+
+	tm := TransactionMetadata{Parameters: []ParameterMetadata{{Name: "param0", Required: true}, {Name: "param1", Required: true}, {Name: "param2", Required: true}}}
+	fc.applyDefaultsTo(&tm, fn, nil)
+
+	// Should leave params without defaults unchanged
+	assert.True(t, tm.Parameters[0].Required, "should leave param without default required")
+	assert.Nil(t, tm.Parameters[0].Schema.Default, "should not set default for param without one")
+
+	// Should mark params with defaults as not required and set converted default
+	assert.False(t, tm.Parameters[1].Required, "should not require param with default")
+	assert.Equal(t, "Initialised", tm.Parameters[1].Schema.Default, "should set string default")
+	assert.False(t, tm.Parameters[2].Required, "should not require int param with default")
+	assert.Equal(t, 1, tm.Parameters[2].Schema.Default, "should set int default as int")
+}
+
+func TestFunctionConfigApplyTo(t *testing.T) {
+	var tm TransactionMetadata
+
+	// Should leave metadata unchanged by empty config
+	tm = TransactionMetadata{Name: "Read", Tag: []string{"submitTx"}, Parameters: []ParameterMetadata{{Name: "param0"}}}
+	new(FunctionConfig).applyTo(&tm)
+	assert.Equal(t, TransactionMetadata{Name: "Read", Tag: []string{"submitTx"}, Parameters: []ParameterMetadata{{Name: "param0"}}}, tm, "should not change metadata")
+
+	// Should apply config to metadata
+	fc := new(FunctionConfig).SetEvaluate(true).SetDescription("reads an asset").SetParameterNames("id", "owner").SetParameterDescriptions("the id")
+	tm = TransactionMetadata{Name: "Read", Tag: []string{"submitTx"}, Parameters: []ParameterMetadata{{Name: "param0"}, {Name: "param1"}}}
+	fc.applyTo(&tm)
+	assert.Equal(t, TransactionMetadata{Name: "Read", Description: "reads an asset", Tag: []string{"evaluateTx"}, Parameters: []ParameterMetadata{{Name: "id", Description: "the id"}, {Name: "owner"}}}, tm, "should apply config")
+}
+
+func TestFunctionConfigMetadata(t *testing.T) {
+	mc := myContract{}
+	mc.ConfigureFunction("UsesContext").SetEvaluate(true).SetDescription("uses the context").SetParameterNames("assetID", "value")
+	cc := convertC2CC(&mc)
+
+	var tm TransactionMetadata
+	for _, v := range cc.metadata.Contracts["myContract"].Transactions {
+		if v.Name == "UsesContext" {
+			tm = v
+		}
+	}
+
+	// Should apply config when generating metadata
+	assert.Equal(t, "uses the context", tm.Description, "should set description in metadata")
+	assert.Equal(t, []string{"evaluateTx"}, tm.Tag, "should tag as evaluate")
+	assert.Equal(t, "assetID", tm.Parameters[0].Name, "should name first parameter")
+	assert.Equal(t, "value", tm.Parameters[1].Name, "should name second parameter")
+
+	// Should panic when configured function does not exist
+	mc = myContract{}
+	mc.ConfigureFunction("Missing")
+	assert.PanicsWithValue(t, "Cannot configure function Missing. Function not found in contract myContract", func() { convertC2CC(&mc) }, "should panic for missing function")
+
+	// Should panic when wrong number of parameter names
+	mc = myContract{}
+	mc.ConfigureFunction("UsesContext").SetParameterNames("assetID")
+	assert.PanicsWithValue(t, "Function UsesContext of contract myContract configured with 1 parameter names. Expected 2", func() { convertC2CC(&mc) }, "should panic for wrong number of parameter names")
+
+	// Should panic when wrong number of parameter tags
+	mc = myContract{}
+	mc.ConfigureFunction("UsesContext").SetParameterTags(`convert:"csv"`)
+	assert.PanicsWithValue(t, "Function UsesContext of contract myContract configured with 1 parameter tags. Expected 2", func() { convertC2CC(&mc) }, "should panic for wrong number of parameter tags")
+
+	// Should panic when defaults invalid
+	mc = myContract{}
+	mc.ConfigureFunction("UsesContext").SetDefault("param0", "1")
+	assert.PanicsWithValue(t, "Function UsesContext of contract myContract configured with invalid defaults. Parameter param1 must have a default or be a pointer as it follows parameter param0 which has a default", func() { convertC2CC(&mc) }, "should panic for invalid defaults")
+}
+
+func TestFunctionConfigDefaults(t *testing.T) {
+	dtc := new(defaultsTestContract)
+	dtc.ConfigureFunction("Update").SetParameterNames("id", "value", "count").SetDefault("value", "Initialised").SetDefault("count", "1")
+	cc := convertC2CC(dtc)
+
+	// Should show defaults in metadata
+	params := cc.metadata.Contracts["defaultsTestContract"].Transactions[0].Parameters
+	assert.True(t, params[0].Required, "should require param without default")
+	assert.False(t, params[1].Required, "should not require param with default")
+	assert.Equal(t, "Initialised", params[1].Schema.Default, "should show default in metadata")
+	assert.Equal(t, 1, params[2].Schema.Default, "should show int default in metadata")
+
+	// Should use defaults for omitted args
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "a"}, invokeType, "a Initialised 1")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "a", "b"}, invokeType, "a b 1")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Update", "a", "b", "2"}, invokeType, "a b 2")
+
+	// Should error when required arg omitted
+	callContractFunctionAndCheckError(t, cc, []string{"Update"}, invokeType, "Incorrect number of params for function Update. Expected 3, received 0. Function signature is Update(id string, value string, count int)")
+}
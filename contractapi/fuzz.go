@@ -0,0 +1,47 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+)
+
+// FuzzTxID the transaction ID used for invocations by FuzzInvoke
+const FuzzTxID = "fuzz"
+
+// FuzzInvoke invokes the chaincode against a new mock stub using args formed by
+// splitting the data at each null byte, the first being the function name e.g.
+// "myContract:Transfer\x00alice\x00100". It is intended to be returned by the
+// Fuzz function of a package built with go-fuzz, or libFuzzer, to find inputs
+// which cause the routing of transactions and the conversion of their args to
+// panic. Panics are not recovered as they are by Invoke so that the fuzzer
+// records them as crashes. Returns 1 when the invocation succeeds, so that the
+// fuzzer gives priority to inputs reaching contract functions, and 0 otherwise.
+func FuzzInvoke(cc *ContractChaincode, data []byte) int {
+	fuzzed := *cc
+	fuzzed.propagatePanics = true
+
+	stub := shimtest.NewMockStub("fuzz", &fuzzed)
+
+	response := stub.MockInvoke(FuzzTxID, bytes.Split(data, []byte{0}))
+
+	if response.Status == 200 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestFuzzInvoke(t *testing.T) {
+	_, restore := captureLogs()
+	defer restore()
+
+	cc := convertC2CC(new(panicContract), new(myContract), new(mapTestContract))
+
+	// Should return 1 when invocation succeeds
+	assert.Equal(t, 1, FuzzInvoke(&cc, []byte("panicContract:Succeed")), "should return 1 for successful invocation")
+	assert.Equal(t, 1, FuzzInvoke(&cc, []byte("myContract:UsesArray\x00[\"a\"]")), "should split args at null bytes")
+
+	// Should return 0 when invocation fails
+	assert.Equal(t, 0, FuzzInvoke(&cc, []byte("myContract:UsesArray\x00not json")), "should return 0 for bad args")
+	assert.Equal(t, 0, FuzzInvoke(&cc, []byte("missing:Function")), "should return 0 for missing contract")
+	assert.Equal(t, 0, FuzzInvoke(&cc, []byte{}), "should return 0 for no data")
+
+	// Should not recover panics
+	assert.PanicsWithValue(t, "panicked with value", func() { FuzzInvoke(&cc, []byte("panicContract:Panic\x00value")) }, "should not recover panic")
+
+	// Should not change chaincode passed
+	stub := shimtest.NewMockStub("fuzzTest", &cc)
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("panicContract:Panic"), []byte("value")})
+	assert.Equal(t, int32(500), response.Status, "should still recover panics of chaincode passed")
+
+	// Should not panic converting arbitrary args
+	fns := []string{"myContract:UsesBasics", "myContract:UsesArray", "myContract:UsesSlices", "mapTestContract:UsesStringMap", "mapTestContract:UsesIntMap", "mapTestContract:UsesStructMap"}
+	fragments := []string{"", "a", "1", "-1", "1.5", "true", "null", "[", "]", "{", "}", "\"", ":", ",", "\x00", "\xff", "9999999999999999999999"}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		data := fns[r.Intn(len(fns))]
+
+		for j := r.Intn(20); j > 0; j-- {
+			data += fragments[r.Intn(len(fragments))]
+		}
+
+		assert.NotPanics(t, func() { FuzzInvoke(&cc, []byte(data)) }, "should not panic for "+strings.Replace(data, "\x00", "\\x00", -1))
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"reflect"
+)
+
+var goContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// contextProvider is met by TransactionContext and therefore by custom transaction
+// contexts that embed it
+type contextProvider interface {
+	Context() context.Context
+}
+
+// contextValues returns the values passed for the context params of a function,
+// the context.Context of the transaction (see TransactionContext.Context) if the
+// function takes one and the transaction context if it takes it. Transaction
+// contexts that do not provide a context.Context pass context.Background().
+func (cfp contractFunctionParams) contextValues(ctx reflect.Value) []reflect.Value {
+	values := []reflect.Value{}
+
+	if cfp.goContext {
+		goContext := context.Background()
+
+		if ctx.IsValid() {
+			if cp, ok := ctx.Interface().(contextProvider); ok {
+				goContext = cp.Context()
+			}
+		}
+
+		values = append(values, reflect.ValueOf(&goContext).Elem())
+	}
+
+	if cfp.context != nil {
+		values = append(values, ctx)
+	}
+
+	return values
+}
@@ -0,0 +1,159 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type goContextContract struct {
+	Contract
+}
+
+func (gcc *goContextContract) TakesBoth(goCtx context.Context, ctx *TransactionContext, value string) (string, error) {
+	goDeadline, goOk := goCtx.Deadline()
+	deadline, ok := ctx.GetDeadline()
+
+	return fmt.Sprintf("%s %t %t", value, goOk == ok, goDeadline.Equal(deadline)), nil
+}
+
+func (gcc *goContextContract) TakesGoContext(goCtx context.Context) bool {
+	return goCtx != nil
+}
+
+func (gcc *goContextContract) ContextBefore(goCtx context.Context, ctx *TransactionContext) {
+	ctx.SetData("before", goCtx == ctx.Context())
+}
+
+func (gcc *goContextContract) ReadBefore(ctx *TransactionContext) bool {
+	before, _ := ctx.GetData("before")
+
+	return before.(bool)
+}
+
+type badGoContextContract struct{}
+
+func (bgcc *badGoContextContract) GoContextSecond(value string, goCtx context.Context) {}
+
+func (bgcc *badGoContextContract) ContextFirst(ctx *TransactionContext, goCtx context.Context) {}
+
+func (bgcc *badGoContextContract) ContextThird(goCtx context.Context, value string, ctx *TransactionContext) {
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestMethod2ContractFunctionParamsGoContext(t *testing.T) {
+	var params contractFunctionParams
+	var err error
+
+	gcc := new(goContextContract)
+	bgcc := new(badGoContextContract)
+
+	// Should take context.Context before transaction context
+	method, _ := reflect.TypeOf(gcc).MethodByName("TakesBoth")
+	params, err = method2ContractFunctionParams(method, basicContextPtrType)
+	assert.Nil(t, err, "should not error for context.Context first")
+	assert.Equal(t, contractFunctionParams{basicContextPtrType, []reflect.Type{reflect.TypeOf("")}, true}, params, "should take context.Context and transaction context")
+
+	// Should take context.Context without transaction context
+	method, _ = reflect.TypeOf(gcc).MethodByName("TakesGoContext")
+	params, err = method2ContractFunctionParams(method, basicContextPtrType)
+	assert.Nil(t, err, "should not error for context.Context only")
+	assert.Equal(t, contractFunctionParams{nil, nil, true}, params, "should take context.Context only")
+
+	// Should error when context.Context not first
+	method, _ = reflect.TypeOf(bgcc).MethodByName("GoContextSecond")
+	_, err = method2ContractFunctionParams(method, basicContextPtrType)
+	assert.EqualError(t, err, "Functions requiring a context.Context must require it as the first parameter. GoContextSecond takes it in as parameter 1", "should error when context.Context not first")
+
+	method, _ = reflect.TypeOf(bgcc).MethodByName("ContextFirst")
+	_, err = method2ContractFunctionParams(method, basicContextPtrType)
+	assert.EqualError(t, err, "Functions requiring a context.Context must require it as the first parameter. ContextFirst takes it in as parameter 1", "should error when context.Context follows transaction context")
+
+	// Should error when transaction context does not follow context.Context
+	method, _ = reflect.TypeOf(bgcc).MethodByName("ContextThird")
+	_, err = method2ContractFunctionParams(method, basicContextPtrType)
+	assert.EqualError(t, err, "Functions requiring the TransactionContext must require it as the first parameter, or second following a context.Context. ContextThird takes it in as parameter 2", "should error when transaction context not after context.Context")
+}
+
+func TestContextValues(t *testing.T) {
+	var values []reflect.Value
+
+	ctx := new(TransactionContext)
+	txContext, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	ctx.setTransactionDetails(transactionDetails{context: txContext})
+
+	// Should pass nothing when no context params
+	values = contractFunctionParams{}.contextValues(reflect.ValueOf(ctx))
+	assert.Len(t, values, 0, "should pass no values without context params")
+
+	// Should pass transaction context
+	values = contractFunctionParams{context: basicContextPtrType}.contextValues(reflect.ValueOf(ctx))
+	assert.Equal(t, []interface{}{ctx}, []interface{}{values[0].Interface()}, "should pass transaction context")
+
+	// Should pass context.Context of transaction context
+	values = contractFunctionParams{context: basicContextPtrType, goContext: true}.contextValues(reflect.ValueOf(ctx))
+	assert.Len(t, values, 2, "should pass both contexts")
+	assert.Equal(t, goContextType, values[0].Type(), "should pass value of type context.Context")
+	assert.Equal(t, txContext, values[0].Interface(), "should pass context of transaction context")
+	assert.Equal(t, ctx, values[1].Interface(), "should pass transaction context second")
+
+	// Should pass background context when transaction context provides none
+	values = contractFunctionParams{goContext: true}.contextValues(reflect.ValueOf(struct{}{}))
+	assert.Equal(t, context.Background(), values[0].Interface(), "should pass background context when not provided")
+
+	values = contractFunctionParams{goContext: true}.contextValues(reflect.Value{})
+	assert.Equal(t, context.Background(), values[0].Interface(), "should pass background context without transaction context")
+}
+
+func TestInvokeWithGoContext(t *testing.T) {
+	gcc := new(goContextContract)
+	gcc.SetBeforeTransaction(gcc.ContextBefore)
+
+	cc := convertC2CC(gcc)
+
+	// Should pass context.Context to function
+	callContractFunctionAndCheckSuccess(t, cc, []string{"TakesGoContext"}, invokeType, "true")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"TakesBoth", "value"}, invokeType, "value true true")
+
+	// Should pass context with deadline of transaction
+	cc.SetTransactionTimeout(time.Hour)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"TakesBoth", "value"}, invokeType, "value true true")
+
+	// Should pass context.Context to before transaction
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ReadBefore"}, invokeType, "true")
+
+	// Should not include context.Context in metadata
+	assert.Len(t, cc.metadata.Contracts["goContextContract"].Transactions, 4, "should include functions taking context.Context")
+
+	for _, tx := range cc.metadata.Contracts["goContextContract"].Transactions {
+		if tx.Name == "TakesBoth" {
+			assert.Len(t, tx.Parameters, 1, "should not include context params in metadata")
+		}
+	}
+}
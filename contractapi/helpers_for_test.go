@@ -15,15 +15,29 @@
 package contractapi
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-openapi/spec"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/attrmgr"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	"github.com/stretchr/testify/assert"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -88,8 +102,8 @@ func testContractChaincodeContractRepresentsContract(t *testing.T, ccns contract
 
 	assert.Equal(t, len(expectedSimpleContractFuncs), len(ccns.functions), "should only have one function as simpleTestContract")
 
-	assert.Equal(t, ccns.functions["DoSomething"].params, contractFunctionParams{nil, nil}, "should set correct params for contract function")
-	assert.Equal(t, ccns.functions["DoSomething"].returns, contractFunctionReturns{stringRefType, true}, "should set correct returns for contract function")
+	assert.Equal(t, ccns.functions["DoSomething"].params, contractFunctionParams{nil, nil, false}, "should set correct params for contract function")
+	assert.Equal(t, ccns.functions["DoSomething"].returns, contractFunctionReturns{stringRefType, true, false}, "should set correct returns for contract function")
 
 	transactionContextHandler := reflect.ValueOf(contract.GetTransactionContextHandler()).Elem().Type()
 	transactionContextPtrHandler := reflect.ValueOf(contract.GetTransactionContextHandler()).Type()
@@ -150,6 +164,114 @@ func testMetadata(t *testing.T, ccMetadata ContractChaincodeMetadata, expectedMe
 	assert.Equal(t, expectedMetadata, ccMetadata, "Should match expected metadata")
 }
 
+func createCreator(mspID string, attrs map[string]string) []byte {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "someuser"},
+		Issuer:       pkix.Name{CommonName: "someca"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	if attrs != nil {
+		attrBytes, _ := json.Marshal(map[string]interface{}{"attrs": attrs})
+		template.ExtraExtensions = []pkix.Extension{{Id: attrmgr.AttrOID, Value: attrBytes}}
+	}
+
+	certBytes, _ := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})
+
+	creator, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: certPEM})
+
+	return creator
+}
+
+type stateErrorTestStub struct {
+	*shimtest.MockStub
+	getErr       error
+	putErr       error
+	delErr       error
+	reads        []string
+	transient    map[string][]byte
+	transientErr error
+}
+
+func (ses *stateErrorTestStub) GetState(key string) ([]byte, error) {
+	ses.reads = append(ses.reads, key)
+
+	if ses.getErr != nil {
+		return nil, ses.getErr
+	}
+
+	return ses.MockStub.GetState(key)
+}
+
+func (ses *stateErrorTestStub) PutState(key string, value []byte) error {
+	if ses.putErr != nil {
+		return ses.putErr
+	}
+
+	return ses.MockStub.PutState(key, value)
+}
+
+func (ses *stateErrorTestStub) DelState(key string) error {
+	if ses.delErr != nil {
+		return ses.delErr
+	}
+
+	return ses.MockStub.DelState(key)
+}
+
+func (ses *stateErrorTestStub) GetTransient() (map[string][]byte, error) {
+	return ses.transient, ses.transientErr
+}
+
+// testContextOption configures the stub or details of the context returned by
+// newStateTestContext
+type testContextOption func(ctx *TransactionContext, stub *stateErrorTestStub)
+
+func withTxID(txID string) testContextOption {
+	return func(ctx *TransactionContext, stub *stateErrorTestStub) {
+		stub.TxID = txID
+	}
+}
+
+func withTxTimestamp(txTimestamp *timestamp.Timestamp) testContextOption {
+	return func(ctx *TransactionContext, stub *stateErrorTestStub) {
+		stub.TxTimestamp = txTimestamp
+	}
+}
+
+func withTransient(transient map[string][]byte) testContextOption {
+	return func(ctx *TransactionContext, stub *stateErrorTestStub) {
+		stub.transient = transient
+	}
+}
+
+func withTransactionDetails(details transactionDetails) testContextOption {
+	return func(ctx *TransactionContext, stub *stateErrorTestStub) {
+		ctx.setTransactionDetails(details)
+	}
+}
+
+func newStateTestContext(options ...testContextOption) (*TransactionContext, *stateErrorTestStub) {
+	stub := shimtest.NewMockStub("stateTest", nil)
+	stub.MockTransactionStart(standardTxID)
+
+	testStub := &stateErrorTestStub{MockStub: stub}
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(testStub)
+
+	for _, option := range options {
+		option(ctx, testStub)
+	}
+
+	return ctx, testStub
+}
+
 func createMetadataJSONFile(data []byte, permissions os.FileMode) string {
 	ex, _ := os.Executable()
 	exPath := filepath.Dir(ex)
@@ -226,6 +348,10 @@ func (mc *myContract) UnknownTransaction(ctx *TransactionContext) (string, error
 	return "some unknown transaction", errors.New("Some unknown error")
 }
 
+func (mc *myContract) UnknownTransactionWithRequest(ctx *TransactionContext, fn string, args []string) (string, error) {
+	return fmt.Sprintf("Unknown function %s called with args %v", fn, args), nil
+}
+
 func (mc *myContract) AfterTransaction(ctx *TransactionContext) (string, error) {
 	return "some after transaction", errors.New("some after error")
 }
@@ -304,6 +430,22 @@ func (mc *myContract) ReturnsNil() error {
 
 func (mc *myContract) ReturnsNothing() {}
 
+type mapTestContract struct {
+	Contract
+}
+
+func (mtc *mapTestContract) UsesStringMap(args map[string]string) map[string]string {
+	return args
+}
+
+func (mtc *mapTestContract) UsesIntMap(args map[string]int) (map[string]int, error) {
+	return args, nil
+}
+
+func (mtc *mapTestContract) UsesStructMap(args map[string]*GoodStruct) map[string]*GoodStruct {
+	return args
+}
+
 type simpleTestContract struct {
 	Contract
 }
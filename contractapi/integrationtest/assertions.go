@@ -0,0 +1,78 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrationtest
+
+import (
+	"strings"
+	"testing"
+)
+
+// RunSimpleQuery queries the chaincode with the passed args and fails the test
+// if the query errors or its output does not contain the expected result
+func RunSimpleQuery(t testing.TB, n *Network, args []string, expectedResult string) {
+	t.Helper()
+
+	output, err := n.Query(args...)
+
+	if err != nil {
+		t.Fatalf("Query %s failed. %s", strings.Join(args, " "), err.Error())
+	}
+
+	if !strings.Contains(output, expectedResult) {
+		t.Fatalf("Query %s returned %q. Expected it to contain %q", strings.Join(args, " "), output, expectedResult)
+	}
+}
+
+// RunSimpleBadQuery queries the chaincode with the passed args and fails the test
+// if the query succeeds or its error does not contain the expected result
+func RunSimpleBadQuery(t testing.TB, n *Network, args []string, expectedResult string) {
+	t.Helper()
+
+	_, err := n.Query(args...)
+
+	if err == nil {
+		t.Fatalf("Query %s succeeded. Expected it to fail with %q", strings.Join(args, " "), expectedResult)
+	}
+
+	if !strings.Contains(err.Error(), expectedResult) {
+		t.Fatalf("Query %s failed with %q. Expected it to contain %q", strings.Join(args, " "), err.Error(), expectedResult)
+	}
+}
+
+// RunSimpleInvoke invokes the chaincode with the passed args and fails the test
+// if the invoke errors
+func RunSimpleInvoke(t testing.TB, n *Network, args []string) {
+	t.Helper()
+
+	if err := n.Invoke(args...); err != nil {
+		t.Fatalf("Invoke %s failed. %s", strings.Join(args, " "), err.Error())
+	}
+}
+
+// RunSimpleBadInvoke invokes the chaincode with the passed args and fails the
+// test if the invoke succeeds or its error does not contain the expected result
+func RunSimpleBadInvoke(t testing.TB, n *Network, args []string, expectedResult string) {
+	t.Helper()
+
+	err := n.Invoke(args...)
+
+	if err == nil {
+		t.Fatalf("Invoke %s succeeded. Expected it to fail with %q", strings.Join(args, " "), expectedResult)
+	}
+
+	if !strings.Contains(err.Error(), expectedResult) {
+		t.Fatalf("Invoke %s failed with %q. Expected it to contain %q", strings.Join(args, " "), err.Error(), expectedResult)
+	}
+}
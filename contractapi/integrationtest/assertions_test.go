@@ -0,0 +1,131 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrationtest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (rt *recordingT) Helper() {}
+
+// Fatalf records the failure and stops the goroutine as testing.T does
+func (rt *recordingT) Fatalf(format string, args ...interface{}) {
+	rt.failures = append(rt.failures, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// Fatal records the failure and stops the goroutine as testing.T does
+func (rt *recordingT) Fatal(args ...interface{}) {
+	rt.failures = append(rt.failures, fmt.Sprint(args...))
+	runtime.Goexit()
+}
+
+func runAssertion(assertion func(t testing.TB)) []string {
+	rt := new(recordingT)
+	done := make(chan bool)
+
+	go func() {
+		defer close(done)
+		assertion(rt)
+	}()
+
+	<-done
+
+	return rt.failures
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestRunSimpleQuery(t *testing.T) {
+	n, fc := newTestNetwork(Config{ContainerName: "peer"})
+	fc.outputs["docker exec peer peer chaincode query"] = "Initialised\n"
+
+	// Should pass when output contains result
+	failures := runAssertion(func(rt testing.TB) { RunSimpleQuery(rt, n, []string{"Read", "ASSET_1"}, "Initialised") })
+	assert.Len(t, failures, 0, "should pass when output contains result")
+
+	// Should fail when output does not contain result
+	failures = runAssertion(func(rt testing.TB) { RunSimpleQuery(rt, n, []string{"Read", "ASSET_1"}, "Updated") })
+	assert.Equal(t, []string{"Query Read ASSET_1 returned \"Initialised\". Expected it to contain \"Updated\""}, failures, "should fail when output does not contain result")
+
+	// Should fail when query errors
+	fc.failures["docker exec"] = 1
+	failures = runAssertion(func(rt testing.TB) { RunSimpleQuery(rt, n, []string{"Read", "ASSET_1"}, "Initialised") })
+	assert.Equal(t, []string{"Query Read ASSET_1 failed. docker exec failed"}, failures, "should fail when query errors")
+}
+
+func TestRunSimpleBadQuery(t *testing.T) {
+	n, fc := newTestNetwork(Config{ContainerName: "peer"})
+
+	// Should pass when error contains result
+	fc.failures["docker exec"] = 1
+	failures := runAssertion(func(rt testing.TB) { RunSimpleBadQuery(rt, n, []string{"Read", "ASSET_2"}, "exec failed") })
+	assert.Len(t, failures, 0, "should pass when error contains result")
+
+	// Should fail when error does not contain result
+	fc.failures["docker exec"] = 1
+	failures = runAssertion(func(rt testing.TB) { RunSimpleBadQuery(rt, n, []string{"Read", "ASSET_2"}, "does not exist") })
+	assert.Equal(t, []string{"Query Read ASSET_2 failed with \"docker exec failed\". Expected it to contain \"does not exist\""}, failures, "should fail when error does not contain result")
+
+	// Should fail when query succeeds
+	failures = runAssertion(func(rt testing.TB) { RunSimpleBadQuery(rt, n, []string{"Read", "ASSET_2"}, "does not exist") })
+	assert.Equal(t, []string{"Query Read ASSET_2 succeeded. Expected it to fail with \"does not exist\""}, failures, "should fail when query succeeds")
+}
+
+func TestRunSimpleInvoke(t *testing.T) {
+	n, fc := newTestNetwork(Config{ContainerName: "peer"})
+
+	// Should pass when invoke succeeds
+	failures := runAssertion(func(rt testing.TB) { RunSimpleInvoke(rt, n, []string{"Create", "ASSET_1"}) })
+	assert.Len(t, failures, 0, "should pass when invoke succeeds")
+
+	// Should fail when invoke errors
+	fc.failures["docker exec"] = 1
+	failures = runAssertion(func(rt testing.TB) { RunSimpleInvoke(rt, n, []string{"Create", "ASSET_1"}) })
+	assert.Equal(t, []string{"Invoke Create ASSET_1 failed. docker exec failed"}, failures, "should fail when invoke errors")
+}
+
+func TestRunSimpleBadInvoke(t *testing.T) {
+	n, fc := newTestNetwork(Config{ContainerName: "peer"})
+
+	// Should pass when error contains result
+	fc.failures["docker exec"] = 1
+	failures := runAssertion(func(rt testing.TB) { RunSimpleBadInvoke(rt, n, []string{"Update", "ASSET_1", "95"}, "exec failed") })
+	assert.Len(t, failures, 0, "should pass when error contains result")
+
+	// Should fail when error does not contain result
+	fc.failures["docker exec"] = 1
+	failures = runAssertion(func(rt testing.TB) { RunSimpleBadInvoke(rt, n, []string{"Update", "ASSET_1", "95"}, "schema") })
+	assert.Equal(t, []string{"Invoke Update ASSET_1 95 failed with \"docker exec failed\". Expected it to contain \"schema\""}, failures, "should fail when error does not contain result")
+
+	// Should fail when invoke succeeds
+	failures = runAssertion(func(rt testing.TB) { RunSimpleBadInvoke(rt, n, []string{"Update", "ASSET_1", "95"}, "schema") })
+	assert.Equal(t, []string{"Invoke Update ASSET_1 95 succeeded. Expected it to fail with \"schema\""}, failures, "should fail when invoke succeeds")
+}
@@ -0,0 +1,344 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package integrationtest provides a lightweight network, a single peer running
+// in a docker container in development mode, for testing chaincode built using
+// contractapi against a real peer without the Fabric test framework
+package integrationtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults used for fields of the config that are not set
+const (
+	DefaultChannel              = "mychannel"
+	DefaultChaincodeName        = "mycc"
+	DefaultChaincodeVersion     = "0"
+	DefaultPeerChaincodeAddress = "localhost:7052"
+	DefaultStartTimeout         = time.Minute
+	DefaultTransactionTimeout   = 30 * time.Second
+)
+
+const containerNamePrefix = "contractapi-integrationtest-"
+
+// readyCheckInterval the time waited between checks of whether the peer is ready
+var readyCheckInterval = time.Second
+
+// Config describes the container to start and the chaincode to run against it.
+// The image must run a peer started with --peer-chaincodedev, along with an
+// orderer, and have the peer CLI configured to use them e.g. the images used by
+// the chaincode-docker-devmode sample or an image built on them. SetupCommands
+// are run using the peer CLI in the container, in order, once the chaincode is
+// running and are typically used to create and join the channel and to install
+// and instantiate the chaincode. Ports are published from the container as
+// host:container pairs, such as 7052:7052 for the chaincode to connect to the
+// peer.
+type Config struct {
+	Image                string
+	ContainerName        string
+	Env                  map[string]string
+	Ports                []string
+	Channel              string
+	Orderer              string
+	ChaincodeName        string
+	ChaincodeVersion     string
+	ChaincodePath        string
+	PeerChaincodeAddress string
+	SetupCommands        [][]string
+	StartTimeout         time.Duration
+	TransactionTimeout   time.Duration
+}
+
+// Network is a single peer in a docker container with a chaincode, run locally
+// in development mode, connected to it
+type Network struct {
+	config    Config
+	started   bool
+	chaincode *exec.Cmd
+	buildDir  string
+	run       func(timeout time.Duration, name string, args ...string) (string, error)
+	start     func(env []string, name string, args ...string) (*exec.Cmd, error)
+}
+
+// Start starts the container of the config, waits for its peer to be ready,
+// builds and runs the chaincode at the path of the config, if set, and runs the
+// setup commands. Where the chaincode path is not set the chaincode must be
+// started separately, for example using the ChaincodeEnv of the network. The
+// network should be stopped once done with, including when Start errors.
+func Start(config Config) (*Network, error) {
+	n := newNetwork(config)
+
+	return n, n.startNetwork()
+}
+
+func newNetwork(config Config) *Network {
+	if config.ContainerName == "" {
+		config.ContainerName = fmt.Sprintf("%s%d", containerNamePrefix, time.Now().UnixNano())
+	}
+
+	if config.Channel == "" {
+		config.Channel = DefaultChannel
+	}
+
+	if config.ChaincodeName == "" {
+		config.ChaincodeName = DefaultChaincodeName
+	}
+
+	if config.ChaincodeVersion == "" {
+		config.ChaincodeVersion = DefaultChaincodeVersion
+	}
+
+	if config.PeerChaincodeAddress == "" {
+		config.PeerChaincodeAddress = DefaultPeerChaincodeAddress
+	}
+
+	if config.StartTimeout == 0 {
+		config.StartTimeout = DefaultStartTimeout
+	}
+
+	if config.TransactionTimeout == 0 {
+		config.TransactionTimeout = DefaultTransactionTimeout
+	}
+
+	return &Network{config: config, run: runCommand, start: startCommand}
+}
+
+func (n *Network) startNetwork() error {
+	if n.config.Image == "" {
+		return errors.New("Config must set the image of the container to start")
+	}
+
+	args := []string{"run", "--detach", "--name", n.config.ContainerName}
+
+	for _, port := range n.config.Ports {
+		args = append(args, "--publish", port)
+	}
+
+	for _, key := range sortedKeys(n.config.Env) {
+		args = append(args, "--env", key+"="+n.config.Env[key])
+	}
+
+	_, err := n.run(n.config.StartTimeout, "docker", append(args, n.config.Image)...)
+
+	if err != nil {
+		return fmt.Errorf("Failed to start container. %s", err.Error())
+	}
+
+	n.started = true
+
+	err = n.waitForPeer()
+
+	if err != nil {
+		return err
+	}
+
+	if n.config.ChaincodePath != "" {
+		err = n.startChaincode()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, command := range n.config.SetupCommands {
+		_, err = n.Peer(n.config.StartTimeout, command...)
+
+		if err != nil {
+			return fmt.Errorf("Failed to set up network. %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (n *Network) waitForPeer() error {
+	deadline := time.Now().Add(n.config.StartTimeout)
+
+	for {
+		_, err := n.Peer(n.config.StartTimeout, "node", "status")
+
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Peer was not ready within %s. %s", n.config.StartTimeout.String(), err.Error())
+		}
+
+		time.Sleep(readyCheckInterval)
+	}
+}
+
+func (n *Network) startChaincode() error {
+	buildDir, err := ioutil.TempDir("", "integrationtest")
+
+	if err != nil {
+		return fmt.Errorf("Failed to create directory to build chaincode. %s", err.Error())
+	}
+
+	n.buildDir = buildDir
+	binary := filepath.Join(buildDir, "chaincode")
+
+	_, err = n.run(n.config.StartTimeout, "go", "build", "-o", binary, n.config.ChaincodePath)
+
+	if err != nil {
+		return fmt.Errorf("Failed to build chaincode. %s", err.Error())
+	}
+
+	n.chaincode, err = n.start(append(os.Environ(), n.ChaincodeEnv()...), binary, "-peer.address="+n.config.PeerChaincodeAddress)
+
+	if err != nil {
+		return fmt.Errorf("Failed to start chaincode. %s", err.Error())
+	}
+
+	return nil
+}
+
+// ChaincodeEnv returns the environment variables a chaincode started separately
+// must be run with to connect to the peer, with the flag -peer.address set to
+// the peer chaincode address of the config
+func (n *Network) ChaincodeEnv() []string {
+	return []string{
+		"CORE_CHAINCODE_ID_NAME=" + n.config.ChaincodeName + ":" + n.config.ChaincodeVersion,
+		"CORE_PEER_TLS_ENABLED=false",
+	}
+}
+
+// Peer runs the peer CLI in the container with the passed args and returns its
+// output, or an error containing its error output when it fails or does not
+// complete within the timeout
+func (n *Network) Peer(timeout time.Duration, args ...string) (string, error) {
+	return n.run(timeout, "docker", append([]string{"exec", n.config.ContainerName, "peer"}, args...)...)
+}
+
+// Invoke submits a transaction to the chaincode with the passed args, the first
+// being the function name, and waits for it to be committed
+func (n *Network) Invoke(args ...string) error {
+	peerArgs := []string{"chaincode", "invoke", "-C", n.config.Channel, "-n", n.config.ChaincodeName, "-c", ctor(args), "--waitForEvent"}
+
+	if n.config.Orderer != "" {
+		peerArgs = append(peerArgs, "-o", n.config.Orderer)
+	}
+
+	_, err := n.Peer(n.config.TransactionTimeout, peerArgs...)
+
+	return err
+}
+
+// Query evaluates a transaction of the chaincode with the passed args, the first
+// being the function name, and returns its payload
+func (n *Network) Query(args ...string) (string, error) {
+	output, err := n.Peer(n.config.TransactionTimeout, "chaincode", "query", "-C", n.config.Channel, "-n", n.config.ChaincodeName, "-c", ctor(args))
+
+	return strings.TrimSuffix(output, "\n"), err
+}
+
+// Stop stops the chaincode, if started by the network, and removes the container
+func (n *Network) Stop() error {
+	if n.chaincode != nil && n.chaincode.Process != nil {
+		n.chaincode.Process.Kill()
+		n.chaincode.Wait()
+		n.chaincode = nil
+	}
+
+	if n.buildDir != "" {
+		os.RemoveAll(n.buildDir)
+		n.buildDir = ""
+	}
+
+	if !n.started {
+		return nil
+	}
+
+	_, err := n.run(n.config.StartTimeout, "docker", "rm", "--force", n.config.ContainerName)
+
+	if err != nil {
+		return fmt.Errorf("Failed to remove container. %s", err.Error())
+	}
+
+	n.started = false
+
+	return nil
+}
+
+func ctor(args []string) string {
+	ctorJSON, _ := json.Marshal(map[string][]string{"Args": args})
+
+	return string(ctorJSON)
+}
+
+func runCommand(timeout time.Duration, name string, args ...string) (string, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return stdout.String(), fmt.Errorf("%s %s failed. %s", name, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+		}
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+
+		return stdout.String(), fmt.Errorf("%s %s did not complete within %s", name, strings.Join(args, " "), timeout.String())
+	}
+
+	return stdout.String(), nil
+}
+
+func startCommand(env []string, name string, args ...string) (*exec.Cmd, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd, cmd.Start()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
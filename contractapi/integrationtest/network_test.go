@@ -0,0 +1,229 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrationtest
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type fakeCommands struct {
+	commands []string
+	outputs  map[string]string
+	failures map[string]int
+	env      []string
+}
+
+func (fc *fakeCommands) run(timeout time.Duration, name string, args ...string) (string, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	fc.commands = append(fc.commands, command)
+
+	for prefix, remaining := range fc.failures {
+		if strings.HasPrefix(command, prefix) && remaining != 0 {
+			fc.failures[prefix] = remaining - 1
+			return "", errors.New(prefix + " failed")
+		}
+	}
+
+	for prefix, output := range fc.outputs {
+		if strings.HasPrefix(command, prefix) {
+			return output, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (fc *fakeCommands) start(env []string, name string, args ...string) (*exec.Cmd, error) {
+	fc.env = env
+	fc.commands = append(fc.commands, "start "+strings.Join(args, " "))
+
+	return new(exec.Cmd), nil
+}
+
+func newTestNetwork(config Config) (*Network, *fakeCommands) {
+	fc := &fakeCommands{outputs: make(map[string]string), failures: make(map[string]int)}
+
+	n := newNetwork(config)
+	n.run = fc.run
+	n.start = fc.start
+
+	return n, fc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewNetwork(t *testing.T) {
+	// Should set defaults
+	n := newNetwork(Config{Image: "some-image"})
+	assert.True(t, strings.HasPrefix(n.config.ContainerName, containerNamePrefix), "should generate container name")
+	assert.Equal(t, DefaultChannel, n.config.Channel, "should use default channel")
+	assert.Equal(t, DefaultChaincodeName, n.config.ChaincodeName, "should use default chaincode name")
+	assert.Equal(t, DefaultChaincodeVersion, n.config.ChaincodeVersion, "should use default chaincode version")
+	assert.Equal(t, DefaultPeerChaincodeAddress, n.config.PeerChaincodeAddress, "should use default peer chaincode address")
+	assert.Equal(t, DefaultStartTimeout, n.config.StartTimeout, "should use default start timeout")
+	assert.Equal(t, DefaultTransactionTimeout, n.config.TransactionTimeout, "should use default transaction timeout")
+
+	// Should keep values set
+	n = newNetwork(Config{ContainerName: "peer", Channel: "channel", ChaincodeName: "cc", ChaincodeVersion: "1", PeerChaincodeAddress: "peer:7052", StartTimeout: time.Second, TransactionTimeout: time.Millisecond})
+	assert.Equal(t, Config{ContainerName: "peer", Channel: "channel", ChaincodeName: "cc", ChaincodeVersion: "1", PeerChaincodeAddress: "peer:7052", StartTimeout: time.Second, TransactionTimeout: time.Millisecond}, n.config, "should keep config set")
+}
+
+func TestStartNetwork(t *testing.T) {
+	var n *Network
+	var fc *fakeCommands
+	var err error
+
+	oldInterval := readyCheckInterval
+	readyCheckInterval = time.Millisecond
+	defer func() { readyCheckInterval = oldInterval }()
+
+	config := Config{
+		Image:         "some-image",
+		ContainerName: "peer",
+		Env:           map[string]string{"B": "2", "A": "1"},
+		Ports:         []string{"7051:7051", "7052:7052"},
+		ChaincodePath: "./chaincode",
+		SetupCommands: [][]string{{"channel", "join", "-b", "mychannel.block"}},
+	}
+
+	// Should error when image not set
+	n, _ = newTestNetwork(Config{})
+	err = n.startNetwork()
+	assert.EqualError(t, err, "Config must set the image of the container to start", "should error when image not set")
+
+	// Should start container, wait for peer, start chaincode and run setup commands
+	n, fc = newTestNetwork(config)
+	fc.failures["docker exec peer peer node status"] = 2
+	err = n.startNetwork()
+	assert.Nil(t, err, "should not error starting network")
+	assert.True(t, n.started, "should mark container started")
+	assert.Equal(t, "docker run --detach --name peer --publish 7051:7051 --publish 7052:7052 --env A=1 --env B=2 some-image", fc.commands[0], "should run container")
+	assert.Equal(t, []string{"docker exec peer peer node status", "docker exec peer peer node status", "docker exec peer peer node status"}, fc.commands[1:4], "should wait for peer to be ready")
+	assert.Equal(t, "go build -o "+n.buildDir+"/chaincode ./chaincode", fc.commands[4], "should build chaincode")
+	assert.Equal(t, "start -peer.address=localhost:7052", fc.commands[5], "should start chaincode")
+	assert.Subset(t, fc.env, []string{"CORE_CHAINCODE_ID_NAME=mycc:0", "CORE_PEER_TLS_ENABLED=false"}, "should start chaincode with env")
+	assert.Equal(t, "docker exec peer peer channel join -b mychannel.block", fc.commands[6], "should run setup commands")
+	n.Stop()
+
+	// Should not start chaincode when path not set
+	config.ChaincodePath = ""
+	n, fc = newTestNetwork(config)
+	err = n.startNetwork()
+	assert.Nil(t, err, "should not error starting network without chaincode")
+	assert.Equal(t, []string{"docker run --detach --name peer --publish 7051:7051 --publish 7052:7052 --env A=1 --env B=2 some-image", "docker exec peer peer node status", "docker exec peer peer channel join -b mychannel.block"}, fc.commands, "should not build chaincode")
+
+	// Should error when commands fail
+	n, fc = newTestNetwork(config)
+	fc.failures["docker run"] = 1
+	err = n.startNetwork()
+	assert.EqualError(t, err, "Failed to start container. docker run failed", "should error when container not started")
+	assert.False(t, n.started, "should not mark container started")
+
+	config.StartTimeout = time.Millisecond
+	n, fc = newTestNetwork(config)
+	fc.failures["docker exec peer peer node status"] = -1
+	err = n.startNetwork()
+	assert.EqualError(t, err, "Peer was not ready within 1ms. docker exec peer peer node status failed", "should error when peer not ready")
+
+	config.ChaincodePath = "./chaincode"
+	n, fc = newTestNetwork(config)
+	fc.failures["go build"] = 1
+	err = n.startNetwork()
+	assert.EqualError(t, err, "Failed to build chaincode. go build failed", "should error when chaincode not built")
+	n.Stop()
+
+	n, fc = newTestNetwork(config)
+	fc.failures["docker exec peer peer channel"] = 1
+	err = n.startNetwork()
+	assert.EqualError(t, err, "Failed to set up network. docker exec peer peer channel failed", "should error when setup fails")
+	n.Stop()
+}
+
+func TestInvokeAndQuery(t *testing.T) {
+	var err error
+
+	n, fc := newTestNetwork(Config{ContainerName: "peer", Orderer: "orderer:7050"})
+
+	// Should invoke with args
+	err = n.Invoke("SimpleAsset:Create", "ASSET_1", "has \"quotes\"")
+	assert.Nil(t, err, "should not error invoking")
+	assert.Equal(t, `docker exec peer peer chaincode invoke -C mychannel -n mycc -c {"Args":["SimpleAsset:Create","ASSET_1","has \"quotes\""]} --waitForEvent -o orderer:7050`, fc.commands[0], "should invoke with args")
+
+	// Should query with args
+	fc.outputs["docker exec peer peer chaincode query"] = "Initialised\n"
+	output, err := n.Query("SimpleAsset:Read", "ASSET_1")
+	assert.Nil(t, err, "should not error querying")
+	assert.Equal(t, "Initialised", output, "should return output of query")
+	assert.Equal(t, `docker exec peer peer chaincode query -C mychannel -n mycc -c {"Args":["SimpleAsset:Read","ASSET_1"]}`, fc.commands[1], "should query with args")
+
+	// Should return errors
+	fc.failures["docker exec peer peer chaincode"] = 2
+	assert.EqualError(t, n.Invoke("Fail"), "docker exec peer peer chaincode failed", "should return error of invoke")
+	_, err = n.Query("Fail")
+	assert.EqualError(t, err, "docker exec peer peer chaincode failed", "should return error of query")
+}
+
+func TestStop(t *testing.T) {
+	var err error
+
+	n, fc := newTestNetwork(Config{ContainerName: "peer"})
+
+	// Should do nothing when not started
+	err = n.Stop()
+	assert.Nil(t, err, "should not error when not started")
+	assert.Len(t, fc.commands, 0, "should not remove container when not started")
+
+	// Should remove container
+	n.started = true
+	err = n.Stop()
+	assert.Nil(t, err, "should not error stopping")
+	assert.Equal(t, []string{"docker rm --force peer"}, fc.commands, "should remove container")
+	assert.False(t, n.started, "should mark container stopped")
+
+	// Should error when cannot remove container
+	n.started = true
+	fc.failures["docker rm"] = 1
+	err = n.Stop()
+	assert.EqualError(t, err, "Failed to remove container. docker rm failed", "should error when cannot remove container")
+}
+
+func TestRunCommand(t *testing.T) {
+	var output string
+	var err error
+
+	// Should return output
+	output, err = runCommand(time.Minute, "go", "env", "GOARCH")
+	assert.Nil(t, err, "should not error running command")
+	assert.NotEmpty(t, output, "should return output")
+
+	// Should return error output
+	_, err = runCommand(time.Minute, "go", "notacommand")
+	assert.Contains(t, err.Error(), "go notacommand failed. go notacommand: unknown command", "should return error output")
+
+	_, err = runCommand(time.Minute, "notacommand")
+	assert.NotNil(t, err, "should error when command does not exist")
+}
@@ -0,0 +1,218 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrationtest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Transactor submits and evaluates transactions of a chaincode. It is met by
+// Network and may be implemented to run scenarios against other networks.
+type Transactor interface {
+	Invoke(args ...string) error
+	Query(args ...string) (string, error)
+}
+
+// Step is a transaction of a scenario and the result expected of it. Exactly one
+// of Invoke and Query must be set, to the function name and args. Unless Error
+// is set the transaction is expected to succeed. A query may also set Payload,
+// which its payload must equal when not blank, and Regex, which its payload must
+// match e.g. ^$ to expect an empty payload. Error is text the error of the
+// transaction must contain.
+type Step struct {
+	Name    string   `yaml:"name,omitempty"`
+	Invoke  []string `yaml:"invoke,omitempty"`
+	Query   []string `yaml:"query,omitempty"`
+	Payload string   `yaml:"payload,omitempty"`
+	Regex   string   `yaml:"regex,omitempty"`
+	Error   string   `yaml:"error,omitempty"`
+}
+
+// Scenario is an ordered list of steps run against the same chaincode
+type Scenario struct {
+	Name  string `yaml:"name"`
+	Steps []Step `yaml:"steps"`
+}
+
+// ParseScenarios returns the scenarios described by the YAML, a list of scenarios
+// each with a name and steps using the YAML names of the fields of Step. Errors if
+// the YAML is invalid, has unknown fields or a step is invalid e.g. does not set
+// exactly one of invoke and query or sets a payload or regex for an invoke.
+func ParseScenarios(data []byte) ([]Scenario, error) {
+	scenarios := []Scenario{}
+
+	err := yaml.UnmarshalStrict(data, &scenarios)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse scenarios. %s", err.Error())
+	}
+
+	for _, scenario := range scenarios {
+		err = scenario.validate()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scenarios, nil
+}
+
+// LoadScenarios reads the file at the path and parses the scenarios it describes
+func LoadScenarios(path string) ([]Scenario, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read scenarios. %s", err.Error())
+	}
+
+	return ParseScenarios(data)
+}
+
+// Execute runs the steps of the scenario in order against the transactor and
+// returns an error describing the first step whose result was not as expected
+func (s Scenario) Execute(transactor Transactor) error {
+	err := s.validate()
+
+	if err != nil {
+		return err
+	}
+
+	for i, step := range s.Steps {
+		err = step.execute(transactor)
+
+		if err != nil {
+			return fmt.Errorf("%s failed. %s", s.describeStep(i), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// RunScenarios executes each scenario against the transactor, failing the test
+// at the first step of a scenario not as expected
+func RunScenarios(t testing.TB, transactor Transactor, scenarios ...Scenario) {
+	t.Helper()
+
+	for _, scenario := range scenarios {
+		if err := scenario.Execute(transactor); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
+
+// RunScenarioFile loads the scenarios of the file at the path and runs them
+// against the transactor, failing the test if they cannot be loaded
+func RunScenarioFile(t testing.TB, transactor Transactor, path string) {
+	t.Helper()
+
+	scenarios, err := LoadScenarios(path)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	RunScenarios(t, transactor, scenarios...)
+}
+
+func (s Scenario) validate() error {
+	for i, step := range s.Steps {
+		var err error
+
+		switch {
+		case len(step.Invoke) == 0 && len(step.Query) == 0:
+			err = errors.New("Must set one of invoke and query")
+		case len(step.Invoke) > 0 && len(step.Query) > 0:
+			err = errors.New("Must set only one of invoke and query")
+		case len(step.Invoke) > 0 && (step.Payload != "" || step.Regex != ""):
+			err = errors.New("Payload and regex may only be set for a query")
+		case step.Error != "" && (step.Payload != "" || step.Regex != ""):
+			err = errors.New("Payload and regex may not be set when expecting an error")
+		}
+
+		if err == nil && step.Regex != "" {
+			if _, regexErr := regexp.Compile(step.Regex); regexErr != nil {
+				err = fmt.Errorf("Invalid regex. %s", regexErr.Error())
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s is invalid. %s", s.describeStep(i), err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s Scenario) describeStep(i int) string {
+	description := fmt.Sprintf("Step %d", i+1)
+
+	if s.Steps[i].Name != "" {
+		description += fmt.Sprintf(" (%s)", s.Steps[i].Name)
+	}
+
+	if s.Name != "" {
+		description += fmt.Sprintf(" of scenario %s", s.Name)
+	}
+
+	return description
+}
+
+func (step Step) execute(transactor Transactor) error {
+	var payload string
+	var err error
+
+	action := "Invoke"
+	args := step.Invoke
+
+	if len(step.Query) > 0 {
+		action = "Query"
+		args = step.Query
+		payload, err = transactor.Query(args...)
+	} else {
+		err = transactor.Invoke(args...)
+	}
+
+	if step.Error != "" {
+		if err == nil {
+			return fmt.Errorf("%s %s succeeded. Expected it to fail with %q", action, strings.Join(args, " "), step.Error)
+		} else if !strings.Contains(err.Error(), step.Error) {
+			return fmt.Errorf("%s %s failed with %q. Expected it to contain %q", action, strings.Join(args, " "), err.Error(), step.Error)
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s %s failed. %s", action, strings.Join(args, " "), err.Error())
+	}
+
+	if step.Payload != "" && payload != step.Payload {
+		return fmt.Errorf("%s %s returned %q. Expected %q", action, strings.Join(args, " "), payload, step.Payload)
+	}
+
+	if step.Regex != "" && !regexp.MustCompile(step.Regex).MatchString(payload) {
+		return fmt.Errorf("%s %s returned %q. Expected it to match %s", action, strings.Join(args, " "), payload, step.Regex)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package integrationtest
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type assetTransactor struct {
+	assets map[string]string
+}
+
+func (at *assetTransactor) Invoke(args ...string) error {
+	switch args[0] {
+	case "Create":
+		if _, ok := at.assets[args[1]]; ok {
+			return errors.New("Asset " + args[1] + " already exists")
+		}
+
+		at.assets[args[1]] = "Initialised"
+	case "Update":
+		at.assets[args[1]] = args[2]
+	default:
+		return errors.New("Unknown function " + args[0])
+	}
+
+	return nil
+}
+
+func (at *assetTransactor) Query(args ...string) (string, error) {
+	value, ok := at.assets[args[1]]
+
+	if !ok {
+		return "", errors.New("Asset " + args[1] + " does not exist")
+	}
+
+	return value, nil
+}
+
+const testScenarios = `
+- name: create asset
+  steps:
+  - invoke: [Create, ASSET_1]
+  - name: read created
+    query: [Read, ASSET_1]
+    payload: Initialised
+  - invoke: [Create, ASSET_1]
+    error: already exists
+- name: update asset
+  steps:
+  - invoke: [Update, ASSET_1, "101.23"]
+  - query: [Read, ASSET_1]
+    regex: ^[0-9]+\.[0-9]{2}$
+  - query: [Read, ASSET_2]
+    error: does not exist
+`
+
+// ================================
+// Tests
+// ================================
+
+func TestParseScenarios(t *testing.T) {
+	var scenarios []Scenario
+	var err error
+
+	// Should parse scenarios
+	scenarios, err = ParseScenarios([]byte(testScenarios))
+	assert.Nil(t, err, "should not error parsing valid scenarios")
+	assert.Equal(t, []Scenario{
+		{Name: "create asset", Steps: []Step{
+			{Invoke: []string{"Create", "ASSET_1"}},
+			{Name: "read created", Query: []string{"Read", "ASSET_1"}, Payload: "Initialised"},
+			{Invoke: []string{"Create", "ASSET_1"}, Error: "already exists"},
+		}},
+		{Name: "update asset", Steps: []Step{
+			{Invoke: []string{"Update", "ASSET_1", "101.23"}},
+			{Query: []string{"Read", "ASSET_1"}, Regex: `^[0-9]+\.[0-9]{2}$`},
+			{Query: []string{"Read", "ASSET_2"}, Error: "does not exist"},
+		}},
+	}, scenarios, "should parse scenarios")
+
+	// Should error for invalid YAML
+	_, err = ParseScenarios([]byte("- name: ["))
+	assert.Contains(t, err.Error(), "Failed to parse scenarios.", "should error for invalid YAML")
+
+	_, err = ParseScenarios([]byte("- name: unknown\n  steps:\n  - submit: [Create]"))
+	assert.Contains(t, err.Error(), "Failed to parse scenarios.", "should error for unknown fields")
+
+	// Should error for invalid steps
+	_, err = ParseScenarios([]byte("- name: invalid\n  steps:\n  - query: [Read]\n  - payload: some payload"))
+	assert.EqualError(t, err, "Step 2 of scenario invalid is invalid. Must set one of invoke and query", "should error when step sets no transaction")
+}
+
+func TestLoadScenarios(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "scenarios")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scenarios.yaml")
+	ioutil.WriteFile(path, []byte(testScenarios), os.ModePerm)
+
+	// Should load scenarios from file
+	scenarios, err := LoadScenarios(path)
+	assert.Nil(t, err, "should not error loading scenarios")
+	assert.Len(t, scenarios, 2, "should parse file")
+
+	// Should error when cannot read file
+	_, err = LoadScenarios(filepath.Join(dir, "missing.yaml"))
+	assert.Contains(t, err.Error(), "Failed to read scenarios.", "should error for missing file")
+}
+
+func TestScenarioValidate(t *testing.T) {
+	// Should not error for valid steps
+	assert.Nil(t, Scenario{Steps: []Step{{Invoke: []string{"Create"}, Error: "exists"}, {Query: []string{"Read"}, Payload: "value", Regex: "^v"}}}.validate(), "should not error for valid steps")
+
+	// Should error for invalid steps
+	assert.EqualError(t, Scenario{Steps: []Step{{}}}.validate(), "Step 1 is invalid. Must set one of invoke and query", "should error when no transaction")
+	assert.EqualError(t, Scenario{Steps: []Step{{Invoke: []string{"Create"}, Query: []string{"Read"}}}}.validate(), "Step 1 is invalid. Must set only one of invoke and query", "should error when both transactions")
+	assert.EqualError(t, Scenario{Steps: []Step{{Invoke: []string{"Create"}, Payload: "value"}}}.validate(), "Step 1 is invalid. Payload and regex may only be set for a query", "should error when invoke expects payload")
+	assert.EqualError(t, Scenario{Steps: []Step{{Query: []string{"Read"}, Regex: "^v", Error: "fails"}}}.validate(), "Step 1 is invalid. Payload and regex may not be set when expecting an error", "should error when expecting payload and error")
+	assert.Contains(t, Scenario{Name: "bad regex", Steps: []Step{{Name: "read", Query: []string{"Read"}, Regex: "["}}}.validate().Error(), "Step 1 (read) of scenario bad regex is invalid. Invalid regex.", "should error for invalid regex")
+}
+
+func TestScenarioExecute(t *testing.T) {
+	var err error
+
+	scenarios, _ := ParseScenarios([]byte(testScenarios))
+
+	// Should execute steps in order
+	at := &assetTransactor{make(map[string]string)}
+	assert.Nil(t, scenarios[0].Execute(at), "should execute first scenario")
+	assert.Nil(t, scenarios[1].Execute(at), "should execute second scenario against same transactor")
+	assert.Equal(t, map[string]string{"ASSET_1": "101.23"}, at.assets, "should invoke transactor")
+
+	// Should error for invalid scenario
+	err = Scenario{Steps: []Step{{}}}.Execute(at)
+	assert.EqualError(t, err, "Step 1 is invalid. Must set one of invoke and query", "should validate scenario")
+
+	// Should error at first step not as expected
+	err = scenarios[0].Execute(at)
+	assert.EqualError(t, err, "Step 1 of scenario create asset failed. Invoke Create ASSET_1 failed. Asset ASSET_1 already exists", "should error when transaction fails")
+
+	err = Scenario{Steps: []Step{{Query: []string{"Read", "ASSET_1"}, Payload: "Initialised"}}}.Execute(at)
+	assert.EqualError(t, err, "Step 1 failed. Query Read ASSET_1 returned \"101.23\". Expected \"Initialised\"", "should error when payload differs")
+
+	err = Scenario{Steps: []Step{{Query: []string{"Read", "ASSET_1"}, Regex: "^[a-z]+$"}}}.Execute(at)
+	assert.EqualError(t, err, "Step 1 failed. Query Read ASSET_1 returned \"101.23\". Expected it to match ^[a-z]+$", "should error when payload does not match")
+
+	err = Scenario{Steps: []Step{{Query: []string{"Read", "ASSET_1"}, Error: "does not exist"}}}.Execute(at)
+	assert.EqualError(t, err, "Step 1 failed. Query Read ASSET_1 succeeded. Expected it to fail with \"does not exist\"", "should error when transaction succeeds")
+
+	err = Scenario{Steps: []Step{{Invoke: []string{"Delete", "ASSET_1"}, Error: "does not exist"}}}.Execute(at)
+	assert.EqualError(t, err, "Step 1 failed. Invoke Delete ASSET_1 failed with \"Unknown function Delete\". Expected it to contain \"does not exist\"", "should error when error differs")
+}
+
+func TestRunScenarios(t *testing.T) {
+	scenarios, _ := ParseScenarios([]byte(testScenarios))
+	at := &assetTransactor{make(map[string]string)}
+
+	// Should pass when scenarios execute
+	failures := runAssertion(func(rt testing.TB) { RunScenarios(rt, at, scenarios...) })
+	assert.Len(t, failures, 0, "should pass when scenarios execute")
+
+	// Should fail at first scenario to error
+	failures = runAssertion(func(rt testing.TB) { RunScenarios(rt, at, scenarios...) })
+	assert.Equal(t, []string{"Step 1 of scenario create asset failed. Invoke Create ASSET_1 failed. Asset ASSET_1 already exists"}, failures, "should fail when scenario errors")
+}
+
+func TestRunScenarioFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "scenarios")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scenarios.yaml")
+	ioutil.WriteFile(path, []byte(testScenarios), os.ModePerm)
+
+	// Should run scenarios of file
+	at := &assetTransactor{make(map[string]string)}
+	failures := runAssertion(func(rt testing.TB) { RunScenarioFile(rt, at, path) })
+	assert.Len(t, failures, 0, "should pass when scenarios of file execute")
+	assert.Len(t, at.assets, 1, "should run scenarios of file")
+
+	// Should fail when cannot load file
+	failures = runAssertion(func(rt testing.TB) { RunScenarioFile(rt, at, filepath.Join(dir, "missing.yaml")) })
+	assert.Len(t, failures, 1, "should fail when cannot load file")
+	assert.Contains(t, failures[0], "Failed to read scenarios.", "should fail with load error")
+}
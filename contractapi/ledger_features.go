@@ -0,0 +1,227 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// FeatureKeyObjectType is the object type of the composite keys used to store
+// the values of feature flags in the world state
+const FeatureKeyObjectType = "feature"
+
+// Features reads and writes feature flags stored in the world state, so that new
+// logic of a function can be switched on and off by a transaction rather than by
+// deploying the chaincode again. A flag stored in the world state takes precedence
+// over the flag as defined for the chaincode (see ContractChaincode.SetFeatureFlag).
+type Features struct {
+	ctx      *TransactionContext
+	defaults map[string]bool
+}
+
+// Features returns the feature flags of the chaincode, as stored in the world state
+func (ctx *TransactionContext) Features() *Features {
+	return &Features{ctx, ctx.details.featureFlags}
+}
+
+// Enabled returns whether the named feature flag is enabled. If the flag is not
+// stored in the world state its value as defined for the chaincode is returned.
+// A flag whose value cannot be read is not enabled.
+func (f *Features) Enabled(name string) bool {
+	enabled, _, err := f.get(name)
+
+	return err == nil && enabled
+}
+
+// Set stores the value of the named feature flag in the world state. State
+// triggers registered for the key of the flag are called after it is written.
+func (f *Features) Set(name string, enabled bool) error {
+	key, err := f.ctx.GetStub().CreateCompositeKey(FeatureKeyObjectType, []string{name})
+
+	if err != nil {
+		return err
+	}
+
+	return f.ctx.putStateBytes(key, []byte(strconv.FormatBool(enabled)))
+}
+
+// Reset deletes the named feature flag from the world state so that its value as
+// defined for the chaincode is used again
+func (f *Features) Reset(name string) error {
+	key, err := f.ctx.GetStub().CreateCompositeKey(FeatureKeyObjectType, []string{name})
+
+	if err != nil {
+		return err
+	}
+
+	return f.ctx.delStateBytes(key)
+}
+
+// List returns the value of each feature flag defined for the chaincode or stored
+// in the world state
+func (f *Features) List() (map[string]bool, error) {
+	flags := make(map[string]bool)
+
+	for name, enabled := range f.defaults {
+		flags[name] = enabled
+	}
+
+	stub := f.ctx.GetStub()
+
+	iterator, err := stub.GetStateByPartialCompositeKey(FeatureKeyObjectType, []string{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer iterator.Close()
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := stub.SplitCompositeKey(kv.Key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		enabled, err := parseFeatureValue(attributes[0], kv.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		flags[attributes[0]] = enabled
+	}
+
+	return flags, nil
+}
+
+// get returns the value of the named feature flag and whether it is stored in the
+// world state
+func (f *Features) get(name string) (bool, bool, error) {
+	key, err := f.ctx.GetStub().CreateCompositeKey(FeatureKeyObjectType, []string{name})
+
+	if err != nil {
+		return false, false, err
+	}
+
+	value, err := f.ctx.getStateBytes(key)
+
+	if err != nil {
+		return false, false, err
+	}
+
+	if value == nil {
+		return f.defaults[name], false, nil
+	}
+
+	enabled, err := parseFeatureValue(name, value)
+
+	return enabled, true, err
+}
+
+func parseFeatureValue(name string, value []byte) (bool, error) {
+	enabled, err := strconv.ParseBool(string(value))
+
+	if err != nil {
+		return false, fmt.Errorf("Value %s of feature flag %s is not a boolean", string(value), name)
+	}
+
+	return enabled, nil
+}
+
+// FeatureFlagContract is a contract for toggling the feature flags stored in the
+// world state (see Features). Add it to the chaincode alongside the contracts whose
+// functions check the flags. Its functions change the behaviour of the chaincode so
+// must be restricted to administrators. It must be created using NewFeatureFlagContract,
+// otherwise the functions changing flags return an error.
+type FeatureFlagContract struct {
+	Contract
+	authorised bool
+}
+
+// NewFeatureFlagContract returns a FeatureFlagContract whose functions are each
+// called after the passed middleware, which must authorise the caller e.g.
+//
+//	contractapi.NewFeatureFlagContract(contractapi.RequireAttribute("role", "admin"))
+//
+// Panics if the authorising middleware is nil.
+func NewFeatureFlagContract(authorise interface{}, middleware ...interface{}) *FeatureFlagContract {
+	if authorise == nil {
+		panic("Cannot create feature flag contract. Middleware authorising callers must be passed")
+	}
+
+	ffc := new(FeatureFlagContract)
+	ffc.Use(AllFunctions, append([]interface{}{authorise}, middleware...)...)
+	ffc.authorised = true
+
+	return ffc
+}
+
+// checkAuthorised returns an error if the contract was not created with middleware
+// authorising callers
+func (ffc *FeatureFlagContract) checkAuthorised() error {
+	if !ffc.authorised {
+		return errors.New("Feature flag contract does not authorise callers. Create it using NewFeatureFlagContract")
+	}
+
+	return nil
+}
+
+// Enable enables the named feature flag
+func (ffc *FeatureFlagContract) Enable(ctx *TransactionContext, name string) error {
+	if err := ffc.checkAuthorised(); err != nil {
+		return err
+	}
+
+	return ctx.Features().Set(name, true)
+}
+
+// Disable disables the named feature flag
+func (ffc *FeatureFlagContract) Disable(ctx *TransactionContext, name string) error {
+	if err := ffc.checkAuthorised(); err != nil {
+		return err
+	}
+
+	return ctx.Features().Set(name, false)
+}
+
+// Reset returns the named feature flag to its value as defined for the chaincode
+func (ffc *FeatureFlagContract) Reset(ctx *TransactionContext, name string) error {
+	if err := ffc.checkAuthorised(); err != nil {
+		return err
+	}
+
+	return ctx.Features().Reset(name)
+}
+
+// IsEnabled returns whether the named feature flag is enabled
+func (ffc *FeatureFlagContract) IsEnabled(ctx *TransactionContext, name string) (bool, error) {
+	enabled, _, err := ctx.Features().get(name)
+
+	return enabled, err
+}
+
+// GetFeatures returns the value of each feature flag
+func (ffc *FeatureFlagContract) GetFeatures(ctx *TransactionContext) (map[string]bool, error) {
+	return ctx.Features().List()
+}
@@ -0,0 +1,212 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type ledgerFeaturesTestContract struct {
+	Contract
+}
+
+func (lftc *ledgerFeaturesTestContract) Price(ctx *TransactionContext) string {
+	if ctx.Features().Enabled("new-pricing") {
+		return "new"
+	}
+
+	return "old"
+}
+
+func allowAll(ctx *TransactionContext) error {
+	return nil
+}
+
+func putFeature(stub *shimtest.MockStub, name string, value string) {
+	key, _ := stub.CreateCompositeKey(FeatureKeyObjectType, []string{name})
+
+	stub.MockTransactionStart("setup")
+	stub.PutState(key, []byte(value))
+	stub.MockTransactionEnd("setup")
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestFeaturesEnabled(t *testing.T) {
+	ctx, stub := newStateTestContext(withTransactionDetails(transactionDetails{featureFlags: map[string]bool{"flag1": true, "flag2": false}}))
+
+	// Should use chaincode value when not stored
+	assert.True(t, ctx.Features().Enabled("flag1"), "should use chaincode value when true")
+	assert.False(t, ctx.Features().Enabled("flag2"), "should use chaincode value when false")
+	assert.False(t, ctx.Features().Enabled("flag3"), "should not be enabled when unknown")
+
+	// Should use stored value
+	putFeature(stub.MockStub, "flag1", "false")
+	putFeature(stub.MockStub, "flag2", "true")
+	putFeature(stub.MockStub, "flag3", "true")
+	assert.False(t, ctx.Features().Enabled("flag1"), "should use stored value over chaincode value when false")
+	assert.True(t, ctx.Features().Enabled("flag2"), "should use stored value over chaincode value when true")
+	assert.True(t, ctx.Features().Enabled("flag3"), "should use stored value when not defined for chaincode")
+
+	// Should not be enabled when cannot be read
+	putFeature(stub.MockStub, "flag4", "not a bool")
+	assert.False(t, ctx.Features().Enabled("flag4"), "should not be enabled when stored value invalid")
+	assert.False(t, ctx.Features().Enabled("\xff"), "should not be enabled when name invalid")
+
+	stub.getErr = errors.New("some error")
+	assert.False(t, ctx.Features().Enabled("flag2"), "should not be enabled when get state errors")
+}
+
+func TestFeaturesSetAndReset(t *testing.T) {
+	var err error
+
+	ctx, stub := newStateTestContext(withTransactionDetails(transactionDetails{featureFlags: map[string]bool{"flag1": true}}))
+
+	// Should store value
+	err = ctx.Features().Set("flag1", false)
+	assert.Nil(t, err, "should not error setting flag")
+	assert.False(t, ctx.Features().Enabled("flag1"), "should use set value")
+
+	// Should return to chaincode value when reset
+	err = ctx.Features().Reset("flag1")
+	assert.Nil(t, err, "should not error resetting flag")
+	assert.True(t, ctx.Features().Enabled("flag1"), "should use chaincode value once reset")
+
+	// Should error for invalid name
+	assert.NotNil(t, ctx.Features().Set("\xff", true), "should error setting invalid name")
+	assert.NotNil(t, ctx.Features().Reset("\xff"), "should error resetting invalid name")
+
+	// Should error when write errors
+	stub.putErr = errors.New("put error")
+	stub.delErr = errors.New("del error")
+	assert.Contains(t, ctx.Features().Set("flag1", true).Error(), "put error", "should error when put state errors")
+	assert.Contains(t, ctx.Features().Reset("flag1").Error(), "del error", "should error when del state errors")
+	stub.putErr = nil
+	stub.delErr = nil
+
+	// Should call state triggers for key of flag
+	changes := []StateChange{}
+	ctx.setTransactionDetails(transactionDetails{featureFlags: map[string]bool{"flag1": true}, stateTriggers: []stateTrigger{{"", func(ctx *TransactionContext, change StateChange) error {
+		changes = append(changes, change)
+		return nil
+	}}}})
+
+	key, _ := stub.CreateCompositeKey(FeatureKeyObjectType, []string{"flag1"})
+	ctx.Features().Set("flag1", false)
+	ctx.Features().Reset("flag1")
+	assert.Equal(t, []StateChange{{Key: key, Value: []byte("false")}, {Key: key, Deleted: true}}, changes, "should call triggers when set and reset")
+}
+
+func TestFeaturesList(t *testing.T) {
+	var flags map[string]bool
+	var err error
+
+	ctx, stub := newStateTestContext(withTransactionDetails(transactionDetails{featureFlags: map[string]bool{"flag1": true, "flag2": false}}))
+
+	// Should list chaincode values
+	flags, err = ctx.Features().List()
+	assert.Nil(t, err, "should not error listing flags")
+	assert.Equal(t, map[string]bool{"flag1": true, "flag2": false}, flags, "should list chaincode values")
+
+	// Should list stored values over chaincode values
+	putFeature(stub.MockStub, "flag2", "true")
+	putFeature(stub.MockStub, "flag3", "false")
+	flags, err = ctx.Features().List()
+	assert.Nil(t, err, "should not error listing stored flags")
+	assert.Equal(t, map[string]bool{"flag1": true, "flag2": true, "flag3": false}, flags, "should list stored values")
+
+	// Should error for invalid stored value
+	putFeature(stub.MockStub, "flag4", "not a bool")
+	_, err = ctx.Features().List()
+	assert.EqualError(t, err, "Value not a bool of feature flag flag4 is not a boolean", "should error for invalid stored value")
+}
+
+func TestFeatureFlagContract(t *testing.T) {
+	cc := convertC2CC(new(ledgerFeaturesTestContract), NewFeatureFlagContract(allowAll))
+	cc.SetFeatureFlag("new-pricing", false)
+
+	stub := shimtest.NewMockStub("featureFlagContractTest", &cc)
+
+	invoke := func(args ...string) string {
+		byteArgs := [][]byte{}
+
+		for _, arg := range args {
+			byteArgs = append(byteArgs, []byte(arg))
+		}
+
+		response := stub.MockInvoke(standardTxID, byteArgs)
+
+		assert.Equal(t, int32(200), response.Status, "should succeed calling "+args[0])
+
+		return string(response.Payload)
+	}
+
+	// Should toggle feature flags used by other contracts
+	assert.Equal(t, "old", invoke("ledgerFeaturesTestContract:Price"), "should use chaincode value")
+	invoke("FeatureFlagContract:Enable", "new-pricing")
+	assert.Equal(t, "true", invoke("FeatureFlagContract:IsEnabled", "new-pricing"), "should return enabled")
+	assert.Equal(t, "new", invoke("ledgerFeaturesTestContract:Price"), "should use enabled value")
+	invoke("FeatureFlagContract:Disable", "new-pricing")
+	assert.Equal(t, "old", invoke("ledgerFeaturesTestContract:Price"), "should use disabled value")
+	assert.Equal(t, `{"new-pricing":false}`, invoke("FeatureFlagContract:GetFeatures"), "should return flags")
+	invoke("FeatureFlagContract:Enable", "other")
+	invoke("FeatureFlagContract:Reset", "new-pricing")
+	assert.Equal(t, `{"new-pricing":false,"other":true}`, invoke("FeatureFlagContract:GetFeatures"), "should return reset flags")
+
+	// Should error when stored value invalid
+	putFeature(stub, "bad", "not a bool")
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("FeatureFlagContract:IsEnabled"), []byte("bad")})
+	assert.Equal(t, "Value not a bool of feature flag bad is not a boolean", response.Message, "should error for invalid stored value")
+}
+
+func TestNewFeatureFlagContract(t *testing.T) {
+	// Should panic when no authorising middleware passed
+	assert.PanicsWithValue(t, "Cannot create feature flag contract. Middleware authorising callers must be passed", func() { NewFeatureFlagContract(nil) }, "should panic without middleware")
+
+	// Should not change flags when not created with middleware
+	cc := convertC2CC(new(FeatureFlagContract))
+	callContractFunctionAndCheckError(t, cc, []string{"Enable", "flag1"}, invokeType, "Feature flag contract does not authorise callers. Create it using NewFeatureFlagContract")
+	callContractFunctionAndCheckError(t, cc, []string{"Disable", "flag1"}, invokeType, "Feature flag contract does not authorise callers. Create it using NewFeatureFlagContract")
+	callContractFunctionAndCheckError(t, cc, []string{"Reset", "flag1"}, invokeType, "Feature flag contract does not authorise callers. Create it using NewFeatureFlagContract")
+
+	// Should restrict functions using middleware
+	ffc := NewFeatureFlagContract(RequireMSP("Org1MSP"), allowAll)
+	assert.Len(t, ffc.GetMiddleware()[AllFunctions], 2, "should add middleware for all functions")
+
+	cc = convertC2CC(ffc)
+	stub := shimtest.NewMockStub("featureFlagContractTest", &cc)
+
+	stub.Creator = createCreator("Org1MSP", nil)
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Enable"), []byte("flag1")})
+	assert.Equal(t, int32(200), response.Status, "should allow admin")
+
+	stub.Creator = createCreator("Org2MSP", nil)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Disable"), []byte("flag1")})
+	assert.Equal(t, int32(403), response.Status, "should deny others")
+
+	stub.Creator = createCreator("Org1MSP", nil)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IsEnabled"), []byte("flag1")})
+	assert.Equal(t, "true", string(response.Payload), "should not disable flag when denied")
+}
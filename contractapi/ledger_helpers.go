@@ -0,0 +1,59 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+)
+
+// GetCollection returns a ledgerapi collection for storing values in the world
+// state. The name of the collection is prefixed with the name of the contract
+// being invoked so that collections of the same name in different contracts of
+// the chaincode do not clash.
+func (ctx *TransactionContext) GetCollection(name string) *ledgerapi.Collection {
+	return ledgerapi.NewCollection(ctx.GetStub(), ctx.getCollectionName(name))
+}
+
+// GetStateList returns a ledgerapi state list for storing states in the world
+// state. The name of the list is prefixed in the same way as GetCollection.
+func (ctx *TransactionContext) GetStateList(name string) *ledgerapi.StateList {
+	return ledgerapi.NewStateList(ctx.GetStub(), ctx.getCollectionName(name))
+}
+
+// GetEventStore returns a ledgerapi event store for storing the history of
+// entities as events. The name of the store is prefixed in the same way as
+// GetCollection. The same event store is returned for the name throughout the
+// transaction so that events appended earlier in the transaction are seen.
+func (ctx *TransactionContext) GetEventStore(name string) *ledgerapi.EventStore {
+	name = ctx.getCollectionName(name)
+
+	if ctx.eventStores == nil {
+		ctx.eventStores = make(map[string]*ledgerapi.EventStore)
+	}
+
+	if _, ok := ctx.eventStores[name]; !ok {
+		ctx.eventStores[name] = ledgerapi.NewEventStore(ctx.GetStub(), name)
+	}
+
+	return ctx.eventStores[name]
+}
+
+func (ctx *TransactionContext) getCollectionName(name string) string {
+	if ctx.details.contractName == "" {
+		return name
+	}
+
+	return ctx.details.contractName + "." + name
+}
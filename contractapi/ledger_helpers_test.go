@@ -0,0 +1,77 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestGetCollection(t *testing.T) {
+	stub := shimtest.NewMockStub("ledgerTest", nil)
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should use name as passed when no contract name
+	assert.Equal(t, ledgerapi.NewCollection(stub, "assets"), ctx.GetCollection("assets"), "should return collection with name passed")
+
+	// Should prefix name with contract name
+	ctx.setTransactionDetails(transactionDetails{contractName: "mycontract"})
+	assert.Equal(t, ledgerapi.NewCollection(stub, "mycontract.assets"), ctx.GetCollection("assets"), "should return collection with contract prefixed name")
+}
+
+func TestGetStateList(t *testing.T) {
+	stub := shimtest.NewMockStub("ledgerTest", nil)
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should use name as passed when no contract name
+	assert.Equal(t, ledgerapi.NewStateList(stub, "assets"), ctx.GetStateList("assets"), "should return state list with name passed")
+
+	// Should prefix name with contract name
+	ctx.setTransactionDetails(transactionDetails{contractName: "mycontract"})
+	assert.Equal(t, ledgerapi.NewStateList(stub, "mycontract.assets"), ctx.GetStateList("assets"), "should return state list with contract prefixed name")
+}
+
+func TestGetEventStore(t *testing.T) {
+	stub := shimtest.NewMockStub("ledgerTest", nil)
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should use name as passed when no contract name
+	assert.Equal(t, ledgerapi.NewEventStore(stub, "accounts"), ctx.GetEventStore("accounts"), "should return event store with name passed")
+
+	// Should prefix name with contract name
+	ctx.setTransactionDetails(transactionDetails{contractName: "mycontract"})
+	assert.Equal(t, ledgerapi.NewEventStore(stub, "mycontract.accounts"), ctx.GetEventStore("accounts"), "should return event store with contract prefixed name")
+
+	// Should return same event store within transaction
+	assert.True(t, ctx.GetEventStore("accounts") == ctx.GetEventStore("accounts"), "should return same event store")
+
+	// Should return new event store for new transaction
+	es := ctx.GetEventStore("accounts")
+	ctx.SetStub(stub)
+	assert.False(t, es == ctx.GetEventStore("accounts"), "should return new event store after stub set")
+}
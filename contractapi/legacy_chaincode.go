@@ -0,0 +1,104 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// legacyContract is a contract handling all its transactions by passing them to
+// the Init and Invoke of a chaincode written directly against the shim
+type legacyContract struct {
+	Contract
+	chaincode shim.Chaincode
+}
+
+// WrapLegacyChaincode returns a contract with the passed name that handles all its
+// transactions using the passed chaincode, so that an existing chaincode written
+// directly against the shim can be registered alongside contracts in the same
+// chaincode while it is refactored into them. The chaincode is passed a stub whose
+// args have the function name without the contract name e.g. a transaction
+// "legacy:transfer" is passed "transfer" as its function. Its Init is called when
+// Init names the contract and its Invoke otherwise. The response of the chaincode
+// is returned as is, without calling the before and after transactions of the
+// chaincode, and the contract has no functions in the metadata.
+func WrapLegacyChaincode(cc shim.Chaincode, name string) ContractInterface {
+	lc := new(legacyContract)
+	lc.SetName(name)
+	lc.chaincode = cc
+
+	return lc
+}
+
+// getLegacyChaincode returns the chaincode wrapped by the named contract, if
+// it was created using WrapLegacyChaincode
+func (cc *ContractChaincode) getLegacyChaincode(ns string) (shim.Chaincode, bool) {
+	contract, ok := cc.contracts[ns]
+
+	if !ok || !contract.receiver.IsValid() {
+		return nil, false
+	}
+
+	lc, ok := contract.receiver.Interface().(*legacyContract)
+
+	if !ok {
+		return nil, false
+	}
+
+	return lc.chaincode, true
+}
+
+// legacyStub replaces the args of a transaction so that the function name passed
+// to a legacy chaincode does not include the name of its contract
+type legacyStub struct {
+	shim.ChaincodeStubInterface
+	args [][]byte
+}
+
+func newLegacyStub(stub shim.ChaincodeStubInterface, fn string) *legacyStub {
+	args := [][]byte{[]byte(fn)}
+
+	if original := stub.GetArgs(); len(original) > 1 {
+		args = append(args, original[1:]...)
+	}
+
+	return &legacyStub{stub, args}
+}
+
+func (ls *legacyStub) GetArgs() [][]byte {
+	return ls.args
+}
+
+func (ls *legacyStub) GetStringArgs() []string {
+	strArgs := make([]string, 0, len(ls.args))
+
+	for _, arg := range ls.args {
+		strArgs = append(strArgs, string(arg))
+	}
+
+	return strArgs
+}
+
+func (ls *legacyStub) GetFunctionAndParameters() (string, []string) {
+	allArgs := ls.GetStringArgs()
+
+	return allArgs[0], allArgs[1:]
+}
+
+func (ls *legacyStub) GetArgsSlice() ([]byte, error) {
+	return bytes.Join(ls.args, nil), nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type legacyTestChaincode struct{}
+
+func (ltc *legacyTestChaincode) Init(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, params := stub.GetFunctionAndParameters()
+
+	return shim.Success([]byte("init " + fn + " " + strings.Join(params, ",")))
+}
+
+func (ltc *legacyTestChaincode) Invoke(stub shim.ChaincodeStubInterface) peer.Response {
+	fn, params := stub.GetFunctionAndParameters()
+
+	switch fn {
+	case "put":
+		if err := stub.PutState(params[0], []byte(params[1])); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(nil)
+	case "get":
+		value, _ := stub.GetState(params[0])
+		return shim.Success(value)
+	default:
+		return peer.Response{Status: 404, Message: "unknown function " + fn}
+	}
+}
+
+type legacyArgsStub struct {
+	*shimtest.MockStub
+	args [][]byte
+}
+
+func (las *legacyArgsStub) GetArgs() [][]byte {
+	return las.args
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestWrapLegacyChaincode(t *testing.T) {
+	ltc := new(legacyTestChaincode)
+	contract := WrapLegacyChaincode(ltc, "legacy")
+
+	assert.Equal(t, "legacy", contract.GetName(), "should set name of contract")
+	assert.Equal(t, ltc, contract.(*legacyContract).chaincode, "should set chaincode of contract")
+}
+
+func TestGetLegacyChaincode(t *testing.T) {
+	var chaincode shim.Chaincode
+	var ok bool
+
+	ltc := new(legacyTestChaincode)
+	cc := convertC2CC(new(myContract), WrapLegacyChaincode(ltc, "legacy"))
+
+	// Should return chaincode of legacy contract
+	chaincode, ok = cc.getLegacyChaincode("legacy")
+	assert.True(t, ok, "should find legacy chaincode")
+	assert.Equal(t, ltc, chaincode, "should return wrapped chaincode")
+
+	// Should not return chaincode of other contracts
+	_, ok = cc.getLegacyChaincode("myContract")
+	assert.False(t, ok, "should not find legacy chaincode for contract")
+
+	_, ok = cc.getLegacyChaincode("missing")
+	assert.False(t, ok, "should not find legacy chaincode for missing contract")
+
+	// Should have no functions in metadata
+	assert.Len(t, cc.metadata.Contracts["legacy"].Transactions, 0, "should have no functions")
+}
+
+func TestLegacyStub(t *testing.T) {
+	mockStub := &legacyArgsStub{shimtest.NewMockStub("legacyTest", nil), [][]byte{[]byte("legacy:transfer"), []byte("alice"), []byte("bob")}}
+	mockStub.MockTransactionStart(standardTxID)
+
+	stub := newLegacyStub(mockStub, "transfer")
+
+	// Should remove contract name from function
+	assert.Equal(t, [][]byte{[]byte("transfer"), []byte("alice"), []byte("bob")}, stub.GetArgs(), "should replace function in args")
+	assert.Equal(t, []string{"transfer", "alice", "bob"}, stub.GetStringArgs(), "should replace function in string args")
+
+	fn, params := stub.GetFunctionAndParameters()
+	assert.Equal(t, "transfer", fn, "should return function without contract name")
+	assert.Equal(t, []string{"alice", "bob"}, params, "should return params")
+
+	argsSlice, err := stub.GetArgsSlice()
+	assert.Nil(t, err, "should not error getting args slice")
+	assert.Equal(t, []byte("transferalicebob"), argsSlice, "should join args")
+
+	// Should use stub for other functions
+	assert.Equal(t, standardTxID, stub.GetTxID(), "should return transaction ID of stub")
+
+	// Should not change args of stub
+	assert.Equal(t, "legacy:transfer", string(mockStub.GetArgs()[0]), "should not change args of stub")
+}
+
+func TestInvokeLegacyChaincode(t *testing.T) {
+	cc := convertC2CC(new(myContract), WrapLegacyChaincode(new(legacyTestChaincode), "legacy"))
+	mockStub := shimtest.NewMockStub("legacyTest", &cc)
+
+	// Should pass transactions to invoke of chaincode
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("legacy:put"), []byte("key1"), []byte("value1")})
+	assert.Equal(t, int32(200), response.Status, "should return response of chaincode")
+
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("legacy:get"), []byte("key1")})
+	assert.Equal(t, "value1", string(response.Payload), "should share world state with chaincode")
+
+	// Should return response of chaincode as is
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("legacy:missing")})
+	assert.Equal(t, peer.Response{Status: 404, Message: "unknown function missing"}, response, "should return response of chaincode unchanged")
+
+	// Should pass init to init of chaincode
+	response = mockStub.MockInit(standardTxID, [][]byte{[]byte("legacy:setup"), []byte("a"), []byte("b")})
+	assert.Equal(t, "init setup a,b", string(response.Payload), "should call init of chaincode")
+
+	// Should call other contracts as normal
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, "Some string")
+}
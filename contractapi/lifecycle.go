@@ -0,0 +1,184 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+var shimStart = shim.Start
+
+var serverStart = func(server *shim.ChaincodeServer) error {
+	return server.Start()
+}
+
+var stopSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// processStartTime the time the chaincode process started, used by the Uptime
+// transaction of the ops contract
+var processStartTime = time.Now()
+
+// OnStart adds a function to be called by Start before the chaincode is started
+// in the shim, e.g. to open connections used by transactions. Functions are
+// called in the order added. If a function returns an error the chaincode is
+// not started and Start returns the error, after calling in reverse the stop
+// functions added before the failing function so that those for functions
+// already started, added after each using OnStop, are called.
+func (cc *ContractChaincode) OnStart(fn func() error) {
+	cc.startHooks = append(cc.startHooks, fn)
+	cc.startHookStops = append(cc.startHookStops, len(cc.stopHooks))
+}
+
+// OnStop adds a function to be called by Start when the chaincode stops, e.g. to
+// flush logs or close connections. Functions are called in the reverse of the
+// order added and are all called even if one returns an error.
+func (cc *ContractChaincode) OnStop(fn func() error) {
+	cc.stopHooks = append(cc.stopHooks, fn)
+}
+
+// StartWithContext starts the chaincode in the fabric shim as Start does but stops
+// when the context is done, as well as when the process is sent an interrupt or
+// terminate signal, e.g. by the peer stopping the chaincode container, or the shim
+// returns. The functions added using OnStart are called before the chaincode is
+// started and those added using OnStop when it stops. If metrics are enabled they
+// are served until the chaincode stops (see EnableMetrics). If no tracer is set and
+// an OTLP endpoint is set in the environment, spans are exported to it until the
+// chaincode stops (see NewOTLPTracerFromEnv). Returns the error of the shim if it
+// stopped the chaincode, otherwise the first error of the stop functions.
+func (cc *ContractChaincode) StartWithContext(ctx context.Context) error {
+	return cc.run(ctx, func() error {
+		return shimStart(cc)
+	})
+}
+
+// StartInExternalMode starts the chaincode as a server, for the peer to connect
+// to when the chaincode is run as an external service, rather than connecting
+// to the peer. The chaincode listens on the address and identifies itself with
+// the chaincode ID given to the peer when the chaincode package was installed.
+// The same checks are made and the same functions called when starting and
+// stopping as for StartWithContext. The chaincode stops when the process is
+// sent an interrupt or terminate signal or the server returns.
+func (cc *ContractChaincode) StartInExternalMode(ccid, address string, tls shim.TLSProperties) error {
+	server := &shim.ChaincodeServer{
+		CCID:     ccid,
+		Address:  address,
+		CC:       cc,
+		TLSProps: tls,
+	}
+
+	return cc.run(context.Background(), func() error {
+		return serverStart(server)
+	})
+}
+
+// run starts the chaincode using the start function, calling the start and stop
+// functions of the chaincode around it, and waits for it to stop
+func (cc *ContractChaincode) run(ctx context.Context, start func() error) error {
+	if err := cc.checkContractNames(); err != nil {
+		return err
+	}
+
+	if err := cc.checkSharedFields(); err != nil {
+		return err
+	}
+
+	if err := cc.checkMetadataFile(); err != nil {
+		return err
+	}
+
+	cc.resolvedFeatureFlags = cc.resolveFeatureFlags()
+
+	cc.writeStartupDiagnostics()
+
+	if cc.tracer == nil {
+		tracer, err := NewOTLPTracerFromEnv()
+
+		if err != nil {
+			return fmt.Errorf("Failed to configure tracing. %s", err.Error())
+		}
+
+		if tracer != nil {
+			cc.tracer = tracer
+			defer tracer.exportEvery(otlpExportInterval)()
+		}
+	}
+
+	for i, hook := range cc.startHooks {
+		if err := hook(); err != nil {
+			cc.stopFrom(cc.startHookStops[i])
+			return fmt.Errorf("Failed to start chaincode. %s", err.Error())
+		}
+	}
+
+	if cc.metricsAddress != "" {
+		server, err := cc.startMetricsServer()
+
+		if err != nil {
+			cc.stop()
+			return err
+		}
+
+		defer server.Close()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, stopSignals...)
+	defer signal.Stop(signals)
+
+	shimErr := make(chan error, 1)
+
+	go func() {
+		shimErr <- start()
+	}()
+
+	var err error
+
+	select {
+	case err = <-shimErr:
+	case <-signals:
+	case <-ctx.Done():
+	}
+
+	if stopErr := cc.stop(); err == nil {
+		err = stopErr
+	}
+
+	return err
+}
+
+func (cc *ContractChaincode) stop() error {
+	return cc.stopFrom(len(cc.stopHooks))
+}
+
+// stopFrom calls in reverse the stop functions before index end, returning the
+// first error
+func (cc *ContractChaincode) stopFrom(end int) error {
+	var err error
+
+	for i := end - 1; i >= 0; i-- {
+		if hookErr := cc.stopHooks[i](); hookErr != nil && err == nil {
+			err = fmt.Errorf("Failed to stop chaincode. %s", hookErr.Error())
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,206 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func stubShimStart(fn func(shim.Chaincode) error) func() {
+	oldShimStart := shimStart
+	shimStart = fn
+
+	return func() { shimStart = oldShimStart }
+}
+
+func stubServerStart(fn func(*shim.ChaincodeServer) error) func() {
+	oldServerStart := serverStart
+	serverStart = fn
+
+	return func() { serverStart = oldServerStart }
+}
+
+func recordHook(calls *[]string, name string, err error) func() error {
+	return func() error {
+		*calls = append(*calls, name)
+		return err
+	}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestOnStart(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.OnStart(func() error { return nil })
+	cc.OnStart(func() error { return nil })
+
+	assert.Len(t, cc.startHooks, 2, "should add start hooks")
+}
+
+func TestOnStop(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.OnStop(func() error { return nil })
+
+	assert.Len(t, cc.stopHooks, 1, "should add stop hook")
+}
+
+func TestStartWithContext(t *testing.T) {
+	var cc ContractChaincode
+	var calls []string
+	var err error
+
+	// Should call start hooks in order and stop hooks in reverse when shim returns
+	calls = []string{}
+	restore := stubShimStart(func(shim.Chaincode) error {
+		calls = append(calls, "shim")
+		return errors.New("shim failure")
+	})
+	defer restore()
+
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStart(recordHook(&calls, "start1", nil))
+	cc.OnStart(recordHook(&calls, "start2", nil))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	cc.OnStop(recordHook(&calls, "stop2", errors.New("stop failure")))
+	err = cc.StartWithContext(context.Background())
+	assert.EqualError(t, err, "shim failure", "should return shim error over stop error")
+	assert.Equal(t, []string{"start1", "start2", "shim", "stop2", "stop1"}, calls, "should call hooks around shim")
+
+	// Should not start shim when start hook errors
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStart(recordHook(&calls, "start1", errors.New("some error")))
+	cc.OnStart(recordHook(&calls, "start2", nil))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	err = cc.StartWithContext(context.Background())
+	assert.EqualError(t, err, "Failed to start chaincode. some error", "should return start hook error")
+	assert.Equal(t, []string{"start1"}, calls, "should not continue after start hook error")
+
+	// Should stop hooks already started when later start hook errors
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStart(recordHook(&calls, "start1", nil))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	cc.OnStart(recordHook(&calls, "start2", nil))
+	cc.OnStop(recordHook(&calls, "stop2", errors.New("stop failure")))
+	cc.OnStart(recordHook(&calls, "start3", errors.New("some error")))
+	cc.OnStop(recordHook(&calls, "stop3", nil))
+	err = cc.StartWithContext(context.Background())
+	assert.EqualError(t, err, "Failed to start chaincode. some error", "should return start hook error over stop error")
+	assert.Equal(t, []string{"start1", "start2", "start3", "stop2", "stop1"}, calls, "should call stop hooks of started hooks in reverse")
+
+	// Should stop hooks when metrics server cannot start
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.EnableMetrics("not an address")
+	cc.OnStart(recordHook(&calls, "start1", nil))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	err = cc.StartWithContext(context.Background())
+	assert.Contains(t, err.Error(), "Failed to start metrics listener.", "should return metrics error")
+	assert.Equal(t, []string{"start1", "stop1"}, calls, "should call stop hooks when metrics fail")
+
+	// Should stop when context done and return stop hook error
+	blocked := make(chan struct{})
+	defer close(blocked)
+	stubShimStart(func(shim.Chaincode) error {
+		<-blocked
+		return nil
+	})
+
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStop(recordHook(&calls, "stop1", errors.New("some error")))
+	cc.OnStop(recordHook(&calls, "stop2", errors.New("other error")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = cc.StartWithContext(ctx)
+	assert.EqualError(t, err, "Failed to stop chaincode. other error", "should return first stop hook error")
+	assert.Equal(t, []string{"stop2", "stop1"}, calls, "should call all stop hooks")
+
+	// Should stop when sent stop signal
+	oldStopSignals := stopSignals
+	stopSignals = []os.Signal{syscall.SIGUSR1}
+	defer func() { stopSignals = oldStopSignals }()
+
+	stubShimStart(func(shim.Chaincode) error {
+		syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		<-blocked
+		return nil
+	})
+
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	err = cc.StartWithContext(context.Background())
+	assert.Nil(t, err, "should not error when stopped by signal")
+	assert.Equal(t, []string{"stop1"}, calls, "should call stop hooks when signalled")
+}
+
+func TestStartInExternalMode(t *testing.T) {
+	var cc ContractChaincode
+	var calls []string
+	var err error
+
+	// Should start server with details and call hooks around it
+	calls = []string{}
+	var started *shim.ChaincodeServer
+	restore := stubServerStart(func(server *shim.ChaincodeServer) error {
+		started = server
+		calls = append(calls, "server")
+		return errors.New("server failure")
+	})
+	defer restore()
+
+	tls := shim.TLSProperties{Disabled: true}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStart(recordHook(&calls, "start1", nil))
+	cc.OnStop(recordHook(&calls, "stop1", nil))
+	err = cc.StartInExternalMode("mycc:1234", "0.0.0.0:9999", tls)
+	assert.EqualError(t, err, "server failure", "should return server error")
+	assert.Equal(t, []string{"start1", "server", "stop1"}, calls, "should call hooks around server")
+	assert.Equal(t, "mycc:1234", started.CCID, "should set chaincode ID")
+	assert.Equal(t, "0.0.0.0:9999", started.Address, "should set address")
+	assert.Equal(t, tls, started.TLSProps, "should set TLS properties")
+	assert.Equal(t, &cc, started.CC, "should serve chaincode")
+
+	// Should not start server when start hook errors
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.OnStart(recordHook(&calls, "start1", errors.New("some error")))
+	err = cc.StartInExternalMode("mycc:1234", "0.0.0.0:9999", tls)
+	assert.EqualError(t, err, "Failed to start chaincode. some error", "should return start hook error")
+	assert.Equal(t, []string{"start1"}, calls, "should not start server after start hook error")
+
+	// Should not start server when metadata file does not match
+	calls = []string{}
+	cc = convertC2CC(new(simpleTestContract))
+	cc.metadataFileMismatches = []string{"Contract a not found in chaincode"}
+	err = cc.StartInExternalMode("mycc:1234", "0.0.0.0:9999", tls)
+	assert.EqualError(t, err, "Metadata file does not match contracts of chaincode. Call SkipMetadataValidation to start regardless. Contract a not found in chaincode", "should run pre-start checks")
+	assert.Empty(t, calls, "should not start server when checks fail")
+}
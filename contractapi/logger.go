@@ -0,0 +1,153 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// LoggingLevelEnvVar the environment variable, set by the peer for chaincode
+// it starts, from which the level of transaction loggers is read
+const LoggingLevelEnvVar = "CORE_CHAINCODE_LOGGING_LEVEL"
+
+// LogLevel defines the minimum severity of entries written by a logger
+type LogLevel int
+
+const (
+	// DebugLogLevel writes all entries
+	DebugLogLevel LogLevel = iota
+	// InfoLogLevel writes info, warning and error entries. This is the default
+	InfoLogLevel
+	// WarningLogLevel writes warning and error entries
+	WarningLogLevel
+	// ErrorLogLevel writes only error entries
+	ErrorLogLevel
+)
+
+var logLevelNames = map[LogLevel]string{
+	DebugLogLevel:   "DEBUG",
+	InfoLogLevel:    "INFO",
+	WarningLogLevel: "WARNING",
+	ErrorLogLevel:   "ERROR",
+}
+
+var logWriter io.Writer = os.Stderr
+
+// Logger writes leveled entries as single line JSON objects containing the
+// message and the fields of the logger
+type Logger struct {
+	level  LogLevel
+	fields map[string]interface{}
+	out    io.Writer
+}
+
+// GetLogger returns a logger for the transaction with fields for the channel ID,
+// transaction ID and the names of the contract and function called, so that its
+// entries can be correlated. The level of the logger is read from the
+// CORE_CHAINCODE_LOGGING_LEVEL environment variable, defaulting to INFO.
+func (ctx *TransactionContext) GetLogger() *Logger {
+	logger := new(Logger)
+	logger.level = parseLogLevel(os.Getenv(LoggingLevelEnvVar))
+	logger.out = logWriter
+	logger.fields = map[string]interface{}{
+		"channelId": ctx.GetStub().GetChannelID(),
+		"txId":      ctx.GetStub().GetTxID(),
+		"contract":  ctx.details.contractName,
+		"function":  ctx.details.functionName,
+	}
+
+	return logger
+}
+
+// WithField returns a copy of the logger that also writes the passed field with
+// each entry
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	logger := new(Logger)
+	logger.level = l.level
+	logger.out = l.out
+	logger.fields = make(map[string]interface{})
+
+	for k, v := range l.fields {
+		logger.fields[k] = v
+	}
+
+	logger.fields[key] = value
+
+	return logger
+}
+
+// Debugf writes a debug entry with the formatted message
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.write(DebugLogLevel, format, args...)
+}
+
+// Infof writes an info entry with the formatted message
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.write(InfoLogLevel, format, args...)
+}
+
+// Warningf writes a warning entry with the formatted message
+func (l *Logger) Warningf(format string, args ...interface{}) {
+	l.write(WarningLogLevel, format, args...)
+}
+
+// Errorf writes an error entry with the formatted message
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.write(ErrorLogLevel, format, args...)
+}
+
+func (l *Logger) write(level LogLevel, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	entry := make(map[string]interface{})
+
+	for k, v := range l.fields {
+		entry[k] = v
+	}
+
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = logLevelNames[level]
+	entry["message"] = fmt.Sprintf(format, args...)
+
+	entryBytes, err := json.Marshal(entry)
+
+	if err != nil {
+		entryBytes, _ = json.Marshal(map[string]interface{}{"level": logLevelNames[ErrorLogLevel], "message": fmt.Sprintf("Failed to write log entry. %s", err.Error())})
+	}
+
+	fmt.Fprintln(l.out, string(entryBytes))
+}
+
+// parseLogLevel converts a level as set for the peer's chaincode logging, e.g.
+// "debug" or "WARNING", into a LogLevel. Unknown levels are treated as INFO.
+func parseLogLevel(level string) LogLevel {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "DEBUG":
+		return DebugLogLevel
+	case "WARN", "WARNING":
+		return WarningLogLevel
+	case "ERROR", "CRITICAL", "PANIC", "FATAL":
+		return ErrorLogLevel
+	default:
+		return InfoLogLevel
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type loggingContract struct {
+	Contract
+}
+
+func (lc *loggingContract) Log(ctx *TransactionContext, message string) {
+	ctx.GetLogger().Infof("logged %s", message)
+}
+
+func captureLogs() (*bytes.Buffer, func()) {
+	buf := new(bytes.Buffer)
+	oldWriter := logWriter
+	logWriter = buf
+
+	return buf, func() { logWriter = oldWriter }
+}
+
+func parseLogEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	entries := []map[string]interface{}{}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		entry := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry), "should write entry as JSON")
+
+		delete(entry, "time")
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestGetLogger(t *testing.T) {
+	buf, restore := captureLogs()
+	defer restore()
+
+	stub := shimtest.NewMockStub("loggerTest", nil)
+	stub.TxID = standardTxID
+	stub.ChannelID = "mychannel"
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	ctx.details.contractName = "org.asset"
+	ctx.details.functionName = "Create"
+
+	// Should write entries with transaction fields at or above level
+	os.Setenv(LoggingLevelEnvVar, "warning")
+	defer os.Unsetenv(LoggingLevelEnvVar)
+
+	logger := ctx.GetLogger()
+	logger.Infof("not written")
+	logger.Warningf("some %s", "warning")
+	logger.WithField("asset", "asset1").Errorf("some error")
+
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "WARNING", "message": "some warning", "channelId": "mychannel", "txId": standardTxID, "contract": "org.asset", "function": "Create"},
+		{"level": "ERROR", "message": "some error", "channelId": "mychannel", "txId": standardTxID, "contract": "org.asset", "function": "Create", "asset": "asset1"},
+	}, parseLogEntries(t, buf), "should write entries at or above level")
+
+	// Should not add fields to original logger
+	buf.Reset()
+	logger.Errorf("other error")
+	assert.NotContains(t, buf.String(), "asset1", "should not add field to original logger")
+
+	// Should default to info level
+	os.Unsetenv(LoggingLevelEnvVar)
+	buf.Reset()
+	logger = ctx.GetLogger()
+	logger.Debugf("not written")
+	logger.Infof("written")
+	assert.Len(t, parseLogEntries(t, buf), 1, "should write info but not debug by default")
+}
+
+func TestLoggerInTransaction(t *testing.T) {
+	buf, restore := captureLogs()
+	defer restore()
+
+	// Should tag entries with contract and function called
+	cc := convertC2CC(new(loggingContract))
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Log", "hello"}, invokeType, "")
+
+	entries := parseLogEntries(t, buf)
+	assert.Len(t, entries, 1, "should write entry")
+	assert.Equal(t, "logged hello", entries[0]["message"], "should write message")
+	assert.Equal(t, "loggingContract", entries[0]["contract"], "should write contract name")
+	assert.Equal(t, "Log", entries[0]["function"], "should write function name")
+}
+
+func TestParseLogLevel(t *testing.T) {
+	assert.Equal(t, DebugLogLevel, parseLogLevel("debug"), "should parse debug")
+	assert.Equal(t, InfoLogLevel, parseLogLevel("INFO"), "should parse info")
+	assert.Equal(t, WarningLogLevel, parseLogLevel("warn"), "should parse warn")
+	assert.Equal(t, WarningLogLevel, parseLogLevel(" WARNING "), "should parse warning")
+	assert.Equal(t, ErrorLogLevel, parseLogLevel("critical"), "should parse critical as error")
+	assert.Equal(t, InfoLogLevel, parseLogLevel(""), "should default to info")
+	assert.Equal(t, InfoLogLevel, parseLogLevel("verbose"), "should default to info for unknown")
+}
@@ -62,22 +62,28 @@ func GetJSONSchema() string {
 type ParameterMetadata struct {
 	Description string      `json:"description,omitempty"`
 	Name        string      `json:"name"`
+	Required    bool        `json:"required,omitempty"`
 	Schema      spec.Schema `json:"schema"`
 }
 
 // TransactionMetadata contains information on what makes up a transaction
 type TransactionMetadata struct {
-	Parameters []ParameterMetadata `json:"parameters,omitempty"`
-	Returns    *spec.Schema        `json:"returns,omitempty"`
-	Tag        []string            `json:"tag,omitempty"`
-	Name       string              `json:"name"`
+	Parameters  []ParameterMetadata `json:"parameters,omitempty"`
+	Returns     *spec.Schema        `json:"returns,omitempty"`
+	Tag         []string            `json:"tag,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Pagination  *PaginationMetadata `json:"pagination,omitempty"`
 }
 
 // ContractMetadata contains information about what makes up a contract
 type ContractMetadata struct {
-	Info         spec.Info             `json:"info,omitempty"`
-	Name         string                `json:"name"`
-	Transactions []TransactionMetadata `json:"transactions"`
+	Info         spec.Info              `json:"info,omitempty"`
+	Name         string                 `json:"name"`
+	Transactions []TransactionMetadata  `json:"transactions"`
+	Constants    map[string]interface{} `json:"constants,omitempty"`
+	Aliases      []string               `json:"aliases,omitempty"`
+	Default      bool                   `json:"default,omitempty"`
 }
 
 // ObjectMetadata description of an asset
@@ -0,0 +1,221 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ListContracts returns a human readable summary of the contracts of the chaincode
+// the system contract is part of, one line per contract, for operators querying
+// a deployed chaincode from the peer CLI.
+func (sc *systemContract) ListContracts() (string, error) {
+	ccm, err := sc.parseMetadata()
+
+	if err != nil {
+		return "", err
+	}
+
+	names := []string{}
+
+	for name := range ccm.Contracts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines := []string{}
+
+	for _, name := range names {
+		contract := ccm.Contracts[name]
+		line := name
+
+		if contract.Default {
+			line += " (default)"
+		}
+
+		line += fmt.Sprintf(": %d transactions", len(contract.Transactions))
+
+		if contract.Info.Description != "" {
+			line += ". " + contract.Info.Description
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// ListFunctions returns a human readable summary of the transactions of the passed
+// contract, one line per transaction giving its signature
+func (sc *systemContract) ListFunctions(contractName string) (string, error) {
+	contract, err := sc.findContract(contractName)
+
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{}
+
+	for _, tx := range contract.Transactions {
+		lines = append(lines, summariseTransaction(tx))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// DescribeFunction returns a human readable description of the passed transaction
+// of the passed contract giving its parameters, return type and whether it should
+// be submitted or evaluated
+func (sc *systemContract) DescribeFunction(contractName string, functionName string) (string, error) {
+	contract, err := sc.findContract(contractName)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, tx := range contract.Transactions {
+		if tx.Name != functionName {
+			continue
+		}
+
+		lines := []string{fmt.Sprintf("Function %s of contract %s", tx.Name, contractName)}
+
+		if tx.Description != "" {
+			lines = append(lines, "Description: "+tx.Description)
+		}
+
+		lines = append(lines, "Type: "+transactionType(tx))
+
+		if len(tx.Parameters) == 0 {
+			lines = append(lines, "Parameters: none")
+		} else {
+			lines = append(lines, "Parameters:")
+		}
+
+		for _, param := range tx.Parameters {
+			line := fmt.Sprintf("  %s %s", param.Name, summariseSchema(param.Schema))
+
+			if !param.Required {
+				line += " (optional)"
+			}
+
+			if param.Description != "" {
+				line += " - " + param.Description
+			}
+
+			lines = append(lines, line)
+		}
+
+		if tx.Returns != nil {
+			lines = append(lines, "Returns: "+summariseSchema(*tx.Returns))
+		} else {
+			lines = append(lines, "Returns: nothing")
+		}
+
+		return strings.Join(lines, "\n"), nil
+	}
+
+	return "", fmt.Errorf("Function %s not found in contract %s", functionName, contractName)
+}
+
+func (sc *systemContract) parseMetadata() (ContractChaincodeMetadata, error) {
+	ccm := ContractChaincodeMetadata{}
+	err := json.Unmarshal([]byte(sc.metadata), &ccm)
+
+	if err != nil {
+		return ccm, fmt.Errorf("Failed to parse metadata. %s", err.Error())
+	}
+
+	return ccm, nil
+}
+
+func (sc *systemContract) findContract(contractName string) (ContractMetadata, error) {
+	ccm, err := sc.parseMetadata()
+
+	if err != nil {
+		return ContractMetadata{}, err
+	}
+
+	contract, ok := ccm.Contracts[contractName]
+
+	if !ok {
+		return ContractMetadata{}, fmt.Errorf("Contract not found with name %s", contractName)
+	}
+
+	return contract, nil
+}
+
+// summariseTransaction returns the signature of the transaction
+// e.g. ReadAsset(id string) Asset [evaluate]
+func summariseTransaction(tx TransactionMetadata) string {
+	params := []string{}
+
+	for _, param := range tx.Parameters {
+		summary := param.Name + " " + summariseSchema(param.Schema)
+
+		if !param.Required {
+			summary += "?"
+		}
+
+		params = append(params, summary)
+	}
+
+	summary := fmt.Sprintf("%s(%s)", tx.Name, strings.Join(params, ", "))
+
+	if tx.Returns != nil {
+		summary += " " + summariseSchema(*tx.Returns)
+	}
+
+	return summary + " [" + transactionType(tx) + "]"
+}
+
+func transactionType(tx TransactionMetadata) string {
+	if stringInSlice("evaluateTx", tx.Tag) {
+		return "evaluate"
+	}
+
+	return "submit"
+}
+
+// summariseSchema returns a short name for the type of the schema, using the
+// name of the component for references and []T and map[string]T for arrays and maps
+func summariseSchema(schema spec.Schema) string {
+	if ref := schema.Ref.String(); ref != "" {
+		return strings.TrimPrefix(ref, "#/components/schemas/")
+	}
+
+	if len(schema.Type) != 1 {
+		return "any"
+	}
+
+	switch schema.Type[0] {
+	case "array":
+		if schema.Items != nil && schema.Items.Schema != nil {
+			return "[]" + summariseSchema(*schema.Items.Schema)
+		}
+	case "object":
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			return "map[string]" + summariseSchema(*schema.AdditionalProperties.Schema)
+		}
+	}
+
+	return schema.Type[0]
+}
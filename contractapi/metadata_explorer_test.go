@@ -0,0 +1,130 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func explorerSystemContract() *systemContract {
+	sc := new(systemContract)
+	sc.setMetadata(`{
+		"contracts": {
+			"assets": {
+				"name": "assets",
+				"default": true,
+				"info": {"description": "Manages assets"},
+				"transactions": [
+					{"name": "CreateAsset", "tag": ["submitTx"], "parameters": [{"name": "id", "required": true, "schema": {"type": "string"}}, {"name": "tags", "schema": {"type": "array", "items": {"type": "string"}}}]},
+					{"name": "ReadAsset", "description": "Returns the asset", "tag": ["evaluateTx"], "parameters": [{"name": "id", "required": true, "description": "ID of the asset", "schema": {"type": "string"}}], "returns": {"$ref": "#/components/schemas/Asset"}}
+				]
+			},
+			"owners": {
+				"name": "owners",
+				"transactions": [
+					{"name": "Count", "tag": ["evaluateTx"], "returns": {"type": "integer"}}
+				]
+			}
+		}
+	}`)
+
+	return sc
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestListContracts(t *testing.T) {
+	var str string
+	var err error
+
+	// Should list contracts in name order
+	str, err = explorerSystemContract().ListContracts()
+	assert.Nil(t, err, "should not error for valid metadata")
+	assert.Equal(t, "assets (default): 2 transactions. Manages assets\nowners: 1 transactions", str, "should summarise contracts")
+
+	// Should error when metadata invalid
+	sc := systemContract{}
+	sc.setMetadata("not json")
+	_, err = sc.ListContracts()
+	assert.Contains(t, err.Error(), "Failed to parse metadata.", "should error when metadata invalid")
+}
+
+func TestListFunctions(t *testing.T) {
+	var str string
+	var err error
+
+	sc := explorerSystemContract()
+
+	// Should list signatures of transactions
+	str, err = sc.ListFunctions("assets")
+	assert.Nil(t, err, "should not error for existing contract")
+	assert.Equal(t, "CreateAsset(id string, tags []string?) [submit]\nReadAsset(id string) Asset [evaluate]", str, "should summarise transactions")
+
+	// Should error when contract not found
+	_, err = sc.ListFunctions("missing")
+	assert.EqualError(t, err, "Contract not found with name missing", "should error for missing contract")
+}
+
+func TestDescribeFunction(t *testing.T) {
+	var str string
+	var err error
+
+	sc := explorerSystemContract()
+
+	// Should describe transaction with params and return
+	str, err = sc.DescribeFunction("assets", "ReadAsset")
+	assert.Nil(t, err, "should not error for existing function")
+	assert.Equal(t, "Function ReadAsset of contract assets\nDescription: Returns the asset\nType: evaluate\nParameters:\n  id string - ID of the asset\nReturns: Asset", str, "should describe transaction")
+
+	// Should describe optional params
+	str, _ = sc.DescribeFunction("assets", "CreateAsset")
+	assert.Equal(t, "Function CreateAsset of contract assets\nType: submit\nParameters:\n  id string\n  tags []string (optional)\nReturns: nothing", str, "should mark optional params")
+
+	// Should describe transaction without params
+	str, _ = sc.DescribeFunction("owners", "Count")
+	assert.Equal(t, "Function Count of contract owners\nType: evaluate\nParameters: none\nReturns: integer", str, "should describe transaction without params")
+
+	// Should error when function not found
+	_, err = sc.DescribeFunction("assets", "Missing")
+	assert.EqualError(t, err, "Function Missing not found in contract assets", "should error for missing function")
+
+	// Should error when contract not found
+	_, err = sc.DescribeFunction("missing", "ReadAsset")
+	assert.EqualError(t, err, "Contract not found with name missing", "should error for missing contract")
+}
+
+func TestSummariseSchema(t *testing.T) {
+	assert.Equal(t, "Asset", summariseSchema(*spec.RefSchema("#/components/schemas/Asset")), "should use component name for refs")
+	assert.Equal(t, "[]integer", summariseSchema(*spec.ArrayProperty(spec.Int64Property())), "should summarise arrays")
+	assert.Equal(t, "map[string]Asset", summariseSchema(*spec.MapProperty(spec.RefSchema("#/components/schemas/Asset"))), "should summarise maps")
+	assert.Equal(t, "any", summariseSchema(spec.Schema{}), "should summarise schemas without type as any")
+}
+
+func TestMetadataExplorer(t *testing.T) {
+	cc := convertC2CC(new(simpleTestContract))
+
+	// Should be callable through the system contract
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":ListFunctions", "simpleTestContract"}, invokeType, "DoSomething() string [submit]")
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":DescribeFunction", "simpleTestContract", "DoSomething"}, invokeType, "Function DoSomething of contract simpleTestContract\nType: submit\nParameters: none\nReturns: string")
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// MetadataFormatJSON the format passed to the GetFormattedMetadata transaction of
+	// the system contract to get the metadata as JSON. This is the default.
+	MetadataFormatJSON = "json"
+	// MetadataFormatYAML the format passed to GetFormattedMetadata to get the metadata as YAML
+	MetadataFormatYAML = "yaml"
+	// MetadataFormatMin the format passed to GetFormattedMetadata to get the metadata as JSON
+	// without descriptions, for tooling that only needs the structure of the chaincode
+	MetadataFormatMin = "min"
+)
+
+// configureSystemContract names the parameters of the transactions of the system
+// contract that take optional args and sets their defaults
+func configureSystemContract(sysC *systemContract) {
+	sysC.ConfigureFunction("GetFormattedMetadata").
+		SetParameterNames("format", "componentsOnly").
+		SetDefault("format", MetadataFormatJSON).
+		SetDefault("componentsOnly", "false")
+}
+
+// formatMetadata returns the parsed metadata in the format passed
+func formatMetadata(metadata interface{}, format string) (string, error) {
+	var bytes []byte
+	var err error
+
+	switch format {
+	case MetadataFormatJSON:
+		bytes, err = json.Marshal(metadata)
+	case MetadataFormatYAML:
+		bytes, err = yaml.Marshal(metadata)
+	case MetadataFormatMin:
+		bytes, err = json.Marshal(stripDescriptions(metadata, false))
+	default:
+		return "", fmt.Errorf("Unknown metadata format %s. Expected %s, %s or %s", format, MetadataFormatJSON, MetadataFormatYAML, MetadataFormatMin)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to format metadata as %s. %s", format, err.Error())
+	}
+
+	return string(bytes), nil
+}
+
+// stripDescriptions returns the parsed metadata without description fields.
+// Keys of a properties object are the names of properties rather than fields
+// so are kept.
+func stripDescriptions(metadata interface{}, isProperties bool) interface{} {
+	switch value := metadata.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{})
+
+		for k, v := range value {
+			if k == "description" && !isProperties {
+				continue
+			}
+
+			stripped[k] = stripDescriptions(v, k == "properties" && !isProperties)
+		}
+
+		return stripped
+	case []interface{}:
+		stripped := []interface{}{}
+
+		for _, v := range value {
+			stripped = append(stripped, stripDescriptions(v, false))
+		}
+
+		return stripped
+	default:
+		return metadata
+	}
+}
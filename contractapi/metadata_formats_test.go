@@ -0,0 +1,80 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestFormatMetadata(t *testing.T) {
+	var formatted string
+	var err error
+
+	metadata := map[string]interface{}{"info": map[string]interface{}{"title": "my chaincode"}}
+
+	formatted, err = formatMetadata(metadata, MetadataFormatJSON)
+	assert.Nil(t, err, "should not error for JSON")
+	assert.Equal(t, `{"info":{"title":"my chaincode"}}`, formatted, "should format as JSON")
+
+	formatted, err = formatMetadata(metadata, MetadataFormatYAML)
+	assert.Nil(t, err, "should not error for YAML")
+	assert.Equal(t, "info:\n  title: my chaincode\n", formatted, "should format as YAML")
+
+	_, err = formatMetadata(map[string]interface{}{"bad": make(chan int)}, MetadataFormatJSON)
+	assert.Contains(t, err.Error(), "Failed to format metadata as json.", "should error when cannot be formatted")
+}
+
+func TestStripDescriptions(t *testing.T) {
+	metadata := map[string]interface{}{
+		"description": "removed",
+		"parameters":  []interface{}{map[string]interface{}{"name": "param0", "description": "removed"}},
+		"properties": map[string]interface{}{
+			"description": map[string]interface{}{"type": "string", "description": "removed"},
+			"properties":  map[string]interface{}{"type": "object", "properties": map[string]interface{}{"description": map[string]interface{}{}}},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"parameters": []interface{}{map[string]interface{}{"name": "param0"}},
+		"properties": map[string]interface{}{
+			"description": map[string]interface{}{"type": "string"},
+			"properties":  map[string]interface{}{"type": "object", "properties": map[string]interface{}{"description": map[string]interface{}{}}},
+		},
+	}
+
+	assert.Equal(t, expected, stripDescriptions(metadata, false), "should remove descriptions but not properties named description")
+}
+
+func TestSystemContractMetadataFormats(t *testing.T) {
+	cc := convertC2CC(new(simpleTestContract))
+
+	// Should keep GetMetadata without args returning JSON
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetMetadata"}, invokeType, cc.contracts[SystemContractName].receiver.Interface().(*systemContract).metadata)
+
+	// Should default to JSON
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetFormattedMetadata"}, invokeType, cc.contracts[SystemContractName].receiver.Interface().(*systemContract).metadata)
+
+	// Should return only components when passed
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetFormattedMetadata", MetadataFormatJSON, "true"}, invokeType, "{}")
+
+	// Should error for unknown format
+	callContractFunctionAndCheckError(t, cc, []string{SystemContractName + ":GetFormattedMetadata", "xml"}, invokeType, "Unknown metadata format xml. Expected json, yaml or min")
+}
@@ -0,0 +1,105 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// SkipMetadataValidation has Start run the chaincode even if the metadata file
+// does not match its contracts
+func (cc *ContractChaincode) SkipMetadataValidation() {
+	cc.skipMetadataValidation = true
+}
+
+// validateMetadataFile compares the contracts of the metadata file with those
+// reflected from the chaincode, returning a description of each mismatch. A
+// contract or function of the file must exist in the chaincode and take the same
+// number and types of parameters.
+func validateMetadataFile(fileMetadata ContractChaincodeMetadata, reflectedMetadata ContractChaincodeMetadata) []string {
+	mismatches := []string{}
+
+	contractNames := []string{}
+
+	for name := range fileMetadata.Contracts {
+		contractNames = append(contractNames, name)
+	}
+
+	sort.Strings(contractNames)
+
+	for _, name := range contractNames {
+		reflectedContract, ok := reflectedMetadata.Contracts[name]
+
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("Contract %s not found in chaincode", name))
+			continue
+		}
+
+		reflectedTransactions := make(map[string]TransactionMetadata)
+
+		for _, transaction := range reflectedContract.Transactions {
+			reflectedTransactions[transaction.Name] = transaction
+		}
+
+		for _, transaction := range fileMetadata.Contracts[name].Transactions {
+			reflectedTransaction, ok := reflectedTransactions[transaction.Name]
+
+			if !ok {
+				mismatches = append(mismatches, fmt.Sprintf("Function %s not found in contract %s", transaction.Name, name))
+				continue
+			}
+
+			if len(transaction.Parameters) != len(reflectedTransaction.Parameters) {
+				mismatches = append(mismatches, fmt.Sprintf("Function %s of contract %s has %d parameters. Expected %d", transaction.Name, name, len(transaction.Parameters), len(reflectedTransaction.Parameters)))
+				continue
+			}
+
+			for i, param := range transaction.Parameters {
+				fileType := describeSchemaType(param.Schema)
+				reflectedType := describeSchemaType(reflectedTransaction.Parameters[i].Schema)
+
+				if fileType != "" && reflectedType != "" && fileType != reflectedType {
+					mismatches = append(mismatches, fmt.Sprintf("Parameter %s of function %s of contract %s has type %s. Expected %s", param.Name, transaction.Name, name, fileType, reflectedType))
+				}
+			}
+		}
+	}
+
+	return mismatches
+}
+
+// describeSchemaType returns the type or reference of the schema, or a blank
+// string when it has neither e.g. for interface{} parameters
+func describeSchemaType(schema spec.Schema) string {
+	if ref := schema.Ref.String(); ref != "" {
+		return ref
+	}
+
+	return strings.Join(schema.Type, ",")
+}
+
+// checkMetadataFile returns an error describing the mismatches between the metadata
+// file and the contracts of the chaincode unless metadata validation is skipped
+func (cc *ContractChaincode) checkMetadataFile() error {
+	if len(cc.metadataFileMismatches) == 0 || cc.skipMetadataValidation {
+		return nil
+	}
+
+	return fmt.Errorf("Metadata file does not match contracts of chaincode. Call SkipMetadataValidation to start regardless. %s", strings.Join(cc.metadataFileMismatches, ". "))
+}
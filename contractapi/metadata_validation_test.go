@@ -0,0 +1,124 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func metadataWithTransactions(contract string, transactions ...TransactionMetadata) ContractChaincodeMetadata {
+	ccm := ContractChaincodeMetadata{}
+	ccm.Contracts = map[string]ContractMetadata{
+		contract: {Name: contract, Transactions: transactions},
+	}
+
+	return ccm
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestValidateMetadataFile(t *testing.T) {
+	reflected := metadataWithTransactions("mycontract",
+		TransactionMetadata{Name: "Create", Parameters: []ParameterMetadata{{Name: "param0", Schema: *spec.StringProperty()}, {Name: "param1", Schema: *spec.RefSchema("#/components/schemas/GoodStruct")}}},
+		TransactionMetadata{Name: "Read", Parameters: []ParameterMetadata{{Name: "param0", Schema: *spec.Int64Property()}}},
+	)
+
+	// Should return no mismatches when file has no contracts
+	assert.Equal(t, []string{}, validateMetadataFile(ContractChaincodeMetadata{}, reflected), "should not find mismatches without file")
+
+	// Should return no mismatches for matching file
+	file := metadataWithTransactions("mycontract",
+		TransactionMetadata{Name: "Create", Parameters: []ParameterMetadata{{Name: "id", Description: "the id", Schema: *spec.StringProperty()}, {Name: "asset", Schema: *spec.RefSchema("#/components/schemas/GoodStruct")}}},
+	)
+	assert.Equal(t, []string{}, validateMetadataFile(file, reflected), "should not find mismatches for matching file")
+
+	// Should not compare schemas without type
+	file = metadataWithTransactions("mycontract",
+		TransactionMetadata{Name: "Read", Parameters: []ParameterMetadata{{Name: "id", Schema: spec.Schema{}}}},
+	)
+	assert.Equal(t, []string{}, validateMetadataFile(file, reflected), "should not compare untyped schema")
+
+	// Should return mismatch for unknown contract
+	file = metadataWithTransactions("othercontract")
+	assert.Equal(t, []string{"Contract othercontract not found in chaincode"}, validateMetadataFile(file, reflected), "should find unknown contract")
+
+	// Should return mismatches for unknown function, wrong param count and wrong types
+	file = metadataWithTransactions("mycontract",
+		TransactionMetadata{Name: "Delete"},
+		TransactionMetadata{Name: "Create", Parameters: []ParameterMetadata{{Name: "id", Schema: *spec.StringProperty()}}},
+		TransactionMetadata{Name: "Read", Parameters: []ParameterMetadata{{Name: "id", Schema: *spec.StringProperty()}}},
+	)
+	assert.Equal(t, []string{
+		"Function Delete not found in contract mycontract",
+		"Function Create of contract mycontract has 1 parameters. Expected 2",
+		"Parameter id of function Read of contract mycontract has type string. Expected integer",
+	}, validateMetadataFile(file, reflected), "should find function mismatches")
+
+	// Should return mismatch for wrong reference
+	file = metadataWithTransactions("mycontract",
+		TransactionMetadata{Name: "Create", Parameters: []ParameterMetadata{{Name: "id", Schema: *spec.StringProperty()}, {Name: "asset", Schema: *spec.RefSchema("#/components/schemas/BadStruct")}}},
+	)
+	assert.Equal(t, []string{"Parameter asset of function Create of contract mycontract has type #/components/schemas/BadStruct. Expected #/components/schemas/GoodStruct"}, validateMetadataFile(file, reflected), "should find reference mismatch")
+}
+
+func TestDescribeSchemaType(t *testing.T) {
+	assert.Equal(t, "string", describeSchemaType(*spec.StringProperty()), "should describe type")
+	assert.Equal(t, "#/components/schemas/GoodStruct", describeSchemaType(*spec.RefSchema("#/components/schemas/GoodStruct")), "should describe reference")
+	assert.Equal(t, "", describeSchemaType(spec.Schema{}), "should return blank for schema without type")
+}
+
+func TestCheckMetadataFile(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should not error when no mismatches
+	assert.Nil(t, cc.checkMetadataFile(), "should not error without mismatches")
+
+	// Should error with mismatches
+	cc.metadataFileMismatches = []string{"Contract a not found in chaincode", "Contract b not found in chaincode"}
+	assert.EqualError(t, cc.checkMetadataFile(), "Metadata file does not match contracts of chaincode. Call SkipMetadataValidation to start regardless. Contract a not found in chaincode. Contract b not found in chaincode", "should error with mismatches")
+
+	// Should not error when validation skipped
+	cc.SkipMetadataValidation()
+	assert.Nil(t, cc.checkMetadataFile(), "should not error when skipping validation")
+}
+
+func TestStartWithMetadataFileMismatches(t *testing.T) {
+	called := false
+	restore := stubShimStart(func(shim.Chaincode) error {
+		called = true
+		return errors.New("shim failure")
+	})
+	defer restore()
+
+	cc := convertC2CC(new(simpleTestContract))
+	cc.metadataFileMismatches = []string{"Contract a not found in chaincode"}
+
+	// Should fail before starting shim
+	err := cc.StartWithContext(context.Background())
+	assert.EqualError(t, err, "Metadata file does not match contracts of chaincode. Call SkipMetadataValidation to start regardless. Contract a not found in chaincode", "should return mismatches")
+	assert.False(t, called, "should not start shim")
+}
@@ -0,0 +1,245 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// MetricsPath the path metrics are served at when enabled
+const MetricsPath = "/metrics"
+
+var metricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type histogram struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type chaincodeMetrics struct {
+	sync.Mutex
+	transactions   map[string]float64
+	durations      map[string]*histogram
+	argumentErrors map[string]float64
+	hookDurations  map[string]*histogram
+}
+
+func newChaincodeMetrics() *chaincodeMetrics {
+	metrics := new(chaincodeMetrics)
+	metrics.transactions = make(map[string]float64)
+	metrics.durations = make(map[string]*histogram)
+	metrics.argumentErrors = make(map[string]float64)
+	metrics.hookDurations = make(map[string]*histogram)
+
+	return metrics
+}
+
+// EnableMetrics sets Start to serve metrics of the chaincode's transactions in the
+// Prometheus text format at /metrics on the passed address, e.g. ":9443", until
+// the chaincode stops. The metrics count the transactions of each function by
+// status, the arguments that fail to convert to the parameters of a function
+// and the durations of transactions and their before and after functions.
+func (cc *ContractChaincode) EnableMetrics(address string) {
+	cc.metricsAddress = address
+	cc.metrics = newChaincodeMetrics()
+}
+
+func (cc *ContractChaincode) startMetricsServer() (*http.Server, error) {
+	listener, err := net.Listen("tcp", cc.metricsAddress)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start metrics listener. %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		cc.metrics.write(w)
+	})
+
+	server := &http.Server{Handler: mux}
+
+	go server.Serve(listener)
+
+	return server, nil
+}
+
+// metricsLabels returns the labels for the transaction, using the name of the
+// function an alias or name in another case resolves to and replacing names of
+// contracts and functions that do not exist so that callers cannot create
+// metrics for any name they pass
+func (cc *ContractChaincode) metricsLabels(ns string, fn string) (string, string) {
+	contract, ok := cc.contracts[ns]
+
+	if !ok {
+		return "unknown", "unknown"
+	}
+
+	fn = cc.resolveFunctionName(ns, fn)
+
+	if _, ok := contract.functions[fn]; !ok && !(cc.batchInvocation && ns == SystemContractName && fn == BatchTransactionName) {
+		return ns, "unknown"
+	}
+
+	return ns, fn
+}
+
+func (cc *ContractChaincode) measureInvoke(stub shim.ChaincodeStubInterface, invoke func(shim.ChaincodeStubInterface) peer.Response) peer.Response {
+	nsFcn, _ := stub.GetFunctionAndParameters()
+
+	ns, fn := cc.metricsLabels(cc.splitFunctionName(nsFcn))
+
+	start := time.Now()
+
+	response := invoke(stub)
+
+	status := "success"
+
+	if response.Status >= shim.ERRORTHRESHOLD {
+		status = "error"
+	}
+
+	cc.metrics.Lock()
+	defer cc.metrics.Unlock()
+
+	labels := formatLabels("contract", ns, "function", fn)
+
+	cc.metrics.transactions[labels+","+formatLabels("status", status)]++
+	cc.metrics.durations[labels] = observe(cc.metrics.durations[labels], time.Since(start))
+
+	return response
+}
+
+func (cc *ContractChaincode) recordArgumentError(ns string, fn string) {
+	if cc.metrics == nil {
+		return
+	}
+
+	cc.metrics.Lock()
+	defer cc.metrics.Unlock()
+
+	cc.metrics.argumentErrors[formatLabels("contract", ns, "function", fn)]++
+}
+
+// callHook calls the before or after transaction, recording its duration if
+// metrics are enabled
+func (cc *ContractChaincode) callHook(handler *transactionHandler, ns string, ctx reflect.Value, iface interface{}, serializer Serializer) (interface{}, error) {
+	start := time.Now()
+
+	_, result, err := handler.call(ctx, iface, serializer)
+
+	if cc.metrics != nil {
+		cc.metrics.Lock()
+		defer cc.metrics.Unlock()
+
+		labels := formatLabels("contract", ns, "hook", strings.ToLower(handler.handlesType.String()))
+		cc.metrics.hookDurations[labels] = observe(cc.metrics.hookDurations[labels], time.Since(start))
+	}
+
+	return result, err
+}
+
+func observe(h *histogram, duration time.Duration) *histogram {
+	if h == nil {
+		h = &histogram{buckets: make([]uint64, len(metricsBuckets))}
+	}
+
+	seconds := duration.Seconds()
+
+	for i, bound := range metricsBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+
+	h.sum += seconds
+	h.count++
+
+	return h
+}
+
+func formatLabels(namesAndValues ...string) string {
+	labels := []string{}
+
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, namesAndValues[i], labelValueReplacer.Replace(namesAndValues[i+1])))
+	}
+
+	return strings.Join(labels, ",")
+}
+
+func (m *chaincodeMetrics) write(w io.Writer) {
+	m.Lock()
+	defer m.Unlock()
+
+	writeCounter(w, "chaincode_transactions_total", "Transactions called by contract, function and status.", m.transactions)
+	writeHistogram(w, "chaincode_transaction_duration_seconds", "Duration of transactions by contract and function.", m.durations)
+	writeCounter(w, "chaincode_argument_errors_total", "Transactions whose arguments failed to convert to the parameters of the function by contract and function.", m.argumentErrors)
+	writeHistogram(w, "chaincode_hook_duration_seconds", "Duration of before and after transactions by contract and hook.", m.hookDurations)
+}
+
+func writeCounter(w io.Writer, name string, help string, values map[string]float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+
+	keys := []string{}
+
+	for labels := range values {
+		keys = append(keys, labels)
+	}
+
+	sort.Strings(keys)
+
+	for _, labels := range keys {
+		fmt.Fprintf(w, "%s{%s} %v\n", name, labels, values[labels])
+	}
+}
+
+func writeHistogram(w io.Writer, name string, help string, values map[string]*histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	keys := []string{}
+
+	for labels := range values {
+		keys = append(keys, labels)
+	}
+
+	sort.Strings(keys)
+
+	for _, labels := range keys {
+		h := values[labels]
+
+		for i, bound := range metricsBuckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%v\"} %d\n", name, labels, bound, h.buckets[i])
+		}
+
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %v\n", name, labels, h.sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+}
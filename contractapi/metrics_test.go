@@ -0,0 +1,160 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func writeMetrics(cc ContractChaincode) string {
+	buf := new(bytes.Buffer)
+	cc.metrics.write(buf)
+
+	return buf.String()
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableMetrics(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableMetrics(":9443")
+
+	assert.Equal(t, ":9443", cc.metricsAddress, "should set the metrics address")
+	assert.NotNil(t, cc.metrics, "should create metrics")
+}
+
+func TestMetricsLabels(t *testing.T) {
+	var contract, function string
+
+	mc := new(myContract)
+	mc.AddFunctionAlias("GetString", "ReturnsString")
+	cc := convertC2CC(mc)
+
+	contract, function = cc.metricsLabels("myContract", "ReturnsString")
+	assert.Equal(t, []string{"myContract", "ReturnsString"}, []string{contract, function}, "should use names of existing function")
+
+	contract, function = cc.metricsLabels("myContract", "Missing")
+	assert.Equal(t, []string{"myContract", "unknown"}, []string{contract, function}, "should replace name of missing function")
+
+	contract, function = cc.metricsLabels("missing", "Missing")
+	assert.Equal(t, []string{"unknown", "unknown"}, []string{contract, function}, "should replace names of missing contract")
+
+	contract, function = cc.metricsLabels(SystemContractName, BatchTransactionName)
+	assert.Equal(t, "unknown", function, "should replace batch function when batch invocation not enabled")
+
+	cc.EnableBatchInvocation()
+	contract, function = cc.metricsLabels(SystemContractName, BatchTransactionName)
+	assert.Equal(t, BatchTransactionName, function, "should use batch function when batch invocation enabled")
+
+	contract, function = cc.metricsLabels("myContract", "GetString")
+	assert.Equal(t, "ReturnsString", function, "should use name of function alias resolves to")
+
+	cc.EnableCaseInsensitiveFunctions()
+	contract, function = cc.metricsLabels("myContract", "returnsstring")
+	assert.Equal(t, "ReturnsString", function, "should use name of function case insensitive name resolves to")
+}
+
+func TestMeasureInvoke(t *testing.T) {
+	mc := new(myContract)
+	mc.SetBeforeTransaction(mc.logBefore)
+
+	cc := convertC2CC(mc)
+	cc.SetAfterTransaction(func() {})
+
+	// Should not record metrics when not enabled
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ReturnsString"}, invokeType, "Some string")
+
+	cc.EnableMetrics(":0")
+
+	// Should count transactions by status and record durations
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ReturnsString"}, invokeType, "Some string")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, "Some string")
+	callContractFunctionAndCheckError(t, cc, []string{"ReturnsError"}, invokeType, "Some error")
+	callContractFunctionAndCheckError(t, cc, []string{"missing:Function"}, invokeType, "Contract not found with name missing")
+
+	metrics := writeMetrics(cc)
+	assert.Contains(t, metrics, "# TYPE chaincode_transactions_total counter\n", "should write counter type")
+	assert.Contains(t, metrics, `chaincode_transactions_total{contract="myContract",function="ReturnsString",status="success"} 2`, "should count successful transactions")
+	assert.Contains(t, metrics, `chaincode_transactions_total{contract="myContract",function="ReturnsError",status="error"} 1`, "should count failed transactions")
+	assert.Contains(t, metrics, `chaincode_transactions_total{contract="unknown",function="unknown",status="error"} 1`, "should count transactions of unknown contracts")
+	assert.Contains(t, metrics, "# TYPE chaincode_transaction_duration_seconds histogram\n", "should write histogram type")
+	assert.Contains(t, metrics, `chaincode_transaction_duration_seconds_bucket{contract="myContract",function="ReturnsString",le="+Inf"} 2`, "should record durations")
+	assert.Contains(t, metrics, `chaincode_transaction_duration_seconds_count{contract="myContract",function="ReturnsString"} 2`, "should count durations")
+
+	// Should record durations of before and after transactions
+	assert.Contains(t, metrics, `chaincode_hook_duration_seconds_count{contract="myContract",hook="before"} 3`, "should record before durations of transactions of existing contracts")
+	assert.Contains(t, metrics, `chaincode_hook_duration_seconds_count{contract="myContract",hook="after"} 2`, "should record after durations of successful transactions")
+
+	// Should count arguments that fail to convert
+	callContractFunctionAndCheckError(t, cc, []string{"UsesSlices", "not json"}, invokeType, "Failed to convert arg for parameter 0 (param0) of function UsesSlices. Expected type []string, received \"not json\". Value not json was not passed in expected format []string")
+	assert.Contains(t, writeMetrics(cc), `chaincode_argument_errors_total{contract="myContract",function="UsesSlices"} 1`, "should count argument errors")
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, `contract="org.asset",function="Create"`, formatLabels("contract", "org.asset", "function", "Create"), "should format labels")
+	assert.Equal(t, `function="a\\b\"c\nd"`, formatLabels("function", "a\\b\"c\nd"), "should escape label values")
+}
+
+func TestObserve(t *testing.T) {
+	h := observe(nil, 20*time.Millisecond)
+	h = observe(h, 2*time.Second)
+
+	assert.Equal(t, []uint64{0, 0, 1, 1, 1, 1, 1, 1, 2, 2, 2}, h.buckets, "should count observations in each bucket at or above them")
+	assert.Equal(t, uint64(2), h.count, "should count observations")
+	assert.InDelta(t, 2.02, h.sum, 0.0001, "should sum observations")
+}
+
+func TestStartMetricsServer(t *testing.T) {
+	var err error
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	listener.Close()
+
+	// Should serve metrics at path
+	cc := convertC2CC(new(myContract))
+	cc.EnableMetrics(address)
+	server, err := cc.startMetricsServer()
+	assert.Nil(t, err, "should not error starting server")
+	defer server.Close()
+
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ReturnsString"}, invokeType, "Some string")
+
+	response, err := http.Get("http://" + address + MetricsPath)
+	assert.Nil(t, err, "should serve metrics")
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	assert.Equal(t, "text/plain; version=0.0.4", response.Header.Get("Content-Type"), "should serve prometheus text format")
+	assert.Contains(t, string(body), `chaincode_transactions_total{contract="myContract",function="ReturnsString",status="success"} 1`, "should serve recorded metrics")
+
+	// Should error when cannot listen on address
+	other := convertC2CC(new(myContract))
+	other.EnableMetrics(address)
+	_, err = other.startMetricsServer()
+	assert.Contains(t, err.Error(), "Failed to start metrics listener.", "should error when address in use")
+}
@@ -0,0 +1,198 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package migrations provides a helper for rewriting assets stored in the world
+// state in a new format, e.g. after the struct of an asset changes, from within
+// the transactions of a contract
+package migrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// ErrSkip can be returned by a TransformFunc to leave the value of a key as it is
+var ErrSkip = errors.New("Skip key")
+
+// TransformFunc is passed the key and the old value, decoded into a pointer to a
+// value of the OldType of the migration, and returns the value to store in its place.
+// Returning an error stops the migration, unless it is ErrSkip.
+type TransformFunc func(key string, old interface{}) (interface{}, error)
+
+// Progress reports how far a migration has got. Bookmark is the key to start the
+// next batch from and is blank once every key in the range has been processed.
+type Progress struct {
+	Migrated int    `json:"migrated"`
+	Skipped  int    `json:"skipped"`
+	LastKey  string `json:"lastKey"`
+	Bookmark string `json:"bookmark"`
+	Done     bool   `json:"done"`
+}
+
+// Migration describes how to rewrite the values of a range of keys. Values are
+// decoded using OldCodec and encoded using NewCodec, which default to the codec
+// registered for the type of the value (see ledgerapi.RegisterCodec) or JSON.
+type Migration struct {
+	// StartKey and EndKey give the range of keys to migrate as for GetStateByRange
+	StartKey string
+	EndKey   string
+
+	// OldType is a value of the type stored in the old format e.g. OldAsset{}
+	OldType interface{}
+
+	OldCodec  ledgerapi.Codec
+	NewCodec  ledgerapi.Codec
+	Transform TransformFunc
+
+	// BatchSize limits the number of keys migrated by a call to Run so that large
+	// ranges can be migrated over several transactions. Zero migrates every key.
+	BatchSize int
+
+	// OnProgress, if set, is called after each key is processed
+	OnProgress func(Progress)
+
+	// EventName, if set, is the name of the chaincode event set with the JSON
+	// formatted progress once the batch is written
+	EventName string
+}
+
+type write struct {
+	key   string
+	value []byte
+}
+
+// Run migrates the keys of the migration from the bookmark, or the start key
+// if the bookmark is blank, until the batch size is reached. Every value in
+// the batch is decoded, transformed and encoded before any is written so that
+// an error leaves the world state untouched. Returns the progress of the
+// migration, with a bookmark to pass to the next call if keys remain.
+func Run(stub shim.ChaincodeStubInterface, migration Migration, bookmark string) (Progress, error) {
+	progress := Progress{}
+
+	if migration.OldType == nil || migration.Transform == nil {
+		return progress, errors.New("Migration must set OldType and Transform")
+	}
+
+	startKey := migration.StartKey
+
+	if bookmark != "" {
+		startKey = bookmark
+	}
+
+	iterator, err := stub.GetStateByRange(startKey, migration.EndKey)
+
+	if err != nil {
+		return progress, fmt.Errorf("Failed to get states in range %s to %s. %s", startKey, migration.EndKey, err.Error())
+	}
+
+	defer iterator.Close()
+
+	writes := []write{}
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return progress, fmt.Errorf("Failed to get next state. %s", err.Error())
+		}
+
+		if migration.BatchSize > 0 && progress.Migrated+progress.Skipped == migration.BatchSize {
+			progress.Bookmark = kv.Key
+			break
+		}
+
+		value, err := migration.migrate(kv.Key, kv.Value)
+
+		if err == ErrSkip {
+			progress.Skipped++
+		} else if err != nil {
+			return progress, err
+		} else {
+			progress.Migrated++
+			writes = append(writes, write{kv.Key, value})
+		}
+
+		progress.LastKey = kv.Key
+
+		if migration.OnProgress != nil {
+			migration.OnProgress(progress)
+		}
+	}
+
+	for _, w := range writes {
+		if err := stub.PutState(w.key, w.value); err != nil {
+			return progress, fmt.Errorf("Failed to put state for key %s. %s", w.key, err.Error())
+		}
+	}
+
+	progress.Done = progress.Bookmark == ""
+
+	if migration.EventName != "" {
+		payload, _ := json.Marshal(progress)
+
+		if err := stub.SetEvent(migration.EventName, payload); err != nil {
+			return progress, fmt.Errorf("Failed to set event %s. %s", migration.EventName, err.Error())
+		}
+	}
+
+	return progress, nil
+}
+
+// migrate returns the value of the key in the new format, or ErrSkip
+func (m Migration) migrate(key string, bytes []byte) ([]byte, error) {
+	oldType := reflect.TypeOf(m.OldType)
+
+	for oldType.Kind() == reflect.Ptr {
+		oldType = oldType.Elem()
+	}
+
+	old := reflect.New(oldType)
+
+	if err := getCodec(m.OldCodec, old.Interface()).Decode(bytes, old.Interface()); err != nil {
+		return nil, fmt.Errorf("Failed to decode value of key %s. %s", key, err.Error())
+	}
+
+	value, err := m.Transform(key, old.Interface())
+
+	if err == ErrSkip {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to transform value of key %s. %s", key, err.Error())
+	}
+
+	encoded, err := getCodec(m.NewCodec, value).Encode(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode value of key %s. %s", key, err.Error())
+	}
+
+	return encoded, nil
+}
+
+func getCodec(codec ledgerapi.Codec, value interface{}) ledgerapi.Codec {
+	if codec != nil {
+		return codec
+	}
+
+	if registered := ledgerapi.GetRegisteredCodec(value); registered != nil {
+		return registered
+	}
+
+	return new(ledgerapi.JSONCodec)
+}
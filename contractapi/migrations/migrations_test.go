@@ -0,0 +1,171 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type oldAsset struct {
+	Value int `json:"value"`
+}
+
+type newAsset struct {
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+func toNewAsset(key string, old interface{}) (interface{}, error) {
+	return newAsset{old.(*oldAsset).Value, "GBP"}, nil
+}
+
+func newMigrationTestStub(values map[string]string) *shimtest.MockStub {
+	stub := shimtest.NewMockStub("migrationTest", nil)
+	stub.MockTransactionStart("setup")
+
+	for key, value := range values {
+		stub.PutState(key, []byte(value))
+	}
+
+	stub.MockTransactionEnd("setup")
+	stub.MockTransactionStart("migrate")
+
+	return stub
+}
+
+func getStateString(stub shim.ChaincodeStubInterface, key string) string {
+	value, _ := stub.GetState(key)
+
+	return string(value)
+}
+
+type failingPutStub struct {
+	*shimtest.MockStub
+}
+
+func (fps *failingPutStub) PutState(key string, value []byte) error {
+	return errors.New("put failed")
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestRun(t *testing.T) {
+	var stub *shimtest.MockStub
+	var progress Progress
+	var err error
+
+	values := map[string]string{"asset1": "{\"value\":1}", "asset2": "{\"value\":2}", "asset3": "{\"value\":3}", "other": "{\"value\":4}"}
+	migration := Migration{StartKey: "asset", EndKey: "asset~", OldType: oldAsset{}, Transform: toNewAsset}
+
+	// Should error for incomplete migration
+	_, err = Run(newMigrationTestStub(values), Migration{}, "")
+	assert.EqualError(t, err, "Migration must set OldType and Transform", "should error when migration incomplete")
+
+	// Should migrate every key in range
+	stub = newMigrationTestStub(values)
+	progress, err = Run(stub, migration, "")
+	assert.Nil(t, err, "should not error for valid migration")
+	assert.Equal(t, Progress{Migrated: 3, LastKey: "asset3", Done: true}, progress, "should return progress of completed migration")
+	assert.Equal(t, "{\"amount\":1,\"unit\":\"GBP\"}", getStateString(stub, "asset1"), "should rewrite value in new format")
+	assert.Equal(t, "{\"amount\":3,\"unit\":\"GBP\"}", getStateString(stub, "asset3"), "should rewrite every value in range")
+	assert.Equal(t, "{\"value\":4}", getStateString(stub, "other"), "should not rewrite values outside range")
+
+	// Should migrate in batches
+	stub = newMigrationTestStub(values)
+	migration.BatchSize = 2
+	progress, err = Run(stub, migration, "")
+	assert.Nil(t, err, "should not error for first batch")
+	assert.Equal(t, Progress{Migrated: 2, LastKey: "asset2", Bookmark: "asset3"}, progress, "should return bookmark of next batch")
+	assert.Equal(t, "{\"value\":3}", getStateString(stub, "asset3"), "should not rewrite values after batch")
+
+	progress, err = Run(stub, migration, progress.Bookmark)
+	assert.Nil(t, err, "should not error for second batch")
+	assert.Equal(t, Progress{Migrated: 1, LastKey: "asset3", Done: true}, progress, "should complete from bookmark")
+	assert.Equal(t, "{\"amount\":3,\"unit\":\"GBP\"}", getStateString(stub, "asset3"), "should rewrite values of second batch")
+
+	// Should skip keys and report progress
+	stub = newMigrationTestStub(values)
+	reported := []Progress{}
+	migration.BatchSize = 0
+	migration.OnProgress = func(p Progress) { reported = append(reported, p) }
+	migration.Transform = func(key string, old interface{}) (interface{}, error) {
+		if key == "asset2" {
+			return nil, ErrSkip
+		}
+
+		return toNewAsset(key, old)
+	}
+	progress, err = Run(stub, migration, "")
+	assert.Nil(t, err, "should not error when skipping keys")
+	assert.Equal(t, 1, progress.Skipped, "should count skipped keys")
+	assert.Equal(t, "{\"value\":2}", getStateString(stub, "asset2"), "should not rewrite skipped keys")
+	assert.Equal(t, []Progress{{Migrated: 1, LastKey: "asset1"}, {Migrated: 1, Skipped: 1, LastKey: "asset2"}, {Migrated: 2, Skipped: 1, LastKey: "asset3"}}, reported, "should report progress after each key")
+
+	// Should set event with progress
+	stub = newMigrationTestStub(values)
+	migration = Migration{StartKey: "asset", EndKey: "asset~", OldType: &oldAsset{}, Transform: toNewAsset, EventName: "migrated"}
+	progress, err = Run(stub, migration, "")
+	assert.Nil(t, err, "should not error when setting event")
+	event := <-stub.ChaincodeEventsChannel
+	expectedPayload, _ := json.Marshal(progress)
+	assert.Equal(t, "migrated", event.EventName, "should set event with name")
+	assert.Equal(t, expectedPayload, event.Payload, "should set event with progress")
+
+	// Should use codecs passed
+	stub = newMigrationTestStub(map[string]string{"asset1": "old"})
+	migration = Migration{StartKey: "asset", EndKey: "asset~", OldType: "", OldCodec: new(ledgerapi.StringCodec), NewCodec: new(ledgerapi.JSONCodec), Transform: func(key string, old interface{}) (interface{}, error) {
+		return map[string]string{"status": *old.(*string)}, nil
+	}}
+	_, err = Run(stub, migration, "")
+	assert.Nil(t, err, "should not error using codecs")
+	assert.Equal(t, "{\"status\":\"old\"}", getStateString(stub, "asset1"), "should decode and encode using codecs")
+
+	// Should not write any values when a value fails
+	stub = newMigrationTestStub(values)
+	migration = Migration{StartKey: "asset", EndKey: "asset~", OldType: oldAsset{}, Transform: func(key string, old interface{}) (interface{}, error) {
+		if key == "asset3" {
+			return nil, errors.New("bad asset")
+		}
+
+		return toNewAsset(key, old)
+	}}
+	_, err = Run(stub, migration, "")
+	assert.EqualError(t, err, "Failed to transform value of key asset3. bad asset", "should error when transform fails")
+	assert.Equal(t, "{\"value\":1}", getStateString(stub, "asset1"), "should not write values before failure")
+
+	stub = newMigrationTestStub(map[string]string{"asset1": "not json"})
+	_, err = Run(stub, Migration{StartKey: "asset", EndKey: "asset~", OldType: oldAsset{}, Transform: toNewAsset}, "")
+	assert.Contains(t, err.Error(), "Failed to decode value of key asset1.", "should error when decode fails")
+
+	stub = newMigrationTestStub(values)
+	_, err = Run(stub, Migration{StartKey: "asset", EndKey: "asset~", OldType: oldAsset{}, NewCodec: new(ledgerapi.StringCodec), Transform: toNewAsset}, "")
+	assert.EqualError(t, err, "Failed to encode value of key asset1. Type migrations.newAsset is not a string type", "should error when encode fails")
+
+	_, err = Run(&failingPutStub{newMigrationTestStub(values)}, Migration{StartKey: "asset", EndKey: "asset~", OldType: oldAsset{}, Transform: toNewAsset}, "")
+	assert.EqualError(t, err, "Failed to put state for key asset1. put failed", "should error when put fails")
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NameValidator checks a contract name, or alias, meets conventions beyond the
+// name grammar, returning an error describing why it does not
+type NameValidator func(name string) error
+
+// SetNameValidator sets a validator called by Start for the name and aliases of
+// each contract of the chaincode, other than the system contract, so that
+// organisations can enforce their own conventions e.g. a required prefix. Names
+// are checked against the name grammar (see ValidateName) by CreateNewChaincode.
+// Start returns an error for the first name the validator rejects. Passing nil
+// removes the validator.
+func (cc *ContractChaincode) SetNameValidator(validator NameValidator) {
+	cc.nameValidator = validator
+}
+
+// ValidateName checks the name meets the grammar of contract names. A name is one
+// or more segments separated by dots, where each segment is one or more Unicode
+// letters, digits, underscores or hyphens, e.g. "org.example.asset" or "資産".
+// Names may not contain colons as these separate the contract name from the
+// function name in the first arg of a transaction.
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("Name must not be empty")
+	}
+
+	for _, segment := range strings.Split(name, ".") {
+		if segment == "" {
+			return errors.New("Name must not start or end with a dot or contain consecutive dots")
+		}
+
+		for _, r := range segment {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+				return fmt.Errorf("Name must only contain letters, digits, underscores, hyphens and dots. Found %q", r)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkContractNames calls the name validator for the name and aliases of each
+// contract other than the system contract
+func (cc *ContractChaincode) checkContractNames() error {
+	if cc.nameValidator == nil {
+		return nil
+	}
+
+	names := []string{}
+
+	for name := range cc.contracts {
+		if name != SystemContractName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := cc.nameValidator(name); err != nil {
+			return fmt.Errorf("Invalid name %s for contract. %s", name, err.Error())
+		}
+	}
+
+	aliases := []string{}
+
+	for alias, name := range cc.aliases {
+		if name != SystemContractName {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	sort.Strings(aliases)
+
+	for _, alias := range aliases {
+		if err := cc.nameValidator(alias); err != nil {
+			return fmt.Errorf("Invalid alias %s for contract %s. %s", alias, cc.aliases[alias], err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestSetNameValidator(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.SetNameValidator(func(name string) error { return nil })
+	assert.NotNil(t, cc.nameValidator, "should set validator")
+
+	cc.SetNameValidator(nil)
+	assert.Nil(t, cc.nameValidator, "should remove validator")
+}
+
+func TestValidateName(t *testing.T) {
+	// Should accept dotted names of letters, digits, underscores and hyphens
+	assert.Nil(t, ValidateName("org.example.asset"), "should accept dotted name")
+	assert.Nil(t, ValidateName("simple_asset-v2"), "should accept underscores, hyphens and digits")
+	assert.Nil(t, ValidateName("org.例え.資産"), "should accept unicode letters")
+	assert.Nil(t, ValidateName(SystemContractName), "should accept system contract name")
+
+	// Should reject empty names and segments
+	assert.EqualError(t, ValidateName(""), "Name must not be empty", "should reject empty name")
+	assert.EqualError(t, ValidateName(".asset"), "Name must not start or end with a dot or contain consecutive dots", "should reject leading dot")
+	assert.EqualError(t, ValidateName("asset."), "Name must not start or end with a dot or contain consecutive dots", "should reject trailing dot")
+	assert.EqualError(t, ValidateName("org..asset"), "Name must not start or end with a dot or contain consecutive dots", "should reject consecutive dots")
+
+	// Should reject other characters
+	assert.EqualError(t, ValidateName("org:asset"), "Name must only contain letters, digits, underscores, hyphens and dots. Found ':'", "should reject colon")
+	assert.EqualError(t, ValidateName("some asset"), "Name must only contain letters, digits, underscores, hyphens and dots. Found ' '", "should reject space")
+}
+
+func TestContractNameValidation(t *testing.T) {
+	var mtc *middlewareTestContract
+
+	// Should panic when name invalid
+	mtc = new(middlewareTestContract)
+	mtc.SetName("org:asset")
+	assert.PanicsWithValue(t, "Invalid name org:asset for contract. Name must only contain letters, digits, underscores, hyphens and dots. Found ':'", func() { convertC2CC(mtc) }, "should panic for invalid name")
+
+	// Should panic when alias invalid
+	mtc = new(middlewareTestContract)
+	mtc.AddNameAlias("simple asset")
+	assert.PanicsWithValue(t, "Invalid alias simple asset for contract middlewareTestContract. Name must only contain letters, digits, underscores, hyphens and dots. Found ' '", func() { convertC2CC(mtc) }, "should panic for invalid alias")
+}
+
+func TestCheckContractNames(t *testing.T) {
+	var cc ContractChaincode
+	var mtc *middlewareTestContract
+
+	validator := func(name string) error {
+		if !strings.HasPrefix(name, "org.example.") {
+			return errors.New("Name must start with org.example.")
+		}
+
+		return nil
+	}
+
+	// Should not check names without validator
+	cc = convertC2CC(new(middlewareTestContract))
+	assert.Nil(t, cc.checkContractNames(), "should not error without validator")
+
+	// Should check names other than system contract
+	mtc = new(middlewareTestContract)
+	mtc.SetName("org.example.asset")
+	cc = convertC2CC(mtc)
+	cc.SetNameValidator(validator)
+	assert.Nil(t, cc.checkContractNames(), "should not error for name passing validator")
+
+	cc = convertC2CC(new(middlewareTestContract))
+	cc.SetNameValidator(validator)
+	assert.EqualError(t, cc.checkContractNames(), "Invalid name middlewareTestContract for contract. Name must start with org.example.", "should error for name failing validator")
+
+	// Should check aliases
+	mtc.AddNameAlias("asset")
+	cc = convertC2CC(mtc)
+	cc.SetNameValidator(validator)
+	assert.EqualError(t, cc.checkContractNames(), "Invalid alias asset for contract org.example.asset. Name must start with org.example.", "should error for alias failing validator")
+
+	// Should not start chaincode when name fails validator
+	restore := stubShimStart(func(shim.Chaincode) error {
+		t.Error("should not start shim")
+		return nil
+	})
+	defer restore()
+
+	cc = convertC2CC(new(middlewareTestContract))
+	cc.SetNameValidator(validator)
+	assert.EqualError(t, cc.Start(), "Invalid name middlewareTestContract for contract. Name must start with org.example.", "should return error from start")
+}
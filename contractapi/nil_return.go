@@ -0,0 +1,71 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import "reflect"
+
+// NilReturn defines the payload returned on success by a function that declares
+// a success return type when the value it returns is nil
+type NilReturn int
+
+const (
+	// EmptyNilReturn returns the nil value as converted by the serializer, an
+	// empty payload for the JSONSerializer. This is the default
+	EmptyNilReturn NilReturn = iota
+	// NullNilReturn returns the JSON null
+	NullNilReturn
+	// EnvelopeNilReturn returns the JSON acknowledgement {"result":null,"status":"OK"}
+	EnvelopeNilReturn
+)
+
+const nullResponse = "null"
+
+const envelopeNilResponse = "{\"result\":null,\"status\":\"OK\"}"
+
+// SetNilReturn sets the payload returned when the function returns a nil pointer,
+// slice, map or interface without error, as some client SDKs reject an empty
+// payload while others require one. Functions that do not declare a success
+// return type are unaffected (see ContractChaincode.SetVoidResponse).
+func (fc *FunctionConfig) SetNilReturn(nr NilReturn) *FunctionConfig {
+	fc.nilReturn = nr
+	return fc
+}
+
+// nilReturnResponse returns the payload for a nil value according to the config
+// of the function, or the payload as converted by the serializer if none is set
+func (fc *FunctionConfig) nilReturnResponse(serialized string) string {
+	if fc == nil {
+		return serialized
+	}
+
+	switch fc.nilReturn {
+	case NullNilReturn:
+		return nullResponse
+	case EnvelopeNilReturn:
+		return envelopeNilResponse
+	default:
+		return serialized
+	}
+}
+
+func isNilReturn(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+
+	return isNillableType(rv.Kind()) && rv.IsNil()
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type nilReturnTestContract struct {
+	Contract
+}
+
+func (nrtc *nilReturnTestContract) Find(found bool) *GoodStruct {
+	if !found {
+		return nil
+	}
+
+	return &GoodStruct{Prop1: "value", Prop2: 1}
+}
+
+func (nrtc *nilReturnTestContract) List() []string {
+	return nil
+}
+
+func (nrtc *nilReturnTestContract) Empty() string {
+	return ""
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetNilReturn(t *testing.T) {
+	fc := new(FunctionConfig)
+
+	assert.Same(t, fc, fc.SetNilReturn(NullNilReturn), "should return config")
+	assert.Equal(t, NullNilReturn, fc.nilReturn, "should set nil return")
+}
+
+func TestNilReturnResponse(t *testing.T) {
+	var fc *FunctionConfig
+
+	assert.Equal(t, "serialized", fc.nilReturnResponse("serialized"), "should return serialized value when no config")
+	assert.Equal(t, "serialized", new(FunctionConfig).nilReturnResponse("serialized"), "should return serialized value by default")
+	assert.Equal(t, "null", new(FunctionConfig).SetNilReturn(NullNilReturn).nilReturnResponse("serialized"), "should return null")
+	assert.Equal(t, "{\"result\":null,\"status\":\"OK\"}", new(FunctionConfig).SetNilReturn(EnvelopeNilReturn).nilReturnResponse("serialized"), "should return envelope")
+}
+
+func TestIsNilReturn(t *testing.T) {
+	var ptr *GoodStruct
+	var slice []string
+
+	assert.True(t, isNilReturn(nil), "should be nil for untyped nil")
+	assert.True(t, isNilReturn(ptr), "should be nil for nil pointer")
+	assert.True(t, isNilReturn(slice), "should be nil for nil slice")
+	assert.False(t, isNilReturn(""), "should not be nil for empty string")
+	assert.False(t, isNilReturn(0), "should not be nil for zero int")
+	assert.False(t, isNilReturn([]string{}), "should not be nil for empty slice")
+}
+
+func TestNilReturns(t *testing.T) {
+	nrtc := new(nilReturnTestContract)
+	cc := convertC2CC(nrtc)
+
+	// Should return empty payload by default
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:Find", "false"}, invokeType, "")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:List"}, invokeType, "")
+
+	// Should return null when set
+	nrtc = new(nilReturnTestContract)
+	nrtc.ConfigureFunction("Find").SetNilReturn(NullNilReturn)
+	nrtc.ConfigureFunction("Empty").SetNilReturn(NullNilReturn)
+	nrtc.ConfigureFunction("List").SetNilReturn(EnvelopeNilReturn)
+	cc = convertC2CC(nrtc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:Find", "false"}, invokeType, "null")
+
+	// Should return envelope when set
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:List"}, invokeType, "{\"result\":null,\"status\":\"OK\"}")
+
+	// Should not affect non nil values
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:Find", "true"}, invokeType, "{\"Prop1\":\"value\",\"prop2\":1}")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"nilReturnTestContract:Empty"}, invokeType, "")
+}
@@ -0,0 +1,128 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+
+	"github.com/go-openapi/spec"
+)
+
+// OpenAPIVersion the version of the OpenAPI specification that documents
+// generated from chaincode metadata conform to
+const OpenAPIVersion = "3.0.0"
+
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       spec.Info                              `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags"`
+	FabricTags  []string                   `json:"x-fabric-tags,omitempty"`
+	Pagination  *PaginationMetadata        `json:"x-fabric-pagination,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema spec.Schema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]spec.Schema `json:"schemas"`
+}
+
+// toOpenAPI converts the metadata into an OpenAPI document with a POST path of
+// /{contract}/{transaction} for each transaction. The parameters of a transaction
+// form the properties of its JSON request body.
+func (ccm *ContractChaincodeMetadata) toOpenAPI() openAPIDocument {
+	doc := openAPIDocument{}
+	doc.OpenAPI = OpenAPIVersion
+	doc.Info = ccm.Info
+	doc.Paths = make(map[string]map[string]openAPIOperation)
+	doc.Components.Schemas = make(map[string]spec.Schema)
+
+	for contractName, contract := range ccm.Contracts {
+		for _, transaction := range contract.Transactions {
+			operation := openAPIOperation{}
+			operation.OperationID = fmt.Sprintf("%s:%s", contractName, transaction.Name)
+			operation.Summary = transaction.Description
+			operation.Tags = []string{contractName}
+			operation.FabricTags = transaction.Tag
+			operation.Pagination = transaction.Pagination
+
+			if len(transaction.Parameters) > 0 {
+				body := spec.Schema{}
+				body.Typed("object", "")
+				body.Properties = make(map[string]spec.Schema)
+
+				for _, param := range transaction.Parameters {
+					schema := param.Schema
+					schema.Description = param.Description
+					body.Properties[param.Name] = schema
+
+					if param.Required {
+						body.Required = append(body.Required, param.Name)
+					}
+				}
+
+				operation.RequestBody = &openAPIRequestBody{
+					Required: true,
+					Content:  map[string]openAPIMediaType{"application/json": {body}},
+				}
+			}
+
+			success := openAPIResponse{Description: "Transaction successful"}
+
+			if transaction.Returns != nil {
+				success.Content = map[string]openAPIMediaType{"application/json": {*transaction.Returns}}
+			}
+
+			operation.Responses = map[string]openAPIResponse{
+				"200": success,
+				"500": {Description: "Transaction returned an error"},
+			}
+
+			doc.Paths[fmt.Sprintf("/%s/%s", contractName, transaction.Name)] = map[string]openAPIOperation{"post": operation}
+		}
+	}
+
+	for name, object := range ccm.Components.Schemas {
+		schema := spec.Schema{}
+		schema.Typed("object", "")
+		schema.Properties = object.Properties
+		schema.Required = object.Required
+		schema.AdditionalProperties = &spec.SchemaOrBool{Allows: object.AdditionalProperties}
+
+		doc.Components.Schemas[name] = schema
+	}
+
+	return doc
+}
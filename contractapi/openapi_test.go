@@ -0,0 +1,116 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestToOpenAPI(t *testing.T) {
+	ccm := ContractChaincodeMetadata{}
+	ccm.Info.Title = "some chaincode"
+	ccm.Info.Version = "1.0.0"
+	ccm.Contracts = map[string]ContractMetadata{
+		"mycontract": {
+			Name: "mycontract",
+			Transactions: []TransactionMetadata{
+				{
+					Name:        "Create",
+					Description: "creates an asset",
+					Tag:         []string{"submitTx"},
+					Parameters: []ParameterMetadata{
+						{Name: "id", Description: "the id", Required: true, Schema: *spec.StringProperty()},
+						{Name: "asset", Schema: *spec.RefSchema("#/components/schemas/GoodStruct")},
+					},
+				},
+				{
+					Name:    "Read",
+					Returns: spec.RefSchema("#/components/schemas/GoodStruct"),
+				},
+				{
+					Name:       "List",
+					Pagination: &PaginationMetadata{"pageSize", "bookmark", "assets", "metadata"},
+				},
+			},
+		},
+	}
+	ccm.Components.Schemas = map[string]ObjectMetadata{
+		"GoodStruct": {
+			Properties:           map[string]spec.Schema{"Prop1": *spec.StringProperty()},
+			Required:             []string{"Prop1"},
+			AdditionalProperties: false,
+		},
+	}
+
+	doc := ccm.toOpenAPI()
+
+	assert.Equal(t, OpenAPIVersion, doc.OpenAPI, "should set OpenAPI version")
+	assert.Equal(t, ccm.Info, doc.Info, "should use metadata info")
+	assert.Len(t, doc.Paths, 3, "should have path per transaction")
+
+	// Should describe transaction with params as request body
+	create := doc.Paths["/mycontract/Create"]["post"]
+	assert.Equal(t, "mycontract:Create", create.OperationID, "should set operation ID")
+	assert.Equal(t, "creates an asset", create.Summary, "should use description as summary")
+	assert.Equal(t, []string{"mycontract"}, create.Tags, "should tag with contract name")
+	assert.Equal(t, []string{"submitTx"}, create.FabricTags, "should include transaction tags")
+	body := create.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, []string{"id"}, body.Required, "should require each required param")
+	assert.Equal(t, "the id", body.Properties["id"].Description, "should include param description")
+	asset := body.Properties["asset"]
+	assert.Equal(t, "#/components/schemas/GoodStruct", asset.Ref.String(), "should keep component refs")
+	assert.Nil(t, create.Responses["200"].Content, "should have no content for transaction without return")
+
+	// Should describe transaction without params or with returns
+	read := doc.Paths["/mycontract/Read"]["post"]
+	assert.Nil(t, read.RequestBody, "should have no request body for transaction without params")
+	assert.Equal(t, *spec.RefSchema("#/components/schemas/GoodStruct"), read.Responses["200"].Content["application/json"].Schema, "should use returns as response schema")
+	assert.Equal(t, "Transaction returned an error", read.Responses["500"].Description, "should describe error response")
+	assert.Nil(t, read.Pagination, "should have no pagination for transaction not paginated")
+
+	// Should include pagination of paginated transactions
+	list := doc.Paths["/mycontract/List"]["post"]
+	assert.Equal(t, &PaginationMetadata{"pageSize", "bookmark", "assets", "metadata"}, list.Pagination, "should include pagination")
+
+	// Should convert components to schemas
+	component := doc.Components.Schemas["GoodStruct"]
+	assert.Equal(t, spec.StringOrArray{"object"}, component.Type, "should type component as object")
+	assert.Equal(t, []string{"Prop1"}, component.Required, "should keep required")
+	assert.False(t, component.AdditionalProperties.Allows, "should keep additional properties")
+}
+
+func TestGetOpenAPIInvoke(t *testing.T) {
+	cc := convertC2CC(new(myContract))
+	mockStub := shimtest.NewMockStub("openAPITest", &cc)
+
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte(SystemContractName + ":GetOpenAPI")})
+
+	doc := make(map[string]interface{})
+	err := json.Unmarshal(response.Payload, &doc)
+
+	assert.Nil(t, err, "should return JSON")
+	assert.Equal(t, OpenAPIVersion, doc["openapi"], "should return OpenAPI document")
+	assert.Contains(t, doc["paths"], "/myContract/ReturnsString", "should contain path for contract function")
+	assert.Contains(t, doc["paths"], "/"+SystemContractName+"/GetOpenAPI", "should contain path for system contract function")
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"runtime"
+	"time"
+)
+
+// OpsContractName the name of the ops contract (see NewOpsContract)
+const OpsContractName = "org.hyperledger.fabric.ops"
+
+// BuildCommit and BuildTime are returned by the BuildInfo transaction of the ops
+// contract. They are blank unless set when the chaincode is built e.g.
+//
+//	go build -ldflags "-X github.com/awjh-ibm/fabric-go-developer-api/contractapi.BuildCommit=$(git rev-parse HEAD)"
+var (
+	BuildCommit string
+	BuildTime   string
+)
+
+// BuildInfo is returned by the BuildInfo transaction of the ops contract
+type BuildInfo struct {
+	Commit      string `json:"commit"`
+	BuildTime   string `json:"buildTime"`
+	GoVersion   string `json:"goVersion"`
+	ContractAPI string `json:"contractapi"`
+}
+
+// OpsContract is a contract that lets network operators check the health of the
+// chaincode container using normal queries. Its transactions do not read the world
+// state and their results differ between peers, so they are tagged to be evaluated
+// and should not be submitted.
+type OpsContract struct {
+	Contract
+}
+
+// NewOpsContract returns an OpsContract named OpsContractName to be added to the
+// chaincode alongside its other contracts
+func NewOpsContract() *OpsContract {
+	oc := new(OpsContract)
+	oc.SetName(OpsContractName)
+
+	for _, name := range []string{"Ping", "BuildInfo", "Uptime"} {
+		oc.ConfigureFunction(name).SetEvaluate(true)
+	}
+
+	return oc
+}
+
+// Ping returns pong, showing that the chaincode is reachable and processing
+// transactions
+func (oc *OpsContract) Ping() string {
+	return "pong"
+}
+
+// BuildInfo returns the commit and time the chaincode was built at (see
+// BuildCommit), the version of Go it was built with and the version of the
+// contractapi library
+func (oc *OpsContract) BuildInfo() BuildInfo {
+	return BuildInfo{BuildCommit, BuildTime, runtime.Version(), LibraryVersion}
+}
+
+// Uptime returns the number of seconds since the chaincode process started
+func (oc *OpsContract) Uptime() int64 {
+	return int64(time.Since(processStartTime) / time.Second)
+}
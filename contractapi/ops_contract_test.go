@@ -0,0 +1,95 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestNewOpsContract(t *testing.T) {
+	oc := NewOpsContract()
+
+	assert.Equal(t, OpsContractName, oc.GetName(), "should set name")
+
+	// Should tag functions to be evaluated
+	cc := convertC2CC(oc)
+
+	for _, tx := range cc.metadata.Contracts[OpsContractName].Transactions {
+		assert.Equal(t, []string{"evaluateTx"}, tx.Tag, "should tag "+tx.Name+" to be evaluated")
+	}
+}
+
+func TestOpsContractPing(t *testing.T) {
+	assert.Equal(t, "pong", new(OpsContract).Ping(), "should return pong")
+}
+
+func TestOpsContractBuildInfo(t *testing.T) {
+	oldCommit := BuildCommit
+	oldTime := BuildTime
+	defer func() {
+		BuildCommit = oldCommit
+		BuildTime = oldTime
+	}()
+
+	assert.Equal(t, BuildInfo{"", "", runtime.Version(), LibraryVersion}, new(OpsContract).BuildInfo(), "should return blank build details when not set")
+
+	BuildCommit = "abc123"
+	BuildTime = "2020-01-01T00:00:00Z"
+	assert.Equal(t, BuildInfo{"abc123", "2020-01-01T00:00:00Z", runtime.Version(), LibraryVersion}, new(OpsContract).BuildInfo(), "should return build details")
+}
+
+func TestOpsContractUptime(t *testing.T) {
+	oldStartTime := processStartTime
+	defer func() {
+		processStartTime = oldStartTime
+	}()
+
+	processStartTime = time.Now().Add(-90 * time.Second)
+	assert.Equal(t, int64(90), new(OpsContract).Uptime(), "should return seconds since start")
+}
+
+func TestInvokeOpsContract(t *testing.T) {
+	BuildCommit = "abc123"
+	defer func() {
+		BuildCommit = ""
+	}()
+
+	cc := convertC2CC(new(myContract), NewOpsContract())
+	stub := shimtest.NewMockStub("opsContractTest", &cc)
+
+	// Should call transactions through ops contract name
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte(OpsContractName + ":Ping")})
+	assert.Equal(t, "pong", string(response.Payload), "should ping")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte(OpsContractName + ":BuildInfo")})
+	info := BuildInfo{}
+	json.Unmarshal(response.Payload, &info)
+	assert.Equal(t, BuildInfo{"abc123", "", runtime.Version(), LibraryVersion}, info, "should return build info")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte(OpsContractName + ":Uptime")})
+	_, err := strconv.ParseInt(string(response.Payload), 10, 64)
+	assert.Nil(t, err, "should return uptime in seconds")
+}
@@ -0,0 +1,133 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+)
+
+// OrgKeyObjectType is the object type of the composite keys used to
+// partition world state data by organisation
+const OrgKeyObjectType = "org"
+
+// GetOrgKey returns the key used to store the passed key in the partition
+// of the world state belonging to the MSP of the client that submitted the
+// transaction. The key is a composite key of OrgKeyObjectType, the MSP ID and
+// the passed key.
+func (ctx *TransactionContext) GetOrgKey(key string) (string, error) {
+	mspID, err := ctx.getClientMSPID()
+
+	if err != nil {
+		return "", err
+	}
+
+	return ctx.GetStub().CreateCompositeKey(OrgKeyObjectType, []string{mspID, key})
+}
+
+// SplitOrgKey returns the MSP ID and key that make up a key returned by GetOrgKey
+// or by the iterator returned by GetOrgStates
+func (ctx *TransactionContext) SplitOrgKey(orgKey string) (string, string, error) {
+	objectType, attributes, err := ctx.GetStub().SplitCompositeKey(orgKey)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if objectType != OrgKeyObjectType || len(attributes) != 2 {
+		return "", "", fmt.Errorf("Key %s is not an organisation key", orgKey)
+	}
+
+	return attributes[0], attributes[1], nil
+}
+
+// GetOrgState returns the value of the passed key from the partition of the
+// world state belonging to the MSP of the client that submitted the transaction
+func (ctx *TransactionContext) GetOrgState(key string) ([]byte, error) {
+	orgKey, err := ctx.GetOrgKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.GetStub().GetState(orgKey)
+}
+
+// PutOrgState writes the passed value for the key to the partition of the
+// world state belonging to the MSP of the client that submitted the transaction.
+// State triggers registered for the organisation key are called after it is written.
+func (ctx *TransactionContext) PutOrgState(key string, value []byte) error {
+	orgKey, err := ctx.GetOrgKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().PutState(orgKey, value)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.fireStateTriggers(StateChange{Key: orgKey, Value: value})
+}
+
+// DelOrgState deletes the passed key from the partition of the world state
+// belonging to the MSP of the client that submitted the transaction. State
+// triggers registered for the organisation key are called after it is deleted.
+func (ctx *TransactionContext) DelOrgState(key string) error {
+	orgKey, err := ctx.GetOrgKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().DelState(orgKey)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.fireStateTriggers(StateChange{Key: orgKey, Deleted: true})
+}
+
+// GetOrgStates returns an iterator over all keys in the partition of the world
+// state belonging to the MSP of the client that submitted the transaction. Keys
+// returned by the iterator can be split using SplitOrgKey. The returned iterator
+// must be closed.
+func (ctx *TransactionContext) GetOrgStates() (*StateIterator, error) {
+	mspID, err := ctx.getClientMSPID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(OrgKeyObjectType, []string{mspID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateIterator{iterator}, nil
+}
+
+func (ctx *TransactionContext) getClientMSPID() (string, error) {
+	ci, err := ctx.GetClientIdentity()
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to get client identity. %s", err.Error())
+	}
+
+	return ci.GetMSPID()
+}
@@ -0,0 +1,126 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestGetOrgKey(t *testing.T) {
+	var key string
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	// Should error when client identity cannot be created
+	_, err = ctx.GetOrgKey("key1")
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error when no creator")
+
+	// Should return composite key of MSP ID and key
+	stub.Creator = createCreator("Org1MSP", nil)
+	ctx.SetStub(stub)
+	key, err = ctx.GetOrgKey("key1")
+	assert.Nil(t, err, "should not error when creator valid")
+	expected, _ := stub.CreateCompositeKey(OrgKeyObjectType, []string{"Org1MSP", "key1"})
+	assert.Equal(t, expected, key, "should return composite key of MSP ID and key")
+}
+
+func TestSplitOrgKey(t *testing.T) {
+	var mspID string
+	var key string
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	// Should return MSP ID and key of org key
+	orgKey, _ := stub.CreateCompositeKey(OrgKeyObjectType, []string{"Org1MSP", "key1"})
+	mspID, key, err = ctx.SplitOrgKey(orgKey)
+	assert.Nil(t, err, "should not error for org key")
+	assert.Equal(t, "Org1MSP", mspID, "should return MSP ID")
+	assert.Equal(t, "key1", key, "should return key")
+
+	// Should error when key is not an org key
+	otherKey, _ := stub.CreateCompositeKey("other", []string{"Org1MSP", "key1"})
+	_, _, err = ctx.SplitOrgKey(otherKey)
+	assert.EqualError(t, err, "Key "+otherKey+" is not an organisation key", "should error for composite key of other type")
+}
+
+func TestOrgState(t *testing.T) {
+	var value []byte
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	// Should error when client identity cannot be created
+	_, err = ctx.GetOrgState("key1")
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error on get when no creator")
+	err = ctx.PutOrgState("key1", []byte("value"))
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error on put when no creator")
+	err = ctx.DelOrgState("key1")
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error on delete when no creator")
+	_, err = ctx.GetOrgStates()
+	assert.Contains(t, err.Error(), "Failed to get client identity.", "should error on list when no creator")
+
+	// Should write and read values in partition of client's org
+	stub.Creator = createCreator("Org1MSP", nil)
+	ctx.SetStub(stub)
+	assert.Nil(t, ctx.PutOrgState("key1", []byte("org1 value1")), "should not error on put")
+	assert.Nil(t, ctx.PutOrgState("key2", []byte("org1 value2")), "should not error on put")
+	value, err = ctx.GetOrgState("key1")
+	assert.Nil(t, err, "should not error on get")
+	assert.Equal(t, []byte("org1 value1"), value, "should read value written by org")
+
+	// Should not read values of other orgs
+	stub.Creator = createCreator("Org2MSP", nil)
+	ctx.SetStub(stub)
+	assert.Nil(t, ctx.PutOrgState("key1", []byte("org2 value1")), "should not error on put")
+	value, _ = ctx.GetOrgState("key2")
+	assert.Nil(t, value, "should not read value of other org")
+
+	// Should list only keys of client's org
+	stub.Creator = createCreator("Org1MSP", nil)
+	ctx.SetStub(stub)
+	iterator, err := ctx.GetOrgStates()
+	assert.Nil(t, err, "should not error on list")
+	keys := []string{}
+	for iterator.HasNext() {
+		orgKey, _, _ := iterator.Next()
+		_, key, _ := ctx.SplitOrgKey(orgKey)
+		keys = append(keys, key)
+	}
+	iterator.Close()
+	assert.Equal(t, []string{"key1", "key2"}, keys, "should list keys of org")
+
+	// Should delete values in partition of client's org
+	assert.Nil(t, ctx.DelOrgState("key1"), "should not error on delete")
+	value, _ = ctx.GetOrgState("key1")
+	assert.Nil(t, value, "should have deleted value")
+	stub.Creator = createCreator("Org2MSP", nil)
+	ctx.SetStub(stub)
+	value, _ = ctx.GetOrgState("key1")
+	assert.Equal(t, []byte("org2 value1"), value, "should not delete value of other org")
+
+	// Should return stub errors
+	stub.getErr = errors.New("some get error")
+	_, err = ctx.GetOrgState("key1")
+	assert.EqualError(t, err, "some get error", "should return get error")
+}
@@ -0,0 +1,342 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Environment variables, as defined by the OpenTelemetry specification, from
+// which Start configures the OTLP tracer when no tracer is set
+const (
+	// OTLPEndpointEnvVar the base URL of the collector. Spans are sent to its
+	// /v1/traces path
+	OTLPEndpointEnvVar = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// OTLPTracesEndpointEnvVar the full URL spans are sent to. Takes precedence
+	// over OTEL_EXPORTER_OTLP_ENDPOINT
+	OTLPTracesEndpointEnvVar = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	// OTLPHeadersEnvVar comma separated key=value pairs of headers sent with
+	// each export, e.g. for authentication. Values are URL decoded
+	OTLPHeadersEnvVar = "OTEL_EXPORTER_OTLP_HEADERS"
+	// OTLPProtocolEnvVar the protocol used to export spans. Only http/json is
+	// supported
+	OTLPProtocolEnvVar = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	// OTelServiceNameEnvVar the service name of the exported spans
+	OTelServiceNameEnvVar = "OTEL_SERVICE_NAME"
+)
+
+const otlpProtocol = "http/json"
+const otlpDefaultServiceName = "unknown_service"
+const otlpExportInterval = 5 * time.Second
+const otlpMaxQueuedSpans = 2048
+
+// OTLP span kind and status codes
+const (
+	otlpSpanKindServer  = 2
+	otlpStatusCodeOK    = 1
+	otlpStatusCodeError = 2
+)
+
+var otlpWarningWriter io.Writer = os.Stderr
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPTracer a Tracer exporting spans to an OpenTelemetry collector using OTLP
+// over HTTP with JSON encoding. Spans are queued when they end and exported by
+// Flush. The trace ID of a span is derived from the ID of its transaction so
+// that the spans of each peer endorsing the transaction share a trace.
+type OTLPTracer struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	mutex       sync.Mutex
+	spans       []otlpSpan
+	dropped     int
+}
+
+type otlpTracerSpan struct {
+	tracer  *OTLPTracer
+	details SpanDetails
+	start   time.Time
+}
+
+// NewOTLPTracer returns a tracer exporting spans to the traces endpoint of a
+// collector, e.g. http://localhost:4318/v1/traces, with the passed service name
+// and sending the passed headers with each export
+func NewOTLPTracer(endpoint string, serviceName string, headers map[string]string) *OTLPTracer {
+	tracer := new(OTLPTracer)
+	tracer.endpoint = endpoint
+	tracer.serviceName = serviceName
+	tracer.headers = headers
+	tracer.client = &http.Client{Timeout: 10 * time.Second}
+
+	return tracer
+}
+
+// NewOTLPTracerFromEnv returns a tracer configured from the OpenTelemetry
+// environment variables (see OTLPEndpointEnvVar). Returns nil when neither
+// endpoint variable is set, and an error when the protocol is not http/json or
+// the headers are invalid.
+func NewOTLPTracerFromEnv() (*OTLPTracer, error) {
+	endpoint := os.Getenv(OTLPTracesEndpointEnvVar)
+
+	if endpoint == "" && os.Getenv(OTLPEndpointEnvVar) != "" {
+		endpoint = strings.TrimSuffix(os.Getenv(OTLPEndpointEnvVar), "/") + "/v1/traces"
+	}
+
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	if protocol := os.Getenv(OTLPProtocolEnvVar); protocol != "" && protocol != otlpProtocol {
+		return nil, fmt.Errorf("OTLP protocol %s is not supported. Use %s", protocol, otlpProtocol)
+	}
+
+	headers, err := parseOTLPHeaders(os.Getenv(OTLPHeadersEnvVar))
+
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := os.Getenv(OTelServiceNameEnvVar)
+
+	if serviceName == "" {
+		serviceName = otlpDefaultServiceName
+	}
+
+	return NewOTLPTracer(endpoint, serviceName, headers), nil
+}
+
+func parseOTLPHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("Invalid OTLP header %s. Headers must be key=value pairs", strings.TrimSpace(pair))
+		}
+
+		headerValue, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid OTLP header %s. %s", strings.TrimSpace(parts[0]), err.Error())
+		}
+
+		headers[strings.TrimSpace(parts[0])] = headerValue
+	}
+
+	return headers, nil
+}
+
+// StartSpan starts a span for the transaction, named for its contract and function
+func (ot *OTLPTracer) StartSpan(details SpanDetails) Span {
+	return &otlpTracerSpan{ot, details, time.Now()}
+}
+
+// End queues the span for export with its duration and status. Spans are
+// dropped if too many are queued, e.g. as the collector cannot be reached.
+func (ots *otlpTracerSpan) End(err error) {
+	end := time.Now()
+
+	traceID := sha256.Sum256([]byte(ots.details.TxID))
+	spanID := make([]byte, 8)
+	rand.Read(spanID)
+
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(traceID[:16]),
+		SpanID:            hex.EncodeToString(spanID),
+		Name:              ots.details.Contract + ":" + ots.details.Function,
+		Kind:              otlpSpanKindServer,
+		StartTimeUnixNano: strconv.FormatInt(ots.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes: []otlpKeyValue{
+			{"fabric.channel_id", otlpAnyValue{ots.details.ChannelID}},
+			{"fabric.tx_id", otlpAnyValue{ots.details.TxID}},
+			{"fabric.contract", otlpAnyValue{ots.details.Contract}},
+			{"fabric.function", otlpAnyValue{ots.details.Function}},
+		},
+		Status: otlpStatus{Code: otlpStatusCodeOK},
+	}
+
+	if err != nil {
+		span.Status = otlpStatus{otlpStatusCodeError, err.Error()}
+	}
+
+	ots.tracer.mutex.Lock()
+	defer ots.tracer.mutex.Unlock()
+
+	if len(ots.tracer.spans) >= otlpMaxQueuedSpans {
+		ots.tracer.dropped++
+		return
+	}
+
+	ots.tracer.spans = append(ots.tracer.spans, span)
+}
+
+// Flush exports the queued spans. Returns an error if the collector could not be
+// reached or rejected the spans, which are then discarded.
+func (ot *OTLPTracer) Flush() error {
+	ot.mutex.Lock()
+	spans := ot.spans
+	dropped := ot.dropped
+	ot.spans = nil
+	ot.dropped = 0
+	ot.mutex.Unlock()
+
+	if dropped > 0 {
+		fmt.Fprintf(otlpWarningWriter, "Warning: Dropped %d spans as too many were queued for export\n", dropped)
+	}
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	traces := otlpTraces{[]otlpResourceSpans{{
+		Resource:   otlpResource{[]otlpKeyValue{{"service.name", otlpAnyValue{ot.serviceName}}}},
+		ScopeSpans: []otlpScopeSpans{{otlpScope{"github.com/awjh-ibm/fabric-go-developer-api/contractapi", LibraryVersion}, spans}},
+	}}}
+
+	body, err := json.Marshal(traces)
+
+	if err != nil {
+		return fmt.Errorf("Failed to marshal spans. %s", err.Error())
+	}
+
+	request, err := http.NewRequest(http.MethodPost, ot.endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("Failed to export spans. %s", err.Error())
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	for key, value := range ot.headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := ot.client.Do(request)
+
+	if err != nil {
+		return fmt.Errorf("Failed to export spans. %s", err.Error())
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("Failed to export spans. Collector returned status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// exportEvery flushes the queued spans at each interval until the returned
+// function is called, which flushes them a final time. Errors are written as
+// warnings.
+func (ot *OTLPTracer) exportEvery(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	flush := func() {
+		if err := ot.Flush(); err != nil {
+			fmt.Fprintf(otlpWarningWriter, "Warning: %s\n", err.Error())
+		}
+	}
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-ticker.C:
+				flush()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+		flush()
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type otlpRequest struct {
+	headers http.Header
+	traces  otlpTraces
+}
+
+func newOTLPTestServer(status int) (*httptest.Server, *[]otlpRequest) {
+	requests := []otlpRequest{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		request := otlpRequest{headers: r.Header}
+		json.Unmarshal(body, &request.traces)
+		requests = append(requests, request)
+
+		w.WriteHeader(status)
+	}))
+
+	return server, &requests
+}
+
+func setOTLPEnv(values map[string]string) func() {
+	names := []string{OTLPEndpointEnvVar, OTLPTracesEndpointEnvVar, OTLPHeadersEnvVar, OTLPProtocolEnvVar, OTelServiceNameEnvVar}
+	old := make(map[string]string)
+
+	for _, name := range names {
+		old[name] = os.Getenv(name)
+		os.Setenv(name, values[name])
+	}
+
+	return func() {
+		for _, name := range names {
+			os.Setenv(name, old[name])
+		}
+	}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewOTLPTracer(t *testing.T) {
+	tracer := NewOTLPTracer("http://localhost:4318/v1/traces", "mycc", map[string]string{"key": "value"})
+
+	assert.Equal(t, "http://localhost:4318/v1/traces", tracer.endpoint, "should set endpoint")
+	assert.Equal(t, "mycc", tracer.serviceName, "should set service name")
+	assert.Equal(t, map[string]string{"key": "value"}, tracer.headers, "should set headers")
+}
+
+func TestNewOTLPTracerFromEnv(t *testing.T) {
+	var tracer *OTLPTracer
+	var err error
+	var restore func()
+
+	// Should return nil when no endpoint set
+	restore = setOTLPEnv(map[string]string{})
+	tracer, err = NewOTLPTracerFromEnv()
+	assert.Nil(t, err, "should not error when no endpoint set")
+	assert.Nil(t, tracer, "should not return tracer when no endpoint set")
+	restore()
+
+	// Should use traces path of endpoint and defaults
+	restore = setOTLPEnv(map[string]string{OTLPEndpointEnvVar: "http://collector:4318/"})
+	tracer, err = NewOTLPTracerFromEnv()
+	assert.Nil(t, err, "should not error for endpoint")
+	assert.Equal(t, "http://collector:4318/v1/traces", tracer.endpoint, "should append traces path to endpoint")
+	assert.Equal(t, otlpDefaultServiceName, tracer.serviceName, "should use default service name")
+	assert.Equal(t, map[string]string{}, tracer.headers, "should have no headers")
+	restore()
+
+	// Should prefer traces endpoint and read service name and headers
+	restore = setOTLPEnv(map[string]string{
+		OTLPEndpointEnvVar:       "http://collector:4318",
+		OTLPTracesEndpointEnvVar: "http://traces:4318/custom",
+		OTLPHeadersEnvVar:        "api-key=abc%3D, tenant = org1",
+		OTLPProtocolEnvVar:       "http/json",
+		OTelServiceNameEnvVar:    "mycc",
+	})
+	tracer, err = NewOTLPTracerFromEnv()
+	assert.Nil(t, err, "should not error for full config")
+	assert.Equal(t, "http://traces:4318/custom", tracer.endpoint, "should use traces endpoint as is")
+	assert.Equal(t, "mycc", tracer.serviceName, "should use service name")
+	assert.Equal(t, map[string]string{"api-key": "abc=", "tenant": "org1"}, tracer.headers, "should parse and decode headers")
+	restore()
+
+	// Should error for unsupported protocol
+	restore = setOTLPEnv(map[string]string{OTLPEndpointEnvVar: "http://collector:4318", OTLPProtocolEnvVar: "grpc"})
+	_, err = NewOTLPTracerFromEnv()
+	assert.EqualError(t, err, "OTLP protocol grpc is not supported. Use http/json", "should error for unsupported protocol")
+	restore()
+
+	// Should error for invalid headers
+	restore = setOTLPEnv(map[string]string{OTLPEndpointEnvVar: "http://collector:4318", OTLPHeadersEnvVar: "novalue"})
+	_, err = NewOTLPTracerFromEnv()
+	assert.EqualError(t, err, "Invalid OTLP header novalue. Headers must be key=value pairs", "should error for invalid header")
+	restore()
+}
+
+func TestOTLPTracerFlush(t *testing.T) {
+	var err error
+
+	server, requests := newOTLPTestServer(http.StatusOK)
+	defer server.Close()
+
+	tracer := NewOTLPTracer(server.URL, "mycc", map[string]string{"Api-Key": "abc"})
+
+	// Should not export when no spans queued
+	err = tracer.Flush()
+	assert.Nil(t, err, "should not error when no spans")
+	assert.Len(t, *requests, 0, "should not export when no spans")
+
+	// Should export ended spans
+	tracer.StartSpan(SpanDetails{ChannelID: "mychannel", TxID: standardTxID, Contract: "myContract", Function: "ReturnsString"}).End(nil)
+	tracer.StartSpan(SpanDetails{TxID: standardTxID, Contract: "myContract", Function: "ReturnsError"}).End(errors.New("some error"))
+	err = tracer.Flush()
+	assert.Nil(t, err, "should not error exporting")
+	assert.Len(t, *requests, 1, "should export spans in one request")
+
+	request := (*requests)[0]
+	assert.Equal(t, "application/json", request.headers.Get("Content-Type"), "should send JSON")
+	assert.Equal(t, "abc", request.headers.Get("Api-Key"), "should send headers")
+
+	resourceSpans := request.traces.ResourceSpans[0]
+	assert.Equal(t, []otlpKeyValue{{"service.name", otlpAnyValue{"mycc"}}}, resourceSpans.Resource.Attributes, "should set service name")
+	assert.Equal(t, LibraryVersion, resourceSpans.ScopeSpans[0].Scope.Version, "should set scope version")
+
+	spans := resourceSpans.ScopeSpans[0].Spans
+	assert.Len(t, spans, 2, "should export each span")
+	assert.Equal(t, "myContract:ReturnsString", spans[0].Name, "should name span for function")
+	assert.Equal(t, otlpSpanKindServer, spans[0].Kind, "should export server span")
+	assert.Len(t, spans[0].TraceID, 32, "should set trace ID")
+	assert.Equal(t, spans[0].TraceID, spans[1].TraceID, "should derive trace ID from transaction")
+	assert.Len(t, spans[0].SpanID, 16, "should set span ID")
+	assert.NotEqual(t, spans[0].SpanID, spans[1].SpanID, "should set unique span IDs")
+	assert.NotEqual(t, "", spans[0].StartTimeUnixNano, "should set start time")
+	assert.Contains(t, spans[0].Attributes, otlpKeyValue{"fabric.channel_id", otlpAnyValue{"mychannel"}}, "should set channel attribute")
+	assert.Contains(t, spans[0].Attributes, otlpKeyValue{"fabric.tx_id", otlpAnyValue{standardTxID}}, "should set transaction attribute")
+	assert.Equal(t, otlpStatus{Code: otlpStatusCodeOK}, spans[0].Status, "should set ok status")
+	assert.Equal(t, otlpStatus{otlpStatusCodeError, "some error"}, spans[1].Status, "should set error status")
+
+	// Should clear queue once exported
+	tracer.Flush()
+	assert.Len(t, *requests, 1, "should not export spans again")
+
+	// Should error when collector rejects spans
+	failing, _ := newOTLPTestServer(http.StatusBadRequest)
+	defer failing.Close()
+
+	tracer = NewOTLPTracer(failing.URL, "mycc", nil)
+	tracer.StartSpan(SpanDetails{}).End(nil)
+	err = tracer.Flush()
+	assert.EqualError(t, err, "Failed to export spans. Collector returned status 400", "should error when collector rejects spans")
+
+	// Should drop spans when queue full
+	buf := new(bytes.Buffer)
+	oldWriter := otlpWarningWriter
+	otlpWarningWriter = buf
+	defer func() { otlpWarningWriter = oldWriter }()
+
+	tracer = NewOTLPTracer(server.URL, "mycc", nil)
+
+	for i := 0; i < otlpMaxQueuedSpans+2; i++ {
+		tracer.StartSpan(SpanDetails{}).End(nil)
+	}
+
+	assert.Len(t, tracer.spans, otlpMaxQueuedSpans, "should not queue more than max spans")
+	tracer.Flush()
+	assert.Equal(t, "Warning: Dropped 2 spans as too many were queued for export\n", buf.String(), "should warn of dropped spans")
+}
+
+func TestStartWithOTLPTracer(t *testing.T) {
+	var cc ContractChaincode
+	var err error
+
+	server, requests := newOTLPTestServer(http.StatusOK)
+	defer server.Close()
+
+	restore := stubShimStart(func(c shim.Chaincode) error {
+		callContractFunctionAndCheckSuccess(t, *c.(*ContractChaincode), []string{"DoSomething"}, invokeType, "Done something")
+		return nil
+	})
+	defer restore()
+
+	// Should export spans to endpoint from environment when chaincode stops
+	restoreEnv := setOTLPEnv(map[string]string{OTLPTracesEndpointEnvVar: server.URL})
+	defer restoreEnv()
+
+	cc = convertC2CC(new(simpleTestContract))
+	err = cc.StartWithContext(context.Background())
+	assert.Nil(t, err, "should not error starting with tracing")
+	assert.IsType(t, new(OTLPTracer), cc.tracer, "should set OTLP tracer")
+	assert.Len(t, *requests, 1, "should export spans when stopped")
+	assert.Equal(t, "simpleTestContract:DoSomething", (*requests)[0].traces.ResourceSpans[0].ScopeSpans[0].Spans[0].Name, "should export span of transaction")
+
+	// Should not replace tracer set
+	tracer := new(recordingTracer)
+	cc = convertC2CC(new(simpleTestContract))
+	cc.SetTracer(tracer)
+	cc.StartWithContext(context.Background())
+	assert.Equal(t, tracer, cc.tracer, "should keep tracer set")
+	assert.Len(t, tracer.spans, 1, "should use tracer set")
+
+	// Should error for invalid configuration
+	os.Setenv(OTLPProtocolEnvVar, "grpc")
+	cc = convertC2CC(new(simpleTestContract))
+	err = cc.StartWithContext(context.Background())
+	assert.EqualError(t, err, "Failed to configure tracing. OTLP protocol grpc is not supported. Use http/json", "should error for invalid config")
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PaginationMetadata details how a paginated transaction, one returning a page
+// of the results of a paginated query, is called and how its response is shaped
+type PaginationMetadata struct {
+	PageSizeParameter string `json:"pageSizeParameter"`
+	BookmarkParameter string `json:"bookmarkParameter"`
+	ResultsProperty   string `json:"resultsProperty"`
+	MetadataProperty  string `json:"metadataProperty"`
+}
+
+type paginationDetails struct {
+	pageSizeIndex    int
+	bookmarkIndex    int
+	resultsProperty  string
+	metadataProperty string
+}
+
+var queryResponseMetadataType = reflect.TypeOf(QueryResponseMetadata{})
+
+// getPaginationDetails identifies functions using the pagination helpers by their
+// return being a page of results, a struct with a slice property and a property of
+// type QueryResponseMetadata as returned by GetQueryResultPage. Such functions
+// must take the page size and bookmark to pass to the helper as an int32 parameter
+// followed by a string parameter. Returns nil for functions not returning a page.
+func getPaginationDetails(fn *contractFunction) (*paginationDetails, error) {
+	returnType := fn.returns.success
+
+	if returnType == nil {
+		return nil, nil
+	}
+
+	if returnType.Kind() == reflect.Ptr {
+		returnType = returnType.Elem()
+	}
+
+	if returnType.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	details := paginationDetails{pageSizeIndex: -1}
+
+	for i := 0; i < returnType.NumField(); i++ {
+		field := returnType.Field(i)
+
+		if isIgnoredField(field) {
+			continue
+		}
+
+		name, _, _ := getJSONFieldDetails(field)
+		fieldType := field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType == queryResponseMetadataType && details.metadataProperty == "" {
+			details.metadataProperty = name
+		} else if fieldType.Kind() == reflect.Slice && details.resultsProperty == "" {
+			details.resultsProperty = name
+		}
+	}
+
+	if details.metadataProperty == "" || details.resultsProperty == "" {
+		return nil, nil
+	}
+
+	for i := 0; i < len(fn.params.fields)-1; i++ {
+		if fn.params.fields[i].Kind() == reflect.Int32 && fn.params.fields[i+1].Kind() == reflect.String {
+			details.pageSizeIndex = i
+			details.bookmarkIndex = i + 1
+			break
+		}
+	}
+
+	if details.pageSizeIndex == -1 {
+		return nil, fmt.Errorf("Return type %s is a page of results but parameters do not include a page size and bookmark. Expected an int32 parameter followed by a string parameter", fn.returns.success.String())
+	}
+
+	return &details, nil
+}
+
+func (pd *paginationDetails) applyTo(transactionMetadata *TransactionMetadata) {
+	transactionMetadata.Pagination = &PaginationMetadata{
+		PageSizeParameter: transactionMetadata.Parameters[pd.pageSizeIndex].Name,
+		BookmarkParameter: transactionMetadata.Parameters[pd.bookmarkIndex].Name,
+		ResultsProperty:   pd.resultsProperty,
+		MetadataProperty:  pd.metadataProperty,
+	}
+}
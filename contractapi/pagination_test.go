@@ -0,0 +1,113 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type paginationTestPage struct {
+	Assets   []GoodStruct           `json:"assets"`
+	Metadata *QueryResponseMetadata `json:"metadata"`
+}
+
+type paginationTestContract struct {
+	Contract
+}
+
+func (ptc *paginationTestContract) List(ctx *TransactionContext, owner string, pageSize int32, bookmark string) (*paginationTestPage, error) {
+	page := new(paginationTestPage)
+	metadata, err := ctx.GetQueryResultPage("{}", pageSize, bookmark, &page.Assets)
+	page.Metadata = metadata
+
+	return page, err
+}
+
+func (ptc *paginationTestContract) Read(ctx *TransactionContext, key string) (*GoodStruct, error) {
+	return nil, nil
+}
+
+type badPaginationTestContract struct {
+	Contract
+}
+
+func (bptc *badPaginationTestContract) List(ctx *TransactionContext, bookmark string) (paginationTestPage, error) {
+	return paginationTestPage{}, nil
+}
+
+func newPaginationTestFunction(fn interface{}) *contractFunction {
+	return newContractFunctionFromFunc(fn, reflect.TypeOf(new(TransactionContext)))
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestGetPaginationDetails(t *testing.T) {
+	var details *paginationDetails
+	var err error
+
+	// Should return nil for functions not returning a page
+	details, err = getPaginationDetails(newPaginationTestFunction(func() {}))
+	assert.Nil(t, err, "should not error for function without return")
+	assert.Nil(t, details, "should not be paginated without return")
+	details, err = getPaginationDetails(newPaginationTestFunction(func(int32, string) string { return "" }))
+	assert.Nil(t, err, "should not error for function returning non struct")
+	assert.Nil(t, details, "should not be paginated returning non struct")
+	details, err = getPaginationDetails(newPaginationTestFunction(func(int32, string) GoodStruct { return GoodStruct{} }))
+	assert.Nil(t, err, "should not error for function returning struct that is not a page")
+	assert.Nil(t, details, "should not be paginated returning struct that is not a page")
+
+	// Should return details for functions returning a page
+	details, err = getPaginationDetails(newPaginationTestFunction(func(string, int32, string) *paginationTestPage { return nil }))
+	assert.Nil(t, err, "should not error for function returning page")
+	assert.Equal(t, &paginationDetails{1, 2, "assets", "metadata"}, details, "should return details of page")
+
+	// Should error when function returning a page does not take page size and bookmark
+	_, err = getPaginationDetails(newPaginationTestFunction(func(string, int32) paginationTestPage { return paginationTestPage{} }))
+	assert.EqualError(t, err, "Return type contractapi.paginationTestPage is a page of results but parameters do not include a page size and bookmark. Expected an int32 parameter followed by a string parameter", "should error when no page size and bookmark")
+}
+
+func TestPaginationMetadata(t *testing.T) {
+	// Should mark paginated transactions in metadata
+	ptc := new(paginationTestContract)
+	ptc.ConfigureFunction("List").SetParameterNames("owner", "size", "start")
+	cc := convertC2CC(ptc)
+	metadata := cc.reflectMetadata()
+
+	transactions := metadata.Contracts["paginationTestContract"].Transactions
+	assert.Equal(t, "List", transactions[0].Name, "should have list transaction")
+	assert.Equal(t, &PaginationMetadata{"size", "start", "assets", "metadata"}, transactions[0].Pagination, "should mark transaction as paginated using configured names")
+	assert.Nil(t, transactions[1].Pagination, "should not mark other transactions as paginated")
+
+	_, ok := metadata.Components.Schemas["QueryResponseMetadata"]
+	assert.True(t, ok, "should include page metadata in components")
+
+	// Should panic when function returning page is not paginated
+	assert.PanicsWithValue(t, "Invalid paginated function List in contract badPaginationTestContract. Return type contractapi.paginationTestPage is a page of results but parameters do not include a page size and bookmark. Expected an int32 parameter followed by a string parameter", func() { convertC2CC(new(badPaginationTestContract)) }, "should panic for invalid paginated function")
+
+	// Should produce metadata valid against the schema
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(GetJSONSchema()), gojsonschema.NewGoLoader(metadata))
+	assert.Nil(t, err, "should validate metadata")
+	assert.True(t, result.Valid(), "should produce valid metadata for paginated transaction")
+}
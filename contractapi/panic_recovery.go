@@ -0,0 +1,99 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// maxCrashReportFrames the maximum number of frames of the stack included
+// in a crash report
+const maxCrashReportFrames = 10
+
+// CrashReport is returned as the payload of the response when a transaction
+// panics. It identifies where the panic occurred by the names of the functions
+// on the stack, without the file paths, line numbers and values of the full
+// stack which is written to the chaincode logs along with the panic value. The
+// digest is the same for panics from the same frames so that reports can be
+// matched to the log entry and to each other.
+type CrashReport struct {
+	Contract string   `json:"contract"`
+	Function string   `json:"function"`
+	TxID     string   `json:"txId"`
+	Digest   string   `json:"digest"`
+	Frames   []string `json:"frames"`
+}
+
+// recoverResponse returns the response for a transaction that panicked with the
+// passed value and logs the panic and full stack. Must be called by the function
+// deferred to recover the panic so that the stack is that of the panic.
+func (cc *ContractChaincode) recoverResponse(stub shim.ChaincodeStubInterface, recovered interface{}) peer.Response {
+	nsFcn, _ := stub.GetFunctionAndParameters()
+	ns, fn := cc.splitFunctionName(nsFcn)
+
+	report := CrashReport{Contract: ns, Function: fn, TxID: stub.GetTxID(), Frames: panicFrames()}
+
+	hash := sha256.Sum256([]byte(strings.Join(report.Frames, "\n")))
+	report.Digest = hex.EncodeToString(hash[:8])
+
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	ctx.details.contractName = ns
+	ctx.details.functionName = fn
+
+	ctx.GetLogger().WithField("digest", report.Digest).WithField("stack", string(debug.Stack())).Errorf("Transaction panicked. %v", recovered)
+
+	payload, _ := json.Marshal(report)
+
+	return peer.Response{Status: shim.ERROR, Message: fmt.Sprintf("Function %s of contract %s panicked. Crash report %s", fn, ns, report.Digest), Payload: payload}
+}
+
+// panicFrames returns the names of the functions on the stack from where the
+// panic occurred up to, but excluding, the call of the contract function by
+// reflection
+func panicFrames() []string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(0, pcs)])
+
+	names := []string{}
+	panicking := false
+
+	for {
+		frame, more := frames.Next()
+
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case !panicking || strings.HasPrefix(frame.Function, "runtime."):
+		case strings.HasPrefix(frame.Function, "reflect."):
+			return names
+		default:
+			names = append(names, frame.Function)
+		}
+
+		if !more || len(names) == maxCrashReportFrames {
+			return names
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type panicContract struct {
+	Contract
+}
+
+func (pc *panicContract) Panic(value string) string {
+	panic("panicked with " + value)
+}
+
+func (pc *panicContract) NilPointer() string {
+	var gs *GoodStruct
+
+	return gs.Prop1
+}
+
+func (pc *panicContract) Succeed() string {
+	return "succeeded"
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestRecoverResponse(t *testing.T) {
+	buf, restore := captureLogs()
+	defer restore()
+
+	cc := convertC2CC(new(panicContract))
+	stub := shimtest.NewMockStub("panicTest", &cc)
+
+	// Should return crash report when function panics
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Panic"), []byte("secret")})
+
+	report := CrashReport{}
+	json.Unmarshal(response.Payload, &report)
+
+	assert.Equal(t, int32(500), response.Status, "should return error status")
+	assert.Equal(t, "Function Panic of contract panicContract panicked. Crash report "+report.Digest, response.Message, "should return digest in message")
+	assert.Equal(t, "panicContract", report.Contract, "should report contract")
+	assert.Equal(t, "Panic", report.Function, "should report function")
+	assert.Equal(t, standardTxID, report.TxID, "should report transaction ID")
+	assert.Len(t, report.Digest, 16, "should report digest")
+	assert.Equal(t, []string{"github.com/awjh-ibm/fabric-go-developer-api/contractapi.(*panicContract).Panic"}, report.Frames, "should report frames up to the call of the function")
+	assert.NotContains(t, string(response.Payload), "secret", "should not return panic value")
+
+	// Should log panic and full stack
+	entries := parseLogEntries(t, buf)
+	assert.Len(t, entries, 1, "should log panic")
+	assert.Equal(t, "ERROR", entries[0]["level"], "should log at error level")
+	assert.Equal(t, "Transaction panicked. panicked with secret", entries[0]["message"], "should log panic value")
+	assert.Equal(t, report.Digest, entries[0]["digest"], "should log digest")
+	assert.Equal(t, "Panic", entries[0]["function"], "should log function")
+	assert.Contains(t, entries[0]["stack"], "panic_recovery_test.go", "should log full stack")
+
+	// Should return same digest for panics from same frames
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Panic"), []byte("other")})
+	otherReport := CrashReport{}
+	json.Unmarshal(response.Payload, &otherReport)
+	assert.Equal(t, report.Digest, otherReport.Digest, "should return same digest for same frames")
+
+	// Should recover runtime panics
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("NilPointer")})
+	otherReport = CrashReport{}
+	json.Unmarshal(response.Payload, &otherReport)
+	assert.Equal(t, int32(500), response.Status, "should return error status for runtime panic")
+	assert.Equal(t, []string{"github.com/awjh-ibm/fabric-go-developer-api/contractapi.(*panicContract).NilPointer"}, otherReport.Frames, "should report frames of runtime panic")
+	assert.NotEqual(t, report.Digest, otherReport.Digest, "should return different digest for different frames")
+
+	// Should continue to process transactions
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Succeed")})
+	assert.Equal(t, "succeeded", string(response.Payload), "should process transactions after panic")
+}
+
+func TestPanicFrames(t *testing.T) {
+	// Should return no frames when not panicking
+	assert.Equal(t, []string{}, panicFrames(), "should return no frames outside panic")
+
+	// Should return frames from panic
+	var frames []string
+
+	func() {
+		defer func() {
+			recover()
+			frames = panicFrames()
+		}()
+
+		panic("some panic")
+	}()
+
+	assert.Equal(t, "github.com/awjh-ibm/fabric-go-developer-api/contractapi.TestPanicFrames.func1", frames[0], "should start at panicking function")
+	assert.Equal(t, "github.com/awjh-ibm/fabric-go-developer-api/contractapi.TestPanicFrames", frames[1], "should include callers")
+	assert.True(t, len(frames) <= maxCrashReportFrames, "should limit number of frames")
+}
@@ -0,0 +1,70 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// PayloadTooLargeDetails is the details of the error returned by Invoke when an
+// arg or the response of a transaction is larger than the chaincode allows
+type PayloadTooLargeDetails struct {
+	Size    int `json:"size"`
+	MaxSize int `json:"maxSize"`
+}
+
+// SetMaxArgumentSize sets the maximum size in bytes of each arg passed to the
+// chaincode, including the function name. A transaction passed a larger arg returns
+// an Error with code 413 before any function is called, so that a client mistake
+// cannot cause a large value to be written to the world state. A size of zero, the
+// default, sets no maximum.
+func (cc *ContractChaincode) SetMaxArgumentSize(size int) {
+	cc.maxArgumentSize = size
+}
+
+// SetMaxResponseSize sets the maximum size in bytes of the payload returned by a
+// successful transaction, after any compression (see EnableResponseCompression).
+// A transaction whose payload is larger returns an Error with code 413 instead.
+// A size of zero, the default, sets no maximum.
+func (cc *ContractChaincode) SetMaxResponseSize(size int) {
+	cc.maxResponseSize = size
+}
+
+// checkArgSizes returns an error if any of the args is larger than the maximum
+// argument size of the chaincode
+func (cc *ContractChaincode) checkArgSizes(args [][]byte) error {
+	if cc.maxArgumentSize <= 0 {
+		return nil
+	}
+
+	for i, arg := range args {
+		if len(arg) > cc.maxArgumentSize {
+			return NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Arg %d is too large. Size %d bytes exceeds the maximum of %d bytes", i, len(arg), cc.maxArgumentSize), PayloadTooLargeDetails{len(arg), cc.maxArgumentSize})
+		}
+	}
+
+	return nil
+}
+
+// checkResponseSize returns an error if the payload is larger than the maximum
+// response size of the chaincode
+func (cc *ContractChaincode) checkResponseSize(payload string) error {
+	if cc.maxResponseSize <= 0 || len(payload) <= cc.maxResponseSize {
+		return nil
+	}
+
+	return NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Response is too large. Size %d bytes exceeds the maximum of %d bytes", len(payload), cc.maxResponseSize), PayloadTooLargeDetails{len(payload), cc.maxResponseSize})
+}
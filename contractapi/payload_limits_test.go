@@ -0,0 +1,127 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type payloadLimitsTestContract struct {
+	Contract
+}
+
+func (pltc *payloadLimitsTestContract) Put(ctx *TransactionContext, value string) error {
+	return ctx.GetStub().PutState("key", []byte(value))
+}
+
+func (pltc *payloadLimitsTestContract) Repeat(value string, count int) string {
+	return strings.Repeat(value, count)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetMaxArgumentSize(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.SetMaxArgumentSize(1024)
+	assert.Equal(t, 1024, cc.maxArgumentSize, "should set max argument size")
+}
+
+func TestSetMaxResponseSize(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.SetMaxResponseSize(1024)
+	assert.Equal(t, 1024, cc.maxResponseSize, "should set max response size")
+}
+
+func TestCheckArgSizes(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should not error when no maximum
+	assert.Nil(t, cc.checkArgSizes([][]byte{[]byte(strings.Repeat("a", 100))}), "should not error when no maximum")
+
+	// Should not error for args not over maximum
+	cc.SetMaxArgumentSize(10)
+	assert.Nil(t, cc.checkArgSizes([][]byte{[]byte("Put"), []byte(strings.Repeat("a", 10))}), "should not error for args not over maximum")
+
+	// Should error for arg over maximum
+	err := cc.checkArgSizes([][]byte{[]byte("Put"), []byte(strings.Repeat("a", 11))})
+	assert.Equal(t, NewError(http.StatusRequestEntityTooLarge, "Arg 1 is too large. Size 11 bytes exceeds the maximum of 10 bytes", PayloadTooLargeDetails{11, 10}), err, "should error for arg over maximum")
+}
+
+func TestCheckResponseSize(t *testing.T) {
+	cc := ContractChaincode{}
+
+	// Should not error when no maximum
+	assert.Nil(t, cc.checkResponseSize(strings.Repeat("a", 100)), "should not error when no maximum")
+
+	// Should not error for payload not over maximum
+	cc.SetMaxResponseSize(10)
+	assert.Nil(t, cc.checkResponseSize(strings.Repeat("a", 10)), "should not error for payload not over maximum")
+
+	// Should error for payload over maximum
+	err := cc.checkResponseSize(strings.Repeat("a", 11))
+	assert.Equal(t, NewError(http.StatusRequestEntityTooLarge, "Response is too large. Size 11 bytes exceeds the maximum of 10 bytes", PayloadTooLargeDetails{11, 10}), err, "should error for payload over maximum")
+}
+
+func TestInvokePayloadLimits(t *testing.T) {
+	cc := convertC2CC(new(payloadLimitsTestContract))
+	cc.SetMaxArgumentSize(10)
+	cc.SetMaxResponseSize(20)
+
+	stub := shimtest.NewMockStub("payloadLimitsTest", &cc)
+
+	// Should call function for payloads within maximums
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Put"), []byte(strings.Repeat("a", 10))})
+	assert.Equal(t, int32(200), response.Status, "should succeed for arg within maximum")
+	assert.Equal(t, []byte(strings.Repeat("a", 10)), stub.State["key"], "should write value")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Repeat"), []byte("a"), []byte("20")})
+	assert.Equal(t, int32(200), response.Status, "should succeed for response within maximum")
+	assert.Equal(t, strings.Repeat("a", 20), string(response.Payload), "should return response within maximum")
+
+	// Should error and not write for arg over maximum
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Put"), []byte(strings.Repeat("b", 11))})
+	assert.Equal(t, int32(http.StatusRequestEntityTooLarge), response.Status, "should return 413 for arg over maximum")
+	assert.Equal(t, "Arg 1 is too large. Size 11 bytes exceeds the maximum of 10 bytes", response.Message, "should return message for arg over maximum")
+	assert.Equal(t, `{"code":413,"message":"Arg 1 is too large. Size 11 bytes exceeds the maximum of 10 bytes","details":{"size":11,"maxSize":10}}`, string(response.Payload), "should return details for arg over maximum")
+	assert.Equal(t, []byte(strings.Repeat("a", 10)), stub.State["key"], "should not write value for arg over maximum")
+
+	// Should error for response over maximum
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Repeat"), []byte("a"), []byte("21")})
+	assert.Equal(t, int32(http.StatusRequestEntityTooLarge), response.Status, "should return 413 for response over maximum")
+	assert.Equal(t, "Response is too large. Size 21 bytes exceeds the maximum of 20 bytes", response.Message, "should return message for response over maximum")
+
+	// Should check size of compressed response
+	cc.EnableResponseCompression(20)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Repeat"), []byte("a"), []byte("9999")})
+	assert.Equal(t, int32(http.StatusRequestEntityTooLarge), response.Status, "should check size of compressed response")
+	assert.Contains(t, response.Message, "Response is too large.", "should return message for compressed response over maximum")
+
+	cc.SetMaxResponseSize(200)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Repeat"), []byte("a"), []byte("9999")})
+	assert.Equal(t, int32(200), response.Status, "should succeed for compressed response within maximum")
+}
@@ -0,0 +1,64 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// LimitConcurrency returns middleware that limits the number of transactions of the
+// functions it is used for that run at once in the chaincode container, to protect
+// the peer from storms of expensive transactions such as rich queries. Add it to a
+// function, or all functions of a contract, using Use e.g.
+//
+//	contract.Use("QueryAssets", contractapi.LimitConcurrency(5))
+//
+// Functions using the same middleware share its limit. Once the limit is reached
+// further transactions are not queued but return an Error with code 429 and the
+// function is not called, so clients should retry later. A transaction holds its
+// place until it completes or its deadline passes (see SetTransactionTimeout). The
+// transaction context must provide the context of the transaction, as
+// TransactionContext does. Panics if the limit is not positive.
+func LimitConcurrency(limit int) func(TransactionContextInterface) error {
+	if limit <= 0 {
+		panic(fmt.Sprintf("Cannot limit concurrency to %d. Limit must be greater than zero", limit))
+	}
+
+	slots := make(chan struct{}, limit)
+
+	return func(ctx TransactionContextInterface) error {
+		contextProvider, ok := ctx.(interface{ Context() context.Context })
+
+		if !ok || contextProvider.Context().Done() == nil {
+			return errors.New("Failed to limit concurrency. Transaction context does not provide the context of the transaction")
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			return NewError(http.StatusTooManyRequests, fmt.Sprintf("Too many concurrent transactions. Limit of %d reached, try again later", limit), nil)
+		}
+
+		go func() {
+			<-contextProvider.Context().Done()
+			<-slots
+		}()
+
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type rateLimitTestContract struct {
+	Contract
+	entered chan bool
+	release chan bool
+}
+
+func (rltc *rateLimitTestContract) Slow() {
+	rltc.entered <- true
+	<-rltc.release
+}
+
+func (rltc *rateLimitTestContract) Fast() {}
+
+type contextlessTestContext struct {
+	TransactionContextInterface
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestLimitConcurrency(t *testing.T) {
+	// Should panic when limit not positive
+	assert.PanicsWithValue(t, "Cannot limit concurrency to 0. Limit must be greater than zero", func() { LimitConcurrency(0) }, "should panic for zero limit")
+	assert.PanicsWithValue(t, "Cannot limit concurrency to -1. Limit must be greater than zero", func() { LimitConcurrency(-1) }, "should panic for negative limit")
+
+	middleware := LimitConcurrency(2)
+
+	newContext := func() (*TransactionContext, context.CancelFunc) {
+		txContext, cancel := context.WithCancel(context.Background())
+
+		ctx := new(TransactionContext)
+		ctx.details.context = txContext
+
+		return ctx, cancel
+	}
+
+	// Should allow transactions up to limit
+	ctx1, cancel1 := newContext()
+	ctx2, cancel2 := newContext()
+	defer cancel2()
+	assert.Nil(t, middleware(ctx1), "should allow first transaction")
+	assert.Nil(t, middleware(ctx2), "should allow second transaction")
+
+	// Should error once limit reached
+	ctx3, cancel3 := newContext()
+	defer cancel3()
+	assert.Equal(t, NewError(http.StatusTooManyRequests, "Too many concurrent transactions. Limit of 2 reached, try again later", nil), middleware(ctx3), "should error when limit reached")
+	assert.Nil(t, LimitConcurrency(2)(ctx3), "should not share limit with other middleware")
+
+	// Should allow transactions once others complete
+	cancel1()
+	assert.Eventually(t, func() bool { return middleware(ctx3) == nil }, time.Second, time.Millisecond, "should allow transaction once another completes")
+
+	// Should error when context does not provide transaction context
+	assert.EqualError(t, middleware(new(TransactionContext)), "Failed to limit concurrency. Transaction context does not provide the context of the transaction", "should error when context never done")
+	assert.EqualError(t, middleware(contextlessTestContext{}), "Failed to limit concurrency. Transaction context does not provide the context of the transaction", "should error when no context")
+}
+
+func TestInvokeLimitConcurrency(t *testing.T) {
+	rltc := new(rateLimitTestContract)
+	rltc.entered = make(chan bool)
+	rltc.release = make(chan bool)
+	rltc.Use("Slow", LimitConcurrency(1))
+	cc := convertC2CC(rltc)
+
+	done := make(chan int32)
+
+	go func() {
+		done <- shimtest.NewMockStub("rateLimitTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("Slow")}).Status
+	}()
+
+	<-rltc.entered
+
+	// Should shed load once limit reached
+	response := shimtest.NewMockStub("rateLimitTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("Slow")})
+	assert.Equal(t, int32(http.StatusTooManyRequests), response.Status, "should return 429 when limit reached")
+	assert.Equal(t, "Too many concurrent transactions. Limit of 1 reached, try again later", response.Message, "should return limit message")
+
+	response = shimtest.NewMockStub("rateLimitTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("Fast")})
+	assert.Equal(t, int32(200), response.Status, "should not limit other functions")
+
+	// Should allow transactions once running transaction completes
+	rltc.release <- true
+	assert.Equal(t, int32(200), <-done, "should complete running transaction")
+
+	go func() {
+		<-rltc.entered
+		rltc.release <- true
+	}()
+
+	assert.Eventually(t, func() bool {
+		return shimtest.NewMockStub("rateLimitTest", &cc).MockInvoke(standardTxID, [][]byte{[]byte("Slow")}).Status == 200
+	}, time.Second, time.Millisecond, "should allow transaction once running transaction completes")
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// ReadOnlyTag is added to the tags of read only functions in the metadata
+const ReadOnlyTag = "readOnly"
+
+// SetReadOnly sets whether the function may only read the world state. Read only
+// functions are also evaluate functions (see SetEvaluate) and are additionally tagged
+// readOnly in the metadata. When a read only function is invoked the stub returns
+// an error for any call that would write the world state, private data or an event
+// so that query functions cannot write by accident.
+func (fc *FunctionConfig) SetReadOnly(readOnly bool) *FunctionConfig {
+	fc.readOnly = readOnly
+	return fc
+}
+
+// readOnlyStub returns an error for calls that write to the ledger, or set an event,
+// during the invocation of a read only function
+type readOnlyStub struct {
+	shim.ChaincodeStubInterface
+	function string
+}
+
+func (ros *readOnlyStub) deny(call string) error {
+	return fmt.Errorf("Function %s is read only. Cannot call %s", ros.function, call)
+}
+
+func (ros *readOnlyStub) PutState(key string, value []byte) error {
+	return ros.deny("PutState")
+}
+
+func (ros *readOnlyStub) DelState(key string) error {
+	return ros.deny("DelState")
+}
+
+func (ros *readOnlyStub) SetStateValidationParameter(key string, ep []byte) error {
+	return ros.deny("SetStateValidationParameter")
+}
+
+func (ros *readOnlyStub) PutPrivateData(collection string, key string, value []byte) error {
+	return ros.deny("PutPrivateData")
+}
+
+func (ros *readOnlyStub) DelPrivateData(collection string, key string) error {
+	return ros.deny("DelPrivateData")
+}
+
+func (ros *readOnlyStub) SetPrivateDataValidationParameter(collection, key string, ep []byte) error {
+	return ros.deny("SetPrivateDataValidationParameter")
+}
+
+func (ros *readOnlyStub) SetEvent(name string, payload []byte) error {
+	return ros.deny("SetEvent")
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type readOnlyTestContract struct {
+	Contract
+}
+
+func (rotc *readOnlyTestContract) Read(ctx *TransactionContext, key string) (string, error) {
+	value, err := ctx.GetStub().GetState(key)
+
+	return string(value), err
+}
+
+func (rotc *readOnlyTestContract) Write(ctx *TransactionContext, key string, value string) error {
+	return ctx.GetStub().PutState(key, []byte(value))
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetReadOnly(t *testing.T) {
+	fc := new(FunctionConfig)
+
+	assert.Same(t, fc, fc.SetReadOnly(true), "should return config")
+	assert.True(t, fc.readOnly, "should set read only")
+}
+
+func TestReadOnlyStub(t *testing.T) {
+	ros := &readOnlyStub{shimtest.NewMockStub("readOnlyTest", nil), "Read"}
+
+	// Should deny writes
+	assert.EqualError(t, ros.PutState("key", []byte("value")), "Function Read is read only. Cannot call PutState", "should deny PutState")
+	assert.EqualError(t, ros.DelState("key"), "Function Read is read only. Cannot call DelState", "should deny DelState")
+	assert.EqualError(t, ros.SetStateValidationParameter("key", nil), "Function Read is read only. Cannot call SetStateValidationParameter", "should deny SetStateValidationParameter")
+	assert.EqualError(t, ros.PutPrivateData("collection", "key", []byte("value")), "Function Read is read only. Cannot call PutPrivateData", "should deny PutPrivateData")
+	assert.EqualError(t, ros.DelPrivateData("collection", "key"), "Function Read is read only. Cannot call DelPrivateData", "should deny DelPrivateData")
+	assert.EqualError(t, ros.SetPrivateDataValidationParameter("collection", "key", nil), "Function Read is read only. Cannot call SetPrivateDataValidationParameter", "should deny SetPrivateDataValidationParameter")
+	assert.EqualError(t, ros.SetEvent("event", nil), "Function Read is read only. Cannot call SetEvent", "should deny SetEvent")
+
+	// Should allow reads
+	_, err := ros.GetState("key")
+	assert.Nil(t, err, "should allow GetState")
+}
+
+func TestFunctionConfigApplyToReadOnly(t *testing.T) {
+	tm := TransactionMetadata{Name: "Read", Tag: []string{"submitTx"}}
+	new(FunctionConfig).SetReadOnly(true).applyTo(&tm)
+
+	assert.Equal(t, []string{"evaluateTx", ReadOnlyTag}, tm.Tag, "should tag read only function as evaluate and read only")
+}
+
+func TestReadOnlyFunctions(t *testing.T) {
+	rotc := new(readOnlyTestContract)
+	rotc.ConfigureFunction("Read").SetReadOnly(true)
+	rotc.ConfigureFunction("Write").SetReadOnly(true)
+	cc := convertC2CC(rotc)
+
+	// Should expose read only in metadata
+	for _, tm := range cc.metadata.Contracts["readOnlyTestContract"].Transactions {
+		assert.Equal(t, []string{"evaluateTx", ReadOnlyTag}, tm.Tag, "should tag read only functions in metadata")
+	}
+
+	stub := shimtest.NewMockStub("readOnlyTest", &cc)
+	stub.MockTransactionStart("setup")
+	stub.PutState("key", []byte("value"))
+	stub.MockTransactionEnd("setup")
+
+	// Should allow reads in read only function
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Read"), []byte("key")})
+	assert.Equal(t, "value", string(response.Payload), "should read state in read only function")
+
+	// Should error on writes in read only function
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Write"), []byte("key"), []byte("other")})
+	assert.Equal(t, "Function Write is read only. Cannot call PutState", response.Message, "should error on write in read only function")
+
+	// Should allow writes in other functions
+	rotc = new(readOnlyTestContract)
+	rotc.ConfigureFunction("Read").SetReadOnly(true)
+	cc = convertC2CC(rotc)
+	stub = shimtest.NewMockStub("readOnlyTest", &cc)
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("Write"), []byte("key"), []byte("other")})
+	assert.Equal(t, int32(200), response.Status, "should allow writes in functions not read only")
+}
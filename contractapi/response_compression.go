@@ -0,0 +1,167 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-openapi/spec"
+)
+
+// GzipContentEncoding is the content encoding of a CompressedResponse whose
+// payload is compressed using gzip
+const GzipContentEncoding = "gzip"
+
+// CompressedResponse is the envelope returned as the payload of a transaction
+// when its response is compressed (see EnableResponseCompression). Compressed is
+// always true and, as the first property of the envelope, marks the payload as
+// compressed. Payload is the response compressed using the content encoding and
+// is base64 encoded when the envelope is marshalled to JSON.
+type CompressedResponse struct {
+	Compressed      bool   `json:"$compressed"`
+	ContentEncoding string `json:"contentEncoding"`
+	Payload         []byte `json:"payload"`
+}
+
+// compressedResponseMarker begins the payload of every CompressedResponse. Responses
+// beginning with it are always compressed so that a payload beginning with it is
+// known to be a CompressedResponse.
+var compressedResponseMarker = []byte(`{"$compressed":true,`)
+
+// EnableResponseCompression enables compressing the payload of successful
+// transactions that is longer than the threshold number of bytes, so that large
+// responses such as those of functions listing the world state remain under the
+// maximum message size of gRPC. A compressed payload is gzipped and returned in a
+// CompressedResponse envelope with content encoding gzip. Payloads no longer than
+// the threshold are returned as is, unless they begin as a CompressedResponse does.
+// Clients can use DecompressResponse to get the original payload of either. The
+// returns schema of each transaction in the metadata describes that it may return
+// either its result or the envelope. A threshold of zero, the default, disables
+// compression.
+func (cc *ContractChaincode) EnableResponseCompression(threshold int) {
+	if threshold > 0 && cc.compressionThreshold <= 0 {
+		cc.describeCompressedResponses()
+	}
+
+	cc.compressionThreshold = threshold
+}
+
+// describeCompressedResponses sets the returns schema of each transaction in the
+// metadata to allow either its result or a CompressedResponse
+func (cc *ContractChaincode) describeCompressedResponses() {
+	for _, contract := range cc.metadata.Contracts {
+		for i, transaction := range contract.Transactions {
+			if transaction.Returns != nil {
+				contract.Transactions[i].Returns = compressedResponseSchema(transaction.Returns)
+			}
+		}
+	}
+
+	if _, ok := cc.contracts[SystemContractName]; ok {
+		cc.setSystemContractMetadata()
+	}
+}
+
+// compressedResponseSchema returns the schema of the payload of a transaction
+// returning the passed schema when responses may be compressed
+func compressedResponseSchema(result *spec.Schema) *spec.Schema {
+	payload := spec.StringProperty()
+	payload.Format = Base64Format
+
+	envelope := new(spec.Schema)
+	envelope.Type = []string{"object"}
+	envelope.Properties = map[string]spec.Schema{
+		"$compressed":     *spec.BooleanProperty().WithEnum(true),
+		"contentEncoding": *spec.StringProperty().WithEnum(GzipContentEncoding),
+		"payload":         *payload,
+	}
+	envelope.Required = []string{"$compressed", "contentEncoding", "payload"}
+	envelope.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+
+	schema := new(spec.Schema)
+	schema.OneOf = []spec.Schema{*result, *envelope}
+
+	return schema
+}
+
+// compressResponse returns the payload in a CompressedResponse if it is longer
+// than the threshold of the chaincode or begins with the marker of a compressed
+// response, otherwise the payload as is
+func (cc *ContractChaincode) compressResponse(payload string) (string, error) {
+	if cc.compressionThreshold <= 0 {
+		return payload, nil
+	}
+
+	if len(payload) <= cc.compressionThreshold && !bytes.HasPrefix([]byte(payload), compressedResponseMarker) {
+		return payload, nil
+	}
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	if _, err := writer.Write([]byte(payload)); err != nil {
+		return "", fmt.Errorf("Failed to compress response. %s", err.Error())
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("Failed to compress response. %s", err.Error())
+	}
+
+	envelope, _ := json.Marshal(CompressedResponse{true, GzipContentEncoding, buffer.Bytes()})
+
+	return string(envelope), nil
+}
+
+// DecompressResponse returns the original payload of a transaction response that
+// may have been compressed by the chaincode (see EnableResponseCompression). The
+// payload is returned as is when it does not begin as a CompressedResponse does.
+// An error is returned when the envelope is not valid, the content encoding is not
+// supported or the compressed payload is not valid.
+func DecompressResponse(payload []byte) ([]byte, error) {
+	if !bytes.HasPrefix(payload, compressedResponseMarker) {
+		return payload, nil
+	}
+
+	envelope := CompressedResponse{}
+
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("Failed to parse compressed response. %s", err.Error())
+	}
+
+	if envelope.ContentEncoding != GzipContentEncoding {
+		return nil, fmt.Errorf("Unsupported content encoding %s. Expected %s", envelope.ContentEncoding, GzipContentEncoding)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(envelope.Payload))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decompress response. %s", err.Error())
+	}
+
+	defer reader.Close()
+
+	decompressed, err := ioutil.ReadAll(reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decompress response. %s", err.Error())
+	}
+
+	return decompressed, nil
+}
@@ -0,0 +1,199 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type responseCompressionTestContract struct {
+	Contract
+}
+
+func (rctc *responseCompressionTestContract) Repeat(value string, count int) string {
+	return strings.Repeat(value, count)
+}
+
+func (rctc *responseCompressionTestContract) Nothing() {}
+
+func gzipString(t *testing.T, value string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte(value))
+	writer.Close()
+
+	return buffer.Bytes()
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableResponseCompression(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.EnableResponseCompression(1024)
+	assert.Equal(t, 1024, cc.compressionThreshold, "should set compression threshold")
+
+	// Should describe envelope in returns schema of transactions
+	cc = convertC2CC(new(responseCompressionTestContract))
+	repeat := *cc.metadata.Contracts["responseCompressionTestContract"].Transactions[1].Returns
+
+	cc.EnableResponseCompression(1024)
+	transactions := cc.metadata.Contracts["responseCompressionTestContract"].Transactions
+	assert.Equal(t, compressedResponseSchema(&repeat), transactions[1].Returns, "should allow result or envelope")
+	assert.Nil(t, transactions[0].Returns, "should not add returns to functions without")
+	assert.Contains(t, cc.contracts[SystemContractName].receiver.Interface().(*systemContract).metadata, `"$compressed"`, "should update metadata of system contract")
+
+	// Should not describe envelope again
+	cc.EnableResponseCompression(2048)
+	assert.Equal(t, compressedResponseSchema(&repeat), cc.metadata.Contracts["responseCompressionTestContract"].Transactions[1].Returns, "should not wrap schema twice")
+}
+
+func TestCompressedResponseSchema(t *testing.T) {
+	schema := compressedResponseSchema(spec.StringProperty())
+
+	assert.Len(t, schema.OneOf, 2, "should be one of result or envelope")
+	assert.Equal(t, *spec.StringProperty(), schema.OneOf[0], "should allow result")
+
+	envelope := schema.OneOf[1]
+	assert.Equal(t, []string{"$compressed", "contentEncoding", "payload"}, envelope.Required, "should require properties of envelope")
+	assert.Equal(t, []interface{}{true}, envelope.Properties["$compressed"].Enum, "should require marker")
+	assert.Equal(t, []interface{}{GzipContentEncoding}, envelope.Properties["contentEncoding"].Enum, "should describe encoding")
+	assert.Equal(t, Base64Format, envelope.Properties["payload"].Format, "should describe payload as base64")
+	assert.False(t, envelope.AdditionalProperties.Allows, "should not allow other properties")
+}
+
+func TestCompressResponse(t *testing.T) {
+	var payload string
+	var err error
+
+	cc := ContractChaincode{}
+
+	// Should not compress when disabled
+	payload, err = cc.compressResponse(strings.Repeat("a", 100))
+	assert.Nil(t, err, "should not error when disabled")
+	assert.Equal(t, strings.Repeat("a", 100), payload, "should return payload when disabled")
+
+	// Should not compress payload not over threshold
+	cc.EnableResponseCompression(100)
+	payload, err = cc.compressResponse(strings.Repeat("a", 100))
+	assert.Nil(t, err, "should not error for payload not over threshold")
+	assert.Equal(t, strings.Repeat("a", 100), payload, "should return payload not over threshold")
+
+	// Should compress payload over threshold
+	payload, err = cc.compressResponse(strings.Repeat("a", 101))
+	assert.Nil(t, err, "should not error for payload over threshold")
+
+	envelope := CompressedResponse{}
+	assert.Nil(t, json.Unmarshal([]byte(payload), &envelope), "should return envelope")
+	assert.Equal(t, GzipContentEncoding, envelope.ContentEncoding, "should set content encoding")
+
+	assert.True(t, envelope.Compressed, "should mark envelope compressed")
+	assert.True(t, strings.HasPrefix(payload, string(compressedResponseMarker)), "should begin with marker")
+
+	reader, _ := gzip.NewReader(bytes.NewReader(envelope.Payload))
+	buffer := new(bytes.Buffer)
+	buffer.ReadFrom(reader)
+	assert.Equal(t, strings.Repeat("a", 101), buffer.String(), "should gzip payload")
+
+	// Should compress payload beginning with marker
+	payload, err = cc.compressResponse(`{"$compressed":true,"a":1}`)
+	assert.Nil(t, err, "should not error for payload beginning with marker")
+
+	decompressed, _ := DecompressResponse([]byte(payload))
+	assert.Equal(t, `{"$compressed":true,"a":1}`, string(decompressed), "should compress payload beginning with marker")
+}
+
+func TestDecompressResponse(t *testing.T) {
+	var payload []byte
+	var err error
+
+	// Should return payload that is not an envelope
+	payload, err = DecompressResponse([]byte("some payload"))
+	assert.Nil(t, err, "should not error for payload not JSON")
+	assert.Equal(t, []byte("some payload"), payload, "should return payload not JSON")
+
+	payload, err = DecompressResponse([]byte("{\"prop1\":\"value\"}"))
+	assert.Nil(t, err, "should not error for payload not an envelope")
+	assert.Equal(t, []byte("{\"prop1\":\"value\"}"), payload, "should return payload not an envelope")
+
+	payload, err = DecompressResponse([]byte(`{"contentEncoding":"gzip","payload":"c29tZSB2YWx1ZQ=="}`))
+	assert.Nil(t, err, "should not error for result with properties of envelope")
+	assert.Equal(t, []byte(`{"contentEncoding":"gzip","payload":"c29tZSB2YWx1ZQ=="}`), payload, "should return result without marker as is")
+
+	// Should decompress envelope
+	envelope, _ := json.Marshal(CompressedResponse{true, GzipContentEncoding, gzipString(t, "some value")})
+	payload, err = DecompressResponse(envelope)
+	assert.Nil(t, err, "should not error for valid envelope")
+	assert.Equal(t, []byte("some value"), payload, "should decompress payload")
+
+	// Should error for bad envelope
+	_, err = DecompressResponse([]byte(`{"$compressed":true,`))
+	assert.Contains(t, err.Error(), "Failed to parse compressed response.", "should error for envelope not JSON")
+
+	envelope, _ = json.Marshal(CompressedResponse{true, "zstd", []byte("some value")})
+	_, err = DecompressResponse(envelope)
+	assert.EqualError(t, err, "Unsupported content encoding zstd. Expected gzip", "should error for unsupported encoding")
+
+	envelope, _ = json.Marshal(CompressedResponse{true, GzipContentEncoding, []byte("some value")})
+	_, err = DecompressResponse(envelope)
+	assert.Contains(t, err.Error(), "Failed to decompress response.", "should error for payload not gzipped")
+
+	compressed := gzipString(t, "some value")
+	envelope, _ = json.Marshal(CompressedResponse{true, GzipContentEncoding, compressed[:len(compressed)-4]})
+	_, err = DecompressResponse(envelope)
+	assert.Contains(t, err.Error(), "Failed to decompress response.", "should error for truncated payload")
+}
+
+func TestInvokeResponseCompression(t *testing.T) {
+	var payload []byte
+	var err error
+
+	cc := convertC2CC(new(responseCompressionTestContract))
+	cc.EnableResponseCompression(10)
+
+	// Should return payload not over threshold as is
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Repeat", "a", "10"}, invokeType, strings.Repeat("a", 10))
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Nothing"}, invokeType, "")
+
+	// Should compress payload over threshold
+	stub := shimtest.NewMockStub("responseCompressionTest", &cc)
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("Repeat"), []byte("a"), []byte("1000")})
+	assert.Equal(t, int32(200), response.Status, "should succeed")
+	assert.True(t, len(response.Payload) < 1000, "should compress payload")
+
+	payload, err = DecompressResponse(response.Payload)
+	assert.Nil(t, err, "should decompress payload")
+	assert.Equal(t, strings.Repeat("a", 1000), string(payload), "should return original payload")
+
+	// Should not compress errors
+	callContractFunctionAndCheckError(t, cc, []string{"Repeat", "a", "not a number"}, invokeType, "Failed to convert arg for parameter 1 (param1) of function Repeat. Expected type int, received \"not a number\". Param not a number could not be converted to type int")
+}
@@ -0,0 +1,440 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// DefaultResultStreamPageSize the number of results returned in a page of a
+// result stream when a blank page size is passed
+const DefaultResultStreamPageSize = 100
+
+// MaxResultStreamPageSize the largest page size that can be requested for a
+// page of a result stream
+const MaxResultStreamPageSize = 1000
+
+// Names of the args passed, as the last two args, to a function returning a
+// ResultStream to select the page of results returned
+const (
+	ResultStreamPageSizeParameter = "pageSize"
+	ResultStreamBookmarkParameter = "bookmark"
+)
+
+// ResultStream is returned by functions whose results may be too large to return
+// in a single response. Rather than the stream being marshalled the results are
+// returned a page at a time as a ResultStreamPage. Callers must pass the page size
+// and the bookmark of the previous page as the last two args, after those of the
+// parameters of the function, passing blank strings for the first page of the
+// default size. The function is called again for each page. Streams returned by
+// GetStateByRangeStream and GetQueryResultStream of the transaction context read
+// each page from the bookmark of the ledger. For other streams the bookmark is the
+// number of results already returned, so the stream must return the same results
+// in the same order each time the function is called, and results before the
+// bookmark are read and discarded. The stream is closed once the page is read.
+type ResultStream interface {
+	// HasNext should return whether the stream has further results
+	HasNext() bool
+
+	// Next should return the next result of the stream
+	Next() (interface{}, error)
+
+	// Close should release resources held by the stream e.g. close the iterator
+	// it reads results from
+	Close() error
+}
+
+// ResultStreamPage is the response of a function returning a ResultStream. The
+// bookmark of the metadata is blank for the last page.
+type ResultStreamPage struct {
+	Results  []interface{}         `json:"results"`
+	Metadata QueryResponseMetadata `json:"metadata"`
+}
+
+var resultStreamType = reflect.TypeOf((*ResultStream)(nil)).Elem()
+var resultStreamPageType = reflect.TypeOf(ResultStreamPage{})
+
+type resultStreamRequest struct {
+	pageSize int32
+	bookmark string
+}
+
+// pagedResultStream is implemented by result streams able to start reading from a
+// bookmark, so that a page is read without reading the results before it
+type pagedResultStream interface {
+	ResultStream
+
+	// startPage prepares the stream to return the page of results from the
+	// bookmark and returns the bookmark of the page after, blank if none
+	startPage(pageSize int32, bookmark string) (string, error)
+}
+
+// stateResultStream streams the results of a state iterator, decoding their
+// values as GetStateAs would
+type stateResultStream struct {
+	iterator  *StateIterator
+	valueType reflect.Type
+}
+
+func (srs *stateResultStream) HasNext() bool {
+	return srs.iterator.HasNext()
+}
+
+func (srs *stateResultStream) Next() (interface{}, error) {
+	elem, target := newDecodeTarget(srs.valueType)
+
+	if _, err := srs.iterator.NextAs(target); err != nil {
+		return nil, err
+	}
+
+	return elem.Elem().Interface(), nil
+}
+
+func (srs *stateResultStream) Close() error {
+	return srs.iterator.Close()
+}
+
+// ledgerResultStream streams the results of a query of the ledger, reading a page
+// using the paginated form of the query when started at a bookmark and every result
+// of the query otherwise
+type ledgerResultStream struct {
+	query      func() (shim.StateQueryIteratorInterface, error)
+	queryPage  func(pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	valueType  reflect.Type
+	results    *stateResultStream
+	queryError error
+}
+
+func (lrs *ledgerResultStream) startPage(pageSize int32, bookmark string) (string, error) {
+	iterator, metadata, err := lrs.queryPage(pageSize, bookmark)
+
+	if err != nil {
+		return "", err
+	}
+
+	lrs.results = &stateResultStream{&StateIterator{iterator}, lrs.valueType}
+
+	if metadata == nil || metadata.FetchedRecordsCount < pageSize {
+		return "", nil
+	}
+
+	return metadata.Bookmark, nil
+}
+
+// open queries every result when the stream is read without starting a page. An
+// error querying is returned by Next.
+func (lrs *ledgerResultStream) open() {
+	if lrs.results != nil || lrs.queryError != nil {
+		return
+	}
+
+	iterator, err := lrs.query()
+
+	if err != nil {
+		lrs.queryError = err
+		return
+	}
+
+	lrs.results = &stateResultStream{&StateIterator{iterator}, lrs.valueType}
+}
+
+func (lrs *ledgerResultStream) HasNext() bool {
+	lrs.open()
+
+	return lrs.queryError != nil || lrs.results.HasNext()
+}
+
+func (lrs *ledgerResultStream) Next() (interface{}, error) {
+	lrs.open()
+
+	if lrs.queryError != nil {
+		return nil, lrs.queryError
+	}
+
+	return lrs.results.Next()
+}
+
+func (lrs *ledgerResultStream) Close() error {
+	if lrs.results == nil {
+		return nil
+	}
+
+	return lrs.results.Close()
+}
+
+// GetStateByRangeStream returns a ResultStream of the values of the keys in the
+// range, decoded into new values of the type of the sample as for AsResultStream.
+// When returned by a function each page is read using GetStateByRangeWithPagination,
+// passing the bookmark of the ledger, so that the keys before the page are not read.
+// Fabric only allows paginated queries in transactions that are evaluated. The last
+// page may be empty when the number of keys is a multiple of the page size. When
+// state namespacing is enabled the keys of the contract in the range are read, as
+// for ForEachState, and pages are read using GetStateByPartialCompositeKeyWithPagination.
+func (ctx *TransactionContext) GetStateByRangeStream(startKey string, endKey string, sample interface{}) ResultStream {
+	stub := ctx.GetStub()
+
+	return &ledgerResultStream{
+		query: func() (shim.StateQueryIteratorInterface, error) {
+			return ctx.getStateByRange(startKey, endKey)
+		},
+		queryPage: func(pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+			if ctx.isSharedState(startKey) {
+				return stub.GetStateByRangeWithPagination(startKey, endKey, pageSize, bookmark)
+			}
+
+			return ctx.getNamespacedRangePage(startKey, endKey, pageSize, bookmark)
+		},
+		valueType: reflect.TypeOf(sample),
+	}
+}
+
+// getNamespacedRangePage reads a page of the keys of the contract in the range. The
+// bookmark of a range query is the key the next page starts from, so the first
+// page starts from the namespaced start key and there is no next page once the
+// bookmark passes the namespaced end key.
+func (ctx *TransactionContext) getNamespacedRangePage(startKey string, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	stub := ctx.GetStub()
+
+	if bookmark == "" && startKey != "" {
+		namespacedStart, err := stub.CreateCompositeKey(ctx.details.contractName, []string{startKey})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		bookmark = namespacedStart
+	}
+
+	iterator, metadata, err := stub.GetStateByPartialCompositeKeyWithPagination(ctx.details.contractName, []string{}, pageSize, bookmark)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if metadata != nil && endKey != "" {
+		namespacedEnd, err := stub.CreateCompositeKey(ctx.details.contractName, []string{endKey})
+
+		if err != nil {
+			iterator.Close()
+			return nil, nil, err
+		}
+
+		if metadata.Bookmark >= namespacedEnd {
+			metadata = &peer.QueryResponseMetadata{FetchedRecordsCount: metadata.FetchedRecordsCount}
+		}
+	}
+
+	return &namespacedRangeIterator{StateQueryIteratorInterface: iterator, ctx: ctx, startKey: startKey, endKey: endKey}, metadata, nil
+}
+
+// GetQueryResultStream returns a ResultStream of the values of the results of the
+// rich query, decoded as for GetStateByRangeStream. When returned by a function each
+// page is read using GetQueryResultWithPagination, passing the bookmark of the ledger.
+func (ctx *TransactionContext) GetQueryResultStream(query string, sample interface{}) ResultStream {
+	stub := ctx.GetStub()
+
+	return &ledgerResultStream{
+		query: func() (shim.StateQueryIteratorInterface, error) {
+			return stub.GetQueryResult(query)
+		},
+		queryPage: func(pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+			return stub.GetQueryResultWithPagination(query, pageSize, bookmark)
+		},
+		valueType: reflect.TypeOf(sample),
+	}
+}
+
+// AsResultStream returns a ResultStream of the values of the results of the
+// iterator, decoded into new values of the type of the sample e.g. passing
+// Asset{} streams values of type Asset
+func (si *StateIterator) AsResultStream(sample interface{}) ResultStream {
+	return &stateResultStream{si, reflect.TypeOf(sample)}
+}
+
+func isResultStreamFunction(fn *contractFunction) bool {
+	return fn.returns.success == resultStreamType
+}
+
+// splitResultStreamParams separates the page size and bookmark args, the last two
+// args, of a function returning a result stream from those for its parameters.
+// They are always passed so that they are not confused with the args of optional
+// parameters left out.
+func splitResultStreamParams(params []string) ([]string, *resultStreamRequest, error) {
+	if len(params) < 2 {
+		return nil, nil, errors.New("Missing page size and bookmark. Expected them as the last two args, blank for the first page of the default size")
+	}
+
+	numParams := len(params) - 2
+	request := &resultStreamRequest{pageSize: DefaultResultStreamPageSize, bookmark: params[numParams+1]}
+
+	if params[numParams] != "" {
+		pageSize, err := strconv.ParseInt(params[numParams], 10, 32)
+
+		if err != nil || pageSize < 1 || pageSize > MaxResultStreamPageSize {
+			return nil, nil, fmt.Errorf("Invalid page size %s. Expected an integer from 1 to %d", params[numParams], MaxResultStreamPageSize)
+		}
+
+		request.pageSize = int32(pageSize)
+	}
+
+	return params[:numParams], request, nil
+}
+
+// readPage closes the stream having read the page of results it was requested
+func (rsr *resultStreamRequest) readPage(stream ResultStream) (*ResultStreamPage, error) {
+	page := &ResultStreamPage{Results: []interface{}{}}
+
+	if stream == nil {
+		return page, nil
+	}
+
+	defer stream.Close()
+
+	if paged, ok := stream.(pagedResultStream); ok {
+		return rsr.readLedgerPage(paged, page)
+	}
+
+	offset := 0
+
+	if rsr.bookmark != "" {
+		var err error
+		offset, err = strconv.Atoi(rsr.bookmark)
+
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("Invalid bookmark %s", rsr.bookmark)
+		}
+	}
+
+	for i := 0; i < offset; i++ {
+		if !stream.HasNext() {
+			return nil, errors.New("Bookmark is beyond the end of the results")
+		}
+
+		if _, err := stream.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	for int32(len(page.Results)) < rsr.pageSize && stream.HasNext() {
+		result, err := stream.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.Results = append(page.Results, result)
+	}
+
+	page.Metadata.FetchedRecordsCount = int32(len(page.Results))
+
+	if stream.HasNext() {
+		page.Metadata.Bookmark = strconv.Itoa(offset + len(page.Results))
+	}
+
+	return page, nil
+}
+
+// readLedgerPage reads the page of a stream starting from the bookmark of the ledger
+func (rsr *resultStreamRequest) readLedgerPage(stream pagedResultStream, page *ResultStreamPage) (*ResultStreamPage, error) {
+	bookmark, err := stream.startPage(rsr.pageSize, rsr.bookmark)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for int32(len(page.Results)) < rsr.pageSize && stream.HasNext() {
+		result, err := stream.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.Results = append(page.Results, result)
+	}
+
+	page.Metadata.FetchedRecordsCount = int32(len(page.Results))
+	page.Metadata.Bookmark = bookmark
+
+	return page, nil
+}
+
+// applyResultStreamMetadata describes the page returned by a function returning a
+// result stream and the page size and bookmark args it takes
+func applyResultStreamMetadata(transactionMetadata *TransactionMetadata) {
+	transactionMetadata.Parameters = append(
+		transactionMetadata.Parameters,
+		ParameterMetadata{Name: ResultStreamPageSizeParameter, Required: true, Schema: *spec.Int32Property()},
+		ParameterMetadata{Name: ResultStreamBookmarkParameter, Required: true, Schema: *spec.StringProperty()},
+	)
+
+	transactionMetadata.Pagination = &PaginationMetadata{
+		PageSizeParameter: ResultStreamPageSizeParameter,
+		BookmarkParameter: ResultStreamBookmarkParameter,
+		ResultsProperty:   "results",
+		MetadataProperty:  "metadata",
+	}
+}
+
+// respond reads the requested page of the stream returned by a function, or the
+// first page when there is no request, and returns it serialized as the response
+func (rsr *resultStreamRequest) respond(streamValue reflect.Value, errorValue reflect.Value, serializer Serializer) (string, interface{}, error) {
+	if rsr == nil {
+		rsr = &resultStreamRequest{pageSize: DefaultResultStreamPageSize}
+	}
+
+	var stream ResultStream
+
+	if !streamValue.IsNil() {
+		stream = streamValue.Interface().(ResultStream)
+	}
+
+	if errorValue.IsValid() && !errorValue.IsNil() {
+		if stream != nil {
+			stream.Close()
+		}
+
+		return "", nil, errorValue.Interface().(error)
+	}
+
+	page, err := rsr.readPage(stream)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	response, err := getSerializer(serializer).ToString(reflect.ValueOf(*page), resultStreamPageType)
+
+	return response, *page, err
+}
+
+// withoutResultStreamParameters returns the metadata of a function returning a
+// result stream without the page size and bookmark parameters added to it
+func withoutResultStreamParameters(transaction *TransactionMetadata, numParams int) *TransactionMetadata {
+	if transaction == nil || len(transaction.Parameters) <= numParams {
+		return transaction
+	}
+
+	trimmed := *transaction
+	trimmed.Parameters = trimmed.Parameters[:numParams]
+
+	return &trimmed
+}
@@ -0,0 +1,412 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type countingStream struct {
+	next      int
+	end       int
+	failAt    int
+	closed    bool
+	closeErrs bool
+}
+
+func (cs *countingStream) HasNext() bool {
+	return cs.next < cs.end
+}
+
+func (cs *countingStream) Next() (interface{}, error) {
+	if cs.next == cs.failAt {
+		return nil, errors.New("some stream error")
+	}
+
+	cs.next++
+
+	return cs.next - 1, nil
+}
+
+func (cs *countingStream) Close() error {
+	cs.closed = true
+	return nil
+}
+
+var lastCountingStream *countingStream
+
+type resultStreamContract struct {
+	Contract
+}
+
+func (rsc *resultStreamContract) Count(end int) ResultStream {
+	lastCountingStream = &countingStream{end: end, failAt: -1}
+	return lastCountingStream
+}
+
+func (rsc *resultStreamContract) All(ctx *TransactionContext) (ResultStream, error) {
+	iterator, err := ctx.GetStub().GetStateByRange("", "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return (&StateIterator{iterator}).AsResultStream(GoodStruct{}), nil
+}
+
+func (rsc *resultStreamContract) Fail() (ResultStream, error) {
+	lastCountingStream = &countingStream{end: 1, failAt: -1}
+	return lastCountingStream, errors.New("some function error")
+}
+
+func (rsc *resultStreamContract) None() ResultStream {
+	return nil
+}
+
+func (rsc *resultStreamContract) Optional(end int, prefix *string) ResultStream {
+	return &countingStream{end: end, failAt: -1}
+}
+
+// ledgerPageStub pages range queries using the key of the first result of the
+// next page as the bookmark, recording the bookmarks it is passed
+type ledgerPageStub struct {
+	*shimtest.MockStub
+	bookmarks []string
+}
+
+func (lps *ledgerPageStub) GetStateByRangeWithPagination(startKey string, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	return lps.page(startKey, endKey, pageSize, bookmark)
+}
+
+func (lps *ledgerPageStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	partialKey, _ := lps.CreateCompositeKey(objectType, keys)
+
+	return lps.page(partialKey, partialKey+string(utf8.MaxRune), pageSize, bookmark)
+}
+
+func (lps *ledgerPageStub) page(startKey string, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	lps.bookmarks = append(lps.bookmarks, bookmark)
+
+	if bookmark != "" {
+		startKey = bookmark
+	}
+
+	keys := []string{}
+	all := shimtest.NewMockStateRangeQueryIterator(lps.MockStub, startKey, endKey)
+
+	for all.HasNext() {
+		kv, _ := all.Next()
+		keys = append(keys, kv.Key)
+	}
+
+	if int32(len(keys)) <= pageSize {
+		return shimtest.NewMockStateRangeQueryIterator(lps.MockStub, startKey, endKey), &peer.QueryResponseMetadata{FetchedRecordsCount: int32(len(keys)), Bookmark: "end"}, nil
+	}
+
+	return shimtest.NewMockStateRangeQueryIterator(lps.MockStub, startKey, keys[pageSize]), &peer.QueryResponseMetadata{FetchedRecordsCount: pageSize, Bookmark: keys[pageSize]}, nil
+}
+
+func (lps *ledgerPageStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	return nil, errors.New("some query error")
+}
+
+func (lps *ledgerPageStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("some query error")
+}
+
+func newLedgerPageStub() *ledgerPageStub {
+	stub := shimtest.NewMockStub("ledgerPage", nil)
+
+	stub.MockTransactionStart(standardTxID)
+	stub.PutState("key1", []byte(`{"Prop1":"value1"}`))
+	stub.PutState("key2", []byte(`{"Prop1":"value2"}`))
+	stub.PutState("key3", []byte(`{"Prop1":"value3"}`))
+	stub.MockTransactionEnd(standardTxID)
+
+	return &ledgerPageStub{MockStub: stub}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSplitResultStreamParams(t *testing.T) {
+	var params []string
+	var request *resultStreamRequest
+	var err error
+
+	// Should use defaults for blank page args
+	params, request, err = splitResultStreamParams([]string{"a", "", ""})
+	assert.Nil(t, err, "should not error for blank page args")
+	assert.Equal(t, []string{"a"}, params, "should remove blank page args")
+	assert.Equal(t, &resultStreamRequest{DefaultResultStreamPageSize, ""}, request, "should use defaults for blank page args")
+
+	// Should use page args passed
+	params, request, err = splitResultStreamParams([]string{"a", "10", "some bookmark"})
+	assert.Nil(t, err, "should not error for valid page args")
+	assert.Equal(t, []string{"a"}, params, "should remove page args")
+	assert.Equal(t, &resultStreamRequest{10, "some bookmark"}, request, "should use page size and bookmark passed")
+
+	params, request, err = splitResultStreamParams([]string{"10", "20"})
+	assert.Nil(t, err, "should not error for only page args")
+	assert.Equal(t, []string{}, params, "should return no params")
+	assert.Equal(t, &resultStreamRequest{10, "20"}, request, "should take last two args as page args")
+
+	params, _, _ = splitResultStreamParams([]string{"a", "b", "10", ""})
+	assert.Equal(t, []string{"a", "b"}, params, "should take args before last two as params")
+
+	// Should error when page args missing
+	_, _, err = splitResultStreamParams([]string{"a"})
+	assert.EqualError(t, err, "Missing page size and bookmark. Expected them as the last two args, blank for the first page of the default size", "should error when page args missing")
+
+	// Should error for invalid page size
+	_, _, err = splitResultStreamParams([]string{"0", ""})
+	assert.EqualError(t, err, "Invalid page size 0. Expected an integer from 1 to 1000", "should error for page size less than one")
+
+	_, _, err = splitResultStreamParams([]string{"1001", ""})
+	assert.EqualError(t, err, "Invalid page size 1001. Expected an integer from 1 to 1000", "should error for page size greater than max")
+
+	_, _, err = splitResultStreamParams([]string{"ten", ""})
+	assert.EqualError(t, err, "Invalid page size ten. Expected an integer from 1 to 1000", "should error for page size not a number")
+}
+
+func TestResultStreamRequestReadPage(t *testing.T) {
+	var page *ResultStreamPage
+	var err error
+
+	// Should read first page and return bookmark of next
+	stream := &countingStream{end: 5, failAt: -1}
+	page, err = (&resultStreamRequest{2, ""}).readPage(stream)
+	assert.Nil(t, err, "should not error reading page")
+	assert.Equal(t, &ResultStreamPage{[]interface{}{0, 1}, QueryResponseMetadata{2, "2"}}, page, "should return first page")
+	assert.True(t, stream.closed, "should close stream")
+
+	// Should skip results before bookmark
+	page, _ = (&resultStreamRequest{2, "2"}).readPage(&countingStream{end: 5, failAt: -1})
+	assert.Equal(t, &ResultStreamPage{[]interface{}{2, 3}, QueryResponseMetadata{2, "4"}}, page, "should return page after bookmark")
+
+	// Should return blank bookmark for last page
+	page, _ = (&resultStreamRequest{2, "4"}).readPage(&countingStream{end: 5, failAt: -1})
+	assert.Equal(t, &ResultStreamPage{[]interface{}{4}, QueryResponseMetadata{1, ""}}, page, "should return last page")
+
+	page, _ = (&resultStreamRequest{2, "5"}).readPage(&countingStream{end: 5, failAt: -1})
+	assert.Equal(t, &ResultStreamPage{[]interface{}{}, QueryResponseMetadata{0, ""}}, page, "should return empty page at end")
+
+	page, _ = (&resultStreamRequest{2, ""}).readPage(nil)
+	assert.Equal(t, &ResultStreamPage{[]interface{}{}, QueryResponseMetadata{0, ""}}, page, "should return empty page for nil stream")
+
+	// Should error for invalid bookmark
+	stream = &countingStream{end: 5, failAt: -1}
+	_, err = (&resultStreamRequest{2, "-1"}).readPage(stream)
+	assert.EqualError(t, err, "Invalid bookmark -1", "should error for negative bookmark")
+	assert.True(t, stream.closed, "should close stream for invalid bookmark")
+
+	_, err = (&resultStreamRequest{2, "some bookmark"}).readPage(&countingStream{end: 5, failAt: -1})
+	assert.EqualError(t, err, "Invalid bookmark some bookmark", "should error for bookmark not a number")
+
+	// Should error when bookmark beyond results
+	stream = &countingStream{end: 5, failAt: -1}
+	_, err = (&resultStreamRequest{2, "6"}).readPage(stream)
+	assert.EqualError(t, err, "Bookmark is beyond the end of the results", "should error for bookmark beyond results")
+	assert.True(t, stream.closed, "should close stream when errors")
+
+	// Should error when stream errors
+	_, err = (&resultStreamRequest{2, "2"}).readPage(&countingStream{end: 5, failAt: 1})
+	assert.EqualError(t, err, "some stream error", "should error when skipping errors")
+
+	_, err = (&resultStreamRequest{2, "2"}).readPage(&countingStream{end: 5, failAt: 3})
+	assert.EqualError(t, err, "some stream error", "should error when reading errors")
+}
+
+func TestLedgerResultStream(t *testing.T) {
+	var page *ResultStreamPage
+	var err error
+
+	stub := newLedgerPageStub()
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should read pages from bookmark of ledger
+	page, err = (&resultStreamRequest{2, ""}).readPage(ctx.GetStateByRangeStream("key1", "key9", GoodStruct{}))
+	assert.Nil(t, err, "should not error reading first page")
+	assert.Equal(t, &ResultStreamPage{[]interface{}{GoodStruct{Prop1: "value1"}, GoodStruct{Prop1: "value2"}}, QueryResponseMetadata{2, "key3"}}, page, "should return first page with bookmark of ledger")
+
+	page, err = (&resultStreamRequest{2, "key3"}).readPage(ctx.GetStateByRangeStream("key1", "key9", GoodStruct{}))
+	assert.Nil(t, err, "should not error reading last page")
+	assert.Equal(t, &ResultStreamPage{[]interface{}{GoodStruct{Prop1: "value3"}}, QueryResponseMetadata{1, ""}}, page, "should return last page with blank bookmark")
+	assert.Equal(t, []string{"", "key3"}, stub.bookmarks, "should pass bookmarks to ledger")
+
+	// Should read every result when not paged
+	stream := ctx.GetStateByRangeStream("key2", "key9", new(GoodStruct))
+	assert.True(t, stream.HasNext(), "should have results")
+	value, err := stream.Next()
+	assert.Nil(t, err, "should not error reading result")
+	assert.Equal(t, &GoodStruct{Prop1: "value2"}, value, "should decode value")
+	stream.Next()
+	assert.False(t, stream.HasNext(), "should have read every result")
+	assert.Nil(t, stream.Close(), "should close iterator")
+	assert.Len(t, stub.bookmarks, 2, "should not page when not paged")
+
+	// Should return errors of query
+	_, err = (&resultStreamRequest{2, ""}).readPage(ctx.GetQueryResultStream("some query", GoodStruct{}))
+	assert.EqualError(t, err, "some query error", "should return error of paged query")
+
+	stream = ctx.GetQueryResultStream("some query", GoodStruct{})
+	assert.True(t, stream.HasNext(), "should have next when query errors")
+	_, err = stream.Next()
+	assert.EqualError(t, err, "some query error", "should return error of query")
+	assert.Nil(t, stream.Close(), "should not error closing unopened stream")
+}
+
+func TestLedgerResultStreamNamespaced(t *testing.T) {
+	var page *ResultStreamPage
+	var err error
+
+	stub := newLedgerPageStub()
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	ctx.details.contractName = "simple"
+	ctx.details.stateNamespacing = true
+
+	stub.MockTransactionStart(standardTxID)
+	for _, key := range []string{"key1", "key2", "key3", "key4"} {
+		ctx.PutStateAs(key, GoodStruct{Prop1: "simple " + key})
+	}
+	stub.MockTransactionEnd(standardTxID)
+
+	key2, _ := stub.CreateCompositeKey("simple", []string{"key2"})
+
+	// Should read pages of keys of contract from namespaced start key
+	page, err = (&resultStreamRequest{1, ""}).readPage(ctx.GetStateByRangeStream("key1", "key4", GoodStruct{}))
+	assert.Nil(t, err, "should not error reading first namespaced page")
+	assert.Equal(t, &ResultStreamPage{[]interface{}{GoodStruct{Prop1: "simple key1"}}, QueryResponseMetadata{1, key2}}, page, "should return first page of keys of contract")
+
+	page, err = (&resultStreamRequest{1, key2}).readPage(ctx.GetStateByRangeStream("key1", "key4", GoodStruct{}))
+	assert.Nil(t, err, "should not error reading next namespaced page")
+	assert.Equal(t, []interface{}{GoodStruct{Prop1: "simple key2"}}, page.Results, "should return page from bookmark")
+
+	stub.bookmarks = nil
+	page, err = (&resultStreamRequest{2, ""}).readPage(ctx.GetStateByRangeStream("key2", "key4", GoodStruct{}))
+	assert.Nil(t, err, "should not error reading last namespaced page")
+	assert.Equal(t, &ResultStreamPage{[]interface{}{GoodStruct{Prop1: "simple key2"}, GoodStruct{Prop1: "simple key3"}}, QueryResponseMetadata{2, ""}}, page, "should return blank bookmark when next page passes end key")
+	assert.Equal(t, []string{key2}, stub.bookmarks, "should start first page from namespaced start key")
+
+	// Should read every key of contract in range when not paged
+	stream := ctx.GetStateByRangeStream("key3", "", GoodStruct{})
+	values := []interface{}{}
+	for stream.HasNext() {
+		value, err := stream.Next()
+		assert.Nil(t, err, "should not error reading namespaced result")
+		values = append(values, value)
+	}
+	assert.Equal(t, []interface{}{GoodStruct{Prop1: "simple key3"}, GoodStruct{Prop1: "simple key4"}}, values, "should read keys of contract from start key")
+	assert.Nil(t, stream.Close(), "should close namespaced iterator")
+}
+
+func TestAsResultStream(t *testing.T) {
+	stub := newPaginationTestStub(map[string]string{"key1": "{\"Prop1\":\"value1\"}", "key2": "{\"Prop1\":\"value2\"}", "key3": "not json"})
+
+	// Should decode values into type of sample
+	stream := (&StateIterator{shimtest.NewMockStateRangeQueryIterator(stub.MockStub, "", "")}).AsResultStream(GoodStruct{})
+	assert.True(t, stream.HasNext(), "should have results")
+	value, err := stream.Next()
+	assert.Nil(t, err, "should not error decoding value")
+	assert.Equal(t, GoodStruct{Prop1: "value1"}, value, "should decode value")
+
+	stream = (&StateIterator{shimtest.NewMockStateRangeQueryIterator(stub.MockStub, "", "")}).AsResultStream(new(GoodStruct))
+	value, _ = stream.Next()
+	assert.Equal(t, &GoodStruct{Prop1: "value1"}, value, "should decode value into pointer")
+
+	// Should error when value cannot be decoded
+	stream.Next()
+	_, err = stream.Next()
+	assert.EqualError(t, err, "Value for key key3 could not be unmarshalled. invalid character 'o' in literal null (expecting 'u')", "should error when value cannot be decoded")
+
+	// Should close iterator
+	assert.Nil(t, stream.Close(), "should close iterator")
+}
+
+func TestResultStreamMetadata(t *testing.T) {
+	cc := convertC2CC(new(resultStreamContract))
+
+	var count TransactionMetadata
+
+	for _, tx := range cc.metadata.Contracts["resultStreamContract"].Transactions {
+		if tx.Name == "Count" {
+			count = tx
+		}
+	}
+
+	// Should add page parameters and pagination
+	assert.Equal(t, []string{"param0", ResultStreamPageSizeParameter, ResultStreamBookmarkParameter}, []string{count.Parameters[0].Name, count.Parameters[1].Name, count.Parameters[2].Name}, "should add page size and bookmark parameters")
+	assert.Equal(t, *spec.Int32Property(), count.Parameters[1].Schema, "should describe page size")
+	assert.True(t, count.Parameters[1].Required, "should require page size")
+	assert.True(t, count.Parameters[2].Required, "should require bookmark")
+	assert.Equal(t, &PaginationMetadata{ResultStreamPageSizeParameter, ResultStreamBookmarkParameter, "results", "metadata"}, count.Pagination, "should add pagination")
+
+	// Should return page
+	assert.Equal(t, "#/components/schemas/ResultStreamPage", count.Returns.Ref.String(), "should return page")
+	assert.Contains(t, cc.metadata.Components.Schemas, "ResultStreamPage", "should add page to components")
+
+	// Should allow result stream as return
+	method, _ := reflect.TypeOf(new(resultStreamContract)).MethodByName("All")
+	returns, err := method2ContractFunctionReturns(method)
+	assert.Nil(t, err, "should not error for result stream return")
+	assert.Equal(t, resultStreamType, returns.success, "should return result stream")
+}
+
+func TestInvokeResultStream(t *testing.T) {
+	cc := convertC2CC(new(resultStreamContract))
+
+	// Should return pages of stream
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Count", "5", "2", ""}, invokeType, `{"results":[0,1],"metadata":{"fetchedRecordsCount":2,"bookmark":"2"}}`)
+	assert.True(t, lastCountingStream.closed, "should close stream")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Count", "5", "2", "4"}, invokeType, `{"results":[4],"metadata":{"fetchedRecordsCount":1,"bookmark":""}}`)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Count", "2", "", ""}, invokeType, `{"results":[0,1],"metadata":{"fetchedRecordsCount":2,"bookmark":""}}`)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"None", "", ""}, invokeType, `{"results":[],"metadata":{"fetchedRecordsCount":0,"bookmark":""}}`)
+
+	// Should not confuse page args with args of optional parameters
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Optional", "3", "a", "1", ""}, invokeType, `{"results":[0],"metadata":{"fetchedRecordsCount":1,"bookmark":"1"}}`)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Optional", "3", "1", "2"}, invokeType, `{"results":[2],"metadata":{"fetchedRecordsCount":1,"bookmark":""}}`)
+
+	// Should return states of iterator
+	stub := shimtest.NewMockStub("resultStreamTest", &cc)
+	stub.MockTransactionStart("setup")
+	stub.PutState("key1", []byte(`{"Prop1":"value1","Prop2":1}`))
+	stub.PutState("key2", []byte(`{"Prop1":"value2","Prop2":2}`))
+	stub.MockTransactionEnd("setup")
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("All"), []byte("1"), []byte("")})
+	assert.Equal(t, `{"results":[{"Prop1":"value1","prop2":1}],"metadata":{"fetchedRecordsCount":1,"bookmark":"1"}}`, string(response.Payload), "should return states of iterator")
+
+	// Should return errors
+	callContractFunctionAndCheckError(t, cc, []string{"Count", "5"}, invokeType, "Missing page size and bookmark. Expected them as the last two args, blank for the first page of the default size")
+	callContractFunctionAndCheckError(t, cc, []string{"Count", "5", "none", ""}, invokeType, "Invalid page size none. Expected an integer from 1 to 1000")
+	callContractFunctionAndCheckError(t, cc, []string{"Count", "5", "2", "6"}, invokeType, "Bookmark is beyond the end of the results")
+	callContractFunctionAndCheckError(t, cc, []string{"Fail", "", ""}, invokeType, "some function error")
+	assert.True(t, lastCountingStream.closed, "should close stream when function errors")
+}
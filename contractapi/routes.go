@@ -0,0 +1,132 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// route holds what Invoke needs to call a function of a contract beyond the
+// function itself, worked out once when the chaincode is created rather than
+// on every call. The schemas of the parameters of the function are compiled
+// when it is first called, as compiling them for every function of a chaincode
+// with many contracts would slow its start.
+type route struct {
+	methodIndex int
+	transaction *TransactionMetadata
+	components  *ComponentMetadata
+	cache       *schemaCache
+	compileOnce sync.Once
+	schemas     []*gojsonschema.Schema
+}
+
+// schemaCache holds the compiled schemas of the parameters of a chaincode keyed
+// by their JSON, so that parameters of the same type in different functions
+// share a compiled schema
+type schemaCache struct {
+	sync.Mutex
+	schemas map[string]*gojsonschema.Schema
+}
+
+// compileRoutes creates the route of each function of each contract keyed by
+// the name of the function within that of its contract. It must be called once
+// the metadata of the chaincode is complete.
+func (cc *ContractChaincode) compileRoutes() {
+	cc.routes = make(map[string]map[string]*route)
+	cc.schemaCache = &schemaCache{schemas: make(map[string]*gojsonschema.Schema)}
+
+	for ns, contract := range cc.contracts {
+		cc.routes[ns] = make(map[string]*route)
+
+		for fn := range contract.functions {
+			cc.routes[ns][fn] = cc.newRoute(ns, fn)
+		}
+	}
+}
+
+// getRoute returns the compiled route of the function, creating it when routes
+// have not been compiled for the chaincode
+func (cc *ContractChaincode) getRoute(ns string, fn string) *route {
+	if r, ok := cc.routes[ns][fn]; ok {
+		return r
+	}
+
+	return cc.newRoute(ns, fn)
+}
+
+func (cc *ContractChaincode) newRoute(ns string, fn string) *route {
+	r := new(route)
+
+	method, _ := cc.contracts[ns].receiver.Type().MethodByName(fn)
+	r.methodIndex = method.Index
+	r.components = &cc.metadata.Components
+	r.cache = cc.schemaCache
+
+	for _, tx := range cc.metadata.Contracts[ns].Transactions {
+		if tx.Name == fn {
+			transaction := tx
+			r.transaction = &transaction
+			break
+		}
+	}
+
+	return r
+}
+
+// getSchemas returns the compiled schemas of the parameters of the function,
+// compiling them on first use. Schemas failing to compile are left nil so that
+// the error is returned when the function is called.
+func (r *route) getSchemas() []*gojsonschema.Schema {
+	r.compileOnce.Do(func() {
+		if r.transaction == nil {
+			return
+		}
+
+		r.schemas = make([]*gojsonschema.Schema, len(r.transaction.Parameters))
+
+		for i, param := range r.transaction.Parameters {
+			r.schemas[i] = r.cache.compile(param, r.components)
+		}
+	})
+
+	return r.schemas
+}
+
+// compile returns the compiled schema of the parameter, reusing that of a
+// parameter with the same schema if compiled before
+func (sc *schemaCache) compile(param ParameterMetadata, components *ComponentMetadata) *gojsonschema.Schema {
+	if sc == nil {
+		schema, _ := compileParameterSchema(param, components)
+		return schema
+	}
+
+	keyBytes, _ := json.Marshal(param.Schema)
+	key := string(keyBytes)
+
+	sc.Lock()
+	defer sc.Unlock()
+
+	if schema, ok := sc.schemas[key]; ok {
+		return schema
+	}
+
+	schema, _ := compileParameterSchema(param, components)
+	sc.schemas[key] = schema
+
+	return schema
+}
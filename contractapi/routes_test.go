@@ -0,0 +1,91 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestCompileRoutes(t *testing.T) {
+	cc := convertC2CC(new(myContract))
+
+	// Should compile route for each function of each contract
+	for ns, contract := range cc.contracts {
+		assert.Len(t, cc.routes[ns], len(contract.functions), "should compile route for each function of "+ns)
+	}
+
+	r := cc.routes["myContract"]["UsesContext"]
+	method, _ := reflect.TypeOf(new(myContract)).MethodByName("UsesContext")
+	assert.Equal(t, method.Index, r.methodIndex, "should use index of method")
+	assert.Equal(t, "UsesContext", r.transaction.Name, "should use metadata of transaction")
+	assert.Nil(t, r.schemas, "should not compile schemas before first use")
+	assert.Len(t, r.getSchemas(), 2, "should compile schema for each parameter")
+	assert.NotNil(t, r.schemas[0], "should compile schema")
+
+	// Should share compiled schemas between parameters with the same schema
+	assert.True(t, r.getSchemas()[0] == r.getSchemas()[1], "should share schema of parameters of same type")
+	assert.True(t, r.getSchemas()[0] == cc.routes["myContract"]["NotUsesContext"].getSchemas()[0], "should share schema between functions")
+}
+
+func TestGetRoute(t *testing.T) {
+	cc := convertC2CC(new(myContract))
+
+	// Should return compiled route
+	assert.True(t, cc.routes["myContract"]["ReturnsString"] == cc.getRoute("myContract", "ReturnsString"), "should return compiled route")
+
+	// Should create route when not compiled
+	cc.routes = nil
+	r := cc.getRoute("myContract", "ReturnsString")
+	assert.Equal(t, "ReturnsString", r.transaction.Name, "should create route")
+	assert.Len(t, r.getSchemas(), 0, "should have no schemas for function without parameters")
+}
+
+func TestNewRoute(t *testing.T) {
+	cc := convertC2CC(new(myContract))
+
+	// Should leave schemas that fail to compile nil
+	contractMetadata := cc.metadata.Contracts["myContract"]
+	for i, tx := range contractMetadata.Transactions {
+		if tx.Name == "UsesContext" {
+			invalid := spec.Schema{}
+			invalid.Ref = spec.MustCreateRef("#/components/schemas/Missing")
+			contractMetadata.Transactions[i].Parameters[0].Schema = invalid
+		}
+	}
+
+	r := cc.newRoute("myContract", "UsesContext")
+	assert.Nil(t, r.getSchemas()[0], "should not compile invalid schema")
+	assert.NotNil(t, r.getSchemas()[1], "should compile valid schema")
+
+	// Should return error for schema that failed to compile when called
+	cc.routes["myContract"]["UsesContext"] = r
+	callContractFunctionAndCheckError(t, cc, []string{"UsesContext", standardAssetID, standardValue}, invokeType, "Invalid schema for parameter \"param0\": Object has no key 'schemas'")
+
+	// Should not have metadata or schemas for unknown transaction
+	assert.Nil(t, cc.newRoute("myContract", "Missing").transaction, "should not have metadata for missing function")
+	assert.Nil(t, cc.newRoute("myContract", "Missing").getSchemas(), "should not have schemas for missing function")
+
+	// Should compile schemas without cache when routes not compiled
+	cc.schemaCache = nil
+	assert.NotNil(t, cc.newRoute("myContract", "UsesContext").getSchemas()[1], "should compile schema without cache")
+}
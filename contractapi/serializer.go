@@ -0,0 +1,144 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+)
+
+// Serializer defines functions for converting the string args of a transaction
+// to the parameter types of contract functions and for converting the values
+// returned by contract functions to the string payload of the response
+type Serializer interface {
+	// FromString converts the passed arg to a value of type t
+	FromString(arg string, t reflect.Type) (reflect.Value, error)
+
+	// ToString converts the passed value to a string. The type t is the
+	// return type declared by the function which may differ from the
+	// type of the value where the function returns an interface
+	ToString(value reflect.Value, t reflect.Type) (string, error)
+}
+
+// JSONSerializer is the default serializer. Basic types are converted using
+// strconv, *big.Int and Decimal from and to their string representation, []byte
+// passed through untouched and arrays, slices, maps and structs from and to JSON.
+type JSONSerializer struct{}
+
+// FromString converts the passed arg to a value of type t. Basic types
+// are parsed from their string representation, other types from JSON.
+func (js *JSONSerializer) FromString(arg string, t reflect.Type) (reflect.Value, error) {
+	if isMarshallingType(t) {
+		return createArraySliceMapOrStruct(arg, t)
+	}
+
+	if isBasicPtrType(t) {
+		converted, err := js.FromString(arg, t.Elem())
+
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("Param %s could not be converted to type %s", arg, t.String())
+		}
+
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(converted.Convert(t.Elem()))
+
+		return ptr, nil
+	}
+
+	bt, ok := specialTypes[t]
+
+	if !ok {
+		bt, ok = basicTypes[t.Kind()]
+	}
+
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("Param %s could not be converted to type %s", arg, t.String())
+	}
+
+	converted, err := bt.convert(arg)
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("Param %s could not be converted to type %s", arg, t.String())
+	}
+
+	return converted, nil
+}
+
+// ToString converts the passed value to a string. Nil values are returned as
+// a blank string, []byte untouched, arrays, slices, maps and structs as JSON and
+// all other types are formatted using fmt.Sprint
+func (js *JSONSerializer) ToString(value reflect.Value, t reflect.Type) (string, error) {
+	return valueToString(value, t, json.Marshal)
+}
+
+// CanonicalJSONSerializer converts args as the JSONSerializer does but returns
+// arrays, slices, maps and structs as canonical JSON (see ledgerapi.MarshalCanonicalJSON)
+// so that return values marshalled with keys in a varying order, e.g. by a
+// json.Marshaler iterating a map, are the same on every endorsing peer
+type CanonicalJSONSerializer struct {
+	JSONSerializer
+}
+
+// ToString converts the passed value to a string as the JSONSerializer does
+// but using canonical JSON
+func (cjs *CanonicalJSONSerializer) ToString(value reflect.Value, t reflect.Type) (string, error) {
+	return valueToString(value, t, ledgerapi.MarshalCanonicalJSON)
+}
+
+func valueToString(value reflect.Value, t reflect.Type, marshal func(interface{}) ([]byte, error)) (string, error) {
+	if isNillableType(value.Kind()) && value.IsNil() {
+		return "", nil
+	}
+
+	if t == bytesReflectType {
+		return string(value.Bytes()), nil
+	}
+
+	if isBasicPtrType(t) {
+		return valueToString(value.Elem(), t.Elem(), marshal)
+	}
+
+	if isMarshallingType(t) || t.Kind() == reflect.Interface && isMarshallingType(value.Type()) {
+		bytes, err := marshal(value.Interface())
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to marshal return value. %s", err.Error())
+		}
+
+		return string(bytes), nil
+	}
+
+	return fmt.Sprint(value.Interface()), nil
+}
+
+var defaultSerializer Serializer = new(JSONSerializer)
+
+func getSerializer(serializer Serializer) Serializer {
+	if serializer == nil {
+		return defaultSerializer
+	}
+
+	return serializer
+}
+
+// SetSerializer sets the serializer used by contracts of the chaincode
+// that do not set their own serializer. If no serializer is set the
+// JSONSerializer is used.
+func (cc *ContractChaincode) SetSerializer(serializer Serializer) {
+	cc.serializer = serializer
+}
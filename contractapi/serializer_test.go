@@ -0,0 +1,224 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type base64Serializer struct {
+	JSONSerializer
+}
+
+func (bs *base64Serializer) FromString(arg string, t reflect.Type) (reflect.Value, error) {
+	decoded, err := base64.StdEncoding.DecodeString(arg)
+
+	if err != nil {
+		return reflect.Value{}, errors.New("Arg was not base64 encoded")
+	}
+
+	return bs.JSONSerializer.FromString(string(decoded), t)
+}
+
+func (bs *base64Serializer) ToString(value reflect.Value, t reflect.Type) (string, error) {
+	str, err := bs.JSONSerializer.ToString(value, t)
+
+	return base64.StdEncoding.EncodeToString([]byte(str)), err
+}
+
+func encode(str string) string {
+	return base64.StdEncoding.EncodeToString([]byte(str))
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestJSONSerializerFromString(t *testing.T) {
+	var value reflect.Value
+	var err error
+
+	js := new(JSONSerializer)
+
+	// Should convert basic types
+	value, err = js.FromString("123", reflect.TypeOf(1))
+	assert.Nil(t, err, "should not error for valid int")
+	assert.Equal(t, 123, value.Interface(), "should convert int")
+
+	// Should error when basic type cannot be converted
+	_, err = js.FromString("abc", reflect.TypeOf(1))
+	assert.EqualError(t, err, "Param abc could not be converted to type int", "should error when arg not an int")
+
+	// Should convert JSON to structs
+	value, err = js.FromString("{\"Prop1\":\"hello\",\"prop2\":1}", reflect.TypeOf(GoodStruct{}))
+	assert.Nil(t, err, "should not error for valid struct")
+	assert.Equal(t, GoodStruct{Prop1: "hello", Prop2: 1}, value.Interface(), "should convert struct")
+
+	// Should error when JSON does not match type
+	_, err = js.FromString("[1,\"a\"]", reflect.TypeOf([]int{}))
+	assert.EqualError(t, err, "Value [1,\"a\"] was not passed in expected format []int", "should error when JSON does not match type")
+
+	// Should error for unsupported types
+	_, err = js.FromString("abc", reflect.TypeOf(make(chan int)))
+	assert.EqualError(t, err, "Param abc could not be converted to type chan int", "should error for unsupported type")
+
+	// Should convert pointers to basic types
+	value, err = js.FromString("abc", reflect.TypeOf(new(string)))
+	assert.Nil(t, err, "should not error for *string")
+	assert.Equal(t, "abc", *(value.Interface().(*string)), "should convert *string")
+
+	value, err = js.FromString("10", reflect.TypeOf(new(int)))
+	assert.Nil(t, err, "should not error for *int")
+	assert.Equal(t, 10, *(value.Interface().(*int)), "should convert *int")
+
+	// Should error when pointer to basic type cannot be converted
+	_, err = js.FromString("abc", reflect.TypeOf(new(int)))
+	assert.EqualError(t, err, "Param abc could not be converted to type *int", "should error for bad *int")
+}
+
+func TestJSONSerializerToString(t *testing.T) {
+	var str string
+	var err error
+
+	js := new(JSONSerializer)
+
+	// Should format basic types
+	str, err = js.ToString(reflect.ValueOf(123), reflect.TypeOf(1))
+	assert.Nil(t, err, "should not error for int")
+	assert.Equal(t, "123", str, "should format int")
+
+	// Should return blank string for nil values
+	str, err = js.ToString(reflect.ValueOf((*GoodStruct)(nil)), reflect.TypeOf(new(GoodStruct)))
+	assert.Nil(t, err, "should not error for nil")
+	assert.Equal(t, "", str, "should return blank string for nil")
+
+	// Should format value pointed to by pointers to basic types
+	someString := "abc"
+	str, err = js.ToString(reflect.ValueOf(&someString), reflect.TypeOf(new(string)))
+	assert.Nil(t, err, "should not error for *string")
+	assert.Equal(t, "abc", str, "should format value pointed to")
+
+	// Should marshal structs
+	str, err = js.ToString(reflect.ValueOf(GoodStruct{Prop1: "hello", Prop2: 1}), reflect.TypeOf(GoodStruct{}))
+	assert.Nil(t, err, "should not error for struct")
+	assert.Equal(t, "{\"Prop1\":\"hello\",\"prop2\":1}", str, "should marshal struct")
+
+	// Should marshal values returned as interfaces
+	var iface interface{} = []string{"a"}
+	str, err = js.ToString(reflect.ValueOf(iface), reflect.TypeOf((*interface{})(nil)).Elem())
+	assert.Nil(t, err, "should not error for interface")
+	assert.Equal(t, "[\"a\"]", str, "should marshal slice returned as interface")
+}
+
+func TestGetSerializerOrDefault(t *testing.T) {
+	bs := new(base64Serializer)
+
+	// Should return default when nil
+	assert.Equal(t, defaultSerializer, getSerializer(nil), "should return default serializer when nil")
+
+	// Should return serializer passed
+	assert.Equal(t, bs, getSerializer(bs), "should return serializer passed when not nil")
+}
+
+func TestSetChaincodeSerializer(t *testing.T) {
+	bs := new(base64Serializer)
+
+	cc := ContractChaincode{}
+	cc.SetSerializer(bs)
+
+	assert.Equal(t, bs, cc.serializer, "should set the serializer")
+}
+
+func TestSerializers(t *testing.T) {
+	mc := myContract{}
+
+	// Should use chaincode serializer when contract has none
+	cc := convertC2CC(&mc)
+	cc.SetSerializer(new(base64Serializer))
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:UsesContext", encode(standardAssetID), encode(standardValue)}, invokeType, encode("You called a function that uses the ctx"))
+
+	// Should use contract serializer over chaincode serializer
+	mc.SetSerializer(new(base64Serializer))
+	cc = convertC2CC(&mc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, encode(mc.ReturnsString()))
+
+	// Should return error when serializer errors
+	callContractFunctionAndCheckError(t, cc, []string{"myContract:UsesContext", standardAssetID, "*"}, invokeType, "Failed to convert arg for parameter 0 (param0) of function UsesContext. Expected type string, received \"ABC123\". Arg was not base64 encoded")
+
+}
+
+type unorderedMarshaler struct{}
+
+func (um unorderedMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{ "b": [1.50, 2E3], "a": "x" }`), nil
+}
+
+func TestCanonicalSerialization(t *testing.T) {
+	var str string
+	var err error
+	var bytes []byte
+
+	compact := `{"b":[1.50,2E3],"a":"x"}`
+	canonical := `{"a":"x","b":[1.5,2000]}`
+	value := reflect.ValueOf(unorderedMarshaler{})
+
+	// Should return JSON as marshalled by default
+	str, err = new(JSONSerializer).ToString(value, value.Type())
+	assert.Nil(t, err, "should not error for custom marshaler")
+	assert.Equal(t, compact, str, "should return JSON as marshalled")
+
+	// Should return canonical JSON for return values when using canonical serializer
+	str, err = new(CanonicalJSONSerializer).ToString(value, value.Type())
+	assert.Nil(t, err, "should not error for custom marshaler")
+	assert.Equal(t, canonical, str, "should return canonical JSON")
+
+	str, err = new(CanonicalJSONSerializer).ToString(reflect.ValueOf(new(int)), reflect.TypeOf(new(int)))
+	assert.Nil(t, err, "should not error for basic pointer")
+	assert.Equal(t, "0", str, "should format basic pointers")
+
+	// Should write JSON as marshalled to world state by default
+	ctx, stub := newStateTestContext()
+	err = ctx.PutStateAs("key", unorderedMarshaler{})
+	assert.Nil(t, err, "should not error for custom marshaler")
+	bytes, _ = stub.MockStub.GetState("key")
+	assert.Equal(t, compact, string(bytes), "should write JSON as marshalled")
+
+	// Should write canonical JSON to world state when enabled
+	ctx.details.canonicalState = true
+	err = ctx.PutStateAs("key", unorderedMarshaler{})
+	assert.Nil(t, err, "should not error for custom marshaler")
+	bytes, _ = stub.MockStub.GetState("key")
+	assert.Equal(t, canonical, string(bytes), "should write canonical JSON")
+
+	// Should write canonical JSON to world state when type registered with canonical codec
+	ledgerapi.RegisterCodec(unorderedMarshaler{}, new(ledgerapi.CanonicalJSONCodec))
+	defer ledgerapi.RegisterCodec(unorderedMarshaler{}, nil)
+
+	ctx, stub = newStateTestContext()
+	err = ctx.PutStateAs("key", unorderedMarshaler{})
+	assert.Nil(t, err, "should not error for custom marshaler")
+	bytes, _ = stub.MockStub.GetState("key")
+	assert.Equal(t, canonical, string(bytes), "should write canonical JSON for registered type")
+}
@@ -0,0 +1,132 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// StateCache memoizes reads of the world state for the remainder of a transaction
+// so that the before, named and after functions of the transaction can each read
+// a key without the key being read from the world state more than once. Writes of
+// a key invalidate its cached value, whether made using the cache or the stub, so
+// that the next read of the key reads the world state again. Keys are namespaced
+// as those of the state helpers when state namespacing is enabled.
+type StateCache struct {
+	stub      shim.ChaincodeStubInterface
+	values    map[string][]byte
+	namespace func(key string) (string, error)
+}
+
+// Get returns the value of the key in the world state, reading it from the world
+// state only the first time the key is read in the transaction
+func (sc *StateCache) Get(key string) ([]byte, error) {
+	namespaced, err := sc.namespaceKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if value, ok := sc.values[namespaced]; ok {
+		return value, nil
+	}
+
+	value, err := sc.stub.GetState(namespaced)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sc.values[namespaced] = value
+
+	return value, nil
+}
+
+// Set writes the value of the key to the world state and invalidates its cached value
+func (sc *StateCache) Set(key string, value []byte) error {
+	namespaced, err := sc.namespaceKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	sc.invalidate(namespaced)
+
+	return sc.stub.PutState(namespaced, value)
+}
+
+// Delete deletes the key from the world state and invalidates its cached value
+func (sc *StateCache) Delete(key string) error {
+	namespaced, err := sc.namespaceKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	sc.invalidate(namespaced)
+
+	return sc.stub.DelState(namespaced)
+}
+
+// Invalidate removes the cached value of the key so that it is read from the
+// world state when next got
+func (sc *StateCache) Invalidate(key string) {
+	if namespaced, err := sc.namespaceKey(key); err == nil {
+		sc.invalidate(namespaced)
+	}
+}
+
+func (sc *StateCache) namespaceKey(key string) (string, error) {
+	if sc.namespace == nil {
+		return key, nil
+	}
+
+	return sc.namespace(key)
+}
+
+// invalidate removes the cached value of the key as stored in the world state
+func (sc *StateCache) invalidate(key string) {
+	delete(sc.values, key)
+}
+
+// Cache returns the state cache of the transaction, creating it on first use. Once
+// the cache is created the stub returned by GetStub invalidates the cached value of
+// keys written using PutState and DelState.
+func (ctx *TransactionContext) Cache() *StateCache {
+	if ctx.cache == nil {
+		ctx.cache = &StateCache{ctx.stub, make(map[string][]byte), ctx.namespaceKey}
+		ctx.stub = &cacheInvalidatingStub{ctx.stub, ctx.cache}
+	}
+
+	return ctx.cache
+}
+
+// cacheInvalidatingStub invalidates the cached value of keys written using the stub
+type cacheInvalidatingStub struct {
+	shim.ChaincodeStubInterface
+	cache *StateCache
+}
+
+func (cis *cacheInvalidatingStub) PutState(key string, value []byte) error {
+	cis.cache.invalidate(key)
+
+	return cis.ChaincodeStubInterface.PutState(key, value)
+}
+
+func (cis *cacheInvalidatingStub) DelState(key string) error {
+	cis.cache.invalidate(key)
+
+	return cis.ChaincodeStubInterface.DelState(key)
+}
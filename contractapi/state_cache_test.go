@@ -0,0 +1,135 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type countingStub struct {
+	*shimtest.MockStub
+	reads int
+	err   error
+}
+
+func (cs *countingStub) GetState(key string) ([]byte, error) {
+	cs.reads++
+
+	if cs.err != nil {
+		return nil, cs.err
+	}
+
+	return cs.MockStub.GetState(key)
+}
+
+func newCountingStub() *countingStub {
+	stub := &countingStub{MockStub: shimtest.NewMockStub("stateCacheTest", nil)}
+	stub.State["key"] = []byte("value")
+	stub.MockTransactionStart("txID")
+
+	return stub
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestStateCacheGet(t *testing.T) {
+	var value []byte
+	var err error
+
+	stub := newCountingStub()
+	sc := &StateCache{stub, make(map[string][]byte), nil}
+
+	// Should read world state on first get
+	value, err = sc.Get("key")
+	assert.Nil(t, err, "should not error when read succeeds")
+	assert.Equal(t, []byte("value"), value, "should return value from world state")
+	assert.Equal(t, 1, stub.reads, "should read world state")
+
+	// Should return cached value on subsequent gets
+	value, _ = sc.Get("key")
+	assert.Equal(t, []byte("value"), value, "should return cached value")
+	assert.Equal(t, 1, stub.reads, "should not read world state again")
+
+	// Should cache missing keys
+	sc.Get("missing")
+	sc.Get("missing")
+	assert.Equal(t, 2, stub.reads, "should read missing key once")
+
+	// Should not cache errors
+	stub.err = errors.New("some error")
+	_, err = sc.Get("other")
+	assert.EqualError(t, err, "some error", "should return read error")
+	_, ok := sc.values["other"]
+	assert.False(t, ok, "should not cache value when read errors")
+}
+
+func TestStateCacheWrites(t *testing.T) {
+	stub := newCountingStub()
+	sc := &StateCache{stub, make(map[string][]byte), nil}
+
+	// Should invalidate on set
+	sc.Get("key")
+	assert.Nil(t, sc.Set("key", []byte("new value")), "should not error on set")
+	assert.Equal(t, []byte("new value"), stub.State["key"], "should write world state")
+	sc.Get("key")
+	assert.Equal(t, 2, stub.reads, "should read world state again after set")
+
+	// Should invalidate on delete
+	assert.Nil(t, sc.Delete("key"), "should not error on delete")
+	assert.Nil(t, stub.State["key"], "should delete from world state")
+	sc.Get("key")
+	assert.Equal(t, 3, stub.reads, "should read world state again after delete")
+
+	// Should invalidate on request
+	sc.Invalidate("key")
+	sc.Get("key")
+	assert.Equal(t, 4, stub.reads, "should read world state again after invalidate")
+}
+
+func TestCache(t *testing.T) {
+	stub := newCountingStub()
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should create cache once
+	cache := ctx.Cache()
+	assert.Same(t, cache, ctx.Cache(), "should return same cache")
+
+	// Should invalidate cache on writes through stub
+	cache.Get("key")
+	ctx.GetStub().PutState("key", []byte("new value"))
+	value, _ := cache.Get("key")
+	assert.Equal(t, []byte("new value"), value, "should read again after PutState")
+
+	cache.Get("key")
+	ctx.GetStub().DelState("key")
+	value, _ = cache.Get("key")
+	assert.Nil(t, value, "should read again after DelState")
+	assert.Equal(t, 3, stub.reads, "should only read when not cached")
+
+	// Should create new cache for new stub
+	ctx.SetStub(stub)
+	assert.False(t, cache == ctx.Cache(), "should create new cache for new stub")
+}
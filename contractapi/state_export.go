@@ -0,0 +1,131 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// ExportTransactionName the name of the system contract transaction used to
+// export the world state when state export is enabled
+const ExportTransactionName = "ExportState"
+
+// ExportFormat identifies the format of the pages returned by the ExportState
+// transaction so that consumers can detect changes to it
+const ExportFormat = "contractapi-export/v1"
+
+const compositeKeyNamespace = "\x00"
+
+// MaxExportBytes the maximum total size of the keys and values in a page of
+// exported state. A page ends early, with a bookmark, rather than exceed it.
+const MaxExportBytes = 1024 * 1024
+
+// ExportedState a key and its value in a page of exported state. The value is
+// base64 encoded in the JSON of the page.
+type ExportedState struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// StateExport a page of exported state. States are in key order. Bookmark is
+// the key to pass to get the next page and is blank for the last page.
+type StateExport struct {
+	Format   string          `json:"format"`
+	States   []ExportedState `json:"states"`
+	Bookmark string          `json:"bookmark"`
+}
+
+// EnableStateExport enables the ExportState transaction of the system contract
+// for backing up the world state or analysing it off-chain in development networks.
+// The transaction takes a key prefix, blank for every key, a page size of at most
+// maxPageSize and optionally the bookmark returned with the previous page, and
+// returns a JSON StateExport of the keys with the prefix. Composite keys are not
+// exported. The transaction should be evaluated rather than submitted.
+func (cc *ContractChaincode) EnableStateExport(maxPageSize int32) {
+	cc.maxExportPageSize = maxPageSize
+}
+
+func (cc *ContractChaincode) exportState(stub shim.ChaincodeStubInterface, params []string) peer.Response {
+	if len(params) != 2 && len(params) != 3 {
+		return shim.Error(fmt.Sprintf("Incorrect number of params. Expected 2 or 3, received %d", len(params)))
+	}
+
+	prefix := params[0]
+
+	pageSize, err := strconv.ParseInt(params[1], 10, 32)
+
+	if err != nil || pageSize < 1 || int32(pageSize) > cc.maxExportPageSize {
+		return shim.Error(fmt.Sprintf("Invalid page size %s. Expected an int between 1 and %d", params[1], cc.maxExportPageSize))
+	}
+
+	startKey := prefix
+	endKey := prefix + string(utf8.MaxRune)
+
+	if len(params) == 3 && params[2] != "" {
+		if !strings.HasPrefix(params[2], prefix) {
+			return shim.Error(fmt.Sprintf("Invalid bookmark %s. Bookmark does not have prefix %s", params[2], prefix))
+		}
+
+		startKey = params[2]
+	}
+
+	iterator, err := stub.GetStateByRange(startKey, endKey)
+
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get states with prefix %s. %s", prefix, err.Error()))
+	}
+
+	defer iterator.Close()
+
+	export := StateExport{Format: ExportFormat, States: []ExportedState{}}
+	size := 0
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get next state. %s", err.Error()))
+		}
+
+		if strings.HasPrefix(kv.Key, compositeKeyNamespace) {
+			continue
+		}
+
+		stateSize := len(kv.Key) + len(kv.Value)
+
+		if len(export.States) == int(pageSize) || (len(export.States) > 0 && size+stateSize > MaxExportBytes) {
+			export.Bookmark = kv.Key
+			break
+		}
+
+		export.States = append(export.States, ExportedState{kv.Key, kv.Value})
+		size += stateSize
+	}
+
+	exportJSON, err := json.Marshal(export)
+
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to marshal exported state. %s", err.Error()))
+	}
+
+	return shim.Success(exportJSON)
+}
@@ -0,0 +1,132 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func newExportTestStub(cc *ContractChaincode, values map[string]string) *shimtest.MockStub {
+	stub := shimtest.NewMockStub("exportTest", cc)
+	stub.MockTransactionStart("setup")
+
+	for key, value := range values {
+		stub.PutState(key, []byte(value))
+	}
+
+	compositeKey, _ := stub.CreateCompositeKey("asset", []string{"composite"})
+	stub.PutState(compositeKey, []byte("composite value"))
+
+	stub.MockTransactionEnd("setup")
+
+	return stub
+}
+
+func invokeExport(stub *shimtest.MockStub, args ...string) peer.Response {
+	bytes := [][]byte{[]byte(SystemContractName + ":" + ExportTransactionName)}
+
+	for _, arg := range args {
+		bytes = append(bytes, []byte(arg))
+	}
+
+	return stub.MockInvoke(standardTxID, bytes)
+}
+
+func parseExport(t *testing.T, response peer.Response) StateExport {
+	t.Helper()
+
+	assert.Equal(t, int32(200), response.Status, "should export successfully")
+
+	export := StateExport{}
+	assert.Nil(t, json.Unmarshal(response.Payload, &export), "should return JSON export")
+
+	return export
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableStateExport(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableStateExport(100)
+
+	assert.Equal(t, int32(100), cc.maxExportPageSize, "should set max page size")
+}
+
+func TestExportState(t *testing.T) {
+	var response peer.Response
+	var export StateExport
+
+	values := map[string]string{"asset1": "value1", "asset2": "value2", "asset3": "value3", "other": "other value"}
+
+	mc := myContract{}
+	cc := convertC2CC(&mc)
+
+	// Should not be callable when not enabled
+	response = invokeExport(newExportTestStub(&cc, values), "asset", "10")
+	assert.Equal(t, "Function ExportState not found in contract org.hyperledger.fabric", response.Message, "should not export when not enabled")
+
+	cc.EnableStateExport(2)
+	stub := newExportTestStub(&cc, values)
+
+	// Should error for invalid params
+	response = invokeExport(stub, "asset")
+	assert.Equal(t, "Incorrect number of params. Expected 2 or 3, received 1", response.Message, "should error for wrong number of params")
+
+	response = invokeExport(stub, "asset", "3")
+	assert.Equal(t, "Invalid page size 3. Expected an int between 1 and 2", response.Message, "should error for page size over max")
+
+	response = invokeExport(stub, "asset", "abc")
+	assert.Equal(t, "Invalid page size abc. Expected an int between 1 and 2", response.Message, "should error for page size not an int")
+
+	response = invokeExport(stub, "asset", "2", "other")
+	assert.Equal(t, "Invalid bookmark other. Bookmark does not have prefix asset", response.Message, "should error for bookmark without prefix")
+
+	// Should export page of keys with prefix
+	response = invokeExport(stub, "asset", "2")
+	export = parseExport(t, response)
+	assert.Equal(t, StateExport{ExportFormat, []ExportedState{{"asset1", []byte("value1")}, {"asset2", []byte("value2")}}, "asset3"}, export, "should export first page")
+	assert.Equal(t, "{\"format\":\""+ExportFormat+"\",\"states\":[{\"key\":\"asset1\",\"value\":\"dmFsdWUx\"},{\"key\":\"asset2\",\"value\":\"dmFsdWUy\"}],\"bookmark\":\"asset3\"}", string(response.Payload), "should export in stable format")
+
+	// Should export next page from bookmark
+	export = parseExport(t, invokeExport(stub, "asset", "2", export.Bookmark))
+	assert.Equal(t, StateExport{ExportFormat, []ExportedState{{"asset3", []byte("value3")}}, ""}, export, "should export last page without bookmark")
+
+	// Should export every simple key for blank prefix
+	export = parseExport(t, invokeExport(stub, "", "2", "asset3"))
+	assert.Equal(t, StateExport{ExportFormat, []ExportedState{{"asset3", []byte("value3")}, {"other", []byte("other value")}}, ""}, export, "should export keys without prefix")
+
+	// Should end page early rather than exceed max bytes
+	large := strings.Repeat("a", MaxExportBytes-10)
+	stub = newExportTestStub(&cc, map[string]string{"asset1": large, "asset2": "value2"})
+	export = parseExport(t, invokeExport(stub, "asset", "2"))
+	assert.Len(t, export.States, 1, "should end page before max bytes exceeded")
+	assert.Equal(t, "asset2", export.Bookmark, "should return bookmark of state not exported")
+
+	// Should export empty page when no keys
+	export = parseExport(t, invokeExport(stub, "missing", "2"))
+	assert.Equal(t, StateExport{ExportFormat, []ExportedState{}, ""}, export, "should export empty page")
+}
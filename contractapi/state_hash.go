@@ -0,0 +1,146 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+)
+
+// HashKeyObjectType is the object type of the composite keys used to store the
+// hashes written by PutStateWithHash
+const HashKeyObjectType = "hash"
+
+// HashState returns the hex encoded SHA-256 hash of the value marshalled to
+// canonical JSON (see ledgerapi.MarshalCanonicalJSON). Values marshalling to the
+// same JSON data have the same hash however their fields are ordered, so that a
+// hash stored in the world state can be checked against a copy of the data held
+// off chain.
+func HashState(v interface{}) (string, error) {
+	bytes, err := ledgerapi.MarshalCanonicalJSON(v)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to hash value. %s", err.Error())
+	}
+
+	hash := sha256.Sum256(bytes)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// PutStateWithHash writes the value to the world state under the key as PutStateAs
+// does and the hash of the value (see HashState) under a composite key of
+// HashKeyObjectType and the key. State triggers registered for the composite key
+// are called after the hash is written. Returns the hash.
+func (ctx *TransactionContext) PutStateWithHash(key string, value interface{}) (string, error) {
+	hash, err := HashState(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := ctx.PutStateAs(key, value); err != nil {
+		return "", err
+	}
+
+	hashKey, err := ctx.GetStub().CreateCompositeKey(HashKeyObjectType, []string{key})
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := ctx.putStateBytes(hashKey, []byte(hash)); err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// GetStateHash returns the hash written for the key by PutStateWithHash. Returns
+// an error wrapping ErrNotFound if no hash has been written for the key.
+func (ctx *TransactionContext) GetStateHash(key string) (string, error) {
+	hashKey, err := ctx.GetStub().CreateCompositeKey(HashKeyObjectType, []string{key})
+
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := ctx.GetStub().GetState(hashKey)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to get hash of key %s. %s", key, err.Error())
+	}
+
+	if hash == nil {
+		return "", &keyNotFoundError{key: key, hash: true}
+	}
+
+	return string(hash), nil
+}
+
+// VerifyStateHash returns whether the hash of the value matches the hash written
+// for the key by PutStateWithHash
+func (ctx *TransactionContext) VerifyStateHash(key string, value interface{}) (bool, error) {
+	stored, err := ctx.GetStateHash(key)
+
+	if err != nil {
+		return false, err
+	}
+
+	hash, err := HashState(value)
+
+	if err != nil {
+		return false, err
+	}
+
+	return hash == stored, nil
+}
+
+// IntegrityContract is a contract for checking copies of data against the hashes
+// written to the world state by PutStateWithHash, e.g. where the data itself is
+// held off chain. Add it to the chaincode alongside the contracts writing the
+// hashes (see NewIntegrityContract).
+type IntegrityContract struct {
+	Contract
+}
+
+// NewIntegrityContract returns an IntegrityContract with its functions tagged to
+// be evaluated
+func NewIntegrityContract() *IntegrityContract {
+	ic := new(IntegrityContract)
+	ic.ConfigureFunction("GetHash").SetEvaluate(true)
+	ic.ConfigureFunction("Verify").SetEvaluate(true)
+
+	return ic
+}
+
+// GetHash returns the hash written for the key
+func (ic *IntegrityContract) GetHash(ctx *TransactionContext, key string) (string, error) {
+	return ctx.GetStateHash(key)
+}
+
+// Verify returns whether the hash of the passed JSON value matches the hash
+// written for the key
+func (ic *IntegrityContract) Verify(ctx *TransactionContext, key string, value string) (bool, error) {
+	if !json.Valid([]byte(value)) {
+		return false, fmt.Errorf("Value for key %s is not valid JSON", key)
+	}
+
+	return ctx.VerifyStateHash(key, json.RawMessage(value))
+}
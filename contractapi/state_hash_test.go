@@ -0,0 +1,177 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type stateHashTestContract struct {
+	Contract
+}
+
+func (shtc *stateHashTestContract) Create(ctx *TransactionContext, key string, value GoodStruct) (string, error) {
+	return ctx.PutStateWithHash(key, value)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestHashState(t *testing.T) {
+	var hash string
+	var err error
+
+	// Should hash canonical JSON
+	hash, err = HashState(map[string]interface{}{"b": 1, "a": "x"})
+	assert.Nil(t, err, "should not error hashing value")
+	assert.Equal(t, "cdab067e9f3beb32d1252cfd63e492592fecbf591b0d08cadb24bb17f3864246", hash, "should return hex sha256 of canonical JSON")
+
+	other, _ := HashState(struct {
+		A string `json:"a"`
+		B int    `json:"b"`
+	}{"x", 1})
+	assert.Equal(t, hash, other, "should hash struct as map with same JSON")
+
+	other, _ = HashState(map[string]interface{}{"b": 2, "a": "x"})
+	assert.NotEqual(t, hash, other, "should hash different values differently")
+
+	// Should error for value that cannot be marshalled
+	_, err = HashState(make(chan int))
+	assert.EqualError(t, err, "Failed to hash value. json: unsupported type: chan int", "should error for value that cannot be marshalled")
+}
+
+func TestPutStateWithHash(t *testing.T) {
+	var hash string
+	var err error
+
+	ctx, stub := newStateTestContext()
+
+	// Should write value and hash
+	hash, err = ctx.PutStateWithHash("key1", GoodStruct{Prop1: "value", Prop2: 1})
+	expectedHash, _ := HashState(GoodStruct{Prop1: "value", Prop2: 1})
+	hashKey, _ := stub.CreateCompositeKey(HashKeyObjectType, []string{"key1"})
+	assert.Nil(t, err, "should not error writing value with hash")
+	assert.Equal(t, expectedHash, hash, "should return hash")
+	assert.Equal(t, []byte(`{"Prop1":"value","prop2":1}`), stub.State["key1"], "should write value")
+	assert.Equal(t, []byte(expectedHash), stub.State[hashKey], "should write hash")
+
+	// Should error when cannot write
+	_, err = ctx.PutStateWithHash("key2", make(chan int))
+	assert.EqualError(t, err, "Failed to hash value. json: unsupported type: chan int", "should error for value that cannot be hashed")
+	assert.Nil(t, stub.State["key2"], "should not write value that cannot be hashed")
+
+	_, err = ctx.PutStateWithHash("\xff", GoodStruct{})
+	assert.NotNil(t, err, "should error for invalid key")
+
+	stub.putErr = errors.New("some error")
+	_, err = ctx.PutStateWithHash("key2", GoodStruct{})
+	assert.EqualError(t, err, "Failed to put key key2. some error", "should error when put errors")
+	stub.putErr = nil
+
+	// Should call state triggers for hash key
+	changes := []StateChange{}
+	ctx.setTransactionDetails(transactionDetails{stateTriggers: []stateTrigger{{"", func(ctx *TransactionContext, change StateChange) error {
+		changes = append(changes, change)
+		return nil
+	}}}})
+
+	hash, _ = ctx.PutStateWithHash("key3", GoodStruct{})
+	hashKey, _ = stub.CreateCompositeKey(HashKeyObjectType, []string{"key3"})
+	assert.Len(t, changes, 2, "should call triggers for value and hash")
+	assert.Equal(t, StateChange{Key: hashKey, Value: []byte(hash)}, changes[1], "should call triggers for hash")
+}
+
+func TestGetAndVerifyStateHash(t *testing.T) {
+	var hash string
+	var verified bool
+	var err error
+
+	ctx, stub := newStateTestContext()
+	written, _ := ctx.PutStateWithHash("key1", GoodStruct{Prop1: "value", Prop2: 1})
+
+	// Should get hash
+	hash, err = ctx.GetStateHash("key1")
+	assert.Nil(t, err, "should not error getting hash")
+	assert.Equal(t, written, hash, "should return written hash")
+
+	// Should verify value against hash
+	verified, err = ctx.VerifyStateHash("key1", GoodStruct{Prop1: "value", Prop2: 1})
+	assert.Nil(t, err, "should not error verifying value")
+	assert.True(t, verified, "should verify matching value")
+
+	verified, err = ctx.VerifyStateHash("key1", map[string]interface{}{"prop2": 1, "Prop1": "value"})
+	assert.Nil(t, err, "should not error verifying map value")
+	assert.True(t, verified, "should verify value with same JSON")
+
+	verified, err = ctx.VerifyStateHash("key1", GoodStruct{Prop1: "value", Prop2: 2})
+	assert.Nil(t, err, "should not error verifying other value")
+	assert.False(t, verified, "should not verify other value")
+
+	// Should error when no hash
+	_, err = ctx.GetStateHash("key2")
+	assert.EqualError(t, err, "Hash of key key2 does not exist in the world state", "should error when no hash")
+	assert.True(t, errors.Is(err, ErrNotFound), "should wrap not found error")
+
+	_, err = ctx.VerifyStateHash("key2", GoodStruct{})
+	assert.True(t, errors.Is(err, ErrNotFound), "should error verifying when no hash")
+
+	_, err = ctx.VerifyStateHash("key1", make(chan int))
+	assert.EqualError(t, err, "Failed to hash value. json: unsupported type: chan int", "should error verifying value that cannot be hashed")
+
+	_, err = ctx.GetStateHash("\xff")
+	assert.NotNil(t, err, "should error for invalid key")
+
+	stub.getErr = errors.New("some error")
+	_, err = ctx.GetStateHash("key1")
+	assert.EqualError(t, err, "Failed to get hash of key key1. some error", "should error when get errors")
+}
+
+func TestIntegrityContract(t *testing.T) {
+	cc := convertC2CC(new(stateHashTestContract), NewIntegrityContract())
+
+	for _, tx := range cc.metadata.Contracts["IntegrityContract"].Transactions {
+		assert.Equal(t, []string{"evaluateTx"}, tx.Tag, "should tag "+tx.Name+" to be evaluated")
+	}
+
+	stub := shimtest.NewMockStub("integrityContractTest", &cc)
+	response := stub.MockInvoke(standardTxID, [][]byte{[]byte("stateHashTestContract:Create"), []byte("key1"), []byte(`{"Prop1":"value","prop2":1}`)})
+	hash := string(response.Payload)
+
+	// Should get hash
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IntegrityContract:GetHash"), []byte("key1")})
+	assert.Equal(t, hash, string(response.Payload), "should return hash")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IntegrityContract:GetHash"), []byte("key2")})
+	assert.Equal(t, int32(404), response.Status, "should return 404 when no hash")
+
+	// Should verify JSON value
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IntegrityContract:Verify"), []byte("key1"), []byte(`{ "prop2": 1, "Prop1": "value" }`)})
+	assert.Equal(t, "true", string(response.Payload), "should verify value with same JSON")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IntegrityContract:Verify"), []byte("key1"), []byte(`{"Prop1":"other","prop2":1}`)})
+	assert.Equal(t, "false", string(response.Payload), "should not verify other value")
+
+	response = stub.MockInvoke(standardTxID, [][]byte{[]byte("IntegrityContract:Verify"), []byte("key1"), []byte(`not json`)})
+	assert.Equal(t, "Value for key key1 is not valid JSON", response.Message, "should error for value not JSON")
+}
@@ -0,0 +1,388 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// EnableStateValidation enables validation of values written using PutStateAs.
+// When enabled values are validated against the schema of their type, using the
+// component schemas of the chaincode's metadata, before they are written so that
+// malformed values are rejected rather than being discovered when later read.
+func (cc *ContractChaincode) EnableStateValidation() {
+	cc.stateValidation = true
+}
+
+// EnableCanonicalState enables writing values stored as JSON by PutStateAs and
+// PutStates as canonical JSON (see ledgerapi.MarshalCanonicalJSON), so that values
+// marshalled with keys in a varying order, e.g. by a json.Marshaler iterating a map,
+// are written as the same bytes by every endorsing peer. Values of types registered
+// with the ledgerapi.CanonicalJSONCodec are written as canonical JSON regardless.
+func (cc *ContractChaincode) EnableCanonicalState() {
+	cc.canonicalState = true
+}
+
+// PinKeys reads each of the passed keys from the world state so that they
+// are included in the read set of the transaction, even though their values
+// are not used. This ensures the transaction is invalidated if any of the keys
+// are modified by another transaction before it is committed, allowing invariants
+// spanning multiple keys to be enforced. Keys pinned are recorded and available
+// via GetPinnedKeys.
+func (ctx *TransactionContext) PinKeys(keys ...string) error {
+	for _, key := range keys {
+		namespaced, err := ctx.namespaceKey(key)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = ctx.GetStub().GetState(namespaced)
+
+		if err != nil {
+			return fmt.Errorf("Failed to pin key %s. %s", key, err.Error())
+		}
+
+		if !stringInSlice(key, ctx.pinnedKeys) {
+			ctx.pinnedKeys = append(ctx.pinnedKeys, key)
+		}
+	}
+
+	return nil
+}
+
+// GetPinnedKeys returns the keys pinned to the read set of the transaction
+func (ctx *TransactionContext) GetPinnedKeys() []string {
+	return ctx.pinnedKeys
+}
+
+// PutStateAs marshals the passed value to JSON, or using the codec registered for
+// its type with ledgerapi.RegisterCodec, and writes it to the world state under the
+// passed key. If the chaincode has state validation enabled then values stored as
+// JSON are validated against the schema for their type before being written.
+// State triggers registered for the key are called after it is written.
+func (ctx *TransactionContext) PutStateAs(key string, value interface{}) error {
+	bytes, err := ctx.marshalState(key, value)
+
+	if err != nil {
+		return err
+	}
+
+	return ctx.putStateBytes(key, bytes)
+}
+
+// GetStateAs reads the passed key from the world state and unmarshals its value
+// into target, using the codec registered for the type of target if any. Returns
+// an error wrapping ErrNotFound if the key does not exist.
+func (ctx *TransactionContext) GetStateAs(key string, target interface{}) error {
+	bytes, err := ctx.getStateBytes(key)
+
+	if err != nil {
+		return err
+	}
+
+	if bytes == nil {
+		return &keyNotFoundError{key: key}
+	}
+
+	return decodeState(key, bytes, target)
+}
+
+// Exists returns whether the passed key exists in the world state
+func (ctx *TransactionContext) Exists(key string) (bool, error) {
+	bytes, err := ctx.getStateBytes(key)
+
+	if err != nil {
+		return false, err
+	}
+
+	return bytes != nil, nil
+}
+
+// Delete deletes the passed key from the world state. Returns an error wrapping
+// ErrNotFound if the key does not exist. State triggers registered for the key
+// are called after it is deleted.
+func (ctx *TransactionContext) Delete(key string) error {
+	exists, err := ctx.Exists(key)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return &keyNotFoundError{key: key}
+	}
+
+	return ctx.delStateBytes(key)
+}
+
+// PutStates writes each of the passed values to the world state under its key as
+// PutStateAs would. Every value is marshalled, and validated if state validation is
+// enabled, before any is written so that none are written if any value is invalid.
+// Values are written in key order so that the write set of the transaction is
+// deterministic.
+func (ctx *TransactionContext) PutStates(values map[string]interface{}) error {
+	keys := []string{}
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	marshalled := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		bytes, err := ctx.marshalState(key, values[key])
+
+		if err != nil {
+			return err
+		}
+
+		marshalled[i] = bytes
+	}
+
+	for i, key := range keys {
+		err := ctx.putStateBytes(key, marshalled[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetStates reads each of the passed keys from the world state and unmarshals its
+// value into a new element of the map pointed to by target, keyed by the key e.g.
+// a *map[string]MyAsset. Keys not in the world state are not added to the map and
+// keys passed more than once are only read once.
+func (ctx *TransactionContext) GetStates(keys []string, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+
+	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Map || targetValue.Elem().Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("Target must be a pointer to a map with string keys. Received %s", reflect.TypeOf(target))
+	}
+
+	mapValue := targetValue.Elem()
+	elemType := mapValue.Type().Elem()
+
+	if mapValue.IsNil() {
+		mapValue.Set(reflect.MakeMap(mapValue.Type()))
+	}
+
+	read := make(map[string]bool)
+
+	for _, key := range keys {
+		if read[key] {
+			continue
+		}
+
+		read[key] = true
+
+		bytes, err := ctx.getStateBytes(key)
+
+		if err != nil {
+			return err
+		}
+
+		if bytes == nil {
+			continue
+		}
+
+		elem, elemTarget := newDecodeTarget(elemType)
+		err = decodeState(key, bytes, elemTarget)
+
+		if err != nil {
+			return err
+		}
+
+		mapValue.SetMapIndex(reflect.ValueOf(key).Convert(mapValue.Type().Key()), elem.Elem())
+	}
+
+	return nil
+}
+
+// marshalState encodes the value using the codec registered for its type with
+// ledgerapi.RegisterCodec, or as JSON if none is registered. Only values stored
+// as JSON are validated as their schemas describe JSON.
+func (ctx *TransactionContext) marshalState(key string, value interface{}) ([]byte, error) {
+	marshal := json.Marshal
+
+	if ctx.details.canonicalState {
+		marshal = ledgerapi.MarshalCanonicalJSON
+	}
+
+	switch codec := ledgerapi.GetRegisteredCodec(value).(type) {
+	case nil, *ledgerapi.JSONCodec:
+	case *ledgerapi.CanonicalJSONCodec:
+		marshal = ledgerapi.MarshalCanonicalJSON
+	default:
+		bytes, err := codec.Encode(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to marshal value for key %s. %s", key, err.Error())
+		}
+
+		return bytes, nil
+	}
+
+	bytes, err := marshal(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal value for key %s. %s", key, err.Error())
+	}
+
+	if ctx.details.stateValidation {
+		err = validateStateValue(bytes, reflect.TypeOf(value), ctx.details.components)
+
+		if err != nil {
+			return nil, fmt.Errorf("Value for key %s did not match schema: %s", key, err.Error())
+		}
+	}
+
+	return bytes, nil
+}
+
+// decodeState decodes the value of the key into target using the codec registered
+// for the type of target with ledgerapi.RegisterCodec, or as JSON if none is registered
+func decodeState(key string, bytes []byte, target interface{}) error {
+	var err error
+
+	if codec := ledgerapi.GetRegisteredCodec(target); codec != nil {
+		err = codec.Decode(bytes, target)
+	} else {
+		err = json.Unmarshal(bytes, target)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Value for key %s could not be unmarshalled. %s", key, err.Error())
+	}
+
+	return nil
+}
+
+// newDecodeTarget returns a new value of the type and the pointer to decode into.
+// The value of pointer types is allocated so that codecs, such as that for protocol
+// buffers, are passed a pointer to the value rather than a pointer to a pointer.
+func newDecodeTarget(typ reflect.Type) (reflect.Value, interface{}) {
+	elem := reflect.New(typ)
+
+	if typ.Kind() == reflect.Ptr {
+		elem.Elem().Set(reflect.New(typ.Elem()))
+
+		return elem, elem.Elem().Interface()
+	}
+
+	return elem, elem.Interface()
+}
+
+func (ctx *TransactionContext) getStateBytes(key string) ([]byte, error) {
+	namespaced, err := ctx.namespaceKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bytes, err := ctx.GetStub().GetState(namespaced)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get key %s. %s", key, err.Error())
+	}
+
+	return bytes, nil
+}
+
+func (ctx *TransactionContext) putStateBytes(key string, bytes []byte) error {
+	namespaced, err := ctx.namespaceKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().PutState(namespaced, bytes)
+
+	if err != nil {
+		return fmt.Errorf("Failed to put key %s. %s", key, err.Error())
+	}
+
+	return ctx.fireStateTriggers(StateChange{Key: key, Value: bytes})
+}
+
+func (ctx *TransactionContext) delStateBytes(key string) error {
+	namespaced, err := ctx.namespaceKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().DelState(namespaced)
+
+	if err != nil {
+		return fmt.Errorf("Failed to delete key %s. %s", key, err.Error())
+	}
+
+	return ctx.fireStateTriggers(StateChange{Key: key, Deleted: true})
+}
+
+func validateStateValue(bytes []byte, typ reflect.Type, registered *ComponentMetadata) error {
+	// copy the registered components so that schemas generated for
+	// unregistered types are not added to the chaincode's metadata
+	components := ComponentMetadata{Schemas: make(map[string]ObjectMetadata)}
+
+	if registered != nil {
+		for name, schema := range registered.Schemas {
+			components.Schemas[name] = schema
+		}
+	}
+
+	schema, err := getSchema(typ, &components)
+
+	if err != nil {
+		return err
+	}
+
+	combined := make(map[string]interface{})
+	combined["components"] = components
+	combined["properties"] = make(map[string]interface{})
+	combined["properties"].(map[string]interface{})["prop"] = schema
+
+	var raw interface{}
+	json.Unmarshal(bytes, &raw)
+
+	toValidate := make(map[string]interface{})
+	toValidate["prop"] = raw
+
+	validator, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(combined))
+
+	if err != nil {
+		return err
+	}
+
+	result, _ := validator.Validate(gojsonschema.NewGoLoader(toValidate))
+
+	if !result.Valid() {
+		return errors.New(validateErrorsToString(result.Errors()))
+	}
+
+	return nil
+}
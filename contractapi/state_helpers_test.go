@@ -0,0 +1,326 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/go-openapi/spec"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestPinKeys(t *testing.T) {
+	ctx, stub := newStateTestContext()
+
+	// Should error when reading key fails
+	stub.getErr = errors.New("some get error")
+	assert.EqualError(t, ctx.PinKeys("key1"), "Failed to pin key key1. some get error", "should return error when get state fails")
+	assert.Nil(t, ctx.GetPinnedKeys(), "should not record keys that failed to pin")
+	stub.getErr = nil
+	stub.reads = nil
+
+	// Should read each key and record it once
+	assert.Nil(t, ctx.PinKeys("key1", "key2", "key1"), "should not error when get state succeeds")
+	assert.Equal(t, []string{"key1", "key2", "key1"}, stub.reads, "should read each key passed")
+	assert.Equal(t, []string{"key1", "key2"}, ctx.GetPinnedKeys(), "should record each pinned key once")
+}
+
+func TestEnableStateValidation(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableStateValidation()
+
+	assert.True(t, cc.stateValidation, "should enable state validation")
+	assert.True(t, cc.getTransactionDetails().stateValidation, "should pass state validation to transaction details")
+}
+
+func TestEnableCanonicalState(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableCanonicalState()
+
+	assert.True(t, cc.canonicalState, "should enable canonical state")
+	assert.True(t, cc.getTransactionDetails().canonicalState, "should pass canonical state to transaction details")
+}
+
+func TestPutStateAs(t *testing.T) {
+	var err error
+	var bytes []byte
+
+	ctx, stub := newStateTestContext()
+
+	components := ComponentMetadata{Schemas: make(map[string]ObjectMetadata)}
+	components.Schemas["GoodStruct"] = ObjectMetadata{
+		Properties: map[string]spec.Schema{
+			"Prop1": *spec.StringProperty().WithPattern("^[a-z]+$"),
+			"prop2": *spec.Int64Property(),
+		},
+		Required:             []string{"Prop1", "prop2"},
+		AdditionalProperties: false,
+	}
+
+	// Should write value as JSON when validation not enabled
+	err = ctx.PutStateAs("key1", GoodStruct{Prop1: "NOT VALID", Prop2: 1})
+	assert.Nil(t, err, "should not error when validation not enabled")
+	bytes, _ = stub.MockStub.GetState("key1")
+	assert.Equal(t, "{\"Prop1\":\"NOT VALID\",\"prop2\":1}", string(bytes), "should write value as JSON")
+
+	// Should write value when it matches registered schema
+	ctx.setTransactionDetails(transactionDetails{stateValidation: true, components: &components})
+	err = ctx.PutStateAs("key2", &GoodStruct{Prop1: "valid", Prop2: 1})
+	assert.Nil(t, err, "should not error when value matches schema")
+	bytes, _ = stub.MockStub.GetState("key2")
+	assert.Equal(t, "{\"Prop1\":\"valid\",\"prop2\":1}", string(bytes), "should write valid value")
+
+	// Should not write value when it does not match registered schema
+	err = ctx.PutStateAs("key3", GoodStruct{Prop1: "NOT VALID", Prop2: 1})
+	assert.Contains(t, err.Error(), "Value for key key3 did not match schema: 1. prop.Prop1: Does not match pattern", "should error when value does not match schema")
+	bytes, _ = stub.MockStub.GetState("key3")
+	assert.Nil(t, bytes, "should not write invalid value")
+
+	// Should validate types not in registered components without adding them
+	err = ctx.PutStateAs("key4", []int{1, 2})
+	assert.Nil(t, err, "should not error for valid slice")
+	err = ctx.PutStateAs("key5", AnotherGoodStruct{StringProp: "a", StructProp: GoodStruct{Prop1: "valid"}})
+	assert.Nil(t, err, "should not error for valid unregistered struct")
+	_, ok := components.Schemas["AnotherGoodStruct"]
+	assert.False(t, ok, "should not add schema to registered components")
+
+	// Should error for types without a schema
+	err = ctx.PutStateAs("key6", new([]string))
+	assert.EqualError(t, err, "Value for key key6 did not match schema: *[]string was not a valid type", "should error for invalid type")
+
+	// Should error when put fails
+	stub.putErr = errors.New("some put error")
+	err = ctx.PutStateAs("key7", 1)
+	assert.EqualError(t, err, "Failed to put key key7. some put error", "should error when put fails")
+
+	// Should error when value cannot be marshalled
+	err = ctx.PutStateAs("key8", make(chan int))
+	assert.EqualError(t, err, "Failed to marshal value for key key8. json: unsupported type: chan int", "should error when marshal fails")
+}
+
+func TestPutStates(t *testing.T) {
+	var err error
+	var bytes []byte
+
+	ctx, stub := newStateTestContext()
+
+	// Should write each value as JSON
+	err = ctx.PutStates(map[string]interface{}{"key1": GoodStruct{Prop1: "a", Prop2: 1}, "key2": []int{1, 2}})
+	assert.Nil(t, err, "should not error when values valid")
+	bytes, _ = stub.MockStub.GetState("key1")
+	assert.Equal(t, "{\"Prop1\":\"a\",\"prop2\":1}", string(bytes), "should write first value as JSON")
+	bytes, _ = stub.MockStub.GetState("key2")
+	assert.Equal(t, "[1,2]", string(bytes), "should write second value as JSON")
+
+	// Should not write any value when one cannot be marshalled
+	err = ctx.PutStates(map[string]interface{}{"key3": 1, "key4": make(chan int)})
+	assert.EqualError(t, err, "Failed to marshal value for key key4. json: unsupported type: chan int", "should error when marshal fails")
+	bytes, _ = stub.MockStub.GetState("key3")
+	assert.Nil(t, bytes, "should not write valid values when another invalid")
+
+	// Should not write any value when one does not match schema
+	ctx.setTransactionDetails(transactionDetails{stateValidation: true})
+	err = ctx.PutStates(map[string]interface{}{"key3": 1, "key4": new([]string)})
+	assert.EqualError(t, err, "Value for key key4 did not match schema: *[]string was not a valid type", "should error when value does not match schema")
+	bytes, _ = stub.MockStub.GetState("key3")
+	assert.Nil(t, bytes, "should not write valid values when another does not match schema")
+
+	// Should error when put fails
+	stub.putErr = errors.New("some put error")
+	err = ctx.PutStates(map[string]interface{}{"key5": 1})
+	assert.EqualError(t, err, "Failed to put key key5. some put error", "should error when put fails")
+}
+
+func TestGetStates(t *testing.T) {
+	var err error
+
+	ctx, stub := newStateTestContext()
+	stub.MockStub.PutState("key1", []byte("{\"Prop1\":\"a\",\"prop2\":1}"))
+	stub.MockStub.PutState("key2", []byte("{\"Prop1\":\"b\",\"prop2\":2}"))
+	stub.MockStub.PutState("bad", []byte("not json"))
+
+	// Should unmarshal each existing key into map
+	values := map[string]GoodStruct{}
+	err = ctx.GetStates([]string{"key1", "key2", "missing", "key1"}, &values)
+	assert.Nil(t, err, "should not error when values valid")
+	assert.Equal(t, map[string]GoodStruct{"key1": {Prop1: "a", Prop2: 1}, "key2": {Prop1: "b", Prop2: 2}}, values, "should unmarshal existing keys")
+	assert.Equal(t, []string{"key1", "key2", "missing"}, stub.reads, "should read each key once")
+
+	// Should create map when nil
+	var nilValues map[string]*GoodStruct
+	err = ctx.GetStates([]string{"key1"}, &nilValues)
+	assert.Nil(t, err, "should not error for nil map")
+	assert.Equal(t, map[string]*GoodStruct{"key1": {Prop1: "a", Prop2: 1}}, nilValues, "should create map")
+
+	// Should error when target not pointer to map
+	err = ctx.GetStates([]string{"key1"}, values)
+	assert.EqualError(t, err, "Target must be a pointer to a map with string keys. Received map[string]contractapi.GoodStruct", "should error when target not pointer")
+	err = ctx.GetStates([]string{"key1"}, &map[int]GoodStruct{})
+	assert.EqualError(t, err, "Target must be a pointer to a map with string keys. Received *map[int]contractapi.GoodStruct", "should error when map keys not strings")
+
+	// Should error when value cannot be unmarshalled
+	err = ctx.GetStates([]string{"bad"}, &values)
+	assert.Contains(t, err.Error(), "Value for key bad could not be unmarshalled.", "should error when unmarshal fails")
+
+	// Should error when get fails
+	stub.getErr = errors.New("some get error")
+	err = ctx.GetStates([]string{"key1"}, &values)
+	assert.EqualError(t, err, "Failed to get key key1. some get error", "should error when get fails")
+}
+
+func TestGetStateAs(t *testing.T) {
+	var err error
+	var value GoodStruct
+
+	ctx, stub := newStateTestContext()
+	stub.MockStub.PutState("key1", []byte("{\"Prop1\":\"a\",\"prop2\":1}"))
+	stub.MockStub.PutState("bad", []byte("not json"))
+
+	// Should unmarshal value of key
+	err = ctx.GetStateAs("key1", &value)
+	assert.Nil(t, err, "should not error when key exists")
+	assert.Equal(t, GoodStruct{Prop1: "a", Prop2: 1}, value, "should unmarshal value")
+
+	// Should return not found error when key does not exist
+	err = ctx.GetStateAs("missing", &value)
+	assert.True(t, errors.Is(err, ErrNotFound), "should return not found error")
+	assert.EqualError(t, err, "Key missing does not exist in the world state", "should include key in error")
+
+	// Should error when value cannot be unmarshalled
+	err = ctx.GetStateAs("bad", &value)
+	assert.Contains(t, err.Error(), "Value for key bad could not be unmarshalled.", "should error when unmarshal fails")
+
+	// Should error when get fails
+	stub.getErr = errors.New("some get error")
+	err = ctx.GetStateAs("key1", &value)
+	assert.EqualError(t, err, "Failed to get key key1. some get error", "should error when get fails")
+}
+
+func TestStateExists(t *testing.T) {
+	var exists bool
+	var err error
+
+	ctx, stub := newStateTestContext()
+	stub.MockStub.PutState("key1", []byte("value"))
+
+	// Should return whether key exists
+	exists, err = ctx.Exists("key1")
+	assert.Nil(t, err, "should not error when get succeeds")
+	assert.True(t, exists, "should return true for existing key")
+
+	exists, _ = ctx.Exists("missing")
+	assert.False(t, exists, "should return false for missing key")
+
+	// Should error when get fails
+	stub.getErr = errors.New("some get error")
+	_, err = ctx.Exists("key1")
+	assert.EqualError(t, err, "Failed to get key key1. some get error", "should error when get fails")
+}
+
+func TestDelete(t *testing.T) {
+	var err error
+
+	ctx, stub := newStateTestContext()
+	stub.MockStub.PutState("key1", []byte("value"))
+	stub.MockStub.PutState("key2", []byte("value"))
+
+	changes := []StateChange{}
+	ctx.setTransactionDetails(transactionDetails{stateTriggers: []stateTrigger{{"key", func(ctx *TransactionContext, change StateChange) error {
+		changes = append(changes, change)
+		return nil
+	}}}})
+
+	// Should delete existing key and fire triggers
+	err = ctx.Delete("key1")
+	assert.Nil(t, err, "should not error when key exists")
+	bytes, _ := stub.MockStub.GetState("key1")
+	assert.Nil(t, bytes, "should delete key")
+	assert.Equal(t, []StateChange{{Key: "key1", Deleted: true}}, changes, "should fire triggers for deletion")
+
+	// Should return not found error when key does not exist
+	err = ctx.Delete("missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "should return not found error")
+
+	// Should error when delete fails
+	stub.delErr = errors.New("some del error")
+	err = ctx.Delete("key2")
+	assert.EqualError(t, err, "Failed to delete key key2. some del error", "should error when delete fails")
+
+	// Should error when get fails
+	stub.getErr = errors.New("some get error")
+	err = ctx.Delete("key2")
+	assert.EqualError(t, err, "Failed to get key key2. some get error", "should error when get fails")
+}
+
+type stateTestCounter string
+
+func TestStateCodecs(t *testing.T) {
+	var err error
+	var bytes []byte
+
+	ledgerapi.RegisterCodec(new(peer.Response), new(ledgerapi.ProtoCodec))
+	ledgerapi.RegisterCodec(stateTestCounter(""), new(ledgerapi.StringCodec))
+	defer ledgerapi.RegisterCodec(new(peer.Response), nil)
+	defer ledgerapi.RegisterCodec(stateTestCounter(""), nil)
+
+	ctx, stub := newStateTestContext()
+	ctx.setTransactionDetails(transactionDetails{stateValidation: true})
+	response := &peer.Response{Status: 200, Message: "some message"}
+
+	// Should write values using registered codecs without validating them
+	err = ctx.PutStateAs("response", response)
+	assert.Nil(t, err, "should not error writing proto message")
+	bytes, _ = stub.MockStub.GetState("response")
+	expected, _ := proto.Marshal(response)
+	assert.Equal(t, expected, bytes, "should write using proto codec")
+
+	err = ctx.PutStates(map[string]interface{}{"counter": stateTestCounter("10"), "other": 1})
+	assert.Nil(t, err, "should not error writing string type")
+	bytes, _ = stub.MockStub.GetState("counter")
+	assert.Equal(t, "10", string(bytes), "should write using string codec")
+	bytes, _ = stub.MockStub.GetState("other")
+	assert.Equal(t, "1", string(bytes), "should write types without codec as JSON")
+
+	err = ctx.PutStateAs("bad", stateTestCounter("10"))
+	assert.Nil(t, err, "should not validate values of types with codec")
+
+	// Should read values using registered codecs
+	readResponse := new(peer.Response)
+	err = ctx.GetStateAs("response", readResponse)
+	assert.Nil(t, err, "should not error reading proto message")
+	assert.True(t, proto.Equal(response, readResponse), "should read using proto codec")
+
+	responses := map[string]*peer.Response{}
+	err = ctx.GetStates([]string{"response"}, &responses)
+	assert.Nil(t, err, "should not error reading pointer types")
+	assert.True(t, proto.Equal(response, responses["response"]), "should read pointer types using proto codec")
+
+	counters := map[string]stateTestCounter{}
+	err = ctx.GetStates([]string{"counter"}, &counters)
+	assert.Nil(t, err, "should not error reading string types")
+	assert.Equal(t, map[string]stateTestCounter{"counter": "10"}, counters, "should read using string codec")
+
+	// Should error when codec fails
+	err = ctx.GetStateAs("counter", readResponse)
+	assert.Contains(t, err.Error(), "Value for key counter could not be unmarshalled.", "should error when codec cannot decode")
+}
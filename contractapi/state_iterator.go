@@ -0,0 +1,268 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+)
+
+// QueryResponseMetadata details about a page of results returned by
+// a paginated query
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32  `json:"fetchedRecordsCount"`
+	Bookmark            string `json:"bookmark"`
+}
+
+// StateIterator wraps the iterator returned by the stub for state queries
+// providing access to the values of results as typed structs
+type StateIterator struct {
+	iterator shim.StateQueryIteratorInterface
+}
+
+// HasNext returns whether the iterator contains further results
+func (si *StateIterator) HasNext() bool {
+	return si.iterator.HasNext()
+}
+
+// Next returns the key and raw value of the next result
+func (si *StateIterator) Next() (string, []byte, error) {
+	kv, err := si.iterator.Next()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kv.Key, kv.Value, nil
+}
+
+// NextAs unmarshals the value of the next result into target, as GetStateAs
+// would, and returns the key of the result
+func (si *StateIterator) NextAs(target interface{}) (string, error) {
+	key, value, err := si.Next()
+
+	if err != nil {
+		return "", err
+	}
+
+	err = decodeState(key, value, target)
+
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// Close closes the underlying iterator. This must be called once the
+// iterator is no longer required
+func (si *StateIterator) Close() error {
+	return si.iterator.Close()
+}
+
+// GetQueryResultWithPagination performs a rich query against the world state
+// returning at most pageSize results starting from the passed bookmark. An empty
+// bookmark returns the first page. The returned iterator must be closed.
+func (ctx *TransactionContext) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (*StateIterator, *QueryResponseMetadata, error) {
+	iterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(query, pageSize, bookmark)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseMetadata := new(QueryResponseMetadata)
+
+	if metadata != nil {
+		responseMetadata.FetchedRecordsCount = metadata.FetchedRecordsCount
+		responseMetadata.Bookmark = metadata.Bookmark
+	}
+
+	return &StateIterator{iterator}, responseMetadata, nil
+}
+
+// GetQueryResultPage performs a paginated rich query against the world state and
+// unmarshals each result into a new element of the slice pointed to by target e.g.
+// a *[]MyAsset. Returns the metadata of the page, the bookmark of which can be used
+// to request the next page. Transactions returning the results in a struct alongside
+// the metadata are marked as paginated in the metadata of the chaincode.
+func (ctx *TransactionContext) GetQueryResultPage(query string, pageSize int32, bookmark string, target interface{}) (*QueryResponseMetadata, error) {
+	iterator, metadata, err := ctx.GetQueryResultWithPagination(query, pageSize, bookmark)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = iterator.unmarshalAll(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func (si *StateIterator) unmarshalAll(target interface{}) error {
+	defer si.Close()
+
+	targetValue := reflect.ValueOf(target)
+
+	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Target must be a pointer to a slice. Received %s", reflect.TypeOf(target))
+	}
+
+	sliceValue := targetValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	for si.HasNext() {
+		elem, elemTarget := newDecodeTarget(elemType)
+
+		_, err := si.NextAs(elemTarget)
+
+		if err != nil {
+			return err
+		}
+
+		sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+	}
+
+	return nil
+}
+
+// ForEachState calls fn, in key order, for each key in the world state from
+// startKey, inclusive, to endKey, exclusive. fn is passed the key and a function
+// that unmarshals the value of the key into a target as GetStateAs would. The first
+// error returned by fn stops the iteration and is returned. The iterator is closed
+// however the iteration ends so callers need not manage it. When state namespacing
+// is enabled the keys of the contract in the range are iterated, unless the start
+// key is shared (see EnableStateNamespacing).
+func (ctx *TransactionContext) ForEachState(startKey string, endKey string, fn func(key string, decode func(interface{}) error) error) error {
+	iterator, err := ctx.getStateByRange(startKey, endKey)
+
+	if err != nil {
+		return fmt.Errorf("Failed to get states in range %s to %s. %s", startKey, endKey, err.Error())
+	}
+
+	defer iterator.Close()
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return err
+		}
+
+		key := kv.Key
+
+		err = fn(key, func(target interface{}) error {
+			return decodeState(key, kv.Value, target)
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getStateByRange returns an iterator of the keys in the range. When state
+// namespacing is enabled, unless the start key is shared, the keys of the contract
+// in the range are iterated and returned without their namespace.
+func (ctx *TransactionContext) getStateByRange(startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	if ctx.isSharedState(startKey) {
+		return ctx.GetStub().GetStateByRange(startKey, endKey)
+	}
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(ctx.details.contractName, []string{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &namespacedRangeIterator{StateQueryIteratorInterface: iterator, ctx: ctx, startKey: startKey, endKey: endKey}, nil
+}
+
+// namespacedRangeIterator iterates the keys of the namespace of a contract from the
+// start key, inclusive, to the end key, exclusive, returning them without their
+// namespace. Namespaced keys are ordered as the keys they namespace.
+type namespacedRangeIterator struct {
+	shim.StateQueryIteratorInterface
+	ctx      *TransactionContext
+	startKey string
+	endKey   string
+	next     *queryresult.KV
+	err      error
+	ended    bool
+}
+
+// advance reads the next key in range, if not already read
+func (nri *namespacedRangeIterator) advance() {
+	for nri.next == nil && nri.err == nil && !nri.ended && nri.StateQueryIteratorInterface.HasNext() {
+		kv, err := nri.StateQueryIteratorInterface.Next()
+
+		if err != nil {
+			nri.err = err
+			return
+		}
+
+		_, attributes, err := nri.ctx.GetStub().SplitCompositeKey(kv.Key)
+
+		if err != nil {
+			nri.err = err
+			return
+		}
+
+		key := attributes[0]
+
+		if key < nri.startKey {
+			continue
+		} else if nri.endKey != "" && key >= nri.endKey {
+			nri.ended = true
+			return
+		}
+
+		nri.next = &queryresult.KV{Namespace: kv.Namespace, Key: key, Value: kv.Value}
+	}
+}
+
+func (nri *namespacedRangeIterator) HasNext() bool {
+	nri.advance()
+
+	return nri.next != nil || nri.err != nil
+}
+
+func (nri *namespacedRangeIterator) Next() (*queryresult.KV, error) {
+	nri.advance()
+
+	if nri.err != nil {
+		err := nri.err
+		nri.err = nil
+
+		return nil, err
+	}
+
+	if nri.next == nil {
+		return nil, errors.New("No more results in range")
+	}
+
+	kv := nri.next
+	nri.next = nil
+
+	return kv, nil
+}
@@ -0,0 +1,218 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type paginationTestStub struct {
+	*shimtest.MockStub
+	shouldError bool
+	query       string
+	pageSize    int32
+	bookmark    string
+}
+
+func (pts *paginationTestStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	if pts.shouldError {
+		return nil, nil, errors.New("some query error")
+	}
+
+	pts.query = query
+	pts.pageSize = pageSize
+	pts.bookmark = bookmark
+
+	return shimtest.NewMockStateRangeQueryIterator(pts.MockStub, "", ""), &peer.QueryResponseMetadata{FetchedRecordsCount: int32(pts.Keys.Len()), Bookmark: "next bookmark"}, nil
+}
+
+func newPaginationTestStub(state map[string]string) *paginationTestStub {
+	stub := shimtest.NewMockStub("paginationTest", nil)
+
+	stub.MockTransactionStart(standardTxID)
+	for key, value := range state {
+		stub.PutState(key, []byte(value))
+	}
+	stub.MockTransactionEnd(standardTxID)
+
+	return &paginationTestStub{MockStub: stub}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestStateIterator(t *testing.T) {
+	var key string
+	var err error
+
+	stub := newPaginationTestStub(map[string]string{"key1": "{\"Prop1\":\"some value\"}", "key2": "not json"})
+	si := StateIterator{shimtest.NewMockStateRangeQueryIterator(stub.MockStub, "", "")}
+
+	// Should return whether more results exist and return the raw next result
+	assert.True(t, si.HasNext(), "should have more results")
+	key, value, err := si.Next()
+	assert.Nil(t, err, "should not error for valid next")
+	assert.Equal(t, "key1", key, "should return next key")
+	assert.Equal(t, []byte("{\"Prop1\":\"some value\"}"), value, "should return next value")
+
+	// Should error when value does not unmarshal
+	_, err = si.NextAs(new(GoodStruct))
+	assert.EqualError(t, err, "Value for key key2 could not be unmarshalled. invalid character 'o' in literal null (expecting 'u')", "should error when value is not valid JSON")
+
+	// Should error when next errors
+	assert.False(t, si.HasNext(), "should have no more results")
+	_, err = si.NextAs(new(GoodStruct))
+	assert.EqualError(t, err, "MockStateRangeQueryIterator.Next() called when it does not HaveNext()", "should return error from iterator")
+
+	// Should close underlying iterator
+	assert.Nil(t, si.Close(), "should close iterator")
+
+	// Should unmarshal value of next into passed struct
+	si = StateIterator{shimtest.NewMockStateRangeQueryIterator(stub.MockStub, "", "")}
+	gs := new(GoodStruct)
+	key, err = si.NextAs(gs)
+	assert.Nil(t, err, "should not error for valid next")
+	assert.Equal(t, "key1", key, "should return key of unmarshalled result")
+	assert.Equal(t, "some value", gs.Prop1, "should unmarshal into struct")
+}
+
+func TestGetQueryResultWithPagination(t *testing.T) {
+	stub := newPaginationTestStub(map[string]string{"key1": "{}"})
+	ctx := TransactionContext{}
+	ctx.SetStub(stub)
+
+	// Should return error from stub
+	stub.shouldError = true
+	_, _, err := ctx.GetQueryResultWithPagination("some query", 10, "")
+	assert.EqualError(t, err, "some query error", "should return error from stub")
+	stub.shouldError = false
+
+	// Should return iterator and metadata
+	iterator, metadata, err := ctx.GetQueryResultWithPagination("some query", 10, "some bookmark")
+	assert.Nil(t, err, "should not error when stub does not")
+	assert.Equal(t, "some query", stub.query, "should pass query to stub")
+	assert.Equal(t, int32(10), stub.pageSize, "should pass page size to stub")
+	assert.Equal(t, "some bookmark", stub.bookmark, "should pass bookmark to stub")
+	assert.Equal(t, &QueryResponseMetadata{1, "next bookmark"}, metadata, "should convert metadata")
+	assert.True(t, iterator.HasNext(), "should return iterator of results")
+}
+
+func TestGetQueryResultPage(t *testing.T) {
+	var err error
+
+	stub := newPaginationTestStub(map[string]string{"key1": "{\"Prop1\":\"value1\"}", "key2": "{\"Prop1\":\"value2\"}"})
+	ctx := TransactionContext{}
+	ctx.SetStub(stub)
+
+	// Should return error from stub
+	stub.shouldError = true
+	_, err = ctx.GetQueryResultPage("some query", 10, "", new([]GoodStruct))
+	assert.EqualError(t, err, "some query error", "should return error from stub")
+	stub.shouldError = false
+
+	// Should error when target is not a pointer to a slice
+	_, err = ctx.GetQueryResultPage("some query", 10, "", []GoodStruct{})
+	assert.EqualError(t, err, "Target must be a pointer to a slice. Received []contractapi.GoodStruct", "should error when target is not slice pointer")
+
+	// Should error when result does not unmarshal
+	_, err = ctx.GetQueryResultPage("some query", 10, "", new([]int))
+	assert.Contains(t, err.Error(), "Value for key key1 could not be unmarshalled.", "should error when result cannot be unmarshalled into target")
+
+	// Should fill target with results
+	results := []GoodStruct{}
+	metadata, err := ctx.GetQueryResultPage("some query", 10, "", &results)
+	assert.Nil(t, err, "should not error for valid results")
+	assert.Equal(t, []GoodStruct{{Prop1: "value1"}, {Prop1: "value2"}}, results, "should unmarshal each result")
+	assert.Equal(t, "next bookmark", metadata.Bookmark, "should return page metadata")
+}
+
+type closeTrackingIterator struct {
+	shim.StateQueryIteratorInterface
+	closed bool
+}
+
+func (cti *closeTrackingIterator) Close() error {
+	cti.closed = true
+	return cti.StateQueryIteratorInterface.Close()
+}
+
+type rangeTestStub struct {
+	*paginationTestStub
+	iterator *closeTrackingIterator
+}
+
+func (rts *rangeTestStub) GetStateByRange(startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+	if rts.shouldError {
+		return nil, errors.New("some range error")
+	}
+
+	rts.iterator = &closeTrackingIterator{StateQueryIteratorInterface: shimtest.NewMockStateRangeQueryIterator(rts.MockStub, startKey, endKey)}
+
+	return rts.iterator, nil
+}
+
+func TestForEachState(t *testing.T) {
+	var err error
+
+	stub := &rangeTestStub{paginationTestStub: newPaginationTestStub(map[string]string{"key1": "{\"Prop1\":\"a\"}", "key2": "{\"Prop1\":\"b\"}", "key3": "not json", "other": "{}"})}
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+
+	// Should call function for each key in range and close iterator
+	values := []string{}
+	err = ctx.ForEachState("key1", "key3", func(key string, decode func(interface{}) error) error {
+		gs := new(GoodStruct)
+		err := decode(gs)
+		values = append(values, key+"="+gs.Prop1)
+		return err
+	})
+	assert.Nil(t, err, "should not error when function does not")
+	assert.Equal(t, []string{"key1=a", "key2=b"}, values, "should call function for each key in range in order")
+	assert.True(t, stub.iterator.closed, "should close iterator")
+
+	// Should stop at and return error of function and close iterator
+	calls := 0
+	err = ctx.ForEachState("key1", "key3", func(key string, decode func(interface{}) error) error {
+		calls++
+		return errors.New("some function error")
+	})
+	assert.EqualError(t, err, "some function error", "should return error of function")
+	assert.Equal(t, 1, calls, "should stop iterating after error")
+	assert.True(t, stub.iterator.closed, "should close iterator when function errors")
+
+	// Should return decode errors
+	err = ctx.ForEachState("key3", "key4", func(key string, decode func(interface{}) error) error {
+		return decode(new(GoodStruct))
+	})
+	assert.Contains(t, err.Error(), "Value for key key3 could not be unmarshalled.", "should return decode error")
+
+	// Should error when range cannot be read
+	stub.shouldError = true
+	err = ctx.ForEachState("key1", "key3", func(key string, decode func(interface{}) error) error {
+		return nil
+	})
+	assert.EqualError(t, err, "Failed to get states in range key1 to key3. some range error", "should error when range fails")
+}
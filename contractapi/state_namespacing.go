@@ -0,0 +1,97 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnableStateNamespacing namespaces the keys read and written by the state helpers
+// of the transaction context (PutStateAs, GetStateAs, Exists, Delete, PutStates,
+// GetStates, PinKeys, ForEachState, GetStateByRangeStream and the state cache) by
+// the name of the contract being invoked, so that contracts of the same chaincode
+// using the same keys do not clash. Keys are stored as composite keys with the
+// contract name as the object type, and the object types of keys created using
+// CreateKey, and queried using GetStatesByPartialKey, are prefixed with the contract
+// name. Keys and object types starting with any of the passed shared prefixes are
+// not namespaced so that they can be shared between contracts. ForEachState and
+// GetStateByRangeStream iterate the keys of the contract unless their start key is
+// shared. Values written directly using the stub are not namespaced, and as
+// namespaced keys are composite keys they are not returned by GetStateByRange of the
+// stub. Rich queries, including those of GetQueryResultStream, are not namespaced
+// and return the keys of all contracts as stored.
+func (cc *ContractChaincode) EnableStateNamespacing(sharedPrefixes ...string) {
+	cc.stateNamespacing = true
+	cc.sharedStatePrefixes = sharedPrefixes
+}
+
+// isSharedState returns whether the key or object type is written as is, either
+// as namespacing is not enabled or the key is shared
+func (ctx *TransactionContext) isSharedState(key string) bool {
+	if !ctx.details.stateNamespacing || ctx.details.contractName == "" {
+		return true
+	}
+
+	for _, prefix := range ctx.details.sharedStatePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namespaceKey returns the key under which the passed key is stored in the world
+// state. Composite keys are already namespaced by their object type.
+func (ctx *TransactionContext) namespaceKey(key string) (string, error) {
+	if ctx.isSharedState(key) || strings.HasPrefix(key, compositeKeyNamespace) {
+		return key, nil
+	}
+
+	namespaced, err := ctx.GetStub().CreateCompositeKey(ctx.details.contractName, []string{key})
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to namespace key %s. %s", key, err.Error())
+	}
+
+	return namespaced, nil
+}
+
+// namespaceObjectType returns the object type used for composite keys created
+// with the passed object type
+func (ctx *TransactionContext) namespaceObjectType(objectType string) string {
+	if ctx.isSharedState(objectType) {
+		return objectType
+	}
+
+	return ctx.details.contractName + "." + objectType
+}
+
+// unnamespaceObjectType returns the object type as passed to CreateKey from the
+// object type of a composite key
+func (ctx *TransactionContext) unnamespaceObjectType(objectType string) string {
+	if !ctx.details.stateNamespacing || ctx.details.contractName == "" {
+		return objectType
+	}
+
+	trimmed := strings.TrimPrefix(objectType, ctx.details.contractName+".")
+
+	if trimmed == objectType || ctx.isSharedState(trimmed) {
+		return objectType
+	}
+
+	return trimmed
+}
@@ -0,0 +1,285 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+func newNamespacedTestContext(stub *stateErrorTestStub, contractName string, sharedPrefixes ...string) *TransactionContext {
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	ctx.details.contractName = contractName
+	ctx.details.stateNamespacing = true
+	ctx.details.sharedStatePrefixes = sharedPrefixes
+
+	return ctx
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableStateNamespacing(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableStateNamespacing("shared.", "config")
+
+	assert.True(t, cc.stateNamespacing, "should enable state namespacing")
+	assert.Equal(t, []string{"shared.", "config"}, cc.sharedStatePrefixes, "should set shared prefixes")
+	assert.True(t, cc.getTransactionDetails().stateNamespacing, "should pass state namespacing to transaction details")
+	assert.Equal(t, []string{"shared.", "config"}, cc.getTransactionDetails().sharedStatePrefixes, "should pass shared prefixes to transaction details")
+}
+
+func TestNamespaceKey(t *testing.T) {
+	var key string
+	var err error
+
+	_, stub := newStateTestContext()
+
+	// Should not namespace when namespacing not enabled
+	ctx := new(TransactionContext)
+	ctx.SetStub(stub)
+	ctx.details.contractName = "simple"
+	key, err = ctx.namespaceKey("asset1")
+	assert.Nil(t, err, "should not error when namespacing not enabled")
+	assert.Equal(t, "asset1", key, "should not namespace when not enabled")
+
+	// Should not namespace when no contract being invoked
+	ctx = newNamespacedTestContext(stub, "")
+	key, _ = ctx.namespaceKey("asset1")
+	assert.Equal(t, "asset1", key, "should not namespace without contract")
+
+	// Should namespace key using contract name
+	ctx = newNamespacedTestContext(stub, "simple", "config")
+	key, err = ctx.namespaceKey("asset1")
+	expected, _ := stub.CreateCompositeKey("simple", []string{"asset1"})
+	assert.Nil(t, err, "should not error for valid key")
+	assert.Equal(t, expected, key, "should namespace key as composite key of contract name")
+
+	// Should not namespace shared keys
+	key, _ = ctx.namespaceKey("config.owner")
+	assert.Equal(t, "config.owner", key, "should not namespace shared key")
+
+	// Should not namespace composite keys
+	composite, _ := stub.CreateCompositeKey("asset", []string{"1"})
+	key, _ = ctx.namespaceKey(composite)
+	assert.Equal(t, composite, key, "should not namespace composite key")
+
+	// Should error when key cannot be namespaced
+	_, err = ctx.namespaceKey("asset\U0010FFFF")
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error for key not valid in composite key")
+}
+
+func TestStateHelpersNamespaced(t *testing.T) {
+	var err error
+	var exists bool
+
+	_, stub := newStateTestContext()
+	simpleCtx := newNamespacedTestContext(stub, "simple", "shared")
+	complexCtx := newNamespacedTestContext(stub, "complex", "shared")
+
+	simpleKey, _ := stub.CreateCompositeKey("simple", []string{"asset1"})
+	complexKey, _ := stub.CreateCompositeKey("complex", []string{"asset1"})
+
+	// Should write same key separately for each contract
+	assert.Nil(t, simpleCtx.PutStateAs("asset1", GoodStruct{Prop1: "simple"}), "should put namespaced key")
+	assert.Nil(t, complexCtx.PutStates(map[string]interface{}{"asset1": GoodStruct{Prop1: "complex"}}), "should put namespaced keys")
+	bytes, _ := stub.GetState(simpleKey)
+	assert.Equal(t, "{\"Prop1\":\"simple\",\"prop2\":0}", string(bytes), "should write key in namespace of simple contract")
+	bytes, _ = stub.GetState(complexKey)
+	assert.Equal(t, "{\"Prop1\":\"complex\",\"prop2\":0}", string(bytes), "should write key in namespace of complex contract")
+	bytes, _ = stub.GetState("asset1")
+	assert.Nil(t, bytes, "should not write key unnamespaced")
+
+	// Should read key from namespace of contract
+	gs := GoodStruct{}
+	assert.Nil(t, simpleCtx.GetStateAs("asset1", &gs), "should get namespaced key")
+	assert.Equal(t, "simple", gs.Prop1, "should read key from namespace of contract")
+
+	states := map[string]GoodStruct{}
+	assert.Nil(t, complexCtx.GetStates([]string{"asset1"}, &states), "should get namespaced keys")
+	assert.Equal(t, map[string]GoodStruct{"asset1": {Prop1: "complex"}}, states, "should key values by key as passed")
+
+	exists, err = simpleCtx.Exists("asset2")
+	assert.Nil(t, err, "should not error checking namespaced key")
+	assert.False(t, exists, "should not find key missing from namespace")
+
+	// Should pin namespaced keys but record them as passed
+	stub.reads = nil
+	assert.Nil(t, simpleCtx.PinKeys("asset1"), "should pin namespaced key")
+	assert.Equal(t, []string{simpleKey}, stub.reads, "should read namespaced key")
+	assert.Equal(t, []string{"asset1"}, simpleCtx.GetPinnedKeys(), "should record key as passed")
+
+	// Should delete key from namespace of contract only
+	assert.Nil(t, simpleCtx.Delete("asset1"), "should delete namespaced key")
+	exists, _ = simpleCtx.Exists("asset1")
+	assert.False(t, exists, "should delete key from namespace of contract")
+	exists, _ = complexCtx.Exists("asset1")
+	assert.True(t, exists, "should not delete key from namespace of other contract")
+
+	// Should share keys with shared prefix
+	assert.Nil(t, simpleCtx.PutStateAs("sharedConfig", GoodStruct{Prop1: "shared"}), "should put shared key")
+	gs = GoodStruct{}
+	assert.Nil(t, complexCtx.GetStateAs("sharedConfig", &gs), "should get shared key")
+	assert.Equal(t, "shared", gs.Prop1, "should read shared key written by other contract")
+	bytes, _ = stub.GetState("sharedConfig")
+	assert.NotNil(t, bytes, "should write shared key as is")
+
+	// Should error when key cannot be namespaced
+	err = simpleCtx.PutStateAs("asset\U0010FFFF", GoodStruct{})
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error putting key that cannot be namespaced")
+	err = simpleCtx.GetStateAs("asset\U0010FFFF", &gs)
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error getting key that cannot be namespaced")
+	err = simpleCtx.PinKeys("asset\U0010FFFF")
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error pinning key that cannot be namespaced")
+}
+
+func TestStateTriggersNamespaced(t *testing.T) {
+	_, stub := newStateTestContext()
+	ctx := newNamespacedTestContext(stub, "simple")
+
+	changes := []StateChange{}
+	ctx.details.stateTriggers = []stateTrigger{{"asset", func(ctx *TransactionContext, change StateChange) error {
+		changes = append(changes, change)
+		return nil
+	}}}
+
+	// Should pass key as passed to helper to triggers
+	assert.Nil(t, ctx.PutStateAs("asset1", GoodStruct{}), "should put namespaced key")
+	assert.Nil(t, ctx.Delete("asset1"), "should delete namespaced key")
+	assert.Equal(t, []StateChange{{Key: "asset1", Value: []byte("{\"Prop1\":\"\",\"prop2\":0}")}, {Key: "asset1", Deleted: true}}, changes, "should fire triggers with key as passed")
+}
+
+func TestCompositeKeysNamespaced(t *testing.T) {
+	var err error
+
+	_, stub := newStateTestContext()
+	simpleCtx := newNamespacedTestContext(stub, "simple", "shared")
+	complexCtx := newNamespacedTestContext(stub, "complex", "shared")
+
+	// Should prefix object type with contract name
+	key, err := simpleCtx.CreateKey("asset", "alice")
+	expected, _ := stub.CreateCompositeKey("simple.asset", []string{"alice"})
+	assert.Nil(t, err, "should not error for valid key")
+	assert.Equal(t, expected, key, "should prefix object type with contract name")
+
+	// Should return object type as passed when splitting key
+	objectType, attrs, _ := simpleCtx.SplitKey(key)
+	assert.Equal(t, "asset", objectType, "should remove contract name from object type")
+	assert.Equal(t, []string{"alice"}, attrs, "should return attributes")
+
+	objectType, _, _ = complexCtx.SplitKey(key)
+	assert.Equal(t, "simple.asset", objectType, "should not remove name of other contract")
+
+	// Should not prefix shared object types
+	sharedKey, _ := simpleCtx.CreateKey("sharedAsset", "alice")
+	expected, _ = stub.CreateCompositeKey("sharedAsset", []string{"alice"})
+	assert.Equal(t, expected, sharedKey, "should not prefix shared object type")
+	objectType, _, _ = simpleCtx.SplitKey(sharedKey)
+	assert.Equal(t, "sharedAsset", objectType, "should return shared object type as is")
+
+	// Should query object type in namespace of contract
+	assert.Nil(t, simpleCtx.PutStateAs(key, GoodStruct{Prop1: "simple"}), "should put composite key")
+	complexKey, _ := complexCtx.CreateKey("asset", "alice")
+	assert.Nil(t, complexCtx.PutStateAs(complexKey, GoodStruct{Prop1: "complex"}), "should put composite key")
+
+	values := []GoodStruct{}
+	err = simpleCtx.GetStatesByPartialKey("asset", []string{}, &values)
+	assert.Nil(t, err, "should not error for partial key")
+	assert.Equal(t, []GoodStruct{{Prop1: "simple"}}, values, "should only return values in namespace of contract")
+}
+
+func TestForEachStateNamespaced(t *testing.T) {
+	_, stub := newStateTestContext()
+	simpleCtx := newNamespacedTestContext(stub, "simple", "shared")
+	complexCtx := newNamespacedTestContext(stub, "complex", "shared")
+
+	for _, key := range []string{"asset1", "asset2", "asset3", "other"} {
+		simpleCtx.PutStateAs(key, GoodStruct{Prop1: "simple " + key})
+	}
+
+	complexCtx.PutStateAs("asset2", GoodStruct{Prop1: "complex"})
+	simpleCtx.PutStateAs("shared1", GoodStruct{Prop1: "shared"})
+
+	collect := func(ctx *TransactionContext, startKey string, endKey string) ([]string, error) {
+		values := []string{}
+
+		err := ctx.ForEachState(startKey, endKey, func(key string, decode func(interface{}) error) error {
+			gs := new(GoodStruct)
+			err := decode(gs)
+			values = append(values, key+"="+gs.Prop1)
+			return err
+		})
+
+		return values, err
+	}
+
+	// Should iterate keys of contract in range as passed
+	values, err := collect(simpleCtx, "asset2", "other")
+	assert.Nil(t, err, "should not error for namespaced range")
+	assert.Equal(t, []string{"asset2=simple asset2", "asset3=simple asset3"}, values, "should iterate keys of contract in range")
+
+	values, _ = collect(simpleCtx, "", "")
+	assert.Equal(t, []string{"asset1=simple asset1", "asset2=simple asset2", "asset3=simple asset3", "other=simple other"}, values, "should iterate all keys of contract for open range")
+
+	values, _ = collect(complexCtx, "", "")
+	assert.Equal(t, []string{"asset2=complex"}, values, "should only iterate keys of contract")
+
+	// Should iterate shared keys as stored
+	values, _ = collect(complexCtx, "shared", "shared~")
+	assert.Equal(t, []string{"shared1=shared"}, values, "should iterate shared keys")
+}
+
+func TestCacheNamespaced(t *testing.T) {
+	_, stub := newStateTestContext()
+	simpleCtx := newNamespacedTestContext(stub, "simple", "shared")
+	complexCtx := newNamespacedTestContext(stub, "complex", "shared")
+
+	simpleKey, _ := stub.CreateCompositeKey("simple", []string{"asset1"})
+
+	// Should read and write keys in namespace of contract
+	assert.Nil(t, simpleCtx.Cache().Set("asset1", []byte("simple")), "should set namespaced key")
+	assert.Equal(t, []byte("simple"), stub.State[simpleKey], "should write key in namespace of contract")
+
+	value, err := simpleCtx.Cache().Get("asset1")
+	assert.Nil(t, err, "should not error getting namespaced key")
+	assert.Equal(t, []byte("simple"), value, "should read key from namespace of contract")
+
+	value, _ = complexCtx.Cache().Get("asset1")
+	assert.Nil(t, value, "should not read key of other contract")
+
+	// Should invalidate namespaced keys written using state helpers
+	simpleCtx.PutStateAs("asset1", "updated")
+	value, _ = simpleCtx.Cache().Get("asset1")
+	assert.Equal(t, []byte("\"updated\""), value, "should read again after write using state helpers")
+
+	// Should delete key in namespace of contract
+	assert.Nil(t, simpleCtx.Cache().Delete("asset1"), "should delete namespaced key")
+	assert.Nil(t, stub.State[simpleKey], "should delete key in namespace of contract")
+
+	// Should error when key cannot be namespaced
+	_, err = simpleCtx.Cache().Get("asset\U0010FFFF")
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error getting key that cannot be namespaced")
+	err = simpleCtx.Cache().Set("asset\U0010FFFF", nil)
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error setting key that cannot be namespaced")
+	err = simpleCtx.Cache().Delete("asset\U0010FFFF")
+	assert.Contains(t, err.Error(), "Failed to namespace key asset\U0010FFFF.", "should error deleting key that cannot be namespaced")
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxStateTriggerDepth the maximum depth of state triggers writing keys that
+// fire further state triggers
+const MaxStateTriggerDepth = 10
+
+// StateChange describes a write to the world state made using a helper of
+// the transaction context
+type StateChange struct {
+	Key     string
+	Value   []byte
+	Deleted bool
+}
+
+// StateTrigger is called within the transaction when a key with the prefix it
+// is registered for is written or deleted. Returning an error fails the write.
+type StateTrigger func(ctx *TransactionContext, change StateChange) error
+
+type stateTrigger struct {
+	prefix  string
+	trigger StateTrigger
+}
+
+// AddStateTrigger registers a trigger to be called whenever a key starting with
+// the passed prefix is written using PutStateAs or PutOrgState, or deleted using
+// DelOrgState, e.g. to maintain indexes or aggregates of the values. Triggers are
+// called in the order added. Organisation keys are composite keys so the prefix
+// is matched against the composite key (see GetOrgKey). Keys written using
+// PutStateAs are passed to the trigger as passed to PutStateAs, rather than as
+// namespaced when state namespacing is enabled. Triggers may themselves
+// write keys using the helpers, firing further triggers, up to a depth of
+// MaxStateTriggerDepth.
+func (cc *ContractChaincode) AddStateTrigger(prefix string, trigger StateTrigger) {
+	cc.stateTriggers = append(cc.stateTriggers, stateTrigger{prefix, trigger})
+}
+
+func (ctx *TransactionContext) fireStateTriggers(change StateChange) error {
+	if ctx.triggerDepth >= MaxStateTriggerDepth {
+		return fmt.Errorf("State triggers exceeded maximum depth of %d writing key %s", MaxStateTriggerDepth, change.Key)
+	}
+
+	ctx.triggerDepth++
+	defer func() { ctx.triggerDepth-- }()
+
+	for _, st := range ctx.details.stateTriggers {
+		if !strings.HasPrefix(change.Key, st.prefix) {
+			continue
+		}
+
+		if err := st.trigger(ctx, change); err != nil {
+			return fmt.Errorf("State trigger for prefix %s failed for key %s. %s", st.prefix, change.Key, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type triggerTestContract struct {
+	Contract
+}
+
+func (ttc *triggerTestContract) Create(ctx *TransactionContext, key string, value int) error {
+	return ctx.PutStateAs("asset~"+key, value)
+}
+
+func newTriggerTestContext(triggers ...stateTrigger) (*TransactionContext, *stateErrorTestStub) {
+	ctx, stub := newStateTestContext()
+	ctx.setTransactionDetails(transactionDetails{stateTriggers: triggers})
+
+	return ctx, stub
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestAddStateTrigger(t *testing.T) {
+	cc := ContractChaincode{}
+
+	cc.AddStateTrigger("asset~", func(ctx *TransactionContext, change StateChange) error { return nil })
+	cc.AddStateTrigger("other~", func(ctx *TransactionContext, change StateChange) error { return nil })
+
+	assert.Equal(t, 2, len(cc.stateTriggers), "should add triggers")
+	assert.Equal(t, "asset~", cc.stateTriggers[0].prefix, "should add triggers in order")
+	assert.Equal(t, "other~", cc.stateTriggers[1].prefix, "should add triggers in order")
+}
+
+func TestFireStateTriggers(t *testing.T) {
+	var err error
+	var changes []StateChange
+
+	record := func(ctx *TransactionContext, change StateChange) error {
+		changes = append(changes, change)
+		return nil
+	}
+
+	// Should not call triggers for keys not matching prefix
+	ctx, _ := newTriggerTestContext(stateTrigger{"asset~", record})
+	err = ctx.PutStateAs("other~key1", 1)
+	assert.Nil(t, err, "should not error when no trigger matches")
+	assert.Nil(t, changes, "should not call trigger for other prefix")
+
+	// Should call triggers for keys matching prefix with value written
+	err = ctx.PutStateAs("asset~key1", 1)
+	assert.Nil(t, err, "should not error when trigger succeeds")
+	assert.Equal(t, []StateChange{{Key: "asset~key1", Value: []byte("1")}}, changes, "should call trigger with written value")
+
+	// Should call triggers in order added
+	changes = nil
+	order := []string{}
+	ctx, _ = newTriggerTestContext(
+		stateTrigger{"asset~", func(ctx *TransactionContext, change StateChange) error { order = append(order, "first"); return nil }},
+		stateTrigger{"", func(ctx *TransactionContext, change StateChange) error { order = append(order, "second"); return nil }},
+	)
+	ctx.PutStateAs("asset~key1", 1)
+	assert.Equal(t, []string{"first", "second"}, order, "should call triggers in order")
+
+	// Should call triggers for org state writes and deletes using org key
+	ctx, stub := newTriggerTestContext(stateTrigger{"", record})
+	stub.Creator = createCreator("Org1MSP", nil)
+	err = ctx.PutOrgState("key1", []byte("value"))
+	assert.Nil(t, err, "should not error putting org state")
+	err = ctx.DelOrgState("key1")
+	assert.Nil(t, err, "should not error deleting org state")
+	orgKey, _ := ctx.GetOrgKey("key1")
+	assert.Equal(t, []StateChange{{Key: orgKey, Value: []byte("value")}, {Key: orgKey, Deleted: true}}, changes, "should call trigger for org state changes")
+
+	// Should not call triggers when write fails
+	changes = nil
+	ctx, stub = newTriggerTestContext(stateTrigger{"", record})
+	stub.Creator = createCreator("Org1MSP", nil)
+	stub.putErr = errors.New("some put error")
+	stub.delErr = errors.New("some del error")
+	ctx.PutStateAs("key1", 1)
+	ctx.PutOrgState("key1", []byte("value"))
+	ctx.DelOrgState("key1")
+	assert.Nil(t, changes, "should not call trigger when write fails")
+
+	// Should return trigger error
+	ctx, _ = newTriggerTestContext(stateTrigger{"asset~", func(ctx *TransactionContext, change StateChange) error { return errors.New("some trigger error") }})
+	err = ctx.PutStateAs("asset~key1", 1)
+	assert.EqualError(t, err, "State trigger for prefix asset~ failed for key asset~key1. some trigger error", "should return trigger error")
+
+	// Should allow triggers to write keys firing further triggers
+	ctx, stub = newTriggerTestContext(
+		stateTrigger{"asset~", func(ctx *TransactionContext, change StateChange) error {
+			return ctx.PutStateAs("index~"+strings.TrimPrefix(change.Key, "asset~"), string(change.Value))
+		}},
+		stateTrigger{"index~", record},
+	)
+	err = ctx.PutStateAs("asset~key1", 1)
+	assert.Nil(t, err, "should not error when trigger writes")
+	bytes, _ := stub.MockStub.GetState("index~key1")
+	assert.Equal(t, "\"1\"", string(bytes), "should write key from trigger")
+	assert.Equal(t, "index~key1", changes[len(changes)-1].Key, "should fire trigger for key written by trigger")
+	assert.Equal(t, 0, ctx.triggerDepth, "should reset depth after triggers")
+
+	// Should error when triggers recurse beyond max depth
+	ctx, _ = newTriggerTestContext(stateTrigger{"loop", func(ctx *TransactionContext, change StateChange) error {
+		return ctx.PutStateAs("loop", 1)
+	}})
+	err = ctx.PutStateAs("loop", 1)
+	assert.Contains(t, err.Error(), fmt.Sprintf("State triggers exceeded maximum depth of %d writing key loop", MaxStateTriggerDepth), "should error when max depth exceeded")
+	assert.Equal(t, 0, ctx.triggerDepth, "should reset depth after error")
+}
+
+func TestInvokeWithStateTriggers(t *testing.T) {
+	cc := convertC2CC(new(triggerTestContract))
+
+	total := 0
+	cc.AddStateTrigger("asset~", func(ctx *TransactionContext, change StateChange) error {
+		total++
+		return ctx.PutStateAs("count", total)
+	})
+
+	// Should call triggers for writes made by transactions
+	callContractFunctionAndCheckSuccess(t, cc, []string{"Create", "key1", "10"}, invokeType, "")
+	assert.Equal(t, 1, total, "should call trigger within transaction")
+
+	// Should fail the transaction when trigger errors
+	cc.AddStateTrigger("asset~", func(ctx *TransactionContext, change StateChange) error { return errors.New("some trigger error") })
+	callContractFunctionAndCheckError(t, cc, []string{"Create", "key2", "10"}, invokeType, "State trigger for prefix asset~ failed for key asset~key2. some trigger error")
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var sharedFieldsWarningWriter io.Writer = os.Stderr
+
+var contractType = reflect.TypeOf(Contract{})
+
+// EnableStrictContracts sets Start to return an error, rather than write a
+// warning, when a contract of the chaincode has exported fields other than an
+// embedded Contract. Contract functions are called on a copy of the contract
+// for each transaction so changes to its fields are not kept between, or shared
+// with the before, after and unknown transactions of, a transaction. Data for
+// a transaction should be stored on the transaction context (see SetData).
+func (cc *ContractChaincode) EnableStrictContracts() {
+	cc.strictContracts = true
+}
+
+// SetData stores a value for the remainder of the transaction under the
+// passed key so that it is available to the before, named, unknown and after
+// functions of the transaction
+func (ctx *TransactionContext) SetData(key string, value interface{}) {
+	if ctx.data == nil {
+		ctx.data = make(map[string]interface{})
+	}
+
+	ctx.data[key] = value
+}
+
+// GetData returns the value stored under the passed key for the transaction
+// and whether a value was stored
+func (ctx *TransactionContext) GetData(key string) (interface{}, bool) {
+	value, ok := ctx.data[key]
+
+	return value, ok
+}
+
+func getSharedFields(contractStruct reflect.Type) []string {
+	sharedFields := []string{}
+
+	for i := 0; i < contractStruct.NumField(); i++ {
+		field := contractStruct.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Type != contractType {
+			sharedFields = append(sharedFields, getSharedFields(field.Type)...)
+			continue
+		}
+
+		if field.PkgPath != "" || (field.Anonymous && (field.Type == contractType || field.Type == reflect.PtrTo(contractType))) {
+			continue
+		}
+
+		sharedFields = append(sharedFields, field.Name)
+	}
+
+	return sharedFields
+}
+
+func (cc *ContractChaincode) checkSharedFields() error {
+	names := []string{}
+
+	for name, contract := range cc.contracts {
+		if len(contract.sharedFields) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+
+	problems := []string{}
+
+	for _, name := range names {
+		problems = append(problems, fmt.Sprintf("Contract %s has exported fields %s which are not kept between transactions.", name, sliceAsCommaSentence(cc.contracts[name].sharedFields)))
+	}
+
+	message := strings.Join(problems, " ") + " Store transaction data on the transaction context instead"
+
+	if cc.strictContracts {
+		return fmt.Errorf("Contracts must not have exported fields. %s", message)
+	}
+
+	fmt.Fprintf(sharedFieldsWarningWriter, "Warning: %s\n", message)
+
+	return nil
+}
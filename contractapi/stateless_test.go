@@ -0,0 +1,142 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type statefulContract struct {
+	Contract
+	Owner   string
+	Count   int
+	private string
+}
+
+type dataTestContract struct {
+	Contract
+}
+
+func (dtc *dataTestContract) GetOwner(ctx *TransactionContext) string {
+	owner, _ := ctx.GetData("owner")
+	return owner.(string)
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestEnableStrictContracts(t *testing.T) {
+	cc := ContractChaincode{}
+	cc.EnableStrictContracts()
+
+	assert.True(t, cc.strictContracts, "should enable strict contracts")
+}
+
+func TestSetData(t *testing.T) {
+	ctx := TransactionContext{}
+
+	// Should store values
+	ctx.SetData("key1", "value1")
+	ctx.SetData("key2", 2)
+	assert.Equal(t, map[string]interface{}{"key1": "value1", "key2": 2}, ctx.data, "should store values")
+
+	// Should replace value
+	ctx.SetData("key1", "other")
+	assert.Equal(t, "other", ctx.data["key1"], "should replace value")
+
+	// Should clear values when stub set
+	ctx.SetStub(nil)
+	assert.Nil(t, ctx.data, "should clear data on set stub")
+}
+
+func TestGetData(t *testing.T) {
+	var value interface{}
+	var ok bool
+
+	ctx := TransactionContext{}
+
+	// Should return false when not set
+	value, ok = ctx.GetData("key1")
+	assert.False(t, ok, "should return false when no data")
+	assert.Nil(t, value, "should return nil when no data")
+
+	// Should return stored value
+	ctx.data = map[string]interface{}{"key1": "value1"}
+	value, ok = ctx.GetData("key1")
+	assert.True(t, ok, "should return true when set")
+	assert.Equal(t, "value1", value, "should return value")
+}
+
+func TestGetSharedFields(t *testing.T) {
+	assert.Equal(t, []string{}, getSharedFields(reflect.TypeOf(myContract{})), "should ignore unexported fields and embedded contract")
+	assert.Equal(t, []string{"Owner", "Count"}, getSharedFields(reflect.TypeOf(statefulContract{})), "should return exported fields")
+	assert.Equal(t, []string{}, getSharedFields(reflect.TypeOf(struct{ *Contract }{})), "should ignore embedded contract pointer")
+}
+
+func TestCheckSharedFields(t *testing.T) {
+	var cc ContractChaincode
+	var err error
+
+	buf := new(bytes.Buffer)
+	oldWriter := sharedFieldsWarningWriter
+	sharedFieldsWarningWriter = buf
+	defer func() { sharedFieldsWarningWriter = oldWriter }()
+
+	// Should do nothing when no shared fields
+	cc = convertC2CC(new(myContract))
+	err = cc.checkSharedFields()
+	assert.Nil(t, err, "should not error when no shared fields")
+	assert.Equal(t, "", buf.String(), "should not warn when no shared fields")
+
+	// Should warn when shared fields
+	cc = convertC2CC(new(statefulContract), new(receiverTestContract))
+	err = cc.checkSharedFields()
+	assert.Nil(t, err, "should not error when not strict")
+	assert.Equal(t, "Warning: Contract receiverTestContract has exported fields Value which are not kept between transactions. Contract statefulContract has exported fields Owner and Count which are not kept between transactions. Store transaction data on the transaction context instead\n", buf.String(), "should warn of shared fields")
+
+	// Should error when strict
+	buf.Reset()
+	cc.EnableStrictContracts()
+	err = cc.checkSharedFields()
+	assert.EqualError(t, err, "Contracts must not have exported fields. Contract receiverTestContract has exported fields Value which are not kept between transactions. Contract statefulContract has exported fields Owner and Count which are not kept between transactions. Store transaction data on the transaction context instead", "should error when strict")
+	assert.Equal(t, "", buf.String(), "should not warn when strict")
+
+	// Should return error on start when strict
+	assert.EqualError(t, cc.Start(), err.Error(), "should return error on start")
+}
+
+func TestTransactionData(t *testing.T) {
+	dtc := new(dataTestContract)
+	dtc.SetBeforeTransaction(func(ctx *TransactionContext) {
+		ctx.SetData("owner", "some owner")
+	})
+	cc := convertC2CC(dtc)
+
+	mockStub := shimtest.NewMockStub("dataTest", &cc)
+
+	// Should share data between before and named function
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("GetOwner")})
+	assert.Equal(t, "some owner", string(response.Payload), "should return data set in before")
+}
@@ -14,15 +14,53 @@
 
 package contractapi
 
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
 type systemContract struct {
 	Contract
-	metadata string
+	metadata     string
+	openAPI      *lazyDocument
+	constants    map[string]map[string]interface{}
+	versions     versionDetails
+	dependencies map[string][]Dependency
+}
+
+// lazyDocument a document returned by a transaction of the system contract that
+// is generated when first requested rather than when the chaincode is created
+type lazyDocument struct {
+	once     sync.Once
+	generate func() string
+	value    string
+}
+
+func (ld *lazyDocument) get() string {
+	if ld == nil {
+		return ""
+	}
+
+	ld.once.Do(func() {
+		ld.value = ld.generate()
+	})
+
+	return ld.value
 }
 
 func (sc *systemContract) setMetadata(metadata string) {
 	sc.metadata = metadata
 }
 
+func (sc *systemContract) setOpenAPI(generate func() string) {
+	sc.openAPI = &lazyDocument{generate: generate}
+}
+
+func (sc *systemContract) setConstants(constants map[string]map[string]interface{}) {
+	sc.constants = constants
+}
+
 // GetMetadata returns JSON formatted metadata of chaincode
 // the system contract is part of. This metadata is composed
 // of reflected metadata combined with the metadata file
@@ -30,3 +68,50 @@ func (sc *systemContract) setMetadata(metadata string) {
 func (sc *systemContract) GetMetadata() string {
 	return sc.metadata
 }
+
+// GetFormattedMetadata returns the metadata returned by GetMetadata
+// in the format passed, JSON by default, and if components only is
+// passed only its components are returned.
+func (sc *systemContract) GetFormattedMetadata(format string, componentsOnly bool) (string, error) {
+	if format == MetadataFormatJSON && !componentsOnly {
+		return sc.metadata, nil
+	}
+
+	var metadata interface{}
+
+	if err := json.Unmarshal([]byte(sc.metadata), &metadata); err != nil {
+		return "", fmt.Errorf("Failed to parse metadata. %s", err.Error())
+	}
+
+	if componentsOnly {
+		metadata = metadata.(map[string]interface{})["components"]
+	}
+
+	return formatMetadata(metadata, format)
+}
+
+// GetOpenAPI returns the metadata of the chaincode the system
+// contract is part of converted to a JSON formatted OpenAPI 3
+// document with a path for each transaction
+func (sc *systemContract) GetOpenAPI() string {
+	return sc.openAPI.get()
+}
+
+// GetContractConstants returns the JSON formatted named sets of reference data
+// of the passed contract. Returns an error if the contract does not
+// define any constants.
+func (sc *systemContract) GetContractConstants(contractName string) (string, error) {
+	constants, ok := sc.constants[contractName]
+
+	if !ok {
+		return "", fmt.Errorf("Contract %s does not define constants", contractName)
+	}
+
+	bytes, err := json.Marshal(constants)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal constants of contract %s. %s", contractName, err.Error())
+	}
+
+	return string(bytes), nil
+}
@@ -37,3 +37,97 @@ func TestGetMetadata(t *testing.T) {
 
 	assert.Equal(t, "my metadata", sc.GetMetadata(), "should have returned metadata field")
 }
+
+func TestGetFormattedMetadata(t *testing.T) {
+	var metadata string
+	var err error
+
+	sc := systemContract{}
+	sc.metadata = "my metadata"
+
+	// Should return metadata field for JSON
+	metadata, err = sc.GetFormattedMetadata(MetadataFormatJSON, false)
+	assert.Nil(t, err, "should not error for JSON")
+	assert.Equal(t, "my metadata", metadata, "should have returned metadata field")
+
+	// Should error when metadata cannot be parsed
+	_, err = sc.GetFormattedMetadata(MetadataFormatYAML, false)
+	assert.Contains(t, err.Error(), "Failed to parse metadata.", "should error when metadata not JSON")
+
+	sc.metadata = `{"components":{"schemas":{"Asset":{"properties":{"description":{"description":"what it is","type":"string"}},"description":"an asset"}}},"info":{"title":"my chaincode","version":"latest"}}`
+
+	// Should return metadata in format passed
+	metadata, err = sc.GetFormattedMetadata(MetadataFormatYAML, false)
+	assert.Nil(t, err, "should not error for YAML")
+	assert.Equal(t, "components:\n  schemas:\n    Asset:\n      description: an asset\n      properties:\n        description:\n          description: what it is\n          type: string\ninfo:\n  title: my chaincode\n  version: latest\n", metadata, "should return metadata as YAML")
+
+	metadata, err = sc.GetFormattedMetadata(MetadataFormatMin, false)
+	assert.Nil(t, err, "should not error for min")
+	assert.Equal(t, `{"components":{"schemas":{"Asset":{"properties":{"description":{"type":"string"}}}}},"info":{"title":"my chaincode","version":"latest"}}`, metadata, "should return metadata without descriptions")
+
+	_, err = sc.GetFormattedMetadata("xml", false)
+	assert.EqualError(t, err, "Unknown metadata format xml. Expected json, yaml or min", "should error for unknown format")
+
+	// Should return only components
+	metadata, err = sc.GetFormattedMetadata(MetadataFormatJSON, true)
+	assert.Nil(t, err, "should not error for components only")
+	assert.Equal(t, `{"schemas":{"Asset":{"description":"an asset","properties":{"description":{"description":"what it is","type":"string"}}}}}`, metadata, "should return only components")
+}
+
+func TestSetConstants(t *testing.T) {
+	sc := systemContract{}
+	constants := map[string]map[string]interface{}{"mycontract": {"currencies": []string{"GBP"}}}
+	sc.setConstants(constants)
+
+	assert.Equal(t, constants, sc.constants, "should have set constants field")
+}
+
+func TestGetContractConstants(t *testing.T) {
+	var str string
+	var err error
+
+	sc := systemContract{}
+	sc.constants = map[string]map[string]interface{}{
+		"mycontract":  {"currencies": []string{"GBP", "USD"}},
+		"badcontract": {"channel": make(chan int)},
+	}
+
+	// Should return JSON of contract constants
+	str, err = sc.GetContractConstants("mycontract")
+	assert.Nil(t, err, "should not error for contract with constants")
+	assert.Equal(t, "{\"currencies\":[\"GBP\",\"USD\"]}", str, "should return constants as JSON")
+
+	// Should error when contract has no constants
+	_, err = sc.GetContractConstants("othercontract")
+	assert.EqualError(t, err, "Contract othercontract does not define constants", "should error for contract without constants")
+
+	// Should error when constants cannot be marshalled
+	_, err = sc.GetContractConstants("badcontract")
+	assert.EqualError(t, err, "Failed to marshal constants of contract badcontract. json: unsupported type: chan int", "should error when constants cannot be marshalled")
+}
+
+func TestSetOpenAPI(t *testing.T) {
+	sc := systemContract{}
+	sc.setOpenAPI(func() string { return "my openapi" })
+
+	assert.Equal(t, "my openapi", sc.openAPI.generate(), "should have set openAPI generator")
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	sc := systemContract{}
+
+	// Should return blank when not set
+	assert.Equal(t, "", sc.GetOpenAPI(), "should return blank when openAPI not set")
+
+	// Should generate openAPI once on first use
+	calls := 0
+	sc.setOpenAPI(func() string {
+		calls++
+		return "my openapi"
+	})
+
+	assert.Equal(t, 0, calls, "should not generate openAPI before use")
+	assert.Equal(t, "my openapi", sc.GetOpenAPI(), "should have returned generated openAPI")
+	assert.Equal(t, "my openapi", sc.GetOpenAPI(), "should return cached openAPI")
+	assert.Equal(t, 1, calls, "should generate openAPI once")
+}
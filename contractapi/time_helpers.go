@@ -0,0 +1,91 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+)
+
+// GetTxTimestamp returns the timestamp of the transaction. The timestamp is set
+// by the client and is the same for all endorsing peers so, unlike time.Now(),
+// can be used without causing endorsement mismatches.
+func (ctx *TransactionContext) GetTxTimestamp() (time.Time, error) {
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed to get transaction timestamp. %s", err.Error())
+	}
+
+	return ptypes.Timestamp(timestamp)
+}
+
+// AssertWithin returns an error if the timestamp of the transaction is not
+// within the duration d of time t. The transaction timestamp is set by the
+// client and is the same for all endorsing peers so, unlike time.Now(), can
+// be safely used when checking deadlines.
+func (ctx *TransactionContext) AssertWithin(d time.Duration, t time.Time) error {
+	txTime, err := ctx.GetTxTimestamp()
+
+	if err != nil {
+		return err
+	}
+
+	diff := txTime.Sub(t)
+
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff > d {
+		return fmt.Errorf("Transaction timestamp %s is not within %s of %s", txTime.Format(time.RFC3339), d.String(), t.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// AssertBefore returns an error if the timestamp of the transaction is not
+// before time t e.g. an auction closing time
+func (ctx *TransactionContext) AssertBefore(t time.Time) error {
+	txTime, err := ctx.GetTxTimestamp()
+
+	if err != nil {
+		return err
+	}
+
+	if !txTime.Before(t) {
+		return fmt.Errorf("Transaction timestamp %s is not before %s", txTime.Format(time.RFC3339), t.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// AssertAfter returns an error if the timestamp of the transaction is not
+// after time t e.g. an offer start time
+func (ctx *TransactionContext) AssertAfter(t time.Time) error {
+	txTime, err := ctx.GetTxTimestamp()
+
+	if err != nil {
+		return err
+	}
+
+	if !txTime.After(t) {
+		return fmt.Errorf("Transaction timestamp %s is not after %s", txTime.Format(time.RFC3339), t.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+var standardTxTime = time.Date(2019, time.October, 1, 12, 0, 0, 0, time.UTC)
+var standardTxTimestamp = &timestamp.Timestamp{Seconds: standardTxTime.Unix()}
+
+// ================================
+// Tests
+// ================================
+
+func TestGetTxTimestamp(t *testing.T) {
+	var txTime time.Time
+	var err error
+
+	noTimeCtx, _ := newStateTestContext(withTxTimestamp(nil))
+	ctx, _ := newStateTestContext(withTxTimestamp(standardTxTimestamp))
+
+	// Should error when stub has no timestamp
+	_, err = noTimeCtx.GetTxTimestamp()
+	assert.EqualError(t, err, "Failed to get transaction timestamp. TxTimestamp not set", "should error when timestamp not available")
+
+	// Should convert the transaction timestamp
+	txTime, err = ctx.GetTxTimestamp()
+	assert.Nil(t, err, "should not error when timestamp available")
+	assert.True(t, standardTxTime.Equal(txTime), "should return transaction timestamp as time")
+}
+
+func TestAssertWithin(t *testing.T) {
+	noTimeCtx, _ := newStateTestContext(withTxTimestamp(nil))
+	ctx, _ := newStateTestContext(withTxTimestamp(standardTxTimestamp))
+
+	// Should error when timestamp not available
+	assert.EqualError(t, noTimeCtx.AssertWithin(time.Hour, standardTxTime), "Failed to get transaction timestamp. TxTimestamp not set", "should error when timestamp not available")
+
+	// Should not error when within duration either side
+	assert.Nil(t, ctx.AssertWithin(time.Hour, standardTxTime.Add(30*time.Minute)), "should not error when time is after but within duration")
+	assert.Nil(t, ctx.AssertWithin(time.Hour, standardTxTime.Add(-time.Hour)), "should not error when time is before but within duration")
+
+	// Should error when outside duration
+	assert.EqualError(t, ctx.AssertWithin(time.Hour, standardTxTime.Add(2*time.Hour)), "Transaction timestamp 2019-10-01T12:00:00Z is not within 1h0m0s of 2019-10-01T14:00:00Z", "should error when outside duration")
+}
+
+func TestAssertBefore(t *testing.T) {
+	noTimeCtx, _ := newStateTestContext(withTxTimestamp(nil))
+	ctx, _ := newStateTestContext(withTxTimestamp(standardTxTimestamp))
+
+	assert.EqualError(t, noTimeCtx.AssertBefore(standardTxTime), "Failed to get transaction timestamp. TxTimestamp not set", "should error when timestamp not available")
+	assert.Nil(t, ctx.AssertBefore(standardTxTime.Add(time.Second)), "should not error when transaction before time")
+	assert.EqualError(t, ctx.AssertBefore(standardTxTime), "Transaction timestamp 2019-10-01T12:00:00Z is not before 2019-10-01T12:00:00Z", "should error when transaction not before time")
+}
+
+func TestAssertAfter(t *testing.T) {
+	noTimeCtx, _ := newStateTestContext(withTxTimestamp(nil))
+	ctx, _ := newStateTestContext(withTxTimestamp(standardTxTimestamp))
+
+	assert.EqualError(t, noTimeCtx.AssertAfter(standardTxTime), "Failed to get transaction timestamp. TxTimestamp not set", "should error when timestamp not available")
+	assert.Nil(t, ctx.AssertAfter(standardTxTime.Add(-time.Second)), "should not error when transaction after time")
+	assert.EqualError(t, ctx.AssertAfter(standardTxTime), "Transaction timestamp 2019-10-01T12:00:00Z is not after 2019-10-01T12:00:00Z", "should error when transaction not after time")
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// SpanDetails describes the transaction a span is started for
+type SpanDetails struct {
+	ChannelID string
+	TxID      string
+	Contract  string
+	Function  string
+}
+
+// Span records the duration and outcome of a transaction
+type Span interface {
+	// End is called when the transaction completes, with an error containing
+	// the message of the response if the transaction failed
+	End(err error)
+}
+
+// Tracer starts spans for transactions. It can be implemented using a
+// tracing library, e.g. OpenTelemetry, to export the spans for the library's
+// configured exporter.
+type Tracer interface {
+	// StartSpan is called before a transaction is dispatched to its contract
+	StartSpan(details SpanDetails) Span
+}
+
+// SetTracer sets the tracer used to start a span around each transaction
+// called using Init or Invoke, including each transaction of a batch. The span
+// covers the routing of the transaction and the before, named, unknown and after
+// functions called for it. Passing nil disables tracing, unless an OTLP endpoint
+// is set in the environment when the chaincode is started (see OTLPTracer).
+func (cc *ContractChaincode) SetTracer(tracer Tracer) {
+	cc.tracer = tracer
+}
+
+func (cc *ContractChaincode) traceInvoke(stub shim.ChaincodeStubInterface) peer.Response {
+	nsFcn, _ := stub.GetFunctionAndParameters()
+
+	ns, fn := cc.splitFunctionName(nsFcn)
+
+	span := cc.tracer.StartSpan(SpanDetails{
+		ChannelID: stub.GetChannelID(),
+		TxID:      stub.GetTxID(),
+		Contract:  ns,
+		Function:  fn,
+	})
+
+	response := cc.invoke(stub)
+
+	var err error
+
+	if response.Status >= shim.ERRORTHRESHOLD {
+		err = errors.New(response.Message)
+	}
+
+	span.End(err)
+
+	return response
+}
@@ -0,0 +1,102 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type recordedSpan struct {
+	details SpanDetails
+	ended   bool
+	err     error
+}
+
+func (rs *recordedSpan) End(err error) {
+	rs.ended = true
+	rs.err = err
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (rt *recordingTracer) StartSpan(details SpanDetails) Span {
+	span := &recordedSpan{details: details}
+	rt.spans = append(rt.spans, span)
+
+	return span
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestSetTracer(t *testing.T) {
+	cc := ContractChaincode{}
+	tracer := new(recordingTracer)
+
+	cc.SetTracer(tracer)
+
+	assert.Equal(t, tracer, cc.tracer, "should set the tracer")
+}
+
+func TestTraceInvoke(t *testing.T) {
+	var tracer *recordingTracer
+
+	cc := convertC2CC(new(myContract))
+
+	// Should start and end span for successful transaction
+	tracer = new(recordingTracer)
+	cc.SetTracer(tracer)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"myContract:ReturnsString"}, invokeType, "Some string")
+	assert.Len(t, tracer.spans, 1, "should start span")
+	assert.Equal(t, SpanDetails{TxID: standardTxID, Contract: "myContract", Function: "ReturnsString"}, tracer.spans[0].details, "should start span with transaction details")
+	assert.True(t, tracer.spans[0].ended, "should end span")
+	assert.Nil(t, tracer.spans[0].err, "should end span without error on success")
+
+	// Should end span with error of failed transaction
+	tracer = new(recordingTracer)
+	cc.SetTracer(tracer)
+	callContractFunctionAndCheckError(t, cc, []string{"ReturnsError"}, invokeType, "Some error")
+	assert.Equal(t, "myContract", tracer.spans[0].details.Contract, "should use default contract when not named")
+	assert.EqualError(t, tracer.spans[0].err, "Some error", "should end span with error")
+
+	tracer = new(recordingTracer)
+	cc.SetTracer(tracer)
+	callContractFunctionAndCheckError(t, cc, []string{"missing:Function"}, invokeType, "Contract not found with name missing")
+	assert.EqualError(t, tracer.spans[0].err, "Contract not found with name missing", "should end span with error for unknown contract")
+
+	// Should start span for each transaction of batch
+	tracer = new(recordingTracer)
+	cc.SetTracer(tracer)
+	cc.EnableBatchInvocation()
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":" + BatchTransactionName, "[{\"function\":\"ReturnsString\"}]"}, invokeType, "[{\"status\":200,\"payload\":\"Some string\"}]")
+	assert.Len(t, tracer.spans, 2, "should start span for batch and its transaction")
+	assert.Equal(t, BatchTransactionName, tracer.spans[0].details.Function, "should start span for batch")
+	assert.Equal(t, "ReturnsString", tracer.spans[1].details.Function, "should start span for transaction of batch")
+
+	// Should not start spans when tracer removed
+	tracer = new(recordingTracer)
+	cc.SetTracer(nil)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"ReturnsString"}, invokeType, "Some string")
+	assert.Len(t, tracer.spans, 0, "should not start span")
+}
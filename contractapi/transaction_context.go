@@ -15,34 +15,148 @@
 package contractapi
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+
+	"github.com/awjh-ibm/fabric-go-developer-api/ledgerapi"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 )
 
-// TransactionContextInterface defines functions a valid transaction context
+// TransactionContextInterface defines functions a transaction context must have
+// to be used by contract functions. Contract functions may take the context as
+// this interface, or another interface implemented by the context set for the
+// contract, rather than as the type of that context so that they can be passed any
+// implementation, e.g. a mock, when unit testing them. Functionality such as
+// access control that needs the identity of the client requires the context to
+// also have a GetClientIdentity function, as TransactionContext does.
+type TransactionContextInterface interface {
+	// GetStub should provide a way to access the stub set for the transaction
+	GetStub() shim.ChaincodeStubInterface
+}
+
+// SettableTransactionContextInterface defines functions a valid transaction context
 // should have. Transaction context's set for contracts to be used in chaincode
 // must implement this interface.
-type TransactionContextInterface interface {
+type SettableTransactionContextInterface interface {
+	TransactionContextInterface
+
 	// SetStub should provide a way to pass the stub from a chaincode transaction
 	// call to the transaction context so that it can be used by contract functions.
 	// This is called by Init/Invoke with the stub passed.
 	SetStub(shim.ChaincodeStubInterface)
 }
 
+// transactionDetails holds information about the chaincode and the transaction
+// being processed that is passed by Invoke to the transaction context
+type transactionDetails struct {
+	featureFlags        map[string]bool
+	stateValidation     bool
+	canonicalState      bool
+	components          *ComponentMetadata
+	contractName        string
+	functionName        string
+	deadline            time.Time
+	context             context.Context
+	stateTriggers       []stateTrigger
+	stateNamespacing    bool
+	sharedStatePrefixes []string
+}
+
+// settableTransactionDetailsInterface is met by TransactionContext and therefore by
+// custom transaction contexts that embed it
+type settableTransactionDetailsInterface interface {
+	setTransactionDetails(transactionDetails)
+}
+
+// validateTransactionContextHandler checks that a new transaction context can be
+// created from the type of the handler for each transaction and have its stub set.
+// A context must be a pointer to a struct and the methods called on it by Invoke
+// must take pointer receivers, so that they are not promoted from an embedded
+// pointer which would be nil in the newly created context
+func validateTransactionContextHandler(ctx SettableTransactionContextInterface) error {
+	if ctx == nil {
+		return errors.New("Transaction context is nil. Expected a pointer to a struct implementing SettableTransactionContextInterface")
+	}
+
+	ctxType := reflect.TypeOf(ctx)
+
+	if ctxType.Kind() != reflect.Ptr || ctxType.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Transaction context %s is not valid. Expected a pointer to a struct implementing SettableTransactionContextInterface", ctxType.String())
+	}
+
+	valueReceiverMethods := []string{}
+
+	if _, ok := ctxType.Elem().MethodByName("SetStub"); ok {
+		valueReceiverMethods = append(valueReceiverMethods, "SetStub")
+	}
+
+	if ctxType.Elem().Implements(reflect.TypeOf((*settableTransactionDetailsInterface)(nil)).Elem()) {
+		valueReceiverMethods = append(valueReceiverMethods, "setTransactionDetails")
+	}
+
+	if len(valueReceiverMethods) > 0 {
+		return fmt.Errorf("Transaction context %s is not valid. Methods %s must take a pointer receiver. If embedding TransactionContext embed it by value rather than as a pointer", ctxType.String(), sliceAsCommaSentence(valueReceiverMethods))
+	}
+
+	return nil
+}
+
 // TransactionContext is a basic transaction context to be used in contracts,
 // containing minimal required functionality use in contracts as part of
 // chaincode. Provides access to the stub and clientIdentity of a transaction.
 // If a contract implements the ContractInterface using the Contract struct then
 // this is the default transaction context that will be used.
 type TransactionContext struct {
-	stub shim.ChaincodeStubInterface
+	stub           shim.ChaincodeStubInterface
+	clientIdentity cid.ClientIdentity
+	pinnedKeys     []string
+	details        transactionDetails
+	data           map[string]interface{}
+	triggerDepth   int
+	rand           *rand.Rand
+	cache          *StateCache
+	eventStores    map[string]*ledgerapi.EventStore
 }
 
 // SetStub stores the passed stub in the transaction context
 func (ctx *TransactionContext) SetStub(stub shim.ChaincodeStubInterface) {
 	ctx.stub = stub
+	ctx.clientIdentity = nil
+	ctx.pinnedKeys = nil
+	ctx.data = nil
+	ctx.rand = nil
+	ctx.cache = nil
+	ctx.eventStores = nil
 }
 
 // GetStub returns the current set stub
 func (ctx *TransactionContext) GetStub() shim.ChaincodeStubInterface {
 	return ctx.stub
 }
+
+func (ctx *TransactionContext) setTransactionDetails(details transactionDetails) {
+	ctx.details = details
+}
+
+// GetClientIdentity returns the identity of the client that submitted the
+// transaction. Provides access to the client's ID, MSP ID, certificate attributes
+// and X509 certificate. The identity is created from the stub on first use and
+// reused for the remainder of the transaction.
+func (ctx *TransactionContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	if ctx.clientIdentity == nil {
+		ci, err := cid.New(ctx.GetStub())
+
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.clientIdentity = ci
+	}
+
+	return ctx.clientIdentity, nil
+}
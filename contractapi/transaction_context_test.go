@@ -15,8 +15,11 @@
 package contractapi
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +37,16 @@ func TestSetStub(t *testing.T) {
 	ctx.SetStub(stub)
 
 	assert.Equal(t, stub, ctx.stub, "should have set the same stub as passed")
+
+	// Should clear client identity of previous stub
+	ctx.clientIdentity = new(clientIdentityTestStr)
+	ctx.SetStub(stub)
+	assert.Nil(t, ctx.clientIdentity, "should have cleared client identity")
+
+	// Should clear state cache of previous stub
+	ctx.cache = new(StateCache)
+	ctx.SetStub(stub)
+	assert.Nil(t, ctx.cache, "should have cleared state cache")
 }
 
 func TestGetStub(t *testing.T) {
@@ -45,3 +58,213 @@ func TestGetStub(t *testing.T) {
 
 	assert.Equal(t, stub, ctx.GetStub(), "should have returned same stub as set")
 }
+
+func TestSetTransactionDetails(t *testing.T) {
+	ctx := TransactionContext{}
+	details := transactionDetails{featureFlags: map[string]bool{"someflag": true}}
+
+	ctx.setTransactionDetails(details)
+
+	assert.Equal(t, details, ctx.details, "should have set the details passed")
+}
+
+type pointerEmbeddedContext struct {
+	*TransactionContext
+}
+
+type valueReceiverContext struct {
+	stub shim.ChaincodeStubInterface
+}
+
+func (ctx valueReceiverContext) SetStub(stub shim.ChaincodeStubInterface) {
+	ctx.stub = stub
+}
+
+func (ctx valueReceiverContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
+func (ctx valueReceiverContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	return nil, nil
+}
+
+type interfaceOnlyContext struct {
+	stub shim.ChaincodeStubInterface
+}
+
+func (ctx *interfaceOnlyContext) SetStub(stub shim.ChaincodeStubInterface) {
+	ctx.stub = stub
+}
+
+func (ctx *interfaceOnlyContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
+func (ctx *interfaceOnlyContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	return cid.New(ctx.stub)
+}
+
+type stringContext string
+
+func (ctx *stringContext) SetStub(stub shim.ChaincodeStubInterface) {}
+
+func (ctx *stringContext) GetStub() shim.ChaincodeStubInterface {
+	return nil
+}
+
+func (ctx *stringContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	return nil, nil
+}
+
+func TestValidateTransactionContextHandler(t *testing.T) {
+	var err error
+
+	// Should not error for valid contexts
+	err = validateTransactionContextHandler(new(TransactionContext))
+	assert.Nil(t, err, "should not error for basic context")
+
+	err = validateTransactionContextHandler(new(customContext))
+	assert.Nil(t, err, "should not error for context embedding basic context")
+
+	err = validateTransactionContextHandler(new(interfaceOnlyContext))
+	assert.Nil(t, err, "should not error for context only implementing interface")
+
+	err = validateTransactionContextHandler((*customContext)(nil))
+	assert.Nil(t, err, "should not error for nil pointer of valid type")
+
+	// Should error for nil
+	err = validateTransactionContextHandler(nil)
+	assert.EqualError(t, err, "Transaction context is nil. Expected a pointer to a struct implementing SettableTransactionContextInterface", "should error for nil")
+
+	// Should error when not pointer to struct
+	err = validateTransactionContextHandler(valueReceiverContext{})
+	assert.EqualError(t, err, "Transaction context contractapi.valueReceiverContext is not valid. Expected a pointer to a struct implementing SettableTransactionContextInterface", "should error for struct value")
+
+	err = validateTransactionContextHandler(new(stringContext))
+	assert.EqualError(t, err, "Transaction context *contractapi.stringContext is not valid. Expected a pointer to a struct implementing SettableTransactionContextInterface", "should error for pointer to non struct")
+
+	// Should error when methods do not take pointer receiver
+	err = validateTransactionContextHandler(new(valueReceiverContext))
+	assert.EqualError(t, err, "Transaction context *contractapi.valueReceiverContext is not valid. Methods SetStub must take a pointer receiver. If embedding TransactionContext embed it by value rather than as a pointer", "should error for value receiver")
+
+	err = validateTransactionContextHandler(new(pointerEmbeddedContext))
+	assert.EqualError(t, err, "Transaction context *contractapi.pointerEmbeddedContext is not valid. Methods SetStub and setTransactionDetails must take a pointer receiver. If embedding TransactionContext embed it by value rather than as a pointer", "should error for embedded pointer")
+}
+
+type clientIdentityTestStr struct {
+	cid.ClientIdentity
+}
+
+func TestGetClientIdentity(t *testing.T) {
+	var ci cid.ClientIdentity
+	var err error
+
+	stub := shimtest.NewMockStub("clientIdentityTest", nil)
+	ctx := TransactionContext{}
+	ctx.SetStub(stub)
+
+	// Should error when creator cannot be parsed
+	ci, err = ctx.GetClientIdentity()
+	assert.Nil(t, ci, "should not return identity when creator invalid")
+	assert.Contains(t, err.Error(), "failed to get transaction invoker's identity", "should return error from cid")
+
+	// Should return identity of creator
+	stub.Creator = createCreator("SomeMSP", map[string]string{"role": "admin"})
+	ci, err = ctx.GetClientIdentity()
+	assert.Nil(t, err, "should not error when creator valid")
+	mspID, _ := ci.GetMSPID()
+	assert.Equal(t, "SomeMSP", mspID, "should return identity with creator MSP ID")
+	value, found, _ := ci.GetAttributeValue("role")
+	assert.True(t, found, "should find attribute of creator")
+	assert.Equal(t, "admin", value, "should return attribute of creator")
+	cert, _ := ci.GetX509Certificate()
+	assert.Equal(t, "someuser", cert.Subject.CommonName, "should return certificate of creator")
+
+	// Should reuse created identity
+	ctx.clientIdentity = new(clientIdentityTestStr)
+	ci, _ = ctx.GetClientIdentity()
+	assert.Equal(t, new(clientIdentityTestStr), ci, "should return stored identity")
+}
+
+type standaloneContext struct {
+	stub shim.ChaincodeStubInterface
+}
+
+func (ctx *standaloneContext) SetStub(stub shim.ChaincodeStubInterface) {
+	ctx.stub = stub
+}
+
+func (ctx *standaloneContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
+func (ctx *standaloneContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	return nil, errors.New("no identity")
+}
+
+type minimalContext struct {
+	stub shim.ChaincodeStubInterface
+}
+
+func (ctx *minimalContext) SetStub(stub shim.ChaincodeStubInterface) {
+	ctx.stub = stub
+}
+
+func (ctx *minimalContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
+type mockTransactionContext struct {
+	txID string
+}
+
+func (ctx *mockTransactionContext) GetStub() shim.ChaincodeStubInterface {
+	stub := shimtest.NewMockStub("mockContext", nil)
+	stub.TxID = ctx.txID
+
+	return stub
+}
+
+func (ctx *mockTransactionContext) GetClientIdentity() (cid.ClientIdentity, error) {
+	return nil, nil
+}
+
+type contextInterfaceTestContract struct {
+	Contract
+}
+
+func (citc *contextInterfaceTestContract) GetTxID(ctx TransactionContextInterface) string {
+	return ctx.GetStub().GetTxID()
+}
+
+func (citc *contextInterfaceTestContract) GetIdentityError(ctx TransactionContextInterface) string {
+	_, err := getClientIdentity(ctx)
+	return err.Error()
+}
+
+func TestContextInterfaces(t *testing.T) {
+	// Should implement interfaces with basic context
+	assert.Implements(t, (*SettableTransactionContextInterface)(nil), new(TransactionContext), "should implement settable interface")
+
+	// Should pass context to functions taking interface
+	citc := new(contextInterfaceTestContract)
+	cc := convertC2CC(citc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetTxID"}, invokeType, standardTxID)
+
+	// Should allow context fully replacing basic context
+	citc = new(contextInterfaceTestContract)
+	citc.SetTransactionContextHandler(new(standaloneContext))
+	cc = convertC2CC(citc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetTxID"}, invokeType, standardTxID)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetIdentityError"}, invokeType, "Failed to get client identity. no identity")
+
+	// Should allow context without client identity
+	citc = new(contextInterfaceTestContract)
+	citc.SetTransactionContextHandler(new(minimalContext))
+	cc = convertC2CC(citc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetTxID"}, invokeType, standardTxID)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"GetIdentityError"}, invokeType, "Failed to get client identity. Transaction context does not provide the client identity")
+
+	// Should allow functions taking interface to be called with mock
+	assert.Equal(t, "mockTxID", citc.GetTxID(&mockTransactionContext{"mockTxID"}), "should use mock context")
+}
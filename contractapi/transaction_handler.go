@@ -50,12 +50,23 @@ type transactionHandler struct {
 	handlesType transactionHandlerType
 }
 
-func (th transactionHandler) call(ctx reflect.Value, data interface{}) (string, interface{}, error) {
-	values := []reflect.Value{}
+// unknownTransactionRequest is the data passed to call for an unknown transaction
+// holding the function name and args that were requested
+type unknownTransactionRequest struct {
+	function string
+	args     []string
+}
 
-	if th.params.context != nil {
-		values = append(values, ctx)
-	}
+var stringSliceType = reflect.TypeOf([]string{})
+
+// takesRequest returns whether an unknown transaction takes the requested
+// function name and args as params
+func (th transactionHandler) takesRequest() bool {
+	return th.handlesType == unknown && len(th.params.fields) == 2
+}
+
+func (th transactionHandler) call(ctx reflect.Value, data interface{}, serializer Serializer) (string, interface{}, error) {
+	values := th.params.contextValues(ctx)
 
 	if th.handlesType == after && len(th.params.fields) == 1 {
 		if data == nil {
@@ -65,15 +76,29 @@ func (th transactionHandler) call(ctx reflect.Value, data interface{}) (string,
 		}
 	}
 
+	if th.takesRequest() {
+		request, _ := data.(unknownTransactionRequest)
+
+		args := request.args
+
+		if args == nil {
+			args = []string{}
+		}
+
+		values = append(values, reflect.ValueOf(request.function), reflect.ValueOf(args))
+	}
+
 	someResp := th.function.Call(values)
 
-	return handleContractFunctionResponse(someResp, th.contractFunction)
+	return handleContractFunctionResponse(someResp, th.contractFunction, serializer)
 }
 
 func newTransactionHandler(fn interface{}, contextHandlerType reflect.Type, handlesType transactionHandlerType) *transactionHandler {
 	cf := newContractFunctionFromFunc(fn, contextHandlerType)
 
-	if handlesType != after && len(cf.params.fields) > 0 {
+	if handlesType == unknown && len(cf.params.fields) > 0 && !(len(cf.params.fields) == 2 && cf.params.fields[0].Kind() == reflect.String && cf.params.fields[1] == stringSliceType) {
+		panic("Unknown transactions may take only the requested function name as a string and its args as a []string as params other than the transaction context")
+	} else if handlesType == before && len(cf.params.fields) > 0 {
 		panic(fmt.Sprintf("%s transactions may not take any params other than the transaction context", handlesType.String()))
 	} else if handlesType == after && len(cf.params.fields) > 1 {
 		panic("After transactions must take at most one non-context param")
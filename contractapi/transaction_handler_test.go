@@ -49,7 +49,10 @@ func TestNewTransactionHandler(t *testing.T) {
 	assert.PanicsWithValue(t, before.String()+" transactions may not take any params other than the transaction context", func() { newTransactionHandler(mc.UsesBasics, basicContextPtrType, before) }, "should error when before does not match expected structure")
 
 	// Should panic when txn passed does not match structure for an unknown txn
-	assert.PanicsWithValue(t, unknown.String()+" transactions may not take any params other than the transaction context", func() { newTransactionHandler(mc.UsesBasics, basicContextPtrType, unknown) }, "should error when unknown does not match expected structure")
+	assert.PanicsWithValue(t, "Unknown transactions may take only the requested function name as a string and its args as a []string as params other than the transaction context", func() { newTransactionHandler(mc.UsesBasics, basicContextPtrType, unknown) }, "should error when unknown does not match expected structure")
+
+	// Should panic when unknown txn takes two params of the wrong types
+	assert.PanicsWithValue(t, "Unknown transactions may take only the requested function name as a string and its args as a []string as params other than the transaction context", func() { newTransactionHandler(mc.UsesContext, basicContextPtrType, unknown) }, "should error when unknown takes params other than requested function and args")
 
 	// Should panic when txn passed does not match structure for an after txn as too many params
 	assert.PanicsWithValue(t, "After transactions must take at most one non-context param", func() { newTransactionHandler(mc.UsesBasics, basicContextPtrType, after) }, "should error when after does not match expected structure, too few params")
@@ -71,6 +74,13 @@ func TestNewTransactionHandler(t *testing.T) {
 	assert.Equal(t, th.params, cf.params, "should create a txn handler for an unknown txn")
 	assert.Equal(t, th.returns, cf.returns, "should create a txn handler for an unknown txn")
 
+	// Should create a txn handler for an unknown transaction taking the requested function and args
+	th = newTransactionHandler(mc.UnknownTransactionWithRequest, basicContextPtrType, unknown)
+	cf = newContractFunctionFromFunc(mc.UnknownTransactionWithRequest, basicContextPtrType)
+	assert.Equal(t, unknown, th.handlesType, "should create a txn handler for an unknown txn taking request")
+	assert.Equal(t, th.params, cf.params, "should create a txn handler for an unknown txn taking request")
+	assert.Equal(t, th.returns, cf.returns, "should create a txn handler for an unknown txn taking request")
+
 	// Should create a txn handler for an after transaction
 	th = newTransactionHandler(mc.AfterTransaction, basicContextPtrType, after)
 	cf = newContractFunctionFromFunc(mc.AfterTransaction, basicContextPtrType)
@@ -101,7 +111,7 @@ func TestTHCall(t *testing.T) {
 	// Should call before transaction type
 	th = newTransactionHandler(mc.BeforeTransaction, basicContextPtrType, before)
 	expectedStr, expectedErr = mc.BeforeTransaction(new(TransactionContext))
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil)
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil, nil)
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as a regular call to BeforeTransaction would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned the string value returned by BeforeTransaction as actual value")
@@ -110,16 +120,28 @@ func TestTHCall(t *testing.T) {
 	// Should call unknown transaction type
 	th = newTransactionHandler(mc.UnknownTransaction, basicContextPtrType, unknown)
 	expectedStr, expectedErr = mc.UnknownTransaction(new(TransactionContext))
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil)
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil, nil)
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as a regular call to UnknownTransaction would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned the string value returned by UnknownTransaction as actual value")
 	assert.Equal(t, expectedErr, actualErr, "Should have returned error as a regular call to UnknownTransaction would")
 
+	// Should call unknown transaction type with requested function and args
+	th = newTransactionHandler(mc.UnknownTransactionWithRequest, basicContextPtrType, unknown)
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), unknownTransactionRequest{"SomeFunction", []string{"arg1", "arg2"}}, nil)
+
+	assert.Equal(t, "Unknown function SomeFunction called with args [arg1 arg2]", actualStr, "should pass requested function and args to UnknownTransactionWithRequest")
+	assert.Equal(t, "Unknown function SomeFunction called with args [arg1 arg2]", actualValue, "should return string value returned by UnknownTransactionWithRequest as actual value")
+	assert.Nil(t, actualErr, "should not error when UnknownTransactionWithRequest returns no error")
+
+	// Should pass empty args to unknown transaction when none requested
+	actualStr, _, _ = th.call(reflect.ValueOf(ctx), unknownTransactionRequest{"SomeFunction", nil}, nil)
+	assert.Equal(t, "Unknown function SomeFunction called with args []", actualStr, "should pass empty args to UnknownTransactionWithRequest")
+
 	// Should call after transaction type
 	th = newTransactionHandler(mc.AfterTransaction, basicContextPtrType, after)
 	expectedStr, expectedErr = mc.AfterTransaction(new(TransactionContext))
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil)
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil, nil)
 
 	assert.Equal(t, expectedStr, actualStr, "Should have returned string as a regular call to AfterTransaction would")
 	assert.Equal(t, expectedStr, actualValue, "Should have returned the string value returned by AfterTransaction as actual value")
@@ -128,7 +150,7 @@ func TestTHCall(t *testing.T) {
 	// Should call after transaction type with interface
 	th = newTransactionHandler(mc.AfterTransactionWithInterface, basicContextPtrType, after)
 	expectedValue, expectedErr = mc.AfterTransactionWithInterface(new(TransactionContext), "some value")
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), "some value")
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), "some value", nil)
 
 	assert.Equal(t, expectedValue, actualStr, "Should have returned string as a regular call to AfterTransactionWithInterface would")
 	assert.Equal(t, expectedValue, actualValue, "Should have returned the string value returned by AfterTransactionWithInterface as actual value")
@@ -137,7 +159,7 @@ func TestTHCall(t *testing.T) {
 	// Should handle when after called with nil because no success type
 	th = newTransactionHandler(mc.AfterTransactionWithInterface, basicContextPtrType, after)
 	expectedValue, expectedErr = mc.AfterTransactionWithInterface(new(TransactionContext), (*UndefinedInterface)(nil))
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil)
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), nil, nil)
 
 	assert.Equal(t, "*contractapi.UndefinedInterface", actualStr, "Should have returned string as a regular call to AfterTransactionWithInterface would")
 	assert.Equal(t, expectedValue, actualValue, "Should have returned the string value returned by AfterTransactionWithInterface as actual value")
@@ -146,7 +168,7 @@ func TestTHCall(t *testing.T) {
 	// Should handle when after called with nil but with success type
 	th = newTransactionHandler(mc.AfterTransactionWithInterface, basicContextPtrType, after)
 	expectedValue, expectedErr = mc.AfterTransactionWithInterface(new(TransactionContext), (*string)(nil))
-	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), (*string)(nil))
+	actualStr, actualValue, actualErr = th.call(reflect.ValueOf(ctx), (*string)(nil), nil)
 
 	assert.Equal(t, "*string", actualStr, "Should have returned string as a regular call to AfterTransactionWithInterface would")
 	assert.Equal(t, expectedValue, actualValue, "Should have returned the string value returned by AfterTransactionWithInterface as actual value")
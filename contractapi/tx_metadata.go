@@ -0,0 +1,104 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/go-openapi/spec"
+)
+
+// TxMetadataTag is added to the tags of functions returning TxMetadata in the metadata
+const TxMetadataTag = "txMetadata"
+
+// TxMetadata holds fields returned alongside the success value of a function, such
+// as the bookmark of the next page of results or warnings. A function returns it
+// between its success value and error e.g. func(...) (string, contractapi.TxMetadata, error).
+// The payload of such functions is then the JSON envelope {"metadata":{...},"result":...}
+// with the success value as its result. The returns schema of the function in the metadata
+// describes the envelope and the function is tagged txMetadata.
+type TxMetadata map[string]interface{}
+
+var txMetadataType = reflect.TypeOf(TxMetadata{})
+
+type txMetadataEnvelope struct {
+	Metadata TxMetadata      `json:"metadata"`
+	Result   json.RawMessage `json:"result"`
+}
+
+// newTxMetadataEnvelope returns the payload of a function returning TxMetadata. Values
+// converted to JSON by the serializer are used as the result as is and other values,
+// such as strings, as JSON strings.
+func newTxMetadataEnvelope(payload string, value interface{}, t reflect.Type, metadata TxMetadata) (string, error) {
+	envelope := txMetadataEnvelope{metadata, json.RawMessage("null")}
+
+	if envelope.Metadata == nil {
+		envelope.Metadata = TxMetadata{}
+	}
+
+	if !isNilReturn(value) {
+		if t.Kind() != reflect.String && t != bytesReflectType && json.Valid([]byte(payload)) {
+			envelope.Result = json.RawMessage(payload)
+		} else {
+			envelope.Result, _ = json.Marshal(payload)
+		}
+	}
+
+	bytes, err := json.Marshal(envelope)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal transaction metadata. %s", err.Error())
+	}
+
+	return string(bytes), nil
+}
+
+// txMetadataSchema returns the schema of the envelope returned by functions returning
+// TxMetadata with the passed schema, nil when there is no success value, as that of
+// its result
+func txMetadataSchema(result *spec.Schema) *spec.Schema {
+	if result == nil {
+		result = new(spec.Schema)
+	}
+
+	schema := new(spec.Schema)
+	schema.Type = []string{"object"}
+	schema.Properties = map[string]spec.Schema{
+		"metadata": *spec.MapProperty(nil),
+		"result":   *result,
+	}
+	schema.Required = []string{"metadata", "result"}
+	schema.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+
+	return schema
+}
+
+// txMetadataResultSchema returns the schema of the result of the envelope described
+// by the passed schema, or nil if it does not describe an envelope
+func txMetadataResultSchema(schema *spec.Schema) *spec.Schema {
+	if schema == nil {
+		return nil
+	}
+
+	result, ok := schema.Properties["result"]
+
+	if !ok {
+		return nil
+	}
+
+	return &result
+}
@@ -0,0 +1,170 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type txMetadataTestContract struct {
+	Contract
+}
+
+func (tmtc *txMetadataTestContract) Query(bookmark string) ([]string, TxMetadata, error) {
+	if bookmark == "bad" {
+		return nil, nil, errors.New("bad bookmark")
+	}
+
+	return []string{"a", "b"}, TxMetadata{"bookmark": "b", "warnings": []string{"partial"}}, nil
+}
+
+func (tmtc *txMetadataTestContract) Name() (string, TxMetadata, error) {
+	return "value", nil, nil
+}
+
+func (tmtc *txMetadataTestContract) Find() (*GoodStruct, TxMetadata, error) {
+	return nil, TxMetadata{"found": false}, nil
+}
+
+func (tmtc *txMetadataTestContract) Data() ([]byte, TxMetadata, error) {
+	return []byte("data"), nil, nil
+}
+
+func (tmtc *txMetadataTestContract) BadMetadata() (string, TxMetadata, error) {
+	return "value", TxMetadata{"bad": make(chan int)}, nil
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewTxMetadataEnvelope(t *testing.T) {
+	var envelope string
+	var err error
+
+	// Should use JSON payloads as result
+	envelope, err = newTxMetadataEnvelope("[1,2]", []int{1, 2}, reflect.TypeOf([]int{}), TxMetadata{"bookmark": "2"})
+	assert.Nil(t, err, "should not error for JSON payload")
+	assert.Equal(t, "{\"metadata\":{\"bookmark\":\"2\"},\"result\":[1,2]}", envelope, "should use JSON payload as result")
+
+	envelope, err = newTxMetadataEnvelope("10", 10, reflect.TypeOf(10), nil)
+	assert.Nil(t, err, "should not error for basic payload")
+	assert.Equal(t, "{\"metadata\":{},\"result\":10}", envelope, "should use number payload as result and empty metadata when nil")
+
+	// Should use strings as JSON strings
+	envelope, err = newTxMetadataEnvelope("[1,2]", "[1,2]", reflect.TypeOf(""), nil)
+	assert.Nil(t, err, "should not error for string payload")
+	assert.Equal(t, "{\"metadata\":{},\"result\":\"[1,2]\"}", envelope, "should use string payload as JSON string")
+
+	envelope, err = newTxMetadataEnvelope("bytes", []byte("bytes"), bytesReflectType, nil)
+	assert.Nil(t, err, "should not error for bytes payload")
+	assert.Equal(t, "{\"metadata\":{},\"result\":\"bytes\"}", envelope, "should use bytes payload as JSON string")
+
+	// Should use null for nil values
+	envelope, err = newTxMetadataEnvelope("", ([]int)(nil), reflect.TypeOf([]int{}), nil)
+	assert.Nil(t, err, "should not error for nil value")
+	assert.Equal(t, "{\"metadata\":{},\"result\":null}", envelope, "should use null result for nil value")
+
+	// Should error when metadata cannot be marshalled
+	_, err = newTxMetadataEnvelope("", nil, reflect.TypeOf(""), TxMetadata{"bad": make(chan int)})
+	assert.Contains(t, err.Error(), "Failed to marshal transaction metadata.", "should error when metadata cannot be marshalled")
+}
+
+func TestTxMetadataSchema(t *testing.T) {
+	var schema *spec.Schema
+
+	// Should describe envelope with result schema
+	schema = txMetadataSchema(spec.StringProperty())
+	assert.Equal(t, spec.StringOrArray{"object"}, schema.Type, "should be object")
+	assert.Equal(t, *spec.MapProperty(nil), schema.Properties["metadata"], "should describe metadata as object")
+	assert.Equal(t, *spec.StringProperty(), schema.Properties["result"], "should use result schema")
+	assert.Equal(t, []string{"metadata", "result"}, schema.Required, "should require metadata and result")
+	assert.False(t, schema.AdditionalProperties.Allows, "should not allow other properties")
+	assert.Equal(t, spec.StringProperty(), txMetadataResultSchema(schema), "should return result schema of envelope")
+
+	// Should describe envelope without result schema
+	schema = txMetadataSchema(nil)
+	assert.Equal(t, spec.Schema{}, schema.Properties["result"], "should use empty schema when no result")
+
+	// Should return nil result schema when not envelope
+	assert.Nil(t, txMetadataResultSchema(nil), "should return nil for nil schema")
+	assert.Nil(t, txMetadataResultSchema(spec.StringProperty()), "should return nil when no result property")
+}
+
+func TestTxMetadataReturns(t *testing.T) {
+	var returns contractFunctionReturns
+	var err error
+
+	// Should parse value, metadata and error returns
+	returns, err = method2ContractFunctionReturns(generateMethodTypesAndValuesFromFunc(new(txMetadataTestContract).Query))
+	assert.Nil(t, err, "should not error for value, metadata and error")
+	assert.Equal(t, contractFunctionReturns{reflect.TypeOf([]string{}), true, true}, returns, "should set metadata in returns")
+
+	// Should error for invalid returns
+	_, err = method2ContractFunctionReturns(generateMethodTypesAndValuesFromFunc(func() (string, string, error) { return "", "", nil }))
+	assert.EqualError(t, err, "Function contains invalid second return type. Type string is not valid. Expected contractapi.TxMetadata", "should error when second return is not metadata")
+
+	_, err = method2ContractFunctionReturns(generateMethodTypesAndValuesFromFunc(func() (string, TxMetadata, string) { return "", nil, "" }))
+	assert.EqualError(t, err, "Function contains invalid third return type. Type string is not valid. Expected error", "should error when third return is not error")
+
+	_, err = method2ContractFunctionReturns(generateMethodTypesAndValuesFromFunc(func() (chan int, TxMetadata, error) { return nil, nil, nil }))
+	assert.Contains(t, err.Error(), "Function contains invalid first return type.", "should error when first return is invalid")
+
+	_, err = method2ContractFunctionReturns(generateMethodTypesAndValuesFromFunc(func() (string, TxMetadata, error, error) { return "", nil, nil, nil }))
+	assert.EqualError(t, err, "Functions may only return a maximum of three values. Function returns 4", "should error for more than three returns")
+}
+
+func TestTxMetadataFunctions(t *testing.T) {
+	tmtc := new(txMetadataTestContract)
+	cc := convertC2CC(tmtc)
+
+	// Should tag functions returning metadata
+	for _, tm := range cc.metadata.Contracts["txMetadataTestContract"].Transactions {
+		assert.Contains(t, tm.Tag, TxMetadataTag, "should tag functions returning metadata")
+	}
+
+	returns := cc.metadata.Contracts["txMetadataTestContract"].Transactions[4].Returns
+	assert.Equal(t, txMetadataSchema(spec.ArrayProperty(spec.StringProperty())), returns, "should describe envelope in returns schema")
+
+	// Should return envelope
+	callContractFunctionAndCheckSuccess(t, cc, []string{"txMetadataTestContract:Query", "a"}, invokeType, "{\"metadata\":{\"bookmark\":\"b\",\"warnings\":[\"partial\"]},\"result\":[\"a\",\"b\"]}")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"txMetadataTestContract:Name"}, invokeType, "{\"metadata\":{},\"result\":\"value\"}")
+
+	// Should return envelope for nil values regardless of nil return
+	tmtc = new(txMetadataTestContract)
+	tmtc.ConfigureFunction("Find").SetNilReturn(NullNilReturn)
+	cc = convertC2CC(tmtc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{"txMetadataTestContract:Find"}, invokeType, "{\"metadata\":{\"found\":false},\"result\":null}")
+
+	// Should base64 encode bytes result when returns schema of envelope result has base64 format
+	tmtc = new(txMetadataTestContract)
+	tmtc.ConfigureFunction("Data").SetBase64Bytes(true)
+	cc = convertC2CC(tmtc)
+	assert.Equal(t, Base64Format, cc.metadata.Contracts["txMetadataTestContract"].Transactions[1].Returns.Properties["result"].Format, "should set base64 format of result")
+	callContractFunctionAndCheckSuccess(t, cc, []string{"txMetadataTestContract:Data"}, invokeType, "{\"metadata\":{},\"result\":\"ZGF0YQ==\"}")
+
+	// Should return errors
+	callContractFunctionAndCheckError(t, cc, []string{"txMetadataTestContract:Query", "bad"}, invokeType, "bad bookmark")
+	callContractFunctionAndCheckError(t, cc, []string{"txMetadataTestContract:BadMetadata"}, invokeType, "Failed to marshal transaction metadata. json: unsupported type: chan int")
+}
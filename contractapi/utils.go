@@ -18,6 +18,7 @@ package contractapi
 import (
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"path"
 	"reflect"
 	"runtime"
@@ -65,29 +66,6 @@ func sliceAsCommaSentence(slice []string) string {
 	return strings.Replace(strings.Join(slice, " and "), " and ", ", ", len(slice)-2)
 }
 
-func embedsStruct(sc interface{}, toEmbed string) bool {
-
-	ifv := reflect.ValueOf(sc).Elem()
-
-	ift := reflect.TypeOf(sc).Elem()
-
-	for i := 0; i < ifv.NumField(); i++ {
-
-		v := ifv.Field(i)
-
-		t := ift.Field(i)
-
-		kind := v.Kind()
-
-		if kind == reflect.Struct && t.Type.String() == toEmbed {
-
-			return true
-
-		}
-	}
-	return false
-}
-
 func readLocalFile(localPath string) ([]byte, error) {
 	_, filename, _, _ := runtime.Caller(1)
 
@@ -439,6 +417,33 @@ var basicTypes = map[reflect.Kind]basicType{
 	reflect.Interface: new(interfaceType),
 }
 
+// specialTypes are types converted and described in the metadata like the basic
+// types despite their kind. They are passed as their string representation rather
+// than as JSON: *big.Int and Decimal as numbers and []byte untouched.
+var specialTypes = map[reflect.Type]basicType{
+	reflect.TypeOf(new(big.Int)): new(bigIntType),
+	reflect.TypeOf(Decimal("")):  new(decimalType),
+	reflect.TypeOf([]byte{}):     new(bytesType),
+}
+
+func isSpecialType(t reflect.Type) bool {
+	_, ok := specialTypes[t]
+	return ok
+}
+
+// isBasicPtrType returns whether the type is a pointer to one of the basic types
+// other than interface{}, e.g. *string. They are converted and described in the
+// metadata as the type they point to
+func isBasicPtrType(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() == reflect.Interface {
+		return false
+	}
+
+	_, ok := basicTypes[t.Elem().Kind()]
+
+	return ok
+}
+
 func listBasicTypes() string {
 	types := []string{}
 
@@ -488,44 +493,107 @@ func buildMapSchema(rmap reflect.Value, components *ComponentMetadata) (*spec.Sc
 	return spec.MapProperty(lowerSchema), nil
 }
 
-func addComponentIfNotExists(obj reflect.Type, components *ComponentMetadata) error {
-	if obj.Kind() == reflect.Ptr {
-		obj = obj.Elem()
+func getJSONFieldDetails(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", false, true
 	}
 
-	if _, ok := components.Schemas[obj.Name()]; ok {
-		return nil
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+
+	if name == "" {
+		name = field.Name
 	}
 
-	schema := ObjectMetadata{}
-	schema.Required = []string{}
-	schema.Properties = make(map[string]spec.Schema)
-	schema.AdditionalProperties = false
+	return name, stringInSlice("omitempty", parts[1:]), false
+}
+
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous || strings.Split(field.Tag.Get("json"), ",")[0] != "" {
+		return false
+	}
+
+	fieldType := field.Type
+
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct
+}
+
+func isIgnoredField(field reflect.StructField) bool {
+	if field.Name == "" || unicode.IsLower([]rune(field.Name)[0]) {
+		return true
+	}
+
+	_, _, ignore := getJSONFieldDetails(field)
+
+	return ignore
+}
+
+func addStructProperties(obj reflect.Type, schema *ObjectMetadata, components *ComponentMetadata) error {
+	if obj.Kind() == reflect.Ptr {
+		obj = obj.Elem()
+	}
 
 	for i := 0; i < obj.NumField(); i++ {
-		if obj.Field(i).Name == "" || unicode.IsLower([]rune(obj.Field(i).Name)[0]) {
-			break
-		}
+		field := obj.Field(i)
+
+		if isEmbeddedStruct(field) {
+			err := addStructProperties(field.Type, schema, components)
 
-		name := obj.Field(i).Tag.Get("json")
+			if err != nil {
+				return err
+			}
 
-		if name == "" {
-			name = obj.Field(i).Name
+			continue
 		}
 
-		var err error
+		if isIgnoredField(field) {
+			continue
+		}
 
-		propSchema, err := getSchema(obj.Field(i).Type, components)
+		name, omitEmpty, _ := getJSONFieldDetails(field)
+
+		propSchema, err := getSchema(field.Type, components)
 
 		if err != nil {
 			return err
 		}
 
-		schema.Required = append(schema.Required, name)
+		if !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
 
 		schema.Properties[name] = *propSchema
 	}
 
+	return nil
+}
+
+func addComponentIfNotExists(obj reflect.Type, components *ComponentMetadata) error {
+	if obj.Kind() == reflect.Ptr {
+		obj = obj.Elem()
+	}
+
+	if _, ok := components.Schemas[obj.Name()]; ok {
+		return nil
+	}
+
+	schema := ObjectMetadata{}
+	schema.Required = []string{}
+	schema.Properties = make(map[string]spec.Schema)
+	schema.AdditionalProperties = false
+
+	err := addStructProperties(obj, &schema, components)
+
+	if err != nil {
+		return err
+	}
+
 	components.Schemas[obj.Name()] = schema
 
 	return nil
@@ -549,6 +617,14 @@ func getSchema(field reflect.Type, components *ComponentMetadata) (*spec.Schema,
 	var schema *spec.Schema
 	var err error
 
+	if st, ok := specialTypes[field]; ok {
+		return st.getSchema(), nil
+	}
+
+	if isBasicPtrType(field) {
+		field = field.Elem()
+	}
+
 	if bt, ok := basicTypes[field.Kind()]; !ok {
 		if field.Kind() == reflect.Array {
 			schema, err = buildArraySchema(reflect.New(field).Elem(), components)
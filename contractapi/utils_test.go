@@ -47,6 +47,23 @@ type AnotherBadStruct struct {
 	Prop1 BadStruct `json:"Prop1"`
 }
 
+type ComplexStruct struct {
+	GoodStruct
+	hidden      string
+	ID          string          `json:"id"`
+	Description string          `json:"description,omitempty"`
+	Internal    string          `json:"-"`
+	Nested      *GoodStruct     `json:"nested"`
+	NestedSlice []GoodStruct    `json:"nestedSlice"`
+	NestedMap   map[string]bool `json:"nestedMap,omitempty"`
+	NamedEmbed  AnotherGoodStruct
+	*EmbeddedStruct
+}
+
+type EmbeddedStruct struct {
+	Extra string `json:"extra"`
+}
+
 func testConvertError(t *testing.T, bt basicType, toPass string, expectedType string) {
 	t.Helper()
 
@@ -456,6 +473,80 @@ func TestAddComponentIfNotExists(t *testing.T) {
 	assert.Equal(t, len(components.Schemas), 0, "should not have added new component")
 }
 
+func TestGetJSONFieldDetails(t *testing.T) {
+	var name string
+	var omitEmpty bool
+	var ignore bool
+
+	csT := reflect.TypeOf(ComplexStruct{})
+
+	// Should use field name when no tag
+	name, omitEmpty, ignore = getJSONFieldDetails(csT.Field(8))
+	assert.Equal(t, "NamedEmbed", name, "should use field name when no json tag")
+	assert.False(t, omitEmpty, "should not be omit empty when no json tag")
+	assert.False(t, ignore, "should not ignore when no json tag")
+
+	// Should use tag name
+	name, omitEmpty, ignore = getJSONFieldDetails(csT.Field(2))
+	assert.Equal(t, "id", name, "should use json tag name")
+	assert.False(t, omitEmpty, "should not be omit empty when tag does not specify")
+	assert.False(t, ignore, "should not ignore when tag has name")
+
+	// Should read omitempty
+	name, omitEmpty, _ = getJSONFieldDetails(csT.Field(3))
+	assert.Equal(t, "description", name, "should strip options from json tag name")
+	assert.True(t, omitEmpty, "should be omit empty when tag specifies")
+
+	// Should ignore "-"
+	_, _, ignore = getJSONFieldDetails(csT.Field(4))
+	assert.True(t, ignore, "should ignore when tag is -")
+}
+
+func TestIsEmbeddedStruct(t *testing.T) {
+	csT := reflect.TypeOf(ComplexStruct{})
+
+	assert.True(t, isEmbeddedStruct(csT.Field(0)), "should be true for embedded struct")
+	assert.True(t, isEmbeddedStruct(csT.Field(9)), "should be true for embedded struct pointer")
+	assert.False(t, isEmbeddedStruct(csT.Field(5)), "should be false for named struct field")
+	assert.False(t, isEmbeddedStruct(csT.Field(2)), "should be false for non struct field")
+}
+
+func TestIsIgnoredField(t *testing.T) {
+	csT := reflect.TypeOf(ComplexStruct{})
+
+	assert.True(t, isIgnoredField(csT.Field(1)), "should ignore unexported field")
+	assert.True(t, isIgnoredField(csT.Field(4)), "should ignore field with - tag")
+	assert.False(t, isIgnoredField(csT.Field(2)), "should not ignore exported field")
+}
+
+func TestAddComponentIfNotExistsComplexStruct(t *testing.T) {
+	components := new(ComponentMetadata)
+	components.Schemas = make(map[string]ObjectMetadata)
+
+	err := addComponentIfNotExists(reflect.TypeOf(new(ComplexStruct)), components)
+
+	expectedProperties := map[string]spec.Schema{
+		"Prop1":       *stringTypeVar.getSchema(),
+		"prop2":       *intTypeVar.getSchema(),
+		"id":          *stringTypeVar.getSchema(),
+		"description": *stringTypeVar.getSchema(),
+		"nested":      *spec.RefSchema("#/components/schemas/GoodStruct"),
+		"nestedSlice": *spec.ArrayProperty(spec.RefSchema("#/components/schemas/GoodStruct")),
+		"nestedMap":   *spec.MapProperty(boolTypeVar.getSchema()),
+		"NamedEmbed":  *spec.RefSchema("#/components/schemas/AnotherGoodStruct"),
+		"extra":       *stringTypeVar.getSchema(),
+	}
+
+	// Should flatten embedded structs, skip ignored fields, not require omitempty fields and reference nested structs
+	assert.Nil(t, err, "should not error for valid struct")
+	assert.Equal(t, 3, len(components.Schemas), "should add components for struct and nested structs")
+	assert.Equal(t, ObjectMetadata{
+		Properties:           expectedProperties,
+		Required:             []string{"Prop1", "prop2", "id", "nested", "nestedSlice", "NamedEmbed", "extra"},
+		AdditionalProperties: false,
+	}, components.Schemas["ComplexStruct"], "should build complete schema for complex struct")
+}
+
 func TestBuildStructSchema(t *testing.T) {
 	var schema *spec.Schema
 	var err error
@@ -516,6 +607,10 @@ func TestGetSchema(t *testing.T) {
 	testGetSchema(t, float32RefType, float32TypeVar.getSchema())
 	testGetSchema(t, float64RefType, float64TypeVar.getSchema())
 
+	// should return schema of type pointed to for pointers to basic types
+	testGetSchema(t, reflect.TypeOf(new(string)), stringTypeVar.getSchema())
+	testGetSchema(t, reflect.TypeOf(new(int)), intTypeVar.getSchema())
+
 	mc := myContract{}
 	mcFuncType := reflect.TypeOf(mc.AfterTransactionWithInterface)
 
@@ -619,13 +714,13 @@ func TestGetSchema(t *testing.T) {
 	testGetSchema(t, reflect.TypeOf([]int32{1}), int32ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]int64{1}), int64ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]uint{1}), uintArraySchema)
-	testGetSchema(t, reflect.TypeOf([]uint8{1}), uint8ArraySchema)
+	testGetSchema(t, reflect.TypeOf([]uint8{1}), new(bytesType).getSchema())
 	testGetSchema(t, reflect.TypeOf([]uint16{1}), uint16ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]uint32{1}), uint32ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]uint64{1}), uint64ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]float32{1}), float32ArraySchema)
 	testGetSchema(t, reflect.TypeOf([]float64{1}), float64ArraySchema)
-	testGetSchema(t, reflect.TypeOf([]byte{1}), uint8ArraySchema)
+	testGetSchema(t, reflect.TypeOf([]byte{1}), new(bytesType).getSchema())
 	testGetSchema(t, reflect.TypeOf([]rune{1}), int32ArraySchema)
 
 	// Should return schema for multidimensional slices made of each of the basic types
@@ -636,13 +731,13 @@ func TestGetSchema(t *testing.T) {
 	testGetSchema(t, reflect.TypeOf([][]int32{[]int32{}}), spec.ArrayProperty(int32ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]int64{[]int64{}}), spec.ArrayProperty(int64ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]uint{[]uint{}}), spec.ArrayProperty(uintArraySchema))
-	testGetSchema(t, reflect.TypeOf([][]uint8{[]uint8{}}), spec.ArrayProperty(uint8ArraySchema))
+	testGetSchema(t, reflect.TypeOf([][]uint8{[]uint8{}}), spec.ArrayProperty(new(bytesType).getSchema()))
 	testGetSchema(t, reflect.TypeOf([][]uint16{[]uint16{}}), spec.ArrayProperty(uint16ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]uint32{[]uint32{}}), spec.ArrayProperty(uint32ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]uint64{[]uint64{}}), spec.ArrayProperty(uint64ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]float32{[]float32{}}), spec.ArrayProperty(float32ArraySchema))
 	testGetSchema(t, reflect.TypeOf([][]float64{[]float64{}}), spec.ArrayProperty(float64ArraySchema))
-	testGetSchema(t, reflect.TypeOf([][]byte{[]byte{}}), spec.ArrayProperty(uint8ArraySchema))
+	testGetSchema(t, reflect.TypeOf([][]byte{[]byte{}}), spec.ArrayProperty(new(bytesType).getSchema()))
 	testGetSchema(t, reflect.TypeOf([][]rune{[]rune{}}), spec.ArrayProperty(int32ArraySchema))
 
 	// Should handle an array of slice
@@ -0,0 +1,350 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ValidationTag is the struct tag read to validate the fields of structs
+// passed as transaction parameters. The tag value is a comma separated list
+// of rules from: required, min=N, max=N and regexp=EXPR. For numbers min and
+// max limit the value, for strings, arrays, slices and maps they limit the
+// length. As regular expressions may contain commas regexp must be the last
+// rule of the tag. Tags are parsed when the chaincode is created and
+// CreateNewChaincode panics if any tag of a parameter type is invalid.
+const ValidationTag = "validate"
+
+// ValidationError is returned when a parameter passed to a transaction fails
+// the validation rules of its struct tags. Invoke returns the error with status
+// 400 rather than 500 to distinguish it from errors of the function itself.
+type ValidationError struct {
+	message string
+}
+
+func (ve *ValidationError) Error() string {
+	return ve.message
+}
+
+// SchemaValidationError is returned when an arg passed for a parameter does not
+// match the schema of the parameter in the metadata, reflected or from the metadata
+// file. Invoke returns the error with status 400 and the error JSON marshalled as
+// the payload so that clients can see which properties failed.
+type SchemaValidationError struct {
+	Parameter string                    `json:"parameter"`
+	Failures  []SchemaValidationFailure `json:"failures"`
+	message   string
+}
+
+// SchemaValidationFailure describes a property of an arg that did not match the
+// schema. The property is the path to it within the arg e.g. owner.name, including
+// the keys of maps and indexes of slices e.g. asset1.owner.name or 1.owner.name,
+// and is blank when the arg itself did not match.
+type SchemaValidationFailure struct {
+	Property string `json:"property"`
+	Message  string `json:"message"`
+}
+
+func (sve *SchemaValidationError) Error() string {
+	return sve.message
+}
+
+func newSchemaValidationError(parameter string, resultErrors []gojsonschema.ResultError) *SchemaValidationError {
+	failures := []SchemaValidationFailure{}
+
+	for _, resultError := range resultErrors {
+		property := strings.TrimPrefix(strings.TrimPrefix(resultError.Field(), "prop"), ".")
+
+		if missing, ok := resultError.Details()["property"].(string); ok {
+			if property != "" {
+				property += "."
+			}
+
+			property += missing
+		}
+
+		failures = append(failures, SchemaValidationFailure{property, resultError.Description()})
+	}
+
+	message := fmt.Sprintf("Value passed for parameter \"%s\" did not match schema: %s", parameter, validateErrorsToString(resultErrors))
+
+	return &SchemaValidationError{parameter, failures, message}
+}
+
+// validationRule is a rule of a validation tag with its limit or regular
+// expression parsed when the chaincode is created
+type validationRule struct {
+	name    string
+	value   string
+	limit   float64
+	pattern *regexp.Regexp
+}
+
+// typeValidator holds the rules of the validation tags of the fields of a type,
+// and of the types it contains, parsed when the chaincode is created so that
+// tags are not parsed and their regular expressions compiled on each transaction
+type typeValidator struct {
+	elem   *typeValidator
+	fields []fieldValidator
+}
+
+// fieldValidator holds the rules of a field of a struct. The name of fields of
+// embedded structs is blank as their fields are validated as if of the parent.
+type fieldValidator struct {
+	index     int
+	name      string
+	rules     []validationRule
+	validator *typeValidator
+}
+
+// newParamValidators returns a validator for each of the parameter types of the
+// named function, or an error if any of them has an invalid validation tag
+func newParamValidators(methodName string, fields []reflect.Type) ([]*typeValidator, error) {
+	validators := []*typeValidator{}
+	seen := make(map[reflect.Type]*typeValidator)
+
+	for _, field := range fields {
+		validator, err := newTypeValidator(field, seen)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s contains invalid %s tag. %s", methodName, ValidationTag, err.Error())
+		}
+
+		validators = append(validators, validator)
+	}
+
+	return validators, nil
+}
+
+// newTypeValidator returns the validator of the type. Validators of types already
+// seen are reused so that recursive types are handled.
+func newTypeValidator(t reflect.Type, seen map[reflect.Type]*typeValidator) (*typeValidator, error) {
+	if validator, ok := seen[t]; ok {
+		return validator, nil
+	}
+
+	validator := new(typeValidator)
+	seen[t] = validator
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Array, reflect.Slice, reflect.Map:
+		elem, err := newTypeValidator(t.Elem(), seen)
+
+		if err != nil {
+			return nil, err
+		}
+
+		validator.elem = elem
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if isIgnoredField(field) {
+				continue
+			}
+
+			rules, err := parseValidationTag(field.Tag.Get(ValidationTag))
+
+			if err != nil {
+				return nil, fmt.Errorf("Field %s of %s. %s", field.Name, t.String(), err.Error())
+			}
+
+			fieldTypeValidator, err := newTypeValidator(field.Type, seen)
+
+			if err != nil {
+				return nil, err
+			}
+
+			name := ""
+
+			if !isEmbeddedStruct(field) {
+				name, _, _ = getJSONFieldDetails(field)
+			}
+
+			validator.fields = append(validator.fields, fieldValidator{i, name, rules, fieldTypeValidator})
+		}
+	}
+
+	return validator, nil
+}
+
+// validateParam returns a ValidationError listing each failure of the value
+func (tv *typeValidator) validateParam(name string, value reflect.Value) error {
+	failures := tv.validate("", value)
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	toReturn := ""
+
+	for i, failure := range failures {
+		toReturn += strconv.Itoa(i+1) + ". " + failure + "\n"
+	}
+
+	return &ValidationError{fmt.Sprintf("Value passed for parameter \"%s\" failed validation: %s", name, strings.Trim(toReturn, "\n"))}
+}
+
+func (tv *typeValidator) validate(path string, value reflect.Value) []string {
+	failures := []string{}
+
+	if tv == nil {
+		return failures
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			failures = append(failures, tv.elem.validate(path, value.Elem())...)
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			failures = append(failures, tv.elem.validate(fmt.Sprintf("%s[%d]", path, i), value.Index(i))...)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			failures = append(failures, tv.elem.validate(fmt.Sprintf("%s[%v]", path, key.Interface()), value.MapIndex(key))...)
+		}
+	case reflect.Struct:
+		for _, field := range tv.fields {
+			fieldPath := path
+
+			if field.name != "" {
+				if fieldPath != "" {
+					fieldPath += "."
+				}
+
+				fieldPath += field.name
+			}
+
+			fieldValue := value.Field(field.index)
+
+			for _, rule := range field.rules {
+				if err := rule.check(fieldValue); err != nil {
+					failures = append(failures, fmt.Sprintf("%s: %s", fieldPath, err.Error()))
+				}
+			}
+
+			failures = append(failures, field.validator.validate(fieldPath, fieldValue)...)
+		}
+	}
+
+	return failures
+}
+
+func parseValidationTag(tag string) ([]validationRule, error) {
+	rules := []validationRule{}
+
+	for tag != "" {
+		var part string
+
+		if strings.HasPrefix(tag, "regexp=") {
+			part, tag = tag, ""
+		} else if idx := strings.Index(tag, ","); idx != -1 {
+			part, tag = tag[:idx], tag[idx+1:]
+		} else {
+			part, tag = tag, ""
+		}
+
+		nameValue := strings.SplitN(part, "=", 2)
+		rule := validationRule{name: strings.TrimSpace(nameValue[0])}
+
+		if len(nameValue) == 2 {
+			rule.value = nameValue[1]
+		}
+
+		var err error
+
+		switch rule.name {
+		case "required":
+		case "min", "max":
+			if rule.limit, err = strconv.ParseFloat(rule.value, 64); err != nil {
+				return nil, fmt.Errorf("Rule %s requires a number. Received %s", rule.name, rule.value)
+			}
+		case "regexp":
+			if rule.pattern, err = regexp.Compile(rule.value); err != nil {
+				return nil, fmt.Errorf("Rule regexp requires a valid regular expression. %s", err.Error())
+			}
+		default:
+			return nil, fmt.Errorf("Unknown rule %s", rule.name)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (vr validationRule) check(value reflect.Value) error {
+	switch vr.name {
+	case "required":
+		if isZeroValue(value) {
+			return fmt.Errorf("is required")
+		}
+	case "min", "max":
+		measure, isLength, ok := measureValue(value)
+
+		if !ok {
+			return fmt.Errorf("rule %s cannot be applied to type %s", vr.name, value.Type().String())
+		}
+
+		description := "must be"
+
+		if isLength {
+			description = "must have length"
+		}
+
+		if vr.name == "min" && measure < vr.limit {
+			return fmt.Errorf("%s at least %s", description, vr.value)
+		} else if vr.name == "max" && measure > vr.limit {
+			return fmt.Errorf("%s at most %s", description, vr.value)
+		}
+	case "regexp":
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("rule regexp cannot be applied to type %s", value.Type().String())
+		}
+
+		if !vr.pattern.MatchString(value.String()) {
+			return fmt.Errorf("must match %s", vr.value)
+		}
+	}
+
+	return nil
+}
+
+func measureValue(value reflect.Value) (float64, bool, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), false, true
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return float64(value.Len()), true, true
+	}
+
+	return 0, false, false
+}
+
+func isZeroValue(value reflect.Value) bool {
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
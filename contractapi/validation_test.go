@@ -0,0 +1,254 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type validatedItem struct {
+	Name string `json:"name" validate:"required,regexp=^[a-z]{1,3}$"`
+}
+
+type validatedStruct struct {
+	ID       string                    `json:"id" validate:"required,min=2,max=4"`
+	Quantity int                       `json:"quantity" validate:"min=1,max=10"`
+	Price    float64                   `json:"price" validate:"max=9.5"`
+	Tags     []string                  `json:"tags" validate:"max=2"`
+	Items    []validatedItem           `json:"items"`
+	ItemMap  map[string]*validatedItem `json:"itemMap"`
+	Ignored  string                    `json:"-" validate:"required"`
+	private  string
+}
+
+type recursiveValidatedStruct struct {
+	Name     string                      `json:"name" validate:"required"`
+	Children []*recursiveValidatedStruct `json:"children"`
+}
+
+type badTagStruct struct {
+	Prop string `validate:"unique"`
+}
+
+type badTagTestContract struct {
+	Contract
+}
+
+func (btc *badTagTestContract) UsesBadTag(bts []badTagStruct) {}
+
+func mustParseRule(name string, value string) validationRule {
+	tag := name
+
+	if value != "" {
+		tag += "=" + value
+	}
+
+	rules, err := parseValidationTag(tag)
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return rules[0]
+}
+
+func newTestValidator(t reflect.Type) *typeValidator {
+	validator, err := newTypeValidator(t, make(map[reflect.Type]*typeValidator))
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return validator
+}
+
+type validationTestContract struct {
+	Contract
+}
+
+func (vtc *validationTestContract) UsesValidatedStruct(vs validatedStruct) string {
+	return vs.ID
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestParseValidationTag(t *testing.T) {
+	var rules []validationRule
+	var err error
+
+	// Should return no rules for blank tag
+	rules, err = parseValidationTag("")
+	assert.Nil(t, err, "should not error for blank tag")
+	assert.Equal(t, []validationRule{}, rules, "should return no rules for blank tag")
+
+	// Should parse rules with regexp containing commas last
+	rules, err = parseValidationTag("required,min=1,max=2.5,regexp=^a{1,2}$")
+	assert.Nil(t, err, "should not error for valid tag")
+	assert.Equal(t, []validationRule{{"required", "", 0, nil}, {"min", "1", 1, nil}, {"max", "2.5", 2.5, nil}, {"regexp", "^a{1,2}$", 0, regexp.MustCompile("^a{1,2}$")}}, rules, "should parse each rule")
+
+	// Should error for non numeric limits
+	_, err = parseValidationTag("min=abc")
+	assert.EqualError(t, err, "Rule min requires a number. Received abc", "should error for non numeric min")
+
+	// Should error for invalid regexp
+	_, err = parseValidationTag("regexp=[")
+	assert.Contains(t, err.Error(), "Rule regexp requires a valid regular expression.", "should error for invalid regexp")
+
+	// Should error for unknown rules
+	_, err = parseValidationTag("unique")
+	assert.EqualError(t, err, "Unknown rule unique", "should error for unknown rule")
+}
+
+func TestValidationRuleCheck(t *testing.T) {
+	// Should check required
+	assert.EqualError(t, mustParseRule("required", "").check(reflect.ValueOf("")), "is required", "should error for zero value")
+	assert.Nil(t, mustParseRule("required", "").check(reflect.ValueOf("a")), "should not error for non zero value")
+
+	// Should check min and max of numbers
+	assert.EqualError(t, mustParseRule("min", "1").check(reflect.ValueOf(0)), "must be at least 1", "should error for int below min")
+	assert.EqualError(t, mustParseRule("max", "1").check(reflect.ValueOf(uint8(2))), "must be at most 1", "should error for uint above max")
+	assert.Nil(t, mustParseRule("max", "1.5").check(reflect.ValueOf(1.5)), "should not error for float at max")
+
+	// Should check min and max of lengths
+	assert.EqualError(t, mustParseRule("min", "2").check(reflect.ValueOf("a")), "must have length at least 2", "should error for short string")
+	assert.EqualError(t, mustParseRule("max", "1").check(reflect.ValueOf([]int{1, 2})), "must have length at most 1", "should error for long slice")
+
+	// Should error when min or max applied to other types
+	assert.EqualError(t, mustParseRule("min", "1").check(reflect.ValueOf(true)), "rule min cannot be applied to type bool", "should error for bool")
+
+	// Should check regexp
+	assert.EqualError(t, mustParseRule("regexp", "^a+$").check(reflect.ValueOf("b")), "must match ^a+$", "should error for non matching string")
+	assert.Nil(t, mustParseRule("regexp", "^a+$").check(reflect.ValueOf("aa")), "should not error for matching string")
+	assert.EqualError(t, mustParseRule("regexp", "^a+$").check(reflect.ValueOf(1)), "rule regexp cannot be applied to type int", "should error for non string")
+}
+
+func TestNewParamValidators(t *testing.T) {
+	var validators []*typeValidator
+	var err error
+
+	// Should return validator for each parameter
+	validators, err = newParamValidators("SomeFunction", []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(validatedStruct{})})
+	assert.Nil(t, err, "should not error for valid tags")
+	assert.Len(t, validators, 2, "should return validator for each parameter")
+	assert.Len(t, validators[1].fields, 6, "should not include ignored fields")
+	assert.Equal(t, "id", validators[1].fields[0].name, "should use JSON name of field")
+	assert.Equal(t, []validationRule{mustParseRule("required", ""), mustParseRule("min", "2"), mustParseRule("max", "4")}, validators[1].fields[0].rules, "should parse rules of field")
+
+	// Should handle recursive types
+	validators, err = newParamValidators("SomeFunction", []reflect.Type{reflect.TypeOf(recursiveValidatedStruct{})})
+	assert.Nil(t, err, "should not error for recursive type")
+	assert.Same(t, validators[0], validators[0].fields[1].validator.elem.elem, "should reuse validator of recursive type")
+
+	// Should error for invalid tags
+	_, err = newParamValidators("SomeFunction", []reflect.Type{reflect.TypeOf(""), reflect.TypeOf([]badTagStruct{})})
+	assert.EqualError(t, err, "SomeFunction contains invalid validate tag. Field Prop of contractapi.badTagStruct. Unknown rule unique", "should error for invalid tag")
+
+	// Should panic at registration for invalid tags
+	assert.PanicsWithValue(t, "UsesBadTag contains invalid validate tag. Field Prop of contractapi.badTagStruct. Unknown rule unique", func() { convertC2CC(new(badTagTestContract)) }, "should panic creating chaincode with invalid tag")
+}
+
+func TestValidateParam(t *testing.T) {
+	var err error
+
+	valid := validatedStruct{ID: "abc", Quantity: 1, Items: []validatedItem{{"a"}}, ItemMap: map[string]*validatedItem{"key": {"b"}, "nil": nil}}
+
+	// Should not error for types without tags
+	assert.Nil(t, newTestValidator(reflect.TypeOf("")).validateParam("param0", reflect.ValueOf("some string")), "should not error for basic type")
+	assert.Nil(t, newTestValidator(reflect.TypeOf(GoodStruct{})).validateParam("param0", reflect.ValueOf(GoodStruct{})), "should not error for struct without tags")
+	assert.Nil(t, (*typeValidator)(nil).validateParam("param0", reflect.ValueOf(validatedStruct{})), "should not error for nil validator")
+
+	// Should not error for valid struct
+	assert.Nil(t, newTestValidator(reflect.TypeOf(valid)).validateParam("param0", reflect.ValueOf(valid)), "should not error for valid struct")
+	assert.Nil(t, newTestValidator(reflect.TypeOf(&valid)).validateParam("param0", reflect.ValueOf(&valid)), "should not error for pointer to valid struct")
+	assert.Nil(t, newTestValidator(reflect.TypeOf([]validatedStruct{})).validateParam("param0", reflect.ValueOf([]validatedStruct{valid})), "should not error for slice of valid structs")
+
+	// Should return each failure of struct
+	invalid := validatedStruct{ID: "abcde", Price: 10, Tags: []string{"a", "b", "c"}, Items: []validatedItem{{"abcd"}}, ItemMap: map[string]*validatedItem{"key": {""}}}
+	err = newTestValidator(reflect.TypeOf(invalid)).validateParam("param0", reflect.ValueOf(invalid))
+	assert.IsType(t, new(ValidationError), err, "should return validation error")
+	assert.Equal(t, "Value passed for parameter \"param0\" failed validation: 1. id: must have length at most 4\n2. quantity: must be at least 1\n3. price: must be at most 9.5\n4. tags: must have length at most 2\n5. items[0].name: must match ^[a-z]{1,3}$\n6. itemMap[key].name: is required\n7. itemMap[key].name: must match ^[a-z]{1,3}$", err.Error(), "should list each failure")
+
+	// Should validate recursive types
+	recursive := recursiveValidatedStruct{Name: "parent", Children: []*recursiveValidatedStruct{{Name: "child", Children: []*recursiveValidatedStruct{{}}}}}
+	err = newTestValidator(reflect.TypeOf(recursive)).validateParam("param0", reflect.ValueOf(recursive))
+	assert.EqualError(t, err, "Value passed for parameter \"param0\" failed validation: 1. children[0].children[0].name: is required", "should validate nested values of recursive type")
+}
+
+func TestNewSchemaValidationError(t *testing.T) {
+	owner := new(spec.Schema)
+	owner.Typed("object", "")
+	owner.Properties = map[string]spec.Schema{"name": *spec.StringProperty().WithMaxLength(3)}
+	asset := new(spec.Schema)
+	asset.Typed("object", "")
+	asset.Properties = map[string]spec.Schema{"id": *spec.StringProperty(), "owner": *owner}
+	asset.Required = []string{"id"}
+
+	schema, err := compileParameterSchema(ParameterMetadata{Name: "asset", Schema: *asset}, &ComponentMetadata{})
+	assert.Nil(t, err, "should compile schema")
+
+	// Should list failing properties of arg
+	result, _ := schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"prop": map[string]interface{}{"owner": map[string]interface{}{"name": "abcd"}}}))
+	sve := newSchemaValidationError("asset", result.Errors())
+	assert.Equal(t, "asset", sve.Parameter, "should set parameter")
+	assert.ElementsMatch(t, []SchemaValidationFailure{{"id", "id is required"}, {"owner.name", "String length must be less than or equal to 3"}}, sve.Failures, "should list failing properties")
+	assert.Equal(t, "Value passed for parameter \"asset\" did not match schema: "+validateErrorsToString(result.Errors()), sve.Error(), "should describe failures in message")
+
+	// Should use blank property when arg itself fails
+	result, _ = schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"prop": "some string"}))
+	sve = newSchemaValidationError("asset", result.Errors())
+	assert.Equal(t, []SchemaValidationFailure{{"", "Invalid type. Expected: object, given: string"}}, sve.Failures, "should use blank property for arg")
+
+	// Should include map keys and slice indexes in path of property
+	owners := new(spec.Schema)
+	owners.Typed("object", "")
+	owners.AdditionalProperties = &spec.SchemaOrBool{Allows: true, Schema: asset}
+	schema, _ = compileParameterSchema(ParameterMetadata{Name: "assets", Schema: *owners}, &ComponentMetadata{})
+	result, _ = schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"prop": map[string]interface{}{"asset1": map[string]interface{}{}}}))
+	sve = newSchemaValidationError("assets", result.Errors())
+	assert.Equal(t, []SchemaValidationFailure{{"asset1.id", "id is required"}}, sve.Failures, "should include map key in path")
+
+	schema, _ = compileParameterSchema(ParameterMetadata{Name: "assets", Schema: *spec.ArrayProperty(asset)}, &ComponentMetadata{})
+	result, _ = schema.Validate(gojsonschema.NewGoLoader(map[string]interface{}{"prop": []interface{}{map[string]interface{}{"id": "1"}, map[string]interface{}{}}}))
+	sve = newSchemaValidationError("assets", result.Errors())
+	assert.Equal(t, []SchemaValidationFailure{{"1.id", "id is required"}}, sve.Failures, "should include slice index in path")
+}
+
+func TestInvokeWithValidation(t *testing.T) {
+	cc := convertC2CC(new(validationTestContract))
+	mockStub := shimtest.NewMockStub("validationTest", &cc)
+
+	// Should call function when params valid
+	response := mockStub.MockInvoke(standardTxID, [][]byte{[]byte("UsesValidatedStruct"), []byte("{\"id\":\"abc\",\"quantity\":1,\"price\":1,\"tags\":[],\"items\":[],\"itemMap\":{}}")})
+	assert.Equal(t, shim.Success([]byte("abc")), response, "should call function when params valid")
+
+	// Should return 400 response when params invalid
+	response = mockStub.MockInvoke(standardTxID, [][]byte{[]byte("UsesValidatedStruct"), []byte("{\"id\":\"\",\"quantity\":1,\"price\":1,\"tags\":[],\"items\":[],\"itemMap\":{}}")})
+	assert.Equal(t, int32(shim.ERRORTHRESHOLD), response.Status, "should return 400 status when params invalid")
+	assert.Equal(t, "Value passed for parameter \"param0\" failed validation: 1. id: is required\n2. id: must have length at least 2", response.Message, "should return validation failures")
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LibraryVersion the version of the contractapi library the chaincode is built with
+const LibraryVersion = "0.1.0"
+
+// versionDetails holds the versions returned by the GetVersions transaction
+// of the system contract
+type versionDetails struct {
+	Chaincode   string            `json:"chaincode"`
+	ContractAPI string            `json:"contractapi"`
+	Contracts   map[string]string `json:"contracts"`
+}
+
+func (sc *systemContract) setVersions(versions versionDetails) {
+	sc.versions = versions
+}
+
+// GetVersions returns the JSON formatted versions of the chaincode the system
+// contract is part of, of each of its contracts (see Contract.SetVersion) and of
+// the contractapi library, so that operators can verify what is running after
+// an upgrade. Versions that are not set are returned as latest.
+func (sc *systemContract) GetVersions() (string, error) {
+	bytes, err := json.Marshal(sc.versions)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal versions. %s", err.Error())
+	}
+
+	return string(bytes), nil
+}
+
+// setSystemContractVersions passes the versions of the chaincode and its contracts
+// to the system contract to be returned by its GetVersions transaction
+func (cc *ContractChaincode) setSystemContractVersions() {
+	contract, ok := cc.contracts[SystemContractName]
+
+	if !ok {
+		return
+	}
+
+	versions := versionDetails{}
+	versions.Chaincode = cc.version
+	versions.ContractAPI = LibraryVersion
+	versions.Contracts = make(map[string]string)
+
+	if versions.Chaincode == "" {
+		versions.Chaincode = "latest"
+	}
+
+	for name, ccn := range cc.contracts {
+		if name != SystemContractName {
+			versions.Contracts[name] = ccn.version
+		}
+	}
+
+	contract.receiver.Interface().(*systemContract).setVersions(versions)
+}
@@ -0,0 +1,52 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contractapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestGetVersions(t *testing.T) {
+	sc := new(systemContract)
+
+	// Should return the versions set
+	sc.setVersions(versionDetails{"1.0.0", LibraryVersion, map[string]string{"myContract": "1.2.0"}})
+	versions, err := sc.GetVersions()
+	assert.Nil(t, err, "should not error")
+	assert.Equal(t, "{\"chaincode\":\"1.0.0\",\"contractapi\":\""+LibraryVersion+"\",\"contracts\":{\"myContract\":\"1.2.0\"}}", versions, "should return versions as JSON")
+}
+
+func TestSetSystemContractVersions(t *testing.T) {
+	// Should do nothing without a system contract
+	cc := ContractChaincode{}
+	assert.NotPanics(t, func() { cc.SetVersion("1.0.0") }, "should not panic without system contract")
+
+	// Should default versions not set to latest
+	mc := new(myContract)
+	sc := new(simpleTestContract)
+	sc.SetVersion("1.2.0")
+	cc = convertC2CC(mc, sc)
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetVersions"}, invokeType, "{\"chaincode\":\"latest\",\"contractapi\":\""+LibraryVersion+"\",\"contracts\":{\"myContract\":\"latest\",\"simpleTestContract\":\"1.2.0\"}}")
+
+	// Should update chaincode version when set
+	cc.SetVersion("2.0.0")
+	callContractFunctionAndCheckSuccess(t, cc, []string{SystemContractName + ":GetVersions"}, invokeType, "{\"chaincode\":\"2.0.0\",\"contractapi\":\""+LibraryVersion+"\",\"contracts\":{\"myContract\":\"latest\",\"simpleTestContract\":\"1.2.0\"}}")
+}
@@ -270,13 +270,13 @@ var _ = Describe("contractapi - EndToEnd", func() {
 			peer := network.Peer("Org1", "peer1")
 
 			By("querying instantited chaincode simpleasset name with unknown function")
-			RunSimpleBadQuery(network, orderer, peer, []string{"simpleasset:BadFunction", "SIMPLE_ASSET_1"}, "Unknown function name simpleasset:BadFunction passed to simple asset with args [SIMPLE_ASSET_1]")
+			RunSimpleBadQuery(network, orderer, peer, []string{"simpleasset:BadFunction", "SIMPLE_ASSET_1"}, "Unknown function name BadFunction passed to simple asset with args [SIMPLE_ASSET_1]")
 
 			By("querying instantited chaincode complexasset name with unknown function")
-			RunSimpleBadQuery(network, orderer, peer, []string{"complexasset:BadFunction", "COMPLEX_ASSET_1"}, "Unknown function name complexasset:BadFunction passed to complex asset with args [COMPLEX_ASSET_1]")
+			RunSimpleBadQuery(network, orderer, peer, []string{"complexasset:BadFunction", "COMPLEX_ASSET_1"}, "Unknown function name BadFunction passed to complex asset with args [COMPLEX_ASSET_1]")
 
 			By("querying a function from another name")
-			RunSimpleBadQuery(network, orderer, peer, []string{"complexasset:Update", "SIMPLE_ASSET_1"}, "Unknown function name complexasset:Update passed to complex asset with args [SIMPLE_ASSET_1]")
+			RunSimpleBadQuery(network, orderer, peer, []string{"complexasset:Update", "SIMPLE_ASSET_1"}, "Unknown function name Update passed to complex asset with args [SIMPLE_ASSET_1]")
 
 			By("querying using the default namespace for the non default contract")
 			RunSimpleBadQuery(network, orderer, peer, []string{"ReadColours", "COMPLEX_ASSET_1"}, "Unknown function name ReadColours passed to simple asset with args [COMPLEX_ASSET_1]")
@@ -58,7 +58,7 @@ func (sa *SimpleAsset) GetName() string {
 }
 
 // GetTransactionContextHandler returns custom transaction context
-func (sa *SimpleAsset) GetTransactionContextHandler() contractapi.TransactionContextInterface {
+func (sa *SimpleAsset) GetTransactionContextHandler() contractapi.SettableTransactionContextInterface {
 	return new(TransactionContext)
 }
 
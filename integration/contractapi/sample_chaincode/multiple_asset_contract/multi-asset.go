@@ -238,15 +238,11 @@ func getAsset(ctx *TransactionContext, assetID string) error {
 	return nil
 }
 
-func handleSimpleUnknown(ctx *TransactionContext) error {
-	fn, args := ctx.GetStub().GetFunctionAndParameters()
-
+func handleSimpleUnknown(ctx *TransactionContext, fn string, args []string) error {
 	return fmt.Errorf("Unknown function name %s passed to simple asset with args %v", fn, args)
 }
 
-func handleComplexUnknown(ctx *TransactionContext) error {
-	fn, args := ctx.GetStub().GetFunctionAndParameters()
-
+func handleComplexUnknown(ctx *TransactionContext, fn string, args []string) error {
 	return fmt.Errorf("Unknown function name %s passed to complex asset with args %v", fn, args)
 }
 
@@ -264,6 +260,7 @@ func main() {
 	cac.SetName("complexasset")
 
 	cc := contractapi.CreateNewChaincode(sac, cac)
+	cc.SetDefault(sac)
 
 	if err := cc.Start(); err != nil {
 		fmt.Printf("Error starting multi asset chaincode: %s", err)
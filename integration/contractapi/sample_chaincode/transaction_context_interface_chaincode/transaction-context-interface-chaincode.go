@@ -33,6 +33,11 @@ func (ctx *TransactionContext) SetStub(stub shim.ChaincodeStubInterface) {
 	ctx.stub = stub
 }
 
+// GetStub returns the stub stored
+func (ctx *TransactionContext) GetStub() shim.ChaincodeStubInterface {
+	return ctx.stub
+}
+
 // SimpleAsset with biz logic
 type SimpleAsset struct {
 	contractapi.Contract
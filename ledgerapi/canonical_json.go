@@ -0,0 +1,140 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalCanonicalJSON returns the JSON encoding of the value in a canonical form
+// in which the keys of every object are sorted, there is no insignificant white
+// space and non integer numbers are formatted as encoding/json formats a float64.
+// Values encoding to the same JSON data therefore encode to the same bytes even if
+// they implement json.Marshaler and write keys in an order that varies, e.g. from
+// iterating a map, so that peers endorsing a transaction produce the same write set.
+// Register the CanonicalJSONCodec for a type to store its values in canonical form.
+func MarshalCanonicalJSON(value interface{}) ([]byte, error) {
+	marshalled, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return canonicalizeJSON(marshalled)
+}
+
+func canonicalizeJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	err := decoder.Decode(&decoded)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	writeCanonicalJSON(buf, decoded)
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonicalJSON(buf *bytes.Buffer, value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		keys := []string{}
+
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		buf.WriteByte('{')
+
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalJSON(buf, key)
+			buf.WriteByte(':')
+			writeCanonicalJSON(buf, typed[key])
+		}
+
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+
+		for i, elem := range typed {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalJSON(buf, elem)
+		}
+
+		buf.WriteByte(']')
+	case json.Number:
+		buf.WriteString(canonicalNumber(typed))
+	default:
+		// strings, bools and null marshal the same however they were written
+		marshalled, _ := json.Marshal(typed)
+		buf.Write(marshalled)
+	}
+}
+
+// canonicalNumber leaves integers as written, so that those too large for a
+// float64 keep their precision, and formats other numbers as encoding/json
+// formats a float64
+func canonicalNumber(number json.Number) string {
+	str := number.String()
+
+	if !strings.ContainsAny(str, ".eE") {
+		return str
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+
+	if err != nil {
+		return str
+	}
+
+	format := byte('f')
+
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	formatted := strconv.AppendFloat(nil, f, format, -1, 64)
+
+	if format == 'e' {
+		// clean up e-09 to e-9 as encoding/json does
+		n := len(formatted)
+
+		if n >= 4 && formatted[n-4] == 'e' && formatted[n-3] == '-' && formatted[n-2] == '0' {
+			formatted[n-2] = formatted[n-1]
+			formatted = formatted[:n-1]
+		}
+	}
+
+	return string(formatted)
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type unorderedMarshaler struct{}
+
+func (um unorderedMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{ "b": [1.50, 2E3, {"z": null, "y": true}], "a": "<x>", "c": 0.0000001 }`), nil
+}
+
+type badMarshaler struct{}
+
+func (bm badMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"a": 1} {"b": 2}`), nil
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	var bytes []byte
+	var err error
+
+	// Should sort keys of structs and maps
+	bytes, err = MarshalCanonicalJSON(struct {
+		Z string            `json:"z"`
+		A map[string]string `json:"a"`
+	}{"last", map[string]string{"y": "2", "x": "1"}})
+	assert.Nil(t, err, "should not error for marshallable value")
+	assert.Equal(t, `{"a":{"x":"1","y":"2"},"z":"last"}`, string(bytes), "should sort keys")
+
+	// Should canonicalize output of custom marshalers
+	bytes, err = MarshalCanonicalJSON(unorderedMarshaler{})
+	assert.Nil(t, err, "should not error for custom marshaler")
+	assert.Equal(t, `{"a":"\u003cx\u003e","b":[1.5,2000,{"y":true,"z":null}],"c":1e-7}`, string(bytes), "should sort keys, remove white space and format numbers")
+
+	// Should keep precision of large integers
+	bytes, _ = MarshalCanonicalJSON(json.RawMessage(`[123456789012345678901234567890]`))
+	assert.Equal(t, `[123456789012345678901234567890]`, string(bytes), "should not change integers")
+
+	// Should error when value cannot be marshalled
+	_, err = MarshalCanonicalJSON(make(chan int))
+	assert.EqualError(t, err, "json: unsupported type: chan int", "should error when value cannot be marshalled")
+
+	// Should error when marshaled value invalid
+	_, err = MarshalCanonicalJSON(badMarshaler{})
+	assert.Contains(t, err.Error(), "invalid character", "should error when marshaler returns invalid JSON")
+}
+
+func TestCanonicalNumber(t *testing.T) {
+	assert.Equal(t, "10", canonicalNumber(json.Number("10")), "should leave integers")
+	assert.Equal(t, "-0.5", canonicalNumber(json.Number("-5e-1")), "should format decimals")
+	assert.Equal(t, "1e+21", canonicalNumber(json.Number("1000000000000000000000.0")), "should use exponent for large numbers")
+	assert.Equal(t, "1e-9", canonicalNumber(json.Number("0.000000001")), "should trim exponent")
+	assert.Equal(t, "1e999", canonicalNumber(json.Number("1e999")), "should leave numbers out of range")
+}
@@ -0,0 +1,163 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// Codec defines functions for encoding values stored in the world state
+type Codec interface {
+	// Encode returns the bytes to store for the passed value
+	Encode(value interface{}) ([]byte, error)
+
+	// Decode reads the passed bytes into target, a pointer to a value
+	Decode(bytes []byte, target interface{}) error
+}
+
+// JSONCodec encodes values as JSON. This is the default codec.
+type JSONCodec struct{}
+
+// Encode marshals the value to JSON
+func (jc *JSONCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode unmarshals the JSON bytes into target
+func (jc *JSONCodec) Decode(bytes []byte, target interface{}) error {
+	return json.Unmarshal(bytes, target)
+}
+
+// CanonicalJSONCodec encodes values as canonical JSON (see MarshalCanonicalJSON),
+// e.g. for types implementing json.Marshaler whose key order may vary
+type CanonicalJSONCodec struct{}
+
+// Encode marshals the value to canonical JSON
+func (cjc *CanonicalJSONCodec) Encode(value interface{}) ([]byte, error) {
+	return MarshalCanonicalJSON(value)
+}
+
+// Decode unmarshals the JSON bytes into target
+func (cjc *CanonicalJSONCodec) Decode(bytes []byte, target interface{}) error {
+	return json.Unmarshal(bytes, target)
+}
+
+// ProtoCodec encodes values using protocol buffers. Values must implement
+// proto.Message.
+type ProtoCodec struct{}
+
+// Encode marshals the value using protocol buffers
+func (pc *ProtoCodec) Encode(value interface{}) ([]byte, error) {
+	message, ok := value.(proto.Message)
+
+	if !ok {
+		return nil, fmt.Errorf("Type %s does not implement proto.Message", reflect.TypeOf(value))
+	}
+
+	return proto.Marshal(message)
+}
+
+// Decode unmarshals the protocol buffer bytes into target
+func (pc *ProtoCodec) Decode(bytes []byte, target interface{}) error {
+	message, ok := target.(proto.Message)
+
+	if !ok {
+		return fmt.Errorf("Type %s does not implement proto.Message", reflect.TypeOf(target))
+	}
+
+	return proto.Unmarshal(bytes, message)
+}
+
+// StringCodec stores values of string types, e.g. counters kept as text, as their
+// raw bytes rather than as quoted JSON strings
+type StringCodec struct{}
+
+// Encode returns the bytes of the string value
+func (sc *StringCodec) Encode(value interface{}) ([]byte, error) {
+	v := reflect.ValueOf(value)
+
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.String {
+		return nil, fmt.Errorf("Type %s is not a string type", reflect.TypeOf(value))
+	}
+
+	return []byte(v.String()), nil
+}
+
+// Decode sets the string pointed to by target to the bytes
+func (sc *StringCodec) Decode(bytes []byte, target interface{}) error {
+	v := reflect.ValueOf(target)
+
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.String {
+		return fmt.Errorf("Type %s is not a pointer to a string type", reflect.TypeOf(target))
+	}
+
+	v.Elem().SetString(string(bytes))
+
+	return nil
+}
+
+var defaultCodec Codec = new(JSONCodec)
+
+var codecRegistry = struct {
+	sync.RWMutex
+	codecs map[reflect.Type]Codec
+}{codecs: make(map[reflect.Type]Codec)}
+
+// RegisterCodec sets the codec used by collections to store values of the
+// same type as the passed value. Pointers and the values they point to share
+// the same codec. Passing a nil codec removes the registration.
+func RegisterCodec(value interface{}, codec Codec) {
+	codecRegistry.Lock()
+	defer codecRegistry.Unlock()
+
+	t := baseType(reflect.TypeOf(value))
+
+	if codec == nil {
+		delete(codecRegistry.codecs, t)
+		return
+	}
+
+	codecRegistry.codecs[t] = codec
+}
+
+// GetRegisteredCodec returns the codec registered for the type of the passed
+// value, or nil if no codec is registered
+func GetRegisteredCodec(value interface{}) Codec {
+	return getRegisteredCodecForType(reflect.TypeOf(value))
+}
+
+func getRegisteredCodecForType(t reflect.Type) Codec {
+	codecRegistry.RLock()
+	defer codecRegistry.RUnlock()
+
+	return codecRegistry.codecs[baseType(t)]
+}
+
+func baseType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
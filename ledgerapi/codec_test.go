@@ -0,0 +1,202 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type csvAsset struct {
+	Owner string
+	ID    string
+}
+
+type csvCodec struct{}
+
+func (cc *csvCodec) Encode(value interface{}) ([]byte, error) {
+	asset, ok := value.(*csvAsset)
+
+	if !ok {
+		return nil, errors.New("Value not a csvAsset")
+	}
+
+	return []byte(asset.Owner + "," + asset.ID), nil
+}
+
+func (cc *csvCodec) Decode(bytes []byte, target interface{}) error {
+	parts := strings.Split(string(bytes), ",")
+
+	if len(parts) != 2 {
+		return errors.New("Value not CSV")
+	}
+
+	asset := target.(*csvAsset)
+	asset.Owner = parts[0]
+	asset.ID = parts[1]
+
+	return nil
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestJSONCodec(t *testing.T) {
+	jc := new(JSONCodec)
+
+	bytes, err := jc.Encode(testAsset{"alice", "1", 10})
+	assert.Nil(t, err, "should not error encoding")
+	assert.Equal(t, "{\"owner\":\"alice\",\"id\":\"1\",\"value\":10}", string(bytes), "should encode as JSON")
+
+	asset := new(testAsset)
+	err = jc.Decode(bytes, asset)
+	assert.Nil(t, err, "should not error decoding")
+	assert.Equal(t, testAsset{"alice", "1", 10}, *asset, "should decode JSON")
+}
+
+func TestCanonicalJSONCodec(t *testing.T) {
+	cjc := new(CanonicalJSONCodec)
+
+	bytes, err := cjc.Encode(testAsset{"alice", "1", 10})
+	assert.Nil(t, err, "should not error encoding")
+	assert.Equal(t, "{\"id\":\"1\",\"owner\":\"alice\",\"value\":10}", string(bytes), "should encode as canonical JSON")
+
+	asset := new(testAsset)
+	err = cjc.Decode(bytes, asset)
+	assert.Nil(t, err, "should not error decoding")
+	assert.Equal(t, testAsset{"alice", "1", 10}, *asset, "should decode JSON")
+}
+
+func TestProtoCodec(t *testing.T) {
+	pc := new(ProtoCodec)
+	response := &peer.Response{Status: 200, Message: "some message"}
+
+	// Should encode and decode proto messages
+	bytes, err := pc.Encode(response)
+	assert.Nil(t, err, "should not error encoding proto message")
+	expected, _ := proto.Marshal(response)
+	assert.Equal(t, expected, bytes, "should encode using protocol buffers")
+
+	decoded := new(peer.Response)
+	err = pc.Decode(bytes, decoded)
+	assert.Nil(t, err, "should not error decoding proto message")
+	assert.True(t, proto.Equal(response, decoded), "should decode using protocol buffers")
+
+	// Should error for values not proto messages
+	_, err = pc.Encode(testAsset{})
+	assert.EqualError(t, err, "Type ledgerapi.testAsset does not implement proto.Message", "should error encoding non proto message")
+	err = pc.Decode(bytes, new(testAsset))
+	assert.EqualError(t, err, "Type *ledgerapi.testAsset does not implement proto.Message", "should error decoding into non proto message")
+}
+
+type testCounter string
+
+func TestStringCodec(t *testing.T) {
+	sc := new(StringCodec)
+
+	// Should encode and decode string types as raw bytes
+	bytes, err := sc.Encode(testCounter("10"))
+	assert.Nil(t, err, "should not error encoding string type")
+	assert.Equal(t, []byte("10"), bytes, "should encode as raw bytes")
+
+	str := "11"
+	bytes, _ = sc.Encode(&str)
+	assert.Equal(t, []byte("11"), bytes, "should encode pointer to string")
+
+	counter := new(testCounter)
+	err = sc.Decode([]byte("12"), counter)
+	assert.Nil(t, err, "should not error decoding into string type")
+	assert.Equal(t, testCounter("12"), *counter, "should decode raw bytes")
+
+	// Should error for values not string types
+	_, err = sc.Encode(10)
+	assert.EqualError(t, err, "Type int is not a string type", "should error encoding non string")
+	err = sc.Decode(bytes, str)
+	assert.EqualError(t, err, "Type string is not a pointer to a string type", "should error decoding into non pointer")
+	err = sc.Decode(bytes, new(int))
+	assert.EqualError(t, err, "Type *int is not a pointer to a string type", "should error decoding into non string")
+}
+
+func TestRegisterCodec(t *testing.T) {
+	cc := new(csvCodec)
+
+	// Should return nil when no codec registered
+	assert.Nil(t, GetRegisteredCodec(csvAsset{}), "should return nil when not registered")
+
+	// Should register codec for type and pointers to type
+	RegisterCodec(new(csvAsset), cc)
+	assert.Equal(t, cc, GetRegisteredCodec(csvAsset{}), "should return codec for type")
+	assert.Equal(t, cc, GetRegisteredCodec(new(csvAsset)), "should return codec for pointer to type")
+
+	// Should remove registration when nil passed
+	RegisterCodec(csvAsset{}, nil)
+	assert.Nil(t, GetRegisteredCodec(csvAsset{}), "should remove registration")
+}
+
+func TestCollectionCodecs(t *testing.T) {
+	var err error
+
+	stub := newTestStub()
+	key, _ := stub.CreateCompositeKey("assets", []string{"alice", "1"})
+
+	RegisterCodec(csvAsset{}, new(csvCodec))
+	defer RegisterCodec(csvAsset{}, nil)
+
+	c := NewCollection(stub, "assets")
+
+	// Should use registered codec for type
+	err = c.Put([]string{"alice", "1"}, &csvAsset{"alice", "1"})
+	assert.Nil(t, err, "should not error using registered codec")
+	bytes, _ := stub.GetState(key)
+	assert.Equal(t, "alice,1", string(bytes), "should encode using registered codec")
+
+	asset := new(csvAsset)
+	c.Get([]string{"alice", "1"}, asset)
+	assert.Equal(t, csvAsset{"alice", "1"}, *asset, "should decode using registered codec")
+
+	all := []*csvAsset{}
+	err = c.GetAll([]string{}, &all)
+	assert.Nil(t, err, "should not error getting all using registered codec")
+	assert.Equal(t, []*csvAsset{{"alice", "1"}}, all, "should decode all using registered codec")
+
+	// Should use JSON for types without registered codec
+	c.Put([]string{"bob", "1"}, testAsset{"bob", "1", 10})
+	jsonKey, _ := stub.CreateCompositeKey("assets", []string{"bob", "1"})
+	bytes, _ = stub.GetState(jsonKey)
+	assert.Equal(t, "{\"owner\":\"bob\",\"id\":\"1\",\"value\":10}", string(bytes), "should encode as JSON when no codec registered")
+
+	// Should use collection codec over registered codec
+	sl := NewStateList(stub, "jsonassets")
+	sl.SetCodec(new(JSONCodec))
+	assert.Equal(t, new(JSONCodec), sl.GetCollection().codec, "should set codec of collection")
+	sl.GetCollection().Put([]string{"alice", "1"}, &csvAsset{"alice", "1"})
+	collectionKey, _ := stub.CreateCompositeKey("jsonassets", []string{"alice", "1"})
+	bytes, _ = stub.GetState(collectionKey)
+	assert.Equal(t, "{\"Owner\":\"alice\",\"ID\":\"1\"}", string(bytes), "should encode using collection codec")
+
+	// Should return codec errors
+	err = c.Put([]string{"carol", "1"}, csvAsset{"carol", "1"})
+	assert.EqualError(t, err, "Failed to encode value for key carol:1 in collection assets. Value not a csvAsset", "should return encode error")
+}
@@ -0,0 +1,217 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ledgerapi provides typed collections over the world state, handling the
+// composite keys and JSON serialization of values stored by contracts.
+package ledgerapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// Collection stores values in the world state under composite keys. The
+// name of the collection is used as the object type of the keys so that
+// values of different collections, e.g. of different contracts, do not clash.
+// Values are encoded using the codec of the collection if set, otherwise
+// the codec registered for their type, otherwise as JSON.
+type Collection struct {
+	name  string
+	stub  shim.ChaincodeStubInterface
+	codec Codec
+}
+
+// NewCollection returns a collection with the passed name that reads and
+// writes the world state using the passed stub
+func NewCollection(stub shim.ChaincodeStubInterface, name string) *Collection {
+	c := new(Collection)
+	c.name = name
+	c.stub = stub
+
+	return c
+}
+
+// GetName returns the name of the collection
+func (c *Collection) GetName() string {
+	return c.name
+}
+
+// SetCodec sets the codec used to encode all values of the collection,
+// overriding codecs registered for their types
+func (c *Collection) SetCodec(codec Codec) {
+	c.codec = codec
+}
+
+// Put encodes the passed value and writes it under the key made up
+// of the passed key parts. Existing values are overwritten.
+func (c *Collection) Put(key []string, value interface{}) error {
+	compositeKey, err := c.createKey(key)
+
+	if err != nil {
+		return err
+	}
+
+	bytes, err := c.getCodec(reflect.TypeOf(value)).Encode(value)
+
+	if err != nil {
+		return fmt.Errorf("Failed to encode value for key %s in collection %s. %s", joinKey(key), c.name, err.Error())
+	}
+
+	return c.stub.PutState(compositeKey, bytes)
+}
+
+// Get reads the value stored under the key made up of the passed key parts
+// and decodes it into target. Returns an error if no value exists.
+func (c *Collection) Get(key []string, target interface{}) error {
+	bytes, err := c.get(key)
+
+	if err != nil {
+		return err
+	}
+
+	if bytes == nil {
+		return fmt.Errorf("Key %s does not exist in collection %s", joinKey(key), c.name)
+	}
+
+	err = c.getCodec(reflect.TypeOf(target)).Decode(bytes, target)
+
+	if err != nil {
+		return fmt.Errorf("Value for key %s in collection %s could not be decoded. %s", joinKey(key), c.name, err.Error())
+	}
+
+	return nil
+}
+
+// Exists returns whether a value is stored under the key made up of the
+// passed key parts
+func (c *Collection) Exists(key []string) (bool, error) {
+	bytes, err := c.get(key)
+
+	if err != nil {
+		return false, err
+	}
+
+	return bytes != nil, nil
+}
+
+// Delete removes the value stored under the key made up of the passed key
+// parts. Returns an error if no value exists.
+func (c *Collection) Delete(key []string) error {
+	exists, err := c.Exists(key)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("Key %s does not exist in collection %s", joinKey(key), c.name)
+	}
+
+	compositeKey, _ := c.createKey(key)
+
+	return c.stub.DelState(compositeKey)
+}
+
+// GetAll decodes each value stored under a key beginning with the passed
+// key parts into a new element of the slice pointed to by target e.g. a
+// *[]MyAsset. Passing no key parts returns all values of the collection.
+func (c *Collection) GetAll(partialKey []string, target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+
+	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Target must be a pointer to a slice. Received %s", reflect.TypeOf(target))
+	}
+
+	iterator, err := c.stub.GetStateByPartialCompositeKey(c.name, partialKey)
+
+	if err != nil {
+		return err
+	}
+
+	defer iterator.Close()
+
+	sliceValue := targetValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	codec := c.getCodec(elemType)
+
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType)
+
+		if elemType.Kind() == reflect.Ptr {
+			elem.Elem().Set(reflect.New(elemType.Elem()))
+			err = codec.Decode(kv.Value, elem.Elem().Interface())
+		} else {
+			err = codec.Decode(kv.Value, elem.Interface())
+		}
+
+		if err != nil {
+			return fmt.Errorf("Value for key %s in collection %s could not be decoded. %s", kv.Key, c.name, err.Error())
+		}
+
+		sliceValue = reflect.Append(sliceValue, elem.Elem())
+	}
+
+	targetValue.Elem().Set(sliceValue)
+
+	return nil
+}
+
+func (c *Collection) getCodec(t reflect.Type) Codec {
+	if c.codec != nil {
+		return c.codec
+	}
+
+	if codec := getRegisteredCodecForType(t); codec != nil {
+		return codec
+	}
+
+	return defaultCodec
+}
+
+func (c *Collection) get(key []string) ([]byte, error) {
+	compositeKey, err := c.createKey(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stub.GetState(compositeKey)
+}
+
+func (c *Collection) createKey(key []string) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("Key for collection %s must have at least one part", c.name)
+	}
+
+	compositeKey, err := c.stub.CreateCompositeKey(c.name, key)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create key for collection %s. %s", c.name, err.Error())
+	}
+
+	return compositeKey, nil
+}
+
+func joinKey(key []string) string {
+	return strings.Join(key, ":")
+}
@@ -0,0 +1,202 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+const standardTxID = "1234567890"
+
+type testAsset struct {
+	Owner string `json:"owner"`
+	ID    string `json:"id"`
+	Value int    `json:"value"`
+}
+
+func (ta *testAsset) GetSplitKey() []string {
+	return []string{ta.Owner, ta.ID}
+}
+
+type getErrorStub struct {
+	*shimtest.MockStub
+}
+
+func (ges *getErrorStub) GetState(key string) ([]byte, error) {
+	return nil, errors.New("some get error")
+}
+
+func newTestStub() *shimtest.MockStub {
+	stub := shimtest.NewMockStub("ledgerapiTest", nil)
+	stub.MockTransactionStart(standardTxID)
+
+	return stub
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewCollection(t *testing.T) {
+	stub := newTestStub()
+
+	c := NewCollection(stub, "assets")
+
+	assert.Equal(t, "assets", c.name, "should set name")
+	assert.Equal(t, stub, c.stub, "should set stub")
+	assert.Equal(t, "assets", c.GetName(), "should return name")
+}
+
+func TestCollectionPut(t *testing.T) {
+	var err error
+
+	stub := newTestStub()
+	c := NewCollection(stub, "assets")
+
+	// Should write JSON under composite key
+	err = c.Put([]string{"alice", "1"}, testAsset{"alice", "1", 10})
+	assert.Nil(t, err, "should not error on put")
+	key, _ := stub.CreateCompositeKey("assets", []string{"alice", "1"})
+	bytes, _ := stub.GetState(key)
+	assert.Equal(t, "{\"owner\":\"alice\",\"id\":\"1\",\"value\":10}", string(bytes), "should write value as JSON")
+
+	// Should error when key has no parts
+	err = c.Put([]string{}, testAsset{})
+	assert.EqualError(t, err, "Key for collection assets must have at least one part", "should error for empty key")
+
+	// Should error when key is invalid
+	err = c.Put([]string{string([]byte{0x00})}, testAsset{})
+	assert.Contains(t, err.Error(), "Failed to create key for collection assets.", "should error for invalid key")
+
+	// Should error when value cannot be marshalled
+	err = c.Put([]string{"alice", "2"}, make(chan int))
+	assert.EqualError(t, err, "Failed to encode value for key alice:2 in collection assets. json: unsupported type: chan int", "should error when value cannot be marshalled")
+}
+
+func TestCollectionGet(t *testing.T) {
+	var err error
+
+	stub := newTestStub()
+	c := NewCollection(stub, "assets")
+	c.Put([]string{"alice", "1"}, testAsset{"alice", "1", 10})
+
+	// Should read value into target
+	asset := new(testAsset)
+	err = c.Get([]string{"alice", "1"}, asset)
+	assert.Nil(t, err, "should not error when value exists")
+	assert.Equal(t, testAsset{"alice", "1", 10}, *asset, "should unmarshal value")
+
+	// Should error when value does not exist
+	err = c.Get([]string{"alice", "2"}, asset)
+	assert.EqualError(t, err, "Key alice:2 does not exist in collection assets", "should error when value does not exist")
+
+	// Should error when value cannot be unmarshalled
+	key, _ := stub.CreateCompositeKey("assets", []string{"bob", "1"})
+	stub.PutState(key, []byte("not json"))
+	err = c.Get([]string{"bob", "1"}, asset)
+	assert.Contains(t, err.Error(), "Value for key bob:1 in collection assets could not be decoded.", "should error when value not JSON")
+
+	// Should error when get fails
+	c = NewCollection(&getErrorStub{stub}, "assets")
+	err = c.Get([]string{"alice", "1"}, asset)
+	assert.EqualError(t, err, "some get error", "should return get error")
+}
+
+func TestCollectionExists(t *testing.T) {
+	var exists bool
+	var err error
+
+	stub := newTestStub()
+	c := NewCollection(stub, "assets")
+	c.Put([]string{"alice", "1"}, testAsset{"alice", "1", 10})
+
+	// Should return true when value exists
+	exists, err = c.Exists([]string{"alice", "1"})
+	assert.Nil(t, err, "should not error when value exists")
+	assert.True(t, exists, "should return true when value exists")
+
+	// Should return false when value does not exist
+	exists, err = c.Exists([]string{"alice", "2"})
+	assert.Nil(t, err, "should not error when value does not exist")
+	assert.False(t, exists, "should return false when value does not exist")
+
+	// Should not find values of other collections
+	exists, _ = NewCollection(stub, "other").Exists([]string{"alice", "1"})
+	assert.False(t, exists, "should not find value of other collection")
+
+	// Should error when get fails
+	c = NewCollection(&getErrorStub{stub}, "assets")
+	_, err = c.Exists([]string{"alice", "1"})
+	assert.EqualError(t, err, "some get error", "should return get error")
+}
+
+func TestCollectionDelete(t *testing.T) {
+	var err error
+
+	stub := newTestStub()
+	c := NewCollection(stub, "assets")
+	c.Put([]string{"alice", "1"}, testAsset{"alice", "1", 10})
+
+	// Should delete value
+	err = c.Delete([]string{"alice", "1"})
+	assert.Nil(t, err, "should not error when value exists")
+	exists, _ := c.Exists([]string{"alice", "1"})
+	assert.False(t, exists, "should have deleted value")
+
+	// Should error when value does not exist
+	err = c.Delete([]string{"alice", "1"})
+	assert.EqualError(t, err, "Key alice:1 does not exist in collection assets", "should error when value does not exist")
+}
+
+func TestCollectionGetAll(t *testing.T) {
+	var err error
+
+	stub := newTestStub()
+	c := NewCollection(stub, "assets")
+	c.Put([]string{"alice", "1"}, testAsset{"alice", "1", 10})
+	c.Put([]string{"alice", "2"}, testAsset{"alice", "2", 20})
+	c.Put([]string{"bob", "1"}, testAsset{"bob", "1", 30})
+	NewCollection(stub, "other").Put([]string{"alice", "3"}, testAsset{"alice", "3", 40})
+
+	// Should error when target not pointer to slice
+	err = c.GetAll([]string{}, []testAsset{})
+	assert.EqualError(t, err, "Target must be a pointer to a slice. Received []ledgerapi.testAsset", "should error when target not pointer")
+
+	// Should return all values of collection
+	all := []testAsset{}
+	err = c.GetAll([]string{}, &all)
+	assert.Nil(t, err, "should not error getting all")
+	assert.Equal(t, []testAsset{{"alice", "1", 10}, {"alice", "2", 20}, {"bob", "1", 30}}, all, "should return all values of collection")
+
+	// Should return values matching partial key
+	alices := []*testAsset{}
+	err = c.GetAll([]string{"alice"}, &alices)
+	assert.Nil(t, err, "should not error getting by partial key")
+	assert.Equal(t, []*testAsset{{"alice", "1", 10}, {"alice", "2", 20}}, alices, "should return values matching partial key")
+
+	// Should error when value cannot be unmarshalled
+	key, _ := stub.CreateCompositeKey("assets", []string{"carol", "1"})
+	stub.PutState(key, []byte("not json"))
+	err = c.GetAll([]string{"carol"}, &all)
+	assert.Contains(t, err.Error(), "Value for key "+key+" in collection assets could not be decoded.", "should error when value not JSON")
+}
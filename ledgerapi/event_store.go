@@ -0,0 +1,275 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// Event is a change to an entity recorded by an EventStore
+type Event struct {
+	Sequence uint64          `json:"sequence"`
+	Type     string          `json:"type"`
+	TxID     string          `json:"txId"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// PayloadAs unmarshals the JSON payload of the event into target
+func (e Event) PayloadAs(target interface{}) error {
+	return json.Unmarshal(e.Payload, target)
+}
+
+// Aggregate is the current state of an entity, built by applying each of
+// its events in order
+type Aggregate interface {
+	// Apply updates the aggregate with the passed event
+	Apply(event Event) error
+}
+
+type eventHead struct {
+	Sequence uint64 `json:"sequence"`
+}
+
+type eventSnapshot struct {
+	Sequence uint64          `json:"sequence"`
+	State    json.RawMessage `json:"state"`
+}
+
+// EventStore stores the history of entities as events rather than storing their
+// current state. Events are appended under composite keys made up of the key of
+// the entity and the sequence number of the event, and are folded into an
+// Aggregate on read. Snapshots of aggregates can be stored so that reads need
+// only apply the events appended since the snapshot. A transaction does not see
+// its own writes to the world state so the event store keeps the events appended
+// by the transaction, which are included in reads of the same event store. An
+// event store should therefore be used for only one transaction.
+type EventStore struct {
+	name      string
+	stub      shim.ChaincodeStubInterface
+	events    *Collection
+	heads     *Collection
+	snapshots *Collection
+	pending   map[string][]Event
+}
+
+// NewEventStore returns an event store with the passed name that reads and
+// writes the world state using the passed stub
+func NewEventStore(stub shim.ChaincodeStubInterface, name string) *EventStore {
+	es := new(EventStore)
+	es.name = name
+	es.stub = stub
+	es.events = NewCollection(stub, name+".events")
+	es.heads = NewCollection(stub, name+".heads")
+	es.snapshots = NewCollection(stub, name+".snapshots")
+	es.pending = make(map[string][]Event)
+
+	// records of the event store are always JSON regardless of registered codecs
+	for _, c := range []*Collection{es.events, es.heads, es.snapshots} {
+		c.SetCodec(new(JSONCodec))
+	}
+
+	return es
+}
+
+// GetName returns the name of the event store
+func (es *EventStore) GetName() string {
+	return es.name
+}
+
+// Append records an event of the passed type for the entity with the passed
+// key. The payload is marshalled to JSON. Returns the sequence number of the
+// event, starting at 1 for the first event of the entity.
+func (es *EventStore) Append(key []string, eventType string, payload interface{}) (uint64, error) {
+	head, err := es.getHead(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to marshal payload of %s event for key %s in event store %s. %s", eventType, joinKey(key), es.name, err.Error())
+	}
+
+	event := Event{}
+	event.Sequence = head.Sequence + 1
+	event.Type = eventType
+	event.TxID = es.stub.GetTxID()
+	event.Payload = payloadBytes
+
+	err = es.events.Put(eventKey(key, event.Sequence), event)
+
+	if err != nil {
+		return 0, err
+	}
+
+	head.Sequence = event.Sequence
+
+	err = es.heads.Put(key, head)
+
+	if err != nil {
+		return 0, err
+	}
+
+	es.pending[pendingKey(key)] = append(es.pending[pendingKey(key)], event)
+
+	return event.Sequence, nil
+}
+
+// GetSequence returns the sequence number of the latest event of the entity
+// with the passed key, or 0 if it has no events
+func (es *EventStore) GetSequence(key []string) (uint64, error) {
+	head, err := es.getHead(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return head.Sequence, nil
+}
+
+// GetEvents returns, in order, the events of the entity with the passed key
+// that have a sequence number greater than fromSequence. Passing 0 returns
+// all events of the entity.
+func (es *EventStore) GetEvents(key []string, fromSequence uint64) ([]Event, error) {
+	head, err := es.getHead(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	pending := es.pending[pendingKey(key)]
+
+	for sequence := fromSequence + 1; sequence <= head.Sequence; sequence++ {
+		if len(pending) > 0 && sequence >= pending[0].Sequence {
+			events = append(events, pending[sequence-pending[0].Sequence])
+			continue
+		}
+
+		event := Event{}
+
+		err := es.events.Get(eventKey(key, sequence), &event)
+
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Load builds the current state of the entity with the passed key into the
+// aggregate. If a snapshot of the entity exists it is unmarshalled into the
+// aggregate and only the events since the snapshot are applied. Returns the
+// sequence number of the last event applied.
+func (es *EventStore) Load(key []string, aggregate Aggregate) (uint64, error) {
+	var sequence uint64
+
+	exists, err := es.snapshots.Exists(key)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if exists {
+		snapshot := eventSnapshot{}
+
+		err = es.snapshots.Get(key, &snapshot)
+
+		if err != nil {
+			return 0, err
+		}
+
+		err = json.Unmarshal(snapshot.State, aggregate)
+
+		if err != nil {
+			return 0, fmt.Errorf("Snapshot for key %s in event store %s could not be unmarshalled. %s", joinKey(key), es.name, err.Error())
+		}
+
+		sequence = snapshot.Sequence
+	}
+
+	events, err := es.GetEvents(key, sequence)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		err = aggregate.Apply(event)
+
+		if err != nil {
+			return 0, fmt.Errorf("Failed to apply event %d for key %s in event store %s. %s", event.Sequence, joinKey(key), es.name, err.Error())
+		}
+
+		sequence = event.Sequence
+	}
+
+	return sequence, nil
+}
+
+// Snapshot loads the current state of the entity with the passed key into the
+// aggregate and stores it as JSON so that later loads start from this point
+func (es *EventStore) Snapshot(key []string, aggregate Aggregate) error {
+	sequence, err := es.Load(key, aggregate)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := json.Marshal(aggregate)
+
+	if err != nil {
+		return fmt.Errorf("Failed to marshal snapshot for key %s in event store %s. %s", joinKey(key), es.name, err.Error())
+	}
+
+	return es.snapshots.Put(key, eventSnapshot{sequence, state})
+}
+
+// getHead returns the head of the entity including the events appended by the
+// transaction, which are not yet in the world state it reads
+func (es *EventStore) getHead(key []string) (eventHead, error) {
+	if pending := es.pending[pendingKey(key)]; len(pending) > 0 {
+		return eventHead{pending[len(pending)-1].Sequence}, nil
+	}
+
+	head := eventHead{}
+
+	exists, err := es.heads.Exists(key)
+
+	if err != nil || !exists {
+		return head, err
+	}
+
+	err = es.heads.Get(key, &head)
+
+	return head, err
+}
+
+func pendingKey(key []string) string {
+	return strings.Join(key, "\x00")
+}
+
+func eventKey(key []string, sequence uint64) []string {
+	return append(append([]string{}, key...), fmt.Sprintf("%020d", sequence))
+}
@@ -0,0 +1,232 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Helpers
+// ================================
+
+type testAccount struct {
+	Balance int `json:"balance"`
+	Applied int `json:"-"`
+}
+
+func (ta *testAccount) Apply(event Event) error {
+	amount := 0
+
+	err := event.PayloadAs(&amount)
+
+	if err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case "deposit":
+		ta.Balance += amount
+	case "withdraw":
+		if amount > ta.Balance {
+			return errors.New("Insufficient funds")
+		}
+
+		ta.Balance -= amount
+	default:
+		return errors.New("Unknown event")
+	}
+
+	ta.Applied++
+
+	return nil
+}
+
+// bufferedWriteStub does not show a transaction its own writes, as on a peer
+type bufferedWriteStub struct {
+	*shimtest.MockStub
+	writes map[string][]byte
+}
+
+func (bws *bufferedWriteStub) PutState(key string, value []byte) error {
+	bws.writes[key] = value
+
+	return nil
+}
+
+func (bws *bufferedWriteStub) commit() {
+	for key, value := range bws.writes {
+		bws.MockStub.PutState(key, value)
+	}
+
+	bws.writes = make(map[string][]byte)
+}
+
+func newBufferedWriteStub() *bufferedWriteStub {
+	return &bufferedWriteStub{newTestStub(), make(map[string][]byte)}
+}
+
+// ================================
+// Tests
+// ================================
+
+func TestNewEventStore(t *testing.T) {
+	stub := newTestStub()
+
+	es := NewEventStore(stub, "accounts")
+
+	assert.Equal(t, "accounts", es.GetName(), "should set name")
+	assert.Equal(t, "accounts.events", es.events.GetName(), "should create events collection")
+	assert.Equal(t, "accounts.heads", es.heads.GetName(), "should create heads collection")
+	assert.Equal(t, "accounts.snapshots", es.snapshots.GetName(), "should create snapshots collection")
+}
+
+func TestEventStoreAppend(t *testing.T) {
+	var sequence uint64
+	var err error
+
+	es := NewEventStore(newTestStub(), "accounts")
+
+	// Should append events with increasing sequence
+	sequence, err = es.Append([]string{"alice"}, "deposit", 10)
+	assert.Nil(t, err, "should not error appending first event")
+	assert.Equal(t, uint64(1), sequence, "should return sequence of first event")
+
+	sequence, _ = es.Append([]string{"alice"}, "withdraw", 5)
+	assert.Equal(t, uint64(2), sequence, "should return sequence of second event")
+
+	sequence, _ = es.Append([]string{"bob"}, "deposit", 20)
+	assert.Equal(t, uint64(1), sequence, "should sequence events per key")
+
+	sequence, _ = es.GetSequence([]string{"alice"})
+	assert.Equal(t, uint64(2), sequence, "should return latest sequence")
+
+	sequence, _ = es.GetSequence([]string{"carol"})
+	assert.Equal(t, uint64(0), sequence, "should return zero for key without events")
+
+	// Should error when payload cannot be marshalled
+	_, err = es.Append([]string{"alice"}, "deposit", make(chan int))
+	assert.EqualError(t, err, "Failed to marshal payload of deposit event for key alice in event store accounts. json: unsupported type: chan int", "should error for invalid payload")
+
+	// Should error when key invalid
+	_, err = es.Append([]string{}, "deposit", 10)
+	assert.EqualError(t, err, "Key for collection accounts.heads must have at least one part", "should error for invalid key")
+}
+
+func TestEventStoreAppendWithoutReadYourWrites(t *testing.T) {
+	var sequence uint64
+
+	stub := newBufferedWriteStub()
+	es := NewEventStore(stub, "accounts")
+
+	// Should sequence events appended in the same transaction
+	sequence, _ = es.Append([]string{"alice"}, "deposit", 10)
+	assert.Equal(t, uint64(1), sequence, "should return sequence of first event")
+	sequence, _ = es.Append([]string{"alice"}, "withdraw", 5)
+	assert.Equal(t, uint64(2), sequence, "should return sequence of second event in same transaction")
+
+	sequence, _ = es.GetSequence([]string{"alice"})
+	assert.Equal(t, uint64(2), sequence, "should return pending sequence")
+
+	// Should read events appended in the same transaction
+	account := new(testAccount)
+	sequence, err := es.Load([]string{"alice"}, account)
+	assert.Nil(t, err, "should not error loading pending events")
+	assert.Equal(t, uint64(2), sequence, "should return sequence of last pending event")
+	assert.Equal(t, 5, account.Balance, "should apply pending events")
+
+	// Should continue from committed events in later transaction
+	stub.commit()
+	es = NewEventStore(stub, "accounts")
+	es.Append([]string{"alice"}, "deposit", 1)
+	sequence, _ = es.Append([]string{"alice"}, "deposit", 2)
+	assert.Equal(t, uint64(4), sequence, "should continue from committed head")
+
+	events, _ := es.GetEvents([]string{"alice"}, 1)
+	assert.Equal(t, []uint64{2, 3, 4}, []uint64{events[0].Sequence, events[1].Sequence, events[2].Sequence}, "should combine committed and pending events")
+}
+
+func TestEventStoreGetEvents(t *testing.T) {
+	es := NewEventStore(newTestStub(), "accounts")
+	es.Append([]string{"alice"}, "deposit", 10)
+	es.Append([]string{"alice"}, "withdraw", 5)
+
+	// Should return all events in order
+	events, err := es.GetEvents([]string{"alice"}, 0)
+	assert.Nil(t, err, "should not error getting events")
+	assert.Equal(t, []Event{
+		{Sequence: 1, Type: "deposit", TxID: standardTxID, Payload: []byte("10")},
+		{Sequence: 2, Type: "withdraw", TxID: standardTxID, Payload: []byte("5")},
+	}, events, "should return events in order")
+
+	// Should return events after sequence
+	events, _ = es.GetEvents([]string{"alice"}, 1)
+	assert.Len(t, events, 1, "should return events after sequence")
+	assert.Equal(t, uint64(2), events[0].Sequence, "should return later event")
+
+	// Should return no events for key without events
+	events, _ = es.GetEvents([]string{"bob"}, 0)
+	assert.Equal(t, []Event{}, events, "should return no events")
+}
+
+func TestEventStoreLoadAndSnapshot(t *testing.T) {
+	var sequence uint64
+	var err error
+
+	stub := newTestStub()
+	es := NewEventStore(stub, "accounts")
+	es.Append([]string{"alice"}, "deposit", 10)
+	es.Append([]string{"alice"}, "withdraw", 5)
+
+	// Should fold events into aggregate
+	account := new(testAccount)
+	sequence, err = es.Load([]string{"alice"}, account)
+	assert.Nil(t, err, "should not error loading")
+	assert.Equal(t, uint64(2), sequence, "should return sequence of last event applied")
+	assert.Equal(t, 5, account.Balance, "should apply each event")
+	assert.Equal(t, 2, account.Applied, "should apply each event once")
+
+	// Should store snapshot and apply only later events
+	err = es.Snapshot([]string{"alice"}, new(testAccount))
+	assert.Nil(t, err, "should not error snapshotting")
+	es.Append([]string{"alice"}, "deposit", 1)
+
+	account = new(testAccount)
+	sequence, _ = es.Load([]string{"alice"}, account)
+	assert.Equal(t, uint64(3), sequence, "should return sequence of last event after snapshot")
+	assert.Equal(t, 6, account.Balance, "should apply events to snapshot")
+	assert.Equal(t, 1, account.Applied, "should only apply events after snapshot")
+
+	// Should error when event cannot be applied
+	es.Append([]string{"alice"}, "withdraw", 100)
+	_, err = es.Load([]string{"alice"}, new(testAccount))
+	assert.EqualError(t, err, "Failed to apply event 4 for key alice in event store accounts. Insufficient funds", "should error when event cannot be applied")
+
+	// Should error when snapshot cannot be unmarshalled
+	key, _ := stub.CreateCompositeKey("accounts.snapshots", []string{"bob"})
+	stub.PutState(key, []byte("{\"sequence\":1,\"state\":\"not an account\"}"))
+	_, err = es.Load([]string{"bob"}, new(testAccount))
+	assert.Contains(t, err.Error(), "Snapshot for key bob in event store accounts could not be unmarshalled.", "should error for invalid snapshot")
+
+	// Should error when snapshot cannot be read
+	key, _ = stub.CreateCompositeKey("accounts.snapshots", []string{"carol"})
+	stub.PutState(key, []byte("not json"))
+	_, err = es.Load([]string{"carol"}, new(testAccount))
+	assert.NotNil(t, err, "should error when snapshot cannot be read")
+}
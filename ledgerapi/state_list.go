@@ -0,0 +1,103 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// State defines functions a value stored in a StateList should have
+type State interface {
+	// GetSplitKey returns the parts of the key the state is stored under
+	GetSplitKey() []string
+}
+
+// StateList stores values implementing State in a collection, using the
+// key parts returned by the value. Add and Update check the existence of
+// the value so that contracts need not perform the check themselves.
+type StateList struct {
+	collection *Collection
+}
+
+// NewStateList returns a state list with the passed name that reads and
+// writes the world state using the passed stub
+func NewStateList(stub shim.ChaincodeStubInterface, name string) *StateList {
+	sl := new(StateList)
+	sl.collection = NewCollection(stub, name)
+
+	return sl
+}
+
+// GetCollection returns the collection that the state list stores values in
+func (sl *StateList) GetCollection() *Collection {
+	return sl.collection
+}
+
+// SetCodec sets the codec used to encode all states of the list, overriding
+// codecs registered for their types
+func (sl *StateList) SetCodec(codec Codec) {
+	sl.collection.SetCodec(codec)
+}
+
+// AddState writes the passed state. Returns an error if a state already
+// exists with the same key.
+func (sl *StateList) AddState(state State) error {
+	exists, err := sl.collection.Exists(state.GetSplitKey())
+
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("Key %s already exists in collection %s", joinKey(state.GetSplitKey()), sl.collection.name)
+	}
+
+	return sl.collection.Put(state.GetSplitKey(), state)
+}
+
+// UpdateState writes the passed state. Returns an error if no state exists
+// with the same key.
+func (sl *StateList) UpdateState(state State) error {
+	exists, err := sl.collection.Exists(state.GetSplitKey())
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("Key %s does not exist in collection %s", joinKey(state.GetSplitKey()), sl.collection.name)
+	}
+
+	return sl.collection.Put(state.GetSplitKey(), state)
+}
+
+// GetState reads the state stored under the passed key into target. Returns
+// an error if no state exists.
+func (sl *StateList) GetState(key []string, target State) error {
+	return sl.collection.Get(key, target)
+}
+
+// StateExists returns whether a state is stored under the passed key
+func (sl *StateList) StateExists(key []string) (bool, error) {
+	return sl.collection.Exists(key)
+}
+
+// DeleteState removes the state stored under the passed key. Returns an
+// error if no state exists.
+func (sl *StateList) DeleteState(key []string) error {
+	return sl.collection.Delete(key)
+}
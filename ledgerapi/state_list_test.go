@@ -0,0 +1,83 @@
+/*
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ledgerapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ================================
+// Tests
+// ================================
+
+func TestNewStateList(t *testing.T) {
+	stub := newTestStub()
+
+	sl := NewStateList(stub, "assets")
+
+	assert.Equal(t, NewCollection(stub, "assets"), sl.collection, "should create collection")
+	assert.Equal(t, sl.collection, sl.GetCollection(), "should return collection")
+}
+
+func TestAddState(t *testing.T) {
+	var err error
+
+	sl := NewStateList(newTestStub(), "assets")
+
+	// Should add state under its key
+	err = sl.AddState(&testAsset{"alice", "1", 10})
+	assert.Nil(t, err, "should not error when state does not exist")
+	asset := new(testAsset)
+	sl.GetState([]string{"alice", "1"}, asset)
+	assert.Equal(t, testAsset{"alice", "1", 10}, *asset, "should have added state")
+
+	// Should error when state exists
+	err = sl.AddState(&testAsset{"alice", "1", 20})
+	assert.EqualError(t, err, "Key alice:1 already exists in collection assets", "should error when state exists")
+}
+
+func TestUpdateState(t *testing.T) {
+	var err error
+
+	sl := NewStateList(newTestStub(), "assets")
+
+	// Should error when state does not exist
+	err = sl.UpdateState(&testAsset{"alice", "1", 10})
+	assert.EqualError(t, err, "Key alice:1 does not exist in collection assets", "should error when state does not exist")
+
+	// Should update state under its key
+	sl.AddState(&testAsset{"alice", "1", 10})
+	err = sl.UpdateState(&testAsset{"alice", "1", 20})
+	assert.Nil(t, err, "should not error when state exists")
+	asset := new(testAsset)
+	sl.GetState([]string{"alice", "1"}, asset)
+	assert.Equal(t, testAsset{"alice", "1", 20}, *asset, "should have updated state")
+}
+
+func TestStateExistsAndDeleteState(t *testing.T) {
+	sl := NewStateList(newTestStub(), "assets")
+	sl.AddState(&testAsset{"alice", "1", 10})
+
+	// Should return whether state exists
+	exists, _ := sl.StateExists([]string{"alice", "1"})
+	assert.True(t, exists, "should return true when state exists")
+
+	// Should delete state
+	assert.Nil(t, sl.DeleteState([]string{"alice", "1"}), "should not error deleting existing state")
+	exists, _ = sl.StateExists([]string{"alice", "1"})
+	assert.False(t, exists, "should return false when state deleted")
+}